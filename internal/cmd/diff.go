@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// diffFormats maps the formats diff can read to the task type they hold
+var diffFormats = map[string]config.TaskType{
+	"vscode-tasks":  config.TypeVSCodeTask,
+	"vscode-launch": config.TypeVSCodeLaunch,
+	"jetbrains":     config.TypeJetBrains,
+}
+
+// diffReport is the JSON output of diff
+type diffReport struct {
+	From      string              `json:"from"`
+	To        string              `json:"to"`
+	Pairs     []diffPair          `json:"pairs"`
+	MissingIn map[string][]string `json:"missingIn"`
+}
+
+// diffPair is a compared pair with the port invocation that reconciles it
+type diffPair struct {
+	config.DriftPair
+	SuggestedPort string `json:"suggestedPort,omitempty"`
+}
+
+// taskPairReport is the JSON output of diff --vscode <name> --jetbrains <name>
+type taskPairReport struct {
+	VSCode    taskPairSide             `json:"vscode"`
+	JetBrains taskPairSide             `json:"jetbrains"`
+	Fields    []config.FieldComparison `json:"fields"`
+	Drifted   bool                     `json:"drifted"`
+}
+
+// taskPairSide names one task of a compared pair
+type taskPairSide struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+}
+
+func NewDiffCommand(verbose *bool, outputFormat *string, configPath *string, logOpts *logOptions) *cobra.Command {
+	var (
+		fromFormat    string
+		toFormat      string
+		mappings      []string
+		vscodeName    string
+		jetbrainsName string
+	)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the same tasks across formats to find drift",
+		Long: `Compare the configurations of two formats to find tasks whose definitions
+have drifted apart, e.g. a build task in tasks.json and its run configuration
+in .idea that no longer pass the same arguments.
+
+Configurations are paired by name, ignoring case; names that differ only in
+separators or a couple of characters ("Build App" and "build-app") are paired
+fuzzily, and --map pairs anything else. For every pair the command line, working
+directory, environment variables and group are compared (groups only outside
+JetBrains, which derives them from the configuration type). Configurations that
+exist on one side only are listed as missing.
+
+--vscode and --jetbrains compare one VSCode task or launch configuration with one
+JetBrains run configuration instead, printing their command, args, cwd and every
+env variable side by side, e.g. to check a pair that was set up by hand.
+
+diff exits with status 1 when the formats disagree, so CI can keep them in
+sync, and suggests the 'taskporter port' invocation that brings the --to side
+in line with the --from side.
+
+Examples:
+  # Compare VSCode tasks with JetBrains run configurations
+  taskporter diff
+
+  # Compare launch configurations, pairing two differently named ones
+  taskporter diff --from vscode-launch --to jetbrains --map "Launch API=API Server"
+
+  # Machine-readable output for CI
+  taskporter diff --output json
+
+  # Compare one VSCode task with its JetBrains counterpart, field by field
+  taskporter diff --vscode build --jetbrains "Gradle Build"
+
+Checking both ends of the strand...`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if vscodeName != "" {
+				if err := runTaskPairDiff(vscodeName, jetbrainsName, *verbose, *outputFormat, *configPath, logOpts); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+
+				return
+			}
+
+			if err := runDiffCommand(fromFormat, toFormat, mappings, *verbose, *outputFormat, *configPath, logOpts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	diffCmd.Flags().StringVar(&fromFormat, "from", "vscode-tasks", "format the other side is compared against (vscode-tasks, vscode-launch, jetbrains)")
+	diffCmd.Flags().StringVar(&toFormat, "to", "jetbrains", "format compared with --from (vscode-tasks, vscode-launch, jetbrains)")
+	diffCmd.Flags().StringArrayVar(&mappings, "map", nil, "pair the --from configuration a with the --to configuration b, as a=b (repeatable)")
+	diffCmd.Flags().StringVar(&vscodeName, "vscode", "", "compare only this VSCode task or launch configuration with --jetbrains, field by field")
+	diffCmd.Flags().StringVar(&jetbrainsName, "jetbrains", "", "JetBrains run configuration to compare with --vscode")
+
+	diffCmd.MarkFlagsRequiredTogether("vscode", "jetbrains")
+	diffCmd.MarkFlagsMutuallyExclusive("vscode", "map")
+
+	for _, flag := range []string{"from", "to"} {
+		_ = diffCmd.RegisterFlagCompletionFunc(flag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"vscode-tasks", "vscode-launch", "jetbrains"}, cobra.ShellCompDirectiveNoFileComp
+		})
+	}
+
+	return diffCmd
+}
+
+func runDiffCommand(fromFormat, toFormat string, mappings []string, verbose bool, outputFormat string, configPath string, logOpts *logOptions) error {
+	if _, err := logOpts.newLogger(os.Stderr, verbose); err != nil {
+		return err
+	}
+
+	if _, ok := diffFormats[toFormat]; !ok {
+		return fmt.Errorf("invalid format '%s'. Valid options: vscode-tasks, vscode-launch, jetbrains", toFormat)
+	}
+
+	// Only formats port converts between can be reconciled
+	if err := validateFormatCombination(fromFormat, toFormat, false); err != nil {
+		return err
+	}
+
+	mapping, err := parseDiffMappings(mappings)
+	if err != nil {
+		return err
+	}
+
+	// Determine project root
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	projectConfig, err := logOpts.newProjectDetector(projectRoot).DetectProject()
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	tasks, err := getAllTasksQuiet(projectConfig.ProjectRoot, logOpts)
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	left := filterTasksByType(tasks, diffFormats[fromFormat])
+	right := filterTasksByType(tasks, diffFormats[toFormat])
+
+	drift, err := config.CompareTaskSets(left, right, projectConfig.ProjectRoot, mapping)
+	if err != nil {
+		return fmt.Errorf("invalid --map: %w", err)
+	}
+
+	report := diffReport{
+		From:      fromFormat,
+		To:        toFormat,
+		Pairs:     make([]diffPair, 0, len(drift.Pairs)),
+		MissingIn: map[string][]string{fromFormat: drift.MissingInLeft, toFormat: drift.MissingInRight},
+	}
+
+	for _, pair := range drift.Pairs {
+		pair.LeftSource = relativeSource(projectConfig.ProjectRoot, pair.LeftSource)
+		pair.RightSource = relativeSource(projectConfig.ProjectRoot, pair.RightSource)
+
+		entry := diffPair{DriftPair: pair}
+		if len(pair.Diffs) > 0 {
+			entry.SuggestedPort = fmt.Sprintf("taskporter port --from %s --to %s", fromFormat, toFormat)
+		}
+
+		report.Pairs = append(report.Pairs, entry)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		displayDiffText(report, verbose)
+	}
+
+	if drift.HasDrift() {
+		return fmt.Errorf("%s and %s have drifted: %d paired configurations differ, %d exist on one side only",
+			fromFormat, toFormat, len(drift.Drifted()), len(drift.MissingInLeft)+len(drift.MissingInRight))
+	}
+
+	return nil
+}
+
+// parseDiffMappings parses --map values of the form a=b
+func parseDiffMappings(mappings []string) (map[string]string, error) {
+	mapping := make(map[string]string, len(mappings))
+
+	for _, value := range mappings {
+		from, to, ok := strings.Cut(value, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid --map '%s': expected <from name>=<to name>", value)
+		}
+
+		mapping[from] = to
+	}
+
+	return mapping, nil
+}
+
+// filterTasksByType returns the tasks of one type, in their original order
+func filterTasksByType(tasks []*config.Task, taskType config.TaskType) []*config.Task {
+	var filtered []*config.Task
+
+	for _, task := range tasks {
+		if task.Type == taskType {
+			filtered = append(filtered, task)
+		}
+	}
+
+	return filtered
+}
+
+// displayDiffText prints one table row per differing field, then how to reconcile the drifted
+// pairs and what is missing on either side
+func displayDiffText(report diffReport, verbose bool) {
+	var drifted, inSync []diffPair
+
+	for _, pair := range report.Pairs {
+		if len(pair.Diffs) > 0 {
+			drifted = append(drifted, pair)
+		} else {
+			inSync = append(inSync, pair)
+		}
+	}
+
+	fmt.Printf("🔍 Comparing %s with %s: %d paired, %d drifted\n", report.From, report.To, len(report.Pairs), len(drifted))
+
+	if len(drifted) > 0 {
+		fmt.Println()
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "   TASK\tFIELD\t%s\t%s\n", strings.ToUpper(report.From), strings.ToUpper(report.To))
+
+		for _, pair := range drifted {
+			for i, diff := range pair.Diffs {
+				label := ""
+				if i == 0 {
+					label = diffPairLabel(pair)
+				}
+
+				fmt.Fprintf(writer, "   %s\t%s\t%s\t%s\n", label, diff.Field, diff.Before, diff.After)
+			}
+		}
+
+		writer.Flush()
+
+		fmt.Println()
+		fmt.Printf("🔧 To bring %s in line with %s:\n", report.To, report.From)
+
+		for _, pair := range drifted {
+			fmt.Printf("   %s: %s\n", diffPairLabel(pair), pair.SuggestedPort)
+		}
+	}
+
+	for _, format := range []string{report.To, report.From} {
+		if missing := report.MissingIn[format]; len(missing) > 0 {
+			fmt.Println()
+			fmt.Printf("❓ Missing in %s: %s\n", format, strings.Join(missing, ", "))
+		}
+	}
+
+	if len(inSync) > 0 {
+		fmt.Println()
+		fmt.Printf("✅ In sync: %d\n", len(inSync))
+
+		if verbose {
+			for _, pair := range inSync {
+				fmt.Printf("   %s\n", diffPairLabel(pair))
+			}
+		}
+	}
+}
+
+// diffPairLabel names a pair, with both names and how they were paired when they differ
+func diffPairLabel(pair diffPair) string {
+	if pair.Left == pair.Right {
+		return pair.Left
+	}
+
+	return fmt.Sprintf("%s ↔ %s (%s)", pair.Left, pair.Right, pair.Match)
+}
+
+// runTaskPairDiff compares one VSCode task or launch configuration with one JetBrains run
+// configuration field by field, failing when they differ
+func runTaskPairDiff(vscodeName, jetbrainsName string, verbose bool, outputFormat string, configPath string, logOpts *logOptions) error {
+	if _, err := logOpts.newLogger(os.Stderr, verbose); err != nil {
+		return err
+	}
+
+	// Determine project root
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	projectConfig, err := logOpts.newProjectDetector(projectRoot).DetectProject()
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	tasks, err := getAllTasksQuiet(projectConfig.ProjectRoot, logOpts)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	// A tasks.json task wins over a launch configuration of the same name
+	vscodeTasks := append(filterTasksByType(tasks, config.TypeVSCodeTask), filterTasksByType(tasks, config.TypeVSCodeLaunch)...)
+
+	vscodeTask := config.FindTaskByName(vscodeTasks, vscodeName)
+	if vscodeTask == nil {
+		return fmt.Errorf("no VSCode task or launch configuration named '%s'", vscodeName)
+	}
+
+	jetbrainsTask := config.FindTaskByName(filterTasksByType(tasks, config.TypeJetBrains), jetbrainsName)
+	if jetbrainsTask == nil {
+		return fmt.Errorf("no JetBrains run configuration named '%s'", jetbrainsName)
+	}
+
+	report := taskPairReport{
+		VSCode:    newTaskPairSide(projectConfig.ProjectRoot, vscodeTask),
+		JetBrains: newTaskPairSide(projectConfig.ProjectRoot, jetbrainsTask),
+		Fields:    config.CompareTaskFields(vscodeTask, jetbrainsTask, projectConfig.ProjectRoot),
+	}
+
+	differing := 0
+
+	for _, field := range report.Fields {
+		if !field.Equal {
+			differing++
+		}
+	}
+
+	report.Drifted = differing > 0
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		displayTaskPairText(report)
+	}
+
+	if report.Drifted {
+		return fmt.Errorf("'%s' and '%s' have drifted: %d fields differ", vscodeTask.Name, jetbrainsTask.Name, differing)
+	}
+
+	return nil
+}
+
+// newTaskPairSide describes a compared task, with its source relative to the project root
+func newTaskPairSide(projectRoot string, task *config.Task) taskPairSide {
+	return taskPairSide{Name: task.Name, Type: string(task.Type), Source: relativeSource(projectRoot, task.Source)}
+}
+
+// displayTaskPairText prints the fields of a compared pair side by side, marking those that differ
+func displayTaskPairText(report taskPairReport) {
+	fmt.Printf("🔍 Comparing %s (%s) with %s (%s)\n", report.VSCode.Name, report.VSCode.Source, report.JetBrains.Name, report.JetBrains.Source)
+	fmt.Println()
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "   \tFIELD\tVSCODE\tJETBRAINS\n")
+
+	for _, field := range report.Fields {
+		mark := "✅"
+		if !field.Equal {
+			mark = "❌"
+		}
+
+		fmt.Fprintf(writer, "   %s\t%s\t%s\t%s\n", mark, field.Field, displayFieldValue(field.Left), displayFieldValue(field.Right))
+	}
+
+	writer.Flush()
+
+	fmt.Println()
+
+	if report.Drifted {
+		from := "vscode-tasks"
+		if report.VSCode.Type == string(config.TypeVSCodeLaunch) {
+			from = "vscode-launch"
+		}
+
+		fmt.Printf("🔧 To bring JetBrains in line with VSCode: taskporter port --from %s --to jetbrains\n", from)
+	} else {
+		fmt.Println("✅ In sync")
+	}
+}
+
+// displayFieldValue shows an empty field as a dash, so table columns stay readable
+func displayFieldValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
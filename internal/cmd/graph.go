@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+func NewGraphCommand(verbose *bool, outputFormat *string, configPath *string, logOpts *logOptions) *cobra.Command {
+	var dot bool
+
+	graphCmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Show how tasks and launch configurations reference each other",
+		Long: `Print the links between tasks and launch configurations: launch configurations
+and their preLaunchTask and postDebugTask, tasks and their dependsOn entries, and
+JetBrains configurations and the configurations their before-run steps start.
+Each link is annotated with the file it is written in, and names that match no
+task are marked as missing.
+
+Use --dot to print a Graphviz DOT graph instead, with nodes colored by source,
+e.g. for docs:
+
+  taskporter graph --dot | dot -Tsvg > tasks.svg
+
+Mapping the strands between every node...`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runGraphCommand(*verbose, *outputFormat, *configPath, dot, logOpts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	graphCmd.Flags().BoolVar(&dot, "dot", false, "print the graph in Graphviz DOT format")
+
+	return graphCmd
+}
+
+func runGraphCommand(verbose bool, outputFormat string, configPath string, dot bool, logOpts *logOptions) error {
+	// Parse problems are reported by list and validate; the graph only shows what could be read
+	if _, err := logOpts.newLogger(os.Stderr, verbose); err != nil {
+		return err
+	}
+
+	// Determine project root
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	projectConfig, err := logOpts.newProjectDetector(projectRoot).DetectProject()
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	tasks, err := getAllTasksQuiet(projectConfig.ProjectRoot, logOpts)
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	graph := config.BuildTaskGraph(tasks)
+
+	switch {
+	case dot:
+		return writeGraphDOT(os.Stdout, graph, projectConfig.ProjectRoot)
+	case outputFormat == "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(graph)
+	default:
+		displayGraphText(graph, projectConfig.ProjectRoot)
+		return nil
+	}
+}
+
+// displayGraphText lists every linked task with the tasks it references (→) and the tasks
+// referencing it (←), so a launch configuration's chain can be read from either end
+func displayGraphText(graph *config.TaskGraph, projectRoot string) {
+	if len(graph.Edges) == 0 {
+		fmt.Println("🕸️  No links between tasks found.")
+		return
+	}
+
+	fmt.Printf("🕸️  Task links (%d tasks, %d links):\n", len(graph.Nodes), len(graph.Edges))
+
+	for _, node := range graph.Nodes {
+		var lines []string
+
+		for _, edge := range graph.Edges {
+			if edge.From == node.ID {
+				lines = append(lines, fmt.Sprintf("%s → %s", edge.Kind, describeGraphNode(graph.Node(edge.To))))
+			}
+		}
+
+		for _, edge := range graph.Edges {
+			if edge.To == node.ID {
+				lines = append(lines, fmt.Sprintf("%s ← %s", edge.Kind, describeGraphNode(graph.Node(edge.From))))
+			}
+		}
+
+		fmt.Println()
+
+		if node.Missing {
+			fmt.Printf("%s ❓ no such task\n", node.Name)
+		} else {
+			fmt.Printf("%s (%s)\n", node.Name, getTaskSourceDisplay(&config.Task{Type: node.Type}))
+			fmt.Printf("  in %s\n", relativeSource(projectRoot, node.Source))
+		}
+
+		for i, line := range lines {
+			branch := "├─"
+			if i == len(lines)-1 {
+				branch = "└─"
+			}
+
+			fmt.Printf("  %s %s\n", branch, line)
+		}
+	}
+}
+
+// describeGraphNode names a node together with its source, or marks it as missing
+func describeGraphNode(node *config.GraphNode) string {
+	if node.Missing {
+		return node.Name + " ❓"
+	}
+
+	return fmt.Sprintf("%s (%s)", node.Name, getTaskSourceDisplay(&config.Task{Type: node.Type}))
+}
+
+// graphNodeColors fills DOT nodes by the kind of configuration they come from
+var graphNodeColors = map[config.TaskType]string{
+	config.TypeVSCodeTask:    "lightblue",
+	config.TypeVSCodeLaunch:  "lightsteelblue",
+	config.TypeJetBrains:     "lightsalmon",
+	config.TypeSublime:       "khaki",
+	config.TypeGitHubActions: "lightgrey",
+	config.TypeGlobal:        "palegreen",
+}
+
+// writeGraphDOT writes the graph in Graphviz DOT format, with nodes colored by source and
+// sources as node tooltips. Nodes and edges keep the graph's order, so the output is diffable.
+func writeGraphDOT(w io.Writer, graph *config.TaskGraph, projectRoot string) error {
+	lines := []string{
+		"digraph taskporter {",
+		"  rankdir=LR;",
+		"  node [shape=box, style=filled, fillcolor=white];",
+	}
+
+	for _, node := range graph.Nodes {
+		if node.Missing {
+			lines = append(lines, fmt.Sprintf("  %s [label=%s, style=dashed, color=red];", node.ID, strconv.Quote(node.Name)))
+			continue
+		}
+
+		fill := ""
+		if color, ok := graphNodeColors[node.Type]; ok {
+			fill = ", fillcolor=" + color
+		}
+
+		label := node.Name + "\n" + getTaskSourceDisplay(&config.Task{Type: node.Type})
+		lines = append(lines, fmt.Sprintf("  %s [label=%s, tooltip=%s%s];", node.ID, strconv.Quote(label), strconv.Quote(relativeSource(projectRoot, node.Source)), fill))
+	}
+
+	for _, edge := range graph.Edges {
+		lines = append(lines, fmt.Sprintf("  %s -> %s [label=%s];", edge.From, edge.To, strconv.Quote(edge.Kind)))
+	}
+
+	lines = append(lines, "}")
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// relativeSource shortens a source file path to be relative to the project root when it is inside it
+func relativeSource(projectRoot string, source string) string {
+	rel, err := filepath.Rel(projectRoot, source)
+	if err != nil || !filepath.IsLocal(rel) {
+		return source
+	}
+
+	return rel
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGraphDOT(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		fixture  string
+		expected string
+	}{
+		{
+			fixture: "testdata",
+			expected: `digraph taskporter {
+  rankdir=LR;
+  node [shape=box, style=filled, fillcolor=white];
+  n0 [label="build\nVSCode Task", tooltip=".vscode/tasks.json", fillcolor=lightblue];
+  n1 [label="run-dev\nVSCode Task", tooltip=".vscode/tasks.json", fillcolor=lightblue];
+  n2 [label="Launch taskporter\nVSCode Launch", tooltip=".vscode/launch.json", fillcolor=lightsteelblue];
+  n1 -> n0 [label="dependsOn"];
+  n2 -> n0 [label="preLaunchTask"];
+}
+`,
+		},
+		{
+			fixture: "jetbrains-testdata",
+			expected: `digraph taskporter {
+  rankdir=LR;
+  node [shape=box, style=filled, fillcolor=white];
+  n0 [label="Gradle Build\nJetBrains", tooltip=".idea/runConfigurations/Gradle_Build.xml", fillcolor=lightsalmon];
+  n1 [label="Spring Boot App\nJetBrains", tooltip=".idea/runConfigurations/Spring_Boot_App.xml", fillcolor=lightsalmon];
+  n1 -> n0 [label="beforeRun"];
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("should render the links of the "+tt.fixture+" fixtures", func(t *testing.T) {
+			projectRoot, err := filepath.Abs(filepath.Join("..", "test", tt.fixture))
+			require.NoError(t, err)
+
+			tasks, err := getAllTasksQuiet(projectRoot, &logOptions{})
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			require.NoError(t, writeGraphDOT(&out, config.BuildTaskGraph(tasks), projectRoot))
+			require.Equal(t, tt.expected, filepath.ToSlash(out.String()))
+		})
+	}
+}
@@ -1,20 +1,38 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/parser/githubactions"
 	"github.com/syndbg/taskporter/internal/parser/jetbrains"
+	"github.com/syndbg/taskporter/internal/parser/sublime"
 	"github.com/syndbg/taskporter/internal/parser/vscode"
 
 	"github.com/spf13/cobra"
 )
 
-func NewListCommand(verbose *bool, outputFormat *string, configPath *string) *cobra.Command {
-	return &cobra.Command{
+func NewListCommand(verbose *bool, outputFormat *string, configPath *string, logOpts *logOptions) *cobra.Command {
+	var (
+		groupFilter  string
+		sourceFilter string
+		changedSince string
+		graphFormat  string
+		tagFilter    []string
+	)
+
+	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List available tasks and launch configurations",
 		Long: `List all discoverable tasks and launch configurations from supported editors.
@@ -22,18 +40,63 @@ func NewListCommand(verbose *bool, outputFormat *string, configPath *string) *co
 Scans for configuration files in the current project:
 - VSCode: .vscode/tasks.json, .vscode/launch.json
 - JetBrains: .idea/runConfigurations/*.xml
+- Sublime Text: *.sublime-project build systems
+- GitHub Actions: run: steps of .github/workflows/*.yml (read-only)
+
+Use --group, --source and --tag to narrow the listing, and --changed-since to show only
+tasks whose configuration file was modified recently (e.g. --changed-since 2h).
+JSON output includes per-source metadata and a catalog hash for change detection.
+
+Tasks that run other tasks first are listed with "→ depends on: build, lint". Use
+--graph dot to print those links (dependsOn, preLaunchTask, postDebugTask and JetBrains
+before-run steps) for every task as a Graphviz DOT graph instead, e.g. for docs:
+
+  taskporter list --graph dot | dot -Tsvg > tasks.svg
 
 Establishing connections to available configurations...`,
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := runListCommand(*verbose, *outputFormat, *configPath); err != nil {
+			if err := runListCommand(*verbose, *outputFormat, *configPath, groupFilter, sourceFilter, tagFilter, changedSince, graphFormat, logOpts); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 		},
 	}
+
+	listCmd.Flags().StringVar(&groupFilter, "group", "", "only list tasks in this group (e.g. build, test)")
+	listCmd.Flags().StringVar(&sourceFilter, "source", "", "only list tasks from this source (vscode-task, vscode-launch, jetbrains, sublime-build, github-actions, global)")
+	listCmd.Flags().StringSliceVar(&tagFilter, "tag", nil, "only list tasks carrying this tag (repeatable, all must match)")
+	listCmd.Flags().StringVar(&changedSince, "changed-since", "", "only list tasks whose source file changed after an RFC 3339 time or a duration ago (e.g. 2h)")
+	listCmd.Flags().StringVar(&graphFormat, "graph", "", "print the links between all tasks instead of listing them (dot)")
+
+	// The graph covers every task, so links into filtered-out tasks don't look missing
+	for _, filter := range []string{"group", "source", "tag", "changed-since"} {
+		listCmd.MarkFlagsMutuallyExclusive("graph", filter)
+	}
+
+	_ = listCmd.RegisterFlagCompletionFunc("group", validTaskGroups)
+	_ = listCmd.RegisterFlagCompletionFunc("source", validTaskSources)
+	_ = listCmd.RegisterFlagCompletionFunc("tag", validTaskTags)
+
+	return listCmd
 }
 
-func runListCommand(verbose bool, outputFormat string, configPath string) error {
+func runListCommand(verbose bool, outputFormat string, configPath string, groupFilter string, sourceFilter string, tagFilter []string, changedSince string, graphFormat string, logOpts *logOptions) error {
+	logger, err := logOpts.newLogger(os.Stderr, verbose)
+	if err != nil {
+		return err
+	}
+
+	if graphFormat != "" && graphFormat != "dot" {
+		return fmt.Errorf("unsupported graph format '%s' (supported: dot)", graphFormat)
+	}
+
+	var since time.Time
+	if changedSince != "" {
+		if since, err = config.ParseChangedSince(changedSince, time.Now()); err != nil {
+			return err
+		}
+	}
+
 	if verbose {
 		fmt.Println("🔍 Scanning for configuration files...")
 	}
@@ -45,7 +108,7 @@ func runListCommand(verbose bool, outputFormat string, configPath string) error
 	}
 
 	// Initialize project detector
-	detector := config.NewProjectDetector(projectRoot)
+	detector := logOpts.newProjectDetector(projectRoot)
 
 	projectConfig, err := detector.DetectProject()
 	if err != nil {
@@ -56,24 +119,32 @@ func runListCommand(verbose bool, outputFormat string, configPath string) error
 		fmt.Printf("📁 Project root: %s\n", projectConfig.ProjectRoot)
 		fmt.Printf("🔧 VSCode detected: %v\n", projectConfig.HasVSCode)
 		fmt.Printf("🧠 JetBrains detected: %v\n", projectConfig.HasJetBrains)
+		fmt.Printf("📝 Sublime Text detected: %v\n", projectConfig.HasSublime)
+		fmt.Printf("🐙 GitHub Actions detected: %v\n", projectConfig.HasGitHubActions)
 	}
 
 	var allTasks []*config.Task
 
 	// Parse VSCode tasks
 	if projectConfig.HasVSCode {
+		settings := loadVSCodeSettings(detector, projectConfig.ProjectRoot, verbose, logger)
+
 		if tasksPath := detector.GetVSCodeTasksPath(); tasksPath != "" {
 			if verbose {
 				fmt.Printf("📋 Parsing VSCode tasks from: %s\n", tasksPath)
 			}
 
-			parser := vscode.NewTasksParser(projectConfig.ProjectRoot)
+			parser := vscode.NewTasksParserWithSettings(projectConfig.ProjectRoot, settings, logger)
+			parser.SetStrict(logOpts.strict)
+			parser.SetContext(detector.Context())
 
 			tasks, err := parser.ParseTasks(tasksPath)
 			if err != nil {
-				if verbose {
-					fmt.Printf("⚠️  Warning: failed to parse VSCode tasks: %v\n", err)
+				if logOpts.strict {
+					return fmt.Errorf("failed to parse VSCode tasks: %w", err)
 				}
+
+				logger.Warn("failed to parse VSCode tasks", logging.KeyFile, tasksPath, "error", err)
 			} else {
 				allTasks = append(allTasks, tasks...)
 				if verbose {
@@ -88,14 +159,11 @@ func runListCommand(verbose bool, outputFormat string, configPath string) error
 				fmt.Printf("🚀 Parsing VSCode launch configs from: %s\n", launchPath)
 			}
 
-			launchParser := vscode.NewLaunchParser(projectConfig.ProjectRoot)
+			launchParser := vscode.NewLaunchParserWithSettings(projectConfig.ProjectRoot, settings, logger)
+			launchParser.SetContext(detector.Context())
 
-			launchTasks, err := launchParser.ParseLaunchConfigs(launchPath)
-			if err != nil {
-				if verbose {
-					fmt.Printf("⚠️  Warning: failed to parse VSCode launch configs: %v\n", err)
-				}
-			} else {
+			launchTasks, err := logOpts.launchFailures.parse(launchParser, launchPath, logger)
+			if err == nil {
 				allTasks = append(allTasks, launchTasks...)
 				if verbose {
 					fmt.Printf("✅ Found %d VSCode launch configurations\n", len(launchTasks))
@@ -111,23 +179,15 @@ func runListCommand(verbose bool, outputFormat string, configPath string) error
 			fmt.Printf("🧠 Parsing JetBrains configurations from: %d files\n", len(jetbrainsPaths))
 		}
 
-		parser := jetbrains.NewRunConfigurationParser(projectConfig.ProjectRoot)
-
-		for _, configPath := range jetbrainsPaths {
-			if verbose {
+		if verbose {
+			for _, configPath := range jetbrainsPaths {
 				fmt.Printf("   📄 %s\n", configPath)
 			}
-
-			task, err := parser.ParseRunConfiguration(configPath)
-			if err != nil {
-				if verbose {
-					fmt.Printf("⚠️  Warning: failed to parse JetBrains config %s: %v\n", configPath, err)
-				}
-			} else {
-				allTasks = append(allTasks, task)
-			}
 		}
 
+		parser := newJetBrainsParser(detector.Context(), projectConfig.ProjectRoot, logger)
+		allTasks = append(allTasks, parseJetBrainsRunConfigs(parser, jetbrainsPaths, showScanProgress(verbose, logger), logger)...)
+
 		if verbose && len(jetbrainsPaths) > 0 {
 			jetbrainsTaskCount := 0
 
@@ -141,19 +201,371 @@ func runListCommand(verbose bool, outputFormat string, configPath string) error
 		}
 	}
 
+	// Parse Sublime Text build systems
+	if projectConfig.HasSublime {
+		allTasks = append(allTasks, parseSublimeProjects(detector, projectConfig.ProjectRoot, verbose, logger)...)
+	}
+
+	// Parse GitHub Actions workflow steps
+	if projectConfig.HasGitHubActions {
+		allTasks = append(allTasks, parseGitHubWorkflows(detector, projectConfig.ProjectRoot, verbose, logger)...)
+	}
+
+	if err := logOpts.operationErr(); err != nil {
+		return err
+	}
+
+	if verbose {
+		reportIgnoredPaths(detector)
+	}
+
+	allTasks = withGlobalTasks(allTasks, projectConfig.ProjectRoot, logOpts.noGlobal, verbose, logger)
+	allTasks, err = withTaskExtensions(allTasks, projectConfig.ProjectRoot, logger)
+	if err != nil {
+		return err
+	}
+
+	applyConfiguredTags(allTasks, projectConfig.ProjectRoot, logger)
+
+	aliases := loadAliases(projectConfig.ProjectRoot, allTasks, logger)
+	config.ApplyAliases(allTasks, aliases)
+
+	if graphFormat != "" {
+		return writeGraphDOT(os.Stdout, config.BuildTaskGraph(allTasks), projectConfig.ProjectRoot)
+	}
+
+	// Source metadata describes the whole catalog, independent of the filters below
+	sources, failures := config.CollectSources(allTasks)
+	for path, err := range failures {
+		logger.Warn("failed to read task source", logging.KeyFile, path, "error", err)
+	}
+
+	// Apply filters
+	allTasks = filterTasksByGroupAndSource(allTasks, groupFilter, sourceFilter)
+	allTasks = config.FilterByTags(allTasks, tagFilter)
+
+	if changedSince != "" {
+		allTasks = config.FilterChangedSince(allTasks, sources, since)
+	}
+
 	// Display results
-	return displayTasks(allTasks, outputFormat)
+	return displayTasks(allTasks, sources, outputFormat, verbose)
+}
+
+// filterTasksByGroupAndSource keeps tasks matching the given group and source (empty matches all)
+func filterTasksByGroupAndSource(tasks []*config.Task, group string, source string) []*config.Task {
+	if group == "" && source == "" {
+		return tasks
+	}
+
+	filtered := make([]*config.Task, 0, len(tasks))
+
+	for _, task := range tasks {
+		if group != "" && !strings.EqualFold(task.Group, group) {
+			continue
+		}
+
+		if source != "" && !strings.EqualFold(string(task.Type), source) {
+			continue
+		}
+
+		filtered = append(filtered, task)
+	}
+
+	return filtered
+}
+
+// newJetBrainsParser creates a run configuration parser that applies the project's run
+// configuration templates. Unreadable templates are only warned about.
+func newJetBrainsParser(ctx context.Context, projectRoot string, logger *slog.Logger) *jetbrains.RunConfigurationParser {
+	parser := jetbrains.NewRunConfigurationParser(projectRoot, logger)
+	parser.SetContext(ctx)
+
+	templates, err := config.LoadRunConfigTemplates(ctx, projectRoot)
+	if err != nil {
+		logger.Warn("failed to read JetBrains run configuration templates", "error", err)
+		return parser
+	}
+
+	parser.SetTemplates(templates)
+
+	return parser
+}
+
+// logJetBrainsParseError warns about a run configuration that failed to parse. Templates, which
+// IntelliJ creates routinely, and configurations of types taskporter doesn't run are skipped
+// quietly.
+func logJetBrainsParseError(logger *slog.Logger, path string, err error) {
+	if errors.Is(err, jetbrains.ErrTemplate) {
+		logger.Debug("skipping JetBrains run configuration template", logging.KeyFile, path)
+		return
+	}
+
+	if errors.Is(err, jetbrains.ErrUnsupportedType) {
+		logger.Debug("skipping unsupported JetBrains configuration", logging.KeyFile, path, "error", err)
+		return
+	}
+
+	logger.Warn("failed to parse JetBrains config", logging.KeyFile, path, "error", err)
+}
+
+// parseJetBrainsRunConfigs parses run configuration files concurrently, keeping their order.
+// Parse errors are logged once every file is done, after the progress spinner is gone.
+func parseJetBrainsRunConfigs(parser *jetbrains.RunConfigurationParser, paths []string, showProgress bool, logger *slog.Logger) []*config.Task {
+	progress := startScanProgress(os.Stderr, len(paths), showProgress)
+
+	tasks := make([]*config.Task, len(paths))
+	errs := make([]error, len(paths))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(runtime.NumCPU(), len(paths)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range next {
+				tasks[i], errs[i] = parser.ParseRunConfiguration(paths[i])
+				progress.Add(1)
+			}
+		}()
+	}
+
+	for i := range paths {
+		next <- i
+	}
+
+	close(next)
+	wg.Wait()
+	progress.Stop()
+
+	var parsed []*config.Task
+
+	for i, path := range paths {
+		if errs[i] != nil {
+			logJetBrainsParseError(logger, path, errs[i])
+			continue
+		}
+
+		parsed = append(parsed, tasks[i])
+	}
+
+	return parsed
+}
+
+// parseSublimeProjects parses build systems from every .sublime-project file, skipping invalid files
+func parseSublimeProjects(detector *config.ProjectDetector, projectRoot string, verbose bool, logger *slog.Logger) []*config.Task {
+	parser := sublime.NewProjectParser(projectRoot, logger)
+	parser.SetContext(detector.Context())
+
+	var tasks []*config.Task
+
+	for _, projectPath := range detector.GetSublimeProjectPaths() {
+		if verbose {
+			fmt.Printf("📝 Scanning Sublime Text build systems from: %s\n", projectPath)
+		}
+
+		projectTasks, err := parser.ParseProject(projectPath)
+		if err != nil {
+			logger.Warn("failed to parse Sublime Text project", logging.KeyFile, projectPath, "error", err)
+
+			continue
+		}
+
+		tasks = append(tasks, projectTasks...)
+	}
+
+	return tasks
 }
 
-func displayTasks(tasks []*config.Task, outputFormat string) error {
+// parseGitHubWorkflows parses run: steps from every workflow file, skipping invalid files
+func parseGitHubWorkflows(detector *config.ProjectDetector, projectRoot string, verbose bool, logger *slog.Logger) []*config.Task {
+	parser := githubactions.NewWorkflowParser(projectRoot, logger)
+	parser.SetContext(detector.Context())
+
+	var tasks []*config.Task
+
+	for _, workflowPath := range detector.GetGitHubWorkflowPaths() {
+		if verbose {
+			fmt.Printf("🐙 Scanning GitHub Actions steps from: %s\n", workflowPath)
+		}
+
+		workflowTasks, err := parser.ParseWorkflow(workflowPath)
+		if err != nil {
+			logger.Warn("failed to parse GitHub Actions workflow", logging.KeyFile, workflowPath, "error", err)
+
+			continue
+		}
+
+		tasks = append(tasks, workflowTasks...)
+	}
+
+	return tasks
+}
+
+// withGlobalTasks adds the tasks from the user-level tasks file, unless disabled with --no-global.
+// Project tasks win on name collision.
+func withGlobalTasks(tasks []*config.Task, projectRoot string, noGlobal bool, verbose bool, logger *slog.Logger) []*config.Task {
+	if noGlobal {
+		return tasks
+	}
+
+	globalPath, err := config.GlobalTasksPath()
+	if err != nil {
+		logger.Debug("skipping global tasks", "error", err)
+		return tasks
+	}
+
+	if _, err := os.Stat(globalPath); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("failed to read global tasks", logging.KeyFile, globalPath, "error", err)
+		}
+
+		return tasks
+	}
+
+	if verbose {
+		fmt.Printf("🌐 Parsing global tasks from: %s\n", globalPath)
+	}
+
+	// Global tasks run in the current project, like its own tasks
+	globalTasks, err := vscode.NewTasksParser(projectRoot, logger).ParseTasks(globalPath)
+	if err != nil {
+		logger.Warn("failed to parse global tasks", logging.KeyFile, globalPath, "error", err)
+		return tasks
+	}
+
+	for _, task := range globalTasks {
+		task.Type = config.TypeGlobal
+	}
+
+	merged, shadowed := config.MergeGlobalTasks(tasks, globalTasks)
+	for _, task := range shadowed {
+		logger.Debug("project task shadows global task", logging.KeyTask, task.Name, logging.KeyFile, globalPath)
+	}
+
+	return merged
+}
+
+// applyConfiguredTags adds the tags taskporter's config files assign to tasks by name
+func applyConfiguredTags(tasks []*config.Task, projectRoot string, logger *slog.Logger) {
+	tags, err := config.LoadTaskTags(projectRoot)
+	if err != nil {
+		logger.Warn("failed to load task tags", "error", err)
+		return
+	}
+
+	config.ApplyTaskTags(tasks, tags)
+}
+
+// reportIgnoredPaths lists the configurations .taskporterignore or .gitignore left out of discovery
+func reportIgnoredPaths(detector *config.ProjectDetector) {
+	for _, ignored := range detector.IgnoredPaths() {
+		fmt.Printf("🙈 Skipping %s (ignored by %s)\n", ignored.Path, ignored.Rule)
+	}
+}
+
+// withTaskExtensions adds the tasks defined with "extends" in taskporter's config files, with
+// their references to the project and other tasks resolved, warning about extensions that
+// cannot be defined
+func withTaskExtensions(tasks []*config.Task, projectRoot string, logger *slog.Logger) ([]*config.Task, error) {
+	extensions, err := config.LoadTaskExtensions(projectRoot)
+	if err != nil {
+		logger.Warn("failed to load task extensions", "error", err)
+		return tasks, nil
+	}
+
+	merged, err := config.ResolveTaskExtensions(tasks, extensions)
+	if err := warnSkippedExtensions(err, logger); err != nil {
+		return nil, err
+	}
+
+	// References are resolved once the whole catalog, extensions included, is known
+	merged, err = config.InterpolateTaskExtensions(merged, extensions, projectRoot)
+	if err := warnSkippedExtensions(err, logger); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// warnSkippedExtensions warns about each extension a joined error reports as left out, except
+// those in an extends cycle: a cycle is a broken config rather than one bad entry, so they are
+// returned as the error instead
+func warnSkippedExtensions(err error, logger *slog.Logger) error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil
+	}
+
+	var cycles []error
+
+	for _, err := range joined.Unwrap() {
+		var cycleErr *config.ExtendsCycleError
+		if errors.As(err, &cycleErr) {
+			cycles = append(cycles, err)
+			continue
+		}
+
+		logger.Warn("skipping task extension", "error", err)
+	}
+
+	return errors.Join(cycles...)
+}
+
+// loadAliases reads the user and project aliases, warning about config files that cannot be
+// read and about aliases a real task shadows
+func loadAliases(projectRoot string, tasks []*config.Task, logger *slog.Logger) []config.Alias {
+	aliases, err := config.LoadAliases(projectRoot)
+	if err != nil {
+		logger.Warn("failed to load aliases", "error", err)
+		return nil
+	}
+
+	for _, issue := range config.CheckAliases(aliases, tasks) {
+		if issue.Kind == config.AliasShadowed {
+			logger.Warn("alias shadowed by a task with the same name, using the task", "alias", issue.Alias, "target", issue.Target, logging.KeyFile, issue.Source)
+		}
+	}
+
+	return aliases
+}
+
+// loadVSCodeSettings parses .vscode/settings.json if present, returning nil when absent or invalid
+func loadVSCodeSettings(detector *config.ProjectDetector, projectRoot string, verbose bool, logger *slog.Logger) *vscode.VSCodeSettings {
+	settingsPath := detector.GetVSCodeSettingsPath()
+	if settingsPath == "" {
+		return nil
+	}
+
+	if verbose {
+		fmt.Printf("⚙️  Reading VSCode settings from: %s\n", settingsPath)
+	}
+
+	parser := vscode.NewSettingsParser(projectRoot)
+	parser.SetContext(detector.Context())
+
+	settings, err := parser.ParseSettings(settingsPath)
+	if err != nil {
+		logger.Warn("failed to parse VSCode settings", logging.KeyFile, settingsPath, "error", err)
+
+		return nil
+	}
+
+	return settings
+}
+
+func displayTasks(tasks []*config.Task, sources []*config.SourceInfo, outputFormat string, verbose bool) error {
 	if outputFormat == "json" {
-		return displayTasksJSON(tasks)
+		return displayTasksJSON(tasks, sources)
 	}
 
-	return displayTasksText(tasks)
+	return displayTasksText(tasks, sources, verbose)
 }
 
-func displayTasksText(tasks []*config.Task) error {
+func displayTasksText(tasks []*config.Task, sources []*config.SourceInfo, verbose bool) error {
 	fmt.Println("📦 Available Tasks & Launch Configurations:")
 	fmt.Println()
 
@@ -161,6 +573,8 @@ func displayTasksText(tasks []*config.Task) error {
 		fmt.Println("No configurations found. Ensure you're in a project directory with:")
 		fmt.Println("  • .vscode/tasks.json or .vscode/launch.json")
 		fmt.Println("  • .idea/runConfigurations/*.xml")
+		fmt.Println("  • *.sublime-project")
+		fmt.Println("  • .github/workflows/*.yml")
 		fmt.Println()
 		fmt.Println("📡 Strand connection pending... no active configurations detected.")
 
@@ -184,12 +598,10 @@ func displayTasksText(tasks []*config.Task) error {
 				fmt.Printf(" [%s]", task.Group)
 			}
 
-			fmt.Printf(" - %s", task.Command)
-
-			if len(task.Args) > 0 {
-				fmt.Printf(" %v", task.Args)
-			}
+			printTaskMarkers(task)
 
+			printTaskCommand(task)
+			printTaskDependencies(task)
 			fmt.Println()
 
 			if task.Description != "" {
@@ -211,12 +623,10 @@ func displayTasksText(tasks []*config.Task) error {
 				fmt.Printf(" [%s]", task.Group)
 			}
 
-			fmt.Printf(" - %s", task.Command)
-
-			if len(task.Args) > 0 {
-				fmt.Printf(" %v", task.Args)
-			}
+			printTaskMarkers(task)
 
+			printTaskCommand(task)
+			printTaskDependencies(task)
 			fmt.Println()
 
 			if task.Description != "" {
@@ -232,7 +642,66 @@ func displayTasksText(tasks []*config.Task) error {
 		fmt.Printf("🧠 JetBrains Run Configurations (%d):\n", len(jbTasks))
 
 		for _, task := range jbTasks {
-			fmt.Printf("  • %s - %s %v\n", task.Name, task.Command, task.Args)
+			fmt.Printf("  • %s", task.Name)
+			printTaskMarkers(task)
+			printTaskCommand(task)
+			printTaskDependencies(task)
+			fmt.Println()
+		}
+
+		fmt.Println()
+	}
+
+	// Display Sublime Text build systems
+	if sublimeTasks := tasksByType[config.TypeSublime]; len(sublimeTasks) > 0 {
+		fmt.Printf("📝 Sublime Text Build Systems (%d):\n", len(sublimeTasks))
+
+		for _, task := range sublimeTasks {
+			fmt.Printf("  • %s", task.Name)
+			printTaskMarkers(task)
+			printTaskCommand(task)
+			printTaskDependencies(task)
+			fmt.Println()
+		}
+
+		fmt.Println()
+	}
+
+	// Display GitHub Actions steps
+	if githubTasks := tasksByType[config.TypeGitHubActions]; len(githubTasks) > 0 {
+		fmt.Printf("🐙 GitHub Actions Steps (%d):\n", len(githubTasks))
+
+		for _, task := range githubTasks {
+			fmt.Printf("  • %s", task.Name)
+			printTaskMarkers(task)
+			printTaskCommand(task)
+			printTaskDependencies(task)
+			fmt.Println()
+		}
+
+		fmt.Println()
+	}
+
+	// Display global tasks
+	if globalTasks := tasksByType[config.TypeGlobal]; len(globalTasks) > 0 {
+		fmt.Printf("🌐 Global Tasks (%d):\n", len(globalTasks))
+
+		for _, task := range globalTasks {
+			fmt.Printf("  • %s", task.Name)
+			printTaskMarkers(task)
+			printTaskCommand(task)
+			printTaskDependencies(task)
+			fmt.Println()
+		}
+
+		fmt.Println()
+	}
+
+	if verbose && len(sources) > 0 {
+		fmt.Printf("📂 Sources (%d):\n", len(sources))
+
+		for _, source := range sources {
+			fmt.Printf("  • %s (%d tasks, modified %s)\n", source.Path, source.TaskCount, formatAge(time.Since(source.ModTime)))
 		}
 
 		fmt.Println()
@@ -243,10 +712,59 @@ func displayTasksText(tasks []*config.Task) error {
 	return nil
 }
 
-func displayTasksJSON(tasks []*config.Task) error {
+// printTaskCommand prints the command a task runs, or for aggregate tasks the tasks they run
+func printTaskCommand(task *config.Task) {
+	switch {
+	case task.IsCompound():
+		fmt.Printf(" - runs %s in parallel", strings.Join(task.DependsOn, ", "))
+	case task.IsAggregate():
+		fmt.Printf(" - runs %s", strings.Join(task.DependsOn, " → "))
+	default:
+		fmt.Printf(" - %s", task.Command)
+
+		if len(task.Args) > 0 {
+			fmt.Printf(" %v", task.Args)
+		}
+	}
+}
+
+// printTaskDependencies prints the tasks that run before this one, unless printTaskCommand
+// already listed them as all the task runs
+func printTaskDependencies(task *config.Task) {
+	var names []string
+
+	if task.PreLaunchTask != "" {
+		names = append(names, task.PreLaunchTask)
+	}
+
+	if !task.IsAggregate() {
+		names = append(names, task.DependsOn...)
+	}
+
+	names = append(names, task.BeforeRun...)
+
+	if len(names) > 0 {
+		fmt.Printf(" → depends on: %s", strings.Join(names, ", "))
+	}
+}
+
+// printTaskMarkers prints the task's aliases and tags inline after its name
+func printTaskMarkers(task *config.Task) {
+	if len(task.Aliases) > 0 {
+		fmt.Printf(" (alias: %s)", strings.Join(task.Aliases, ", "))
+	}
+
+	if len(task.Tags) > 0 {
+		fmt.Printf(" #%s", strings.Join(task.Tags, " #"))
+	}
+}
+
+func displayTasksJSON(tasks []*config.Task, sources []*config.SourceInfo) error {
 	output := map[string]interface{}{
-		"tasks": tasks,
-		"count": len(tasks),
+		"tasks":       tasks,
+		"count":       len(tasks),
+		"sources":     sources,
+		"catalogHash": config.CatalogHash(sources),
 	}
 
 	encoder := json.NewEncoder(os.Stdout)
@@ -254,3 +772,17 @@ func displayTasksJSON(tasks []*config.Task) error {
 
 	return encoder.Encode(output)
 }
+
+// formatAge renders how long ago something happened, e.g. "2h ago"
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	}
+}
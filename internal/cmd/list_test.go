@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterTasksByGroupAndSource(t *testing.T) {
+	build := &config.Task{Name: "build", Type: config.TypeVSCodeTask, Group: "build"}
+	test := &config.Task{Name: "test", Type: config.TypeVSCodeTask, Group: "test"}
+	debug := &config.Task{Name: "debug", Type: config.TypeVSCodeLaunch}
+	app := &config.Task{Name: "app", Type: config.TypeJetBrains, Group: "build"}
+	tasks := []*config.Task{build, test, debug, app}
+
+	tests := []struct {
+		name     string
+		group    string
+		source   string
+		expected []*config.Task
+	}{
+		{
+			name:     "no filters",
+			expected: tasks,
+		},
+		{
+			name:     "group only",
+			group:    "build",
+			expected: []*config.Task{build, app},
+		},
+		{
+			name:     "source only",
+			source:   "vscode-task",
+			expected: []*config.Task{build, test},
+		},
+		{
+			name:     "group and source",
+			group:    "build",
+			source:   "jetbrains",
+			expected: []*config.Task{app},
+		},
+		{
+			name:     "case-insensitive",
+			group:    "TEST",
+			source:   "VSCode-Task",
+			expected: []*config.Task{test},
+		},
+		{
+			name:     "no match",
+			group:    "deploy",
+			expected: []*config.Task{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, filterTasksByGroupAndSource(tasks, tt.group, tt.source))
+		})
+	}
+}
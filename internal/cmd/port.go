@@ -1,28 +1,45 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 
 	"github.com/syndbg/taskporter/internal/config"
 	"github.com/syndbg/taskporter/internal/converter"
-	"github.com/syndbg/taskporter/internal/parser/jetbrains"
 	"github.com/syndbg/taskporter/internal/parser/vscode"
 	"github.com/syndbg/taskporter/internal/security"
 
 	"github.com/spf13/cobra"
 )
 
-func NewPortCommand(verbose *bool, configPath *string) *cobra.Command {
-	var fromFormat string
+// portOptions holds the flags of the port command
+type portOptions struct {
+	from           string
+	to             string
+	dryRun         bool
+	fullPreview    bool
+	output         string // Directory, file or path template the converted configurations go to
+	paranoidMode   bool
+	force          bool
+	modernize      bool
+	showSkipped    bool
+	applyTemplates bool
+	shell          string // Script dialect for --to shell
+	targetOS       string
+	report         string // JSON file with how each configuration was ported
+	minFidelity    string
+}
 
-	var (
-		toFormat     string
-		dryRun       bool
-		outputPath   string
-		paranoidMode bool
-	)
+func NewPortCommand(verbose *bool, configPath *string, logOpts *logOptions) *cobra.Command {
+	var opts portOptions
 
 	portCmd := &cobra.Command{
 		Use:   "port",
@@ -32,6 +49,9 @@ func NewPortCommand(verbose *bool, configPath *string) *cobra.Command {
 Supports conversion between:
 - VSCode tasks.json ↔ JetBrains run configurations
 - VSCode launch.json ↔ JetBrains run configurations
+- VSCode tasks.json → Makefile targets
+- JetBrains run configurations → run.sh shell script
+- Legacy VSCode tasks.json (version 0.1.0) → version 2.0.0
 
 This command helps bridge development workflows when switching between editors
 or working in mixed-IDE teams. Like a porter carrying cargo between stations!
@@ -46,15 +66,47 @@ Examples:
   # Convert JetBrains configs to VSCode launch format
   taskporter port --from jetbrains --to vscode-launch
 
-  # Dry run to preview changes
+  # Generate a Makefile with one target per VSCode task
+  taskporter port --from vscode-tasks --to makefile
+
+  # Generate a POSIX run.sh for IDE-less servers (./run.sh <name>)
+  taskporter port --from jetbrains --to shell --shell sh
+
+  # Rewrite a version 0.1.0 tasks.json in the current schema
+  taskporter port --from vscode-tasks --to vscode-tasks --modernize
+
+  # Dry run to preview changes: which run configurations would be added,
+  # updated or left unchanged
   taskporter port --from vscode-tasks --to jetbrains --dry-run
 
-  # Specify output path
+  # Review a regenerated tasks.json as a diff, and print it in full too
+  taskporter port --from jetbrains --to vscode-tasks --dry-run --full-preview
+
+  # Specify output path (a directory, or a .json file for VSCode targets)
   taskporter port --from vscode-tasks --to jetbrains --output .idea/runConfigurations/
+  taskporter port --from jetbrains --to vscode-tasks --output .vscode/tasks.json
+
+  # Group generated run configurations into folders ({name}, {source}, {group})
+  taskporter port --from vscode-tasks --to jetbrains --output '.idea/runConfigurations/{group}/{name}.xml'
+
+  # Give generated run configurations the defaults of the project's configuration templates
+  taskporter port --from vscode-tasks --to jetbrains --apply-templates
+
+  # Overwrite existing files that were not generated by taskporter
+  taskporter port --from jetbrains --to vscode-tasks --force
+
+  # List the run configuration files that were not ported, and why
+  taskporter port --from jetbrains --to vscode-tasks --show-skipped
+
+  # Use the launch.json "windows" blocks instead of the current platform's
+  taskporter port --from vscode-launch --to jetbrains --target-os windows
+
+  # Audit how every configuration would map before changing anything
+  taskporter port --from jetbrains --to vscode-tasks --dry-run --report mapping.json
 
 Establishing cross-platform development strand...`,
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := runPortCommand(fromFormat, toFormat, *verbose, *configPath, dryRun, outputPath, paranoidMode); err != nil {
+			if err := runPortCommand(*verbose, *configPath, logOpts, opts); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
@@ -62,11 +114,20 @@ Establishing cross-platform development strand...`,
 	}
 
 	// Add flags
-	portCmd.Flags().StringVar(&fromFormat, "from", "", "source format (vscode-tasks, vscode-launch, jetbrains)")
-	portCmd.Flags().StringVar(&toFormat, "to", "", "target format (vscode-tasks, vscode-launch, jetbrains)")
-	portCmd.Flags().BoolVar(&dryRun, "dry-run", false, "preview changes without writing files")
-	portCmd.Flags().StringVar(&outputPath, "output", "", "output directory (default: auto-detect)")
-	portCmd.Flags().BoolVar(&paranoidMode, "paranoid-mode", false, "Enable security validation of paths and content")
+	portCmd.Flags().StringVar(&opts.from, "from", "", "source format (vscode-tasks, vscode-launch, jetbrains)")
+	portCmd.Flags().StringVar(&opts.to, "to", "", "target format (vscode-tasks, vscode-launch, jetbrains, makefile, shell)")
+	portCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "preview changes without writing files: a diff against existing files, the content of new ones")
+	portCmd.Flags().BoolVar(&opts.fullPreview, "full-preview", false, "with --dry-run, also print the full content of every file that would be written")
+	portCmd.Flags().StringVar(&opts.output, "output", "", "output directory, .json file for VSCode targets, or path template with {name}, {source} and {group} for JetBrains (default: auto-detect)")
+	portCmd.Flags().BoolVar(&opts.paranoidMode, "paranoid-mode", false, "Enable security validation of paths and content")
+	portCmd.Flags().BoolVar(&opts.force, "force", false, "overwrite existing files that were not generated by taskporter")
+	portCmd.Flags().BoolVar(&opts.modernize, "modernize", false, "rewrite a legacy (version 0.1.0) tasks.json in the 2.0.0 schema (with --from/--to vscode-tasks)")
+	portCmd.Flags().BoolVar(&opts.applyTemplates, "apply-templates", false, "layer the options and env of the project's JetBrains run configuration templates beneath generated configurations (with --to jetbrains)")
+	portCmd.Flags().BoolVar(&opts.showSkipped, "show-skipped", false, "list the JetBrains run configuration files that were skipped, with the reason")
+	portCmd.Flags().StringVar(&opts.targetOS, "target-os", "", "platform whose tasks.json and launch.json \"windows\", \"osx\" or \"linux\" blocks are applied (linux, darwin/osx, windows; default: current)")
+	portCmd.Flags().StringVar(&opts.shell, "shell", converter.ShellBash, "script dialect for --to shell (bash, sh)")
+	portCmd.Flags().StringVar(&opts.report, "report", "", "write a JSON report of how each configuration was ported to this file")
+	portCmd.Flags().StringVar(&opts.minFidelity, "min-fidelity", "", "fail, writing nothing unless --force, when a configuration converts below this level (partial, full)")
 
 	// Mark required flags
 	_ = portCmd.MarkFlagRequired("from")
@@ -78,39 +139,78 @@ Establishing cross-platform development strand...`,
 	})
 
 	_ = portCmd.RegisterFlagCompletionFunc("to", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"vscode-tasks", "vscode-launch", "jetbrains"}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"vscode-tasks", "vscode-launch", "jetbrains", "makefile", "shell"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	_ = portCmd.RegisterFlagCompletionFunc("shell", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{converter.ShellBash, converter.ShellPOSIX}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	_ = portCmd.RegisterFlagCompletionFunc("min-fidelity", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{converter.FidelityPartial, converter.FidelityFull}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	_ = portCmd.RegisterFlagCompletionFunc("report", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json"}, cobra.ShellCompDirectiveFilterFileExt
+	})
+
+	// Output is a directory for JetBrains targets, a directory or .json file for VSCode targets,
+	// a .sh file for shell scripts and any file for Makefiles
+	_ = portCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if strings.HasPrefix(opts.to, "vscode-") {
+			return []string{"json"}, cobra.ShellCompDirectiveFilterFileExt
+		}
+
+		if opts.to == "shell" {
+			return []string{"sh"}, cobra.ShellCompDirectiveFilterFileExt
+		}
+
+		if opts.to == "makefile" {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		return nil, cobra.ShellCompDirectiveFilterDirs
 	})
 
 	return portCmd
 }
 
-func runPortCommand(fromFormat, toFormat string, verbose bool, configPath string, dryRun bool, outputPath string, paranoidMode bool) error {
+func runPortCommand(verbose bool, configPath string, logOpts *logOptions, opts portOptions) error {
+	logger, err := logOpts.newLogger(os.Stderr, verbose)
+	if err != nil {
+		return err
+	}
+
 	// Create sanitizer for input validation (only used in paranoid mode)
 	sanitizer := security.NewSanitizer(".")
 
 	// Only validate inputs in paranoid mode
-	if paranoidMode {
+	if opts.paranoidMode {
 		// Validate config path if provided
 		if err := sanitizer.ValidateConfigPath(configPath); err != nil {
 			return fmt.Errorf("invalid config path: %w", err)
 		}
 
 		// Validate output path if provided
-		if err := sanitizer.ValidateOutputPath(outputPath); err != nil {
+		if err := sanitizer.ValidateOutputPath(opts.output); err != nil {
 			return fmt.Errorf("invalid output path: %w", err)
 		}
+
+		if err := sanitizer.ValidateOutputPath(opts.report); err != nil {
+			return fmt.Errorf("invalid report path: %w", err)
+		}
 	}
 
 	if verbose {
 		fmt.Printf("🚛 Preparing to port configurations...\n")
-		fmt.Printf("📤 From: %s\n", fromFormat)
-		fmt.Printf("📥 To: %s\n", toFormat)
+		fmt.Printf("📤 From: %s\n", opts.from)
+		fmt.Printf("📥 To: %s\n", opts.to)
 
-		if dryRun {
+		if opts.dryRun {
 			fmt.Printf("🔍 Mode: Dry run (preview only)\n")
 		}
 
-		if paranoidMode {
+		if opts.paranoidMode {
 			fmt.Printf("🛡️ Paranoid mode: Security validation enabled\n")
 		} else {
 			fmt.Printf("🤝 Trust mode: Processing configurations as-is\n")
@@ -120,176 +220,421 @@ func runPortCommand(fromFormat, toFormat string, verbose bool, configPath string
 	}
 
 	// Validate format combinations
-	if err := validateFormatCombination(fromFormat, toFormat); err != nil {
+	if err := validateFormatCombination(opts.from, opts.to, opts.modernize); err != nil {
+		return err
+	}
+
+	if converter.IsOutputTemplate(opts.output) && opts.to != "jetbrains" {
+		return fmt.Errorf("output template %s only applies to --to jetbrains, which writes one file per configuration", opts.output)
+	}
+
+	if opts.fullPreview && !opts.dryRun {
+		return fmt.Errorf("--full-preview only applies to --dry-run")
+	}
+
+	if opts.applyTemplates && opts.to != "jetbrains" {
+		return fmt.Errorf("--apply-templates only applies to --to jetbrains")
+	}
+
+	if opts.shell != converter.ShellBash && opts.shell != converter.ShellPOSIX {
+		return fmt.Errorf("invalid shell '%s'. Valid options: %s, %s", opts.shell, converter.ShellBash, converter.ShellPOSIX)
+	}
+
+	goos, err := parseTargetOS(opts.targetOS)
+	if err != nil {
 		return err
 	}
 
+	var minimum string
+	if opts.minFidelity != "" {
+		if minimum, err = converter.ParseMinFidelity(opts.minFidelity); err != nil {
+			return err
+		}
+	}
+
 	// Determine project root
 	projectRoot := "."
 	if configPath != "" {
 		projectRoot = filepath.Dir(configPath)
 	}
 
+	guard := newOverwriteGuard(opts.force)
+	ctx := logOpts.operationContext()
+
+	var templates config.RunConfigTemplates
+	if opts.applyTemplates {
+		if templates, err = loadPortTemplates(ctx, projectRoot, verbose); err != nil {
+			return err
+		}
+	}
+
 	// Execute the conversion based on format combination
-	switch {
-	case fromFormat == "vscode-tasks" && toFormat == "jetbrains":
-		return convertVSCodeTasksToJetBrains(projectRoot, outputPath, verbose, dryRun)
-	case fromFormat == "jetbrains" && toFormat == "vscode-tasks":
-		return convertJetBrainsToVSCodeTasks(projectRoot, outputPath, verbose, dryRun)
-	case fromFormat == "jetbrains" && toFormat == "vscode-launch":
-		return convertJetBrainsToVSCodeLaunch(projectRoot, outputPath, verbose, dryRun)
-	case fromFormat == "vscode-launch" && toFormat == "jetbrains":
-		return convertVSCodeLaunchToJetBrains(projectRoot, outputPath, verbose, dryRun)
-	default:
-		fmt.Printf("🚧 Conversion from %s to %s is not yet implemented!\n", fromFormat, toFormat)
-		fmt.Printf("📋 Planned conversion: %s → %s\n", fromFormat, toFormat)
+	convert := func(out io.Writer, dryRun bool, report *converter.Report, logger *slog.Logger) error {
+		write := converter.WriteOptions{Context: ctx, Out: out, Guard: guard, FullPreview: opts.fullPreview, Report: report}
+
+		var err error
+
+		switch {
+		case opts.modernize:
+			err = modernizeVSCodeTasks(logOpts, write, projectRoot, opts.output, verbose, dryRun, logger)
+		case opts.from == "vscode-tasks" && opts.to == "jetbrains":
+			err = convertVSCodeTasksToJetBrains(logOpts, write, projectRoot, opts.output, goos, verbose, dryRun, templates, logger)
+		case opts.from == "vscode-tasks" && opts.to == "makefile":
+			err = convertVSCodeTasksToMakefile(logOpts, write, projectRoot, opts.output, goos, verbose, dryRun, logger)
+		case opts.from == "jetbrains" && opts.to == "vscode-tasks":
+			err = convertJetBrainsToVSCodeTasks(logOpts, write, projectRoot, opts.output, verbose, dryRun, opts.showSkipped, logger)
+		case opts.from == "jetbrains" && opts.to == "vscode-launch":
+			err = convertJetBrainsToVSCodeLaunch(logOpts, write, projectRoot, opts.output, verbose, dryRun, opts.showSkipped, logger)
+		case opts.from == "jetbrains" && opts.to == "shell":
+			err = convertJetBrainsToShell(logOpts, write, projectRoot, opts.output, opts.shell, verbose, dryRun, opts.showSkipped, logger)
+		case opts.from == "vscode-launch" && opts.to == "jetbrains":
+			err = convertVSCodeLaunchToJetBrains(logOpts, write, projectRoot, opts.output, goos, verbose, dryRun, templates, logger)
+		default:
+			fmt.Fprintf(out, "🚧 Conversion from %s to %s is not yet implemented!\n", opts.from, opts.to)
+			fmt.Fprintf(out, "📋 Planned conversion: %s → %s\n", opts.from, opts.to)
+
+			if dryRun {
+				fmt.Fprintf(out, "✅ Dry run completed - no files were modified\n")
+			} else {
+				fmt.Fprintf(out, "📡 Strand connection established... migration ready for implementation!\n")
+			}
+		}
 
-		if dryRun {
-			fmt.Printf("✅ Dry run completed - no files were modified\n")
-		} else {
-			fmt.Printf("📡 Strand connection established... migration ready for implementation!\n")
+		return err
+	}
+
+	// Every port is recorded for the fidelity summary; --report also saves it as JSON
+	report := converter.NewReport(projectRoot, version, opts.from, opts.to, opts.dryRun)
+
+	// A silent dry run grades the configurations first, so a port below --min-fidelity writes nothing
+	if minimum != "" && !opts.dryRun && !opts.force {
+		graded := converter.NewReport(projectRoot, version, opts.from, opts.to, true)
+
+		gradeErr := convert(io.Discard, true, graded, slog.New(slog.DiscardHandler))
+		if below := graded.BelowFidelity(minimum); gradeErr == nil && len(below) > 0 {
+			report, err = graded, fmt.Errorf("%w; nothing was written (--force writes them anyway)", fidelityError(below, minimum))
 		}
 	}
 
-	return nil
-}
+	if err == nil {
+		err = convert(os.Stdout, opts.dryRun, report, logger)
+	}
 
-// convertVSCodeTasksToJetBrains handles the conversion from VSCode tasks to JetBrains
-func convertVSCodeTasksToJetBrains(projectRoot, outputPath string, verbose, dryRun bool) error {
-	// Initialize project detector
-	detector := config.NewProjectDetector(projectRoot)
+	// A timed-out read surfaces as whatever error its caller made of it; name the slow file instead
+	if opErr := logOpts.operationErr(); opErr != nil {
+		err = opErr
+	}
 
-	projectConfig, err := detector.DetectProject()
-	if err != nil {
-		return fmt.Errorf("failed to detect project configuration: %w", err)
+	// An empty source is a normal state for a fresh project, so it succeeds, but distinctly
+	if errors.Is(err, errNothingToConvert) {
+		fmt.Printf("📭 %v\n", err)
+
+		report.NothingToConvert = true
+		err = nil
 	}
 
-	if !projectConfig.HasVSCode {
-		return fmt.Errorf("no VSCode configuration found in project")
+	displayFidelity(report, minimum, verbose)
+
+	// With --force or --dry-run, the port is carried out and fails afterwards
+	if below := report.BelowFidelity(minimum); err == nil && minimum != "" && len(below) > 0 {
+		err = fidelityError(below, minimum)
 	}
 
-	// Parse VSCode tasks
-	tasksPath := detector.GetVSCodeTasksPath()
-	if tasksPath == "" {
-		return fmt.Errorf("no VSCode tasks.json found")
+	// The report is written even when the conversion failed, so the failure can be audited too
+	if opts.report != "" {
+		if writeErr := report.Write(opts.report); writeErr != nil {
+			return errors.Join(err, writeErr)
+		}
+
+		fmt.Printf("📋 Report: %d converted, %d skipped, %d failed → %s\n",
+			report.Count(converter.OutcomeConverted), report.Count(converter.OutcomeSkipped), report.Count(converter.OutcomeFailed), opts.report)
 	}
 
+	return err
+}
+
+// displayFidelity summarizes how faithful the converted configurations are to their sources,
+// detailing those below the minimum level, or every one short of full with verbose
+func displayFidelity(report *converter.Report, minimum string, verbose bool) {
+	if report.Count(converter.OutcomeConverted) == 0 {
+		return
+	}
+
+	fmt.Printf("🎯 Fidelity: %d full, %d partial, %d approximate\n",
+		report.CountFidelity(converter.FidelityFull), report.CountFidelity(converter.FidelityPartial), report.CountFidelity(converter.FidelityApproximate))
+
+	detailed := report.BelowFidelity(minimum)
 	if verbose {
-		fmt.Printf("📋 Reading VSCode tasks from: %s\n", tasksPath)
+		detailed = report.BelowFidelity(converter.FidelityFull)
 	}
 
-	parser := vscode.NewTasksParser(projectConfig.ProjectRoot)
+	for _, entry := range detailed {
+		var fields []string
 
-	tasks, err := parser.ParseTasks(tasksPath)
+		if len(entry.ApproximatedFields) > 0 {
+			fields = append(fields, "approximated "+strings.Join(entry.ApproximatedFields, ", "))
+		}
+
+		if len(entry.DroppedFields) > 0 {
+			fields = append(fields, "dropped "+strings.Join(entry.DroppedFields, ", "))
+		}
+
+		fmt.Printf("   ⚠️  %s: %s (%s)\n", entry.SourceName, entry.Fidelity, strings.Join(fields, "; "))
+	}
+}
+
+// fidelityError fails a port with configurations below the --min-fidelity level, naming them
+func fidelityError(below []converter.ReportEntry, minimum string) error {
+	names := make([]string, 0, len(below))
+	for _, entry := range below {
+		names = append(names, entry.SourceName)
+	}
+
+	return fmt.Errorf("%d configuration(s) below --min-fidelity %s: %s", len(below), minimum, strings.Join(names, ", "))
+}
+
+// errNothingToConvert marks a source that exists but defines nothing to port. port reports
+// it and still succeeds, with nothingToConvert set in the --report JSON for scripts.
+var errNothingToConvert = errors.New("nothing to convert")
+
+// parseTargetOS maps a --target-os value to a GOOS name, defaulting to the current platform.
+// VSCode's "osx" spelling is accepted alongside Go's "darwin".
+func parseTargetOS(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return runtime.GOOS, nil
+	case "linux":
+		return "linux", nil
+	case "darwin", "osx", "macos":
+		return "darwin", nil
+	case "windows":
+		return "windows", nil
+	default:
+		return "", fmt.Errorf("invalid target OS '%s'. Valid options: linux, darwin (osx), windows", value)
+	}
+}
+
+// loadPortTemplates reads the run configuration templates --apply-templates layers beneath
+// generated configurations
+func loadPortTemplates(ctx context.Context, projectRoot string, verbose bool) (config.RunConfigTemplates, error) {
+	templates, err := config.LoadRunConfigTemplates(ctx, projectRoot)
 	if err != nil {
-		return fmt.Errorf("failed to parse VSCode tasks: %w", err)
+		return nil, err
 	}
 
-	if len(tasks) == 0 {
-		fmt.Printf("⚠️  No tasks found in %s\n", tasksPath)
-		return nil
+	if len(templates) == 0 {
+		fmt.Printf("⚠️  No JetBrains run configuration templates found in .idea; generated configurations keep the IDE defaults\n")
+
+		return templates, nil
 	}
 
 	if verbose {
-		fmt.Printf("✅ Found %d VSCode tasks to convert\n", len(tasks))
+		types := make([]string, 0, len(templates))
+		for configType := range templates {
+			types = append(types, configType)
+		}
+
+		sort.Strings(types)
+		fmt.Printf("🧩 Applying run configuration templates: %s\n", strings.Join(types, ", "))
+	}
+
+	return templates, nil
+}
+
+// newOverwriteGuard protects hand-written files, asking before replacing them when a human is at the terminal
+func newOverwriteGuard(force bool) *converter.OverwriteGuard {
+	guard := &converter.OverwriteGuard{Force: force}
+
+	if !force && isInteractiveTerminal() {
+		guard.Confirm = func(path string) bool {
+			return confirmPrompt(os.Stdout, fmt.Sprintf("❓ %s was not generated by taskporter. Overwrite? [y/N] ", path))
+		}
+	}
+
+	return guard
+}
+
+// convertVSCodeTasksToJetBrains handles the conversion from VSCode tasks to JetBrains
+func convertVSCodeTasksToJetBrains(logOpts *logOptions, write converter.WriteOptions, projectRoot, outputPath, goos string, verbose, dryRun bool, templates config.RunConfigTemplates, logger *slog.Logger) error {
+	tasks, err := loadVSCodeTasksForPort(logOpts, write.Out, projectRoot, goos, verbose, logger)
+	if err != nil || len(tasks) == 0 {
+		return err
 	}
 
 	// Create converter and perform conversion
-	conv := converter.NewVSCodeToJetBrainsConverter(projectRoot, outputPath, verbose)
+	conv := converter.NewVSCodeToJetBrainsConverter(projectRoot, outputPath, verbose, logger)
+	conv.WriteOptions = write
+	conv.SetTemplates(templates)
 
 	return conv.ConvertTasks(tasks, dryRun)
 }
 
-// convertJetBrainsToVSCodeTasks handles the conversion from JetBrains to VSCode tasks
-func convertJetBrainsToVSCodeTasks(projectRoot, outputPath string, verbose, dryRun bool) error {
+// convertVSCodeTasksToMakefile handles the conversion from VSCode tasks to Makefile targets
+func convertVSCodeTasksToMakefile(logOpts *logOptions, write converter.WriteOptions, projectRoot, outputPath, goos string, verbose, dryRun bool, logger *slog.Logger) error {
+	tasks, err := loadVSCodeTasksForPort(logOpts, write.Out, projectRoot, goos, verbose, logger)
+	if err != nil || len(tasks) == 0 {
+		return err
+	}
+
+	// Create converter and perform conversion
+	conv := converter.NewVSCodeToMakefileConverter(projectRoot, outputPath, verbose, logger)
+	conv.WriteOptions = write
+
+	return conv.ConvertTasks(tasks, dryRun)
+}
+
+// modernizeVSCodeTasks rewrites a legacy tasks.json in the current schema
+func modernizeVSCodeTasks(logOpts *logOptions, write converter.WriteOptions, projectRoot, outputPath string, verbose, dryRun bool, logger *slog.Logger) error {
+	detector := logOpts.newProjectDetector(projectRoot)
+
+	tasksPath := detector.GetVSCodeTasksPath()
+	if tasksPath == "" {
+		return fmt.Errorf("no VSCode tasks.json found")
+	}
+
+	if verbose {
+		fmt.Fprintf(write.Out, "📋 Reading VSCode tasks from: %s\n", tasksPath)
+	}
+
+	modernizer := converter.NewVSCodeTasksModernizer(projectRoot, outputPath, verbose, logger)
+	modernizer.WriteOptions = write
+
+	return modernizer.Modernize(tasksPath, dryRun)
+}
+
+// loadVSCodeTasksForPort parses the project's tasks.json with the platform blocks of goos, failing
+// with errNothingToConvert when it defines no tasks
+func loadVSCodeTasksForPort(logOpts *logOptions, out io.Writer, projectRoot, goos string, verbose bool, logger *slog.Logger) ([]*config.Task, error) {
+	ctx := logOpts.operationContext()
+
 	// Initialize project detector
-	detector := config.NewProjectDetector(projectRoot)
+	detector := logOpts.newProjectDetector(projectRoot)
 
 	projectConfig, err := detector.DetectProject()
 	if err != nil {
-		return fmt.Errorf("failed to detect project configuration: %w", err)
+		return nil, fmt.Errorf("failed to detect project configuration: %w", err)
 	}
 
-	if !projectConfig.HasJetBrains {
-		return fmt.Errorf("no JetBrains configuration found in project")
+	if !projectConfig.HasVSCode {
+		return nil, fmt.Errorf("no VSCode configuration found in project")
 	}
 
-	// Parse JetBrains configurations
-	jetbrainsPaths := detector.GetJetBrainsRunConfigPaths()
-	if len(jetbrainsPaths) == 0 {
-		return fmt.Errorf("no JetBrains run configurations found")
+	// Parse VSCode tasks
+	tasksPath := detector.GetVSCodeTasksPath()
+	if tasksPath == "" {
+		return nil, fmt.Errorf("no VSCode tasks.json found")
 	}
 
 	if verbose {
-		fmt.Printf("📋 Reading JetBrains configurations from %d files\n", len(jetbrainsPaths))
+		fmt.Fprintf(out, "📋 Reading VSCode tasks from: %s\n", tasksPath)
 	}
 
-	parser := jetbrains.NewRunConfigurationParser(projectConfig.ProjectRoot)
+	parser := vscode.NewTasksParser(projectConfig.ProjectRoot, logger)
+	parser.SetStrict(logOpts.strict)
+	parser.SetTargetOS(goos)
+	parser.SetContext(ctx)
 
-	var allTasks []*config.Task
+	tasks, err := parser.ParseTasks(tasksPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse VSCode tasks: %w", err)
+	}
 
-	for _, configPath := range jetbrainsPaths {
-		task, err := parser.ParseRunConfiguration(configPath)
-		if err != nil {
-			if verbose {
-				fmt.Printf("⚠️  Warning: failed to parse %s: %v\n", configPath, err)
-			}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("%w: %s has no tasks", errNothingToConvert, tasksPath)
+	}
 
-			continue
-		}
+	if verbose {
+		fmt.Fprintf(out, "✅ Found %d VSCode tasks to convert\n", len(tasks))
+	}
 
-		allTasks = append(allTasks, task)
+	return tasks, nil
+}
+
+// convertJetBrainsToVSCodeTasks handles the conversion from JetBrains to VSCode tasks
+func convertJetBrainsToVSCodeTasks(logOpts *logOptions, write converter.WriteOptions, projectRoot, outputPath string, verbose, dryRun, showSkipped bool, logger *slog.Logger) error {
+	allTasks, err := loadJetBrainsTasksForPort(logOpts, write.Out, projectRoot, verbose, showSkipped, write.Report, logger)
+	if err != nil || len(allTasks) == 0 {
+		return err
 	}
 
-	if len(allTasks) == 0 {
-		fmt.Printf("⚠️  No valid JetBrains configurations found to convert\n")
-		return nil
+	// Create converter and perform conversion
+	conv := converter.NewJetBrainsToVSCodeConverter(projectRoot, outputPath, verbose, logger)
+	conv.WriteOptions = write
+
+	return conv.ConvertTasks(allTasks, dryRun)
+}
+
+// convertJetBrainsToVSCodeLaunch handles the conversion from JetBrains to VSCode launch
+func convertJetBrainsToVSCodeLaunch(logOpts *logOptions, write converter.WriteOptions, projectRoot, outputPath string, verbose, dryRun, showSkipped bool, logger *slog.Logger) error {
+	allTasks, err := loadJetBrainsTasksForPort(logOpts, write.Out, projectRoot, verbose, showSkipped, write.Report, logger)
+	if err != nil || len(allTasks) == 0 {
+		return err
 	}
 
-	if verbose {
-		fmt.Printf("✅ Found %d JetBrains configurations to convert\n", len(allTasks))
+	// Create converter and perform conversion
+	conv := converter.NewJetBrainsToVSCodeLaunchConverter(projectRoot, outputPath, verbose, logger)
+	conv.WriteOptions = write
+
+	return conv.ConvertToLaunch(allTasks, dryRun)
+}
+
+// convertJetBrainsToShell handles the conversion from JetBrains to a shell script
+func convertJetBrainsToShell(logOpts *logOptions, write converter.WriteOptions, projectRoot, outputPath, shell string, verbose, dryRun, showSkipped bool, logger *slog.Logger) error {
+	allTasks, err := loadJetBrainsTasksForPort(logOpts, write.Out, projectRoot, verbose, showSkipped, write.Report, logger)
+	if err != nil || len(allTasks) == 0 {
+		return err
 	}
 
 	// Create converter and perform conversion
-	conv := converter.NewJetBrainsToVSCodeConverter(projectRoot, outputPath, verbose)
+	conv := converter.NewJetBrainsToShellConverter(projectRoot, outputPath, verbose, logger)
+	conv.SetShell(shell)
+	conv.WriteOptions = write
 
 	return conv.ConvertTasks(allTasks, dryRun)
 }
 
-// convertJetBrainsToVSCodeLaunch handles the conversion from JetBrains to VSCode launch
-func convertJetBrainsToVSCodeLaunch(projectRoot, outputPath string, verbose, dryRun bool) error {
+// loadJetBrainsTasksForPort parses the project's run configurations, skipping templates and unparseable
+// ones and returning no tasks (and no error) when none are valid. The skipped files are listed with
+// showSkipped, and always when nothing was left to port.
+func loadJetBrainsTasksForPort(logOpts *logOptions, out io.Writer, projectRoot string, verbose, showSkipped bool, report *converter.Report, logger *slog.Logger) ([]*config.Task, error) {
+	ctx := logOpts.operationContext()
+
 	// Initialize project detector
-	detector := config.NewProjectDetector(projectRoot)
+	detector := logOpts.newProjectDetector(projectRoot)
 
 	projectConfig, err := detector.DetectProject()
 	if err != nil {
-		return fmt.Errorf("failed to detect project configuration: %w", err)
+		return nil, fmt.Errorf("failed to detect project configuration: %w", err)
 	}
 
 	if !projectConfig.HasJetBrains {
-		return fmt.Errorf("no JetBrains configuration found in project")
+		return nil, fmt.Errorf("no JetBrains configuration found in project")
 	}
 
 	// Parse JetBrains configurations
 	jetbrainsPaths := detector.GetJetBrainsRunConfigPaths()
 	if len(jetbrainsPaths) == 0 {
-		return fmt.Errorf("no JetBrains run configurations found")
+		return nil, fmt.Errorf("no JetBrains run configurations found")
 	}
 
 	if verbose {
-		fmt.Printf("📋 Reading JetBrains configurations from %d files\n", len(jetbrainsPaths))
+		fmt.Fprintf(out, "📋 Reading JetBrains configurations from %d files\n", len(jetbrainsPaths))
 	}
 
-	parser := jetbrains.NewRunConfigurationParser(projectConfig.ProjectRoot)
+	parser := newJetBrainsParser(ctx, projectConfig.ProjectRoot, logger)
 
-	var allTasks []*config.Task
+	var (
+		allTasks []*config.Task
+		skipped  []skippedFile
+	)
 
 	for _, configPath := range jetbrainsPaths {
 		task, err := parser.ParseRunConfiguration(configPath)
 		if err != nil {
-			if verbose {
-				fmt.Printf("⚠️  Warning: failed to parse %s: %v\n", configPath, err)
-			}
+			logJetBrainsParseError(logger, configPath, err)
+
+			skipped = append(skipped, newSkippedFile(projectConfig.ProjectRoot, configPath, err))
+			report.Add(converter.ReportEntry{SourceFile: configPath, Outcome: converter.OutcomeSkipped, Reason: skipped[len(skipped)-1].reason})
 
 			continue
 		}
@@ -298,24 +643,67 @@ func convertJetBrainsToVSCodeLaunch(projectRoot, outputPath string, verbose, dry
 	}
 
 	if len(allTasks) == 0 {
-		fmt.Printf("⚠️  No valid JetBrains configurations found to convert\n")
-		return nil
+		printSkippedFiles(out, skipped)
+
+		return nil, fmt.Errorf("%w: no valid JetBrains run configurations found", errNothingToConvert)
+	}
+
+	if showSkipped {
+		printSkippedFiles(out, skipped)
 	}
 
 	if verbose {
-		fmt.Printf("✅ Found %d JetBrains configurations to convert\n", len(allTasks))
+		fmt.Fprintf(out, "✅ Found %d JetBrains configurations to convert\n", len(allTasks))
 	}
 
-	// Create converter and perform conversion
-	conv := converter.NewJetBrainsToVSCodeLaunchConverter(projectRoot, outputPath, verbose)
+	return allTasks, nil
+}
 
-	return conv.ConvertToLaunch(allTasks, dryRun)
+// skippedFile is a configuration file port left out, with the reason
+type skippedFile struct {
+	path   string
+	reason string
+}
+
+// newSkippedFile describes a file the parser rejected. The parser's error repeats the path, the
+// cause is enough next to it.
+func newSkippedFile(projectRoot, path string, err error) skippedFile {
+	if cause := errors.Unwrap(err); cause != nil {
+		err = cause
+	}
+
+	if relative, relErr := filepath.Rel(projectRoot, path); relErr == nil {
+		path = relative
+	}
+
+	return skippedFile{path: path, reason: err.Error()}
+}
+
+// printSkippedFiles prints the skipped files as a table
+func printSkippedFiles(out io.Writer, skipped []skippedFile) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	width := len("FILE")
+	for _, file := range skipped {
+		width = max(width, len(file.path))
+	}
+
+	fmt.Fprintf(out, "⏭️  Skipped %d files:\n", len(skipped))
+	fmt.Fprintf(out, "   %-*s  %s\n", width, "FILE", "REASON")
+
+	for _, file := range skipped {
+		fmt.Fprintf(out, "   %-*s  %s\n", width, file.path, file.reason)
+	}
 }
 
 // convertVSCodeLaunchToJetBrains handles the conversion from VSCode launch to JetBrains
-func convertVSCodeLaunchToJetBrains(projectRoot, outputPath string, verbose, dryRun bool) error {
+func convertVSCodeLaunchToJetBrains(logOpts *logOptions, write converter.WriteOptions, projectRoot, outputPath, goos string, verbose, dryRun bool, templates config.RunConfigTemplates, logger *slog.Logger) error {
+	ctx := write.Context
+
 	// Initialize project detector
-	detector := config.NewProjectDetector(projectRoot)
+	detector := logOpts.newProjectDetector(projectRoot)
 
 	projectConfig, err := detector.DetectProject()
 	if err != nil {
@@ -333,10 +721,12 @@ func convertVSCodeLaunchToJetBrains(projectRoot, outputPath string, verbose, dry
 	}
 
 	if verbose {
-		fmt.Printf("📋 Reading VSCode launch configs from: %s\n", launchPath)
+		fmt.Fprintf(write.Out, "📋 Reading VSCode launch configs from: %s\n", launchPath)
 	}
 
-	launchParser := vscode.NewLaunchParser(projectConfig.ProjectRoot)
+	launchParser := vscode.NewLaunchParser(projectConfig.ProjectRoot, logger)
+	launchParser.SetTargetOS(goos)
+	launchParser.SetContext(ctx)
 
 	launchTasks, err := launchParser.ParseLaunchConfigs(launchPath)
 	if err != nil {
@@ -344,44 +734,66 @@ func convertVSCodeLaunchToJetBrains(projectRoot, outputPath string, verbose, dry
 	}
 
 	if len(launchTasks) == 0 {
-		fmt.Printf("⚠️  No launch configurations found in %s\n", launchPath)
-		return nil
+		return fmt.Errorf("%w: %s has no launch configurations", errNothingToConvert, launchPath)
 	}
 
 	if verbose {
-		fmt.Printf("✅ Found %d VSCode launch configurations to convert\n", len(launchTasks))
+		fmt.Fprintf(write.Out, "✅ Found %d VSCode launch configurations to convert\n", len(launchTasks))
 	}
 
 	// Create converter and perform conversion
-	conv := converter.NewVSCodeLaunchToJetBrainsConverter(projectRoot, outputPath, verbose)
+	conv := converter.NewVSCodeLaunchToJetBrainsConverter(projectRoot, outputPath, verbose, logger)
+	conv.WriteOptions = write
+	conv.SetTemplates(templates)
 
 	return conv.ConvertLaunchConfigs(launchTasks, dryRun)
 }
 
-func validateFormatCombination(from, to string) error {
-	validFormats := map[string]bool{
+func validateFormatCombination(from, to string, modernize bool) error {
+	if modernize {
+		if from != "vscode-tasks" || to != "vscode-tasks" {
+			return fmt.Errorf("--modernize requires --from vscode-tasks --to vscode-tasks")
+		}
+
+		return nil
+	}
+
+	sourceFormats := map[string]bool{
 		"vscode-tasks":  true,
 		"vscode-launch": true,
 		"jetbrains":     true,
 	}
 
-	if !validFormats[from] {
+	// Makefiles and shell scripts can be generated but not read back
+	targetFormats := map[string]bool{
+		"vscode-tasks":  true,
+		"vscode-launch": true,
+		"jetbrains":     true,
+		"makefile":      true,
+		"shell":         true,
+	}
+
+	if !sourceFormats[from] {
 		return fmt.Errorf("invalid source format '%s'. Valid options: vscode-tasks, vscode-launch, jetbrains", from)
 	}
 
-	if !validFormats[to] {
-		return fmt.Errorf("invalid target format '%s'. Valid options: vscode-tasks, vscode-launch, jetbrains", to)
+	if !targetFormats[to] {
+		return fmt.Errorf("invalid target format '%s'. Valid options: vscode-tasks, vscode-launch, jetbrains, makefile, shell", to)
 	}
 
 	if from == to {
+		if from == "vscode-tasks" {
+			return fmt.Errorf("source and target formats cannot be the same (use --modernize to upgrade a legacy tasks.json)")
+		}
+
 		return fmt.Errorf("source and target formats cannot be the same")
 	}
 
 	// Check for supported conversion paths
 	supportedConversions := map[string][]string{
-		"vscode-tasks":  {"jetbrains"},
+		"vscode-tasks":  {"jetbrains", "makefile"},
 		"vscode-launch": {"jetbrains"},
-		"jetbrains":     {"vscode-tasks", "vscode-launch"},
+		"jetbrains":     {"vscode-tasks", "vscode-launch", "shell"},
 	}
 
 	if supported, exists := supportedConversions[from]; exists {
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// spinnerFrames are drawn in turn while configuration files are parsed
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const (
+	// spinnerDelay keeps the spinner from flashing on projects that parse instantly
+	spinnerDelay = 200 * time.Millisecond
+
+	spinnerInterval = 100 * time.Millisecond
+)
+
+// scanProgress shows "Scanning N config files..." with a spinner on stderr while
+// configuration files are parsed, so big projects don't look stuck. A nil *scanProgress
+// is valid and shows nothing.
+type scanProgress struct {
+	out   io.Writer
+	total int
+	done  atomic.Int64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// startScanProgress starts the spinner for total files, or returns nil when enabled is
+// false or there is nothing to scan
+func startScanProgress(out io.Writer, total int, enabled bool) *scanProgress {
+	if !enabled || total == 0 {
+		return nil
+	}
+
+	p := &scanProgress{
+		out:     out,
+		total:   total,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Add records n more parsed files
+func (p *scanProgress) Add(n int) {
+	if p == nil {
+		return
+	}
+
+	p.done.Add(int64(n))
+}
+
+// Stop removes the spinner line. It is safe to call more than once.
+func (p *scanProgress) Stop() {
+	if p == nil {
+		return
+	}
+
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.stopped
+	})
+}
+
+func (p *scanProgress) run() {
+	defer close(p.stopped)
+
+	select {
+	case <-p.stop:
+		return
+	case <-time.After(spinnerDelay):
+	}
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		fmt.Fprintf(p.out, "\r\033[K%s Scanning %d config files... %d/%d", spinnerFrames[frame%len(spinnerFrames)], p.total, p.done.Load(), p.total)
+
+		select {
+		case <-p.stop:
+			fmt.Fprint(p.out, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// showScanProgress reports whether to show the scan spinner: only for humans at a terminal,
+// and not when verbose output or debug logs would interleave with it
+func showScanProgress(verbose bool, logger *slog.Logger) bool {
+	return !verbose && stderrIsTerminal() && !logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// stderrIsTerminal reports whether stderr is attached to a terminal, where the spinner can redraw its line
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
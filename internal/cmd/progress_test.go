@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanProgress(t *testing.T) {
+	t.Run("should draw the file count and clear its line when stopped", func(t *testing.T) {
+		var out bytes.Buffer
+
+		progress := startScanProgress(&out, 3, true)
+		progress.Add(2)
+		time.Sleep(spinnerDelay + spinnerInterval)
+		progress.Stop()
+		progress.Stop()
+
+		require.Contains(t, out.String(), "Scanning 3 config files... 2/3")
+		require.True(t, bytes.HasSuffix(out.Bytes(), []byte("\r\033[K")))
+	})
+
+	t.Run("should stay silent on fast scans and when disabled", func(t *testing.T) {
+		var out bytes.Buffer
+
+		startScanProgress(&out, 3, true).Stop()
+		require.Empty(t, out.String())
+
+		disabled := startScanProgress(&out, 3, false)
+		require.Nil(t, disabled)
+
+		// A nil progress is usable
+		disabled.Add(1)
+		disabled.Stop()
+	})
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/runner"
+
+	"github.com/spf13/cobra"
+)
+
+func NewPsCommand(verbose *bool, outputFormat *string, configPath *string, logOpts *logOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ps",
+		Short: "List detached tasks that are still running",
+		Long: `List the tasks started with 'taskporter run --detach' that are still running,
+with their PID, uptime and log file.
+
+Detached tasks are tracked by pidfiles under .taskporter/pids in the project root;
+pidfiles of tasks that have exited are cleaned up as they are found.
+
+Examples:
+  taskporter ps
+  taskporter ps --output json
+
+Checking which porters are still out on delivery...`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runPsCommand(*verbose, *outputFormat, *configPath, logOpts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runPsCommand(verbose bool, outputFormat string, configPath string, logOpts *logOptions) error {
+	taskRunner, projectRoot, err := newDetachedTaskRunner(verbose, configPath, logOpts)
+	if err != nil {
+		return err
+	}
+
+	running, err := taskRunner.ListDetached()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(running)
+	}
+
+	if len(running) == 0 {
+		fmt.Println("💤 No detached tasks running. Start one with 'taskporter run <task> --detach'.")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TASK\tPID\tUPTIME\tLOG")
+
+	for _, detached := range running {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\n", detached.Task, detached.PID, time.Since(detached.Started).Round(time.Second), relativeSource(absPath(projectRoot), detached.Log))
+	}
+
+	return writer.Flush()
+}
+
+// newDetachedTaskRunner creates a task runner for the project's detached tasks
+func newDetachedTaskRunner(verbose bool, configPath string, logOpts *logOptions) (*runner.TaskRunner, string, error) {
+	logger, err := logOpts.newLogger(os.Stderr, verbose)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Determine project root
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	projectConfig, err := logOpts.newProjectDetector(projectRoot).DetectProject()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	return runner.NewTaskRunnerWithProjectRoot(verbose, projectConfig.ProjectRoot, logger), projectConfig.ProjectRoot, nil
+}
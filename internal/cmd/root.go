@@ -1,9 +1,84 @@
 package cmd
 
 import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+
 	"github.com/spf13/cobra"
 )
 
+// version is the taskporter release, shown by --version and recorded in port reports
+const version = "0.1.0"
+
+// logOptions holds the persistent flags shared by all commands (diagnostics, task discovery and
+// the file operation timeout) along with the state one invocation keeps across task loads
+type logOptions struct {
+	level     string
+	format    string
+	strict    bool // Fail instead of warning about problems that hide tasks (e.g. duplicate labels)
+	noGlobal  bool // Leave out tasks from the user-level tasks file
+	gitignore bool // Leave out configurations the project's .gitignore ignores, besides .taskporterignore
+	opTimeout time.Duration
+
+	ctx    context.Context // Bounds each file operation of discovery, parsing and conversion by opTimeout, see operationContext
+	cancel context.CancelFunc
+
+	launchFailures launchFileFailures // launch.json files that failed to parse, warned about once per invocation
+}
+
+// newLogger builds the diagnostics logger. Without --log-level, warnings and errors are shown,
+// and --verbose lowers the level to debug for compatibility.
+func (o *logOptions) newLogger(w io.Writer, verbose bool) (*slog.Logger, error) {
+	levelName := o.level
+	if levelName == "" {
+		levelName = "warn"
+		if verbose {
+			levelName = "debug"
+		}
+	}
+
+	level, err := logging.ParseLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+
+	return logging.New(w, level, o.format)
+}
+
+// newProjectDetector creates a project detector honoring the discovery flags
+func (o *logOptions) newProjectDetector(projectRoot string) *config.ProjectDetector {
+	detector := config.NewProjectDetector(projectRoot)
+	detector.SetRespectGitignore(o.gitignore)
+	detector.SetContext(o.operationContext())
+
+	return detector
+}
+
+// operationContext returns the context that abandons each file operation of the command's
+// discovery, parsing and conversion once it has taken longer than --op-timeout
+func (o *logOptions) operationContext() context.Context {
+	if o.ctx == nil {
+		o.ctx, o.cancel = config.WithOperationTimeout(context.Background(), o.opTimeout)
+	}
+
+	return o.ctx
+}
+
+// operationErr reports --op-timeout expiring, naming the file taskporter was stuck reading.
+// Parsers only warn about files they fail to read, so commands check it after loading.
+func (o *logOptions) operationErr() error {
+	if o.ctx == nil {
+		return nil
+	}
+
+	return config.OperationErr(o.ctx)
+}
+
 // NewRootCommand creates and configures the root command with all subcommands
 func NewRootCommand() *cobra.Command {
 	// Local variables for flags - no globals!
@@ -11,6 +86,7 @@ func NewRootCommand() *cobra.Command {
 		verbose      bool
 		configPath   string
 		outputFormat string
+		logOpts      logOptions
 	)
 
 	rootCmd := &cobra.Command{
@@ -23,7 +99,12 @@ Bridge and execute tasks from various code editors (VSCode, JetBrains IDEs) dire
 from the terminal, enabling seamless cross-environment developer workflows.
 
 Connecting isolated development environments... strand established.`,
-		Version: "0.1.0",
+		Version: version,
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if logOpts.cancel != nil {
+				logOpts.cancel()
+			}
+		},
 	}
 
 	// Setup global flags
@@ -31,13 +112,35 @@ Connecting isolated development environments... strand established.`,
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "config file path (default: auto-detect)")
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "output format (text, json)")
 
+	rootCmd.PersistentFlags().StringVar(&logOpts.level, "log-level", "", "diagnostics level (debug, info, warn, error) (default: warn, or debug with --verbose)")
+	rootCmd.PersistentFlags().StringVar(&logOpts.format, "log-format", logging.FormatText, "diagnostics format on stderr (text, json)")
+	rootCmd.PersistentFlags().BoolVar(&logOpts.strict, "strict", false, "treat configuration warnings such as duplicate task labels as errors")
+	rootCmd.PersistentFlags().BoolVar(&logOpts.noGlobal, "no-global", false, "leave out tasks from the user-level ~/.config/taskporter/tasks.json")
+	rootCmd.PersistentFlags().DurationVar(&logOpts.opTimeout, "op-timeout", config.DefaultOperationTimeout, "give up on a file read during discovery, parsing or conversion that takes this long, e.g. on a hung network filesystem (0 disables)")
+	rootCmd.PersistentFlags().BoolVar(&logOpts.gitignore, "respect-gitignore", false, "also leave out configurations the project's .gitignore ignores (.taskporterignore always applies)")
+
 	_ = rootCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
-	rootCmd.AddCommand(NewListCommand(&verbose, &outputFormat, &configPath))
-	rootCmd.AddCommand(NewRunCommand(&verbose, &configPath))
-	rootCmd.AddCommand(NewPortCommand(&verbose, &configPath))
+	_ = rootCmd.RegisterFlagCompletionFunc("log-level", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return logging.Levels, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	_ = rootCmd.RegisterFlagCompletionFunc("log-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{logging.FormatText, logging.FormatJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	rootCmd.AddCommand(NewListCommand(&verbose, &outputFormat, &configPath, &logOpts))
+	rootCmd.AddCommand(NewRunCommand(&verbose, &configPath, &logOpts))
+	rootCmd.AddCommand(NewPortCommand(&verbose, &configPath, &logOpts))
+	rootCmd.AddCommand(NewValidateCommand(&verbose, &outputFormat, &configPath, &logOpts))
+	rootCmd.AddCommand(NewGraphCommand(&verbose, &outputFormat, &configPath, &logOpts))
+	rootCmd.AddCommand(NewSelftestCommand(&verbose, &outputFormat, &configPath, &logOpts))
+	rootCmd.AddCommand(NewDiffCommand(&verbose, &outputFormat, &configPath, &logOpts))
+	rootCmd.AddCommand(NewStatsCommand(&verbose, &outputFormat, &configPath, &logOpts))
+	rootCmd.AddCommand(NewPsCommand(&verbose, &outputFormat, &configPath, &logOpts))
+	rootCmd.AddCommand(NewStopCommand(&verbose, &configPath, &logOpts))
 
 	return rootCmd
 }
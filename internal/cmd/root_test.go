@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRootCommandSurface pins the commands and flags users and scripts rely on, so
+// a refactor cannot drop one silently. Update it together with the README.
+func TestRootCommandSurface(t *testing.T) {
+	root := NewRootCommand()
+
+	t.Run("global flags", func(t *testing.T) {
+		require.Equal(t, []string{
+			"config", "log-format", "log-level", "no-global", "op-timeout", "output", "respect-gitignore", "strict", "verbose",
+		}, flagNames(root.PersistentFlags()))
+	})
+
+	t.Run("commands and flags", func(t *testing.T) {
+		expected := map[string][]string{
+			"diff":  {"from", "jetbrains", "map", "to", "vscode"},
+			"graph": {"dot"},
+			"list":  {"changed-since", "graph", "group", "source", "tag"},
+			"port":  {"apply-templates", "dry-run", "force", "from", "full-preview", "min-fidelity", "modernize", "output", "paranoid-mode", "report", "shell", "show-skipped", "target-os", "to"},
+			"run": {
+				"base", "clean-env", "confirm", "container", "container-engine", "continue-on-error", "create-cwd", "detach", "dry-run", "dump-script", "each", "expect-exit", "fail-fast",
+				"force-capture", "isolate-env", "keep-env", "keep-going", "list", "no-interactive", "paranoid-mode", "print-env", "quiet-success", "remote", "remote-allow", "report",
+				"respect-problem-matcher", "retries", "retry-delay", "retry-pre", "select-from", "since", "skip-pre", "tag",
+			},
+			"ps":       nil,
+			"selftest": nil,
+			"stats":    {"after"},
+			"stop":     nil,
+			"validate": {"fix"},
+		}
+
+		actual := make(map[string][]string, len(root.Commands()))
+		for _, command := range root.Commands() {
+			actual[command.Name()] = flagNames(command.LocalNonPersistentFlags())
+		}
+
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("hidden commands", func(t *testing.T) {
+		var hidden []string
+
+		for _, command := range root.Commands() {
+			if command.Hidden {
+				hidden = append(hidden, command.Name())
+			}
+		}
+
+		require.Equal(t, []string{"selftest"}, hidden)
+	})
+}
+
+// flagNames returns the names of the flags in the set, sorted
+func flagNames(flags *pflag.FlagSet) []string {
+	var names []string
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		names = append(names, flag.Name)
+	})
+
+	return names
+}
@@ -1,102 +1,431 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/syndbg/taskporter/internal/config"
-	"github.com/syndbg/taskporter/internal/parser/jetbrains"
-	"github.com/syndbg/taskporter/internal/parser/vscode"
+	"github.com/syndbg/taskporter/internal/logging"
 	"github.com/syndbg/taskporter/internal/runner"
 	"github.com/syndbg/taskporter/internal/security"
 
 	"github.com/spf13/cobra"
 )
 
-// getAllTasksQuiet gets all tasks without verbose output for completion
-func getAllTasksQuiet() ([]*config.Task, error) {
-	// Determine project root
-	projectRoot := "."
+// getAllTasksQuiet gets all tasks without verbose output, for completion and machine-readable listings
+func getAllTasksQuiet(projectRoot string, logOpts *logOptions) ([]*config.Task, error) {
+	// Diagnostics would corrupt completion output
+	logger := logging.Discard()
 
 	// Initialize project detector
-	detector := config.NewProjectDetector(projectRoot)
+	detector := logOpts.newProjectDetector(projectRoot)
 
 	projectConfig, err := detector.DetectProject()
 	if err != nil {
 		return nil, err
 	}
 
-	var allTasks []*config.Task
+	allTasks, err := parseTaskSources(projectTaskSources(detector, projectConfig, taskSourceOptions{launchFailures: &logOpts.launchFailures}, logger), nil)
+	if err != nil {
+		return nil, err
+	}
 
-	// Parse VSCode tasks
-	if projectConfig.HasVSCode {
-		if tasksPath := detector.GetVSCodeTasksPath(); tasksPath != "" {
-			parser := vscode.NewTasksParser(projectConfig.ProjectRoot)
+	if err := logOpts.operationErr(); err != nil {
+		return nil, err
+	}
 
-			tasks, err := parser.ParseTasks(tasksPath)
-			if err == nil {
-				allTasks = append(allTasks, tasks...)
-			}
+	allTasks = withGlobalTasks(allTasks, projectConfig.ProjectRoot, logOpts.noGlobal, false, logger)
+	allTasks, err = withTaskExtensions(allTasks, projectConfig.ProjectRoot, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	applyConfiguredTags(allTasks, projectConfig.ProjectRoot, logger)
+
+	return allTasks, nil
+}
+
+// discoveryFlags returns the --no-global, --respect-gitignore and --op-timeout flags of the command being completed
+func discoveryFlags(cmd *cobra.Command) *logOptions {
+	noGlobal, _ := cmd.Flags().GetBool("no-global")
+	gitignore, _ := cmd.Flags().GetBool("respect-gitignore")
+	opTimeout, _ := cmd.Flags().GetDuration("op-timeout")
+
+	return &logOptions{noGlobal: noGlobal, gitignore: gitignore, opTimeout: opTimeout}
+}
+
+// completionTasks discovers the tasks of the project --config points at, for shell completion
+func completionTasks(cmd *cobra.Command) ([]*config.Task, string, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	tasks, err := getAllTasksQuiet(projectRoot, discoveryFlags(cmd))
+
+	return tasks, projectRoot, err
+}
+
+// validTaskNames provides dynamic completion for task names
+func validTaskNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	tasks, projectRoot, err := completionTasks(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Several tasks can run in one invocation; don't offer the ones already named
+	var taskNames []string
+	for _, task := range tasks {
+		if !slices.Contains(args, task.Name) {
+			taskNames = append(taskNames, task.Name)
 		}
+	}
 
-		// Parse VSCode launch configurations
-		if launchPath := detector.GetVSCodeLaunchPath(); launchPath != "" {
-			launchParser := vscode.NewLaunchParser(projectConfig.ProjectRoot)
+	// Offer usable aliases too, described with the task they expand to
+	aliases, err := config.LoadAliases(projectRoot)
+	if err != nil {
+		return taskNames, cobra.ShellCompDirectiveNoFileComp
+	}
 
-			launchTasks, err := launchParser.ParseLaunchConfigs(launchPath)
-			if err == nil {
-				allTasks = append(allTasks, launchTasks...)
-			}
+	config.ApplyAliases(tasks, aliases)
+
+	for _, task := range tasks {
+		for _, alias := range task.Aliases {
+			taskNames = append(taskNames, fmt.Sprintf("%s\talias for %s", alias, task.Name))
 		}
 	}
 
-	// Parse JetBrains configurations
-	if projectConfig.HasJetBrains {
-		jetbrainsPaths := detector.GetJetBrainsRunConfigPaths()
-		if len(jetbrainsPaths) > 0 {
-			parser := jetbrains.NewRunConfigurationParser(projectConfig.ProjectRoot)
-			for _, path := range jetbrainsPaths {
-				task, err := parser.ParseRunConfiguration(path)
-				if err == nil {
-					allTasks = append(allTasks, task)
+	return taskNames, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTaskValues completes a flag with the distinct, non-empty values that values returns
+// for the discovered tasks, in discovery order
+func completeTaskValues(values func(task *config.Task) []string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		tasks, _, err := completionTasks(cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		seen := make(map[string]bool)
+
+		var completions []string
+
+		for _, task := range tasks {
+			for _, value := range values(task) {
+				if value != "" && !seen[value] {
+					seen[value] = true
+					completions = append(completions, value)
 				}
 			}
 		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
 	}
+}
 
-	return allTasks, nil
+// validTaskGroups provides dynamic completion for the groups of discovered tasks
+var validTaskGroups = completeTaskValues(func(task *config.Task) []string { return []string{task.Group} })
+
+// validTaskTags provides dynamic completion for the tags of discovered tasks
+var validTaskTags = completeTaskValues(func(task *config.Task) []string { return task.Tags })
+
+// validTaskSources provides dynamic completion for the sources of discovered tasks
+var validTaskSources = completeTaskValues(func(task *config.Task) []string { return []string{string(task.Type)} })
+
+// taskListEntry is one element of `run --list`, a stable contract for editor integrations.
+// Every key is always present; cwd and source are absolute.
+type taskListEntry struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Source  string   `json:"source"`
+	Group   string   `json:"group"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Cwd     string   `json:"cwd"`
+	Tags    []string `json:"tags"`
 }
 
-// validTaskNames provides dynamic completion for task names
-func validTaskNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+// printTaskListJSON writes every discovered task to w as a JSON array, without decoration or diagnostics
+func printTaskListJSON(w io.Writer, configPath string, logOpts *logOptions, args []string) error {
 	if len(args) > 0 {
-		// Only complete the first argument (task name)
-		return nil, cobra.ShellCompDirectiveNoFileComp
+		return fmt.Errorf("--list does not take a task name")
 	}
 
-	// Get the project configurations to find available tasks
-	tasks, err := getAllTasksQuiet()
+	// Determine project root
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	tasks, err := getAllTasksQuiet(projectRoot, logOpts)
 	if err != nil {
-		return nil, cobra.ShellCompDirectiveNoFileComp
+		return fmt.Errorf("failed to detect project configuration: %w", err)
 	}
 
-	var taskNames []string
+	entries := make([]taskListEntry, 0, len(tasks))
+
 	for _, task := range tasks {
-		taskNames = append(taskNames, task.Name)
+		taskArgs := task.Args
+		if taskArgs == nil {
+			taskArgs = []string{}
+		}
+
+		cwd := task.Cwd
+		if cwd == "" {
+			cwd = projectRoot
+		}
+
+		tags := task.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+
+		entries = append(entries, taskListEntry{
+			Name:    task.Name,
+			Type:    string(task.Type),
+			Source:  absPath(task.Source),
+			Group:   task.Group,
+			Command: task.Command,
+			Args:    taskArgs,
+			Cwd:     absPath(cwd),
+			Tags:    tags,
+		})
 	}
 
-	return taskNames, cobra.ShellCompDirectiveNoFileComp
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(entries)
 }
 
-func NewRunCommand(verbose *bool, configPath *string) *cobra.Command {
-	var (
-		noInteractive bool
-		paranoidMode  bool
-	)
+// absPath returns path as an absolute path, or unchanged if it is empty or cannot be resolved
+func absPath(path string) string {
+	if path == "" {
+		return path
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+
+	return path
+}
+
+// runOptions holds the flags that control how the run command selects and executes tasks
+type runOptions struct {
+	noInteractive bool
+	paranoidMode  bool
+	forceCapture  bool
+	quietSuccess  bool
+	confirm       bool
+	dryRun        bool
+	dumpScript    bool
+	printEnv      bool
+	report        string   // JSON file with the duration and outcome of every task run
+	each          string   // File whose lines the task runs once for, "-" for stdin
+	items         []string // Read from each
+	item          string   // The --each item being run, shown with the task in the summary
+	list          bool
+	remote        string
+	remoteAllow   []string
+	container     string
+	engine        string
+	isolateEnv    bool
+	cleanEnv      bool
+	keepEnv       []string
+	createCwd     bool
+	detach        bool
+	tags          []string
+	selectFrom    string
+	problems      bool
+	since         bool
+	base          string
+	retries       int
+	retryDelay    time.Duration
+	retryPre      bool
+	skipPre       bool
+	keepGoing     bool
+	failFast      bool
+	expectExit    string
+	expectedExit  runner.ExitCodes // Parsed from expectExit
+	logger        *slog.Logger
+	ctx           context.Context         // Cancelled on interrupt once tasks are running
+	stop          context.CancelCauseFunc // Set with --fail-fast, cancels ctx with errFailFast
+	results       *taskResults            // Outcome of every task run, for the --keep-going summary
+	prelaunched   map[string]bool         // preLaunchTasks a compound already ran for its members
+}
+
+// errFailFast is why tasks were not started after another task failed with --fail-fast
+var errFailFast = errors.New("an earlier task failed (--fail-fast)")
+
+// failedTasksError reports every task of a --keep-going run that failed, with its exit code
+type failedTasksError struct {
+	failed []taskResult
+	total  int
+}
+
+func (e *failedTasksError) Error() string {
+	failed := make([]string, len(e.failed))
+	for i, result := range e.failed {
+		failed[i] = fmt.Sprintf("%s (exit %d)", result.label(), runner.ExitCode(result.err))
+	}
+
+	return fmt.Sprintf("%d of %d tasks failed: %s", len(e.failed), e.total, strings.Join(failed, ", "))
+}
+
+// Unwrap returns the task errors, so the exit code is the worst one among them
+func (e *failedTasksError) Unwrap() []error {
+	errs := make([]error, len(e.failed))
+	for i, result := range e.failed {
+		errs[i] = result.err
+	}
+
+	return errs
+}
+
+// taskResult is the outcome of one task run
+type taskResult struct {
+	name   string
+	item   string // The --each item the task ran for, if any
+	timing runner.TaskTiming
+	err    error
+}
+
+// label names the task run, with its --each item if it had one
+func (r taskResult) label() string {
+	if r.item == "" {
+		return r.name
+	}
+
+	return fmt.Sprintf("%s (%s)", r.name, r.item)
+}
+
+// taskResults collects task outcomes; compound members report from several goroutines
+type taskResults struct {
+	mu      sync.Mutex
+	results []taskResult
+}
+
+func (r *taskResults) add(name, item string, timing runner.TaskTiming, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results = append(r.results, taskResult{name: name, item: item, timing: timing, err: err})
+}
+
+// printSummary lists every task that ran with its outcome, duration and share of the run's wall
+// time, when there was more than one. Parallel tasks overlap, so shares may add up past 100%.
+func (r *taskResults) printSummary(w io.Writer, total time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.results) < 2 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "📊 Summary (%d tasks in %s):\n", len(r.results), formatTaskDuration(total))
+
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, result := range r.results {
+		status, exit := "✅", ""
+		if result.err != nil {
+			status, exit = "❌", fmt.Sprintf("exit %d", runner.ExitCode(result.err))
+		}
+
+		share := 0.0
+		if total > 0 {
+			share = float64(result.timing.Duration()) / float64(total) * 100
+		}
+
+		fmt.Fprintf(writer, "   %s %s\t%s\t%.0f%%\t%s\n", status, result.label(), formatTaskDuration(result.timing.Duration()), share, exit)
+	}
+
+	writer.Flush()
+}
+
+// runReport is the JSON file run --report writes for CI
+type runReport struct {
+	DurationMs int64           `json:"durationMs"` // Wall time of the whole run
+	Failed     int             `json:"failed"`
+	Tasks      []runReportTask `json:"tasks"`
+}
+
+// runReportTask is one task run in a runReport, in the order the tasks finished
+type runReportTask struct {
+	Name       string    `json:"name"`
+	Item       string    `json:"item,omitempty"` // The --each item the task ran for
+	Status     string    `json:"status"`         // "passed" or "failed"
+	ExitCode   int       `json:"exitCode"`
+	Started    time.Time `json:"started"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// writeReport writes the outcome and duration of every task run as JSON to path
+func (r *taskResults) writeReport(path string, total time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := runReport{DurationMs: total.Milliseconds(), Tasks: make([]runReportTask, 0, len(r.results))}
+
+	for _, result := range r.results {
+		task := runReportTask{
+			Name:       result.name,
+			Item:       result.item,
+			Status:     "passed",
+			ExitCode:   runner.ExitCode(result.err),
+			Started:    result.timing.Started,
+			DurationMs: result.timing.Duration().Milliseconds(),
+		}
+
+		if result.err != nil {
+			task.Status = "failed"
+			report.Failed++
+		}
+
+		report.Tasks = append(report.Tasks, task)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write run report: %w", err)
+	}
+
+	return nil
+}
+
+// formatTaskDuration formats a task duration like go test does, e.g. "1.25s"
+func formatTaskDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
+
+func NewRunCommand(verbose *bool, configPath *string, logOpts *logOptions) *cobra.Command {
+	var opts runOptions
 
 	runCmd := &cobra.Command{
-		Use:   "run [task-name]",
+		Use:   "run [task-name...]",
 		Short: "Execute a task or launch configuration",
 		Long: `Execute a specified task or launch configuration from any supported editor.
 
@@ -104,43 +433,214 @@ If no task name is provided, an interactive selector will be shown.
 Use --no-interactive flag to disable interactive mode (useful for CI/CD).
 
 The task name should match exactly as it appears in the configuration files.
+Aliases from .taskporter.json or ~/.config/taskporter/config.json are accepted too,
+e.g. {"aliases": {"api": "Run API server (staging profile, port 8081)"}}.
 Supports tasks from:
 - VSCode tasks.json
 - VSCode launch.json
 - JetBrains run configurations
+- Sublime Text build systems (*.sublime-project)
+- GitHub Actions run: steps (.github/workflows/*.yml), e.g. "ci/test: Run tests", and
+  whole jobs such as "ci/test" that run every run: step of the job in order
 
 By default, taskporter trusts user configurations and executes them as-is (like IDEs).
 Use --paranoid-mode for additional security validation of commands and arguments.
 
+Use --confirm to review a task's command, working directory and environment before
+it runs. Tasks in the "deploy" group or marked "confirm": true always ask first.
+Use --list to print every task as a plain JSON array (name, type, source, group,
+command, args and resolved cwd) for editor integrations.
+
+Use --dry-run to print the same details plus the environment variables the task
+adds or overrides (secrets redacted) without running anything. For a launch
+configuration it previews the whole chain: preLaunchTask → launch → postDebugTask.
+
+Use --print-env to print the exact environment the task's process gets, one KEY=VALUE
+per line, before it runs (or in the --dry-run preview). Nothing is redacted, so the
+output may contain secrets.
+
+Use --remote user@host to run the task over ssh on a remote dev box. The working
+directory and environment are recreated there, so the project should be checked out
+at the same path. In paranoid mode the host must also be passed to --remote-allow.
+
+Use --respect-problem-matcher to fail a VSCode task when its output matches the
+pattern.regexp of its problemMatcher, even if the process exits 0, the way VSCode
+reports problems. Named matchers such as $tsc carry no pattern and are ignored.
+
+Use --tag to only consider tasks carrying a tag (repeatable, all must match). Without a
+task name the interactive selector opens pre-filtered; type #tag in its search to narrow further.
+
+Use --select-from <source> to only offer tasks from one source (vscode-task, vscode-launch,
+jetbrains, sublime-build, github-actions, global), e.g. to pick a debug launch among many build tasks.
+
+Use --since in monorepos to only run tasks for modules you touched: tasks are skipped
+unless a file changed since the branch left --base (default main, uncommitted changes
+included) lies under their working directory or is the file defining them.
+
+Use --container <image> for reproducible, CI-style runs: the task executes in a
+throwaway docker or podman container with the project root mounted at /workspace.
+
+Use --isolate-env for reproducible builds on the host: instead of inheriting the whole
+environment (the way VSCode merges a task's env over it), the task starts from PATH,
+HOME and TMPDIR only, plus any variable named with --keep-env (repeatable), and its own
+env is applied on top. In paranoid mode a task may set the variables you kept, PATH
+included, e.g.
+  taskporter run release --isolate-env --keep-env GOPATH --keep-env GOFLAGS
+
+Use --clean-env to go further: the task starts from nothing but a PATH of the system
+directories (/usr/local/bin, /usr/bin, /bin and their sbin siblings) plus --keep-env
+variables, and its own env on top, so inherited shell variables can't make builds differ.
+A .taskporter.json task extending another with "cleanEnv": true always runs that way. Paranoid
+mode uses a clean environment by default; pass --clean-env=false to inherit instead.
+
+Use --detach for servers that should keep running after taskporter returns: the task
+starts in its own process group with its output in .taskporter/logs/<task>-<timestamp>.log,
+taskporter prints its PID and log file and exits 0 once it stayed up for a second (a task
+crashing sooner fails with its exit code). 'taskporter ps' lists detached tasks still
+running and 'taskporter stop <task|pid>' stops them. dependsOn and preLaunchTask run first,
+in the foreground.
+
+A task whose working directory does not exist fails before it starts, naming the
+directory and the file defining the task. Use --create-cwd to create it instead, or
+run it through a .taskporter.json task extending it with "createCwd": true.
+
+Several task names run one after another, stopping at the first failure. With
+--keep-going (-k, like make) the remaining tasks (and dependsOn tasks) still run, a
+summary lists every task at the end, and the error names each failed task with its exit
+code; the exit code is the worst one among them. --fail-fast makes the default explicit
+and also keeps parallel compound members and dependsOn tasks from starting once one fails.
+Use --retries to run a task that exits non-zero again, e.g.
+  taskporter run e2e --retries 2 --retry-delay 10s
+
+Use --each to run a task once per line of a file (or stdin with -), with ${item}
+in its command and args replaced by the line, like xargs -n 1:
+  git diff --name-only | taskporter run format-file --each -
+
 Preparing to establish execution strand...`,
-		Args:              cobra.MaximumNArgs(1),
 		ValidArgsFunction: validTaskNames,
 		Run: func(cmd *cobra.Command, args []string) {
-			var taskName string
-			if len(args) > 0 {
-				taskName = args[0]
+			if opts.list {
+				if err := printTaskListJSON(os.Stdout, *configPath, logOpts, args); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+
+				return
+			}
+
+			// Paranoid mode doesn't let inherited variables in unless asked to
+			if opts.paranoidMode && !opts.isolateEnv && !cmd.Flags().Changed("clean-env") {
+				opts.cleanEnv = true
 			}
-			if err := runTaskCommand(taskName, *verbose, *configPath, noInteractive, paranoidMode); err != nil {
+
+			if err := runTaskCommand(args, *verbose, *configPath, logOpts, opts); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+				os.Exit(runner.ExitCode(err))
 			}
 		},
 	}
 
-	runCmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Disable interactive mode (useful for CI/CD)")
-	runCmd.Flags().BoolVar(&paranoidMode, "paranoid-mode", false, "Enable security validation (default: trust user configurations)")
+	runCmd.Flags().BoolVar(&opts.noInteractive, "no-interactive", false, "Disable interactive mode (useful for CI/CD)")
+	runCmd.Flags().BoolVar(&opts.paranoidMode, "paranoid-mode", false, "Enable security validation (default: trust user configurations)")
+	runCmd.Flags().BoolVar(&opts.forceCapture, "force-capture", false, "Apply output capture even to interactive tasks (integratedTerminal, EXECUTE_IN_TERMINAL)")
+	runCmd.Flags().BoolVar(&opts.quietSuccess, "quiet-success", false, "Hide the output of tasks that succeed and show it only when they fail (keeps CI logs clean)")
+	runCmd.Flags().BoolVar(&opts.confirm, "confirm", false, "Show the resolved command and ask for confirmation before running")
+	runCmd.Flags().BoolVar(&opts.list, "list", false, "Print all tasks as a JSON array and exit (for editor integrations)")
+	runCmd.Flags().BoolVar(&opts.printEnv, "print-env", false, "Print the full environment of each task as KEY=VALUE lines before it runs; unredacted, so it may contain secrets")
+	runCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the resolved command and the environment variables the task adds or overrides, without running it")
+	runCmd.Flags().BoolVar(&opts.dumpScript, "dump-script", false, "Print a shell one-liner doing what running each task would (cd, env exports, quoted args), without running it")
+	runCmd.Flags().StringVar(&opts.remote, "remote", "", "Run the task over ssh on this host (user@host)")
+	runCmd.Flags().StringSliceVar(&opts.remoteAllow, "remote-allow", nil, "Hosts --remote may target in paranoid mode")
+	runCmd.Flags().StringVar(&opts.container, "container", "", "Run the task inside this container image with the project mounted at /workspace")
+	runCmd.Flags().StringVar(&opts.engine, "container-engine", "", "Container engine for --container (docker, podman; default: auto-detect)")
+	runCmd.Flags().BoolVar(&opts.isolateEnv, "isolate-env", false, "Start tasks from PATH, HOME and TMPDIR only instead of the whole environment")
+	runCmd.Flags().BoolVar(&opts.cleanEnv, "clean-env", false, "Start tasks from a minimal PATH and their own env only (default in paranoid mode)")
+	runCmd.Flags().StringArrayVar(&opts.keepEnv, "keep-env", nil, "Variable to keep from the environment with --isolate-env or --clean-env (repeatable)")
+	runCmd.Flags().BoolVar(&opts.createCwd, "create-cwd", false, "Create a task's missing working directory instead of failing")
+	runCmd.Flags().StringVar(&opts.report, "report", "", "Write the duration and outcome of every task run to this JSON file (for CI)")
+	runCmd.Flags().StringVar(&opts.each, "each", "", "Run the task once per line of this file (- for stdin), replacing ${item} in its command and args")
+	runCmd.Flags().BoolVar(&opts.detach, "detach", false, "Start the task in the background with its output in .taskporter/logs, and return once it is up")
+	runCmd.Flags().BoolVar(&opts.problems, "respect-problem-matcher", false, "Fail tasks whose output matches their problemMatcher pattern, even if they exit 0")
+	runCmd.Flags().StringSliceVar(&opts.tags, "tag", nil, "Only consider tasks carrying this tag (repeatable)")
+	runCmd.Flags().BoolVar(&opts.since, "since", false, "Only run tasks whose working directory or source changed since --base (per git diff)")
+	runCmd.Flags().StringVar(&opts.base, "base", "main", "Git ref --since compares against")
+	runCmd.Flags().IntVar(&opts.retries, "retries", 0, "Run a task that exits non-zero up to this many more times")
+	runCmd.Flags().DurationVar(&opts.retryDelay, "retry-delay", time.Second, "Wait before the first retry; each further retry waits twice as long")
+	runCmd.Flags().BoolVar(&opts.retryPre, "retry-pre", false, "Retry failing preLaunchTasks too")
+	runCmd.Flags().BoolVar(&opts.skipPre, "skip-pre", false, "Run launch configurations without their preLaunchTask, e.g. when the build is already done")
+	runCmd.Flags().StringVar(&opts.expectExit, "expect-exit", "", "Exit codes that count as success besides 0, e.g. 1 or 0-3,5 (for report-only linters)")
+	runCmd.Flags().BoolVarP(&opts.keepGoing, "keep-going", "k", false, "Keep running the remaining tasks after one fails, then report every failed task (like make -k)")
+	runCmd.Flags().BoolVar(&opts.keepGoing, "continue-on-error", false, "Alias for --keep-going")
+	runCmd.Flags().BoolVar(&opts.failFast, "fail-fast", false, "Stop at the first failure, also keeping parallel tasks that haven't started from starting")
+	runCmd.Flags().StringVar(&opts.selectFrom, "select-from", "", "Only consider tasks from this source (vscode-task, vscode-launch, jetbrains, sublime-build, github-actions, global)")
+
+	runCmd.MarkFlagsMutuallyExclusive("remote", "container")
+	runCmd.MarkFlagsMutuallyExclusive("isolate-env", "clean-env")
+	runCmd.MarkFlagsMutuallyExclusive("each", "detach")
+	runCmd.MarkFlagsMutuallyExclusive("fail-fast", "keep-going", "continue-on-error")
+	runCmd.MarkFlagsMutuallyExclusive("dump-script", "dry-run")
+	runCmd.MarkFlagsMutuallyExclusive("dump-script", "detach")
+	runCmd.MarkFlagsMutuallyExclusive("dump-script", "print-env")
+
+	_ = runCmd.RegisterFlagCompletionFunc("container-engine", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{runner.ContainerEngineDocker, runner.ContainerEnginePodman}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = runCmd.RegisterFlagCompletionFunc("tag", validTaskTags)
+	_ = runCmd.RegisterFlagCompletionFunc("select-from", validTaskSources)
 
 	return runCmd
 }
 
-func runTaskCommand(taskName string, verbose bool, configPath string, noInteractive bool, paranoidMode bool) error {
+func runTaskCommand(taskNames []string, verbose bool, configPath string, logOpts *logOptions, opts runOptions) error {
+	logger, err := logOpts.newLogger(os.Stderr, verbose)
+	if err != nil {
+		return err
+	}
+
+	opts.logger = logger
+	opts.ctx = context.Background()
+	opts.results = &taskResults{}
+
+	if opts.retries < 0 {
+		return fmt.Errorf("invalid --retries %d: must not be negative", opts.retries)
+	}
+
+	if opts.expectExit != "" {
+		if opts.expectedExit, err = runner.ParseExitCodes(opts.expectExit); err != nil {
+			return fmt.Errorf("invalid --expect-exit: %w", err)
+		}
+	}
+
+	if opts.engine != "" && opts.engine != runner.ContainerEngineDocker && opts.engine != runner.ContainerEnginePodman {
+		return fmt.Errorf("invalid container engine '%s'. Valid options: %s, %s", opts.engine, runner.ContainerEngineDocker, runner.ContainerEnginePodman)
+	}
+
+	if err := validateSelectFrom(opts.selectFrom); err != nil {
+		return err
+	}
+
+	if len(opts.keepEnv) > 0 && !opts.isolateEnv && !opts.cleanEnv {
+		return fmt.Errorf("--keep-env requires --isolate-env or --clean-env")
+	}
+
+	if opts.each != "" {
+		// The interactive selector would compete with the items for stdin
+		if opts.each == "-" && len(taskNames) == 0 {
+			return fmt.Errorf("--each - reads the items from stdin, so name the task to run")
+		}
+
+		if opts.items, err = readEachItems(opts.each); err != nil {
+			return err
+		}
+	}
+
 	// Create sanitizer for input validation (only used in paranoid mode)
 	sanitizer := security.NewSanitizer(".")
 
 	// Only validate inputs in paranoid mode
-	if paranoidMode {
-		// Validate task name if provided
-		if taskName != "" {
+	if opts.paranoidMode {
+		// Validate task names if provided
+		for _, taskName := range taskNames {
 			if err := sanitizer.ValidateTaskName(taskName); err != nil {
 				return fmt.Errorf("invalid task name: %w", err)
 			}
@@ -159,7 +659,7 @@ func runTaskCommand(taskName string, verbose bool, configPath string, noInteract
 	}
 
 	// Initialize project detector and find all tasks
-	detector := config.NewProjectDetector(projectRoot)
+	detector := logOpts.newProjectDetector(projectRoot)
 
 	projectConfig, err := detector.DetectProject()
 	if err != nil {
@@ -168,211 +668,1014 @@ func runTaskCommand(taskName string, verbose bool, configPath string, noInteract
 
 	if verbose {
 		fmt.Printf("📁 Project root: %s\n", projectConfig.ProjectRoot)
+		reportIgnoredPaths(detector)
 	}
 
-	var allTasks []*config.Task
+	var changedFilter *runner.ChangedTaskFilter
+	if opts.since {
+		if changedFilter, err = runner.NewChangedTaskFilterFromGit(projectConfig.ProjectRoot, opts.base, logger); err != nil {
+			return fmt.Errorf("failed to find changed files: %w", err)
+		}
 
-	// Parse VSCode tasks
-	if projectConfig.HasVSCode {
-		if tasksPath := detector.GetVSCodeTasksPath(); tasksPath != "" {
-			if verbose {
-				fmt.Printf("📋 Scanning VSCode tasks from: %s\n", tasksPath)
-			}
+		if verbose {
+			fmt.Printf("🧭 %d files changed since %s\n", len(changedFilter.Changed()), opts.base)
+		}
+	}
 
-			parser := vscode.NewTasksParser(projectConfig.ProjectRoot)
+	// Without a task name the selector opens right away and fills in as each source is parsed
+	if len(taskNames) == 0 && !opts.noInteractive {
+		return runTaskSelection(detector, projectConfig, changedFilter, verbose, logOpts, opts)
+	}
 
-			tasks, err := parser.ParseTasks(tasksPath)
-			if err != nil {
-				if verbose {
-					fmt.Printf("⚠️  Warning: failed to parse VSCode tasks: %v\n", err)
-				}
-			} else {
-				allTasks = append(allTasks, tasks...)
-			}
-		}
+	// The selector and task output take over the terminal, so the spinner only runs while scanning
+	sources := projectTaskSources(detector, projectConfig, taskSourceOptions{
+		verbose:  verbose,
+		strict:   logOpts.strict,
+		progress: !opts.noInteractive && showScanProgress(verbose, logger),
 
-		// Parse VSCode launch configurations
-		if launchPath := detector.GetVSCodeLaunchPath(); launchPath != "" {
-			if verbose {
-				fmt.Printf("🚀 Scanning VSCode launch configs from: %s\n", launchPath)
-			}
+		launchFailures: &logOpts.launchFailures,
+	}, logger)
 
-			launchParser := vscode.NewLaunchParser(projectConfig.ProjectRoot)
+	allTasks, err := parseTaskSources(sources, nil)
+	if err != nil {
+		return err
+	}
 
-			launchTasks, err := launchParser.ParseLaunchConfigs(launchPath)
-			if err != nil {
-				if verbose {
-					fmt.Printf("⚠️  Warning: failed to parse VSCode launch configs: %v\n", err)
-				}
-			} else {
-				allTasks = append(allTasks, launchTasks...)
-			}
-		}
+	if err := logOpts.operationErr(); err != nil {
+		return err
 	}
 
-	// Parse JetBrains configurations
-	if projectConfig.HasJetBrains {
-		jetbrainsPaths := detector.GetJetBrainsRunConfigPaths()
-		if verbose && len(jetbrainsPaths) > 0 {
-			fmt.Printf("🧠 Scanning JetBrains configurations from: %d files\n", len(jetbrainsPaths))
-		}
+	allTasks, err = finishTaskList(allTasks, projectConfig.ProjectRoot, verbose, logOpts, logger)
+	if err != nil {
+		return err
+	}
 
-		parser := jetbrains.NewRunConfigurationParser(projectConfig.ProjectRoot)
-		for _, configPath := range jetbrainsPaths {
-			task, err := parser.ParseRunConfiguration(configPath)
-			if err != nil {
-				if verbose {
-					fmt.Printf("⚠️  Warning: failed to parse JetBrains config %s: %v\n", configPath, err)
-				}
-			} else {
-				allTasks = append(allTasks, task)
-			}
-		}
+	var aliases []config.Alias
+	if len(taskNames) > 0 {
+		aliases = loadAliases(projectConfig.ProjectRoot, allTasks, logger)
 	}
 
-	if len(allTasks) == 0 {
-		fmt.Println("❌ No tasks found in this project.")
-		fmt.Println()
-		fmt.Println("Use 'taskporter list' to see available tasks and launch configurations.")
-		fmt.Println("📡 Strand connection failed... no active configurations detected.")
+	// Tagged and source-restricted runs only consider matching tasks; preLaunchTask lookups still see every task
+	candidates := filterTasksByGroupAndSource(config.FilterByTags(allTasks, opts.tags), "", opts.selectFrom)
 
-		return nil
+	// A named task is looked up among all tasks so an unaffected one is skipped rather than not found
+	if changedFilter != nil && len(taskNames) == 0 {
+		candidates = changedFilter.Filter(candidates)
 	}
 
-	// Convert to value type for interactive selector
-	tasks := make([]config.Task, len(allTasks))
-	for i, taskPtr := range allTasks {
-		tasks[i] = *taskPtr
+	if reportNoRunCandidates(allTasks, candidates, opts) {
+		return nil
 	}
 
-	// If no task name provided, run interactive mode (unless disabled)
-	if taskName == "" {
-		if noInteractive {
-			fmt.Println("❌ No task name provided and interactive mode is disabled.")
-			fmt.Println()
-			fmt.Println("Available tasks:")
-
-			for _, taskPtr := range allTasks {
-				fmt.Printf("  • %s", taskPtr.Name)
+	// If no task name provided, list the tasks: interactive mode is disabled
+	if len(taskNames) == 0 {
+		fmt.Println("❌ No task name provided and interactive mode is disabled.")
+		fmt.Println()
+		fmt.Println("Available tasks:")
 
-				if taskPtr.Group != "" {
-					fmt.Printf(" [%s]", taskPtr.Group)
-				}
+		for _, taskPtr := range candidates {
+			fmt.Printf("  • %s", taskPtr.Name)
 
-				fmt.Printf(" - %s", getTaskSourceDisplay(taskPtr))
-				fmt.Println()
+			if taskPtr.Group != "" {
+				fmt.Printf(" [%s]", taskPtr.Group)
 			}
 
+			fmt.Printf(" - %s", getTaskSourceDisplay(taskPtr))
 			fmt.Println()
-			fmt.Println("Usage: taskporter run <task-name>")
-			fmt.Println("   or: taskporter run (for interactive mode)")
-			fmt.Println("📡 Strand connection failed... no task specified.")
+		}
+
+		fmt.Println()
+		fmt.Println("Usage: taskporter run <task-name>")
+		fmt.Println("   or: taskporter run (for interactive mode)")
+		fmt.Println("📡 Strand connection failed... no task specified.")
+
+		return nil
+	}
 
+	// Resolve every named task before running any, so a typo in the last name doesn't surface halfway through
+	requested := make([]*config.Task, 0, len(taskNames))
+
+	for _, taskName := range taskNames {
+		task, found := findNamedTask(taskName, candidates, aliases, verbose, opts)
+		if !found {
 			return nil
 		}
 
-		if verbose {
-			fmt.Printf("🎮 Starting interactive task selector...\n")
-		}
+		requested = append(requested, task)
+	}
 
-		selectedTask, err := runner.RunInteractiveTaskSelector(tasks)
-		if err != nil {
-			return fmt.Errorf("interactive selection failed: %w", err)
+	selected := make([]*config.Task, 0, len(requested))
+
+	for _, task := range requested {
+		if changedFilter != nil && !changedFilter.Affected(task) {
+			fmt.Printf("⏭️  Skipping %s: no changes under its working directory or in its source since %s\n", task.Name, opts.base)
+
+			continue
 		}
 
-		if selectedTask == nil {
-			// User cancelled
+		if !confirmTaskExecution(task, opts) {
+			fmt.Println("👋 Porter mission cancelled. Until next time!")
+
 			return nil
 		}
-		// Use the selected task
-		task := selectedTask
 
-		return executeSelectedTask(task, allTasks, projectConfig, detector, verbose, paranoidMode)
+		selected = append(selected, task)
 	}
 
-	if verbose {
-		fmt.Printf("🔍 Searching for task: %s\n", taskName)
-	}
+	return executeTasks(selected, allTasks, projectConfig, detector, verbose, opts)
+}
 
-	// Find the requested task
-	finder := runner.NewTaskFinder()
+// finishTaskList adds the global and extended tasks to the parsed ones and applies the configured tags
+func finishTaskList(tasks []*config.Task, projectRoot string, verbose bool, logOpts *logOptions, logger *slog.Logger) ([]*config.Task, error) {
+	tasks = withGlobalTasks(tasks, projectRoot, logOpts.noGlobal, verbose, logger)
 
-	task, err := finder.FindTask(taskName, allTasks)
+	tasks, err := withTaskExtensions(tasks, projectRoot, logger)
 	if err != nil {
-		fmt.Printf("❌ %v\n", err)
-		fmt.Println()
-		fmt.Println("Available tasks:")
-
-		for _, t := range allTasks {
-			fmt.Printf("  • %s", t.Name)
+		return nil, err
+	}
 
-			if t.Group != "" {
-				fmt.Printf(" [%s]", t.Group)
-			}
+	applyConfiguredTags(tasks, projectRoot, logger)
 
-			fmt.Println()
-		}
+	return tasks, nil
+}
 
+// reportNoRunCandidates explains why there is nothing to run, reporting false when there is
+func reportNoRunCandidates(allTasks, candidates []*config.Task, opts runOptions) bool {
+	switch {
+	case len(allTasks) == 0:
+		fmt.Println("❌ No tasks found in this project.")
 		fmt.Println()
-		fmt.Println("📡 Strand connection failed... task not in network.")
-
-		return nil
-	}
-
-	if verbose {
-		fmt.Printf("✅ Found task: %s (%s)\n", task.Name, task.Type)
+		fmt.Println("Use 'taskporter list' to see available tasks and launch configurations.")
+	case len(candidates) == 0:
+		fmt.Printf("❌ No tasks %s.\n", describeRunFilters(opts))
 		fmt.Println()
+		fmt.Println("Use 'taskporter list' to see available tasks with their sources and tags.")
+	default:
+		return false
 	}
 
-	return executeSelectedTask(task, allTasks, projectConfig, detector, verbose, paranoidMode)
+	fmt.Println("📡 Strand connection failed... no active configurations detected.")
+
+	return true
 }
 
-// executeSelectedTask executes a task with proper preLaunchTask handling
-func executeSelectedTask(task *config.Task, allTasks []*config.Task, projectConfig *config.ProjectConfig, detector *config.ProjectDetector, verbose bool, paranoidMode bool) error {
-	// Check for preLaunchTask if this is a launch configuration
-	if task.Type == config.TypeVSCodeLaunch {
-		finder := runner.NewTaskFinder()
-		if err := runPreLaunchTask(task, allTasks, projectConfig, detector, finder, verbose, paranoidMode); err != nil {
-			return fmt.Errorf("preLaunchTask failed: %w", err)
+// runTaskSelection opens the interactive selector while the project's sources are still being
+// parsed, each in a goroutine of its own, and adds their tasks as they complete. A task picked
+// before every source is parsed runs once they are, so dependsOn and preLaunchTask see them all.
+func runTaskSelection(detector *config.ProjectDetector, projectConfig *config.ProjectConfig, changedFilter *runner.ChangedTaskFilter, verbose bool, logOpts *logOptions, opts runOptions) error {
+	// Verbose scanning output would draw over the selector
+	sources := projectTaskSources(detector, projectConfig, taskSourceOptions{strict: logOpts.strict, launchFailures: &logOpts.launchFailures}, opts.logger)
+
+	candidatesOf := func(tasks []*config.Task) []*config.Task {
+		candidates := filterTasksByGroupAndSource(config.FilterByTags(tasks, opts.tags), "", opts.selectFrom)
+		if changedFilter != nil {
+			candidates = changedFilter.Filter(candidates)
 		}
-	}
 
-	// Execute the main task with paranoid mode option
-	taskRunner := runner.NewTaskRunnerWithOptions(verbose, projectConfig.ProjectRoot, paranoidMode)
-	if err := taskRunner.RunTask(task); err != nil {
-		return fmt.Errorf("execution failed: %w", err)
+		return candidates
 	}
 
-	return nil
-}
+	// Buffered for every batch, so loading finishes even if the selector quits first
+	batches := make(chan runner.TaskBatch, len(sources)+1)
+	loaded := make(chan struct{})
 
-// runPreLaunchTask executes a preLaunchTask if specified in a launch configuration
-func runPreLaunchTask(launchTask *config.Task, allTasks []*config.Task, projectConfig *config.ProjectConfig, detector *config.ProjectDetector, finder *runner.TaskFinder, verbose bool, paranoidMode bool) error {
-	// Only check VSCode launch configurations for preLaunchTask
-	if launchTask.Type != config.TypeVSCodeLaunch {
-		return nil
-	}
+	var allTasks, candidates []*config.Task
 
-	// Get the launch file path
-	launchPath := detector.GetVSCodeLaunchPath()
-	if launchPath == "" {
-		return nil // No launch.json file found
-	}
+	var loadErr error
 
-	// Create launch parser to get preLaunchTask name
-	launchParser := vscode.NewLaunchParser(projectConfig.ProjectRoot)
+	go func() {
+		defer close(loaded)
 
-	preLaunchTaskName, err := launchParser.GetPreLaunchTask(launchPath, launchTask.Name)
-	if err != nil {
-		if verbose {
-			fmt.Printf("⚠️  Warning: failed to get preLaunchTask for %s: %v\n", launchTask.Name, err)
+		// The configured tags are read once, partial lists are tagged before they are filtered
+		tags, _ := config.LoadTaskTags(projectConfig.ProjectRoot)
+
+		allTasks, loadErr = parseTaskSources(sources, func(tasks []*config.Task, done int) {
+			config.ApplyTaskTags(tasks, tags)
+			batches <- runner.TaskBatch{Tasks: taskValues(candidatesOf(tasks)), Loaded: done, Sources: len(sources)}
+		})
+
+		if loadErr == nil {
+			loadErr = logOpts.operationErr()
 		}
 
-		return nil // Continue without preLaunchTask
-	}
+		if loadErr != nil {
+			batches <- runner.TaskBatch{Err: loadErr}
+			return
+		}
+
+		if allTasks, loadErr = finishTaskList(allTasks, projectConfig.ProjectRoot, false, logOpts, opts.logger); loadErr != nil {
+			batches <- runner.TaskBatch{Err: loadErr}
+			return
+		}
+
+		candidates = candidatesOf(allTasks)
+		batches <- runner.TaskBatch{Tasks: taskValues(candidates), Loaded: len(sources), Sources: len(sources), Final: true}
+	}()
+
+	if verbose {
+		fmt.Printf("🎮 Starting interactive task selector...\n")
+	}
+
+	selectedTask, err := runner.RunStreamingTaskSelector(batches, opts.confirm)
+	if err != nil {
+		return fmt.Errorf("interactive selection failed: %w", err)
+	}
+
+	if selectedTask == nil {
+		// The selector closes by itself once loading failed or found nothing to pick from;
+		// a user who cancelled earlier doesn't wait for the rest to load
+		select {
+		case <-loaded:
+		default:
+			return nil
+		}
+
+		if loadErr != nil {
+			return loadErr
+		}
+
+		reportNoRunCandidates(allTasks, candidates, opts)
+
+		return nil
+	}
+
+	<-loaded
+
+	if loadErr != nil {
+		return loadErr
+	}
+
+	// The selector holds copies; run the task the complete list has under that name and source
+	task := selectedTask
+	if index := slices.IndexFunc(candidates, func(candidate *config.Task) bool {
+		return candidate.Name == selectedTask.Name && candidate.Source == selectedTask.Source
+	}); index >= 0 {
+		task = candidates[index]
+	}
+
+	return executeTasks([]*config.Task{task}, allTasks, projectConfig, detector, verbose, opts)
+}
+
+// taskValues copies tasks for the interactive selector, which works on values
+func taskValues(tasks []*config.Task) []config.Task {
+	values := make([]config.Task, len(tasks))
+	for i, task := range tasks {
+		values[i] = *task
+	}
+
+	return values
+}
+
+// findNamedTask looks up a task named on the command line. When a human is at the terminal,
+// a name matching several tasks opens the selector over them and a misspelt one offers the
+// closest matches. It reports the available tasks and returns false if none matches.
+func findNamedTask(taskName string, candidates []*config.Task, aliases []config.Alias, verbose bool, opts runOptions) (*config.Task, bool) {
+	if verbose {
+		fmt.Printf("🔍 Searching for task: %s\n", taskName)
+	}
+
+	// Find the requested task
+	finder := runner.NewTaskFinder()
+	finder.SetAliases(aliases)
+
+	task, err := finder.FindTask(taskName, candidates)
+
+	interactive := !opts.noInteractive && isInteractiveTerminal()
+
+	// Let a human pick between the tasks an ambiguous name matched
+	var ambiguousErr *runner.AmbiguousMatchError
+	if errors.As(err, &ambiguousErr) && interactive {
+		return selectNamedTask(taskName, ambiguousErr.Matches, taskName, verbose)
+	}
+
+	// Offer the closest fuzzy matches when a human is at the terminal. The misspelt name would
+	// filter them out again, so the selector starts with an empty search.
+	var notFoundErr *runner.TaskNotFoundError
+	if errors.As(err, &notFoundErr) && interactive {
+		if len(notFoundErr.SuggestedTasks) > 1 {
+			fmt.Printf("❓ Task '%s' not found. Did you mean one of these?\n", taskName)
+
+			return selectNamedTask(taskName, notFoundErr.SuggestedTasks, "", verbose)
+		}
+
+		if notFoundErr.Match != nil {
+			prompt := fmt.Sprintf("❓ Task '%s' not found. Did you mean '%s'? [y/N] ", taskName, notFoundErr.Match.Name)
+			if confirmPrompt(os.Stdout, prompt) {
+				task, err = notFoundErr.Match, nil
+			}
+		}
+	}
+
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+
+		// Suggestions are already part of the error, so skip the full listing
+		if notFoundErr != nil && len(notFoundErr.Suggestions) > 0 {
+			fmt.Println()
+			fmt.Println("📡 Strand connection failed... task not in network.")
+
+			return nil, false
+		}
+
+		fmt.Println()
+		fmt.Println("Available tasks:")
+
+		for _, t := range candidates {
+			fmt.Printf("  • %s", t.Name)
+
+			if t.Group != "" {
+				fmt.Printf(" [%s]", t.Group)
+			}
+
+			fmt.Println()
+		}
+
+		fmt.Println()
+		fmt.Println("📡 Strand connection failed... task not in network.")
+
+		return nil, false
+	}
+
+	if verbose {
+		fmt.Printf("✅ Found task: %s (%s)\n", task.Name, task.Type)
+		fmt.Println()
+	}
+
+	return task, true
+}
+
+// selectNamedTask opens the interactive selector over the tasks a name could refer to, with
+// query in the search box, and returns the one picked. It returns false if the user cancels.
+func selectNamedTask(taskName string, matches []*config.Task, query string, verbose bool) (*config.Task, bool) {
+	if verbose {
+		fmt.Printf("🎮 '%s' matches %d tasks, starting interactive task selector...\n", taskName, len(matches))
+	}
+
+	tasks := make([]config.Task, 0, len(matches))
+	for _, match := range matches {
+		tasks = append(tasks, *match)
+	}
+
+	selected, err := runner.RunInteractiveTaskSelectorWithQuery(tasks, query)
+	if err != nil {
+		fmt.Printf("❌ interactive selection failed: %v\n", err)
+
+		return nil, false
+	}
+
+	if selected == nil {
+		fmt.Println("👋 Porter mission cancelled. Until next time!")
+
+		return nil, false
+	}
+
+	// Hand back the task itself rather than the selector's copy
+	for _, match := range matches {
+		if match.Name == selected.Name && match.Source == selected.Source {
+			return match, true
+		}
+	}
+
+	return nil, false
+}
+
+// executeTasks runs the tasks one after another, stopping at the first failure unless
+// --keep-going is given. An interrupt ends the running task and cancels the rest.
+func executeTasks(tasks []*config.Task, allTasks []*config.Task, projectConfig *config.ProjectConfig, detector *config.ProjectDetector, verbose bool, opts runOptions) (err error) {
+	if opts.dumpScript {
+		return dumpTaskScripts(tasks, allTasks, projectConfig.ProjectRoot, verbose, opts)
+	}
+
+	if !opts.dryRun {
+		// Only catch interrupts once tasks run, so Ctrl+C still ends taskporter at a prompt.
+		// The runner passes them on to the running task; here they cancel the remaining tasks.
+		ctx, stop := signal.NotifyContext(context.Background(), runner.InterruptSignals...)
+		defer stop()
+
+		opts.ctx = ctx
+	}
+
+	if opts.failFast {
+		ctx, stop := context.WithCancelCause(opts.ctx)
+		defer stop(nil)
+
+		opts.ctx, opts.stop = ctx, stop
+	}
+
+	if !opts.dryRun {
+		started := time.Now()
+
+		defer func() {
+			total := time.Since(started)
+			opts.results.printSummary(os.Stdout, total)
+
+			if opts.report == "" {
+				return
+			}
+
+			if reportErr := opts.results.writeReport(opts.report, total); reportErr != nil {
+				err = errors.Join(err, reportErr)
+			}
+		}()
+	}
+
+	var (
+		failed      []taskResult
+		interrupted error
+	)
+
+	for _, task := range tasks {
+		if opts.ctx.Err() != nil {
+			interrupted = fmt.Errorf("task '%s' was not started: %w", task.Name, context.Cause(opts.ctx))
+
+			break
+		}
+
+		err := executeSelectedTask(task, allTasks, projectConfig, detector, verbose, opts)
+		if err == nil {
+			continue
+		}
+
+		if !opts.keepGoing {
+			return err
+		}
+
+		failed = append(failed, taskResult{name: task.Name, err: err})
+	}
+
+	switch {
+	case len(failed) == 1 && len(tasks) == 1:
+		err = failed[0].err
+	case len(failed) > 0:
+		err = &failedTasksError{failed: failed, total: len(tasks)}
+	}
+
+	return errors.Join(err, interrupted)
+}
+
+// describeRunFilters phrases the active --tag, --select-from and --since filters for the "no tasks" message
+func describeRunFilters(opts runOptions) string {
+	var parts []string
+
+	if len(opts.tags) > 0 {
+		parts = append(parts, "tagged "+strings.Join(opts.tags, ", "))
+	}
+
+	if opts.selectFrom != "" {
+		parts = append(parts, "from source "+opts.selectFrom)
+	}
+
+	if opts.since {
+		parts = append(parts, "affected by changes since "+opts.base)
+	}
+
+	return strings.Join(parts, " and ")
+}
+
+// validateSelectFrom rejects --select-from values that name no task source
+func validateSelectFrom(source string) error {
+	if source == "" {
+		return nil
+	}
+
+	names := make([]string, 0, len(config.TaskTypes))
+
+	for _, taskType := range config.TaskTypes {
+		if strings.EqualFold(string(taskType), source) {
+			return nil
+		}
+
+		names = append(names, string(taskType))
+	}
+
+	return fmt.Errorf("invalid source '%s'. Valid options: %s", source, strings.Join(names, ", "))
+}
+
+// executeSelectedTask executes a task with proper preLaunchTask handling
+func executeSelectedTask(task *config.Task, allTasks []*config.Task, projectConfig *config.ProjectConfig, detector *config.ProjectDetector, verbose bool, opts runOptions) error {
+	if task.IsCompound() {
+		if opts.each != "" {
+			return fmt.Errorf("--each does not apply to compound configuration '%s'; run its members instead", task.Name)
+		}
+
+		return runCompoundTask(task, allTasks, projectConfig, detector, verbose, opts)
+	}
+
+	if opts.each != "" && !runner.UsesItem(task) {
+		fmt.Printf("⚠️  %s does not use %s, so every item runs the same command\n", task.Name, runner.ItemVariable)
+	}
+
+	// Failures before the task itself only stop it without --keep-going
+	var errs []error
+
+	if len(task.DependsOn) > 0 {
+		if err := runDependencies(task, allTasks, projectConfig, detector, verbose, opts); err != nil {
+			err = fmt.Errorf("dependsOn of '%s' failed: %w", task.Name, err)
+			if !opts.keepGoing {
+				return err
+			}
+
+			errs = append(errs, err)
+		}
+
+		// An aggregate task is done once its dependencies ran
+		if task.IsAggregate() {
+			return errors.Join(errs...)
+		}
+	}
+
+	if opts.printEnv {
+		if err := printTaskEnvironment(os.Stdout, task, projectConfig.ProjectRoot, verbose, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.dryRun {
+		if opts.each != "" {
+			return runner.RunEach(context.Background(), task, opts.items, true, func(i int, expanded *config.Task) error {
+				fmt.Printf("📄 [%d/%d] %s\n", i+1, len(opts.items), opts.items[i])
+
+				return previewTask(expanded, projectConfig.ProjectRoot, verbose, opts)
+			})
+		}
+
+		if task.Type == config.TypeVSCodeLaunch && (task.PreLaunchTask != "" || task.PostDebugTask != "") {
+			return previewLaunchChain(task, allTasks, projectConfig.ProjectRoot, verbose, opts)
+		}
+
+		return previewTask(task, projectConfig.ProjectRoot, verbose, opts)
+	}
+
+	// Check for preLaunchTask if this is a launch configuration
+	if task.Type == config.TypeVSCodeLaunch {
+		finder := runner.NewTaskFinder()
+		if err := runPreLaunchTask(task, allTasks, projectConfig, finder, verbose, opts); err != nil {
+			err = fmt.Errorf("preLaunchTask failed: %w", err)
+			if !opts.keepGoing {
+				return err
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	// Dependencies and preLaunchTasks above ran in the foreground; only the task itself is detached
+	if opts.detach {
+		if err := startDetachedTask(task, projectConfig.ProjectRoot, verbose, opts); err != nil {
+			errs = append(errs, fmt.Errorf("detached start failed: %w", err))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	// Execute the main task with the run options applied, once per item with --each
+	if opts.each != "" {
+		if err := runTaskForEachItem(task, projectConfig.ProjectRoot, verbose, opts); err != nil {
+			errs = append(errs, fmt.Errorf("execution failed: %w", err))
+		}
+	} else if err := runSingleTask(task, projectConfig.ProjectRoot, verbose, opts, true); err != nil {
+		errs = append(errs, fmt.Errorf("execution failed: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// dumpTaskScripts prints one shell line per task doing what running it would, so it can be
+// pasted into a terminal or CI script without taskporter. Nothing else goes to stdout.
+func dumpTaskScripts(tasks []*config.Task, allTasks []*config.Task, projectRoot string, verbose bool, opts runOptions) error {
+	// A preLaunchTask that cannot be found fails instead of prompting for the closest one
+	opts.noInteractive = true
+
+	taskRunner := newTaskRunner(verbose, projectRoot, opts)
+
+	for _, task := range tasks {
+		scripts, err := taskScripts(task, allTasks, taskRunner, opts)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(strings.Join(scripts, " && "))
+	}
+
+	return nil
+}
+
+// taskScripts returns the commands running a task takes, in order: its dependencies, its
+// preLaunchTask, then the task itself, once per --each item. The members of a compound and
+// dependencies started together follow one another, as a shell line cannot run them in parallel.
+func taskScripts(task *config.Task, allTasks []*config.Task, taskRunner *runner.TaskRunner, opts runOptions) ([]string, error) {
+	if task.IsCompound() && opts.each != "" {
+		return nil, fmt.Errorf("--each does not apply to compound configuration '%s'; run its members instead", task.Name)
+	}
+
+	dependencies, err := dependencyTasks(task, allTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	var scripts []string
+
+	// A compound's preLaunchTask runs before its members
+	if task.IsCompound() {
+		if scripts, err = preLaunchScript(task, allTasks, taskRunner, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, dependency := range dependencies {
+		dependencyScripts, err := taskScripts(dependency, allTasks, taskRunner, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		scripts = append(scripts, dependencyScripts...)
+	}
+
+	if task.IsCompound() || task.IsAggregate() {
+		return scripts, nil
+	}
+
+	preLaunch, err := preLaunchScript(task, allTasks, taskRunner, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	scripts = append(scripts, preLaunch...)
+
+	if opts.each == "" {
+		script, err := taskRunner.DumpScript(task)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(scripts, script), nil
+	}
+
+	err = runner.RunEach(context.Background(), task, opts.items, false, func(_ int, expanded *config.Task) error {
+		script, err := taskRunner.DumpScript(expanded)
+		scripts = append(scripts, script)
+
+		return err
+	})
+
+	return scripts, err
+}
+
+// preLaunchScript returns the command of a launch configuration's preLaunchTask, if it runs one
+func preLaunchScript(task *config.Task, allTasks []*config.Task, taskRunner *runner.TaskRunner, opts runOptions) ([]string, error) {
+	// --skip-pre is not announced here, so stdout holds nothing but the script
+	if task.Type != config.TypeVSCodeLaunch || task.PreLaunchTask == "" || opts.skipPre {
+		return nil, nil
+	}
+
+	preLaunchTask, err := findPreLaunchTask(task, allTasks, runner.NewTaskFinder(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("preLaunchTask failed: %w", err)
+	}
+
+	script, err := taskRunner.DumpScript(preLaunchTask)
+	if err != nil {
+		return nil, fmt.Errorf("preLaunchTask '%s': %w", preLaunchTask.Name, err)
+	}
+
+	return []string{script}, nil
+}
+
+// readEachItems reads the items of --each from a file, or from stdin for "-"
+func readEachItems(path string) ([]string, error) {
+	input := io.Reader(os.Stdin)
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --each items: %w", err)
+		}
+		defer file.Close()
+
+		input = file
+	}
+
+	items, err := runner.ReadItems(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no items to run for in --each %s", path)
+	}
+
+	return items, nil
+}
+
+// runTaskForEachItem runs the task once per --each item with ${item} replaced by it. The run
+// stops at the first failing item unless --keep-going is set.
+func runTaskForEachItem(task *config.Task, projectRoot string, verbose bool, opts runOptions) error {
+	ctx := opts.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return runner.RunEach(ctx, task, opts.items, opts.keepGoing, func(i int, expanded *config.Task) error {
+		fmt.Printf("📄 [%d/%d] %s: %s\n", i+1, len(opts.items), task.Name, opts.items[i])
+
+		itemOpts := opts
+		itemOpts.item = opts.items[i]
+
+		return runSingleTask(expanded, projectRoot, verbose, itemOpts, true)
+	})
+}
+
+// printTaskEnvironment writes the environment the task's process would get, one KEY=VALUE per
+// line. The header goes to stderr so the variables alone can be piped or redirected.
+func printTaskEnvironment(w io.Writer, task *config.Task, projectRoot string, verbose bool, opts runOptions) error {
+	env, err := newTaskRunner(verbose, projectRoot, opts).ResolveEnvironment(task)
+	if err != nil {
+		return fmt.Errorf("failed to build environment for task '%s': %w", task.Name, err)
+	}
+
+	switch {
+	case opts.remote != "":
+		fmt.Fprintf(os.Stderr, "🌐 Environment %s sets on %s (unredacted, may contain secrets):\n", task.Name, opts.remote)
+	case opts.container != "":
+		fmt.Fprintf(os.Stderr, "🌐 Environment %s sets in the container (unredacted, may contain secrets):\n", task.Name)
+	default:
+		fmt.Fprintf(os.Stderr, "🌐 Environment of %s (%d variables, unredacted, may contain secrets):\n", task.Name, len(env))
+	}
+
+	for _, entry := range env {
+		fmt.Fprintln(w, entry)
+	}
+
+	return nil
+}
+
+// runSingleTask runs one task with the run options applied and records its outcome for the
+// summary. Tasks that exit non-zero are retried as --retries allows, if retry is set.
+func runSingleTask(task *config.Task, projectRoot string, verbose bool, opts runOptions, retry bool) error {
+	taskRunner := newTaskRunner(verbose, projectRoot, opts)
+	if !retry {
+		taskRunner.SetRetryPolicy(runner.RetryPolicy{})
+	}
+
+	// Tasks that --fail-fast keeps from starting have no run to record
+	startable := opts.ctx == nil || opts.ctx.Err() == nil
+
+	timing, err := taskRunner.RunTaskTimed(opts.ctx, task)
+	opts.results.add(task.Name, opts.item, timing, err)
+
+	if startable {
+		recordHistory(task, projectRoot, timing, err, opts.logger)
+	}
+
+	if err != nil && opts.stop != nil {
+		opts.stop(errFailFast)
+	}
+
+	return err
+}
+
+// startDetachedTask starts a task in the background for --detach and prints its PID and log file
+func startDetachedTask(task *config.Task, projectRoot string, verbose bool, opts runOptions) error {
+	timing := runner.TaskTiming{Started: time.Now()}
+
+	detached, err := newTaskRunner(verbose, projectRoot, opts).StartDetached(task)
+	timing.Ended = time.Now()
+	opts.results.add(task.Name, opts.item, timing, err)
+
+	if err != nil {
+		return err
+	}
+
+	if detached.Exited {
+		fmt.Printf("✅ %s finished while starting; output in %s\n", task.Name, detached.Log)
+
+		return nil
+	}
+
+	fmt.Printf("🛰️  Detached %s (PID %d)\n", task.Name, detached.PID)
+	fmt.Printf("   📄 Log: %s\n", detached.Log)
+	fmt.Printf("   Stop it with: taskporter stop %d\n", detached.PID)
+
+	return nil
+}
+
+// recordHistory appends a run to the local history that `taskporter stats` reads. The history
+// is best effort: failing to write it never fails the run.
+func recordHistory(task *config.Task, projectRoot string, timing runner.TaskTiming, err error, logger *slog.Logger) {
+	project, absErr := filepath.Abs(projectRoot)
+	if absErr != nil {
+		return
+	}
+
+	entry := config.HistoryEntry{
+		Project:    project,
+		Task:       task.Name,
+		Source:     relativeSource(projectRoot, task.Source),
+		Started:    timing.Started,
+		DurationMs: timing.Duration().Milliseconds(),
+	}
+
+	if err != nil {
+		entry.ExitCode = runner.ExitCode(err)
+	}
+
+	if writeErr := config.AppendHistory(entry); writeErr != nil {
+		logging.OrDiscard(logger).Debug("failed to record run history", logging.KeyTask, task.Name, "error", writeErr)
+	}
+}
+
+// runDependencies runs the tasks a task dependsOn before the task itself: one after another
+// for dependsOrder "sequence", together otherwise
+func runDependencies(task *config.Task, allTasks []*config.Task, projectConfig *config.ProjectConfig, detector *config.ProjectDetector, verbose bool, opts runOptions) error {
+	dependencies, err := dependencyTasks(task, allTasks)
+	if err != nil {
+		return err
+	}
+
+	sequence := task.DependsOrder == config.DependsOrderSequence
+
+	// A dry run previews the dependencies one after another
+	if opts.dryRun {
+		order := "together"
+		if sequence {
+			order = "in sequence"
+		}
+
+		fmt.Printf("🔍 [DRY RUN] %s [%s] first runs %d tasks %s:\n", task.Name, getTaskSourceDisplay(task), len(dependencies), order)
+
+		for i, dependency := range dependencies {
+			fmt.Printf("   %d. %s\n", i+1, dependency.Name)
+		}
+
+		for _, dependency := range dependencies {
+			fmt.Println()
+
+			if err := executeSelectedTask(dependency, allTasks, projectConfig, detector, verbose, opts); err != nil {
+				return err
+			}
+		}
+
+		fmt.Println()
+
+		return nil
+	}
+
+	if !sequence {
+		taskRunner := newTaskRunner(verbose, projectConfig.ProjectRoot, opts)
+
+		return taskRunner.RunCompound(task, dependencies, nil, func(dependency *config.Task) error {
+			return executeSelectedTask(dependency, allTasks, projectConfig, detector, verbose, opts)
+		})
+	}
+
+	var errs []error
+
+	for _, dependency := range dependencies {
+		if err := opts.ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		if err := executeSelectedTask(dependency, allTasks, projectConfig, detector, verbose, opts); err != nil {
+			if !opts.keepGoing {
+				return err
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runCompoundTask runs a compound's preLaunchTask once, then starts its members together with
+// the delays configured for the compound. Each member runs like a directly selected task.
+func runCompoundTask(task *config.Task, allTasks []*config.Task, projectConfig *config.ProjectConfig, detector *config.ProjectDetector, verbose bool, opts runOptions) error {
+	members, err := dependencyTasks(task, allTasks)
+	if err != nil {
+		return err
+	}
+
+	settings, err := config.LoadCompoundSettings(projectConfig.ProjectRoot, task.Name)
+	if err != nil {
+		return err
+	}
+
+	delays := settings.MemberDelays(members)
+
+	// A dry run previews the members one after another instead of interleaving them
+	if opts.dryRun {
+		fmt.Printf("🔍 [DRY RUN] %s [%s] starts %d tasks together:\n", task.Name, getTaskSourceDisplay(task), len(members))
+
+		for i, member := range members {
+			fmt.Printf("   %d. %s", i+1, member.Name)
+
+			if delays[i] > 0 {
+				fmt.Printf(" (after %s)", delays[i])
+			}
+
+			fmt.Println()
+		}
+
+		for _, member := range members {
+			fmt.Println()
+
+			if err := executeSelectedTask(member, allTasks, projectConfig, detector, verbose, opts); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var errs []error
+
+	// Members sharing a preLaunchTask would each start it, at the same time; every distinct
+	// preLaunchTask, the compound's own first, runs once before the members instead
+	finder := runner.NewTaskFinder()
+	prelaunched := maps.Clone(opts.prelaunched)
+
+	if prelaunched == nil {
+		prelaunched = make(map[string]bool)
+	}
+
+	for _, launch := range append([]*config.Task{task}, members...) {
+		if launch.Type != config.TypeVSCodeLaunch || launch.PreLaunchTask == "" || prelaunched[launch.PreLaunchTask] {
+			continue
+		}
+
+		if err := runPreLaunchTask(launch, allTasks, projectConfig, finder, verbose, opts); err != nil {
+			err = fmt.Errorf("preLaunchTask failed: %w", err)
+			if !opts.keepGoing {
+				return err
+			}
+
+			errs = append(errs, err)
+		}
+
+		prelaunched[launch.PreLaunchTask] = true
+	}
+
+	opts.prelaunched = prelaunched
+	taskRunner := newTaskRunner(verbose, projectConfig.ProjectRoot, opts)
+
+	err = taskRunner.RunCompound(task, members, delays, func(member *config.Task) error {
+		return executeSelectedTask(member, allTasks, projectConfig, detector, verbose, opts)
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("execution failed: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// dependencyTasks resolves the tasks a compound starts or a task dependsOn. Names prefer tasks from
+// the task's own source, since a launch compound lists launch configurations and dependsOn lists tasks.
+func dependencyTasks(task *config.Task, allTasks []*config.Task) ([]*config.Task, error) {
+	var sameSource []*config.Task
+
+	for _, candidate := range allTasks {
+		if candidate.Type == task.Type {
+			sameSource = append(sameSource, candidate)
+		}
+	}
+
+	// Dependencies are run recursively, so a cycle would never stop starting tasks
+	for _, issue := range config.CheckDependencies(sameSource) {
+		if issue.Kind == config.DependencyCycle && slices.Contains(issue.Cycle, task.Name) {
+			return nil, fmt.Errorf("'%s' cannot run: %s", task.Name, issue)
+		}
+	}
+
+	dependencies := make([]*config.Task, 0, len(task.DependsOn))
+
+	for _, name := range task.DependsOn {
+		dependency := config.FindTaskByName(sameSource, name)
+		if dependency == nil {
+			dependency = config.FindTaskByName(allTasks, name)
+		}
+
+		if dependency == nil {
+			return nil, fmt.Errorf("'%s' refers to unknown task '%s'", task.Name, name)
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies, nil
+}
+
+// runPreLaunchTask executes the preLaunchTask of a launch configuration, if it has one
+func runPreLaunchTask(launchTask *config.Task, allTasks []*config.Task, projectConfig *config.ProjectConfig, finder *runner.TaskFinder, verbose bool, opts runOptions) error {
+	preLaunchTaskName := launchTask.PreLaunchTask
+	if launchTask.Type != config.TypeVSCodeLaunch || preLaunchTaskName == "" {
+		return nil
+	}
+
+	if opts.prelaunched[preLaunchTaskName] {
+		if verbose {
+			fmt.Printf("🔗 preLaunchTask %s already ran for the compound\n", preLaunchTaskName)
+		}
 
-	// If no preLaunchTask specified, continue
-	if preLaunchTaskName == "" {
 		return nil
 	}
 
@@ -380,10 +1683,9 @@ func runPreLaunchTask(launchTask *config.Task, allTasks []*config.Task, projectC
 		fmt.Printf("🔗 Launch configuration has preLaunchTask: %s\n", preLaunchTaskName)
 	}
 
-	// Find the preLaunchTask
-	preLaunchTask, err := finder.FindTask(preLaunchTaskName, allTasks)
-	if err != nil {
-		return fmt.Errorf("preLaunchTask '%s' not found: %w", preLaunchTaskName, err)
+	preLaunchTask, err := findPreLaunchTask(launchTask, allTasks, finder, opts)
+	if err != nil || preLaunchTask == nil {
+		return err
 	}
 
 	if verbose {
@@ -391,20 +1693,225 @@ func runPreLaunchTask(launchTask *config.Task, allTasks []*config.Task, projectC
 		fmt.Println()
 	}
 
-	// Execute the preLaunchTask with paranoid mode option
-	taskRunner := runner.NewTaskRunnerWithOptions(verbose, projectConfig.ProjectRoot, paranoidMode)
-	if err := taskRunner.RunTask(preLaunchTask); err != nil {
-		return fmt.Errorf("preLaunchTask '%s' execution failed: %w", preLaunchTaskName, err)
+	// Execute the preLaunchTask with the run options applied; it is only retried with --retry-pre
+	if err := runSingleTask(preLaunchTask, projectConfig.ProjectRoot, verbose, opts, opts.retryPre); err != nil {
+		return fmt.Errorf("preLaunchTask '%s' execution failed: %w", preLaunchTask.Name, err)
 	}
 
 	if verbose {
-		fmt.Printf("✅ PreLaunchTask '%s' completed successfully\n", preLaunchTaskName)
+		fmt.Printf("✅ PreLaunchTask '%s' completed successfully\n", preLaunchTask.Name)
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// findPreLaunchTask finds the task to run before a launch configuration. It returns nil when
+// there is none to run: without a preLaunchTask, with --skip-pre, or when the user skips a
+// misspelt one. A misspelt name fails with the closest task names, unless a human at the
+// terminal accepts running the closest one instead.
+func findPreLaunchTask(launchTask *config.Task, allTasks []*config.Task, finder *runner.TaskFinder, opts runOptions) (*config.Task, error) {
+	name := launchTask.PreLaunchTask
+	if name == "" {
+		return nil, nil
+	}
+
+	if opts.skipPre {
+		fmt.Printf("⏭️  Skipping preLaunchTask '%s' (--skip-pre)\n", name)
+		return nil, nil
+	}
+
+	task, err := finder.FindTask(name, allTasks)
+	if err == nil {
+		return task, nil
+	}
+
+	var notFoundErr *runner.TaskNotFoundError
+	if errors.As(err, &notFoundErr) && notFoundErr.Match != nil && !opts.noInteractive && isInteractiveTerminal() {
+		prompt := fmt.Sprintf("❓ preLaunchTask '%s' not found — run '%s' instead? [Y/n/skip] ", name, notFoundErr.Match.Name)
+
+		answer, ok := promptAnswer(os.Stdout, prompt)
+		switch {
+		case ok && (answer == "" || answer == "y" || answer == "yes"):
+			return notFoundErr.Match, nil
+		case ok && (answer == "s" || answer == "skip"):
+			fmt.Printf("⏭️  Skipping preLaunchTask '%s'\n", name)
+			return nil, nil
+		}
+	}
+
+	return nil, fmt.Errorf("preLaunchTask '%s' not found: %w", name, err)
+}
+
+// previewTask prints what running the task would do, including its environment changes
+func previewTask(task *config.Task, projectRoot string, verbose bool, opts runOptions) error {
+	if err := previewTaskDetails(task, projectRoot, verbose, opts); err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Dry run completed - task was not executed")
+
+	return nil
+}
+
+// previewLaunchChain prints everything running a launch configuration involves, in order: its
+// preLaunchTask, the launch itself and the postDebugTask VSCode runs once the session ends
+func previewLaunchChain(task *config.Task, allTasks []*config.Task, projectRoot string, verbose bool, opts runOptions) error {
+	finder := runner.NewTaskFinder()
+	chain := []string{task.Name}
+
+	preLaunchTask, err := findPreLaunchTask(task, allTasks, finder, opts)
+	if err != nil {
+		return err
+	}
+
+	if preLaunchTask != nil {
+		chain = append([]string{preLaunchTask.Name}, chain...)
+	}
+
+	if task.PostDebugTask != "" {
+		chain = append(chain, task.PostDebugTask)
+	}
+
+	fmt.Printf("🔗 [DRY RUN] %s runs %s\n", task.Name, strings.Join(chain, " → "))
+
+	step := 0
+
+	if preLaunchTask != nil {
+		step++
+
+		fmt.Println()
+		fmt.Printf("%d. preLaunchTask\n", step)
+
+		if err := previewTaskDetails(preLaunchTask, projectRoot, verbose, opts); err != nil {
+			return err
+		}
+	}
+
+	step++
+
+	fmt.Println()
+	fmt.Printf("%d. Launch\n", step)
+
+	if err := previewTaskDetails(task, projectRoot, verbose, opts); err != nil {
+		return err
+	}
+
+	if task.PostDebugTask != "" {
+		step++
+
 		fmt.Println()
+		fmt.Printf("%d. postDebugTask (VSCode runs it when the debug session ends; taskporter run does not)\n", step)
+
+		// It never runs here, so a missing postDebugTask is only pointed out
+		postDebugTask, err := finder.FindTask(task.PostDebugTask, allTasks)
+		if err != nil {
+			fmt.Printf("   ⚠️  %v\n", err)
+		} else if err := previewTaskDetails(postDebugTask, projectRoot, verbose, opts); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Dry run completed - no task was executed")
+
+	return nil
+}
+
+// previewTaskDetails prints the command, working directory and environment changes of a task
+func previewTaskDetails(task *config.Task, projectRoot string, verbose bool, opts runOptions) error {
+	taskRunner := newTaskRunner(verbose, projectRoot, opts)
+
+	diff, err := taskRunner.PreviewEnvironment(task)
+	if err != nil {
+		return fmt.Errorf("failed to build environment for task '%s': %w", task.Name, err)
+	}
+
+	fmt.Printf("🔍 [DRY RUN] %s [%s]\n", task.Name, getTaskSourceDisplay(task))
+
+	for _, line := range runner.TaskCommandSummary(task) {
+		fmt.Printf("   %s\n", line)
+	}
+
+	if opts.remote != "" {
+		fmt.Printf("   Remote: %s (via ssh)\n", opts.remote)
+	}
+
+	if opts.container != "" {
+		fmt.Printf("   Container: %s\n", opts.container)
+	}
+
+	if opts.remote == "" && opts.container == "" {
+		previewWorkingDirectory(task, projectRoot, opts.createCwd)
+	}
+
+	if opts.remote == "" && opts.container == "" {
+		switch taskRunner.EnvStrategy(task) {
+		case runner.EnvStrategyIsolated:
+			fmt.Printf("🧼 Isolated environment: %d variable(s) kept\n", diff.Inherited)
+		case runner.EnvStrategyClean:
+			fmt.Printf("🧼 Clean environment: %d variable(s) besides the task's own\n", diff.Inherited)
+		}
+	}
+
+	if diff.IsEmpty() {
+		fmt.Println("🌐 Environment: inherited unchanged")
+	} else {
+		fmt.Printf("🌐 Environment changes (%d added, %d overridden):\n", len(diff.Added), len(diff.Overridden))
+
+		for _, line := range diff.Lines() {
+			fmt.Printf("   %s\n", line)
+		}
 	}
 
 	return nil
 }
 
+// previewWorkingDirectory flags a working directory the run would fail on, or create with --create-cwd
+func previewWorkingDirectory(task *config.Task, projectRoot string, createCwd bool) {
+	issue := config.CheckWorkingDirectory(task, projectRoot)
+	if issue == nil {
+		return
+	}
+
+	if issue.Kind == config.CwdMissing && createCwd {
+		fmt.Println("   📁 cwd does not exist and will be created (--create-cwd)")
+		return
+	}
+
+	fmt.Printf("   ⚠️  %s\n", issue.Problem())
+}
+
+// newTaskRunner creates a task runner configured from the run options
+func newTaskRunner(verbose bool, projectRoot string, opts runOptions) *runner.TaskRunner {
+	taskRunner := runner.NewTaskRunnerWithOptions(verbose, projectRoot, opts.paranoidMode, opts.logger)
+	taskRunner.SetForceCapture(opts.forceCapture)
+	taskRunner.SetQuietSuccess(opts.quietSuccess)
+	taskRunner.SetRespectProblemMatcher(opts.problems)
+	taskRunner.SetRetryPolicy(runner.RetryPolicy{Retries: opts.retries, Delay: opts.retryDelay})
+	taskRunner.SetExpectedExitCodes(opts.expectedExit)
+
+	if opts.remote != "" {
+		taskRunner.SetRemote(opts.remote, opts.remoteAllow)
+	}
+
+	if opts.container != "" {
+		taskRunner.SetContainer(opts.container, opts.engine)
+	}
+
+	if opts.isolateEnv {
+		taskRunner.SetIsolatedEnv(opts.keepEnv)
+	}
+
+	if opts.cleanEnv {
+		taskRunner.SetCleanEnv(opts.keepEnv)
+	}
+
+	taskRunner.SetCreateCwd(opts.createCwd)
+
+	return taskRunner
+}
+
 // getTaskSourceDisplay returns a display-friendly source name for a task
 func getTaskSourceDisplay(task *config.Task) string {
 	switch task.Type {
@@ -414,7 +1921,72 @@ func getTaskSourceDisplay(task *config.Task) string {
 		return "VSCode Launch"
 	case config.TypeJetBrains:
 		return "JetBrains"
+	case config.TypeSublime:
+		return "Sublime Text"
+	case config.TypeGitHubActions:
+		return "GitHub Actions"
+	case config.TypeGlobal:
+		return "Global"
 	default:
 		return string(task.Type)
 	}
 }
+
+// isInteractiveTerminal reports whether stdin is attached to a terminal
+func isInteractiveTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirmTaskExecution asks on stderr before running a task chosen without the selector.
+// --confirm always asks; opted-in tasks ask only when a human is at the terminal.
+func confirmTaskExecution(task *config.Task, opts runOptions) bool {
+	// Nothing runs in a dry run or a script dump, so there is nothing to confirm
+	if opts.dryRun || opts.dumpScript {
+		return true
+	}
+
+	if !opts.confirm && (!task.RequiresConfirmation() || opts.noInteractive || !isInteractiveTerminal()) {
+		return true
+	}
+
+	fmt.Fprintf(os.Stderr, "🎯 %s [%s]\n", task.Name, getTaskSourceDisplay(task))
+
+	for _, line := range runner.TaskSummary(task) {
+		fmt.Fprintf(os.Stderr, "   %s\n", line)
+	}
+
+	if opts.remote != "" {
+		fmt.Fprintf(os.Stderr, "   Remote: %s (via ssh)\n", opts.remote)
+	}
+
+	if opts.container != "" {
+		fmt.Fprintf(os.Stderr, "   Container: %s\n", opts.container)
+	}
+
+	return confirmPrompt(os.Stderr, "❓ Run this task? [y/N] ")
+}
+
+// confirmPrompt asks a yes/no question on out and reads the answer from stdin
+func confirmPrompt(out io.Writer, prompt string) bool {
+	answer, ok := promptAnswer(out, prompt)
+
+	return ok && (answer == "y" || answer == "yes")
+}
+
+// promptAnswer asks a question on out and reads the answer from stdin, lowercased and trimmed.
+// It returns false when no answer could be read.
+func promptAnswer(out io.Writer, prompt string) (string, bool) {
+	fmt.Fprint(out, prompt)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", false
+	}
+
+	return strings.ToLower(strings.TrimSpace(answer)), true
+}
@@ -0,0 +1,581 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/runner"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteTasks_FailureModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture tasks need a POSIX shell")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root := t.TempDir()
+	projectConfig := &config.ProjectConfig{ProjectRoot: root}
+
+	exiting := func(name string, code int) *config.Task {
+		return &config.Task{Name: name, Type: config.TypeVSCodeTask, Command: "sh", Args: []string{"-c", "exit " + strconv.Itoa(code)}, Cwd: root}
+	}
+
+	tasks := []*config.Task{exiting("ok", 0), exiting("bad", 4), exiting("worse", 7)}
+
+	ranTasks := func(opts runOptions) []string {
+		var names []string
+		for _, result := range opts.results.results {
+			names = append(names, result.name)
+		}
+
+		return names
+	}
+
+	t.Run("should stop at the first failure by default", func(t *testing.T) {
+		opts := runOptions{results: &taskResults{}}
+
+		err := executeTasks(tasks, tasks, projectConfig, nil, false, opts)
+		require.Equal(t, 4, runner.ExitCode(err))
+		require.Equal(t, []string{"ok", "bad"}, ranTasks(opts))
+	})
+
+	t.Run("should run every task with --keep-going and name the failed ones", func(t *testing.T) {
+		opts := runOptions{keepGoing: true, results: &taskResults{}}
+
+		err := executeTasks(tasks, tasks, projectConfig, nil, false, opts)
+		require.EqualError(t, err, "2 of 3 tasks failed: bad (exit 4), worse (exit 7)")
+		require.Equal(t, 7, runner.ExitCode(err))
+		require.Equal(t, []string{"ok", "bad", "worse"}, ranTasks(opts))
+
+		var exitErr *runner.TaskExitError
+		require.ErrorAs(t, err, &exitErr)
+	})
+
+	t.Run("should keep parallel tasks from starting with --fail-fast", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, config.ProjectConfigFile), []byte(`{"compounds": {"all": {"delay": "200ms"}}}`), 0644))
+
+		all := &config.Task{Name: "all", Type: config.TypeVSCodeTask, DependsOn: []string{"bad", "ok"}, Cwd: root}
+		allTasks := append([]*config.Task{all}, tasks...)
+
+		opts := runOptions{failFast: true, results: &taskResults{}}
+
+		err := executeTasks([]*config.Task{all}, allTasks, projectConfig, nil, false, opts)
+		require.Equal(t, 4, runner.ExitCode(err))
+		require.True(t, errors.Is(err, errFailFast))
+		require.ErrorContains(t, err, "task 'ok' was not started: an earlier task failed (--fail-fast)")
+	})
+}
+
+func TestExecuteTasks_DependsOn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture tasks need a POSIX shell")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root := t.TempDir()
+	projectConfig := &config.ProjectConfig{ProjectRoot: root}
+
+	exiting := func(name string, code int, dependsOn ...string) *config.Task {
+		return &config.Task{Name: name, Type: config.TypeVSCodeTask, Command: "sh", Args: []string{"-c", "exit " + strconv.Itoa(code)}, Cwd: root, DependsOn: dependsOn, DependsOrder: config.DependsOrderSequence}
+	}
+
+	ranTasks := func(opts runOptions) []string {
+		var names []string
+		for _, result := range opts.results.results {
+			names = append(names, result.name)
+		}
+
+		return names
+	}
+
+	t.Run("should run the dependsOn chain first", func(t *testing.T) {
+		tasks := []*config.Task{exiting("build", 0, "generate"), exiting("generate", 0, "fetch"), exiting("fetch", 0)}
+		opts := runOptions{results: &taskResults{}}
+
+		require.NoError(t, executeTasks(tasks[:1], tasks, projectConfig, nil, false, opts))
+		require.Equal(t, []string{"fetch", "generate", "build"}, ranTasks(opts))
+	})
+
+	t.Run("should stop at a failing dependency by default", func(t *testing.T) {
+		tasks := []*config.Task{exiting("build", 0, "generate"), exiting("generate", 4)}
+		opts := runOptions{results: &taskResults{}}
+
+		err := executeTasks(tasks[:1], tasks, projectConfig, nil, false, opts)
+		require.Equal(t, 4, runner.ExitCode(err))
+		require.Equal(t, []string{"generate"}, ranTasks(opts))
+	})
+
+	t.Run("should still run the task after a dependency fails with --keep-going", func(t *testing.T) {
+		tasks := []*config.Task{exiting("build", 0, "generate"), exiting("generate", 4)}
+		opts := runOptions{keepGoing: true, results: &taskResults{}}
+
+		err := executeTasks(tasks[:1], tasks, projectConfig, nil, false, opts)
+		require.Equal(t, 4, runner.ExitCode(err))
+		require.Equal(t, []string{"generate", "build"}, ranTasks(opts))
+	})
+
+	t.Run("should start the members of a compound", func(t *testing.T) {
+		all := &config.Task{Name: "all", Type: config.TypeVSCodeTask, DependsOn: []string{"generate"}, Cwd: root}
+		tasks := []*config.Task{all, exiting("generate", 0)}
+		opts := runOptions{results: &taskResults{}}
+
+		require.NoError(t, executeTasks(tasks[:1], tasks, projectConfig, nil, false, opts))
+		require.Equal(t, []string{"generate"}, ranTasks(opts))
+	})
+}
+
+func TestRunCompoundTask_SharedPreLaunchTask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture tasks need a POSIX shell")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root := t.TempDir()
+	projectConfig := &config.ProjectConfig{ProjectRoot: root}
+	builds := filepath.Join(root, "builds.log")
+
+	build := &config.Task{Name: "build", Type: config.TypeVSCodeTask, Command: "sh", Args: []string{"-c", "echo built >> " + builds}, Cwd: root}
+	launch := func(name, preLaunchTask string) *config.Task {
+		return &config.Task{Name: name, Type: config.TypeVSCodeLaunch, Command: "sh", Args: []string{"-c", "exit 0"}, Cwd: root, PreLaunchTask: preLaunchTask}
+	}
+
+	fullStack := &config.Task{Name: "Full Stack", Type: config.TypeVSCodeLaunch, DependsOn: []string{"API", "Worker", "Web"}, PreLaunchTask: "build"}
+	allTasks := []*config.Task{fullStack, launch("API", "build"), launch("Worker", "build"), launch("Web", ""), build}
+
+	t.Run("should run a preLaunchTask shared by members once before starting them", func(t *testing.T) {
+		opts := runOptions{noInteractive: true, results: &taskResults{}}
+
+		require.NoError(t, executeTasks([]*config.Task{fullStack}, allTasks, projectConfig, nil, false, opts))
+
+		data, err := os.ReadFile(builds)
+		require.NoError(t, err)
+		require.Equal(t, "built\n", string(data))
+
+		ran := make([]string, 0, len(opts.results.results))
+		for _, result := range opts.results.results {
+			ran = append(ran, result.name)
+		}
+
+		require.Equal(t, "build", ran[0])
+		require.ElementsMatch(t, []string{"build", "API", "Worker", "Web"}, ran)
+	})
+}
+
+func TestExecuteTasks_Summary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture tasks need a POSIX shell")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root := t.TempDir()
+	projectConfig := &config.ProjectConfig{ProjectRoot: root}
+
+	tasks := []*config.Task{
+		{Name: "lint", Type: config.TypeVSCodeTask, Command: "sh", Args: []string{"-c", "exit 0"}, Cwd: root},
+		{Name: "test", Type: config.TypeVSCodeTask, Command: "sh", Args: []string{"-c", "sleep 0.2; exit 3"}, Cwd: root},
+	}
+
+	t.Run("should print each task's duration and status and write the report", func(t *testing.T) {
+		reportPath := filepath.Join(t.TempDir(), "run.json")
+		opts := runOptions{keepGoing: true, report: reportPath, results: &taskResults{}}
+
+		var err error
+
+		output := captureStdout(t, func() {
+			err = executeTasks(tasks, tasks, projectConfig, nil, false, opts)
+		})
+
+		require.Equal(t, 3, runner.ExitCode(err))
+		require.Contains(t, output, "📊 Summary (2 tasks in ")
+		require.Regexp(t, `✅ lint +\d+\.\d\ds +\d+%`, output)
+		require.Regexp(t, `❌ test +0\.[2-9]\ds +\d+% +exit 3`, output)
+
+		data, readErr := os.ReadFile(reportPath)
+		require.NoError(t, readErr)
+
+		var report runReport
+		require.NoError(t, json.Unmarshal(data, &report))
+
+		require.Equal(t, 1, report.Failed)
+		require.Len(t, report.Tasks, 2)
+		require.Equal(t, "passed", report.Tasks[0].Status)
+		require.Equal(t, "test", report.Tasks[1].Name)
+		require.Equal(t, "failed", report.Tasks[1].Status)
+		require.Equal(t, 3, report.Tasks[1].ExitCode)
+		require.GreaterOrEqual(t, report.Tasks[1].DurationMs, int64(200))
+		require.GreaterOrEqual(t, report.DurationMs, report.Tasks[1].DurationMs)
+	})
+
+	t.Run("should name the item of every --each run", func(t *testing.T) {
+		reportPath := filepath.Join(t.TempDir(), "run.json")
+		each := &config.Task{Name: "check", Type: config.TypeVSCodeTask, Command: "sh", Args: []string{"-c", `test "$0" = b.txt`, "${item}"}, Cwd: root}
+		opts := runOptions{keepGoing: true, each: "files.txt", items: []string{"a.txt", "b.txt"}, report: reportPath, results: &taskResults{}}
+
+		var err error
+
+		output := captureStdout(t, func() {
+			err = executeTasks([]*config.Task{each}, []*config.Task{each}, projectConfig, nil, false, opts)
+		})
+
+		require.Error(t, err)
+		require.Regexp(t, `❌ check \(a\.txt\) +\d+\.\d\ds +\d+% +exit 1`, output)
+		require.Regexp(t, `✅ check \(b\.txt\) +\d+\.\d\ds`, output)
+
+		data, readErr := os.ReadFile(reportPath)
+		require.NoError(t, readErr)
+
+		var report runReport
+		require.NoError(t, json.Unmarshal(data, &report))
+
+		require.Len(t, report.Tasks, 2)
+		require.Equal(t, "check", report.Tasks[0].Name)
+		require.Equal(t, "a.txt", report.Tasks[0].Item)
+		require.Equal(t, "b.txt", report.Tasks[1].Item)
+	})
+
+	t.Run("should not print a summary for a single task", func(t *testing.T) {
+		opts := runOptions{results: &taskResults{}}
+
+		output := captureStdout(t, func() {
+			require.NoError(t, executeTasks(tasks[:1], tasks, projectConfig, nil, false, opts))
+		})
+
+		require.NotContains(t, output, "Summary")
+	})
+}
+
+func TestPreviewLaunchChain(t *testing.T) {
+	root := t.TempDir()
+
+	build := &config.Task{Name: "build", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"build", "./..."}, Cwd: root}
+	down := &config.Task{Name: "db down", Type: config.TypeVSCodeTask, Command: "docker", Args: []string{"compose", "down"}, Cwd: root}
+	launch := &config.Task{Name: "API", Type: config.TypeVSCodeLaunch, Command: "go", Args: []string{"run", "."}, Cwd: root, Env: map[string]string{"PORT": "8080"}, PreLaunchTask: "build", PostDebugTask: "db down"}
+
+	allTasks := []*config.Task{build, down, launch}
+	opts := runOptions{dryRun: true, results: &taskResults{}}
+
+	t.Run("should preview the preLaunchTask, the launch and the postDebugTask in order", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			require.NoError(t, executeSelectedTask(launch, allTasks, &config.ProjectConfig{ProjectRoot: root}, nil, false, opts))
+		})
+
+		require.Contains(t, output, "🔗 [DRY RUN] API runs build → API → db down\n")
+		require.Regexp(t, `(?s)1\. preLaunchTask\n🔍 \[DRY RUN\] build .*2\. Launch\n🔍 \[DRY RUN\] API .*\+ PORT=8080.*3\. postDebugTask \(VSCode runs it when the debug session ends; taskporter run does not\)\n🔍 \[DRY RUN\] db down `, output)
+		require.Contains(t, output, "✅ Dry run completed - no task was executed\n")
+		require.Empty(t, opts.results.results)
+	})
+
+	t.Run("should fail on a missing preLaunchTask like a real run", func(t *testing.T) {
+		missing := &config.Task{Name: "Worker", Type: config.TypeVSCodeLaunch, Command: "go", PreLaunchTask: "generate"}
+
+		captureStdout(t, func() {
+			err := executeSelectedTask(missing, allTasks, &config.ProjectConfig{ProjectRoot: root}, nil, false, opts)
+			require.ErrorContains(t, err, "preLaunchTask 'generate' not found")
+		})
+	})
+
+	t.Run("should name the closest tasks when the preLaunchTask is misspelt", func(t *testing.T) {
+		misspelt := &config.Task{Name: "Worker", Type: config.TypeVSCodeLaunch, Command: "go", PreLaunchTask: "biuld"}
+
+		captureStdout(t, func() {
+			err := executeSelectedTask(misspelt, allTasks, &config.ProjectConfig{ProjectRoot: root}, nil, false, runOptions{dryRun: true, noInteractive: true, results: &taskResults{}})
+			require.EqualError(t, err, "preLaunchTask 'biuld' not found: task 'biuld' not found. Did you mean: build?")
+		})
+	})
+
+	t.Run("should leave the preLaunchTask out with --skip-pre", func(t *testing.T) {
+		misspelt := &config.Task{Name: "Worker", Type: config.TypeVSCodeLaunch, Command: "go", PreLaunchTask: "biuld"}
+
+		output := captureStdout(t, func() {
+			require.NoError(t, executeSelectedTask(misspelt, allTasks, &config.ProjectConfig{ProjectRoot: root}, nil, false, runOptions{dryRun: true, skipPre: true, results: &taskResults{}}))
+		})
+
+		require.Contains(t, output, "⏭️  Skipping preLaunchTask 'biuld' (--skip-pre)\n")
+		require.Contains(t, output, "🔗 [DRY RUN] Worker runs Worker\n")
+		require.NotContains(t, output, "preLaunchTask\n🔍")
+	})
+
+	t.Run("should only point out a missing postDebugTask", func(t *testing.T) {
+		missing := &config.Task{Name: "Worker", Type: config.TypeVSCodeLaunch, Command: "go", PostDebugTask: "cleanup"}
+
+		output := captureStdout(t, func() {
+			require.NoError(t, executeSelectedTask(missing, allTasks, &config.ProjectConfig{ProjectRoot: root}, nil, false, opts))
+		})
+
+		require.Contains(t, output, "🔗 [DRY RUN] Worker runs Worker → cleanup\n")
+		require.Contains(t, output, "   ⚠️  task 'cleanup' not found")
+	})
+}
+
+func TestPrintTaskEnvironment(t *testing.T) {
+	root := t.TempDir()
+
+	t.Setenv("TASKPORTER_TEST_TOKEN", "hunter2")
+
+	task := &config.Task{Name: "deploy", Type: config.TypeVSCodeTask, Command: "./deploy.sh", Cwd: root, Env: map[string]string{"STAGE": "prod"}}
+
+	t.Run("should print every variable unredacted before the dry-run preview", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			require.NoError(t, executeSelectedTask(task, []*config.Task{task}, &config.ProjectConfig{ProjectRoot: root}, nil, false, runOptions{dryRun: true, printEnv: true, results: &taskResults{}}))
+		})
+
+		require.Contains(t, output, "\nSTAGE=prod\n")
+		require.Contains(t, output, "\nTASKPORTER_TEST_TOKEN=hunter2\n")
+		require.Less(t, strings.Index(output, "STAGE=prod"), strings.Index(output, "🔍 [DRY RUN] deploy"))
+	})
+
+	t.Run("should only print the task's variables for container runs", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		require.NoError(t, printTaskEnvironment(&buf, task, root, false, runOptions{container: "golang:1.24"}))
+		require.Equal(t, "STAGE=prod\n", buf.String())
+	})
+}
+
+func TestDumpScript(t *testing.T) {
+	root := t.TempDir()
+
+	generate := &config.Task{Name: "generate", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"generate", "./..."}, Cwd: root}
+	build := &config.Task{Name: "build", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"build", "-o", "bin/my api"}, Cwd: root, DependsOn: []string{"generate"}}
+	launch := &config.Task{Name: "API", Type: config.TypeVSCodeLaunch, Command: "bin/my api", Args: []string{"--name=${item}"}, Cwd: root, Env: map[string]string{"PORT": "8080"}, PreLaunchTask: "build"}
+
+	allTasks := []*config.Task{generate, build, launch}
+	projectConfig := &config.ProjectConfig{ProjectRoot: root}
+	cd := "cd " + root
+
+	t.Run("should print one line chaining dependencies, the preLaunchTask and the task", func(t *testing.T) {
+		opts := runOptions{dumpScript: true, results: &taskResults{}}
+
+		output := captureStdout(t, func() {
+			require.NoError(t, executeTasks([]*config.Task{launch, build}, allTasks, projectConfig, nil, false, opts))
+		})
+
+		require.Equal(t, "("+cd+" && go build -o 'bin/my api') && ("+cd+" && export PORT=8080 && 'bin/my api' '--name=${item}')\n"+
+			"("+cd+" && go generate ./...) && ("+cd+" && go build -o 'bin/my api')\n", output, "like a real run, a preLaunchTask runs without its dependencies")
+		require.Empty(t, opts.results.results, "nothing runs")
+	})
+
+	t.Run("should run the task once per --each item and leave out the preLaunchTask with --skip-pre", func(t *testing.T) {
+		opts := runOptions{dumpScript: true, skipPre: true, each: "-", items: []string{"a", "it's b"}, results: &taskResults{}}
+
+		output := captureStdout(t, func() {
+			require.NoError(t, executeTasks([]*config.Task{launch}, allTasks, projectConfig, nil, false, opts))
+		})
+
+		require.Equal(t, "("+cd+" && export PORT=8080 && 'bin/my api' --name=a) && ("+cd+" && export PORT=8080 && 'bin/my api' '--name=it'\\''s b')\n", output)
+	})
+
+	t.Run("should fail on a missing preLaunchTask like a real run", func(t *testing.T) {
+		missing := &config.Task{Name: "Worker", Type: config.TypeVSCodeLaunch, Command: "go", PreLaunchTask: "biuld"}
+
+		output := captureStdout(t, func() {
+			err := executeTasks([]*config.Task{missing}, allTasks, projectConfig, nil, false, runOptions{dumpScript: true, results: &taskResults{}})
+			require.EqualError(t, err, "preLaunchTask failed: preLaunchTask 'biuld' not found: task 'biuld' not found. Did you mean: build?")
+		})
+		require.Empty(t, output)
+	})
+}
+
+// captureStdout returns what fn prints to standard output
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+
+	fn()
+
+	require.NoError(t, writer.Close())
+
+	return <-output
+}
+
+func TestParseTaskSources(t *testing.T) {
+	source := func(name string, delay time.Duration, err error) taskSource {
+		return taskSource{name: name, parse: func() ([]*config.Task, error) {
+			time.Sleep(delay)
+			return []*config.Task{{Name: name}}, err
+		}}
+	}
+
+	names := func(tasks []*config.Task) []string {
+		var names []string
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+
+		return names
+	}
+
+	t.Run("should list tasks in source order whichever source completes first", func(t *testing.T) {
+		var progress [][]string
+
+		tasks, err := parseTaskSources([]taskSource{
+			source("slow", 50*time.Millisecond, nil),
+			source("fast", 0, nil),
+		}, func(tasks []*config.Task, loaded int) {
+			progress = append(progress, names(tasks))
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"slow", "fast"}, names(tasks))
+		require.Equal(t, [][]string{{"fast"}, {"slow", "fast"}}, progress)
+	})
+
+	t.Run("should fail without waiting for the other sources", func(t *testing.T) {
+		started := time.Now()
+
+		_, err := parseTaskSources([]taskSource{
+			source("hung", time.Minute, nil),
+			source("strict", 0, errors.New("failed to parse VSCode tasks")),
+		}, nil)
+
+		require.EqualError(t, err, "failed to parse VSCode tasks")
+		require.Less(t, time.Since(started), time.Minute)
+	})
+}
+
+func TestPrintTaskListJSON(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".vscode"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".vscode", "tasks.json"), []byte(`{
+    "version": "2.0.0",
+    "tasks": [
+        {"label": "build", "type": "shell", "command": "go", "args": ["build", "./..."], "group": "build", "options": {"cwd": "${workspaceFolder}/cmd"}},
+        {"label": "lint", "type": "shell", "command": "golangci-lint run"}
+    ]
+}`), 0644))
+
+	configPath := filepath.Join(root, ".taskporter.json")
+
+	t.Run("should list every task with all keys present and absolute paths", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, printTaskListJSON(&out, configPath, &logOptions{noGlobal: true}, nil))
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+		require.Len(t, entries, 2)
+
+		tasksPath := filepath.Join(root, ".vscode", "tasks.json")
+
+		require.Equal(t, map[string]any{
+			"name": "build", "type": "vscode-task", "source": tasksPath, "group": "build",
+			"command": "go", "args": []any{"build", "./..."}, "cwd": filepath.Join(root, "cmd"), "tags": []any{},
+		}, entries[0])
+		require.Equal(t, map[string]any{
+			"name": "lint", "type": "vscode-task", "source": tasksPath, "group": "",
+			"command": "golangci-lint run", "args": []any{}, "cwd": root, "tags": []any{},
+		}, entries[1])
+	})
+
+	t.Run("should reject a task name", func(t *testing.T) {
+		var out bytes.Buffer
+
+		require.EqualError(t, printTaskListJSON(&out, configPath, &logOptions{noGlobal: true}, []string{"build"}), "--list does not take a task name")
+		require.Empty(t, out.String())
+	})
+}
+
+func TestTaskCompletion(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".vscode"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".vscode", "tasks.json"), []byte(`{
+    "version": "2.0.0",
+    "tasks": [
+        {"label": "build", "type": "shell", "command": "go build", "group": "build", "detail": "Compile [tags: go,ci]"},
+        {"label": "test", "type": "shell", "command": "go test", "group": "test", "detail": "Unit tests [tags: go]"},
+        {"label": "lint", "type": "shell", "command": "golangci-lint run", "group": "build"}
+    ]
+}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".vscode", "launch.json"), []byte(`{
+    "version": "0.2.0",
+    "configurations": [
+        {"name": "debug", "type": "go", "request": "launch", "program": "${workspaceFolder}"}
+    ]
+}`), 0644))
+
+	// Completion runs from wherever the shell is, so it has to follow --config
+	cmd := &cobra.Command{}
+	cmd.Flags().String("config", filepath.Join(root, ".taskporter.json"), "")
+	cmd.Flags().Bool("no-global", true, "")
+
+	tests := []struct {
+		name     string
+		complete cobra.CompletionFunc
+		expected []string
+	}{
+		{
+			name:     "groups",
+			complete: validTaskGroups,
+			expected: []string{"build", "test", "launch"},
+		},
+		{
+			name:     "tags",
+			complete: validTaskTags,
+			expected: []string{"go", "ci"},
+		},
+		{
+			name:     "sources",
+			complete: validTaskSources,
+			expected: []string{"vscode-task", "vscode-launch"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completions, directive := tt.complete(cmd, nil, "")
+			require.ElementsMatch(t, tt.expected, completions)
+			require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+		})
+	}
+
+	t.Run("task names skip the ones already named", func(t *testing.T) {
+		completions, _ := validTaskNames(cmd, []string{"build"}, "")
+		require.ElementsMatch(t, []string{"test", "lint", "debug"}, completions)
+	})
+}
+
+func TestValidateSelectFrom(t *testing.T) {
+	t.Run("should accept no source and every task type in any case", func(t *testing.T) {
+		require.NoError(t, validateSelectFrom(""))
+		require.NoError(t, validateSelectFrom("vscode-launch"))
+		require.NoError(t, validateSelectFrom("JetBrains"))
+	})
+
+	t.Run("should list the valid sources for an unknown one", func(t *testing.T) {
+		require.EqualError(t, validateSelectFrom("intellij"), "invalid source 'intellij'. Valid options: vscode-task, vscode-launch, jetbrains, sublime-build, github-actions, global")
+	})
+}
@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/converter"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+
+	"github.com/spf13/cobra"
+)
+
+// Outcomes of one configuration in one selftest route
+const (
+	selftestPass    = "pass"    // Every compared field survived the round trip
+	selftestFail    = "fail"    // Some fields changed unexpectedly
+	selftestMissing = "missing" // The configuration did not come back from the target format
+)
+
+// selftestRoute is one conversion the selftest performs and reads back
+type selftestRoute struct {
+	from  string
+	to    string
+	tasks []*config.Task
+	// convert writes tasks to dir, reporting progress to out, and returns them parsed back with
+	// the target format's parser, along with the generated files that could not be parsed back
+	convert func(out io.Writer, tasks []*config.Task, dir string) ([]*config.Task, []string, error)
+	// lossy lists the fields the target format cannot express, so changes to them are expected
+	lossy []string
+}
+
+// selftestResult is the outcome of one configuration in one route
+type selftestResult struct {
+	Task   string            `json:"task"`
+	Source string            `json:"source"`
+	Status string            `json:"status"`
+	Diffs  []config.TaskDiff `json:"diffs,omitempty"`
+}
+
+// selftestRouteReport is the outcome of one route
+type selftestRouteReport struct {
+	Route      string           `json:"route"`
+	Results    []selftestResult `json:"results"`
+	Unreadable []string         `json:"unreadable,omitempty"` // Generated files the opposite parser rejected, with the reason
+	Error      string           `json:"error,omitempty"`      // Set when the conversion failed as a whole
+}
+
+// selftestReport is the full matrix, also written as JSON for attaching to issues
+type selftestReport struct {
+	Version string                `json:"version"`
+	Routes  []selftestRouteReport `json:"routes"`
+}
+
+// problems counts the configurations that did not survive and the routes that failed
+func (r selftestReport) problems() int {
+	count := 0
+
+	for _, route := range r.Routes {
+		if route.Error != "" {
+			count++
+		}
+
+		for _, result := range route.Results {
+			if result.Status != selftestPass {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+func NewSelftestCommand(verbose *bool, outputFormat *string, configPath *string, logOpts *logOptions) *cobra.Command {
+	selftestCmd := &cobra.Command{
+		Use:    "selftest",
+		Short:  "Round-trip this project's configurations through every converter",
+		Hidden: true,
+		Long: `Parse every configuration in the current project, convert it with every
+supported converter into a temporary directory, parse the generated files back with
+the opposite parser and report which configurations survived the round trip.
+
+Real outputs are never touched. Fields the target format cannot express are
+expected to change and are not reported; any other difference fails the
+configuration. Use --output json to attach the report to an issue.
+
+Checking every strand end to end...`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runSelftestCommand(*verbose, *outputFormat, *configPath, cmd.Root().Version, logOpts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	return selftestCmd
+}
+
+func runSelftestCommand(verbose bool, outputFormat string, configPath string, version string, logOpts *logOptions) error {
+	logger, err := logOpts.newLogger(os.Stderr, verbose)
+	if err != nil {
+		return err
+	}
+
+	// Determine project root
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	detector := logOpts.newProjectDetector(projectRoot)
+
+	projectConfig, err := detector.DetectProject()
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "taskporter-selftest-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	report := selftestReport{Version: version, Routes: []selftestRouteReport{}}
+
+	for _, route := range selftestRoutes(detector, projectConfig.ProjectRoot, logger) {
+		if len(route.tasks) == 0 {
+			continue
+		}
+
+		name := route.from + " → " + route.to
+
+		if verbose && outputFormat != "json" {
+			fmt.Printf("🔄 %s (%d configurations)\n", name, len(route.tasks))
+		}
+
+		dir := filepath.Join(tmpDir, route.from+"-to-"+route.to)
+
+		routeReport := selftestRouteReport{Route: name, Results: []selftestResult{}}
+
+		// Converters report progress, which would drown the matrix
+		parsed, unreadable, err := route.convert(io.Discard, route.tasks, dir)
+		routeReport.Unreadable = unreadable
+
+		if err != nil {
+			routeReport.Error = err.Error()
+		} else {
+			routeReport.Results = compareRoundTrip(route.tasks, parsed, route.lossy)
+		}
+
+		report.Routes = append(report.Routes, routeReport)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		displaySelftestText(report)
+	}
+
+	if problems := report.problems(); problems > 0 {
+		return fmt.Errorf("selftest found %d problems", problems)
+	}
+
+	return nil
+}
+
+// selftestRoutes parses the project's configurations and pairs them with every conversion that
+// can be read back. Makefiles and shell scripts cannot be parsed, so they are not round-tripped.
+func selftestRoutes(detector *config.ProjectDetector, projectRoot string, logger *slog.Logger) []selftestRoute {
+	var vscodeTasks, launchTasks, jetbrainsTasks []*config.Task
+
+	if tasksPath := detector.GetVSCodeTasksPath(); tasksPath != "" {
+		tasks, err := vscode.NewTasksParser(projectRoot, logger).ParseTasks(tasksPath)
+		if err != nil {
+			logger.Warn("failed to parse VSCode tasks", logging.KeyFile, tasksPath, "error", err)
+		}
+
+		vscodeTasks = tasks
+	}
+
+	if launchPath := detector.GetVSCodeLaunchPath(); launchPath != "" {
+		tasks, err := vscode.NewLaunchParser(projectRoot, logger).ParseLaunchConfigs(launchPath)
+		if err != nil {
+			logger.Warn("failed to parse VSCode launch configs", logging.KeyFile, launchPath, "error", err)
+		}
+
+		launchTasks = tasks
+	}
+
+	jetbrainsParser := newJetBrainsParser(detector.Context(), projectRoot, logger)
+
+	for _, path := range detector.GetJetBrainsRunConfigPaths() {
+		task, err := jetbrainsParser.ParseRunConfiguration(path)
+		if err != nil {
+			logJetBrainsParseError(logger, path, err)
+			continue
+		}
+
+		jetbrainsTasks = append(jetbrainsTasks, task)
+	}
+
+	// Reads back every run configuration a JetBrains converter wrote to dir
+	parseJetBrainsDir := func(dir string) ([]*config.Task, []string, error) {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var (
+			tasks      []*config.Task
+			unreadable []string
+		)
+
+		for _, path := range paths {
+			task, err := jetbrainsParser.ParseRunConfiguration(path)
+			if err != nil {
+				// The parser's error repeats the temporary path, the file name is enough
+				if cause := errors.Unwrap(err); cause != nil {
+					err = cause
+				}
+
+				unreadable = append(unreadable, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+
+				continue
+			}
+
+			tasks = append(tasks, task)
+		}
+
+		return tasks, unreadable, nil
+	}
+
+	return []selftestRoute{
+		{
+			from:  "vscode-tasks",
+			to:    "jetbrains",
+			tasks: vscodeTasks,
+			convert: func(out io.Writer, tasks []*config.Task, dir string) ([]*config.Task, []string, error) {
+				conv := converter.NewVSCodeToJetBrainsConverter(projectRoot, dir, false, logger)
+				conv.SetOutput(out)
+
+				if err := conv.ConvertTasks(tasks, false); err != nil {
+					return nil, nil, err
+				}
+
+				return parseJetBrainsDir(dir)
+			},
+			// Run configurations have no groups, problem matchers or consoles, and before-run tasks
+			// replace dependsOn
+			lossy: []string{config.FieldGroup, config.FieldDescription, config.FieldShell, config.FieldConsole, config.FieldInteractive,
+				config.FieldConfirm, config.FieldDependsOn, config.FieldDependsOrder, config.FieldProblemPatterns},
+		},
+		{
+			from:  "vscode-launch",
+			to:    "jetbrains",
+			tasks: launchTasks,
+			convert: func(out io.Writer, tasks []*config.Task, dir string) ([]*config.Task, []string, error) {
+				conv := converter.NewVSCodeLaunchToJetBrainsConverter(projectRoot, dir, false, logger)
+				conv.SetOutput(out)
+
+				if err := conv.ConvertLaunchConfigs(tasks, false); err != nil {
+					return nil, nil, err
+				}
+
+				return parseJetBrainsDir(dir)
+			},
+			lossy: []string{config.FieldGroup, config.FieldDescription, config.FieldConsole, config.FieldInteractive, config.FieldConfirm},
+		},
+		{
+			from:  "jetbrains",
+			to:    "vscode-tasks",
+			tasks: jetbrainsTasks,
+			convert: func(out io.Writer, tasks []*config.Task, dir string) ([]*config.Task, []string, error) {
+				outputPath := filepath.Join(dir, "tasks.json")
+
+				conv := converter.NewJetBrainsToVSCodeConverter(projectRoot, outputPath, false, logger)
+				conv.SetOutput(out)
+
+				if err := conv.ConvertTasks(tasks, false); err != nil {
+					return nil, nil, err
+				}
+
+				parsed, err := vscode.NewTasksParser(projectRoot, logger).ParseTasks(outputPath)
+
+				return parsed, nil, err
+			},
+			// Tasks get a group guessed from their name, and IDE runtimes become plain commands
+			lossy: []string{config.FieldGroup, config.FieldDescription, config.FieldShell, config.FieldRuntimePath, config.FieldConsole,
+				config.FieldInteractive, config.FieldDependsOn, config.FieldDependsOrder},
+		},
+		{
+			from:  "jetbrains",
+			to:    "vscode-launch",
+			tasks: jetbrainsTasks,
+			convert: func(out io.Writer, tasks []*config.Task, dir string) ([]*config.Task, []string, error) {
+				outputPath := filepath.Join(dir, "launch.json")
+
+				conv := converter.NewJetBrainsToVSCodeLaunchConverter(projectRoot, outputPath, false, logger)
+				conv.SetOutput(out)
+
+				if err := conv.ConvertToLaunch(tasks, false); err != nil {
+					return nil, nil, err
+				}
+
+				parsed, err := vscode.NewLaunchParser(projectRoot, logger).ParseLaunchConfigs(outputPath)
+
+				return parsed, nil, err
+			},
+			lossy: []string{config.FieldGroup, config.FieldDescription, config.FieldShell, config.FieldConsole, config.FieldInteractive,
+				config.FieldDependsOn, config.FieldDependsOrder},
+		},
+	}
+}
+
+// compareRoundTrip matches every original configuration with the one parsed back under the same name
+func compareRoundTrip(original []*config.Task, parsed []*config.Task, lossy []string) []selftestResult {
+	results := make([]selftestResult, 0, len(original))
+
+	for _, task := range original {
+		result := selftestResult{Task: task.Name, Source: task.Source, Status: selftestPass}
+
+		if back := config.FindTaskByName(parsed, task.Name); back == nil {
+			result.Status = selftestMissing
+		} else if result.Diffs = config.DiffTasks(task, back, lossy...); len(result.Diffs) > 0 {
+			result.Status = selftestFail
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func displaySelftestText(report selftestReport) {
+	fmt.Println("🧪 Round-trip selftest:")
+	fmt.Println()
+
+	if len(report.Routes) == 0 {
+		fmt.Println("No convertible configurations found.")
+		return
+	}
+
+	for _, route := range report.Routes {
+		fmt.Printf("🔄 %s\n", route.Route)
+
+		if route.Error != "" {
+			fmt.Printf("  ❌ conversion failed: %s\n", route.Error)
+		}
+
+		for _, result := range route.Results {
+			switch result.Status {
+			case selftestPass:
+				fmt.Printf("  ✅ %s\n", result.Task)
+			case selftestMissing:
+				fmt.Printf("  ❌ %s - missing after the round trip\n", result.Task)
+			default:
+				fmt.Printf("  ❌ %s\n", result.Task)
+
+				for _, diff := range result.Diffs {
+					fmt.Printf("     • %s\n", diff)
+				}
+			}
+		}
+
+		for _, unreadable := range route.Unreadable {
+			fmt.Printf("  ⚠️  could not read back %s\n", unreadable)
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println("📡 Attach the output of 'taskporter selftest --output json' when reporting a conversion problem.")
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/converter"
+	"github.com/syndbg/taskporter/internal/parser/jetbrains"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestProjectRootWithSpecialCharacters runs list, run and port end to end in a project whose root
+// contains spaces and parentheses, the way resolved ${workspaceFolder} paths reach command lines
+func TestProjectRootWithSpecialCharacters(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture script needs a POSIX shell")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root := filepath.Join(t.TempDir(), "My Projects", "app (fork)")
+	workDir := filepath.Join(root, "sub dir")
+
+	writeFile := func(path, content string, perm os.FileMode) {
+		t.Helper()
+
+		path = filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), perm))
+	}
+
+	writeFile(".vscode/settings.json", `{
+    "terminal.integrated.shell.linux": "/bin/sh",
+    "terminal.integrated.shell.osx": "/bin/sh"
+}`, 0644)
+
+	script := filepath.Join(root, "scripts", "greet.sh")
+	output := filepath.Join(root, "out", "greeting (1).txt")
+
+	// Absolute paths are embedded the way configurations generated for this checkout carry them
+	tasksJSON, err := json.Marshal(map[string]interface{}{
+		"version": "2.0.0",
+		"tasks": []map[string]interface{}{
+			{
+				"label":   "greet",
+				"type":    "shell",
+				"command": script,
+				"args":    []string{output},
+				"options": map[string]string{"cwd": "${workspaceFolder}/sub dir"},
+			},
+			{
+				"label":   "java app",
+				"type":    "process",
+				"command": "java",
+				"args":    []string{"com.example.Main", filepath.Join(root, "data (1).txt")},
+				"options": map[string]string{"cwd": "${workspaceFolder}/sub dir"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	writeFile(".vscode/tasks.json", string(tasksJSON), 0644)
+	writeFile(".vscode/launch.json", `{
+    "version": "0.2.0",
+    "configurations": [
+        {"name": "server", "type": "node", "request": "launch", "program": "${workspaceFolder}/src (v2)/server.js"}
+    ]
+}`, 0644)
+	writeFile("scripts/greet.sh", "#!/bin/sh\npwd > \"$1\"\n", 0755)
+	require.NoError(t, os.MkdirAll(workDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "out"), 0755))
+
+	configPath := filepath.Join(root, ".taskporter.json")
+	logOpts := &logOptions{noGlobal: true}
+
+	// requireGreeting checks the greet task wrote its working directory to its output file
+	requireGreeting := func(t *testing.T) {
+		t.Helper()
+
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+
+		expected, err := filepath.EvalSymlinks(workDir)
+		require.NoError(t, err)
+
+		actual, err := filepath.EvalSymlinks(string(content[:len(content)-1]))
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+		require.NoError(t, os.Remove(output))
+	}
+
+	t.Run("list resolves paths as single words", func(t *testing.T) {
+		tasks, err := getAllTasksQuiet(root, logOpts)
+		require.NoError(t, err)
+		require.Len(t, tasks, 3)
+
+		require.Equal(t, script, tasks[0].Command)
+		require.Equal(t, []string{output}, tasks[0].Args)
+		require.Equal(t, workDir, tasks[0].Cwd)
+		require.Equal(t, "/bin/sh", tasks[0].Shell)
+
+		require.Equal(t, []string{filepath.Join(root, "src (v2)", "server.js")}, tasks[2].Args)
+	})
+
+	t.Run("run quotes the script and its arguments for the shell", func(t *testing.T) {
+		err := runTaskCommand([]string{"greet"}, false, configPath, logOpts, runOptions{noInteractive: true})
+		require.NoError(t, err)
+
+		requireGreeting(t)
+	})
+
+	t.Run("port to jetbrains keeps paths intact", func(t *testing.T) {
+		err := runPortCommand(false, configPath, logOpts, portOptions{from: "vscode-tasks", to: "jetbrains", shell: converter.ShellBash, force: true})
+		require.NoError(t, err)
+
+		outputDir := filepath.Join(root, ".idea", "runConfigurations")
+
+		// The generated shell script text runs like the task does
+		data, err := os.ReadFile(filepath.Join(outputDir, "greet.xml"))
+		require.NoError(t, err)
+
+		var component converter.JetBrainsComponent
+		require.NoError(t, xml.Unmarshal(data, &component))
+
+		var scriptText string
+
+		for _, option := range component.Configuration.Options {
+			if option.Name == "SCRIPT_TEXT" {
+				scriptText = option.Value
+			}
+		}
+
+		command := exec.Command("/bin/sh", "-c", scriptText)
+		command.Dir = workDir
+		require.NoError(t, command.Run(), scriptText)
+
+		requireGreeting(t)
+
+		// Parameters and the working directory survive a round trip
+		parser := jetbrains.NewRunConfigurationParser(root, nil)
+
+		task, err := parser.ParseRunConfiguration(filepath.Join(outputDir, "java_app.xml"))
+		require.NoError(t, err)
+		require.Equal(t, workDir, task.Cwd)
+		require.Contains(t, task.Args, filepath.Join(root, "data (1).txt"))
+	})
+
+	t.Run("port to makefile quotes the script and its arguments", func(t *testing.T) {
+		if _, err := exec.LookPath("make"); err != nil {
+			t.Skip("make is not installed")
+		}
+
+		makefile := filepath.Join(root, "Makefile")
+
+		err := runPortCommand(false, configPath, logOpts, portOptions{from: "vscode-tasks", to: "makefile", output: makefile, shell: converter.ShellBash, force: true})
+		require.NoError(t, err)
+
+		out, err := exec.Command("make", "-f", makefile, "-C", root, "greet").CombinedOutput()
+		require.NoError(t, err, string(out))
+
+		requireGreeting(t)
+	})
+}
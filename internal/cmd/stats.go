@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+func NewStatsCommand(verbose *bool, outputFormat *string, configPath *string, logOpts *logOptions) *cobra.Command {
+	var after string
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show how often tasks run, how long they take and how often they fail",
+		Long: `Summarize the project's tasks and their local run history: tasks per source
+file, the most frequently run tasks with their average duration and failure rate,
+tasks that are defined but never ran, and runs of tasks that have since been
+renamed or deleted.
+
+Every 'taskporter run' appends to ~/.config/taskporter/history.jsonl. The
+report is computed from that file alone; nothing is sent anywhere.
+
+Examples:
+  # Report on the whole history
+  taskporter stats
+
+  # Only the last week, as JSON for a dashboard
+  taskporter stats --after 7d --output json
+
+Counting the deliveries along the strand...`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runStatsCommand(*verbose, *outputFormat, *configPath, after, logOpts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	statsCmd.Flags().StringVar(&after, "after", "", "only count runs after this time (RFC 3339 or a duration ago such as 12h or 7d)")
+
+	return statsCmd
+}
+
+func runStatsCommand(verbose bool, outputFormat string, configPath string, after string, logOpts *logOptions) error {
+	if _, err := logOpts.newLogger(os.Stderr, verbose); err != nil {
+		return err
+	}
+
+	var since time.Time
+
+	if after != "" {
+		var err error
+		if since, err = config.ParseChangedSince(after, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// Determine project root
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	projectConfig, err := logOpts.newProjectDetector(projectRoot).DetectProject()
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	tasks, err := getAllTasksQuiet(projectConfig.ProjectRoot, logOpts)
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	project, err := filepath.Abs(projectConfig.ProjectRoot)
+	if err != nil {
+		return err
+	}
+
+	entries, err := config.ReadHistory(project, since)
+	if err != nil {
+		return err
+	}
+
+	stats := config.ComputeUsageStats(project, tasks, entries)
+
+	for i := range stats.Sources {
+		stats.Sources[i].Source = relativeSource(projectConfig.ProjectRoot, stats.Sources[i].Source)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(stats)
+	}
+
+	displayStatsText(stats, len(entries))
+
+	return nil
+}
+
+// displayStatsText prints the report as tables, one section per kind of statistic
+func displayStatsText(stats *config.UsageStats, runs int) {
+	fmt.Println("📦 Tasks per source:")
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, source := range stats.Sources {
+		fmt.Fprintf(writer, "   %s\t%d\n", source.Source, source.Tasks)
+	}
+
+	writer.Flush()
+
+	fmt.Println()
+
+	if runs == 0 {
+		fmt.Println("📊 No runs recorded yet. 'taskporter run' records every run it starts.")
+	} else {
+		fmt.Printf("📊 Runs (%d recorded):\n", runs)
+		printTaskStatsTable(stats.Tasks)
+	}
+
+	if len(stats.Removed) > 0 {
+		fmt.Println()
+		fmt.Println("🗑️  Removed tasks (renamed or deleted since they ran):")
+		printTaskStatsTable(stats.Removed)
+	}
+
+	if len(stats.NeverRun) > 0 {
+		fmt.Println()
+		fmt.Printf("💤 Never run (%d):\n", len(stats.NeverRun))
+
+		for _, name := range stats.NeverRun {
+			fmt.Printf("   %s\n", name)
+		}
+	}
+}
+
+// printTaskStatsTable prints one row per task with its run count, average duration and failure rate
+func printTaskStatsTable(tasks []config.TaskStats) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "   TASK\tRUNS\tAVG\tFAILED\tLAST RUN")
+
+	for _, task := range tasks {
+		fmt.Fprintf(writer, "   %s\t%d\t%s\t%.0f%%\t%s\n",
+			task.Name, task.Runs, task.AverageDuration().Round(time.Millisecond), task.FailureRate()*100, task.LastRun.Local().Format("2006-01-02 15:04"))
+	}
+
+	writer.Flush()
+}
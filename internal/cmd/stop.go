@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func NewStopCommand(verbose *bool, configPath *string, logOpts *logOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop <task|pid>",
+		Short: "Stop a detached task",
+		Long: `Stop a task started with 'taskporter run --detach', named by task or PID.
+Every running detached instance of a named task is stopped.
+
+The task's process group gets SIGTERM so servers can shut down cleanly, and is
+killed if it is still running 10 seconds later. On Windows the task is killed
+right away.
+
+Examples:
+  taskporter stop "Run API server"
+  taskporter stop 48213
+
+Recalling the porter...`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: validDetachedTasks,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runStopCommand(args[0], *verbose, *configPath, logOpts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runStopCommand(target string, verbose bool, configPath string, logOpts *logOptions) error {
+	taskRunner, _, err := newDetachedTaskRunner(verbose, configPath, logOpts)
+	if err != nil {
+		return err
+	}
+
+	stopped, err := taskRunner.StopDetached(target)
+
+	for _, detached := range stopped {
+		fmt.Printf("🛑 Stopped %s (PID %d)\n", detached.Task, detached.PID)
+	}
+
+	return err
+}
+
+// validDetachedTasks completes the names of running detached tasks
+func validDetachedTasks(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+
+	logOpts := discoveryFlags(cmd)
+	logOpts.level = "error"
+
+	taskRunner, _, err := newDetachedTaskRunner(false, configPath, logOpts)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	running, err := taskRunner.ListDetached()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+
+	for _, detached := range running {
+		names = append(names, detached.Task)
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
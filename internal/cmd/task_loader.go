@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+)
+
+// taskSource parses the tasks of one kind of configuration file
+type taskSource struct {
+	name  string
+	parse func() ([]*config.Task, error)
+}
+
+// taskSourceOptions controls how projectTaskSources parses
+type taskSourceOptions struct {
+	verbose  bool
+	strict   bool // A tasks.json that fails to parse fails the load instead of being skipped
+	progress bool // Show the JetBrains scan spinner
+
+	launchFailures *launchFileFailures // The invocation's launch.json failures, see logOptions
+}
+
+// projectTaskSources returns a source for every kind of configuration the project has, in
+// the order their tasks are listed. Nothing is parsed until a source's parse is called.
+func projectTaskSources(detector *config.ProjectDetector, projectConfig *config.ProjectConfig, opts taskSourceOptions, logger *slog.Logger) []taskSource {
+	projectRoot := projectConfig.ProjectRoot
+
+	var sources []taskSource
+
+	if projectConfig.HasVSCode {
+		// Tasks and launch configurations both resolve variables from settings.json
+		settings := sync.OnceValue(func() *vscode.VSCodeSettings {
+			return loadVSCodeSettings(detector, projectRoot, opts.verbose, logger)
+		})
+
+		if tasksPath := detector.GetVSCodeTasksPath(); tasksPath != "" {
+			sources = append(sources, taskSource{name: "VSCode tasks", parse: func() ([]*config.Task, error) {
+				if opts.verbose {
+					fmt.Printf("📋 Scanning VSCode tasks from: %s\n", tasksPath)
+				}
+
+				parser := vscode.NewTasksParserWithSettings(projectRoot, settings(), logger)
+				parser.SetStrict(opts.strict)
+				parser.SetContext(detector.Context())
+
+				tasks, err := parser.ParseTasks(tasksPath)
+				if err != nil {
+					if opts.strict {
+						return nil, fmt.Errorf("failed to parse VSCode tasks: %w", err)
+					}
+
+					logger.Warn("failed to parse VSCode tasks", logging.KeyFile, tasksPath, "error", err)
+				}
+
+				return tasks, nil
+			}})
+		}
+
+		if launchPath := detector.GetVSCodeLaunchPath(); launchPath != "" {
+			sources = append(sources, taskSource{name: "VSCode launch configurations", parse: func() ([]*config.Task, error) {
+				if opts.verbose {
+					fmt.Printf("🚀 Scanning VSCode launch configs from: %s\n", launchPath)
+				}
+
+				launchParser := vscode.NewLaunchParserWithSettings(projectRoot, settings(), logger)
+				launchParser.SetContext(detector.Context())
+
+				launchTasks, err := opts.launchFailures.parse(launchParser, launchPath, logger)
+				if err == nil && len(launchTasks) == 0 {
+					logger.Debug("no launch configurations", logging.KeyFile, launchPath)
+				}
+
+				return launchTasks, nil
+			}})
+		}
+	}
+
+	if projectConfig.HasJetBrains {
+		sources = append(sources, taskSource{name: "JetBrains run configurations", parse: func() ([]*config.Task, error) {
+			jetbrainsPaths := detector.GetJetBrainsRunConfigPaths()
+			if opts.verbose && len(jetbrainsPaths) > 0 {
+				fmt.Printf("🧠 Scanning JetBrains configurations from: %d files\n", len(jetbrainsPaths))
+			}
+
+			parser := newJetBrainsParser(detector.Context(), projectRoot, logger)
+
+			return parseJetBrainsRunConfigs(parser, jetbrainsPaths, opts.progress, logger), nil
+		}})
+	}
+
+	if projectConfig.HasSublime {
+		sources = append(sources, taskSource{name: "Sublime Text build systems", parse: func() ([]*config.Task, error) {
+			return parseSublimeProjects(detector, projectRoot, opts.verbose, logger), nil
+		}})
+	}
+
+	if projectConfig.HasGitHubActions {
+		sources = append(sources, taskSource{name: "GitHub Actions steps", parse: func() ([]*config.Task, error) {
+			return parseGitHubWorkflows(detector, projectRoot, opts.verbose, logger), nil
+		}})
+	}
+
+	return sources
+}
+
+// parseTaskSources parses every source in a goroutine of its own and returns their tasks in
+// source order, so a slow source (e.g. on a network filesystem) doesn't hold up the others.
+// onProgress, if set, is called with the tasks of the sources parsed so far, in source order,
+// each time one completes.
+func parseTaskSources(sources []taskSource, onProgress func(tasks []*config.Task, loaded int)) ([]*config.Task, error) {
+	type result struct {
+		index int
+		tasks []*config.Task
+		err   error
+	}
+
+	// Buffered, so sources still running after an error don't block forever
+	done := make(chan result, len(sources))
+
+	for i, source := range sources {
+		go func() {
+			tasks, err := source.parse()
+			done <- result{index: i, tasks: tasks, err: err}
+		}()
+	}
+
+	parsed := make([][]*config.Task, len(sources))
+
+	for loaded := 1; loaded <= len(sources); loaded++ {
+		r := <-done
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		parsed[r.index] = r.tasks
+
+		if onProgress != nil {
+			onProgress(slices.Concat(parsed...), loaded)
+		}
+	}
+
+	return slices.Concat(parsed...), nil
+}
+
+// launchFileFailure is a launch.json that failed to parse earlier in this invocation
+type launchFileFailure struct {
+	err    error
+	warned atomic.Bool
+}
+
+// launchFileFailures maps launch.json paths to their *launchFileFailure, so code paths loading
+// tasks again in the same invocation skip the file instead of reading it and warning about it
+// once more. The zero value is ready to use.
+type launchFileFailures struct {
+	files sync.Map
+}
+
+// parse parses a launch.json, warning about one that fails to parse only the first time a
+// logger that shows warnings sees it. Empty files parse as no configurations.
+func (f *launchFileFailures) parse(parser *vscode.LaunchParser, launchPath string, logger *slog.Logger) ([]*config.Task, error) {
+	value, failed := f.files.Load(launchPath)
+	if !failed {
+		launchTasks, err := parser.ParseLaunchConfigs(launchPath)
+		if err == nil {
+			return launchTasks, nil
+		}
+
+		value, _ = f.files.LoadOrStore(launchPath, &launchFileFailure{err: err})
+	}
+
+	failure := value.(*launchFileFailure)
+
+	// Quiet loaders (completion, machine-readable output) must not use up the one warning
+	if logger.Enabled(context.Background(), slog.LevelWarn) && failure.warned.CompareAndSwap(false, true) {
+		logger.Warn("failed to parse VSCode launch configs", logging.KeyFile, launchPath, "error", failure.err)
+	}
+
+	return nil, failure.err
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/converter"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaunchFileFailures(t *testing.T) {
+	t.Run("should warn about a broken launch.json once per invocation", func(t *testing.T) {
+		launchPath := filepath.Join(t.TempDir(), "launch.json")
+		require.NoError(t, os.WriteFile(launchPath, []byte(`{"configurations": [`), 0644))
+
+		recorder, logger := logging.NewRecorder()
+		parser := vscode.NewLaunchParser(filepath.Dir(launchPath), nil)
+
+		var failures launchFileFailures
+
+		// A quiet loader first, as completion does, must not use up the warning
+		_, err := failures.parse(parser, launchPath, logging.Discard())
+		require.Error(t, err)
+
+		for range 3 {
+			tasks, err := failures.parse(parser, launchPath, logger)
+			require.Error(t, err)
+			require.Empty(t, tasks)
+		}
+
+		entries := recorder.Entries()
+		require.Len(t, entries, 1)
+		require.Equal(t, "failed to parse VSCode launch configs", entries[0].Message)
+	})
+
+	t.Run("should warn again in another invocation", func(t *testing.T) {
+		launchPath := filepath.Join(t.TempDir(), "launch.json")
+		require.NoError(t, os.WriteFile(launchPath, []byte(`{"configurations": [`), 0644))
+
+		recorder, logger := logging.NewRecorder()
+		parser := vscode.NewLaunchParser(filepath.Dir(launchPath), nil)
+
+		for range 2 {
+			var failures launchFileFailures
+
+			_, err := failures.parse(parser, launchPath, logger)
+			require.Error(t, err)
+		}
+
+		require.Len(t, recorder.Entries(), 2)
+	})
+
+	t.Run("should parse an empty launch.json quietly", func(t *testing.T) {
+		launchPath := filepath.Join(t.TempDir(), "launch.json")
+		require.NoError(t, os.WriteFile(launchPath, nil, 0644))
+
+		recorder, logger := logging.NewRecorder()
+
+		var failures launchFileFailures
+
+		tasks, err := failures.parse(vscode.NewLaunchParser(filepath.Dir(launchPath), nil), launchPath, logger)
+		require.NoError(t, err)
+		require.Empty(t, tasks)
+		require.Empty(t, recorder.Entries())
+	})
+}
+
+func TestPortNothingToConvert(t *testing.T) {
+	t.Run("should succeed and flag the report when launch.json has no configurations", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(projectRoot, ".vscode"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".vscode", "launch.json"), []byte(`{"version": "0.2.0", "configurations": []}`), 0644))
+
+		reportPath := filepath.Join(t.TempDir(), "report.json")
+		configPath := filepath.Join(projectRoot, ".taskporter.json")
+
+		err := runPortCommand(false, configPath, &logOptions{}, portOptions{from: "vscode-launch", to: "jetbrains", dryRun: true, shell: converter.ShellBash, report: reportPath})
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(reportPath)
+		require.NoError(t, err)
+
+		var report converter.Report
+		require.NoError(t, json.Unmarshal(data, &report))
+		require.True(t, report.NothingToConvert)
+		require.Empty(t, report.Entries)
+	})
+}
+
+func TestPortMinFidelity(t *testing.T) {
+	// newProject has a task every target keeps whole and one whose group JetBrains has no place for
+	newProject := func(t *testing.T) string {
+		t.Helper()
+
+		projectRoot := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(projectRoot, ".vscode"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".vscode", "tasks.json"), []byte(`{
+			"version": "2.0.0",
+			"tasks": [
+				{"label": "lint", "type": "shell", "command": "golangci-lint", "args": ["run"]},
+				{"label": "build", "type": "shell", "command": "go", "args": ["build", "./..."], "group": "build"}
+			]
+		}`), 0644))
+
+		return projectRoot
+	}
+
+	port := func(projectRoot, minFidelity string, force bool, reportPath string) error {
+		return runPortCommand(false, filepath.Join(projectRoot, ".taskporter.json"), &logOptions{}, portOptions{from: "vscode-tasks", to: "jetbrains", shell: converter.ShellBash, force: force, report: reportPath, minFidelity: minFidelity})
+	}
+
+	t.Run("should write nothing when a configuration falls below the level", func(t *testing.T) {
+		projectRoot := newProject(t)
+		reportPath := filepath.Join(t.TempDir(), "report.json")
+
+		err := port(projectRoot, "full", false, reportPath)
+		require.EqualError(t, err, "1 configuration(s) below --min-fidelity full: build; nothing was written (--force writes them anyway)")
+		require.NoDirExists(t, filepath.Join(projectRoot, ".idea"))
+
+		data, err := os.ReadFile(reportPath)
+		require.NoError(t, err)
+
+		var report converter.Report
+		require.NoError(t, json.Unmarshal(data, &report))
+		require.True(t, report.DryRun)
+		require.Len(t, report.Entries, 2)
+		require.Equal(t, converter.FidelityFull, report.Entries[0].Fidelity)
+		require.Equal(t, converter.FidelityPartial, report.Entries[1].Fidelity)
+		require.Equal(t, []string{"group"}, report.Entries[1].DroppedFields)
+	})
+
+	t.Run("should port when every configuration meets the level", func(t *testing.T) {
+		projectRoot := newProject(t)
+
+		require.NoError(t, port(projectRoot, "partial", false, ""))
+		require.FileExists(t, filepath.Join(projectRoot, ".idea", "runConfigurations", "build.xml"))
+	})
+
+	t.Run("should write with --force and still fail", func(t *testing.T) {
+		projectRoot := newProject(t)
+
+		err := port(projectRoot, "full", true, "")
+		require.EqualError(t, err, "1 configuration(s) below --min-fidelity full: build")
+		require.FileExists(t, filepath.Join(projectRoot, ".idea", "runConfigurations", "build.xml"))
+	})
+
+	t.Run("should grade launch.json keys no target carries as dropped", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(projectRoot, ".vscode"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".vscode", "launch.json"), []byte(`{
+			"version": "0.2.0",
+			"configurations": [
+				{"name": "serve", "type": "go", "request": "launch", "program": "${workspaceFolder}", "buildFlags": "-tags=dev", "envFile": "${workspaceFolder}/.env", "showLog": true, "stopOnEntry": true}
+			]
+		}`), 0644))
+
+		err := runPortCommand(false, filepath.Join(projectRoot, ".taskporter.json"), &logOptions{}, portOptions{from: "vscode-launch", to: "jetbrains", shell: converter.ShellBash, minFidelity: "full"})
+		require.EqualError(t, err, "1 configuration(s) below --min-fidelity full: serve; nothing was written (--force writes them anyway)")
+		require.NoDirExists(t, filepath.Join(projectRoot, ".idea"))
+	})
+
+	t.Run("should reject unknown levels", func(t *testing.T) {
+		err := port(newProject(t), "approximate", false, "")
+		require.EqualError(t, err, "invalid fidelity level 'approximate'. Valid options: partial, full")
+	})
+}
+
+func TestPortRespectGitignore(t *testing.T) {
+	projectRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectRoot, ".vscode"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".vscode", "tasks.json"), []byte(`{"version": "2.0.0", "tasks": [{"label": "lint", "type": "shell", "command": "golangci-lint"}]}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".gitignore"), []byte(".vscode/\n"), 0644))
+
+	port := func(logOpts *logOptions) error {
+		return runPortCommand(false, filepath.Join(projectRoot, ".taskporter.json"), logOpts, portOptions{from: "vscode-tasks", to: "jetbrains", dryRun: true, shell: converter.ShellBash})
+	}
+
+	t.Run("should port configurations .gitignore matches by default", func(t *testing.T) {
+		require.NoError(t, port(&logOptions{}))
+	})
+
+	t.Run("should leave them out with --respect-gitignore", func(t *testing.T) {
+		require.EqualError(t, port(&logOptions{gitignore: true}), "no VSCode configuration found in project")
+	})
+}
+
+func TestPortTargetOS(t *testing.T) {
+	projectRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectRoot, ".vscode"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".vscode", "tasks.json"), []byte(`{"version": "2.0.0", "tasks": [{"label": "build", "type": "shell", "command": "make", "windows": {"command": "nmake"}}]}`), 0644))
+
+	port := func(targetOS string) string {
+		outputDir := t.TempDir()
+		require.NoError(t, runPortCommand(false, filepath.Join(projectRoot, ".taskporter.json"), &logOptions{}, portOptions{from: "vscode-tasks", to: "jetbrains", output: outputDir, shell: converter.ShellBash, targetOS: targetOS}))
+
+		data, err := os.ReadFile(filepath.Join(outputDir, "build.xml"))
+		require.NoError(t, err)
+
+		return string(data)
+	}
+
+	t.Run("should apply the tasks.json block of --target-os", func(t *testing.T) {
+		require.Contains(t, port("windows"), "nmake")
+	})
+
+	t.Run("should leave out the blocks of other platforms", func(t *testing.T) {
+		require.NotContains(t, port("linux"), "nmake")
+	})
+}
+
+func TestJetBrainsTemplates(t *testing.T) {
+	projectRoot := filepath.Join("..", "test", "jetbrains-testdata")
+	runConfigsDir := filepath.Join(projectRoot, ".idea", "runConfigurations")
+
+	t.Run("should leave default=\"true\" templates out of the runnable tasks quietly", func(t *testing.T) {
+		paths, err := filepath.Glob(filepath.Join(runConfigsDir, "*.xml"))
+		require.NoError(t, err)
+		require.Contains(t, paths, filepath.Join(runConfigsDir, "Python_Template.xml"))
+
+		recorder, logger := logging.NewRecorder()
+		tasks := parseJetBrainsRunConfigs(newJetBrainsParser(context.Background(), projectRoot, logger), paths, false, logger)
+
+		var names []string
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+
+		require.ElementsMatch(t, []string{"Application", "Gradle Build", "Kotlin App", "service-api:bootRun", "Spring Boot App"}, names)
+
+		for _, entry := range recorder.Entries() {
+			require.Less(t, entry.Level, slog.LevelWarn, entry.Message)
+		}
+	})
+
+	t.Run("should keep templates for port --apply-templates", func(t *testing.T) {
+		templates, err := config.LoadRunConfigTemplates(context.Background(), projectRoot)
+		require.NoError(t, err)
+		require.Contains(t, templates, "PythonConfigurationType")
+		require.Equal(t, []config.TemplateValue{{Name: "PYTHONUNBUFFERED", Value: "1"}}, templates["PythonConfigurationType"].EnvVars)
+	})
+}
+
+func TestParseJetBrainsRunConfigs(t *testing.T) {
+	t.Run("should skip unsupported types quietly and warn about broken files", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		runConfigsDir := filepath.Join(projectRoot, ".idea", "runConfigurations")
+		require.NoError(t, os.MkdirAll(runConfigsDir, 0755))
+
+		junit := filepath.Join(runConfigsDir, "Tests.xml")
+		require.NoError(t, os.WriteFile(junit, []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration name="Tests" type="JUnit" factoryName="JUnit" />
+</component>`), 0644))
+
+		broken := filepath.Join(runConfigsDir, "Broken.xml")
+		require.NoError(t, os.WriteFile(broken, []byte(`<component name="ProjectRunConfigurationManager">`), 0644))
+
+		recorder, logger := logging.NewRecorder()
+		tasks := parseJetBrainsRunConfigs(newJetBrainsParser(context.Background(), projectRoot, logger), []string{junit, broken}, false, logger)
+		require.Empty(t, tasks)
+
+		levels := make(map[string]slog.Level)
+		for _, entry := range recorder.Entries() {
+			levels[entry.Attrs[logging.KeyFile]] = entry.Level
+		}
+
+		require.Equal(t, slog.LevelDebug, levels[junit])
+		require.Equal(t, slog.LevelWarn, levels[broken])
+	})
+}
+
+func TestTaskExtensions(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	projectRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectRoot, ".vscode"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".vscode", "tasks.json"),
+		[]byte(`{"version": "2.0.0", "tasks": [{"label": "test", "type": "shell", "command": "go", "args": ["test"]}]}`), 0644))
+
+	writeExtensions := func(t *testing.T, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(projectRoot, config.ProjectConfigFile), []byte(content), 0644))
+	}
+
+	t.Run("should skip extensions of unknown tasks", func(t *testing.T) {
+		writeExtensions(t, `{"tasks": {"race": {"extends": "test", "args": ["-race"]}, "lost": {"extends": "missing"}}}`)
+
+		tasks, err := getAllTasksQuiet(projectRoot, &logOptions{})
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		require.Equal(t, "race", tasks[1].Name)
+	})
+
+	t.Run("should fail on extends cycles", func(t *testing.T) {
+		writeExtensions(t, `{"tasks": {"race": {"extends": "test"}, "a": {"extends": "b"}, "b": {"extends": "a"}}}`)
+
+		_, err := getAllTasksQuiet(projectRoot, &logOptions{})
+		require.ErrorContains(t, err, "extends cycle a → b → a")
+
+		var cycleErr *config.ExtendsCycleError
+		require.ErrorAs(t, err, &cycleErr)
+	})
+}
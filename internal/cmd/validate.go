@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/converter"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+
+	"github.com/spf13/cobra"
+)
+
+func NewValidateCommand(verbose *bool, outputFormat *string, configPath *string, logOpts *logOptions) *cobra.Command {
+	var fix bool
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check task configurations for broken dependency chains",
+		Long: `Statically check task configurations without running anything.
+
+Reports dependsOn entries that name no known task and dependency cycles
+that would otherwise only show up when a developer runs the task, plus
+aliases that name no known task or are shadowed by a real task.
+Exits with a non-zero status when problems are found, so it can gate CI.
+
+Working directories that do not exist, are not directories, or (for JetBrains
+configurations) resolve outside the project are reported as warnings: build output
+directories are often missing on a fresh checkout. Tasks extending another in
+.taskporter.json with "createCwd": true are not reported, and 'run --create-cwd'
+creates any of them.
+
+Use --fix to remove trailing commas from tasks.json and launch.json first, so
+strict JSON tools can read them too. Comments are kept.
+
+Inspecting the strands before anyone walks them...`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runValidateCommand(*verbose, *outputFormat, *configPath, fix, logOpts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	validateCmd.Flags().BoolVar(&fix, "fix", false, "remove trailing commas from tasks.json and launch.json (comments are kept)")
+
+	return validateCmd
+}
+
+func runValidateCommand(verbose bool, outputFormat string, configPath string, fix bool, logOpts *logOptions) error {
+	logger, err := logOpts.newLogger(os.Stderr, verbose)
+	if err != nil {
+		return err
+	}
+
+	// Determine project root
+	projectRoot := "."
+	if configPath != "" {
+		projectRoot = filepath.Dir(configPath)
+	}
+
+	detector := logOpts.newProjectDetector(projectRoot)
+
+	projectConfig, err := detector.DetectProject()
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	if fix {
+		for _, path := range []string{detector.GetVSCodeTasksPath(), detector.GetVSCodeLaunchPath()} {
+			if path == "" {
+				continue
+			}
+
+			if err := fixTrailingCommas(path, outputFormat != "json"); err != nil {
+				return err
+			}
+		}
+	}
+
+	// dependsOn only exists in VSCode tasks, and only refers to tasks from the same file
+	var tasks []*config.Task
+
+	if tasksPath := detector.GetVSCodeTasksPath(); tasksPath != "" {
+		if verbose {
+			fmt.Printf("📋 Validating VSCode tasks from: %s\n", tasksPath)
+		}
+
+		settings := loadVSCodeSettings(detector, projectConfig.ProjectRoot, verbose, logger)
+
+		parser := vscode.NewTasksParserWithSettings(projectConfig.ProjectRoot, settings, logger)
+		parser.SetStrict(logOpts.strict)
+		parser.SetContext(detector.Context())
+
+		tasks, err = parser.ParseTasks(tasksPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse VSCode tasks: %w", err)
+		}
+	}
+
+	issues := config.CheckDependencies(tasks)
+
+	// Aliases may name any task, including global ones the user keeps in every project
+	aliasIssues, err := checkAliases(projectConfig.ProjectRoot, logOpts)
+	if err != nil {
+		return err
+	}
+
+	// Every task can point at a missing directory, not only the VSCode tasks dependsOn is checked for
+	allTasks, err := getAllTasksQuiet(projectConfig.ProjectRoot, logOpts)
+	if err != nil {
+		return fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	cwdIssues := config.CheckWorkingDirectories(allTasks, projectConfig.ProjectRoot)
+
+	if outputFormat == "json" {
+		if err := displayValidationJSON(tasks, issues, aliasIssues, cwdIssues); err != nil {
+			return err
+		}
+	} else {
+		displayValidationText(tasks, issues, aliasIssues)
+		displayCwdWarnings(cwdIssues)
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d dependency problems", len(issues))
+	}
+
+	if len(aliasIssues) > 0 {
+		return fmt.Errorf("found %d alias problems", len(aliasIssues))
+	}
+
+	return nil
+}
+
+// checkAliases reports aliases that cannot be used against every task in the project
+func checkAliases(projectRoot string, logOpts *logOptions) ([]config.AliasIssue, error) {
+	aliases, err := config.LoadAliases(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+
+	allTasks, err := getAllTasksQuiet(projectRoot, logOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect project configuration: %w", err)
+	}
+
+	return config.CheckAliases(aliases, allTasks), nil
+}
+
+// fixTrailingCommas rewrites path without the trailing commas VSCode tolerates but strict JSON rejects
+func fixTrailingCommas(path string, report bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	fixed, removed, err := vscode.RemoveTrailingCommas(data)
+	if err != nil {
+		return fmt.Errorf("failed to fix %s: %w", path, err)
+	}
+
+	if removed == 0 {
+		return nil
+	}
+
+	if err := converter.WriteFileAtomic(path, fixed, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if report {
+		fmt.Printf("🔧 Removed %d trailing commas from %s\n", removed, path)
+	}
+
+	return nil
+}
+
+func displayValidationText(tasks []*config.Task, issues []config.DependencyIssue, aliasIssues []config.AliasIssue) {
+	if len(issues) == 0 {
+		fmt.Printf("✅ No dependency problems found in %d tasks\n", len(tasks))
+	} else {
+		fmt.Printf("❌ Found %d dependency problems:\n", len(issues))
+
+		for _, issue := range issues {
+			fmt.Printf("  • %s\n", issue)
+
+			if issue.Source != "" {
+				fmt.Printf("    in %s\n", issue.Source)
+			}
+		}
+
+		fmt.Println()
+		fmt.Println("📡 Strand broken... fix the dependsOn entries above before running these tasks.")
+	}
+
+	if len(aliasIssues) > 0 {
+		if len(issues) > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("❌ Found %d alias problems:\n", len(aliasIssues))
+
+		for _, issue := range aliasIssues {
+			fmt.Printf("  • %s\n", issue)
+			fmt.Printf("    in %s\n", issue.Source)
+		}
+	}
+}
+
+// displayCwdWarnings lists the working directories runs of the tasks would fail on
+func displayCwdWarnings(cwdIssues []config.CwdIssue) {
+	if len(cwdIssues) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("⚠️  Found %d working directory warnings:\n", len(cwdIssues))
+
+	for _, issue := range cwdIssues {
+		fmt.Printf("  • %s\n", issue)
+
+		if issue.Source != "" {
+			fmt.Printf("    in %s\n", issue.Source)
+		}
+	}
+}
+
+func displayValidationJSON(tasks []*config.Task, issues []config.DependencyIssue, aliasIssues []config.AliasIssue, cwdIssues []config.CwdIssue) error {
+	if issues == nil {
+		issues = []config.DependencyIssue{}
+	}
+
+	if aliasIssues == nil {
+		aliasIssues = []config.AliasIssue{}
+	}
+
+	if cwdIssues == nil {
+		cwdIssues = []config.CwdIssue{}
+	}
+
+	output := map[string]interface{}{
+		"tasks":       len(tasks),
+		"issues":      issues,
+		"count":       len(issues),
+		"aliasIssues": aliasIssues,
+		"cwdWarnings": cwdIssues,
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(output)
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProjectConfigFile is taskporter's own per-project configuration, at the project root
+const ProjectConfigFile = ".taskporter.json"
+
+// Kinds of alias problems found by CheckAliases
+const (
+	AliasDangling = "dangling" // The alias names no known task
+	AliasShadowed = "shadowed" // A real task has the alias's name, so the alias is never used
+)
+
+// Alias is a short, user-defined name for a task
+type Alias struct {
+	Name   string `json:"name"`
+	Target string `json:"target"` // Real task name the alias expands to
+	Source string `json:"source"` // Configuration file the alias was defined in
+}
+
+// AliasIssue describes an alias that cannot be used
+type AliasIssue struct {
+	Kind   string `json:"kind"` // AliasDangling or AliasShadowed
+	Alias  string `json:"alias"`
+	Target string `json:"target"`
+	Source string `json:"source"`
+}
+
+// String describes the issue in one line
+func (i AliasIssue) String() string {
+	if i.Kind == AliasShadowed {
+		return fmt.Sprintf("alias %q is shadowed by a task with the same name and never expands to %q", i.Alias, i.Target)
+	}
+
+	return fmt.Sprintf("alias %q refers to unknown task %q", i.Alias, i.Target)
+}
+
+// settingsFile is the structure of .taskporter.json and the user-level config.json
+type settingsFile struct {
+	Aliases   map[string]string           `json:"aliases"`
+	Compounds map[string]CompoundSettings `json:"compounds"`
+	Tasks     map[string]TaskExtension    `json:"tasks"`
+	Tags      map[string][]string         `json:"tags"`
+}
+
+// loadedSettings is a settings file together with where it was read from
+type loadedSettings struct {
+	path     string
+	settings settingsFile
+}
+
+// UserConfigPath returns the user-level configuration file, $XDG_CONFIG_HOME/taskporter/config.json
+// or ~/.config/taskporter/config.json
+func UserConfigPath() (string, error) {
+	return userConfigFile("config.json")
+}
+
+// readSettings reads the user-level config.json and the project's .taskporter.json, in that
+// order, so later files win. Missing files are skipped.
+func readSettings(projectRoot string) ([]loadedSettings, error) {
+	paths := []string{filepath.Join(projectRoot, ProjectConfigFile)}
+
+	if userPath, err := UserConfigPath(); err == nil {
+		paths = append([]string{userPath}, paths...)
+	}
+
+	var loaded []loadedSettings
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		var settings settingsFile
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		loaded = append(loaded, loadedSettings{path: path, settings: settings})
+	}
+
+	return loaded, nil
+}
+
+// LoadAliases reads the aliases from the user-level config.json and the project's .taskporter.json.
+// Project aliases win over user aliases with the same name. Missing files are not an error.
+// Aliases are returned sorted by name.
+func LoadAliases(projectRoot string) ([]Alias, error) {
+	loaded, err := readSettings(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]Alias)
+
+	for _, file := range loaded {
+		for name, target := range file.settings.Aliases {
+			byName[strings.ToLower(name)] = Alias{Name: name, Target: target, Source: file.path}
+		}
+	}
+
+	aliases := make([]Alias, 0, len(byName))
+	for _, alias := range byName {
+		aliases = append(aliases, alias)
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return strings.ToLower(aliases[i].Name) < strings.ToLower(aliases[j].Name)
+	})
+
+	return aliases, nil
+}
+
+// CheckAliases reports aliases that name no known task and aliases shadowed by a real task.
+// Names are compared case-insensitively, as the task finder does.
+func CheckAliases(aliases []Alias, tasks []*Task) []AliasIssue {
+	names := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		names[strings.ToLower(task.Name)] = true
+	}
+
+	var issues []AliasIssue
+
+	for _, alias := range aliases {
+		switch {
+		case names[strings.ToLower(alias.Name)]:
+			issues = append(issues, AliasIssue{Kind: AliasShadowed, Alias: alias.Name, Target: alias.Target, Source: alias.Source})
+		case !names[strings.ToLower(alias.Target)]:
+			issues = append(issues, AliasIssue{Kind: AliasDangling, Alias: alias.Name, Target: alias.Target, Source: alias.Source})
+		}
+	}
+
+	return issues
+}
+
+// ApplyAliases records on each task the usable aliases that expand to it, for display
+func ApplyAliases(tasks []*Task, aliases []Alias) {
+	shadowed := make(map[string]bool)
+	for _, issue := range CheckAliases(aliases, tasks) {
+		shadowed[strings.ToLower(issue.Alias)] = true
+	}
+
+	for _, alias := range aliases {
+		if shadowed[strings.ToLower(alias.Name)] {
+			continue
+		}
+
+		if task := FindTaskByName(tasks, alias.Target); task != nil {
+			task.Aliases = append(task.Aliases, alias.Name)
+		}
+	}
+}
+
+// FindTaskByName returns the first task named name, preferring an exact match over a
+// case-insensitive one, or nil if there is none
+func FindTaskByName(tasks []*Task, name string) *Task {
+	for _, task := range tasks {
+		if task.Name == name {
+			return task
+		}
+	}
+
+	for _, task := range tasks {
+		if strings.EqualFold(task.Name, name) {
+			return task
+		}
+	}
+
+	return nil
+}
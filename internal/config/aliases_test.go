@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAliases(t *testing.T) {
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	t.Run("should merge user and project aliases with the project winning", func(t *testing.T) {
+		configHome := t.TempDir()
+		projectRoot := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+
+		userPath := filepath.Join(configHome, "taskporter", "config.json")
+		projectPath := filepath.Join(projectRoot, ProjectConfigFile)
+
+		writeFile(t, userPath, `{"aliases": {"api": "user api", "up": "docker compose up"}}`)
+		writeFile(t, projectPath, `{"aliases": {"API": "Run API server (staging profile, port 8081)"}}`)
+
+		aliases, err := LoadAliases(projectRoot)
+		require.NoError(t, err)
+		require.Equal(t, []Alias{
+			{Name: "API", Target: "Run API server (staging profile, port 8081)", Source: projectPath},
+			{Name: "up", Target: "docker compose up", Source: userPath},
+		}, aliases)
+	})
+
+	t.Run("should ignore missing files", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		aliases, err := LoadAliases(t.TempDir())
+		require.NoError(t, err)
+		require.Empty(t, aliases)
+	})
+
+	t.Run("should fail on invalid JSON", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		projectRoot := t.TempDir()
+		writeFile(t, filepath.Join(projectRoot, ProjectConfigFile), `{"aliases": `)
+
+		_, err := LoadAliases(projectRoot)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), ProjectConfigFile)
+	})
+}
+
+func TestCheckAliases(t *testing.T) {
+	tasks := []*Task{
+		{Name: "Run API server (staging profile, port 8081)", Type: TypeJetBrains},
+		{Name: "test", Type: TypeVSCodeTask},
+	}
+
+	aliases := []Alias{
+		{Name: "api", Target: "run api server (staging profile, port 8081)", Source: ProjectConfigFile},
+		{Name: "Test", Target: "Run API server (staging profile, port 8081)", Source: ProjectConfigFile},
+		{Name: "web", Target: "Run web", Source: ProjectConfigFile},
+	}
+
+	t.Run("should report shadowed and dangling aliases", func(t *testing.T) {
+		issues := CheckAliases(aliases, tasks)
+		require.Equal(t, []AliasIssue{
+			{Kind: AliasShadowed, Alias: "Test", Target: "Run API server (staging profile, port 8081)", Source: ProjectConfigFile},
+			{Kind: AliasDangling, Alias: "web", Target: "Run web", Source: ProjectConfigFile},
+		}, issues)
+		require.Equal(t, `alias "web" refers to unknown task "Run web"`, issues[1].String())
+	})
+
+	t.Run("should attach only usable aliases to their tasks", func(t *testing.T) {
+		ApplyAliases(tasks, aliases)
+		require.Equal(t, []string{"api"}, tasks[0].Aliases)
+		require.Empty(t, tasks[1].Aliases)
+	})
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TaskDiff is a task field whose value differs between two versions of a task
+type TaskDiff struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// String describes the difference in one line
+func (d TaskDiff) String() string {
+	return fmt.Sprintf("%s: %q → %q", d.Field, d.Before, d.After)
+}
+
+// Names of the fields compared by DiffTasks
+const (
+	FieldName            = "name"
+	FieldCommandLine     = "commandLine" // Command words and args together, since formats split them differently
+	FieldCwd             = "cwd"
+	FieldEnv             = "env"
+	FieldGroup           = "group"
+	FieldDescription     = "description"
+	FieldTags            = "tags"
+	FieldShell           = "shell"
+	FieldRuntimePath     = "runtimePath"
+	FieldConsole         = "console"
+	FieldInteractive     = "interactive"
+	FieldConfirm         = "confirm"
+	FieldDependsOn       = "dependsOn"
+	FieldDependsOrder    = "dependsOrder"
+	FieldProblemPatterns = "problemPatterns"
+)
+
+// taskFields renders each compared field of a task as a string, in display order
+var taskFields = []struct {
+	name  string
+	value func(task *Task) string
+}{
+	{FieldName, func(task *Task) string { return task.Name }},
+	{FieldCommandLine, func(task *Task) string { return formatWords(append(strings.Fields(task.Command), task.Args...)) }},
+	{FieldCwd, func(task *Task) string { return task.Cwd }},
+	{FieldEnv, func(task *Task) string { return formatEnv(task.Env) }},
+	{FieldGroup, func(task *Task) string { return task.Group }},
+	{FieldDescription, func(task *Task) string { return task.Description }},
+	{FieldTags, func(task *Task) string { return strings.Join(task.Tags, ",") }},
+	{FieldShell, func(task *Task) string { return task.Shell }},
+	{FieldRuntimePath, func(task *Task) string { return task.RuntimePath }},
+	{FieldConsole, func(task *Task) string { return task.Console }},
+	{FieldInteractive, func(task *Task) string { return fmt.Sprint(task.Interactive) }},
+	{FieldConfirm, func(task *Task) string { return fmt.Sprint(task.Confirm) }},
+	{FieldDependsOn, func(task *Task) string { return strings.Join(task.DependsOn, ",") }},
+	{FieldDependsOrder, func(task *Task) string { return task.DependsOrder }},
+	{FieldProblemPatterns, func(task *Task) string { return strings.Join(task.ProblemPatterns, "\n") }},
+}
+
+// DiffTasks compares the portable fields of two tasks, skipping the fields named in ignore.
+// Type, Source, Aliases and Passthrough describe where a task came from rather than what it
+// runs, so they are never compared.
+func DiffTasks(before, after *Task, ignore ...string) []TaskDiff {
+	skip := make(map[string]bool, len(ignore))
+	for _, field := range ignore {
+		skip[field] = true
+	}
+
+	var diffs []TaskDiff
+
+	for _, field := range taskFields {
+		if skip[field.name] {
+			continue
+		}
+
+		if b, a := field.value(before), field.value(after); b != a {
+			diffs = append(diffs, TaskDiff{Field: field.name, Before: b, After: a})
+		}
+	}
+
+	return diffs
+}
+
+// DiffTaskFields compares only the named fields of two tasks, in display order
+func DiffTaskFields(before, after *Task, fields ...string) []TaskDiff {
+	var diffs []TaskDiff
+
+	for _, field := range taskFields {
+		if !slices.Contains(fields, field.name) {
+			continue
+		}
+
+		if b, a := field.value(before), field.value(after); b != a {
+			diffs = append(diffs, TaskDiff{Field: field.name, Before: b, After: a})
+		}
+	}
+
+	return diffs
+}
+
+// formatWords renders a command line word by word, quoting the words that are empty or hold
+// whitespace, so ["a b"] and ["a", "b"] don't compare equal
+func formatWords(words []string) string {
+	formatted := make([]string, len(words))
+	for i, word := range words {
+		formatted[i] = word
+		if word == "" || strings.ContainsFunc(word, unicode.IsSpace) {
+			formatted[i] = strconv.Quote(word)
+		}
+	}
+
+	return strings.Join(formatted, " ")
+}
+
+// formatEnv renders env as sorted KEY=value pairs
+func formatEnv(env map[string]string) string {
+	pairs := make([]string, 0, len(env))
+	for key, value := range env {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
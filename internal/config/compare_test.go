@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTasks(t *testing.T) {
+	original := &Task{
+		Name:    "build",
+		Type:    TypeVSCodeTask,
+		Command: "go build",
+		Args:    []string{"./..."},
+		Env:     map[string]string{"B": "2", "A": "1"},
+		Group:   "build",
+		Source:  "/project/.vscode/tasks.json",
+	}
+
+	t.Run("should ignore where the task came from and how the command is split", func(t *testing.T) {
+		ported := &Task{
+			Name:    "build",
+			Type:    TypeJetBrains,
+			Command: "go",
+			Args:    []string{"build", "./..."},
+			Env:     map[string]string{"A": "1", "B": "2"},
+			Group:   "build",
+			Source:  "/tmp/build.xml",
+		}
+
+		require.Empty(t, DiffTasks(original, ported))
+	})
+
+	t.Run("should report changed fields", func(t *testing.T) {
+		ported := &Task{Name: "build", Command: "go", Args: []string{"build"}, Env: map[string]string{"A": "1"}}
+
+		require.Equal(t, []TaskDiff{
+			{Field: FieldCommandLine, Before: "go build ./...", After: "go build"},
+			{Field: FieldEnv, Before: "A=1,B=2", After: "A=1"},
+			{Field: FieldGroup, Before: "build", After: ""},
+		}, DiffTasks(original, ported))
+	})
+
+	t.Run("should tell args apart that split differently", func(t *testing.T) {
+		joined := &Task{Name: "commit", Command: "git", Args: []string{"commit", "-m", "fix bug"}}
+		split := &Task{Name: "commit", Command: "git", Args: []string{"commit", "-m", "fix", "bug"}}
+
+		require.Equal(t, []TaskDiff{
+			{Field: FieldCommandLine, Before: `git commit -m "fix bug"`, After: "git commit -m fix bug"},
+		}, DiffTasks(joined, split))
+	})
+
+	t.Run("should skip fields the target cannot express", func(t *testing.T) {
+		ported := &Task{Name: "build", Command: "go build ./...", Env: map[string]string{"A": "1", "B": "2"}}
+
+		require.Empty(t, DiffTasks(original, ported, FieldGroup))
+	})
+
+	t.Run("should describe a difference in one line", func(t *testing.T) {
+		diff := TaskDiff{Field: FieldCwd, Before: "$PROJECT_DIR$", After: "/project"}
+		require.Equal(t, `cwd: "$PROJECT_DIR$" → "/project"`, diff.String())
+	})
+}
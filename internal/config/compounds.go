@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration written as a Go duration string ("500ms", "2s") in settings files
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string like \"2s\": %w", err)
+	}
+
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+
+	if parsed < 0 {
+		return fmt.Errorf("duration %q must not be negative", text)
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// CompoundSettings controls how the members of a compound task are started, from the
+// "compounds" object in .taskporter.json or the user-level config.json, keyed by compound name:
+//
+//	{"compounds": {"Full Stack": {"delay": "2s", "delays": {"Frontend": "5s"}}}}
+type CompoundSettings struct {
+	Delay  Duration            `json:"delay,omitempty"`  // Wait before starting each member after the first
+	Delays map[string]Duration `json:"delays,omitempty"` // Wait before starting the named member, overriding Delay
+}
+
+// DelayBefore returns how long to wait before starting the member at index
+func (s CompoundSettings) DelayBefore(member string, index int) time.Duration {
+	for name, delay := range s.Delays {
+		if strings.EqualFold(name, member) {
+			return time.Duration(delay)
+		}
+	}
+
+	if index == 0 {
+		return 0
+	}
+
+	return time.Duration(s.Delay)
+}
+
+// MemberDelays returns the wait before starting each member of a compound, in order
+func (s CompoundSettings) MemberDelays(members []*Task) []time.Duration {
+	delays := make([]time.Duration, len(members))
+	for i, member := range members {
+		delays[i] = s.DelayBefore(member.Name, i)
+	}
+
+	return delays
+}
+
+// LoadCompoundSettings reads the settings for the compound named name from the user-level
+// config.json and the project's .taskporter.json. Project settings replace user settings for
+// the same compound. Names are matched case-insensitively; no settings means no delays.
+func LoadCompoundSettings(projectRoot string, name string) (CompoundSettings, error) {
+	loaded, err := readSettings(projectRoot)
+	if err != nil {
+		return CompoundSettings{}, err
+	}
+
+	var settings CompoundSettings
+
+	for _, file := range loaded {
+		for compound, compoundSettings := range file.settings.Compounds {
+			if strings.EqualFold(compound, name) {
+				settings = compoundSettings
+			}
+		}
+	}
+
+	return settings, nil
+}
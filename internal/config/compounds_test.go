@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCompoundSettings(t *testing.T) {
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	t.Run("should let project settings replace user settings", func(t *testing.T) {
+		configHome := t.TempDir()
+		projectRoot := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+
+		writeFile(t, filepath.Join(configHome, "taskporter", "config.json"),
+			`{"compounds": {"Full Stack": {"delay": "10s"}, "Workers": {"delay": "1s"}}}`)
+		writeFile(t, filepath.Join(projectRoot, ProjectConfigFile),
+			`{"compounds": {"full stack": {"delay": "2s", "delays": {"Frontend": "5s"}}}}`)
+
+		settings, err := LoadCompoundSettings(projectRoot, "Full Stack")
+		require.NoError(t, err)
+		require.Equal(t, Duration(2*time.Second), settings.Delay)
+		require.Equal(t, map[string]Duration{"Frontend": Duration(5 * time.Second)}, settings.Delays)
+
+		workers, err := LoadCompoundSettings(projectRoot, "Workers")
+		require.NoError(t, err)
+		require.Equal(t, Duration(time.Second), workers.Delay)
+	})
+
+	t.Run("should default to no delays", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		settings, err := LoadCompoundSettings(t.TempDir(), "Full Stack")
+		require.NoError(t, err)
+		require.Equal(t, []time.Duration{0, 0}, settings.MemberDelays([]*Task{{Name: "Backend"}, {Name: "Frontend"}}))
+	})
+
+	t.Run("should reject invalid durations", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		for _, delay := range []string{`"soon"`, `"-1s"`, `5`} {
+			projectRoot := t.TempDir()
+			writeFile(t, filepath.Join(projectRoot, ProjectConfigFile), `{"compounds": {"Full Stack": {"delay": `+delay+`}}}`)
+
+			_, err := LoadCompoundSettings(projectRoot, "Full Stack")
+			require.Error(t, err, delay)
+		}
+	})
+}
+
+func TestCompoundSettings_MemberDelays(t *testing.T) {
+	members := []*Task{{Name: "Database"}, {Name: "Backend"}, {Name: "Frontend"}}
+
+	t.Run("should wait the delay between members", func(t *testing.T) {
+		settings := CompoundSettings{Delay: Duration(2 * time.Second)}
+
+		require.Equal(t, []time.Duration{0, 2 * time.Second, 2 * time.Second}, settings.MemberDelays(members))
+	})
+
+	t.Run("should prefer per-member delays", func(t *testing.T) {
+		settings := CompoundSettings{
+			Delay:  Duration(2 * time.Second),
+			Delays: map[string]Duration{"database": Duration(time.Second), "Frontend": Duration(5 * time.Second)},
+		}
+
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 5 * time.Second}, settings.MemberDelays(members))
+	})
+}
+
+func TestTask_IsCompound(t *testing.T) {
+	require.True(t, (&Task{DependsOn: []string{"a", "b"}}).IsCompound())
+	require.True(t, (&Task{DependsOn: []string{"a"}, DependsOrder: DependsOrderParallel}).IsCompound())
+	require.False(t, (&Task{DependsOn: []string{"a"}, DependsOrder: DependsOrderSequence}).IsCompound())
+	require.False(t, (&Task{Command: "make", DependsOn: []string{"a"}}).IsCompound())
+	require.False(t, (&Task{}).IsCompound())
+}
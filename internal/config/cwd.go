@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Kinds of working directory problems
+const (
+	CwdMissing        = "missing"
+	CwdNotDirectory   = "not-directory"
+	CwdOutsideProject = "outside-project"
+)
+
+// CwdIssue is a problem with a task's working directory that would only show up when it runs
+type CwdIssue struct {
+	Task   string `json:"task"`
+	Source string `json:"source,omitempty"`
+	Cwd    string `json:"cwd"`
+	Kind   string `json:"kind"`
+}
+
+func (i CwdIssue) String() string {
+	return fmt.Sprintf("task '%s': %s: %s", i.Task, i.Problem(), i.Cwd)
+}
+
+// Problem describes the kind of issue in a few words, e.g. "cwd does not exist"
+func (i CwdIssue) Problem() string {
+	switch i.Kind {
+	case CwdNotDirectory:
+		return "cwd is not a directory"
+	case CwdOutsideProject:
+		return "cwd resolves outside the project"
+	default:
+		return "cwd does not exist"
+	}
+}
+
+// CheckWorkingDirectory returns the problem with the task's working directory, or nil. Tasks
+// that create their directory (createCwd) may point at a missing one, and directories still
+// holding unresolved variables are not checked. JetBrains working directories outside
+// projectRoot are reported since they are often absolute paths from another machine.
+func CheckWorkingDirectory(task *Task, projectRoot string) *CwdIssue {
+	if task.Cwd == "" || strings.Contains(task.Cwd, "${") {
+		return nil
+	}
+
+	issue := &CwdIssue{Task: task.Name, Source: task.Source, Cwd: task.Cwd}
+
+	info, err := os.Stat(task.Cwd)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if task.CreateCwd {
+			return nil
+		}
+
+		issue.Kind = CwdMissing
+	case err == nil && !info.IsDir():
+		issue.Kind = CwdNotDirectory
+	case task.Type == TypeJetBrains && IsOutsideProject(task.Cwd, projectRoot):
+		issue.Kind = CwdOutsideProject
+	default:
+		return nil
+	}
+
+	return issue
+}
+
+// CheckWorkingDirectories checks the working directory of every task, in task order
+func CheckWorkingDirectories(tasks []*Task, projectRoot string) []CwdIssue {
+	var issues []CwdIssue
+
+	for _, task := range tasks {
+		if issue := CheckWorkingDirectory(task, projectRoot); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+
+	return issues
+}
+
+// IsOutsideProject reports whether path, relative to the working directory when not absolute,
+// lies outside projectRoot
+func IsOutsideProject(path, projectRoot string) bool {
+	absRoot, rootErr := filepath.Abs(projectRoot)
+	absPath, pathErr := filepath.Abs(path)
+
+	if rootErr != nil || pathErr != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(absRoot, absPath)
+
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWorkingDirectories(t *testing.T) {
+	projectRoot := t.TempDir()
+	outside := t.TempDir()
+
+	makefile := filepath.Join(projectRoot, "Makefile")
+	require.NoError(t, os.WriteFile(makefile, nil, 0644))
+
+	missing := filepath.Join(projectRoot, "build")
+
+	tasks := []*Task{
+		{Name: "root", Type: TypeVSCodeTask, Cwd: projectRoot},
+		{Name: "default", Type: TypeVSCodeTask},
+		{Name: "build", Type: TypeVSCodeTask, Cwd: missing, Source: ".vscode/tasks.json"},
+		{Name: "dist", Type: TypeVSCodeTask, Cwd: missing, CreateCwd: true},
+		{Name: "file", Type: TypeVSCodeTask, Cwd: makefile},
+		{Name: "unresolved", Type: TypeVSCodeTask, Cwd: "${fileDirname}"},
+		{Name: "stale", Type: TypeJetBrains, Cwd: outside, Source: ".idea/runConfigurations/stale.xml"},
+		{Name: "elsewhere", Type: TypeVSCodeTask, Cwd: outside},
+	}
+
+	issues := CheckWorkingDirectories(tasks, projectRoot)
+	require.Equal(t, []CwdIssue{
+		{Task: "build", Source: ".vscode/tasks.json", Cwd: missing, Kind: CwdMissing},
+		{Task: "file", Cwd: makefile, Kind: CwdNotDirectory},
+		{Task: "stale", Source: ".idea/runConfigurations/stale.xml", Cwd: outside, Kind: CwdOutsideProject},
+	}, issues)
+
+	require.Equal(t, "task 'build': cwd does not exist: "+missing, issues[0].String())
+}
+
+func TestIsOutsideProject(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "work", "app")
+
+	require.False(t, IsOutsideProject(root, root))
+	require.False(t, IsOutsideProject(filepath.Join(root, "cmd"), root))
+	require.False(t, IsOutsideProject(filepath.Join(root, "..dots"), root))
+	require.True(t, IsOutsideProject(filepath.Join(root, ".."), root))
+	require.True(t, IsOutsideProject(filepath.Join(root, "..", "other"), root))
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of dependency problems found by CheckDependencies
+const (
+	DependencyMissing = "missing"
+	DependencyCycle   = "cycle"
+)
+
+// DependencyIssue describes a broken dependsOn chain
+type DependencyIssue struct {
+	Kind       string   `json:"kind"`                 // DependencyMissing or DependencyCycle
+	Task       string   `json:"task"`                 // Task whose dependsOn is broken
+	Source     string   `json:"source"`               // File the task was defined in
+	Dependency string   `json:"dependency,omitempty"` // Missing dependency name
+	Cycle      []string `json:"cycle,omitempty"`      // Task names along the cycle, starting and ending with Task
+}
+
+// String describes the issue in one line
+func (i DependencyIssue) String() string {
+	if i.Kind == DependencyCycle {
+		return fmt.Sprintf("dependency cycle: %s", strings.Join(i.Cycle, " → "))
+	}
+
+	return fmt.Sprintf("task %q depends on unknown task %q", i.Task, i.Dependency)
+}
+
+// CheckDependencies walks the dependsOn graph without running anything, reporting dependencies
+// that name no known task and every cycle. Names resolve to the first task with that name,
+// as lookups do at run time. Issues are reported in task order.
+func CheckDependencies(tasks []*Task) []DependencyIssue {
+	byName := make(map[string]*Task, len(tasks))
+	for _, task := range tasks {
+		if _, ok := byName[task.Name]; !ok {
+			byName[task.Name] = task
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	var (
+		issues []DependencyIssue
+		path   []*Task
+		visit  func(task *Task)
+	)
+
+	state := make(map[*Task]int, len(tasks))
+
+	visit = func(task *Task) {
+		state[task] = visiting
+		path = append(path, task)
+
+		for _, name := range task.DependsOn {
+			dependency, ok := byName[name]
+			if !ok {
+				issues = append(issues, DependencyIssue{Kind: DependencyMissing, Task: task.Name, Source: task.Source, Dependency: name})
+				continue
+			}
+
+			switch state[dependency] {
+			case unvisited:
+				visit(dependency)
+			case visiting:
+				issues = append(issues, newCycleIssue(path, dependency))
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[task] = done
+	}
+
+	for _, task := range tasks {
+		if state[task] == unvisited {
+			visit(task)
+		}
+	}
+
+	return issues
+}
+
+// newCycleIssue reports the part of the walk path that loops back to target
+func newCycleIssue(path []*Task, target *Task) DependencyIssue {
+	start := len(path) - 1
+	for path[start] != target {
+		start--
+	}
+
+	cycle := make([]string, 0, len(path)-start+1)
+	for _, task := range path[start:] {
+		cycle = append(cycle, task.Name)
+	}
+
+	cycle = append(cycle, target.Name)
+
+	return DependencyIssue{Kind: DependencyCycle, Task: target.Name, Source: target.Source, Cycle: cycle}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	t.Run("should accept a valid chain", func(t *testing.T) {
+		tasks := []*Task{
+			{Name: "package", DependsOn: []string{"test", "lint"}},
+			{Name: "test", DependsOn: []string{"compile"}},
+			{Name: "compile"},
+			{Name: "lint", DependsOn: []string{"compile"}},
+		}
+
+		require.Empty(t, CheckDependencies(tasks))
+	})
+
+	t.Run("should report missing dependencies", func(t *testing.T) {
+		tasks := []*Task{
+			{Name: "package", Source: "tasks.json", DependsOn: []string{"docs", "test"}},
+			{Name: "test"},
+		}
+
+		issues := CheckDependencies(tasks)
+		require.Equal(t, []DependencyIssue{
+			{Kind: DependencyMissing, Task: "package", Source: "tasks.json", Dependency: "docs"},
+		}, issues)
+		require.Equal(t, `task "package" depends on unknown task "docs"`, issues[0].String())
+	})
+
+	t.Run("should report cycles once", func(t *testing.T) {
+		tasks := []*Task{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"c"}},
+			{Name: "c", DependsOn: []string{"a"}},
+			{Name: "d", DependsOn: []string{"a"}},
+		}
+
+		issues := CheckDependencies(tasks)
+		require.Len(t, issues, 1)
+		require.Equal(t, DependencyCycle, issues[0].Kind)
+		require.Equal(t, []string{"a", "b", "c", "a"}, issues[0].Cycle)
+		require.Equal(t, "dependency cycle: a → b → c → a", issues[0].String())
+	})
+
+	t.Run("should report self dependencies", func(t *testing.T) {
+		issues := CheckDependencies([]*Task{{Name: "loop", DependsOn: []string{"loop"}}})
+		require.Len(t, issues, 1)
+		require.Equal(t, []string{"loop", "loop"}, issues[0].Cycle)
+	})
+
+	t.Run("should report separate cycles and missing targets together", func(t *testing.T) {
+		tasks := []*Task{
+			{Name: "x", DependsOn: []string{"y"}},
+			{Name: "y", DependsOn: []string{"x", "ghost"}},
+			{Name: "p", DependsOn: []string{"q"}},
+			{Name: "q", DependsOn: []string{"p"}},
+		}
+
+		issues := CheckDependencies(tasks)
+		require.Len(t, issues, 3)
+		require.Equal(t, []string{"x", "y", "x"}, issues[0].Cycle)
+		require.Equal(t, "ghost", issues[1].Dependency)
+		require.Equal(t, []string{"p", "q", "p"}, issues[2].Cycle)
+	})
+}
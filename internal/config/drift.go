@@ -0,0 +1,310 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/syndbg/taskporter/internal/matcher"
+)
+
+// How the tasks of a DriftPair were paired
+const (
+	DriftMatchMapped = "mapped" // Paired by a --map override
+	DriftMatchName   = "name"   // Same name, ignoring case
+	DriftMatchFuzzy  = "fuzzy"  // Names differ only in separators or a couple of characters
+)
+
+// driftFuzzyDistance is the most edits two normalized names may be apart to pair fuzzily
+const driftFuzzyDistance = 2
+
+// DriftPair is the same logical task defined in two sources, with how the definitions differ
+type DriftPair struct {
+	Left        string     `json:"left"`
+	Right       string     `json:"right"`
+	LeftSource  string     `json:"leftSource"`
+	RightSource string     `json:"rightSource"`
+	Match       string     `json:"match"`
+	Diffs       []TaskDiff `json:"diffs,omitempty"`
+}
+
+// DriftReport compares the tasks of two sources
+type DriftReport struct {
+	Pairs          []DriftPair `json:"pairs"`
+	MissingInLeft  []string    `json:"missingInLeft"`  // Right tasks nothing on the left pairs with
+	MissingInRight []string    `json:"missingInRight"` // Left tasks nothing on the right pairs with
+}
+
+// Drifted returns the pairs whose definitions differ
+func (r *DriftReport) Drifted() []DriftPair {
+	var drifted []DriftPair
+
+	for _, pair := range r.Pairs {
+		if len(pair.Diffs) > 0 {
+			drifted = append(drifted, pair)
+		}
+	}
+
+	return drifted
+}
+
+// HasDrift reports whether the sources disagree: a pair differs or a task exists on one side only
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Drifted()) > 0 || len(r.MissingInLeft) > 0 || len(r.MissingInRight) > 0
+}
+
+// CompareTaskSets pairs the tasks of two sources and compares each pair's command line, cwd, env
+// and group. Tasks are paired by mapping (left name → right name) first, then by name ignoring
+// case, then fuzzily by near-identical names. Working directories are compared relative to
+// projectRoot, and groups only when both sources set one and neither is JetBrains, which derives
+// it from the configuration type.
+func CompareTaskSets(left, right []*Task, projectRoot string, mapping map[string]string) (*DriftReport, error) {
+	report := &DriftReport{Pairs: []DriftPair{}, MissingInLeft: []string{}, MissingInRight: []string{}}
+
+	pairedRight := make(map[*Task]bool, len(right))
+	pairs := make(map[*Task]*Task, len(left))
+	matches := make(map[*Task]string, len(left))
+
+	pair := func(l, r *Task, match string) {
+		pairs[l], matches[l] = r, match
+		pairedRight[r] = true
+	}
+
+	for _, leftName := range slices.Sorted(maps.Keys(mapping)) {
+		l := FindTaskByName(left, leftName)
+		if l == nil {
+			return nil, fmt.Errorf("mapped task '%s' not found", leftName)
+		}
+
+		r := FindTaskByName(right, mapping[leftName])
+		if r == nil {
+			return nil, fmt.Errorf("mapped task '%s' not found", mapping[leftName])
+		}
+
+		pair(l, r, DriftMatchMapped)
+	}
+
+	unpaired := func(candidates []*Task, paired func(*Task) bool) []*Task {
+		var result []*Task
+
+		for _, task := range candidates {
+			if !paired(task) {
+				result = append(result, task)
+			}
+		}
+
+		return result
+	}
+
+	isLeftPaired := func(task *Task) bool { return pairs[task] != nil }
+	isRightPaired := func(task *Task) bool { return pairedRight[task] }
+
+	for _, l := range unpaired(left, isLeftPaired) {
+		if r := FindTaskByName(unpaired(right, isRightPaired), l.Name); r != nil {
+			pair(l, r, DriftMatchName)
+		}
+	}
+
+	for _, l := range unpaired(left, isLeftPaired) {
+		if r := closestTask(l, unpaired(right, isRightPaired)); r != nil {
+			pair(l, r, DriftMatchFuzzy)
+		}
+	}
+
+	for _, l := range left {
+		r := pairs[l]
+		if r == nil {
+			report.MissingInRight = append(report.MissingInRight, l.Name)
+			continue
+		}
+
+		report.Pairs = append(report.Pairs, DriftPair{
+			Left:        l.Name,
+			Right:       r.Name,
+			LeftSource:  l.Source,
+			RightSource: r.Source,
+			Match:       matches[l],
+			Diffs:       diffDrift(l, r, projectRoot),
+		})
+	}
+
+	for _, r := range unpaired(right, isRightPaired) {
+		report.MissingInLeft = append(report.MissingInLeft, r.Name)
+	}
+
+	return report, nil
+}
+
+// closestTask returns the candidate whose normalized name is nearest to the task's, when it is
+// near enough and no other candidate is as near
+func closestTask(task *Task, candidates []*Task) *Task {
+	name := normalizeDriftName(task.Name)
+
+	var (
+		best     *Task
+		bestDist = driftFuzzyDistance + 1
+		tied     bool
+	)
+
+	for _, candidate := range candidates {
+		candidateName := normalizeDriftName(candidate.Name)
+
+		// Short names are a couple of edits away from almost anything
+		if min(len(name), len(candidateName)) <= driftFuzzyDistance*2 && name != candidateName {
+			continue
+		}
+
+		distance := matcher.LevenshteinDistance(name, candidateName)
+
+		switch {
+		case distance < bestDist:
+			best, bestDist, tied = candidate, distance, false
+		case distance == bestDist:
+			tied = true
+		}
+	}
+
+	if tied {
+		return nil
+	}
+
+	return best
+}
+
+// normalizeDriftName lowercases a name and drops everything but letters and digits, so "Build
+// App", "build-app" and "build:app" are the same name
+func normalizeDriftName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, name)
+}
+
+// diffDrift compares the fields that make two definitions of a task behave differently, with
+// one difference per env key
+func diffDrift(left, right *Task, projectRoot string) []TaskDiff {
+	diffs := DiffTaskFields(left, right, FieldCommandLine)
+
+	if l, r := normalizeDriftCwd(left.Cwd, projectRoot), normalizeDriftCwd(right.Cwd, projectRoot); l != r {
+		diffs = append(diffs, TaskDiff{Field: FieldCwd, Before: l, After: r})
+	}
+
+	keys := make([]string, 0, len(left.Env)+len(right.Env))
+	for key := range left.Env {
+		keys = append(keys, key)
+	}
+
+	for key := range right.Env {
+		if _, ok := left.Env[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		l, inLeft := left.Env[key]
+		r, inRight := right.Env[key]
+
+		if inLeft && inRight && l == r {
+			continue
+		}
+
+		if !inLeft {
+			l = "(unset)"
+		}
+
+		if !inRight {
+			r = "(unset)"
+		}
+
+		diffs = append(diffs, TaskDiff{Field: FieldEnv + "." + key, Before: l, After: r})
+	}
+
+	// JetBrains derives the group from the configuration type, so it says nothing about drift
+	comparableGroups := left.Group != "" && right.Group != "" && left.Type != TypeJetBrains && right.Type != TypeJetBrains
+	if comparableGroups && left.Group != right.Group {
+		diffs = append(diffs, TaskDiff{Field: FieldGroup, Before: left.Group, After: right.Group})
+	}
+
+	return diffs
+}
+
+// FieldComparison is one field of two definitions of a task, side by side
+type FieldComparison struct {
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	Equal bool   `json:"equal"`
+}
+
+// CompareTaskFields lines up the command, args, cwd and env of two definitions of a task, one
+// row per env key set on either side, so a single pair can be reviewed field by field. Formats
+// split a command line between command and args differently, so both count as equal when they
+// join to the same command line. Working directories are compared relative to projectRoot.
+func CompareTaskFields(left, right *Task, projectRoot string) []FieldComparison {
+	differing := make(map[string]TaskDiff)
+	for _, diff := range diffDrift(left, right, projectRoot) {
+		differing[diff.Field] = diff
+	}
+
+	_, commandLineDiffers := differing[FieldCommandLine]
+	_, cwdDiffers := differing[FieldCwd]
+	leftArgs, rightArgs := strings.Join(left.Args, " "), strings.Join(right.Args, " ")
+
+	fields := []FieldComparison{
+		{Field: "command", Left: left.Command, Right: right.Command, Equal: !commandLineDiffers || left.Command == right.Command},
+		{Field: "args", Left: leftArgs, Right: rightArgs, Equal: !commandLineDiffers || leftArgs == rightArgs},
+		{Field: FieldCwd, Left: normalizeDriftCwd(left.Cwd, projectRoot), Right: normalizeDriftCwd(right.Cwd, projectRoot), Equal: !cwdDiffers},
+	}
+
+	// diffDrift reports the env keys that differ; the ones both sides agree on are added equal
+	var env []FieldComparison
+
+	for field, diff := range differing {
+		if strings.HasPrefix(field, FieldEnv+".") {
+			env = append(env, FieldComparison{Field: field, Left: diff.Before, Right: diff.After})
+		}
+	}
+
+	for key, value := range left.Env {
+		if other, ok := right.Env[key]; ok && other == value {
+			env = append(env, FieldComparison{Field: FieldEnv + "." + key, Left: value, Right: value, Equal: true})
+		}
+	}
+
+	slices.SortFunc(env, func(a, b FieldComparison) int { return strings.Compare(a.Field, b.Field) })
+
+	return append(fields, env...)
+}
+
+// normalizeDriftCwd renders a working directory relative to the project root, "." for the root
+// itself or an unset one, so formats that store absolute and relative paths compare equal
+func normalizeDriftCwd(cwd, projectRoot string) string {
+	if cwd == "" {
+		return "."
+	}
+
+	if !filepath.IsAbs(cwd) {
+		return filepath.Clean(cwd)
+	}
+
+	absRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return filepath.Clean(cwd)
+	}
+
+	rel, err := filepath.Rel(absRoot, cwd)
+	if err != nil || !filepath.IsLocal(rel) {
+		return filepath.Clean(cwd)
+	}
+
+	return rel
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareTaskSets(t *testing.T) {
+	vscodeTasks := []*Task{
+		{Name: "build", Type: TypeVSCodeTask, Command: "go build", Args: []string{"./..."}, Cwd: "/project", Env: map[string]string{"GOFLAGS": "-mod=mod"}},
+		{Name: "Build App", Type: TypeVSCodeTask, Command: "go build ./cmd/app"},
+		{Name: "lint", Type: TypeVSCodeTask, Command: "golangci-lint run"},
+		{Name: "Start API", Type: TypeVSCodeTask, Command: "go run ./cmd/api", Cwd: "/project/cmd"},
+	}
+
+	jetbrainsTasks := []*Task{
+		{Name: "BUILD", Type: TypeJetBrains, Command: "go", Args: []string{"build", "."}, Group: "run", Env: map[string]string{"CGO_ENABLED": "0"}},
+		{Name: "build-app", Type: TypeJetBrains, Command: "go build ./cmd/app", Group: "build"},
+		{Name: "API Server", Type: TypeJetBrains, Command: "go run ./cmd/api", Cwd: "cmd"},
+		{Name: "serve", Type: TypeJetBrains, Command: "go run ."},
+	}
+
+	t.Run("should pair by name, fuzzily and by mapping and report differing fields", func(t *testing.T) {
+		report, err := CompareTaskSets(vscodeTasks, jetbrainsTasks, "/project", map[string]string{"Start API": "API Server"})
+		require.NoError(t, err)
+
+		require.Equal(t, []DriftPair{
+			{Left: "build", Right: "BUILD", Match: DriftMatchName, Diffs: []TaskDiff{
+				{Field: FieldCommandLine, Before: "go build ./...", After: "go build ."},
+				{Field: "env.CGO_ENABLED", Before: "(unset)", After: "0"},
+				{Field: "env.GOFLAGS", Before: "-mod=mod", After: "(unset)"},
+			}},
+			{Left: "Build App", Right: "build-app", Match: DriftMatchFuzzy},
+			{Left: "Start API", Right: "API Server", Match: DriftMatchMapped},
+		}, report.Pairs)
+		require.Equal(t, []string{"serve"}, report.MissingInLeft)
+		require.Equal(t, []string{"lint"}, report.MissingInRight)
+		require.Len(t, report.Drifted(), 1)
+		require.True(t, report.HasDrift())
+	})
+
+	t.Run("should report no drift for matching sets", func(t *testing.T) {
+		report, err := CompareTaskSets(vscodeTasks[1:2], jetbrainsTasks[1:2], "/project", nil)
+		require.NoError(t, err)
+
+		require.False(t, report.HasDrift())
+	})
+
+	t.Run("should compare groups outside JetBrains", func(t *testing.T) {
+		left := []*Task{{Name: "test", Type: TypeVSCodeTask, Command: "go test", Group: "test"}}
+		right := []*Task{{Name: "test", Type: TypeVSCodeLaunch, Command: "go test", Group: "build"}}
+
+		report, err := CompareTaskSets(left, right, "/project", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []TaskDiff{{Field: FieldGroup, Before: "test", After: "build"}}, report.Pairs[0].Diffs)
+	})
+
+	t.Run("should not pair short or ambiguous names fuzzily", func(t *testing.T) {
+		left := []*Task{{Name: "test"}, {Name: "deploy-api"}}
+		right := []*Task{{Name: "tests"}, {Name: "deploy-app"}, {Name: "deploy-apk"}}
+
+		report, err := CompareTaskSets(left, right, "/project", nil)
+		require.NoError(t, err)
+
+		require.Empty(t, report.Pairs)
+	})
+
+	t.Run("should fail on a mapping naming a missing task", func(t *testing.T) {
+		_, err := CompareTaskSets(vscodeTasks, jetbrainsTasks, "/project", map[string]string{"lint": "Lint All"})
+
+		require.EqualError(t, err, "mapped task 'Lint All' not found")
+	})
+}
+
+func TestCompareTaskFields(t *testing.T) {
+	t.Run("should treat command and args as equal when they form the same command line", func(t *testing.T) {
+		left := &Task{Name: "build", Type: TypeVSCodeTask, Command: "gradle build", Cwd: "/project"}
+		right := &Task{Name: "Gradle Build", Type: TypeJetBrains, Command: "gradle", Args: []string{"build"}}
+
+		require.Equal(t, []FieldComparison{
+			{Field: "command", Left: "gradle build", Right: "gradle", Equal: true},
+			{Field: "args", Left: "", Right: "build", Equal: true},
+			{Field: FieldCwd, Left: ".", Right: ".", Equal: true},
+		}, CompareTaskFields(left, right, "/project"))
+	})
+
+	t.Run("should list differing fields, shared env variables and ones set on one side only", func(t *testing.T) {
+		left := &Task{Command: "gradle", Args: []string{"build", "--info"}, Cwd: "app", Env: map[string]string{"JAVA_OPTS": "-Xmx1g", "CI": "1", "GOFLAGS": "-mod=mod"}}
+		right := &Task{Command: "gradle", Args: []string{"build"}, Cwd: "/project", Env: map[string]string{"JAVA_OPTS": "-Xmx2g", "TERM": "dumb", "GOFLAGS": "-mod=mod"}}
+
+		require.Equal(t, []FieldComparison{
+			{Field: "command", Left: "gradle", Right: "gradle", Equal: true},
+			{Field: "args", Left: "build --info", Right: "build", Equal: false},
+			{Field: FieldCwd, Left: "app", Right: ".", Equal: false},
+			{Field: "env.CI", Left: "1", Right: "(unset)", Equal: false},
+			{Field: "env.GOFLAGS", Left: "-mod=mod", Right: "-mod=mod", Equal: true},
+			{Field: "env.JAVA_OPTS", Left: "-Xmx1g", Right: "-Xmx2g", Equal: false},
+			{Field: "env.TERM", Left: "(unset)", Right: "dumb", Equal: false},
+		}, CompareTaskFields(left, right, "/project"))
+	})
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TaskExtension defines a task that inherits everything from another task and changes only
+// what it sets, from the "tasks" object in .taskporter.json or the user-level config.json,
+// keyed by the new task's name:
+//
+//	{"tasks": {"test-race": {"extends": "test", "args": ["-race"]}}}
+type TaskExtension struct {
+	Name      string            `json:"-"`
+	Extends   string            `json:"extends"`             // Task or other extension inherited from
+	Command   string            `json:"command,omitempty"`   // Replaces the base command
+	Args      []string          `json:"args,omitempty"`      // Appended to the base args
+	Env       map[string]string `json:"env,omitempty"`       // Merged over the base env
+	CreateCwd bool              `json:"createCwd,omitempty"` // Create the base's missing cwd before running
+	CleanEnv  bool              `json:"cleanEnv,omitempty"`  // Run without the inherited environment
+	Source    string            `json:"-"`                   // Configuration file the extension was defined in
+}
+
+// ExtendsCycleError is a chain of extensions that extend each other, so none of them can be defined
+type ExtendsCycleError struct {
+	Cycle []string // Names along the cycle, the first repeated at the end
+}
+
+// Error implements the error interface
+func (e *ExtendsCycleError) Error() string {
+	return fmt.Sprintf("extends cycle %s", strings.Join(e.Cycle, " → "))
+}
+
+// LoadTaskExtensions reads the task extensions from the user-level config.json and the project's
+// .taskporter.json. Project extensions replace user extensions with the same name. Missing files
+// are not an error. Extensions are returned sorted by name.
+func LoadTaskExtensions(projectRoot string) ([]TaskExtension, error) {
+	loaded, err := readSettings(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]TaskExtension)
+
+	for _, file := range loaded {
+		for name, extension := range file.settings.Tasks {
+			extension.Name = name
+			extension.Source = file.path
+			byName[strings.ToLower(name)] = extension
+		}
+	}
+
+	extensions := slices.Collect(maps.Values(byName))
+
+	sort.Slice(extensions, func(i, j int) bool {
+		return strings.ToLower(extensions[i].Name) < strings.ToLower(extensions[j].Name)
+	})
+
+	return extensions, nil
+}
+
+// ResolveTaskExtensions appends a task for every extension to tasks. An extension may extend
+// another extension. Extensions that clash with a task, name no known base or are part of an
+// extends cycle are left out and reported in the returned error, cycles as an *ExtendsCycleError.
+func ResolveTaskExtensions(tasks []*Task, extensions []TaskExtension) ([]*Task, error) {
+	var errs []error
+
+	// A real task wins over an extension with its name, also as the base of other extensions
+	byName := make(map[string]TaskExtension, len(extensions))
+
+	for _, extension := range extensions {
+		if existing := FindTaskByName(tasks, extension.Name); existing != nil {
+			errs = append(errs, fmt.Errorf("task '%s' from %s is already defined in %s", extension.Name, extension.Source, existing.Source))
+			continue
+		}
+
+		byName[strings.ToLower(extension.Name)] = extension
+	}
+
+	resolved := make(map[string]*Task, len(byName))
+
+	var resolve func(extension TaskExtension, chain []string) (*Task, error)
+
+	resolve = func(extension TaskExtension, chain []string) (*Task, error) {
+		if task, ok := resolved[strings.ToLower(extension.Name)]; ok {
+			return task, nil
+		}
+
+		chain = append(chain, extension.Name)
+
+		var base *Task
+
+		if next, ok := byName[strings.ToLower(extension.Extends)]; ok {
+			for i, name := range chain {
+				if strings.EqualFold(name, next.Name) {
+					return nil, &ExtendsCycleError{Cycle: append(slices.Clone(chain[i:]), next.Name)}
+				}
+			}
+
+			var err error
+			if base, err = resolve(next, chain); err != nil {
+				return nil, err
+			}
+		} else if base = FindTaskByName(tasks, extension.Extends); base == nil {
+			return nil, fmt.Errorf("'%s' extends unknown task '%s'", extension.Name, extension.Extends)
+		}
+
+		task := extension.apply(base)
+		resolved[strings.ToLower(extension.Name)] = task
+
+		return task, nil
+	}
+
+	merged := tasks
+
+	for _, extension := range extensions {
+		if _, ok := byName[strings.ToLower(extension.Name)]; !ok {
+			continue
+		}
+
+		task, err := resolve(extension, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("task '%s' from %s cannot be defined: %w", extension.Name, extension.Source, err))
+			continue
+		}
+
+		merged = append(merged, task)
+	}
+
+	return merged, errors.Join(errs...)
+}
+
+// apply derives the extension's task from base, which is left unchanged
+func (e TaskExtension) apply(base *Task) *Task {
+	task := *base
+	task.Name = e.Name
+	task.Extends = base.Name
+	task.Source = e.Source
+	task.Aliases = nil
+	task.Tags = slices.Clone(base.Tags)
+	task.DependsOn = slices.Clone(base.DependsOn)
+
+	// Source-specific fields describe the base task, not this one
+	task.Passthrough = nil
+
+	if e.Command != "" {
+		task.Command = e.Command
+	}
+
+	task.Args = append(slices.Clone(base.Args), e.Args...)
+	task.CreateCwd = base.CreateCwd || e.CreateCwd
+	task.CleanEnv = base.CleanEnv || e.CleanEnv
+
+	if len(base.Env) > 0 || len(e.Env) > 0 {
+		task.Env = make(map[string]string, len(base.Env)+len(e.Env))
+		maps.Copy(task.Env, base.Env)
+		maps.Copy(task.Env, e.Env)
+	}
+
+	return &task
+}
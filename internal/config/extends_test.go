@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTaskExtensions(t *testing.T) {
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	t.Run("should let project extensions replace user extensions", func(t *testing.T) {
+		configHome := t.TempDir()
+		projectRoot := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+
+		userPath := filepath.Join(configHome, "taskporter", "config.json")
+		projectPath := filepath.Join(projectRoot, ProjectConfigFile)
+
+		writeFile(t, userPath, `{"tasks": {"test-race": {"extends": "test", "args": ["-count=1"]}, "lint-fix": {"extends": "lint"}}}`)
+		writeFile(t, projectPath, `{"tasks": {"Test-Race": {"extends": "test", "args": ["-race"], "env": {"CGO_ENABLED": "1"}, "createCwd": true, "cleanEnv": true}}}`)
+
+		extensions, err := LoadTaskExtensions(projectRoot)
+		require.NoError(t, err)
+		require.Equal(t, []TaskExtension{
+			{Name: "lint-fix", Extends: "lint", Source: userPath},
+			{Name: "Test-Race", Extends: "test", Args: []string{"-race"}, Env: map[string]string{"CGO_ENABLED": "1"}, CreateCwd: true, CleanEnv: true, Source: projectPath},
+		}, extensions)
+	})
+}
+
+func TestResolveTaskExtensions(t *testing.T) {
+	newTasks := func() []*Task {
+		return []*Task{{
+			Name:    "test",
+			Type:    TypeVSCodeTask,
+			Command: "go",
+			Args:    []string{"test", "./..."},
+			Env:     map[string]string{"GOFLAGS": "-mod=mod", "CGO_ENABLED": "0"},
+			Group:   "test",
+			Source:  ".vscode/tasks.json",
+		}}
+	}
+
+	t.Run("should merge args and env over the base task", func(t *testing.T) {
+		tasks := newTasks()
+
+		resolved, err := ResolveTaskExtensions(tasks, []TaskExtension{
+			{Name: "test-race", Extends: "test", Args: []string{"-race"}, Env: map[string]string{"CGO_ENABLED": "1"}, CleanEnv: true, Source: ProjectConfigFile},
+		})
+		require.NoError(t, err)
+		require.Len(t, resolved, 2)
+
+		derived := resolved[1]
+		require.Equal(t, "test-race", derived.Name)
+		require.Equal(t, "test", derived.Extends)
+		require.Equal(t, "go", derived.Command)
+		require.Equal(t, []string{"test", "./...", "-race"}, derived.Args)
+		require.Equal(t, map[string]string{"GOFLAGS": "-mod=mod", "CGO_ENABLED": "1"}, derived.Env)
+		require.Equal(t, "test", derived.Group)
+		require.Equal(t, ProjectConfigFile, derived.Source)
+		require.True(t, derived.CleanEnv)
+
+		// The base task is left alone
+		require.Equal(t, []string{"test", "./..."}, tasks[0].Args)
+		require.Equal(t, "0", tasks[0].Env["CGO_ENABLED"])
+	})
+
+	t.Run("should let extensions extend each other", func(t *testing.T) {
+		resolved, err := ResolveTaskExtensions(newTasks(), []TaskExtension{
+			{Name: "test-race-verbose", Extends: "test-race", Args: []string{"-v"}},
+			{Name: "test-race", Extends: "test", Args: []string{"-race"}},
+			{Name: "test-gotest", Extends: "test", Command: "gotest"},
+		})
+		require.NoError(t, err)
+		require.Len(t, resolved, 4)
+
+		require.Equal(t, []string{"test", "./...", "-race", "-v"}, resolved[1].Args)
+		require.Equal(t, "test-race", resolved[1].Extends)
+		require.Equal(t, "gotest", resolved[3].Command)
+	})
+
+	t.Run("should report extends cycles", func(t *testing.T) {
+		resolved, err := ResolveTaskExtensions(newTasks(), []TaskExtension{
+			{Name: "a", Extends: "b", Source: ProjectConfigFile},
+			{Name: "b", Extends: "a", Source: ProjectConfigFile},
+			{Name: "c", Extends: "c", Source: ProjectConfigFile},
+			{Name: "ok", Extends: "test", Source: ProjectConfigFile},
+		})
+		require.Len(t, resolved, 2)
+		require.Equal(t, "ok", resolved[1].Name)
+
+		require.ErrorContains(t, err, "task 'a' from .taskporter.json cannot be defined: extends cycle a → b → a")
+		require.ErrorContains(t, err, "task 'b' from .taskporter.json cannot be defined: extends cycle b → a → b")
+		require.ErrorContains(t, err, "extends cycle c → c")
+	})
+
+	t.Run("should report unknown bases and clashes with real tasks", func(t *testing.T) {
+		resolved, err := ResolveTaskExtensions(newTasks(), []TaskExtension{
+			{Name: "bench", Extends: "benchmarks", Source: ProjectConfigFile},
+			{Name: "Test", Extends: "test", Source: ProjectConfigFile},
+		})
+		require.Len(t, resolved, 1)
+
+		require.ErrorContains(t, err, "'bench' extends unknown task 'benchmarks'")
+		require.ErrorContains(t, err, "task 'Test' from .taskporter.json is already defined in .vscode/tasks.json")
+	})
+}
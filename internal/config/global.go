@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GlobalTasksPath returns the user-level tasks file, $XDG_CONFIG_HOME/taskporter/tasks.json
+// or ~/.config/taskporter/tasks.json. It uses the VSCode tasks.json format.
+func GlobalTasksPath() (string, error) {
+	return userConfigFile("tasks.json")
+}
+
+// userConfigFile returns name inside the user-level taskporter directory,
+// $XDG_CONFIG_HOME/taskporter or ~/.config/taskporter
+func userConfigFile(name string) (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, "taskporter", name), nil
+}
+
+// MergeGlobalTasks appends global tasks to the project tasks. Project tasks win on name
+// collision; the global tasks they shadow are returned separately.
+func MergeGlobalTasks(project []*Task, global []*Task) ([]*Task, []*Task) {
+	names := make(map[string]bool, len(project))
+	for _, task := range project {
+		names[task.Name] = true
+	}
+
+	merged := project
+
+	var shadowed []*Task
+
+	for _, task := range global {
+		if names[task.Name] {
+			shadowed = append(shadowed, task)
+			continue
+		}
+
+		merged = append(merged, task)
+	}
+
+	return merged, shadowed
+}
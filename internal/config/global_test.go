@@ -0,0 +1,46 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalTasksPath(t *testing.T) {
+	t.Run("should honor XDG_CONFIG_HOME", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/custom/config")
+
+		path, err := GlobalTasksPath()
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join("/custom/config", "taskporter", "tasks.json"), path)
+	})
+
+	t.Run("should default to ~/.config", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("HOME", home)
+
+		path, err := GlobalTasksPath()
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(home, ".config", "taskporter", "tasks.json"), path)
+	})
+}
+
+func TestMergeGlobalTasks(t *testing.T) {
+	build := &Task{Name: "build", Type: TypeVSCodeTask}
+	globalBuild := &Task{Name: "build", Type: TypeGlobal}
+	cleanup := &Task{Name: "docker cleanup", Type: TypeGlobal}
+
+	t.Run("should let project tasks win on name collision", func(t *testing.T) {
+		merged, shadowed := MergeGlobalTasks([]*Task{build}, []*Task{globalBuild, cleanup})
+		require.Equal(t, []*Task{build, cleanup}, merged)
+		require.Equal(t, []*Task{globalBuild}, shadowed)
+	})
+
+	t.Run("should keep global tasks without project tasks", func(t *testing.T) {
+		merged, shadowed := MergeGlobalTasks(nil, []*Task{cleanup})
+		require.Equal(t, []*Task{cleanup}, merged)
+		require.Empty(t, shadowed)
+	})
+}
@@ -0,0 +1,137 @@
+package config
+
+import "strconv"
+
+// Kinds of links between tasks in a TaskGraph
+const (
+	LinkDependsOn     = "dependsOn"
+	LinkPreLaunchTask = "preLaunchTask"
+	LinkPostDebugTask = "postDebugTask"
+	LinkBeforeRun     = "beforeRun"
+)
+
+// GraphNode is a task taking part in at least one link
+type GraphNode struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Type    TaskType `json:"type,omitempty"`
+	Source  string   `json:"source,omitempty"`
+	Missing bool     `json:"missing,omitempty"` // Referenced by name, but no task has that name
+}
+
+// GraphEdge links a task to another task it runs around its own command
+type GraphEdge struct {
+	From   string `json:"from"`   // ID of the referencing task
+	To     string `json:"to"`     // ID of the referenced task
+	Kind   string `json:"kind"`   // LinkDependsOn, LinkPreLaunchTask, LinkPostDebugTask or LinkBeforeRun
+	Source string `json:"source"` // File the reference is written in
+}
+
+// TaskGraph holds the links between tasks and launch configurations
+type TaskGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Node returns the node with the given ID, or nil
+func (g *TaskGraph) Node(id string) *GraphNode {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return &g.Nodes[i]
+		}
+	}
+
+	return nil
+}
+
+// BuildTaskGraph collects the dependsOn, preLaunchTask, postDebugTask and JetBrains before-run
+// references between tasks. Names resolve to the first task with that name, as lookups do at run
+// time, and names no task carries become missing nodes. Tasks without links are left out. Nodes
+// are numbered in task order, followed by missing names in the order they are first referenced,
+// so the same tasks always give the same graph. References are only collected, never followed,
+// so cycles show up as edges rather than failing the build.
+func BuildTaskGraph(tasks []*Task) *TaskGraph {
+	byName := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		if _, ok := byName[task.Name]; !ok {
+			byName[task.Name] = i
+		}
+	}
+
+	type reference struct {
+		from int
+		to   string
+		kind string
+	}
+
+	var references []reference
+
+	for i, task := range tasks {
+		for _, name := range task.DependsOn {
+			references = append(references, reference{from: i, to: name, kind: LinkDependsOn})
+		}
+
+		if task.PreLaunchTask != "" {
+			references = append(references, reference{from: i, to: task.PreLaunchTask, kind: LinkPreLaunchTask})
+		}
+
+		if task.PostDebugTask != "" {
+			references = append(references, reference{from: i, to: task.PostDebugTask, kind: LinkPostDebugTask})
+		}
+
+		for _, name := range task.BeforeRun {
+			references = append(references, reference{from: i, to: name, kind: LinkBeforeRun})
+		}
+	}
+
+	linked := make(map[int]bool)
+	missing := make(map[string]bool)
+
+	var missingNames []string
+
+	for _, ref := range references {
+		linked[ref.from] = true
+
+		if target, ok := byName[ref.to]; ok {
+			linked[target] = true
+		} else if !missing[ref.to] {
+			missing[ref.to] = true
+			missingNames = append(missingNames, ref.to)
+		}
+	}
+
+	graph := &TaskGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	ids := make(map[int]string, len(linked))
+
+	for i, task := range tasks {
+		if !linked[i] {
+			continue
+		}
+
+		ids[i] = nodeID(len(graph.Nodes))
+		graph.Nodes = append(graph.Nodes, GraphNode{ID: ids[i], Name: task.Name, Type: task.Type, Source: task.Source})
+	}
+
+	missingIDs := make(map[string]string, len(missingNames))
+
+	for _, name := range missingNames {
+		missingIDs[name] = nodeID(len(graph.Nodes))
+		graph.Nodes = append(graph.Nodes, GraphNode{ID: missingIDs[name], Name: name, Missing: true})
+	}
+
+	for _, ref := range references {
+		to := missingIDs[ref.to]
+		if target, ok := byName[ref.to]; ok {
+			to = ids[target]
+		}
+
+		graph.Edges = append(graph.Edges, GraphEdge{From: ids[ref.from], To: to, Kind: ref.kind, Source: tasks[ref.from].Source})
+	}
+
+	return graph
+}
+
+// nodeID numbers nodes, since task names are not unique across sources
+func nodeID(index int) string {
+	return "n" + strconv.Itoa(index)
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTaskGraph(t *testing.T) {
+	t.Run("links launch configurations and dependencies", func(t *testing.T) {
+		tasks := []*Task{
+			{Name: "build", Type: TypeVSCodeTask, Source: "tasks.json"},
+			{Name: "lint", Type: TypeVSCodeTask, Source: "tasks.json"},
+			{Name: "check", Type: TypeVSCodeTask, Source: "tasks.json", DependsOn: []string{"build", "docs"}},
+			{Name: "Launch", Type: TypeVSCodeLaunch, Source: "launch.json", PreLaunchTask: "build", PostDebugTask: "cleanup"},
+		}
+
+		graph := BuildTaskGraph(tasks)
+
+		require.Equal(t, []GraphNode{
+			{ID: "n0", Name: "build", Type: TypeVSCodeTask, Source: "tasks.json"},
+			{ID: "n1", Name: "check", Type: TypeVSCodeTask, Source: "tasks.json"},
+			{ID: "n2", Name: "Launch", Type: TypeVSCodeLaunch, Source: "launch.json"},
+			{ID: "n3", Name: "docs", Missing: true},
+			{ID: "n4", Name: "cleanup", Missing: true},
+		}, graph.Nodes)
+
+		require.Equal(t, []GraphEdge{
+			{From: "n1", To: "n0", Kind: LinkDependsOn, Source: "tasks.json"},
+			{From: "n1", To: "n3", Kind: LinkDependsOn, Source: "tasks.json"},
+			{From: "n2", To: "n0", Kind: LinkPreLaunchTask, Source: "launch.json"},
+			{From: "n2", To: "n4", Kind: LinkPostDebugTask, Source: "launch.json"},
+		}, graph.Edges)
+
+		require.Equal(t, "Launch", graph.Node("n2").Name)
+		require.Nil(t, graph.Node("n9"))
+	})
+
+	t.Run("resolves names to the first task", func(t *testing.T) {
+		tasks := []*Task{
+			{Name: "build", Type: TypeVSCodeTask},
+			{Name: "build", Type: TypeJetBrains},
+			{Name: "Launch", Type: TypeVSCodeLaunch, PreLaunchTask: "build"},
+		}
+
+		graph := BuildTaskGraph(tasks)
+
+		require.Len(t, graph.Nodes, 2)
+		require.Equal(t, TypeVSCodeTask, graph.Node(graph.Edges[0].To).Type)
+	})
+
+	t.Run("links JetBrains before-run configurations", func(t *testing.T) {
+		tasks := []*Task{
+			{Name: "Gradle Build", Type: TypeJetBrains, Source: "Gradle_Build.xml"},
+			{Name: "App", Type: TypeJetBrains, Source: "App.xml", BeforeRun: []string{"Gradle Build"}},
+		}
+
+		graph := BuildTaskGraph(tasks)
+
+		require.Equal(t, []GraphEdge{{From: "n1", To: "n0", Kind: LinkBeforeRun, Source: "App.xml"}}, graph.Edges)
+	})
+
+	t.Run("tolerates cycles", func(t *testing.T) {
+		tasks := []*Task{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"a"}},
+		}
+
+		graph := BuildTaskGraph(tasks)
+
+		require.Len(t, graph.Nodes, 2)
+		require.Len(t, graph.Edges, 2)
+	})
+
+	t.Run("empty without links", func(t *testing.T) {
+		graph := BuildTaskGraph([]*Task{{Name: "build"}})
+
+		require.Empty(t, graph.Nodes)
+		require.Empty(t, graph.Edges)
+	})
+}
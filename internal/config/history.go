@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryEntry records one task run in the local history file
+type HistoryEntry struct {
+	Project    string    `json:"project"`    // Absolute project root the task ran in
+	Task       string    `json:"task"`       // Task name at the time of the run
+	Source     string    `json:"source"`     // Configuration file defining the task
+	Started    time.Time `json:"started"`    // When the run started
+	DurationMs int64     `json:"durationMs"` // Wall time of the run, retries included
+	ExitCode   int       `json:"exitCode"`   // 0 for success
+}
+
+// Duration returns how long the run took
+func (e HistoryEntry) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+// maxHistoryBytes is the size at which the history file is rotated: it moves to
+// history.jsonl.1, replacing the previous one, so at most twice this is kept
+const maxHistoryBytes = 1 << 20
+
+// HistoryPath returns the run history file, $XDG_CONFIG_HOME/taskporter/history.jsonl or
+// ~/.config/taskporter/history.jsonl. It holds one JSON HistoryEntry per line and never
+// leaves the machine; older entries are rotated out to history.jsonl.1.
+func HistoryPath() (string, error) {
+	return userConfigFile("history.jsonl")
+}
+
+// AppendHistory adds an entry to the history file, creating it if needed and rotating it once
+// it reaches maxHistoryBytes
+func AppendHistory(entry HistoryEntry) error {
+	path, err := HistoryPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() >= maxHistoryBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate history file: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// ReadHistory returns the entries recorded for the project root since the given time (all
+// entries for a zero time), oldest first, the rotated file included. A missing history file
+// is not an error, and lines that cannot be read, such as one cut short by a crash, are skipped.
+func ReadHistory(projectRoot string, since time.Time) ([]HistoryEntry, error) {
+	path, err := HistoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []HistoryEntry
+
+	for _, file := range []string{path + ".1", path} {
+		read, err := readHistoryFile(file, project, since)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, read...)
+	}
+
+	return entries, nil
+}
+
+// readHistoryFile returns the entries of one history file recorded for project since the given time
+func readHistoryFile(path, project string, since time.Time) ([]HistoryEntry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []HistoryEntry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+
+		if entry.Project != project || entry.Started.Before(since) {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return entries, nil
+}
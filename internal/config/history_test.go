@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	projectRoot := t.TempDir()
+	started := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should read nothing without a history file", func(t *testing.T) {
+		entries, err := ReadHistory(projectRoot, time.Time{})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("should read back the project's entries since a time", func(t *testing.T) {
+		require.NoError(t, AppendHistory(HistoryEntry{Project: projectRoot, Task: "build", Started: started, DurationMs: 1500}))
+		require.NoError(t, AppendHistory(HistoryEntry{Project: "/elsewhere", Task: "build", Started: started}))
+		require.NoError(t, AppendHistory(HistoryEntry{Project: projectRoot, Task: "test", Started: started.Add(time.Hour), ExitCode: 1}))
+
+		// A line cut short by a crash is skipped
+		path, err := HistoryPath()
+		require.NoError(t, err)
+
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		_, err = file.WriteString(`{"project": "` + filepath.ToSlash(projectRoot) + "\n")
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		entries, err := ReadHistory(projectRoot, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, "build", entries[0].Task)
+		require.Equal(t, 1500*time.Millisecond, entries[0].Duration())
+		require.Equal(t, 1, entries[1].ExitCode)
+
+		entries, err = ReadHistory(projectRoot, started.Add(time.Minute))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, "test", entries[0].Task)
+	})
+
+	t.Run("should rotate a full history file and keep reading it", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		path, err := HistoryPath()
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+
+		line, err := json.Marshal(HistoryEntry{Project: projectRoot, Task: "build", Started: started})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, append(line, '\n'), 0644))
+
+		full, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		require.NoError(t, full.Truncate(maxHistoryBytes))
+		require.NoError(t, full.Close())
+
+		require.NoError(t, AppendHistory(HistoryEntry{Project: projectRoot, Task: "test", Started: started}))
+
+		rotated, err := os.Stat(path + ".1")
+		require.NoError(t, err)
+		require.EqualValues(t, maxHistoryBytes, rotated.Size())
+
+		entries, err := ReadHistory(projectRoot, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, "build", entries[0].Task)
+		require.Equal(t, "test", entries[1].Task)
+	})
+}
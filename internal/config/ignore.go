@@ -0,0 +1,223 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFile lists paths, in gitignore syntax, that taskporter leaves out of discovery
+const IgnoreFile = ".taskporterignore"
+
+// IgnoreRule is one pattern line of an ignore file
+type IgnoreRule struct {
+	Pattern string // The line as written, e.g. "examples/**/.vscode/"
+	Source  string // Ignore file the line is from
+	Line    int
+
+	negate  bool
+	dirOnly bool
+	regex   *regexp.Regexp
+}
+
+// String describes where the rule comes from, e.g. ".taskporterignore:3: vendor/"
+func (r IgnoreRule) String() string {
+	return fmt.Sprintf("%s:%d: %s", filepath.Base(r.Source), r.Line, r.Pattern)
+}
+
+// IgnoreRules decides which project paths are left out of discovery. Like git, the last
+// matching rule wins, "!" re-includes a path, and nothing below an ignored directory can
+// be re-included.
+type IgnoreRules struct {
+	rules []IgnoreRule
+}
+
+// LoadIgnoreRules reads .taskporterignore from the project root and, with gitignore set, the
+// root .gitignore before it, so .taskporterignore can re-include what git ignores. Missing
+// files are not an error.
+func LoadIgnoreRules(ctx context.Context, projectRoot string, gitignore bool) (*IgnoreRules, error) {
+	files := []string{filepath.Join(projectRoot, IgnoreFile)}
+	if gitignore {
+		files = append([]string{filepath.Join(projectRoot, ".gitignore")}, files...)
+	}
+
+	rules := &IgnoreRules{}
+
+	for _, path := range files {
+		data, err := ReadFileContext(ctx, path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		var timeoutErr *TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return nil, err
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		rules.rules = append(rules.rules, ParseIgnoreRules(string(data), path).rules...)
+	}
+
+	return rules, nil
+}
+
+// ParseIgnoreRules parses ignore file content in gitignore syntax. Lines that cannot be
+// turned into a pattern are skipped.
+func ParseIgnoreRules(content string, source string) *IgnoreRules {
+	rules := &IgnoreRules{}
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		// Trailing spaces are insignificant unless escaped
+		if !strings.HasSuffix(line, `\ `) {
+			line = strings.TrimRight(line, " ")
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := IgnoreRule{Pattern: line, Source: source, Line: i + 1}
+		pattern := line
+
+		if strings.HasPrefix(pattern, "!") {
+			rule.negate = true
+			pattern = pattern[1:]
+		} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+			pattern = pattern[1:]
+		}
+
+		if strings.HasSuffix(pattern, "/") {
+			rule.dirOnly = true
+			pattern = strings.TrimRight(pattern, "/")
+		}
+
+		// A slash anywhere but the end anchors the pattern to the project root
+		anchored := strings.Contains(pattern, "/")
+		pattern = strings.TrimPrefix(pattern, "/")
+
+		if pattern == "" {
+			continue
+		}
+
+		expr := "^" + globToRegexp(pattern) + "$"
+		if !anchored {
+			expr = "^(?:.*/)?" + globToRegexp(pattern) + "$"
+		}
+
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			continue
+		}
+
+		rule.regex = regex
+		rules.rules = append(rules.rules, rule)
+	}
+
+	return rules
+}
+
+// Match reports whether path, relative to the project root, is ignored, and the rule that
+// decided it. A path below an ignored directory is ignored by that directory's rule.
+func (r *IgnoreRules) Match(path string, isDir bool) (IgnoreRule, bool) {
+	if r == nil || len(r.rules) == 0 {
+		return IgnoreRule{}, false
+	}
+
+	path = filepath.ToSlash(filepath.Clean(path))
+	parts := strings.Split(path, "/")
+
+	for i := 1; i < len(parts); i++ {
+		if rule, ignored := r.matchOne(strings.Join(parts[:i], "/"), true); ignored {
+			return rule, true
+		}
+	}
+
+	return r.matchOne(path, isDir)
+}
+
+// Empty reports whether there are no rules
+func (r *IgnoreRules) Empty() bool {
+	return r == nil || len(r.rules) == 0
+}
+
+// matchOne applies the rules to a single path, ignoring its parents
+func (r *IgnoreRules) matchOne(path string, isDir bool) (IgnoreRule, bool) {
+	var (
+		decided IgnoreRule
+		ignored bool
+	)
+
+	for _, rule := range r.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		if rule.regex.MatchString(path) {
+			decided, ignored = rule, !rule.negate
+		}
+	}
+
+	return decided, ignored
+}
+
+// globToRegexp translates a gitignore glob to a regular expression. "*" and "?" stay within
+// a path segment, "**" spans segments, and "[...]" classes are kept.
+func globToRegexp(glob string) string {
+	var expr strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+
+				// "**/" matches zero or more directories
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+
+					expr.WriteString("(?:.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+
+				continue
+			}
+
+			expr.WriteString("[^/]*")
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				expr.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return expr.String()
+}
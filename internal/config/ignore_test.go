@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreRules_Match(t *testing.T) {
+	rules := ParseIgnoreRules(`
+# Dependencies ship their own editor configs
+vendor/
+examples/**/.vscode/
+/.idea/runConfigurations/Template*.xml
+*.sublime-project
+!keep.sublime-project
+third_party/**
+`, IgnoreFile)
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+		rule    string
+	}{
+		{path: "vendor", isDir: true, ignored: true, rule: "vendor/"},
+		{path: "vendor/lib/.idea/runConfigurations/Run.xml", ignored: true, rule: "vendor/"},
+		{path: "services/vendor/.vscode/tasks.json", ignored: true, rule: "vendor/"},
+		{path: "vendor", isDir: false, ignored: false},
+		{path: "examples/.vscode/tasks.json", ignored: true, rule: "examples/**/.vscode/"},
+		{path: "examples/a/b/.vscode/launch.json", ignored: true, rule: "examples/**/.vscode/"},
+		{path: ".vscode/tasks.json", ignored: false},
+		{path: ".idea/runConfigurations/Template_Go.xml", ignored: true, rule: "/.idea/runConfigurations/Template*.xml"},
+		{path: "sub/.idea/runConfigurations/Template_Go.xml", ignored: false},
+		{path: ".idea/runConfigurations/App.xml", ignored: false},
+		{path: "app.sublime-project", ignored: true, rule: "*.sublime-project"},
+		{path: "keep.sublime-project", ignored: false},
+		{path: "third_party/x/.vscode/tasks.json", ignored: true, rule: "third_party/**"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rule, ignored := rules.Match(tt.path, tt.isDir)
+			require.Equal(t, tt.ignored, ignored)
+
+			if tt.ignored {
+				require.Equal(t, tt.rule, rule.Pattern)
+			}
+		})
+	}
+
+	t.Run("cannot re-include below an ignored directory", func(t *testing.T) {
+		rules := ParseIgnoreRules("vendor/\n!vendor/app/.vscode/tasks.json\n", IgnoreFile)
+
+		_, ignored := rules.Match("vendor/app/.vscode/tasks.json", false)
+		require.True(t, ignored)
+	})
+
+	t.Run("matches nothing without rules", func(t *testing.T) {
+		_, ignored := ParseIgnoreRules("# only a comment\n\n", IgnoreFile).Match(".vscode/tasks.json", false)
+		require.False(t, ignored)
+	})
+}
@@ -0,0 +1,436 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// InterpolationError is a ${...} reference in a value of a task defined in taskporter's config
+// files that cannot be resolved, naming the task and field holding it
+type InterpolationError struct {
+	Task      string
+	Field     string // e.g. "command", "args[1]" or "env.GOFLAGS"
+	Reference string // The reference without ${ and }, e.g. "tasks.build.cwd"
+	Err       error
+}
+
+// errUnterminatedReference is a ${ of the project or tasks namespace without its closing }
+var errUnterminatedReference = errors.New("missing closing }")
+
+// Error implements the error interface
+func (e *InterpolationError) Error() string {
+	if errors.Is(e.Err, errUnterminatedReference) {
+		return fmt.Sprintf("%s of '%s' has ${%s without a closing }", e.Field, e.Task, e.Reference)
+	}
+
+	return fmt.Sprintf("%s of '%s' references ${%s}: %v", e.Field, e.Task, e.Reference, e.Err)
+}
+
+// Unwrap returns why the reference cannot be resolved
+func (e *InterpolationError) Unwrap() error {
+	return e.Err
+}
+
+// InterpolateTaskExtensions resolves the ${project.root}, ${project.name} and
+// ${tasks.<name>.<field>} references in the command, args and env the extensions set, once the
+// whole catalog is known. Fields are cwd, command, args and env.<NAME>; an argument that is just
+// ${tasks.<name>.args} is replaced by all of that task's arguments. $${ stands for a literal ${.
+// Only values from taskporter's config files are interpolated: whatever an extension inherits
+// from an IDE configuration keeps that format's own variables. Extensions with references that
+// are unknown or form a cycle are left out and reported in the returned error.
+func InterpolateTaskExtensions(tasks []*Task, extensions []TaskExtension, projectRoot string) ([]*Task, error) {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		root = projectRoot
+	}
+
+	in := &interpolator{projectRoot: root, tasks: tasks, extensions: make(map[string]TaskExtension, len(extensions))}
+
+	// Only extensions ResolveTaskExtensions defined a task for take part
+	for _, extension := range extensions {
+		if task := FindTaskByName(tasks, extension.Name); task != nil && task.Extends != "" && task.Source == extension.Source {
+			in.extensions[strings.ToLower(extension.Name)] = extension
+		}
+	}
+
+	var errs []error
+
+	interpolated := make([]*Task, 0, len(tasks))
+
+	for _, task := range tasks {
+		extension, ok := in.extensions[strings.ToLower(task.Name)]
+		if !ok || task.Extends == "" {
+			interpolated = append(interpolated, task)
+			continue
+		}
+
+		if err := in.apply(task, extension); err != nil {
+			errs = append(errs, fmt.Errorf("task '%s' from %s cannot be defined: %w", extension.Name, extension.Source, err))
+			continue
+		}
+
+		interpolated = append(interpolated, task)
+	}
+
+	return interpolated, errors.Join(errs...)
+}
+
+// interpolator resolves references between tasks field by field, so only references that really
+// depend on each other count as a cycle
+type interpolator struct {
+	projectRoot string
+	tasks       []*Task
+	extensions  map[string]TaskExtension // By lowercased name
+	resolving   []string                 // "task.field" values being resolved, innermost last
+}
+
+// apply replaces the command, args and env of an extension's task with their resolved values
+func (in *interpolator) apply(task *Task, extension TaskExtension) error {
+	command, err := in.command(extension.Name)
+	if err != nil {
+		return err
+	}
+
+	args, err := in.args(extension.Name)
+	if err != nil {
+		return err
+	}
+
+	keys, err := in.envKeys(extension.Name)
+	if err != nil {
+		return err
+	}
+
+	var env map[string]string
+
+	if len(keys) > 0 {
+		env = make(map[string]string, len(keys))
+
+		for _, key := range keys {
+			if env[key], _, err = in.env(extension.Name, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	task.Command, task.Args, task.Env = command, args, env
+
+	return nil
+}
+
+// enter marks a field as being resolved, failing when it already is, and returns the func
+// unmarking it
+func (in *interpolator) enter(task, field string) (func(), error) {
+	ref := task + "." + field
+
+	for i, resolving := range in.resolving {
+		if strings.EqualFold(resolving, ref) {
+			return nil, fmt.Errorf("reference cycle %s", strings.Join(append(slices.Clone(in.resolving[i:]), ref), " → "))
+		}
+	}
+
+	in.resolving = append(in.resolving, ref)
+
+	return func() { in.resolving = in.resolving[:len(in.resolving)-1] }, nil
+}
+
+// lookup returns the extension named name, or else the task, or an error if there is neither
+func (in *interpolator) lookup(name string) (*TaskExtension, *Task, error) {
+	if extension, ok := in.extensions[strings.ToLower(name)]; ok {
+		return &extension, nil, nil
+	}
+
+	if task := FindTaskByName(in.tasks, name); task != nil {
+		return nil, task, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown task '%s'", name)
+}
+
+// command returns the resolved command of a task
+func (in *interpolator) command(name string) (string, error) {
+	extension, task, err := in.lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	if task != nil {
+		return task.Command, nil
+	}
+
+	if extension.Command == "" {
+		return in.command(extension.Extends)
+	}
+
+	leave, err := in.enter(extension.Name, "command")
+	if err != nil {
+		return "", err
+	}
+	defer leave()
+
+	return in.expand(extension.Command, extension.Name, "command")
+}
+
+// cwd returns the working directory of a task, which extensions inherit from their base
+func (in *interpolator) cwd(name string) (string, error) {
+	extension, task, err := in.lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	if task != nil {
+		return task.Cwd, nil
+	}
+
+	return in.cwd(extension.Extends)
+}
+
+// args returns the resolved arguments of a task: an extension's own follow its base's
+func (in *interpolator) args(name string) ([]string, error) {
+	extension, task, err := in.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if task != nil {
+		return task.Args, nil
+	}
+
+	leave, err := in.enter(extension.Name, "args")
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	args, err := in.args(extension.Extends)
+	if err != nil {
+		return nil, err
+	}
+
+	args = slices.Clone(args)
+
+	for i, arg := range extension.Args {
+		field := fmt.Sprintf("args[%d]", i)
+
+		// A lone reference to another task's args splices them in as separate arguments
+		if ref, ok := strings.CutPrefix(arg, "${tasks."); ok && strings.HasSuffix(ref, ".args}") && !strings.Contains(ref, "${") {
+			reference := strings.TrimSuffix("tasks."+ref, "}")
+
+			spliced, err := in.args(strings.TrimSuffix(ref, ".args}"))
+			if err != nil {
+				return nil, referenceError(extension.Name, field, reference, err)
+			}
+
+			args = append(args, spliced...)
+
+			continue
+		}
+
+		expanded, err := in.expand(arg, extension.Name, field)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, expanded)
+	}
+
+	return args, nil
+}
+
+// envKeys returns the names of every variable a task sets, sorted
+func (in *interpolator) envKeys(name string) ([]string, error) {
+	extension, task, err := in.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if task != nil {
+		return slices.Sorted(maps.Keys(task.Env)), nil
+	}
+
+	keys, err := in.envKeys(extension.Extends)
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range extension.Env {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// env returns the resolved value of a task's variable, and whether the task sets it
+func (in *interpolator) env(name, key string) (string, bool, error) {
+	extension, task, err := in.lookup(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	if task != nil {
+		value, ok := task.Env[key]
+		return value, ok, nil
+	}
+
+	value, ok := extension.Env[key]
+	if !ok {
+		return in.env(extension.Extends, key)
+	}
+
+	field := "env." + key
+
+	leave, err := in.enter(extension.Name, field)
+	if err != nil {
+		return "", false, err
+	}
+	defer leave()
+
+	expanded, err := in.expand(value, extension.Name, field)
+
+	return expanded, true, err
+}
+
+// expand resolves the references in one value of a task's field. Anything in ${...} outside the
+// project and tasks namespaces, such as ${workspaceFolder}, is left as written.
+func (in *interpolator) expand(value, task, field string) (string, error) {
+	owns := func(reference string) bool {
+		return strings.HasPrefix(reference, "tasks.") || strings.HasPrefix(reference, "project.")
+	}
+
+	expanded, reference, err := expandReferences(value, owns, in.resolve)
+	if err != nil {
+		return "", referenceError(task, field, reference, err)
+	}
+
+	return expanded, nil
+}
+
+// ExpandVariable replaces every ${name} in value with replacement. Like the references of task
+// extensions, $${ stands for a literal ${ and other ${...} are left as written.
+func ExpandVariable(value, name, replacement string) string {
+	owns := func(reference string) bool { return reference == name }
+	resolve := func(string) (string, error) { return replacement, nil }
+
+	expanded, _, err := expandReferences(value, owns, resolve)
+	if err != nil {
+		return value
+	}
+
+	return expanded
+}
+
+// expandReferences replaces the ${...} references in value that owns claims with their value
+// from resolve, leaving the others as written. $${ stands for a literal ${. When a reference
+// cannot be resolved, or has no closing }, it is returned along with the error.
+func expandReferences(value string, owns func(reference string) bool, resolve func(reference string) (string, error)) (string, string, error) {
+	var expanded strings.Builder
+
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			expanded.WriteString(value)
+			return expanded.String(), "", nil
+		}
+
+		// $${ is a literal ${
+		if start > 0 && value[start-1] == '$' {
+			expanded.WriteString(value[:start-1])
+			expanded.WriteString("${")
+			value = value[start+2:]
+
+			continue
+		}
+
+		expanded.WriteString(value[:start])
+		value = value[start+2:]
+
+		end := strings.Index(value, "}")
+		reference := value
+		if end >= 0 {
+			reference = value[:end]
+		}
+
+		if !owns(reference) {
+			expanded.WriteString("${")
+			continue
+		}
+
+		if end < 0 {
+			return "", reference, errUnterminatedReference
+		}
+
+		resolved, err := resolve(reference)
+		if err != nil {
+			return "", reference, err
+		}
+
+		expanded.WriteString(resolved)
+		value = value[end+1:]
+	}
+}
+
+// resolve returns the value of a project.* or tasks.<name>.<field> reference
+func (in *interpolator) resolve(reference string) (string, error) {
+	switch reference {
+	case "project.root":
+		return in.projectRoot, nil
+	case "project.name":
+		return filepath.Base(in.projectRoot), nil
+	}
+
+	rest, ok := strings.CutPrefix(reference, "tasks.")
+	if !ok {
+		return "", errors.New("unknown reference; project has root and name")
+	}
+
+	// Task names may contain dots, so the field is matched from the end
+	if i := strings.LastIndex(rest, ".env."); i > 0 {
+		key := rest[i+len(".env."):]
+
+		value, ok, err := in.env(rest[:i], key)
+		if err == nil && !ok {
+			err = fmt.Errorf("task '%s' sets no variable %s", rest[:i], key)
+		}
+
+		return value, err
+	}
+
+	i := strings.LastIndex(rest, ".")
+	if i <= 0 {
+		return "", errors.New("expected tasks.<name>.<field>")
+	}
+
+	name, field := rest[:i], rest[i+1:]
+
+	switch field {
+	case "command":
+		return in.command(name)
+	case "cwd":
+		return in.cwd(name)
+	case "args":
+		args, err := in.args(name)
+		return strings.Join(args, " "), err
+	default:
+		return "", fmt.Errorf("unknown field '%s'; tasks have cwd, command, args and env.<NAME>", field)
+	}
+}
+
+// referenceError reports a reference that cannot be resolved. The innermost error already
+// names the task and field the problem is in, so it is passed on as it is.
+func referenceError(task, field, reference string, err error) error {
+	var interpolationErr *InterpolationError
+	if errors.As(err, &interpolationErr) {
+		return err
+	}
+
+	return &InterpolationError{Task: task, Field: field, Reference: reference, Err: err}
+}
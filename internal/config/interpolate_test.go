@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolateTaskExtensions(t *testing.T) {
+	projectRoot := filepath.Join(string(filepath.Separator), "work", "shop")
+
+	newTasks := func() []*Task {
+		return []*Task{{
+			Name:    "build",
+			Type:    TypeVSCodeTask,
+			Command: "go",
+			Args:    []string{"build", "-o", "${workspaceFolder}/bin/shop", "./cmd/shop"},
+			Cwd:     filepath.Join(projectRoot, "services", "shop"),
+			Env:     map[string]string{"GOFLAGS": "-mod=vendor"},
+			Source:  ".vscode/tasks.json",
+		}}
+	}
+
+	// resolve defines the extensions over the build task, then interpolates them
+	resolve := func(t *testing.T, extensions ...TaskExtension) ([]*Task, error) {
+		t.Helper()
+
+		for i := range extensions {
+			extensions[i].Source = ProjectConfigFile
+		}
+
+		tasks, err := ResolveTaskExtensions(newTasks(), extensions)
+		require.NoError(t, err)
+
+		return InterpolateTaskExtensions(tasks, extensions, projectRoot)
+	}
+
+	t.Run("should resolve project and task references in command, args and env", func(t *testing.T) {
+		tasks, err := resolve(t, TaskExtension{
+			Name:    "watch",
+			Extends: "build",
+			Command: "reflex",
+			Args:    []string{"-d", "${tasks.build.cwd}", "--", "${tasks.build.command}", "${tasks.build.args}"},
+			Env:     map[string]string{"SHOP_ROOT": "${project.root}", "GOFLAGS": "${tasks.build.env.GOFLAGS} -race", "APP": "${project.name}"},
+		})
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+
+		watch := tasks[1]
+		require.Equal(t, "reflex", watch.Command)
+		require.Equal(t, []string{
+			"build", "-o", "${workspaceFolder}/bin/shop", "./cmd/shop",
+			"-d", filepath.Join(projectRoot, "services", "shop"), "--", "go", "build", "-o", "${workspaceFolder}/bin/shop", "./cmd/shop",
+		}, watch.Args, "a lone args reference splices the arguments in, and IDE variables are left alone")
+		require.Equal(t, map[string]string{"SHOP_ROOT": projectRoot, "GOFLAGS": "-mod=vendor -race", "APP": "shop"}, watch.Env)
+
+		require.Equal(t, "go", tasks[0].Command, "tasks from IDE formats are not interpolated")
+	})
+
+	t.Run("should resolve chained references whichever order extensions are listed in", func(t *testing.T) {
+		tasks, err := resolve(t,
+			TaskExtension{Name: "deploy", Extends: "build", Command: "${tasks.package.command} --push"},
+			TaskExtension{Name: "package", Extends: "build", Command: "docker build -t shop ${tasks.build.cwd}"},
+			TaskExtension{Name: "deploy-staging", Extends: "deploy", Args: []string{"--tag=${tasks.deploy.env.GOFLAGS}"}},
+		)
+		require.NoError(t, err)
+
+		byName := make(map[string]*Task)
+		for _, task := range tasks {
+			byName[task.Name] = task
+		}
+
+		shopDir := filepath.Join(projectRoot, "services", "shop")
+		require.Equal(t, "docker build -t shop "+shopDir, byName["package"].Command)
+		require.Equal(t, "docker build -t shop "+shopDir+" --push", byName["deploy"].Command)
+		require.Equal(t, byName["deploy"].Command, byName["deploy-staging"].Command, "resolved values are inherited")
+		require.Equal(t, "--tag=-mod=vendor", byName["deploy-staging"].Args[len(byName["deploy-staging"].Args)-1])
+	})
+
+	t.Run("should keep an escaped ${ literally", func(t *testing.T) {
+		tasks, err := resolve(t, TaskExtension{Name: "echo", Extends: "build", Command: "echo", Args: []string{"$${tasks.build.cwd} is ${tasks.build.cwd}"}})
+		require.NoError(t, err)
+		require.Equal(t, "${tasks.build.cwd} is "+filepath.Join(projectRoot, "services", "shop"), tasks[1].Args[len(tasks[1].Args)-1])
+	})
+
+	t.Run("should leave out extensions in a reference cycle, naming the task and field", func(t *testing.T) {
+		tasks, err := resolve(t,
+			TaskExtension{Name: "a", Extends: "build", Command: "${tasks.b.command}"},
+			TaskExtension{Name: "b", Extends: "build", Command: "run ${tasks.a.command}"},
+			TaskExtension{Name: "c", Extends: "build", Env: map[string]string{"SELF": "${tasks.c.env.SELF}"}},
+			TaskExtension{Name: "ok", Extends: "build", Args: []string{"${tasks.a.cwd}"}},
+		)
+		require.Len(t, tasks, 2, "only build and ok are defined")
+		require.Equal(t, "ok", tasks[1].Name)
+
+		require.EqualError(t, err, "task 'a' from .taskporter.json cannot be defined: command of 'b' references ${tasks.a.command}: reference cycle a.command → b.command → a.command\n"+
+			"task 'b' from .taskporter.json cannot be defined: command of 'a' references ${tasks.b.command}: reference cycle b.command → a.command → b.command\n"+
+			"task 'c' from .taskporter.json cannot be defined: env.SELF of 'c' references ${tasks.c.env.SELF}: reference cycle c.env.SELF → c.env.SELF")
+	})
+
+	t.Run("should name unknown tasks, fields, variables and references", func(t *testing.T) {
+		tests := []struct {
+			arg      string
+			expected string
+		}{
+			{"${tasks.biuld.cwd}", "args[0] of 'x' references ${tasks.biuld.cwd}: unknown task 'biuld'"},
+			{"${tasks.build.dir}", "args[0] of 'x' references ${tasks.build.dir}: unknown field 'dir'; tasks have cwd, command, args and env.<NAME>"},
+			{"${tasks.build.env.HOME}", "args[0] of 'x' references ${tasks.build.env.HOME}: task 'build' sets no variable HOME"},
+			{"${project.path}", "args[0] of 'x' references ${project.path}: unknown reference; project has root and name"},
+			{"${tasks.build}", "args[0] of 'x' references ${tasks.build}: expected tasks.<name>.<field>"},
+			{"${tasks.build.cwd", "args[0] of 'x' has ${tasks.build.cwd without a closing }"},
+		}
+
+		for _, tt := range tests {
+			tasks, err := resolve(t, TaskExtension{Name: "x", Extends: "build", Args: []string{tt.arg}})
+			require.Len(t, tasks, 1, tt.arg)
+
+			var interpolationErr *InterpolationError
+			require.True(t, errors.As(err, &interpolationErr), tt.arg)
+			require.EqualError(t, interpolationErr, tt.expected)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultOperationTimeout bounds each file operation of discovery, parsing and conversion, so a
+// hung network filesystem fails the command instead of blocking it forever
+const DefaultOperationTimeout = 30 * time.Second
+
+// TimeoutError reports a file operation abandoned because its deadline passed or its context was
+// cancelled while it was still waiting on the filesystem
+type TimeoutError struct {
+	Op   string // e.g. "reading"
+	Path string
+	Err  error
+}
+
+// Error implements the error interface
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out %s %s", e.Op, e.Path)
+}
+
+// Unwrap returns the context error
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// operationKey is the context key of the operation a context bounds
+type operationKey struct{}
+
+// operation holds the deadline of each file operation and remembers the first one that timed
+// out, so the command can name the slow file even when the parser that hit it only logged a warning
+type operation struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	slowest *TimeoutError
+}
+
+// WithOperationTimeout returns a context under which every file operation is abandoned once it
+// has taken longer than timeout. Each operation gets a deadline of its own, so time spent between
+// them, such as waiting for an answer to a prompt, never counts. A zero timeout never abandons them.
+func WithOperationTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.WithValue(parent, operationKey{}, &operation{timeout: timeout}))
+}
+
+// OperationErr returns the first file operation under ctx that timed out, naming its file, or
+// why ctx was cancelled, and nil otherwise
+func OperationErr(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+
+	if op, ok := ctx.Value(operationKey{}).(*operation); ok {
+		op.mu.Lock()
+		defer op.mu.Unlock()
+
+		if op.slowest != nil {
+			return fmt.Errorf("%w (--op-timeout %s)", op.slowest, op.timeout)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// ReadFileContext is os.ReadFile abandoned when ctx is done
+func ReadFileContext(ctx context.Context, path string) ([]byte, error) {
+	return withContext(ctx, "reading", path, func() ([]byte, error) {
+		return os.ReadFile(path)
+	})
+}
+
+// ReadDirContext is os.ReadDir abandoned when ctx is done
+func ReadDirContext(ctx context.Context, path string) ([]os.DirEntry, error) {
+	return withContext(ctx, "reading", path, func() ([]os.DirEntry, error) {
+		return os.ReadDir(path)
+	})
+}
+
+// StatContext is os.Stat abandoned when ctx is done
+func StatContext(ctx context.Context, path string) (fs.FileInfo, error) {
+	return withContext(ctx, "reading", path, func() (fs.FileInfo, error) {
+		return os.Stat(path)
+	})
+}
+
+// withContext runs fn, returning a TimeoutError if ctx is done or the operation's timeout passes
+// first. A blocked system call can't be interrupted, so fn keeps running in the background and
+// its result is discarded.
+func withContext[T any](ctx context.Context, op, path string, fn func() (T, error)) (T, error) {
+	var zero T
+
+	if ctx == nil {
+		return fn()
+	}
+
+	tracked, _ := ctx.Value(operationKey{}).(*operation)
+	if tracked != nil && tracked.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, tracked.timeout)
+		defer cancel()
+	}
+
+	if ctx.Done() == nil {
+		return fn()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return zero, &TimeoutError{Op: op, Path: path, Err: err}
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		timeoutErr := &TimeoutError{Op: op, Path: path, Err: ctx.Err()}
+
+		if tracked != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			tracked.mu.Lock()
+			if tracked.slowest == nil {
+				tracked.slowest = timeoutErr
+			}
+			tracked.mu.Unlock()
+		}
+
+		return zero, timeoutErr
+	}
+}
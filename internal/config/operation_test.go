@@ -0,0 +1,81 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationTimeout(t *testing.T) {
+	t.Run("should read files while the operation is running", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0o600))
+
+		ctx, cancel := WithOperationTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		data, err := ReadFileContext(ctx, path)
+		require.NoError(t, err)
+		require.Equal(t, "{}", string(data))
+		require.NoError(t, OperationErr(ctx))
+	})
+
+	t.Run("should give up on a read that outlasts the timeout and name its file", func(t *testing.T) {
+		ctx, cancel := WithOperationTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		// Stands in for a read blocked on a hung network filesystem
+		_, err := withContext(ctx, "reading", "/mnt/nfs/.vscode/tasks.json", func() ([]byte, error) {
+			<-release
+			return nil, nil
+		})
+
+		var timeoutErr *TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.Equal(t, "timed out reading /mnt/nfs/.vscode/tasks.json", err.Error())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.EqualError(t, OperationErr(ctx), "timed out reading /mnt/nfs/.vscode/tasks.json (--op-timeout 10ms)")
+	})
+
+	t.Run("should time each operation on its own", func(t *testing.T) {
+		ctx, cancel := WithOperationTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// Stands in for a prompt or slow work between two reads
+		time.Sleep(100 * time.Millisecond)
+
+		_, err := ReadDirContext(ctx, t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, OperationErr(ctx))
+	})
+
+	t.Run("should fail reads once the operation is cancelled", func(t *testing.T) {
+		ctx, cancel := WithOperationTimeout(context.Background(), time.Minute)
+		cancel()
+
+		_, err := ReadDirContext(ctx, t.TempDir())
+		require.True(t, errors.Is(err, context.Canceled))
+		require.ErrorIs(t, OperationErr(ctx), context.Canceled)
+	})
+
+	t.Run("should never time out with a zero timeout", func(t *testing.T) {
+		ctx, cancel := WithOperationTimeout(context.Background(), 0)
+		defer cancel()
+
+		_, hasDeadline := ctx.Deadline()
+		require.False(t, hasDeadline)
+
+		_, err := StatContext(ctx, t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, OperationErr(ctx))
+	})
+}
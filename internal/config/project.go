@@ -2,8 +2,10 @@ package config
 
 // ProjectConfig represents the overall project configuration
 type ProjectConfig struct {
-	ProjectRoot  string  `json:"project_root"`
-	Tasks        []*Task `json:"tasks"`
-	HasVSCode    bool    `json:"has_vscode"`
-	HasJetBrains bool    `json:"has_jetbrains"`
+	ProjectRoot      string  `json:"project_root"`
+	Tasks            []*Task `json:"tasks"`
+	HasVSCode        bool    `json:"has_vscode"`
+	HasJetBrains     bool    `json:"has_jetbrains"`
+	HasSublime       bool    `json:"has_sublime"`
+	HasGitHubActions bool    `json:"has_github_actions"`
 }
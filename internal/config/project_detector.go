@@ -1,13 +1,28 @@
 package config
 
 import (
-	"os"
+	"context"
+	"errors"
+	"io/fs"
 	"path/filepath"
+	"sort"
 )
 
 // ProjectDetector handles detection of IDE configuration files
 type ProjectDetector struct {
+	ctx         context.Context
 	projectRoot string
+	gitignore   bool
+	ignore      *IgnoreRules          // Loaded on first use
+	ignoreErr   error                 // Reported by DetectProject
+	timeoutErr  error                 // First file operation the context cut short, reported by DetectProject
+	ignored     map[string]IgnoreRule // Paths left out of discovery, relative to the project root
+}
+
+// IgnoredPath is a configuration path left out of discovery by an ignore rule
+type IgnoredPath struct {
+	Path string // Relative to the project root
+	Rule IgnoreRule
 }
 
 // NewProjectDetector creates a new project detector for the given directory
@@ -23,12 +38,49 @@ func NewProjectDetector(projectRoot string) *ProjectDetector {
 	}
 
 	return &ProjectDetector{
+		ctx:         context.Background(),
 		projectRoot: abs,
+		ignored:     make(map[string]IgnoreRule),
 	}
 }
 
+// SetRespectGitignore also leaves out the paths the project root's .gitignore ignores.
+// .taskporterignore is always respected and can re-include them.
+func (pd *ProjectDetector) SetRespectGitignore(gitignore bool) {
+	pd.gitignore = gitignore
+	pd.ignore = nil
+}
+
+// SetContext bounds the detector's file operations by ctx, e.g. an --op-timeout deadline
+func (pd *ProjectDetector) SetContext(ctx context.Context) {
+	pd.ctx = ctx
+}
+
+// Context returns the context bounding discovery, for the parsers of the detected files
+func (pd *ProjectDetector) Context() context.Context {
+	return pd.ctx
+}
+
+// IgnoredPaths returns the configuration paths ignore rules left out so far, sorted by path
+func (pd *ProjectDetector) IgnoredPaths() []IgnoredPath {
+	paths := make([]IgnoredPath, 0, len(pd.ignored))
+	for path, rule := range pd.ignored {
+		paths = append(paths, IgnoredPath{Path: path, Rule: rule})
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i].Path < paths[j].Path
+	})
+
+	return paths
+}
+
 // DetectProject scans for IDE configuration files and returns project config
 func (pd *ProjectDetector) DetectProject() (*ProjectConfig, error) {
+	if pd.loadIgnore(); pd.ignoreErr != nil {
+		return nil, pd.ignoreErr
+	}
+
 	config := &ProjectConfig{
 		ProjectRoot: pd.projectRoot,
 		Tasks:       []*Task{},
@@ -49,6 +101,20 @@ func (pd *ProjectDetector) DetectProject() (*ProjectConfig, error) {
 		}
 	}
 
+	// Check for Sublime Text project files
+	if len(pd.GetSublimeProjectPaths()) > 0 {
+		config.HasSublime = true
+	}
+
+	// Check for GitHub Actions workflows
+	if len(pd.GetGitHubWorkflowPaths()) > 0 {
+		config.HasGitHubActions = true
+	}
+
+	if pd.timeoutErr != nil {
+		return nil, pd.timeoutErr
+	}
+
 	return config, nil
 }
 
@@ -72,7 +138,18 @@ func (pd *ProjectDetector) GetVSCodeLaunchPath() string {
 	return ""
 }
 
-// GetJetBrainsRunConfigPaths returns paths to all JetBrains run configuration files
+// GetVSCodeSettingsPath returns the path to VSCode settings.json if it exists
+func (pd *ProjectDetector) GetVSCodeSettingsPath() string {
+	path := filepath.Join(pd.projectRoot, ".vscode", "settings.json")
+	if pd.fileExists(path) {
+		return path
+	}
+
+	return ""
+}
+
+// GetJetBrainsRunConfigPaths returns paths to all JetBrains run configuration files. IntelliJ
+// only loads the files directly in runConfigurations, so subfolders are left out.
 func (pd *ProjectDetector) GetJetBrainsRunConfigPaths() []string {
 	var paths []string
 
@@ -82,14 +159,55 @@ func (pd *ProjectDetector) GetJetBrainsRunConfigPaths() []string {
 		return paths
 	}
 
-	entries, err := os.ReadDir(runConfigsDir)
+	entries, err := pd.readDir(runConfigsDir)
+	if err != nil {
+		return paths
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(runConfigsDir, entry.Name())
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".xml" && !pd.isIgnored(path, false) {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// GetSublimeProjectPaths returns paths to all Sublime Text .sublime-project files in the project root
+func (pd *ProjectDetector) GetSublimeProjectPaths() []string {
+	var paths []string
+
+	entries, err := pd.readDir(pd.projectRoot)
+	if err != nil {
+		return paths
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(pd.projectRoot, entry.Name())
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".sublime-project" && !pd.isIgnored(path, false) {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// GetGitHubWorkflowPaths returns paths to all GitHub Actions workflow files in .github/workflows
+func (pd *ProjectDetector) GetGitHubWorkflowPaths() []string {
+	var paths []string
+
+	entries, err := pd.readDir(filepath.Join(pd.projectRoot, ".github", "workflows"))
 	if err != nil {
 		return paths
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".xml" {
-			paths = append(paths, filepath.Join(runConfigsDir, entry.Name()))
+		path := filepath.Join(pd.projectRoot, ".github", "workflows", entry.Name())
+		ext := filepath.Ext(entry.Name())
+
+		if !entry.IsDir() && (ext == ".yml" || ext == ".yaml") && !pd.isIgnored(path, false) {
+			paths = append(paths, path)
 		}
 	}
 
@@ -98,11 +216,62 @@ func (pd *ProjectDetector) GetJetBrainsRunConfigPaths() []string {
 
 // Helper functions
 func (pd *ProjectDetector) fileExists(path string) bool {
-	info, err := os.Stat(path)
-	return err == nil && !info.IsDir()
+	info, err := pd.stat(path)
+	return err == nil && !info.IsDir() && !pd.isIgnored(path, false)
 }
 
 func (pd *ProjectDetector) dirExists(path string) bool {
-	info, err := os.Stat(path)
-	return err == nil && info.IsDir()
+	info, err := pd.stat(path)
+	return err == nil && info.IsDir() && !pd.isIgnored(path, true)
+}
+
+// stat is StatContext remembering a timeout for DetectProject
+func (pd *ProjectDetector) stat(path string) (fs.FileInfo, error) {
+	info, err := StatContext(pd.ctx, path)
+	pd.recordTimeout(err)
+
+	return info, err
+}
+
+// readDir is ReadDirContext remembering a timeout for DetectProject
+func (pd *ProjectDetector) readDir(path string) ([]fs.DirEntry, error) {
+	entries, err := ReadDirContext(pd.ctx, path)
+	pd.recordTimeout(err)
+
+	return entries, err
+}
+
+// recordTimeout keeps err when it is the first timeout, so detection names the slow path
+// rather than reporting the files behind it as missing
+func (pd *ProjectDetector) recordTimeout(err error) {
+	var timeoutErr *TimeoutError
+	if pd.timeoutErr == nil && errors.As(err, &timeoutErr) {
+		pd.timeoutErr = err
+	}
+}
+
+// loadIgnore reads the ignore rules the first time they are needed
+func (pd *ProjectDetector) loadIgnore() {
+	if pd.ignore == nil && pd.ignoreErr == nil {
+		pd.ignore, pd.ignoreErr = LoadIgnoreRules(pd.ctx, pd.projectRoot, pd.gitignore)
+	}
+}
+
+// isIgnored reports whether an ignore rule leaves path out, remembering it for IgnoredPaths
+func (pd *ProjectDetector) isIgnored(path string, isDir bool) bool {
+	if pd.loadIgnore(); pd.ignore.Empty() {
+		return false
+	}
+
+	rel, err := filepath.Rel(pd.projectRoot, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return false
+	}
+
+	rule, ignored := pd.ignore.Match(rel, isDir)
+	if ignored {
+		pd.ignored[filepath.ToSlash(rel)] = rule
+	}
+
+	return ignored
 }
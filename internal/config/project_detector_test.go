@@ -137,6 +137,26 @@ func TestProjectDetector(t *testing.T) {
 		})
 	})
 
+	t.Run("GetVSCodeSettingsPath", func(t *testing.T) {
+		t.Run("when settings.json exists", func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			vscodeDir := filepath.Join(tempDir, ".vscode")
+			require.NoError(t, os.MkdirAll(vscodeDir, 0755))
+
+			settingsFile := filepath.Join(vscodeDir, "settings.json")
+			require.NoError(t, os.WriteFile(settingsFile, []byte("{}"), 0644))
+
+			detector := NewProjectDetector(tempDir)
+			require.Equal(t, settingsFile, detector.GetVSCodeSettingsPath())
+		})
+
+		t.Run("when settings.json doesn't exist", func(t *testing.T) {
+			detector := NewProjectDetector(t.TempDir())
+			require.Empty(t, detector.GetVSCodeSettingsPath())
+		})
+	})
+
 	t.Run("GetJetBrainsRunConfigPaths", func(t *testing.T) {
 		t.Run("with XML configuration files", func(t *testing.T) {
 			tempDir := t.TempDir()
@@ -167,6 +187,18 @@ func TestProjectDetector(t *testing.T) {
 			}
 		})
 
+		t.Run("with configurations in subfolders", func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			runConfigsDir := filepath.Join(tempDir, ".idea", "runConfigurations")
+			require.NoError(t, os.MkdirAll(filepath.Join(runConfigsDir, "tests"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(runConfigsDir, "App.xml"), []byte("<configuration/>"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(runConfigsDir, "tests", "Unit.xml"), []byte("<configuration/>"), 0644))
+
+			detector := NewProjectDetector(tempDir)
+			require.Equal(t, []string{filepath.Join(runConfigsDir, "App.xml")}, detector.GetJetBrainsRunConfigPaths())
+		})
+
 		t.Run("without runConfigurations directory", func(t *testing.T) {
 			tempDir := t.TempDir()
 
@@ -176,4 +208,110 @@ func TestProjectDetector(t *testing.T) {
 			require.Len(t, paths, 0)
 		})
 	})
+
+	t.Run("GetSublimeProjectPaths", func(t *testing.T) {
+		t.Run("with sublime-project files", func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			projectFile := filepath.Join(tempDir, "app.sublime-project")
+			require.NoError(t, os.WriteFile(projectFile, []byte("{}"), 0644))
+
+			// Workspace files hold editor state, not build systems
+			workspaceFile := filepath.Join(tempDir, "app.sublime-workspace")
+			require.NoError(t, os.WriteFile(workspaceFile, []byte("{}"), 0644))
+
+			detector := NewProjectDetector(tempDir)
+			require.Equal(t, []string{projectFile}, detector.GetSublimeProjectPaths())
+
+			projectConfig, err := detector.DetectProject()
+			require.NoError(t, err)
+			require.True(t, projectConfig.HasSublime)
+		})
+
+		t.Run("without sublime-project files", func(t *testing.T) {
+			detector := NewProjectDetector(t.TempDir())
+			require.Empty(t, detector.GetSublimeProjectPaths())
+
+			projectConfig, err := detector.DetectProject()
+			require.NoError(t, err)
+			require.False(t, projectConfig.HasSublime)
+		})
+	})
+
+	t.Run("GetGitHubWorkflowPaths", func(t *testing.T) {
+		t.Run("with workflow files", func(t *testing.T) {
+			tempDir := t.TempDir()
+			workflowsDir := filepath.Join(tempDir, ".github", "workflows")
+			require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+
+			ci := filepath.Join(workflowsDir, "ci.yml")
+			release := filepath.Join(workflowsDir, "release.yaml")
+
+			for _, path := range []string{ci, release, filepath.Join(workflowsDir, "README.md")} {
+				require.NoError(t, os.WriteFile(path, []byte("jobs: {}\n"), 0644))
+			}
+
+			detector := NewProjectDetector(tempDir)
+			require.Equal(t, []string{ci, release}, detector.GetGitHubWorkflowPaths())
+
+			projectConfig, err := detector.DetectProject()
+			require.NoError(t, err)
+			require.True(t, projectConfig.HasGitHubActions)
+		})
+
+		t.Run("without workflow files", func(t *testing.T) {
+			detector := NewProjectDetector(t.TempDir())
+			require.Empty(t, detector.GetGitHubWorkflowPaths())
+
+			projectConfig, err := detector.DetectProject()
+			require.NoError(t, err)
+			require.False(t, projectConfig.HasGitHubActions)
+		})
+	})
+
+	t.Run("ignore rules", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		writeFile := func(path, content string) {
+			t.Helper()
+
+			path = filepath.Join(tempDir, path)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		}
+
+		writeFile(".vscode/tasks.json", "{}")
+		writeFile(".idea/runConfigurations/App.xml", "<configuration/>")
+		writeFile(".idea/runConfigurations/Example_Template.xml", "<configuration/>")
+		writeFile("app.sublime-project", "{}")
+		writeFile(IgnoreFile, "# Templates shipped by the framework\n.idea/runConfigurations/Example_*\n*.sublime-project\n")
+		writeFile(".gitignore", ".vscode/\n")
+
+		t.Run("should leave out paths matched by .taskporterignore", func(t *testing.T) {
+			detector := NewProjectDetector(tempDir)
+
+			projectConfig, err := detector.DetectProject()
+			require.NoError(t, err)
+			require.True(t, projectConfig.HasVSCode)
+			require.False(t, projectConfig.HasSublime)
+
+			require.Equal(t, []string{filepath.Join(tempDir, ".idea", "runConfigurations", "App.xml")}, detector.GetJetBrainsRunConfigPaths())
+
+			ignored := detector.IgnoredPaths()
+			require.Len(t, ignored, 2)
+			require.Equal(t, ".idea/runConfigurations/Example_Template.xml", ignored[0].Path)
+			require.Equal(t, ".taskporterignore:2: .idea/runConfigurations/Example_*", ignored[0].Rule.String())
+			require.Equal(t, "app.sublime-project", ignored[1].Path)
+		})
+
+		t.Run("should leave out paths matched by .gitignore only when asked", func(t *testing.T) {
+			detector := NewProjectDetector(tempDir)
+			detector.SetRespectGitignore(true)
+
+			projectConfig, err := detector.DetectProject()
+			require.NoError(t, err)
+			require.False(t, projectConfig.HasVSCode)
+			require.Empty(t, detector.GetVSCodeTasksPath())
+		})
+	})
 }
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// ShConfigurationType is the JetBrains type of shell script run configurations
+const ShConfigurationType = "ShConfigurationType"
+
+// RunConfigTemplate holds the defaults IntelliJ gives new run configurations of one type, as set
+// under "Edit configuration templates"
+type RunConfigTemplate struct {
+	Type     string
+	Source   string // File the template was read from
+	Options  []TemplateValue
+	EnvVars  []TemplateValue
+	Settings []TemplateSetting // External system (Gradle) settings
+}
+
+// TemplateValue is an option or environment variable of a run configuration template
+type TemplateValue struct {
+	Name  string
+	Value string
+}
+
+// TemplateSetting is an external system setting of a run configuration template, holding a
+// value or a map such as env
+type TemplateSetting struct {
+	Name  string
+	Value string
+	Map   []TemplateValue
+}
+
+// RunConfigTemplates holds a project's run configuration templates by configuration type
+type RunConfigTemplates map[string]*RunConfigTemplate
+
+// runConfigTemplateOption is an <option> element of a template, holding a value or a map
+type runConfigTemplateOption struct {
+	Name    string                   `xml:"name,attr"`
+	Value   string                   `xml:"value,attr"`
+	Entries []runConfigTemplateEntry `xml:"map>entry"`
+}
+
+// runConfigTemplateEntry is an <entry> of an option's map
+type runConfigTemplateEntry struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// runConfigTemplateEnv is an <env> element of a template
+type runConfigTemplateEnv struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// runConfigTemplateElement is a <configuration> element that may be a template
+type runConfigTemplateElement struct {
+	Type     string                    `xml:"type,attr"`
+	Default  string                    `xml:"default,attr"`
+	Options  []runConfigTemplateOption `xml:"option"`
+	EnvVars  []runConfigTemplateEnv    `xml:"envs>env"`
+	Settings []runConfigTemplateOption `xml:"ExternalSystemSettings>option"`
+}
+
+// IsRunConfigTemplateFile reports whether a run configuration file holds a template by its name,
+// e.g. "_template__of_Application.xml"
+func IsRunConfigTemplateFile(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), "_template")
+}
+
+// LoadRunConfigTemplates reads the run configuration templates of the project: the
+// default="true" configurations of .idea/workspace.xml, overridden by the shared ones under
+// .idea/runConfigurations (_template__*.xml files or default="true" configurations). A project
+// without templates has none, which is not an error.
+func LoadRunConfigTemplates(ctx context.Context, projectRoot string) (RunConfigTemplates, error) {
+	templates := make(RunConfigTemplates)
+
+	workspacePath := filepath.Join(projectRoot, ".idea", "workspace.xml")
+	if err := templates.loadFile(ctx, workspacePath, false); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	runConfigsDir := filepath.Join(projectRoot, ".idea", "runConfigurations")
+
+	err := filepath.WalkDir(runConfigsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return &TimeoutError{Op: "reading", Path: path, Err: err}
+		}
+
+		if entry.IsDir() || filepath.Ext(path) != ".xml" {
+			return nil
+		}
+
+		return templates.loadFile(ctx, path, IsRunConfigTemplateFile(path))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// loadFile adds the templates in an XML file. Every configuration of a template file is a
+// template; elsewhere only those marked default="true" are.
+func (t RunConfigTemplates) loadFile(ctx context.Context, path string, templateFile bool) error {
+	data, err := ReadFileContext(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to read run configuration templates: %w", err)
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to parse XML in %s: %w", path, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "configuration" {
+			continue
+		}
+
+		var element runConfigTemplateElement
+		if err := decoder.DecodeElement(&element, &start); err != nil {
+			return fmt.Errorf("failed to parse XML in %s: %w", path, err)
+		}
+
+		if element.Type == "" || (!templateFile && element.Default != "true") {
+			continue
+		}
+
+		template := &RunConfigTemplate{Type: element.Type, Source: path}
+
+		// Empty values are what the IDE uses anyway, so only set ones are defaults worth keeping
+		for _, option := range element.Options {
+			if option.Value != "" {
+				template.Options = append(template.Options, TemplateValue{Name: option.Name, Value: option.Value})
+			}
+		}
+
+		for _, env := range element.EnvVars {
+			template.EnvVars = append(template.EnvVars, TemplateValue{Name: env.Name, Value: env.Value})
+		}
+
+		for _, option := range element.Settings {
+			if option.Value == "" && len(option.Entries) == 0 {
+				continue
+			}
+
+			setting := TemplateSetting{Name: option.Name, Value: option.Value}
+			for _, entry := range option.Entries {
+				setting.Map = append(setting.Map, TemplateValue{Name: entry.Key, Value: entry.Value})
+			}
+
+			template.Settings = append(template.Settings, setting)
+		}
+
+		t[element.Type] = template
+	}
+}
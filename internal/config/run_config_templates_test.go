@@ -0,0 +1,114 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTemplateProject writes a project with run configuration templates in workspace.xml and
+// under .idea/runConfigurations
+func writeTemplateProject(t *testing.T) string {
+	t.Helper()
+
+	projectRoot := t.TempDir()
+	runConfigsDir := filepath.Join(projectRoot, ".idea", "runConfigurations")
+	require.NoError(t, os.MkdirAll(runConfigsDir, 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".idea", "workspace.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<project version="4">
+  <component name="RunManager">
+    <configuration default="true" type="Application" factoryName="Application">
+      <option name="VM_PARAMETERS" value="-Xmx256m" />
+    </configuration>
+    <configuration default="true" type="ShConfigurationType">
+      <option name="INTERPRETER_PATH" value="/bin/bash" />
+      <option name="SCRIPT_OPTIONS" value="" />
+      <envs>
+        <env name="LANG" value="C.UTF-8" />
+      </envs>
+    </configuration>
+    <configuration name="Build" type="ShConfigurationType">
+      <option name="SCRIPT_TEXT" value="make" />
+    </configuration>
+  </component>
+</project>
+`), 0644))
+
+	require.NoError(t, os.WriteFile(filepath.Join(runConfigsDir, "_template__of_Application.xml"), []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration type="Application" factoryName="Application">
+    <option name="VM_PARAMETERS" value="-Xmx1g -Dprofile=dev" />
+    <envs>
+      <env name="JAVA_TOOL_OPTIONS" value="-Dfile.encoding=UTF-8" />
+    </envs>
+  </configuration>
+</component>
+`), 0644))
+
+	require.NoError(t, os.WriteFile(filepath.Join(runConfigsDir, "Server.xml"), []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration name="Server" type="Application" factoryName="Application">
+    <option name="MAIN_CLASS_NAME" value="com.example.Server" />
+  </configuration>
+</component>
+`), 0644))
+
+	return projectRoot
+}
+
+func TestRunConfigTemplates(t *testing.T) {
+	t.Run("should read templates from workspace.xml and template files", func(t *testing.T) {
+		templates, err := LoadRunConfigTemplates(context.Background(), writeTemplateProject(t))
+		require.NoError(t, err)
+		require.Len(t, templates, 2)
+
+		shell := templates[ShConfigurationType]
+		require.Equal(t, []TemplateValue{{Name: "INTERPRETER_PATH", Value: "/bin/bash"}}, shell.Options)
+		require.Equal(t, []TemplateValue{{Name: "LANG", Value: "C.UTF-8"}}, shell.EnvVars)
+		require.Equal(t, "workspace.xml", filepath.Base(shell.Source))
+	})
+
+	t.Run("should prefer shared templates over workspace.xml", func(t *testing.T) {
+		templates, err := LoadRunConfigTemplates(context.Background(), writeTemplateProject(t))
+		require.NoError(t, err)
+
+		application := templates["Application"]
+		require.Equal(t, []TemplateValue{{Name: "VM_PARAMETERS", Value: "-Xmx1g -Dprofile=dev"}}, application.Options)
+		require.Equal(t, "_template__of_Application.xml", filepath.Base(application.Source))
+	})
+
+	t.Run("should find no templates in a project without .idea", func(t *testing.T) {
+		templates, err := LoadRunConfigTemplates(context.Background(), t.TempDir())
+		require.NoError(t, err)
+		require.Empty(t, templates)
+	})
+
+	t.Run("should read the external system settings of Gradle templates", func(t *testing.T) {
+		projectRoot := writeTemplateProject(t)
+
+		require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ".idea", "runConfigurations", "_template__of_GradleRunConfiguration.xml"), []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration type="GradleRunConfiguration" factoryName="Gradle">
+    <ExternalSystemSettings>
+      <option name="env">
+        <map>
+          <entry key="CI" value="true" />
+        </map>
+      </option>
+      <option name="executionName" />
+      <option name="vmOptions" value="-Xmx2g" />
+    </ExternalSystemSettings>
+  </configuration>
+</component>
+`), 0644))
+
+		templates, err := LoadRunConfigTemplates(context.Background(), projectRoot)
+		require.NoError(t, err)
+
+		require.Equal(t, []TemplateSetting{
+			{Name: "env", Map: []TemplateValue{{Name: "CI", Value: "true"}}},
+			{Name: "vmOptions", Value: "-Xmx2g"},
+		}, templates["GradleRunConfiguration"].Settings)
+	})
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SourceInfo describes a configuration file tasks were read from
+type SourceInfo struct {
+	Path      string    `json:"path"`
+	ModTime   time.Time `json:"modTime"`
+	Size      int64     `json:"size"`
+	Hash      string    `json:"hash"` // sha256 of the raw file bytes
+	TaskCount int       `json:"taskCount"`
+}
+
+// StatSource reads the file at path and returns its freshness metadata
+func StatSource(path string) (*SourceInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat source %s: %w", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source %s: %w", path, err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return &SourceInfo{
+		Path:    path,
+		ModTime: info.ModTime(),
+		Size:    info.Size(),
+		Hash:    hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// CollectSources returns metadata for every distinct task source, sorted by path.
+// Sources that can no longer be read are returned in the error map instead.
+func CollectSources(tasks []*Task) ([]*SourceInfo, map[string]error) {
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		if task.Source != "" {
+			counts[task.Source]++
+		}
+	}
+
+	paths := make([]string, 0, len(counts))
+	for path := range counts {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	sources := make([]*SourceInfo, 0, len(paths))
+	failures := make(map[string]error)
+
+	for _, path := range paths {
+		source, err := StatSource(path)
+		if err != nil {
+			failures[path] = err
+			continue
+		}
+
+		source.TaskCount = counts[path]
+		sources = append(sources, source)
+	}
+
+	return sources, failures
+}
+
+// CatalogHash combines the source hashes into one value that changes whenever any source
+// file is added, removed or edited. Sources are hashed in path order, so the result is stable.
+func CatalogHash(sources []*SourceInfo) string {
+	sorted := append([]*SourceInfo(nil), sources...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	h := sha256.New()
+	for _, source := range sorted {
+		fmt.Fprintf(h, "%s\x00%s\n", source.Path, source.Hash)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ParseChangedSince parses an RFC 3339 timestamp or a duration such as "2h", "30m" or "7d",
+// which is taken as that long before now
+func ParseChangedSince(value string, now time.Time) (time.Time, error) {
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if days, found := strings.CutSuffix(value, "d"); found {
+		var count int
+
+		count, err = strconv.Atoi(days)
+		age = time.Duration(count) * 24 * time.Hour
+	}
+
+	if err != nil || age < 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected an RFC 3339 timestamp (e.g. 2024-05-01T09:00:00Z) or a duration (e.g. 2h, 30m, 7d)", value)
+	}
+
+	return now.Add(-age), nil
+}
+
+// FilterChangedSince keeps tasks whose source was modified after since
+func FilterChangedSince(tasks []*Task, sources []*SourceInfo, since time.Time) []*Task {
+	changed := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		changed[source.Path] = source.ModTime.After(since)
+	}
+
+	filtered := make([]*Task, 0, len(tasks))
+
+	for _, task := range tasks {
+		if changed[task.Source] {
+			filtered = append(filtered, task)
+		}
+	}
+
+	return filtered
+}
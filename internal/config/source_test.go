@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSources(t *testing.T) {
+	dir := t.TempDir()
+	tasksPath := filepath.Join(dir, "tasks.json")
+	launchPath := filepath.Join(dir, "launch.json")
+
+	require.NoError(t, os.WriteFile(tasksPath, []byte(`{"tasks": []}`), 0644))
+	require.NoError(t, os.WriteFile(launchPath, []byte(`{"configurations": []}`), 0644))
+
+	old := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(launchPath, old, old))
+
+	tasks := []*Task{
+		{Name: "build", Source: tasksPath},
+		{Name: "test", Source: tasksPath},
+		{Name: "Debug", Source: launchPath},
+		{Name: "gone", Source: filepath.Join(dir, "missing.json")},
+	}
+
+	t.Run("CollectSources", func(t *testing.T) {
+		sources, failures := CollectSources(tasks)
+		require.Len(t, sources, 2)
+		require.Len(t, failures, 1)
+
+		require.Equal(t, launchPath, sources[0].Path)
+		require.Equal(t, 1, sources[0].TaskCount)
+		require.True(t, sources[0].ModTime.Equal(old))
+		require.Equal(t, int64(len(`{"configurations": []}`)), sources[0].Size)
+
+		require.Equal(t, tasksPath, sources[1].Path)
+		require.Equal(t, 2, sources[1].TaskCount)
+		require.Equal(t, "2f057bef3b56b4458275a4a6aa796c052d309f3316ed28d6767cc5644a9f0b1b", sources[1].Hash)
+	})
+
+	t.Run("CatalogHash", func(t *testing.T) {
+		sources, _ := CollectSources(tasks)
+		hash := CatalogHash(sources)
+
+		reversed := []*SourceInfo{sources[1], sources[0]}
+		require.Equal(t, hash, CatalogHash(reversed), "hash should not depend on source order")
+
+		require.NoError(t, os.WriteFile(tasksPath, []byte(`{"tasks": [{}]}`), 0644))
+
+		edited, _ := CollectSources(tasks)
+		require.NotEqual(t, hash, CatalogHash(edited))
+		require.NotEqual(t, hash, CatalogHash(sources[:1]), "removing a source should change the hash")
+	})
+
+	t.Run("ParseChangedSince", func(t *testing.T) {
+		now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+
+		since, err := ParseChangedSince("2h", now)
+		require.NoError(t, err)
+		require.Equal(t, now.Add(-2*time.Hour), since)
+
+		since, err = ParseChangedSince("2024-05-01T09:00:00Z", now)
+		require.NoError(t, err)
+		require.Equal(t, old, since)
+
+		since, err = ParseChangedSince("7d", now)
+		require.NoError(t, err)
+		require.Equal(t, now.Add(-7*24*time.Hour), since)
+
+		_, err = ParseChangedSince("yesterday", now)
+		require.Error(t, err)
+
+		_, err = ParseChangedSince("-1h", now)
+		require.Error(t, err)
+	})
+
+	t.Run("FilterChangedSince", func(t *testing.T) {
+		sources, _ := CollectSources(tasks)
+
+		recent := FilterChangedSince(tasks, sources, old.Add(time.Hour))
+		require.Len(t, recent, 2)
+		require.Equal(t, "build", recent[0].Name)
+		require.Equal(t, "test", recent[1].Name)
+
+		require.Len(t, FilterChangedSince(tasks, sources, old.Add(-time.Hour)), 3)
+	})
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// SourceCount is the number of tasks a configuration file defines
+type SourceCount struct {
+	Source string `json:"source"`
+	Tasks  int    `json:"tasks"`
+}
+
+// TaskStats aggregates the recorded runs of one task
+type TaskStats struct {
+	Name      string    `json:"name"`
+	Source    string    `json:"source,omitempty"`
+	Runs      int       `json:"runs"`
+	Failures  int       `json:"failures"`
+	AverageMs int64     `json:"averageMs"`
+	LastRun   time.Time `json:"lastRun"`
+}
+
+// FailureRate returns the share of runs that failed, from 0 to 1
+func (s TaskStats) FailureRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+
+	return float64(s.Failures) / float64(s.Runs)
+}
+
+// AverageDuration returns the mean wall time of the runs
+func (s TaskStats) AverageDuration() time.Duration {
+	return time.Duration(s.AverageMs) * time.Millisecond
+}
+
+// UsageStats summarizes a project's tasks and their run history
+type UsageStats struct {
+	Sources  []SourceCount `json:"sources"`
+	Tasks    []TaskStats   `json:"tasks"`    // Tasks that ran, most runs first
+	NeverRun []string      `json:"neverRun"` // Tasks defined but without recorded runs
+	Removed  []TaskStats   `json:"removed"`  // Runs of tasks that are no longer defined, e.g. renamed or deleted ones
+}
+
+// historyKey identifies a task across the history of every project
+type historyKey struct {
+	project string
+	task    string
+}
+
+// ComputeUsageStats aggregates the history entries of the project rooted at projectRoot (an
+// absolute path) per task. Entries are matched to tasks by project and name, so a task of
+// another project sharing a name is not counted; those naming no current task are kept under
+// Removed rather than dropped.
+func ComputeUsageStats(projectRoot string, tasks []*Task, entries []HistoryEntry) *UsageStats {
+	stats := &UsageStats{Sources: []SourceCount{}, Tasks: []TaskStats{}, NeverRun: []string{}, Removed: []TaskStats{}}
+
+	defined := make(map[string]bool, len(tasks))
+	sourceIndex := make(map[string]int)
+
+	for _, task := range tasks {
+		defined[task.Name] = true
+
+		i, ok := sourceIndex[task.Source]
+		if !ok {
+			i = len(stats.Sources)
+			sourceIndex[task.Source] = i
+			stats.Sources = append(stats.Sources, SourceCount{Source: task.Source})
+		}
+
+		stats.Sources[i].Tasks++
+	}
+
+	byKey := make(map[historyKey]*TaskStats)
+	totals := make(map[historyKey]time.Duration)
+
+	var order []historyKey
+
+	for _, entry := range entries {
+		if entry.Project != projectRoot {
+			continue
+		}
+
+		key := historyKey{project: entry.Project, task: entry.Task}
+
+		taskStats, ok := byKey[key]
+		if !ok {
+			taskStats = &TaskStats{Name: entry.Task}
+			byKey[key] = taskStats
+			order = append(order, key)
+		}
+
+		taskStats.Runs++
+		totals[key] += entry.Duration()
+
+		if entry.ExitCode != 0 {
+			taskStats.Failures++
+		}
+
+		if !entry.Started.Before(taskStats.LastRun) {
+			taskStats.LastRun = entry.Started
+			taskStats.Source = entry.Source
+		}
+	}
+
+	for _, key := range order {
+		taskStats := byKey[key]
+		taskStats.AverageMs = (totals[key] / time.Duration(taskStats.Runs)).Milliseconds()
+
+		if defined[key.task] {
+			stats.Tasks = append(stats.Tasks, *taskStats)
+		} else {
+			stats.Removed = append(stats.Removed, *taskStats)
+		}
+	}
+
+	for _, task := range tasks {
+		if _, ran := byKey[historyKey{project: projectRoot, task: task.Name}]; !ran && !slices.Contains(stats.NeverRun, task.Name) {
+			stats.NeverRun = append(stats.NeverRun, task.Name)
+		}
+	}
+
+	mostRunFirst := func(list []TaskStats) {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Runs > list[j].Runs })
+	}
+
+	mostRunFirst(stats.Tasks)
+	mostRunFirst(stats.Removed)
+
+	return stats
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeUsageStats(t *testing.T) {
+	tasks := []*Task{
+		{Name: "build", Source: ".vscode/tasks.json"},
+		{Name: "test", Source: ".vscode/tasks.json"},
+		{Name: "Run App", Source: ".idea/runConfigurations/Run_App.xml"},
+	}
+
+	started := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	run := func(task string, minutes int, durationMs int64, exitCode int) HistoryEntry {
+		return HistoryEntry{Project: "/work/app", Task: task, Source: ".vscode/tasks.json", Started: started.Add(time.Duration(minutes) * time.Minute), DurationMs: durationMs, ExitCode: exitCode}
+	}
+
+	elsewhere := run("Run App", 4, 100, 0)
+	elsewhere.Project = "/work/other"
+
+	stats := ComputeUsageStats("/work/app", tasks, []HistoryEntry{
+		run("build", 0, 1000, 0),
+		run("test", 1, 4000, 1),
+		run("test", 2, 2000, 0),
+		run("lint", 3, 500, 0),
+		elsewhere,
+	})
+
+	t.Run("should count tasks per source", func(t *testing.T) {
+		require.Equal(t, []SourceCount{
+			{Source: ".vscode/tasks.json", Tasks: 2},
+			{Source: ".idea/runConfigurations/Run_App.xml", Tasks: 1},
+		}, stats.Sources)
+	})
+
+	t.Run("should aggregate runs per task, most runs first", func(t *testing.T) {
+		require.Len(t, stats.Tasks, 2)
+
+		test := stats.Tasks[0]
+		require.Equal(t, "test", test.Name)
+		require.Equal(t, 2, test.Runs)
+		require.Equal(t, 1, test.Failures)
+		require.Equal(t, 0.5, test.FailureRate())
+		require.Equal(t, 3*time.Second, test.AverageDuration())
+		require.Equal(t, started.Add(2*time.Minute), test.LastRun)
+
+		require.Equal(t, "build", stats.Tasks[1].Name)
+		require.Zero(t, stats.Tasks[1].FailureRate())
+	})
+
+	t.Run("should keep runs of tasks that no longer exist", func(t *testing.T) {
+		require.Len(t, stats.Removed, 1)
+		require.Equal(t, "lint", stats.Removed[0].Name)
+		require.Equal(t, 1, stats.Removed[0].Runs)
+	})
+
+	t.Run("should list tasks that never ran in this project", func(t *testing.T) {
+		require.Equal(t, []string{"Run App"}, stats.NeverRun)
+	})
+}
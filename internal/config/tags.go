@@ -0,0 +1,152 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tagsPattern matches the `[tags: a,b]` convention at the end of a task's detail or folder name
+var tagsPattern = regexp.MustCompile(`(?i)\[tags:([^\]]*)\]\s*$`)
+
+// ParseTags extracts the tags from a trailing `[tags: a,b]` annotation.
+// Tags are trimmed, empty entries and case-insensitive duplicates are dropped.
+func ParseTags(text string) []string {
+	match := tagsPattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+
+	var tags []string
+
+	seen := make(map[string]bool)
+
+	for _, tag := range strings.Split(match[1], ",") {
+		tag = strings.TrimSpace(tag)
+
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// FormatTags renders tags in the `[tags: a,b]` convention, or "" when there are none
+func FormatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	return "[tags: " + strings.Join(tags, ",") + "]"
+}
+
+// TagAnnotation returns the tag-carrying string converters should write for the task:
+// the annotation from its description verbatim when there is one, otherwise one built from Tags
+func TagAnnotation(task *Task) string {
+	if loc := tagsPattern.FindStringIndex(task.Description); loc != nil {
+		return strings.TrimSpace(task.Description[loc[0]:loc[1]])
+	}
+
+	return FormatTags(task.Tags)
+}
+
+// HasTags reports whether the task carries every one of the given tags (case-insensitive)
+func (t *Task) HasTags(tags []string) bool {
+	for _, want := range tags {
+		found := false
+
+		for _, tag := range t.Tags {
+			if strings.EqualFold(tag, want) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FilterByTags keeps the tasks carrying every one of the given tags. No tags keeps all tasks.
+func FilterByTags(tasks []*Task, tags []string) []*Task {
+	if len(tags) == 0 {
+		return tasks
+	}
+
+	filtered := make([]*Task, 0, len(tasks))
+
+	for _, task := range tasks {
+		if task.HasTags(tags) {
+			filtered = append(filtered, task)
+		}
+	}
+
+	return filtered
+}
+
+// LoadTaskTags reads the tags assigned to tasks by name in the "tags" object of the user-level
+// config.json and the project's .taskporter.json, for tasks whose source cannot carry tags:
+//
+//	{"tags": {"Deploy staging": ["local"], "lint": ["ci", "quick"]}}
+//
+// Tags from both files are combined. Missing files are not an error.
+func LoadTaskTags(projectRoot string) (map[string][]string, error) {
+	loaded, err := readSettings(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string][]string)
+
+	for _, file := range loaded {
+		for name, taskTags := range file.settings.Tags {
+			tags[name] = append(tags[name], taskTags...)
+		}
+	}
+
+	return tags, nil
+}
+
+// ApplyTaskTags adds configured tags to the tasks they name, matched like aliases are. Every
+// task with the name is tagged, as several formats may define a task of the same name. Tags a
+// task already carries are not repeated.
+func ApplyTaskTags(tasks []*Task, tags map[string][]string) {
+	for name, taskTags := range tags {
+		for _, task := range findTasksByName(tasks, name) {
+			for _, tag := range taskTags {
+				tag = strings.TrimSpace(tag)
+				if tag != "" && !task.HasTags([]string{tag}) {
+					task.Tags = append(task.Tags, tag)
+				}
+			}
+		}
+	}
+}
+
+// findTasksByName returns every task named name, or if there is none, every task whose name
+// matches it case-insensitively
+func findTasksByName(tasks []*Task, name string) []*Task {
+	var exact, folded []*Task
+
+	for _, task := range tasks {
+		switch {
+		case task.Name == name:
+			exact = append(exact, task)
+		case strings.EqualFold(task.Name, name):
+			folded = append(folded, task)
+		}
+	}
+
+	if len(exact) > 0 {
+		return exact
+	}
+
+	return folded
+}
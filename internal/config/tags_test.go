@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTags(t *testing.T) {
+	t.Run("should read a trailing tags annotation", func(t *testing.T) {
+		require.Equal(t, []string{"ci", "slow"}, ParseTags("Runs everything [tags: ci, slow]"))
+		require.Equal(t, []string{"quick"}, ParseTags("[TAGS:quick]  "))
+	})
+
+	t.Run("should drop empty and duplicate tags", func(t *testing.T) {
+		require.Equal(t, []string{"ci", "slow"}, ParseTags("[tags: ci,,slow, CI]"))
+	})
+
+	t.Run("should ignore text without a trailing annotation", func(t *testing.T) {
+		require.Nil(t, ParseTags("Build the project"))
+		require.Nil(t, ParseTags("[tags: ci] then build"))
+		require.Nil(t, ParseTags(""))
+	})
+}
+
+func TestTagAnnotation(t *testing.T) {
+	t.Run("should keep the original annotation verbatim", func(t *testing.T) {
+		task := &Task{Description: "Lint [tags: ci,  Quick]", Tags: []string{"ci", "Quick"}}
+		require.Equal(t, "[tags: ci,  Quick]", TagAnnotation(task))
+	})
+
+	t.Run("should build an annotation from tags", func(t *testing.T) {
+		task := &Task{Description: "JetBrains Application configuration", Tags: []string{"ci", "slow"}}
+		require.Equal(t, "[tags: ci,slow]", TagAnnotation(task))
+		require.Equal(t, []string{"ci", "slow"}, ParseTags(TagAnnotation(task)))
+	})
+
+	t.Run("should be empty without tags", func(t *testing.T) {
+		require.Empty(t, TagAnnotation(&Task{Description: "Build"}))
+	})
+}
+
+func TestFilterByTags(t *testing.T) {
+	lint := &Task{Name: "lint", Tags: []string{"ci", "quick"}}
+	e2e := &Task{Name: "e2e", Tags: []string{"CI", "slow"}}
+	build := &Task{Name: "build"}
+	tasks := []*Task{lint, e2e, build}
+
+	t.Run("should keep all tasks without tags", func(t *testing.T) {
+		require.Equal(t, tasks, FilterByTags(tasks, nil))
+	})
+
+	t.Run("should match tags case-insensitively", func(t *testing.T) {
+		require.Equal(t, []*Task{lint, e2e}, FilterByTags(tasks, []string{"ci"}))
+	})
+
+	t.Run("should require every tag", func(t *testing.T) {
+		require.Equal(t, []*Task{e2e}, FilterByTags(tasks, []string{"ci", "slow"}))
+		require.Empty(t, FilterByTags(tasks, []string{"quick", "slow"}))
+	})
+}
+
+func TestTaskTagsFromSettings(t *testing.T) {
+	configHome := t.TempDir()
+	projectRoot := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configHome, "taskporter"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configHome, "taskporter", "config.json"),
+		[]byte(`{"tags": {"Deploy staging": ["local"]}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectRoot, ProjectConfigFile),
+		[]byte(`{"tags": {"Deploy staging": ["manual"], "lint": ["CI", "quick"], "gone": ["ci"]}}`), 0644))
+
+	tags, err := LoadTaskTags(projectRoot)
+	require.NoError(t, err)
+	require.Equal(t, []string{"local", "manual"}, tags["Deploy staging"])
+
+	deploy := &Task{Name: "deploy staging"}
+	lint := &Task{Name: "lint", Tags: []string{"ci"}}
+	jetbrainsLint := &Task{Name: "lint", Type: TypeJetBrains}
+	lintAll := &Task{Name: "Lint"}
+
+	ApplyTaskTags([]*Task{deploy, lint, jetbrainsLint, lintAll}, tags)
+
+	require.Equal(t, []string{"local", "manual"}, deploy.Tags)
+	require.Equal(t, []string{"ci", "quick"}, lint.Tags)
+
+	// Every exact match is tagged; a case-insensitive one only without an exact match
+	require.Equal(t, []string{"CI", "quick"}, jetbrainsLint.Tags)
+	require.Empty(t, lintAll.Tags)
+}
@@ -1,23 +1,106 @@
 package config
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // TaskType represents the type of task or configuration
 type TaskType string
 
 const (
-	TypeVSCodeTask   TaskType = "vscode-task"
-	TypeVSCodeLaunch TaskType = "vscode-launch"
-	TypeJetBrains    TaskType = "jetbrains"
+	TypeVSCodeTask    TaskType = "vscode-task"
+	TypeVSCodeLaunch  TaskType = "vscode-launch"
+	TypeJetBrains     TaskType = "jetbrains"
+	TypeSublime       TaskType = "sublime-build"
+	TypeGitHubActions TaskType = "github-actions" // run: steps of .github/workflows, read-only
+	TypeGlobal        TaskType = "global"         // User-level tasks available in every project
+)
+
+// TaskTypes lists every task type, in the order sources are displayed
+var TaskTypes = []TaskType{TypeVSCodeTask, TypeVSCodeLaunch, TypeJetBrains, TypeSublime, TypeGitHubActions, TypeGlobal}
+
+// Dependency orders supported by VSCode's dependsOrder
+const (
+	DependsOrderParallel = "parallel"
+	DependsOrderSequence = "sequence"
+)
+
+// Execution kinds of VSCode tasks.json tasks
+const (
+	ExecutionProcess = "process" // Command is an executable run directly with Args
+	ExecutionShell   = "shell"   // Command is a command line passed to a shell verbatim, Args are quoted and appended
+)
+
+// DefaultShell returns the shell VSCode runs shell tasks with on goos when no terminal profile
+// is configured
+func DefaultShell(goos string) string {
+	if goos == "windows" {
+		return "cmd.exe"
+	}
+
+	return "/bin/sh"
+}
+
+// Quoting styles of VSCode shell task args written as {"value": ..., "quoting": ...}
+const (
+	QuotingEscape = "escape" // Characters the shell would interpret are escaped one by one
+	QuotingStrong = "strong" // The arg is quoted so the shell interprets nothing in it
+	QuotingWeak   = "weak"   // The arg is quoted but the shell still expands variables in it
 )
 
+// ConfirmGroup is the task group that always asks for confirmation before running
+const ConfirmGroup = "deploy"
+
 // Task represents a unified task or launch configuration
 type Task struct {
-	Name        string            `json:"name"`
-	Type        TaskType          `json:"type"`
-	Command     string            `json:"command,omitempty"`
-	Args        []string          `json:"args,omitempty"`
-	Cwd         string            `json:"cwd,omitempty"`
-	Env         map[string]string `json:"env,omitempty"`
-	Group       string            `json:"group,omitempty"`
-	Description string            `json:"description,omitempty"`
-	Source      string            `json:"source"` // Path to the source configuration file
+	Name            string            `json:"name"`
+	Type            TaskType          `json:"type"`
+	Command         string            `json:"command,omitempty"`
+	Args            []string          `json:"args,omitempty"`
+	ArgQuoting      []string          `json:"argQuoting,omitempty"` // Quoting style per Args entry of shell tasks, "" (or missing) for the default
+	Cwd             string            `json:"cwd,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+	UnsetEnv        []string          `json:"unsetEnv,omitempty"` // Variables removed from the inherited environment (null env values in VSCode), sorted
+	Group           string            `json:"group,omitempty"`
+	Description     string            `json:"description,omitempty"`
+	Tags            []string          `json:"tags,omitempty"`            // From a `[tags: a,b]` annotation in the task's detail or folder name
+	Aliases         []string          `json:"aliases,omitempty"`         // Short names from taskporter's config files that expand to this task
+	Execution       string            `json:"execution,omitempty"`       // ExecutionProcess or ExecutionShell for VSCode tasks, empty when the source doesn't say
+	Shell           string            `json:"shell,omitempty"`           // Shell used to run the command line, empty for direct exec
+	RuntimePath     string            `json:"runtimePath,omitempty"`     // Interpreter or JVM executable chosen in the IDE, preferred over Command from PATH
+	Console         string            `json:"console,omitempty"`         // VSCode console kind (integratedTerminal, externalTerminal, internalConsole)
+	Interactive     bool              `json:"interactive,omitempty"`     // Task needs the raw terminal (stdin, TUI output)
+	Confirm         bool              `json:"confirm,omitempty"`         // Ask before running (destructive tasks)
+	CreateCwd       bool              `json:"createCwd,omitempty"`       // Create a missing Cwd before running instead of failing
+	CleanEnv        bool              `json:"cleanEnv,omitempty"`        // Start from a minimal PATH and Env only instead of the inherited environment
+	DependsOn       []string          `json:"dependsOn,omitempty"`       // Names of tasks that must run first
+	DependsOrder    string            `json:"dependsOrder,omitempty"`    // DependsOrderParallel (default) or DependsOrderSequence
+	PreLaunchTask   string            `json:"preLaunchTask,omitempty"`   // Task a launch configuration runs before starting
+	PostDebugTask   string            `json:"postDebugTask,omitempty"`   // Task a launch configuration runs after the debug session ends
+	BeforeRun       []string          `json:"beforeRun,omitempty"`       // Configurations a JetBrains before-run step starts first (listed and graphed, not run)
+	ProblemPatterns []string          `json:"problemPatterns,omitempty"` // problemMatcher regexps marking output lines as problems
+	Extends         string            `json:"extends,omitempty"`         // Task this one inherits from via a "tasks" entry in .taskporter.json
+	Platform        string            `json:"platform,omitempty"`        // launch.json or tasks.json platform block ("osx", "linux", "windows") merged into the task
+	Source          string            `json:"source"`                    // Path to the source configuration file
+	Passthrough     json.RawMessage   `json:"-"`                         // Original source object, kept so converters can preserve fields they don't model
+	UnreadFields    []string          `json:"-"`                         // Keys of the source object the parser does not read, so no converter carries them over
+}
+
+// RequiresConfirmation reports whether the task opted in to a confirmation step,
+// either explicitly or by belonging to the deploy group
+func (t *Task) RequiresConfirmation() bool {
+	return t.Confirm || strings.EqualFold(t.Group, ConfirmGroup)
+}
+
+// IsAggregate reports whether the task has no command of its own and only runs its
+// dependencies, like a tasks.json "ci" task that dependsOn lint, test and build
+func (t *Task) IsAggregate() bool {
+	return t.Command == "" && len(t.DependsOn) > 0
+}
+
+// IsCompound reports whether the task only exists to start its dependencies together,
+// like a launch.json compound or an aggregate task with parallel dependsOn
+func (t *Task) IsCompound() bool {
+	return t.IsAggregate() && t.DependsOrder != DependsOrderSequence
 }
@@ -0,0 +1,229 @@
+package converter
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Formats environment variable references are translated to
+const (
+	envRefsVSCode    = "vscode"
+	envRefsJetBrains = "jetbrains"
+)
+
+// envRefPattern finds the environment variable references of every supported syntax, leftmost first:
+//
+//	$$            an escaped dollar sign, kept as written
+//	${env:NAME}   VSCode
+//	${userHome}   VSCode
+//	$NAME$        JetBrains macro
+//	%NAME%        Windows (two characters or more, so "%Y%m%d" date formats are left alone)
+//	${NAME}       shell, upper case only so VSCode variables such as ${workspaceFolder} never match
+//	$NAME         shell, upper case only
+var envRefPattern = regexp.MustCompile(`\$\$|\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}|\$\{userHome\}|\$([A-Za-z_][A-Za-z0-9_]*)\$|%([A-Za-z_][A-Za-z0-9_]+)%|\$\{([A-Z_][A-Z0-9_]*)\}|\$([A-Z_][A-Z0-9_]*)`)
+
+// homeEnvVars are the environment variables holding the user's home directory, which JetBrains
+// has the $USER_HOME$ macro and VSCode the ${userHome} variable for
+var homeEnvVars = []string{"HOME", "USERPROFILE"}
+
+// translateEnvRefs rewrites the environment variable references in value to the syntax of target
+// ("vscode" or "jetbrains") where the target has a safe equivalent. shellRefs also translates
+// the references a shell expands itself, $NAME and %NAME%, which is only safe in paths and env
+// values: in arguments they are the shell's to expand, and as likely to be awk fields, regex
+// anchors or printf formats. It returns the rewritten value and the references kept as written
+// because the target has no equivalent.
+func translateEnvRefs(value, target string, shellRefs bool) (string, []string) {
+	var unresolved []string
+
+	result := envRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		match := envRefPattern.FindStringSubmatch(ref)
+		envName, macro, windowsName, shellName := match[1], match[2], match[3], match[4]+match[5]
+
+		switch {
+		case ref == "$$":
+			return ref
+		case ref == "${userHome}":
+			if target == envRefsJetBrains {
+				return "$USER_HOME$"
+			}
+
+			return ref
+		case envName != "":
+			if target == envRefsVSCode {
+				return ref
+			}
+
+			if slices.Contains(homeEnvVars, envName) {
+				return "$USER_HOME$"
+			}
+		case macro != "":
+			if target == envRefsJetBrains {
+				return ref
+			}
+
+			if macro == "USER_HOME" {
+				return "${userHome}"
+			}
+		case windowsName != "":
+			// Like shell references below, cmd.exe expands these in arguments
+			if !shellRefs {
+				return ref
+			}
+
+			if target == envRefsVSCode {
+				return "${env:" + windowsName + "}"
+			}
+
+			if slices.Contains(homeEnvVars, strings.ToUpper(windowsName)) {
+				return "$USER_HOME$"
+			}
+		default:
+			// Shell references are JetBrains' own literal syntax and left to the shell in arguments
+			if !shellRefs {
+				return ref
+			}
+
+			if target == envRefsVSCode {
+				return "${env:" + shellName + "}"
+			}
+
+			// JetBrains expands only its own $NAME$ macros in paths and env values
+			if slices.Contains(homeEnvVars, shellName) {
+				return "$USER_HOME$"
+			}
+		}
+
+		unresolved = append(unresolved, ref)
+
+		return ref
+	})
+
+	return result, unresolved
+}
+
+// envRefWarnings translates the values of one generated configuration, collecting a warning for
+// every reference kept as written and the fields holding them
+type envRefWarnings struct {
+	target   string
+	warnings []string
+	fields   []string // "env" for any variable
+}
+
+// translate translates value, found in field
+func (w *envRefWarnings) translate(field, value string, shellRefs bool) string {
+	result, unresolved := translateEnvRefs(value, w.target, shellRefs)
+
+	targetName := "VSCode"
+	if w.target == envRefsJetBrains {
+		targetName = "JetBrains"
+	}
+
+	for _, ref := range unresolved {
+		w.warnings = append(w.warnings, fmt.Sprintf("%s %q references %s, which has no %s equivalent and was kept as written", field, value, ref, targetName))
+	}
+
+	if name, _, _ := strings.Cut(field, " "); len(unresolved) > 0 && !slices.Contains(w.fields, name) {
+		w.fields = append(w.fields, name)
+	}
+
+	return result
+}
+
+// translateAll translates every value of a list, returning a new slice
+func (w *envRefWarnings) translateAll(field string, values []string, shellRefs bool) []string {
+	if values == nil {
+		return nil
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = w.translate(field, value, shellRefs)
+	}
+
+	return result
+}
+
+// translateEnv translates the values of an environment, returning a new map
+func (w *envRefWarnings) translateEnv(env map[string]string) map[string]string {
+	if env == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(env))
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		result[key] = w.translate("env "+key, env[key], true)
+	}
+
+	return result
+}
+
+// translateRunConfigurationEnvRefs rewrites the environment variable references in the option
+// and env values of a generated JetBrains run configuration, including those of its external
+// system (Gradle) settings, returning the warnings for those it kept. Script text is the shell's
+// to expand and left alone.
+func translateRunConfigurationEnvRefs(config *JetBrainsRunConfiguration) *envRefWarnings {
+	refs := &envRefWarnings{target: envRefsJetBrains}
+
+	for i, option := range config.Options {
+		if option.Name == "SCRIPT_TEXT" {
+			continue
+		}
+
+		config.Options[i].Value = refs.translate(option.Name, option.Value, option.Name == "WORKING_DIRECTORY")
+	}
+
+	if config.EnvVars != nil {
+		for i, env := range config.EnvVars.EnvVars {
+			config.EnvVars.EnvVars[i].Value = refs.translate("env "+env.Name, env.Value, true)
+		}
+	}
+
+	if config.ExternalSystemSettings != nil {
+		for i, option := range config.ExternalSystemSettings.Options {
+			if option.Map != nil {
+				for j, entry := range option.Map.Entries {
+					option.Map.Entries[j].Value = refs.translate(option.Name+" "+entry.Key, entry.Value, true)
+				}
+			}
+
+			if option.Value != "" {
+				config.ExternalSystemSettings.Options[i].Value = refs.translate(option.Name, option.Value, option.Name == "externalProjectPath")
+			}
+		}
+	}
+
+	return refs
+}
+
+// translateTaskEnvRefs rewrites the environment variable references in the cwd, env and args of
+// a generated VSCode task, returning the warnings for those it kept
+func translateTaskEnvRefs(task *VSCodeTask) *envRefWarnings {
+	refs := &envRefWarnings{target: envRefsVSCode}
+
+	task.Args = refs.translateAll("args", task.Args, false)
+
+	if task.Options != nil {
+		task.Options.Cwd = refs.translate("cwd", task.Options.Cwd, true)
+		task.Options.Env = refs.translateEnv(task.Options.Env)
+	}
+
+	return refs
+}
+
+// translateLaunchEnvRefs rewrites the environment variable references in the program, cwd, env
+// and arguments of a generated VSCode launch configuration, returning the warnings for those it
+// kept
+func translateLaunchEnvRefs(launch *VSCodeLaunchConfig) *envRefWarnings {
+	refs := &envRefWarnings{target: envRefsVSCode}
+
+	launch.Program = refs.translate("program", launch.Program, true)
+	launch.Cwd = refs.translate("cwd", launch.Cwd, true)
+	launch.Env = refs.translateEnv(launch.Env)
+	launch.Args = refs.translateAll("args", launch.Args, false)
+	launch.VMArgs = refs.translate("vmArgs", launch.VMArgs, false)
+
+	return refs
+}
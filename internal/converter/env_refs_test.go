@@ -0,0 +1,135 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateEnvRefs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		value      string
+		target     string
+		shellRefs  bool
+		expected   string
+		unresolved []string
+	}{
+		// VSCode sources
+		{"vscode home to jetbrains", "${env:HOME}/.cache", envRefsJetBrains, true, "$USER_HOME$/.cache", nil},
+		{"vscode userprofile to jetbrains", "${env:USERPROFILE}", envRefsJetBrains, false, "$USER_HOME$", nil},
+		{"vscode userHome to jetbrains", "${userHome}/bin", envRefsJetBrains, true, "$USER_HOME$/bin", nil},
+		{"vscode env without macro", "${env:API_TOKEN}", envRefsJetBrains, true, "${env:API_TOKEN}", []string{"${env:API_TOKEN}"}},
+		{"vscode env kept for vscode", "${env:API_TOKEN}", envRefsVSCode, true, "${env:API_TOKEN}", nil},
+
+		// JetBrains sources
+		{"jetbrains home macro to vscode", "$USER_HOME$/go", envRefsVSCode, true, "${userHome}/go", nil},
+		{"jetbrains path variable without equivalent", "$MAVEN_REPOSITORY$/lib", envRefsVSCode, true, "$MAVEN_REPOSITORY$/lib", []string{"$MAVEN_REPOSITORY$"}},
+		{"jetbrains macro kept for jetbrains", "$PROJECT_DIR$/build", envRefsJetBrains, true, "$PROJECT_DIR$/build", nil},
+		{"shell reference to vscode", "$HOME/.config", envRefsVSCode, true, "${env:HOME}/.config", nil},
+		{"braced shell reference to vscode", "${GOPATH}/bin", envRefsVSCode, true, "${env:GOPATH}/bin", nil},
+		{"shell home to jetbrains", "$HOME/.config", envRefsJetBrains, true, "$USER_HOME$/.config", nil},
+		{"shell reference without macro", "$GOPATH/bin", envRefsJetBrains, true, "$GOPATH/bin", []string{"$GOPATH"}},
+		{"shell reference in args left to the shell for jetbrains", "$GOPATH/bin", envRefsJetBrains, false, "$GOPATH/bin", nil},
+		{"shell reference in args left to the shell", "{print $NF}", envRefsVSCode, false, "{print $NF}", nil},
+
+		// Windows sources
+		{"windows to vscode", "%APPDATA%\\tool", envRefsVSCode, true, "${env:APPDATA}\\tool", nil},
+		{"windows reference in args left to cmd.exe", "%APPDATA%\\tool", envRefsVSCode, false, "%APPDATA%\\tool", nil},
+		{"windows home in args left to cmd.exe", "%USERPROFILE%\\.m2", envRefsJetBrains, false, "%USERPROFILE%\\.m2", nil},
+		{"windows home to jetbrains", "%USERPROFILE%\\.m2", envRefsJetBrains, true, "$USER_HOME$\\.m2", nil},
+		{"windows without macro", "%APPDATA%\\tool", envRefsJetBrains, true, "%APPDATA%\\tool", []string{"%APPDATA%"}},
+
+		// Several references in one value
+		{"multiple references to vscode", "%TEMP%/$USER_HOME$:$PATH", envRefsVSCode, true, "${env:TEMP}/${userHome}:${env:PATH}", nil},
+		{"multiple references to jetbrains", "${env:HOME}/${env:TOOL}/${env:HOME}", envRefsJetBrains, true, "$USER_HOME$/${env:TOOL}/$USER_HOME$", []string{"${env:TOOL}"}},
+
+		// Literal dollar and percent signs
+		{"price", "costs $5", envRefsVSCode, true, "costs $5", nil},
+		{"escaped dollar", "$$HOME", envRefsVSCode, true, "$$HOME", nil},
+		{"lone dollar", "a $ b", envRefsJetBrains, true, "a $ b", nil},
+		{"date format", "+%Y%m%d", envRefsVSCode, false, "+%Y%m%d", nil},
+		{"percent encoding", "a%20b%20c", envRefsVSCode, false, "a%20b%20c", nil},
+		{"vscode variable", "${workspaceFolder}/out", envRefsVSCode, true, "${workspaceFolder}/out", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run("should translate "+tc.name, func(t *testing.T) {
+			result, unresolved := translateEnvRefs(tc.value, tc.target, tc.shellRefs)
+
+			require.Equal(t, tc.expected, result)
+			require.Equal(t, tc.unresolved, unresolved)
+		})
+	}
+}
+
+func TestTranslateRunConfigurationEnvRefs(t *testing.T) {
+	t.Run("should translate option and env values and warn about kept references", func(t *testing.T) {
+		config := &JetBrainsRunConfiguration{
+			Options: []JetBrainsOption{
+				{Name: "WORKING_DIRECTORY", Value: "${env:HOME}/src"},
+				{Name: "PROGRAM_PARAMETERS", Value: "--token ${env:API_TOKEN}"},
+				{Name: "SCRIPT_TEXT", Value: "echo ${env:HOME}"},
+			},
+			EnvVars: &JetBrainsEnvVars{EnvVars: []JetBrainsEnvVar{{Name: "CACHE", Value: "%USERPROFILE%\\cache"}}},
+		}
+
+		refs := translateRunConfigurationEnvRefs(config)
+
+		require.Equal(t, "$USER_HOME$/src", config.Options[0].Value)
+		require.Equal(t, "--token ${env:API_TOKEN}", config.Options[1].Value)
+		require.Equal(t, "echo ${env:HOME}", config.Options[2].Value)
+		require.Equal(t, "$USER_HOME$\\cache", config.EnvVars.EnvVars[0].Value)
+		require.Equal(t, []string{`PROGRAM_PARAMETERS "--token ${env:API_TOKEN}" references ${env:API_TOKEN}, which has no JetBrains equivalent and was kept as written`}, refs.warnings)
+		require.Equal(t, []string{"PROGRAM_PARAMETERS"}, refs.fields)
+	})
+
+	t.Run("should warn about a shell reference left in the working directory", func(t *testing.T) {
+		config := &JetBrainsRunConfiguration{
+			Options: []JetBrainsOption{
+				{Name: "WORKING_DIRECTORY", Value: "$GOPATH/src/app"},
+				{Name: "PROGRAM_PARAMETERS", Value: "{print $NF}"},
+			},
+		}
+
+		refs := translateRunConfigurationEnvRefs(config)
+
+		require.Equal(t, "$GOPATH/src/app", config.Options[0].Value)
+		require.Equal(t, []string{`WORKING_DIRECTORY "$GOPATH/src/app" references $GOPATH, which has no JetBrains equivalent and was kept as written`}, refs.warnings)
+		require.Equal(t, []string{"WORKING_DIRECTORY"}, refs.fields)
+	})
+
+	t.Run("should translate the env and project path of Gradle settings", func(t *testing.T) {
+		config := &JetBrainsRunConfiguration{
+			Type: GradleConfigurationType,
+			ExternalSystemSettings: &JetBrainsExternalSystemSettings{Options: []JetBrainsSettingOption{
+				{Name: "env", Map: &JetBrainsSettingMap{Entries: []JetBrainsSettingMapEntry{{Key: "GRADLE_USER_HOME", Value: "${env:HOME}/.gradle"}}}},
+				{Name: "externalProjectPath", Value: "$GOPATH/src/app"},
+				{Name: "taskNames", List: &JetBrainsSettingList{Values: []JetBrainsSettingListValue{{Value: ":app:build"}}}},
+			}},
+		}
+
+		refs := translateRunConfigurationEnvRefs(config)
+
+		options := config.ExternalSystemSettings.Options
+		require.Equal(t, "$USER_HOME$/.gradle", options[0].Map.Entries[0].Value)
+		require.Equal(t, "$GOPATH/src/app", options[1].Value)
+		require.Equal(t, ":app:build", options[2].List.Values[0].Value)
+		require.Equal(t, []string{`externalProjectPath "$GOPATH/src/app" references $GOPATH, which has no JetBrains equivalent and was kept as written`}, refs.warnings)
+		require.Equal(t, []string{"externalProjectPath"}, refs.fields)
+	})
+}
+
+func TestTranslateTaskEnvRefs(t *testing.T) {
+	t.Run("should not modify the source args", func(t *testing.T) {
+		args := []string{"$USER_HOME$/bin", "%APPDATA%"}
+		task := &VSCodeTask{Args: args, Options: &VSCodeTaskOptions{Cwd: "$USER_HOME$", Env: map[string]string{"P": "$HOME/bin", "D": "%APPDATA%"}}}
+
+		require.Empty(t, translateTaskEnvRefs(task).warnings)
+		require.Equal(t, []string{"${userHome}/bin", "%APPDATA%"}, task.Args)
+		require.Equal(t, []string{"$USER_HOME$/bin", "%APPDATA%"}, args)
+		require.Equal(t, "${env:APPDATA}", task.Options.Env["D"])
+		require.Equal(t, "${userHome}", task.Options.Cwd)
+		require.Equal(t, "${env:HOME}/bin", task.Options.Env["P"])
+	})
+}
@@ -0,0 +1,113 @@
+package converter
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/golden"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestConverterGoldenOutputs snapshots the files every converter writes for every fixture,
+// so refactoring a converter shows exactly which output changed. Each port runs into its own
+// project root, whose generated files are snapshotted together.
+func TestConverterGoldenOutputs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("snapshots hold POSIX paths")
+	}
+
+	taskFixtures := []string{
+		"dependency-tasks.json", "edge-cases.json", "gradle-tasks.json", "java-tasks.json",
+		"maven-tasks.json", "nodejs-tasks.json", "python-tasks.json", "shell-tasks.json",
+	}
+
+	for _, fixture := range taskFixtures {
+		t.Run(fixture, func(t *testing.T) {
+			t.Run("jetbrains", func(t *testing.T) {
+				projectRoot := t.TempDir()
+				tasks := parseGoldenTasks(t, projectRoot, fixture)
+
+				require.NoError(t, NewVSCodeToJetBrainsConverter(projectRoot, "", false, nil).ConvertTasks(tasks, false))
+				golden.AssertDir(t, projectRoot, goldenSnapshotName(fixture, "jetbrains"))
+			})
+
+			t.Run("makefile", func(t *testing.T) {
+				projectRoot := t.TempDir()
+				tasks := parseGoldenTasks(t, projectRoot, fixture)
+
+				require.NoError(t, NewVSCodeToMakefileConverter(projectRoot, "", false, nil).ConvertTasks(tasks, false))
+				golden.AssertDir(t, projectRoot, goldenSnapshotName(fixture, "makefile"))
+			})
+		})
+	}
+
+	launchFixtures := []string{
+		"vscode-launch-compound.json", "vscode-launch-go.json", "vscode-launch-java.json",
+		"vscode-launch-nodejs.json", "vscode-launch-passthrough.json", "vscode-launch-python.json",
+	}
+
+	for _, fixture := range launchFixtures {
+		t.Run(fixture, func(t *testing.T) {
+			projectRoot := t.TempDir()
+
+			tasks, err := vscode.NewLaunchParser(projectRoot, nil).ParseLaunchConfigs(filepath.Join("testdata", fixture))
+			require.NoError(t, err)
+
+			require.NoError(t, NewVSCodeLaunchToJetBrainsConverter(projectRoot, "", false, nil).ConvertLaunchConfigs(tasks, false))
+			golden.AssertDir(t, projectRoot, goldenSnapshotName(fixture, "jetbrains"))
+		})
+	}
+
+	jetbrainsFixtures := map[string]string{
+		"jetbrains-go.xml":     "Go",
+		"jetbrains-java.xml":   "Java",
+		"jetbrains-nodejs.xml": "NodeJS",
+		"jetbrains-python.xml": "Python",
+	}
+
+	for fixture, language := range jetbrainsFixtures {
+		t.Run(fixture, func(t *testing.T) {
+			targets := map[string]func(projectRoot string, tasks []*config.Task) error{
+				"vscode-tasks": func(projectRoot string, tasks []*config.Task) error {
+					return NewJetBrainsToVSCodeConverter(projectRoot, "", false, nil).ConvertTasks(tasks, false)
+				},
+				"vscode-launch": func(projectRoot string, tasks []*config.Task) error {
+					return NewJetBrainsToVSCodeLaunchConverter(projectRoot, "", false, nil).ConvertToLaunch(tasks, false)
+				},
+				"shell": func(projectRoot string, tasks []*config.Task) error {
+					return NewJetBrainsToShellConverter(projectRoot, "", false, nil).ConvertTasks(tasks, false)
+				},
+			}
+
+			for target, convert := range targets {
+				t.Run(target, func(t *testing.T) {
+					projectRoot := t.TempDir()
+					task := jetbrainsConfigToTask(loadJetBrainsTestData(t, fixture), language)
+
+					require.NoError(t, convert(projectRoot, []*config.Task{task}))
+					golden.AssertDir(t, projectRoot, goldenSnapshotName(fixture, target))
+				})
+			}
+		})
+	}
+}
+
+// parseGoldenTasks parses a tasks.json fixture for a port into projectRoot
+func parseGoldenTasks(t *testing.T, projectRoot, fixture string) []*config.Task {
+	t.Helper()
+
+	tasks, err := vscode.NewTasksParser(projectRoot, nil).ParseTasks(filepath.Join("testdata", fixture))
+	require.NoError(t, err)
+
+	return tasks
+}
+
+// goldenSnapshotName names the snapshot of porting fixture to target
+func goldenSnapshotName(fixture, target string) string {
+	return "ports/" + strings.TrimSuffix(fixture, filepath.Ext(fixture)) + "_to_" + target + ".txt"
+}
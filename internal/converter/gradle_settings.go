@@ -0,0 +1,158 @@
+package converter
+
+import (
+	"encoding/xml"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/shellwords"
+)
+
+// GradleConfigurationType is the JetBrains type of configurations that run Gradle tasks
+const GradleConfigurationType = "GradleRunConfiguration"
+
+// gradleJVMArgsProperty passes JVM options to the Gradle build; JetBrains keeps them as vmOptions
+const gradleJVMArgsProperty = "-Dorg.gradle.jvmargs="
+
+// gradleValueFlags are the Gradle command-line options that take their value as the next argument.
+// The value must stay with its option instead of being mistaken for a task name.
+var gradleValueFlags = []string{
+	"-b", "--build-file",
+	"-c", "--settings-file",
+	"-g", "--gradle-user-home",
+	"-I", "--init-script",
+	"-x", "--exclude-task",
+	"--console", "--include-build", "--priority", "--warning-mode",
+}
+
+// JetBrainsExternalSystemSettings holds what a configuration running build tool tasks (such as
+// Gradle) runs: the tasks, their arguments, the environment and the project they belong to
+type JetBrainsExternalSystemSettings struct {
+	XMLName xml.Name                 `xml:"ExternalSystemSettings"`
+	Options []JetBrainsSettingOption `xml:"option"`
+}
+
+// JetBrainsSettingOption is an ExternalSystemSettings option holding a value, a list or a map
+type JetBrainsSettingOption struct {
+	XMLName xml.Name              `xml:"option"`
+	Name    string                `xml:"name,attr"`
+	Value   string                `xml:"value,attr,omitempty"`
+	List    *JetBrainsSettingList `xml:"list,omitempty"`
+	Map     *JetBrainsSettingMap  `xml:"map,omitempty"`
+}
+
+// JetBrainsSettingList is the list of an option such as taskNames
+type JetBrainsSettingList struct {
+	Values []JetBrainsSettingListValue `xml:"option"`
+}
+
+// JetBrainsSettingListValue is one entry of a JetBrainsSettingList
+type JetBrainsSettingListValue struct {
+	Value string `xml:"value,attr"`
+}
+
+// JetBrainsSettingMap is the map of an option such as env
+type JetBrainsSettingMap struct {
+	Entries []JetBrainsSettingMapEntry `xml:"entry"`
+}
+
+// JetBrainsSettingMapEntry is one key and value of a JetBrainsSettingMap
+type JetBrainsSettingMapEntry struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// gradleArgs is a Gradle command line split the way a JetBrains Gradle configuration stores it
+type gradleArgs struct {
+	taskNames        []string // e.g. ":service-api:bootRun"
+	scriptParameters []string // Options, with the values of those that take one
+	projectDir       string   // From -p or --project-dir
+	vmOptions        string   // From -Dorg.gradle.jvmargs
+}
+
+// splitGradleArgs separates the tasks of a Gradle command line from its options, and pulls out
+// the project directory and build JVM options JetBrains keeps in settings of their own
+func splitGradleArgs(args []string) gradleArgs {
+	var split gradleArgs
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case (arg == "-p" || arg == "--project-dir") && i+1 < len(args):
+			i++
+			split.projectDir = args[i]
+		case strings.HasPrefix(arg, "--project-dir="):
+			split.projectDir = strings.TrimPrefix(arg, "--project-dir=")
+		case strings.HasPrefix(arg, gradleJVMArgsProperty):
+			split.vmOptions = strings.TrimPrefix(arg, gradleJVMArgsProperty)
+		case slices.Contains(gradleValueFlags, arg) && i+1 < len(args):
+			split.scriptParameters = append(split.scriptParameters, arg, args[i+1])
+			i++
+		case strings.HasPrefix(arg, "-"):
+			split.scriptParameters = append(split.scriptParameters, arg)
+		default:
+			split.taskNames = append(split.taskNames, arg)
+		}
+	}
+
+	return split
+}
+
+// gradleSettings describes a Gradle task the way JetBrains Gradle configurations do. The Gradle
+// project is the task's -p directory (relative to its working directory), or else the working
+// directory itself, so tasks of included builds and submodules keep running in their project.
+func (c *VSCodeToJetBrainsConverter) gradleSettings(task *config.Task) *JetBrainsExternalSystemSettings {
+	split := splitGradleArgs(task.Args)
+
+	projectPath := task.Cwd
+	if split.projectDir != "" {
+		projectPath = split.projectDir
+
+		if !filepath.IsAbs(projectPath) && !strings.HasPrefix(projectPath, "${") {
+			base := task.Cwd
+			if base == "" {
+				base = c.projectRoot
+			}
+
+			projectPath = filepath.Join(base, projectPath)
+		}
+	}
+
+	var options []JetBrainsSettingOption
+
+	if len(task.Env) > 0 {
+		envMap := &JetBrainsSettingMap{}
+
+		for _, key := range slices.Sorted(maps.Keys(task.Env)) {
+			envMap.Entries = append(envMap.Entries, JetBrainsSettingMapEntry{Key: key, Value: c.convertVSCodeVariables(task.Env[key])})
+		}
+
+		options = append(options, JetBrainsSettingOption{Name: "env", Map: envMap})
+	}
+
+	options = append(options,
+		JetBrainsSettingOption{Name: "externalProjectPath", Value: c.workingDirectory(projectPath)},
+		JetBrainsSettingOption{Name: "externalSystemIdString", Value: "GRADLE"},
+	)
+
+	if len(split.scriptParameters) > 0 {
+		options = append(options, JetBrainsSettingOption{Name: "scriptParameters", Value: shellwords.Join(split.scriptParameters)})
+	}
+
+	taskNames := &JetBrainsSettingList{}
+	for _, name := range split.taskNames {
+		taskNames.Values = append(taskNames.Values, JetBrainsSettingListValue{Value: name})
+	}
+
+	options = append(options, JetBrainsSettingOption{Name: "taskNames", List: taskNames})
+
+	if split.vmOptions != "" {
+		options = append(options, JetBrainsSettingOption{Name: "vmOptions", Value: split.vmOptions})
+	}
+
+	return &JetBrainsExternalSystemSettings{Options: options}
+}
@@ -0,0 +1,330 @@
+package converter
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/shellwords"
+)
+
+// Shell dialects supported by the shell script target
+const (
+	ShellBash  = "bash"
+	ShellPOSIX = "sh"
+)
+
+// invalidShellFunctionChars matches characters that cannot appear in a POSIX function name
+var invalidShellFunctionChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// jetbrainsProjectVariables are JetBrains path macros that map to the script's project directory
+var jetbrainsProjectVariables = []string{"$PROJECT_DIR$", "$MODULE_DIR$"}
+
+// JetBrainsToShellConverter converts JetBrains run configurations to a standalone shell script
+type JetBrainsToShellConverter struct {
+	WriteOptions
+	projectRoot string
+	outputPath  string
+	verbose     bool
+	shell       string
+	logger      *slog.Logger
+}
+
+// NewJetBrainsToShellConverter creates a new converter that writes bash scripts
+func NewJetBrainsToShellConverter(projectRoot, outputPath string, verbose bool, logger *slog.Logger) *JetBrainsToShellConverter {
+	return &JetBrainsToShellConverter{
+		projectRoot:  projectRoot,
+		outputPath:   outputPath,
+		verbose:      verbose,
+		shell:        ShellBash,
+		WriteOptions: defaultWriteOptions(),
+		logger:       logging.OrDiscard(logger).With(logging.KeyComponent, "jetbrains-to-shell"),
+	}
+}
+
+// SetShell selects the script dialect, ShellBash or ShellPOSIX
+func (c *JetBrainsToShellConverter) SetShell(shell string) {
+	c.shell = shell
+}
+
+// ConvertTasks writes JetBrains tasks to a run.sh script with one function per configuration
+func (c *JetBrainsToShellConverter) ConvertTasks(tasks []*config.Task, dryRun bool) (err error) {
+	if c.verbose {
+		fmt.Fprintf(c.Out, "🔄 Converting %d JetBrains configurations to a %s script...\n", len(tasks), c.shell)
+	}
+
+	// Filter only JetBrains tasks
+	jetBrainsTasks := make([]*config.Task, 0)
+
+	for _, task := range tasks {
+		if task.Type != config.TypeJetBrains {
+			c.Report.Skip(task, fmt.Sprintf("not a JetBrains configuration (type %s)", task.Type))
+			continue
+		}
+
+		jetBrainsTasks = append(jetBrainsTasks, task)
+	}
+
+	if len(jetBrainsTasks) == 0 {
+		fmt.Fprintf(c.Out, "⚠️  No JetBrains configurations found to convert\n")
+		return nil
+	}
+
+	// Determine output path
+	outputPath, err := ResolveFileOutput(c.outputPath, filepath.Join(c.projectRoot, "run.sh"))
+	if err != nil {
+		return err
+	}
+
+	if c.verbose {
+		fmt.Fprintf(c.Out, "📁 Output file: %s\n", outputPath)
+	}
+
+	if !dryRun {
+		if err := ensureWritableDir(filepath.Dir(outputPath)); err != nil {
+			return err
+		}
+	}
+
+	// Every function lands in the one script, so they all fail together
+	content, entries := c.generateScript(jetBrainsTasks, outputPath)
+	defer func() { c.Report.Add(failEntries(entries, err)...) }()
+
+	if dryRun {
+		previewFile(c.Context, c.Out, "script", outputPath, withScriptProvenance(c.shebang(), []byte(content)), c.FullPreview)
+	} else {
+		if err := c.Guard.Check(c.Context, outputPath); err != nil {
+			return err
+		}
+
+		// The script is meant to be run directly, so make it executable
+		if err := WriteFileAtomic(outputPath, withScriptProvenance(c.shebang(), []byte(content)), 0755); err != nil {
+			return fmt.Errorf("failed to write shell script: %w", err)
+		}
+
+		if err := os.Chmod(outputPath, 0755); err != nil {
+			return fmt.Errorf("failed to make shell script executable: %w", err)
+		}
+
+		if c.verbose {
+			fmt.Fprintf(c.Out, "✅ Successfully created %s\n", outputPath)
+		}
+	}
+
+	fmt.Fprintf(c.Out, "✅ Successfully converted %d/%d JetBrains configurations\n", countOutcome(entries, OutcomeConverted), len(jetBrainsTasks))
+
+	return nil
+}
+
+// shebang returns the interpreter line for the selected dialect
+func (c *JetBrainsToShellConverter) shebang() string {
+	if c.shell == ShellPOSIX {
+		return "#!/bin/sh"
+	}
+
+	return "#!/usr/bin/env bash"
+}
+
+// generateScript renders the script body (without shebang) and returns the report entry of each task
+func (c *JetBrainsToShellConverter) generateScript(tasks []*config.Task, outputPath string) (string, []ReportEntry) {
+	var functions strings.Builder
+
+	var cases strings.Builder
+
+	names := make([]string, 0, len(tasks))
+	seen := make(map[string]int)
+	entries := make([]ReportEntry, 0, len(tasks))
+
+	for _, task := range tasks {
+		entry := newReportEntry(task, OutcomeConverted)
+
+		body, err := c.functionBody(task, &entry)
+		if err != nil {
+			c.logger.Warn("failed to convert task", logging.KeyTask, task.Name, "error", err)
+
+			entry.Outcome, entry.Reason = OutcomeFailed, err.Error()
+			entries = append(entries, entry)
+
+			continue
+		}
+
+		function := "run_" + strings.Trim(invalidShellFunctionChars.ReplaceAllString(task.Name, "_"), "_")
+
+		// Disambiguate names that sanitize to the same function
+		seen[function]++
+		if seen[function] > 1 {
+			function = fmt.Sprintf("%s_%d", function, seen[function])
+		}
+
+		names = append(names, task.Name)
+
+		entry.TargetFile, entry.TargetName, entry.TargetType = outputPath, function, "function"
+		entry.dropUnused(task)
+		entries = append(entries, entry)
+
+		functions.WriteString(fmt.Sprintf("\n# %s\n", strings.ReplaceAll(task.Name, "\n", " ")))
+		functions.WriteString(function + "() (\n")
+		functions.WriteString(body)
+		functions.WriteString(")\n")
+
+		cases.WriteString(fmt.Sprintf("    %s) shift; %s \"$@\" ;;\n", posixQuote(task.Name), function))
+	}
+
+	var b strings.Builder
+
+	b.WriteString("# Run configurations ported from JetBrains; usage: ./" + filepath.Base(outputPath) + " <name> [args...]\n")
+
+	if c.shell == ShellPOSIX {
+		b.WriteString("set -eu\n\n")
+	} else {
+		b.WriteString("set -euo pipefail\n\n")
+	}
+
+	b.WriteString(fmt.Sprintf("PROJECT_DIR=$(CDPATH= cd -- \"$(dirname -- \"$0\")\"/%s && pwd)\n", posixQuote(c.projectDirFrom(outputPath))))
+	b.WriteString(functions.String())
+	b.WriteString("\nusage() {\n")
+	b.WriteString("    echo \"usage: $0 <name> [args...]\"\n")
+	b.WriteString("    echo\n")
+	b.WriteString("    echo \"configurations:\"\n")
+
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("    echo %s\n", posixQuote("  "+name)))
+	}
+
+	b.WriteString("}\n\n")
+	b.WriteString("case \"${1:-}\" in\n")
+	b.WriteString(cases.String())
+	b.WriteString("    \"\"|-h|--help) usage ;;\n")
+	b.WriteString("    *) echo \"unknown configuration: $1\" >&2; usage >&2; exit 1 ;;\n")
+	b.WriteString("esac\n")
+
+	return b.String(), entries
+}
+
+// functionBody renders the env exports, cd and exec lines of a configuration's function,
+// recording the fields it carries over in entry
+func (c *JetBrainsToShellConverter) functionBody(task *config.Task, entry *ReportEntry) (string, error) {
+	if strings.TrimSpace(task.Command) == "" {
+		return "", fmt.Errorf("empty command in task '%s'", task.Name)
+	}
+
+	entry.use("env", "cwd", "args")
+
+	var b strings.Builder
+
+	// Sort keys for deterministic ordering
+	keys := make([]string, 0, len(task.Env))
+	for key := range task.Env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		b.WriteString(fmt.Sprintf("    export %s=%s\n", key, c.shellWord(task.Env[key])))
+	}
+
+	b.WriteString(fmt.Sprintf("    cd %s\n", c.shellWord(c.portableCwd(task.Cwd))))
+
+	// A shell script runs as written, over as many lines as it has; splitting it into words
+	// would break it apart
+	if task.Execution == config.ExecutionShell {
+		shell := task.Shell
+		if shell == "" {
+			shell = "sh"
+		}
+
+		words := []string{c.shellWord(shell), "-c", c.shellWord(task.Command), c.shellWord(shell)}
+		for _, arg := range task.Args {
+			words = append(words, c.shellWord(arg))
+		}
+
+		b.WriteString(fmt.Sprintf("    exec %s \"$@\"\n", strings.Join(words, " ")))
+
+		return b.String(), nil
+	}
+
+	parts := shellwords.Split(task.Command)
+	words := make([]string, 0, len(parts)+len(task.Args))
+	for _, word := range append(parts, task.Args...) {
+		words = append(words, c.shellWord(word))
+	}
+
+	b.WriteString(fmt.Sprintf("    exec %s \"$@\"\n", strings.Join(words, " ")))
+
+	return b.String(), nil
+}
+
+// portableCwd expresses cwd in terms of $PROJECT_DIR$ so the script works from any checkout location
+func (c *JetBrainsToShellConverter) portableCwd(cwd string) string {
+	if cwd == "" {
+		return "$PROJECT_DIR$"
+	}
+
+	absRoot, rootErr := filepath.Abs(c.projectRoot)
+	absCwd, cwdErr := filepath.Abs(cwd)
+
+	if rootErr != nil || cwdErr != nil || strings.Contains(cwd, "$") {
+		return cwd
+	}
+
+	rel, err := filepath.Rel(absRoot, absCwd)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return absCwd
+	}
+
+	if rel == "." {
+		return "$PROJECT_DIR$"
+	}
+
+	return "$PROJECT_DIR$/" + filepath.ToSlash(rel)
+}
+
+// projectDirFrom returns the project root relative to the directory the script is written to
+func (c *JetBrainsToShellConverter) projectDirFrom(outputPath string) string {
+	absRoot, rootErr := filepath.Abs(c.projectRoot)
+	absDir, dirErr := filepath.Abs(filepath.Dir(outputPath))
+
+	if rootErr != nil || dirErr != nil {
+		return "."
+	}
+
+	rel, err := filepath.Rel(absDir, absRoot)
+	if err != nil {
+		return absRoot
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// shellWord quotes value as a single shell word, expanding JetBrains project macros to "$PROJECT_DIR"
+func (c *JetBrainsToShellConverter) shellWord(value string) string {
+	for _, variable := range jetbrainsProjectVariables {
+		value = strings.ReplaceAll(value, variable, "\x00")
+	}
+
+	if !strings.Contains(value, "\x00") {
+		return posixQuote(value)
+	}
+
+	// Quote the literal segments and splice the variable between them
+	segments := strings.Split(value, "\x00")
+	quoted := make([]string, 0, len(segments))
+
+	for _, segment := range segments {
+		if segment == "" {
+			quoted = append(quoted, "")
+			continue
+		}
+
+		quoted = append(quoted, posixQuote(segment))
+	}
+
+	return strings.Join(quoted, `"$PROJECT_DIR"`)
+}
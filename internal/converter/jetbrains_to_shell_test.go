@@ -0,0 +1,154 @@
+package converter
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJetBrainsToShellConverter(t *testing.T) {
+	t.Run("NewJetBrainsToShellConverter", func(t *testing.T) {
+		converter := NewJetBrainsToShellConverter("/test/project", "run.sh", true, nil)
+
+		require.NotNil(t, converter)
+		require.Equal(t, "/test/project", converter.projectRoot)
+		require.Equal(t, "run.sh", converter.outputPath)
+		require.Equal(t, ShellBash, converter.shell)
+		require.True(t, converter.verbose)
+	})
+
+	t.Run("ConvertTasks", func(t *testing.T) {
+		t.Run("should write an executable script with a dispatcher", func(t *testing.T) {
+			projectRoot := t.TempDir()
+			tasks := []*config.Task{
+				{
+					Name:    "Application",
+					Type:    config.TypeJetBrains,
+					Command: "java",
+					Args:    []string{"-cp", "build/classes", "com.example.Main", "--name", "it's me"},
+					Cwd:     filepath.Join(projectRoot, "server"),
+					Env:     map[string]string{"JAVA_OPTS": "-Xmx1g -server", "CONFIG": "$PROJECT_DIR$/config.yml"},
+				},
+				{
+					Name:    "Gradle Build",
+					Type:    config.TypeJetBrains,
+					Command: "gradle",
+					Args:    []string{"build"},
+					Cwd:     projectRoot,
+				},
+				{
+					Name:    "build",
+					Type:    config.TypeVSCodeTask,
+					Command: "make",
+				},
+			}
+
+			converter := NewJetBrainsToShellConverter(projectRoot, "", false, nil)
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			scriptPath := filepath.Join(projectRoot, "run.sh")
+			info, err := os.Stat(scriptPath)
+			require.NoError(t, err)
+			require.NotZero(t, info.Mode()&0o100, "script should be executable")
+
+			data, err := os.ReadFile(scriptPath)
+			require.NoError(t, err)
+
+			content := string(data)
+			require.Contains(t, content, "#!/usr/bin/env bash\n# "+ProvenanceMarker+"\n")
+			require.Contains(t, content, "set -euo pipefail\n")
+			require.Contains(t, content, "run_Application() (\n")
+			require.Contains(t, content, "    export CONFIG=\"$PROJECT_DIR\"/config.yml\n")
+			require.Contains(t, content, "    export JAVA_OPTS='-Xmx1g -server'\n")
+			require.Contains(t, content, "    cd \"$PROJECT_DIR\"/server\n")
+			require.Contains(t, content, `    exec java -cp build/classes com.example.Main --name 'it'\''s me' "$@"`)
+			require.Contains(t, content, "    'Gradle Build') shift; run_Gradle_Build \"$@\" ;;\n")
+			require.NotContains(t, content, "make")
+		})
+
+		t.Run("should use POSIX sh when requested", func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "run.sh")
+			tasks := []*config.Task{{Name: "build", Type: config.TypeJetBrains, Command: "gradle"}}
+
+			converter := NewJetBrainsToShellConverter("/test/project", outputPath, false, nil)
+			converter.SetShell(ShellPOSIX)
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			data, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			require.Contains(t, string(data), "#!/bin/sh\n")
+			require.Contains(t, string(data), "set -eu\n")
+			require.NotContains(t, string(data), "pipefail")
+		})
+
+		t.Run("should not write files in dry run", func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "run.sh")
+			tasks := []*config.Task{{Name: "build", Type: config.TypeJetBrains, Command: "gradle"}}
+
+			converter := NewJetBrainsToShellConverter("/test/project", outputPath, false, nil)
+			require.NoError(t, converter.ConvertTasks(tasks, true))
+			require.NoFileExists(t, outputPath)
+		})
+
+		t.Run("generated script runs the selected configuration", func(t *testing.T) {
+			shPath, err := exec.LookPath("sh")
+			if err != nil {
+				t.Skip("sh not available")
+			}
+
+			projectRoot := t.TempDir()
+			require.NoError(t, os.Mkdir(filepath.Join(projectRoot, "sub dir"), 0o755))
+
+			tasks := []*config.Task{
+				{
+					Name:    "greet",
+					Type:    config.TypeJetBrains,
+					Command: "sh",
+					Args:    []string{"-c", `echo "$GREETING from ${PWD##*/}:" "$@"`, "greet"},
+					Cwd:     filepath.Join(projectRoot, "sub dir"),
+					Env:     map[string]string{"GREETING": "hello world"},
+				},
+			}
+
+			outputPath := filepath.Join(projectRoot, "scripts", "run.sh")
+			converter := NewJetBrainsToShellConverter(projectRoot, outputPath, false, nil)
+			converter.SetShell(ShellPOSIX)
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			output, err := exec.Command(shPath, outputPath, "greet", "extra arg").CombinedOutput()
+			require.NoError(t, err, string(output))
+			require.Equal(t, "hello world from sub dir: extra arg\n", string(output))
+
+			output, err = exec.Command(shPath, outputPath, "missing").CombinedOutput()
+			require.Error(t, err)
+			require.Contains(t, string(output), "unknown configuration: missing")
+		})
+	})
+
+	t.Run("shellWord", func(t *testing.T) {
+		converter := NewJetBrainsToShellConverter("/test/project", "", false, nil)
+
+		tests := []struct {
+			input    string
+			expected string
+		}{
+			{"plain", "plain"},
+			{"", "''"},
+			{"two words", "'two words'"},
+			{"$HOME", "'$HOME'"},
+			{"$PROJECT_DIR$", `"$PROJECT_DIR"`},
+			{"$MODULE_DIR$/lib dir", `"$PROJECT_DIR"'/lib dir'`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				require.Equal(t, tt.expected, converter.shellWord(tt.input))
+			})
+		}
+	})
+}
@@ -1,28 +1,33 @@
 package converter
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"log/slog"
 	"path/filepath"
 	"strings"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/shellwords"
 )
 
 // JetBrainsToVSCodeConverter converts JetBrains run configurations to VSCode tasks
 type JetBrainsToVSCodeConverter struct {
+	WriteOptions
 	projectRoot string
 	outputPath  string
 	verbose     bool
+	logger      *slog.Logger
 }
 
 // NewJetBrainsToVSCodeConverter creates a new converter
-func NewJetBrainsToVSCodeConverter(projectRoot, outputPath string, verbose bool) *JetBrainsToVSCodeConverter {
+func NewJetBrainsToVSCodeConverter(projectRoot, outputPath string, verbose bool, logger *slog.Logger) *JetBrainsToVSCodeConverter {
 	return &JetBrainsToVSCodeConverter{
-		projectRoot: projectRoot,
-		outputPath:  outputPath,
-		verbose:     verbose,
+		projectRoot:  projectRoot,
+		outputPath:   outputPath,
+		verbose:      verbose,
+		WriteOptions: defaultWriteOptions(),
+		logger:       logging.OrDiscard(logger).With(logging.KeyComponent, "jetbrains-to-vscode-tasks"),
 	}
 }
 
@@ -35,10 +40,13 @@ type VSCodeTasksFile struct {
 // VSCodeTask represents a single task in tasks.json
 type VSCodeTask struct {
 	Label          string             `json:"label"`
-	Type           string             `json:"type"`
+	Type           string             `json:"type,omitempty"` // Empty for aggregate tasks, which only run their dependsOn
 	Command        string             `json:"command,omitempty"`
 	Args           []string           `json:"args,omitempty"`
+	DependsOn      []string           `json:"dependsOn,omitempty"`
+	DependsOrder   string             `json:"dependsOrder,omitempty"`
 	Group          interface{}        `json:"group,omitempty"`
+	Detail         string             `json:"detail,omitempty"` // Carries the `[tags: a,b]` annotation so tags survive porting
 	Options        *VSCodeTaskOptions `json:"options,omitempty"`
 	ProblemMatcher []string           `json:"problemMatcher,omitempty"`
 }
@@ -50,26 +58,41 @@ type VSCodeTaskOptions struct {
 }
 
 // ConvertTasks converts JetBrains tasks to VSCode tasks.json format
-func (c *JetBrainsToVSCodeConverter) ConvertTasks(tasks []*config.Task, dryRun bool) error {
+func (c *JetBrainsToVSCodeConverter) ConvertTasks(tasks []*config.Task, dryRun bool) (err error) {
 	if c.verbose {
-		fmt.Printf("🔄 Converting %d JetBrains configurations to VSCode tasks format...\n", len(tasks))
+		fmt.Fprintf(c.Out, "🔄 Converting %d JetBrains configurations to VSCode tasks format...\n", len(tasks))
 	}
 
 	// Filter only JetBrains tasks
 	jetBrainsTasks := make([]*config.Task, 0)
 	for _, task := range tasks {
-		if task.Type == config.TypeJetBrains {
-			jetBrainsTasks = append(jetBrainsTasks, task)
+		if task.Type != config.TypeJetBrains {
+			c.Report.Skip(task, fmt.Sprintf("not a JetBrains configuration (type %s)", task.Type))
+			continue
 		}
+
+		jetBrainsTasks = append(jetBrainsTasks, task)
 	}
 
 	if len(jetBrainsTasks) == 0 {
-		fmt.Printf("⚠️  No JetBrains configurations found to convert\n")
+		fmt.Fprintf(c.Out, "⚠️  No JetBrains configurations found to convert\n")
 		return nil
 	}
 
 	if c.verbose {
-		fmt.Printf("📋 Converting %d JetBrains configurations\n", len(jetBrainsTasks))
+		fmt.Fprintf(c.Out, "📋 Converting %d JetBrains configurations\n", len(jetBrainsTasks))
+	}
+
+	// Determine output path
+	outputPath, err := ResolveFileOutput(c.outputPath, filepath.Join(c.projectRoot, ".vscode", "tasks.json"))
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		if err := ensureWritableDir(filepath.Dir(outputPath)); err != nil {
+			return err
+		}
 	}
 
 	// Convert tasks
@@ -78,72 +101,90 @@ func (c *JetBrainsToVSCodeConverter) ConvertTasks(tasks []*config.Task, dryRun b
 		Tasks:   make([]VSCodeTask, 0, len(jetBrainsTasks)),
 	}
 
+	// Every task lands in the one tasks.json, so they all fail together
+	entries := make([]ReportEntry, 0, len(jetBrainsTasks))
+	defer func() { c.Report.Add(failEntries(entries, err)...) }()
+
 	for _, task := range jetBrainsTasks {
-		vscodeTask, err := c.convertSingleTask(task)
+		entry := newReportEntry(task, OutcomeConverted)
+
+		vscodeTask, err := c.convertSingleTask(task, &entry)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: failed to convert task '%s': %v\n", task.Name, err)
+			c.logger.Warn("failed to convert task", logging.KeyTask, task.Name, "error", err)
+
+			entry.Outcome, entry.Reason = OutcomeFailed, err.Error()
+			entries = append(entries, entry)
+
 			continue
 		}
 
-		vscodeTasksFile.Tasks = append(vscodeTasksFile.Tasks, *vscodeTask)
-	}
+		refs := translateTaskEnvRefs(vscodeTask)
+		entry.approximate(refs.fields...)
 
-	// Determine output path
-	outputPath := c.outputPath
-	if outputPath == "" {
-		outputPath = filepath.Join(c.projectRoot, ".vscode", "tasks.json")
+		for _, warning := range refs.warnings {
+			c.logger.Warn("kept untranslatable env reference", logging.KeyTask, task.Name, "warning", warning)
+
+			entry.Warnings = append(entry.Warnings, warning)
+		}
+
+		entry.TargetFile, entry.TargetName, entry.TargetType = outputPath, vscodeTask.Label, vscodeTask.Type
+		entry.dropUnused(task)
+		entries = append(entries, entry)
+
+		vscodeTasksFile.Tasks = append(vscodeTasksFile.Tasks, *vscodeTask)
 	}
 
 	if c.verbose {
-		fmt.Printf("📁 Output file: %s\n", outputPath)
+		fmt.Fprintf(c.Out, "📁 Output file: %s\n", outputPath)
 	}
 
 	if dryRun {
-		fmt.Printf("   [DRY RUN] Would create: %s\n", outputPath)
-		fmt.Printf("📝 Preview of tasks.json content:\n")
-
-		jsonData, _ := json.MarshalIndent(vscodeTasksFile, "", "    ")
-		fmt.Printf("%s\n", string(jsonData))
-	} else {
-		// Create output directory
-		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+		content, err := renderVSCodeFileAt(c.Context, outputPath, vscodeTasksFile, "tasks", "label")
+		if err != nil {
+			return fmt.Errorf("failed to marshal tasks.json: %w", err)
 		}
 
+		previewFile(c.Context, c.Out, "tasks.json", outputPath, content, c.FullPreview)
+	} else {
 		// Write tasks.json file
 		if err := c.writeVSCodeTasksFile(vscodeTasksFile, outputPath); err != nil {
 			return fmt.Errorf("failed to write tasks.json: %w", err)
 		}
 
 		if c.verbose {
-			fmt.Printf("✅ Successfully created %s\n", outputPath)
+			fmt.Fprintf(c.Out, "✅ Successfully created %s\n", outputPath)
 		}
 	}
 
-	fmt.Printf("✅ Successfully converted %d/%d JetBrains configurations\n", len(vscodeTasksFile.Tasks), len(jetBrainsTasks))
+	fmt.Fprintf(c.Out, "✅ Successfully converted %d/%d JetBrains configurations\n", len(vscodeTasksFile.Tasks), len(jetBrainsTasks))
 
 	return nil
 }
 
-// convertSingleTask converts a single JetBrains task to VSCode format
-func (c *JetBrainsToVSCodeConverter) convertSingleTask(task *config.Task) (*VSCodeTask, error) {
+// convertSingleTask converts a single JetBrains task to VSCode format, recording the fields it
+// carries over in entry
+func (c *JetBrainsToVSCodeConverter) convertSingleTask(task *config.Task, entry *ReportEntry) (*VSCodeTask, error) {
 	vscodeTask := &VSCodeTask{
-		Label: task.Name,
-		Type:  "shell", // Default to shell type
+		Label:  task.Name,
+		Type:   "shell", // Default to shell type
+		Detail: config.TagAnnotation(task),
 	}
 
 	// Convert based on the task command and structure
-	if err := c.determineVSCodeTaskDetails(task, vscodeTask); err != nil {
+	if err := c.determineVSCodeTaskDetails(task, vscodeTask, entry); err != nil {
 		return nil, err
 	}
 
+	entry.use("cwd", "env")
+
 	// Set working directory (convert JetBrains variables)
 	if task.Cwd != "" {
 		if vscodeTask.Options == nil {
 			vscodeTask.Options = &VSCodeTaskOptions{}
 		}
 
-		vscodeTask.Options.Cwd = c.convertJetBrainsVariables(task.Cwd)
+		// The parser resolved $PROJECT_DIR$ to an absolute path, which only fits this checkout
+		vscodeTask.Options.Cwd = relativizeToWorkspace(c.convertJetBrainsVariables(task.Cwd), c.projectRoot)
 	}
 
 	// Convert environment variables
@@ -165,9 +206,37 @@ func (c *JetBrainsToVSCodeConverter) convertSingleTask(task *config.Task) (*VSCo
 }
 
 // determineVSCodeTaskDetails sets command and args based on the JetBrains task
-func (c *JetBrainsToVSCodeConverter) determineVSCodeTaskDetails(task *config.Task, vscodeTask *VSCodeTask) error {
+func (c *JetBrainsToVSCodeConverter) determineVSCodeTaskDetails(task *config.Task, vscodeTask *VSCodeTask, entry *ReportEntry) error {
+	// Aggregate tasks have nothing to run but their dependencies
+	if task.IsAggregate() {
+		entry.use("dependsOn", "dependsOrder")
+
+		vscodeTask.Type = ""
+		vscodeTask.DependsOn = task.DependsOn
+
+		if task.DependsOrder == config.DependsOrderSequence {
+			vscodeTask.DependsOrder = config.DependsOrderSequence
+		}
+
+		return nil
+	}
+
+	entry.use("args")
+
+	// A shell script is a command line of its own, possibly over several lines, and VSCode
+	// passes shell task commands to the shell verbatim
+	if task.Execution == config.ExecutionShell {
+		vscodeTask.Command = task.Command
+
+		if len(task.Args) > 0 {
+			vscodeTask.Args = task.Args
+		}
+
+		return nil
+	}
+
 	// Parse the command from task.Command which might contain the full command line
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 	if len(parts) == 0 {
 		return fmt.Errorf("empty command in task '%s'", task.Name)
 	}
@@ -219,26 +288,21 @@ func (c *JetBrainsToVSCodeConverter) determineTaskGroup(task *config.Task) inter
 
 // convertJetBrainsVariables converts JetBrains variables to VSCode format
 func (c *JetBrainsToVSCodeConverter) convertJetBrainsVariables(input string) string {
-	result := input
-
-	// Convert JetBrains variables to VSCode equivalents
-	result = strings.ReplaceAll(result, "$PROJECT_DIR$", "${workspaceFolder}")
-	result = strings.ReplaceAll(result, "$MODULE_DIR$", "${workspaceFolder}")
-	result = strings.ReplaceAll(result, "$FileDir$", "${fileDirname}")
-	result = strings.ReplaceAll(result, "$FileName$", "${fileBasename}")
-	result = strings.ReplaceAll(result, "$FilePath$", "${file}")
-
-	return result
+	return ConvertJetBrainsVariables(input)
 }
 
-// writeVSCodeTasksFile writes the VSCode tasks file
+// writeVSCodeTasksFile writes the VSCode tasks file, editing an existing one in place
 func (c *JetBrainsToVSCodeConverter) writeVSCodeTasksFile(tasksFile *VSCodeTasksFile, outputPath string) error {
-	jsonData, err := json.MarshalIndent(tasksFile, "", "    ")
+	content, err := renderVSCodeFileAt(c.Context, outputPath, tasksFile, "tasks", "label")
 	if err != nil {
 		return fmt.Errorf("failed to marshal tasks.json: %w", err)
 	}
 
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
+	if err := c.Guard.Check(c.Context, outputPath); err != nil {
+		return err
+	}
+
+	if err := WriteFileAtomic(outputPath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
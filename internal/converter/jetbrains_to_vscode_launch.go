@@ -3,26 +3,33 @@ package converter
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/shellwords"
 )
 
 // JetBrainsToVSCodeLaunchConverter converts JetBrains run configurations to VSCode launch configs
 type JetBrainsToVSCodeLaunchConverter struct {
+	WriteOptions
 	projectRoot string
 	outputPath  string
 	verbose     bool
+	logger      *slog.Logger
 }
 
 // NewJetBrainsToVSCodeLaunchConverter creates a new launch converter
-func NewJetBrainsToVSCodeLaunchConverter(projectRoot, outputPath string, verbose bool) *JetBrainsToVSCodeLaunchConverter {
+func NewJetBrainsToVSCodeLaunchConverter(projectRoot, outputPath string, verbose bool, logger *slog.Logger) *JetBrainsToVSCodeLaunchConverter {
 	return &JetBrainsToVSCodeLaunchConverter{
-		projectRoot: projectRoot,
-		outputPath:  outputPath,
-		verbose:     verbose,
+		projectRoot:  projectRoot,
+		outputPath:   outputPath,
+		verbose:      verbose,
+		WriteOptions: defaultWriteOptions(),
+		logger:       logging.OrDiscard(logger).With(logging.KeyComponent, "jetbrains-to-vscode-launch"),
 	}
 }
 
@@ -40,87 +47,135 @@ type VSCodeLaunchConfig struct {
 	Program     string            `json:"program,omitempty"`
 	Module      string            `json:"module,omitempty"`
 	MainClass   string            `json:"mainClass,omitempty"`
+	JavaExec    string            `json:"javaExec,omitempty"`
+	VMArgs      string            `json:"vmArgs,omitempty"`
+	Python      string            `json:"python,omitempty"`
 	Args        []string          `json:"args,omitempty"`
 	Cwd         string            `json:"cwd,omitempty"`
 	Env         map[string]string `json:"env,omitempty"`
 	Console     string            `json:"console,omitempty"`
 	StopOnEntry bool              `json:"stopOnEntry,omitempty"`
+
+	preserved map[string]json.RawMessage // Fields kept from the original VSCode configuration
+	guessed   []string                   // Fields given a default because the source did not tell
 }
 
 // ConvertToLaunch converts JetBrains tasks to VSCode launch.json format
-func (c *JetBrainsToVSCodeLaunchConverter) ConvertToLaunch(tasks []*config.Task, dryRun bool) error {
+func (c *JetBrainsToVSCodeLaunchConverter) ConvertToLaunch(tasks []*config.Task, dryRun bool) (err error) {
 	if c.verbose {
-		fmt.Printf("🔄 Converting %d JetBrains configurations to VSCode launch format...\n", len(tasks))
+		fmt.Fprintf(c.Out, "🔄 Converting %d JetBrains configurations to VSCode launch format...\n", len(tasks))
 	}
 
 	// Filter only JetBrains tasks that can be converted to launch configs
 	jetBrainsTasks := make([]*config.Task, 0)
 	for _, task := range tasks {
-		if task.Type == config.TypeJetBrains && c.canConvertToLaunch(task) {
+		switch {
+		case task.Type != config.TypeJetBrains:
+			c.Report.Skip(task, fmt.Sprintf("not a JetBrains configuration (type %s)", task.Type))
+		case !c.canConvertToLaunch(task):
+			c.Report.Skip(task, "not an application configuration that can be launched")
+		default:
 			jetBrainsTasks = append(jetBrainsTasks, task)
 		}
 	}
 
 	if len(jetBrainsTasks) == 0 {
-		fmt.Printf("⚠️  No JetBrains configurations suitable for launch conversion found\n")
-		fmt.Printf("💡 Note: Only Application-type JetBrains configs can be converted to launch configurations\n")
+		fmt.Fprintf(c.Out, "⚠️  No JetBrains configurations suitable for launch conversion found\n")
+		fmt.Fprintf(c.Out, "💡 Note: Only Application-type JetBrains configs can be converted to launch configurations\n")
 
 		return nil
 	}
 
 	if c.verbose {
-		fmt.Printf("📋 Converting %d suitable JetBrains configurations\n", len(jetBrainsTasks))
+		fmt.Fprintf(c.Out, "📋 Converting %d suitable JetBrains configurations\n", len(jetBrainsTasks))
+	}
+
+	// Determine output path
+	outputPath, err := ResolveFileOutput(c.outputPath, filepath.Join(c.projectRoot, ".vscode", "launch.json"))
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		if err := ensureWritableDir(filepath.Dir(outputPath)); err != nil {
+			return err
+		}
 	}
 
+	// Configurations regenerated under the same name keep the fields taskporter doesn't model
+	originals := c.originalLaunchConfigs(jetBrainsTasks, outputPath)
+
 	// Convert tasks
 	launchFile := &VSCodeLaunchFile{
 		Version:        "0.2.0",
 		Configurations: make([]VSCodeLaunchConfig, 0, len(jetBrainsTasks)),
 	}
 
+	// Every configuration lands in the one launch.json, so they all fail together
+	entries := make([]ReportEntry, 0, len(jetBrainsTasks))
+	defer func() { c.Report.Add(failEntries(entries, err)...) }()
+
 	for _, task := range jetBrainsTasks {
-		launchConfig, err := c.convertSingleTaskToLaunch(task)
+		entry := newReportEntry(task, OutcomeConverted)
+
+		launchConfig, err := c.convertSingleTaskToLaunch(task, &entry)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: failed to convert task '%s': %v\n", task.Name, err)
+			c.logger.Warn("failed to convert task", logging.KeyTask, task.Name, "error", err)
+
+			entry.Outcome, entry.Reason = OutcomeFailed, err.Error()
+			entries = append(entries, entry)
+
 			continue
 		}
 
-		launchFile.Configurations = append(launchFile.Configurations, *launchConfig)
-	}
+		refs := translateLaunchEnvRefs(launchConfig)
+		entry.approximate(refs.fields...)
 
-	// Determine output path
-	outputPath := c.outputPath
-	if outputPath == "" {
-		outputPath = filepath.Join(c.projectRoot, ".vscode", "launch.json")
+		for _, warning := range refs.warnings {
+			c.logger.Warn("kept untranslatable env reference", logging.KeyTask, task.Name, "warning", warning)
+
+			entry.Warnings = append(entry.Warnings, warning)
+		}
+
+		if original, ok := originals[task.Name]; ok {
+			if err := preserveOriginalFields(launchConfig, original); err != nil {
+				c.logger.Warn("failed to preserve original launch fields", logging.KeyTask, task.Name, "error", err)
+
+				entry.Warnings = append(entry.Warnings, fmt.Sprintf("fields of the existing launch configuration were not preserved: %v", err))
+			}
+		}
+
+		entry.TargetFile, entry.TargetName, entry.TargetType = outputPath, launchConfig.Name, launchConfig.Type
+		entry.dropUnused(task)
+		entry.approximate(launchConfig.guessed...)
+		entries = append(entries, entry)
+
+		launchFile.Configurations = append(launchFile.Configurations, *launchConfig)
 	}
 
 	if c.verbose {
-		fmt.Printf("📁 Output file: %s\n", outputPath)
+		fmt.Fprintf(c.Out, "📁 Output file: %s\n", outputPath)
 	}
 
 	if dryRun {
-		fmt.Printf("   [DRY RUN] Would create: %s\n", outputPath)
-		fmt.Printf("📝 Preview of launch.json content:\n")
-
-		jsonData, _ := json.MarshalIndent(launchFile, "", "    ")
-		fmt.Printf("%s\n", string(jsonData))
-	} else {
-		// Create output directory
-		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+		content, err := renderVSCodeFileAt(c.Context, outputPath, launchFile, "configurations", "name")
+		if err != nil {
+			return fmt.Errorf("failed to marshal launch.json: %w", err)
 		}
 
+		previewFile(c.Context, c.Out, "launch.json", outputPath, content, c.FullPreview)
+	} else {
 		// Write launch.json file
 		if err := c.writeVSCodeLaunchFile(launchFile, outputPath); err != nil {
 			return fmt.Errorf("failed to write launch.json: %w", err)
 		}
 
 		if c.verbose {
-			fmt.Printf("✅ Successfully created %s\n", outputPath)
+			fmt.Fprintf(c.Out, "✅ Successfully created %s\n", outputPath)
 		}
 	}
 
-	fmt.Printf("✅ Successfully converted %d/%d JetBrains configurations to launch configs\n", len(launchFile.Configurations), len(jetBrainsTasks))
+	fmt.Fprintf(c.Out, "✅ Successfully converted %d/%d JetBrains configurations to launch configs\n", len(launchFile.Configurations), len(jetBrainsTasks))
 
 	return nil
 }
@@ -143,17 +198,19 @@ func (c *JetBrainsToVSCodeLaunchConverter) canConvertToLaunch(task *config.Task)
 }
 
 // convertSingleTaskToLaunch converts a single JetBrains task to VSCode launch format
-func (c *JetBrainsToVSCodeLaunchConverter) convertSingleTaskToLaunch(task *config.Task) (*VSCodeLaunchConfig, error) {
+func (c *JetBrainsToVSCodeLaunchConverter) convertSingleTaskToLaunch(task *config.Task, entry *ReportEntry) (*VSCodeLaunchConfig, error) {
 	launchConfig := &VSCodeLaunchConfig{
 		Name:    task.Name,
 		Request: "launch",
 	}
 
 	// Determine launch type and configuration based on command
-	if err := c.determineLaunchType(task, launchConfig); err != nil {
+	if err := c.determineLaunchType(task, launchConfig, entry); err != nil {
 		return nil, err
 	}
 
+	entry.use("cwd", "env")
+
 	// Set working directory (convert JetBrains variables)
 	if task.Cwd != "" {
 		launchConfig.Cwd = c.convertJetBrainsVariables(task.Cwd)
@@ -161,6 +218,10 @@ func (c *JetBrainsToVSCodeLaunchConverter) convertSingleTaskToLaunch(task *confi
 		launchConfig.Cwd = "${workspaceFolder}"
 	}
 
+	// The parser resolved $PROJECT_DIR$ to absolute paths, which only fit this checkout
+	launchConfig.Cwd = relativizeToWorkspace(launchConfig.Cwd, c.projectRoot)
+	launchConfig.Program = relativizeToWorkspace(launchConfig.Program, c.projectRoot)
+
 	// Convert environment variables
 	if len(task.Env) > 0 {
 		launchConfig.Env = make(map[string]string)
@@ -172,11 +233,14 @@ func (c *JetBrainsToVSCodeLaunchConverter) convertSingleTaskToLaunch(task *confi
 	return launchConfig, nil
 }
 
-// determineLaunchType sets the appropriate launch type and configuration
-func (c *JetBrainsToVSCodeLaunchConverter) determineLaunchType(task *config.Task, launchConfig *VSCodeLaunchConfig) error {
+// determineLaunchType sets the appropriate launch type and configuration, recording the fields
+// it carries over in entry. Every type carries the args.
+func (c *JetBrainsToVSCodeLaunchConverter) determineLaunchType(task *config.Task, launchConfig *VSCodeLaunchConfig, entry *ReportEntry) error {
 	command := strings.ToLower(task.Command)
 	description := strings.ToLower(task.Description)
 
+	entry.use("args")
+
 	if strings.Contains(command, "go") || strings.Contains(description, "goapplicationrunconfiguration") {
 		// Go application
 		launchConfig.Type = "go"
@@ -202,13 +266,20 @@ func (c *JetBrainsToVSCodeLaunchConverter) determineLaunchType(task *config.Task
 		// Extract main class from command or args
 		mainClass := c.extractJavaMainClass(task)
 		if mainClass == "" {
-			return fmt.Errorf("could not determine main class for Java application '%s'", task.Name)
+			mainClass = "Main"
+			launchConfig.guessed = append(launchConfig.guessed, "mainClass")
 		}
 
 		launchConfig.MainClass = mainClass
+		launchConfig.JavaExec = task.RuntimePath
+		entry.use("runtimePath")
+
+		// JVM options come before the main class, program arguments after it
+		vmArgs, args := c.extractJavaArgs(task, mainClass)
+		if len(vmArgs) > 0 {
+			launchConfig.VMArgs = shellwords.Join(vmArgs)
+		}
 
-		// Add program arguments (excluding main class)
-		args := c.extractJavaArgs(task, mainClass)
 		if len(args) > 0 {
 			launchConfig.Args = args
 		}
@@ -216,10 +287,11 @@ func (c *JetBrainsToVSCodeLaunchConverter) determineLaunchType(task *config.Task
 		// Node.js application
 		launchConfig.Type = "node"
 
-		// Extract program path
+		// Extract program path, falling back to a common pattern
 		program := c.extractNodeProgram(task)
 		if program == "" {
-			return fmt.Errorf("could not determine program for Node.js application '%s'", task.Name)
+			program = "${workspaceFolder}/index.js"
+			launchConfig.guessed = append(launchConfig.guessed, "program")
 		}
 
 		launchConfig.Program = c.convertJetBrainsVariables(program)
@@ -232,19 +304,21 @@ func (c *JetBrainsToVSCodeLaunchConverter) determineLaunchType(task *config.Task
 	} else if strings.Contains(command, "python") || strings.Contains(description, "pythonconfigurationtype") {
 		// Python application
 		launchConfig.Type = "python"
+		launchConfig.Python = task.RuntimePath
+		entry.use("runtimePath")
 
 		// Extract program path or module
 		program := c.extractPythonProgram(task)
-		if program == "" {
-			// Check if this is a module execution
-			module := c.extractPythonModule(task)
-			if module == "" {
-				return fmt.Errorf("could not determine program for Python application '%s'", task.Name)
-			}
+		module := c.extractPythonModule(task)
 
-			launchConfig.Module = module
-		} else {
+		switch {
+		case program != "":
 			launchConfig.Program = c.convertJetBrainsVariables(program)
+		case module != "":
+			launchConfig.Module = module
+		default:
+			launchConfig.Program = "${workspaceFolder}/main.py"
+			launchConfig.guessed = append(launchConfig.guessed, "program")
 		}
 
 		// Add arguments
@@ -255,9 +329,10 @@ func (c *JetBrainsToVSCodeLaunchConverter) determineLaunchType(task *config.Task
 	} else {
 		// Generic external tool - use node as fallback
 		launchConfig.Type = "node"
+		launchConfig.guessed = append(launchConfig.guessed, "type")
 
 		// Try to extract program from command
-		parts := strings.Fields(task.Command)
+		parts := shellwords.Split(task.Command)
 		if len(parts) > 0 {
 			// Use the first part as program, rest as args
 			launchConfig.Program = c.convertJetBrainsVariables(parts[0])
@@ -277,40 +352,46 @@ func (c *JetBrainsToVSCodeLaunchConverter) determineLaunchType(task *config.Task
 // extractJavaMainClass extracts the main class from Java command
 func (c *JetBrainsToVSCodeLaunchConverter) extractJavaMainClass(task *config.Task) string {
 	// Look in command arguments for class name
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 	for _, part := range parts {
 		if strings.Contains(part, ".") && !strings.HasPrefix(part, "-") {
 			return part
 		}
 	}
 
-	// Look in task args
-	for _, arg := range task.Args {
+	// Look in task args, skipping the class path that may follow a JVM option
+	for i, arg := range task.Args {
+		if i > 0 && isJavaClassPathOption(task.Args[i-1]) {
+			continue
+		}
+
 		if strings.Contains(arg, ".") && !strings.HasPrefix(arg, "-") && !strings.HasSuffix(arg, ".jar") {
 			return arg
 		}
 	}
 
-	return "Main" // fallback
+	return ""
 }
 
-// extractJavaArgs extracts Java program arguments (excluding main class)
-func (c *JetBrainsToVSCodeLaunchConverter) extractJavaArgs(task *config.Task, mainClass string) []string {
-	var args []string
-
-	// Add args from task, excluding main class
-	for _, arg := range task.Args {
-		if arg != mainClass {
-			args = append(args, arg)
-		}
+// extractJavaArgs splits the task args around the main class into JVM options and program arguments
+func (c *JetBrainsToVSCodeLaunchConverter) extractJavaArgs(task *config.Task, mainClass string) (vmArgs []string, args []string) {
+	index := slices.Index(task.Args, mainClass)
+	if index < 0 {
+		// The main class is part of the command, so every arg is a program argument
+		return nil, task.Args
 	}
 
-	return args
+	return task.Args[:index], task.Args[index+1:]
+}
+
+// isJavaClassPathOption reports whether arg is a java option whose value is the next argument
+func isJavaClassPathOption(arg string) bool {
+	return arg == "-cp" || arg == "-classpath" || arg == "--class-path"
 }
 
 // extractNodeProgram extracts the Node.js program path
 func (c *JetBrainsToVSCodeLaunchConverter) extractNodeProgram(task *config.Task) string {
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 
 	// Look for the script file in command parts
 	for i, part := range parts {
@@ -326,8 +407,7 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractNodeProgram(task *config.Task)
 		}
 	}
 
-	// Fallback to a common pattern
-	return "${workspaceFolder}/index.js"
+	return ""
 }
 
 // extractNodeArgs extracts Node.js program arguments
@@ -335,7 +415,7 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractNodeArgs(task *config.Task) []
 	var args []string
 
 	// Extract args from command (skip 'node' and program file)
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 	foundProgram := false
 
 	for i, part := range parts {
@@ -370,7 +450,7 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractPythonProgram(task *config.Tas
 		}
 	}
 
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 
 	// Look for the script file in command parts
 	for i, part := range parts {
@@ -386,8 +466,7 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractPythonProgram(task *config.Tas
 		}
 	}
 
-	// Fallback
-	return "${workspaceFolder}/main.py"
+	return ""
 }
 
 // extractPythonModule extracts the Python module name for -m execution
@@ -407,7 +486,7 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractPythonArgs(task *config.Task)
 	var args []string
 
 	// Extract args from command (skip 'python' and program file)
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 	foundProgram := false
 
 	for i, part := range parts {
@@ -444,28 +523,23 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractPythonArgs(task *config.Task)
 	return args
 }
 
-// convertJetBrainsVariables converts JetBrains variables to VSCode format (same as in jetbrains_to_vscode.go)
+// convertJetBrainsVariables converts JetBrains variables to VSCode format
 func (c *JetBrainsToVSCodeLaunchConverter) convertJetBrainsVariables(input string) string {
-	result := input
-
-	// Convert JetBrains variables to VSCode equivalents
-	result = strings.ReplaceAll(result, "$PROJECT_DIR$", "${workspaceFolder}")
-	result = strings.ReplaceAll(result, "$MODULE_DIR$", "${workspaceFolder}")
-	result = strings.ReplaceAll(result, "$FileDir$", "${fileDirname}")
-	result = strings.ReplaceAll(result, "$FileName$", "${fileBasename}")
-	result = strings.ReplaceAll(result, "$FilePath$", "${file}")
-
-	return result
+	return ConvertJetBrainsVariables(input)
 }
 
-// writeVSCodeLaunchFile writes the VSCode launch file
+// writeVSCodeLaunchFile writes the VSCode launch file, editing an existing one in place
 func (c *JetBrainsToVSCodeLaunchConverter) writeVSCodeLaunchFile(launchFile *VSCodeLaunchFile, outputPath string) error {
-	jsonData, err := json.MarshalIndent(launchFile, "", "    ")
+	content, err := renderVSCodeFileAt(c.Context, outputPath, launchFile, "configurations", "name")
 	if err != nil {
 		return fmt.Errorf("failed to marshal launch.json: %w", err)
 	}
 
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
+	if err := c.Guard.Check(c.Context, outputPath); err != nil {
+		return err
+	}
+
+	if err := WriteFileAtomic(outputPath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -477,7 +551,7 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractGoPackage(task *config.Task) s
 	// Look for PACKAGE option in JetBrains configuration description or command
 	if strings.Contains(task.Description, "PACKAGE") {
 		// Parse from description if available
-		parts := strings.Fields(task.Description)
+		parts := shellwords.Split(task.Description)
 		for i, part := range parts {
 			if part == "PACKAGE" && i+1 < len(parts) {
 				return c.convertJetBrainsVariables(parts[i+1])
@@ -486,7 +560,7 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractGoPackage(task *config.Task) s
 	}
 
 	// Look for package path in command
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 	for _, part := range parts {
 		if strings.Contains(part, "/") || strings.Contains(part, ".") {
 			return c.convertJetBrainsVariables(part)
@@ -511,7 +585,7 @@ func (c *JetBrainsToVSCodeLaunchConverter) extractGoArgs(task *config.Task) []st
 	// Look for PROGRAM_PARAMETERS in task description
 	if strings.Contains(task.Description, "PROGRAM_PARAMETERS") {
 		// Parse from description if available
-		parts := strings.Fields(task.Description)
+		parts := shellwords.Split(task.Description)
 		foundParams := false
 
 		for _, part := range parts {
@@ -1,13 +1,17 @@
 package converter
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+	"github.com/syndbg/taskporter/internal/shellwords"
 
 	"github.com/stretchr/testify/require"
 )
@@ -21,11 +25,11 @@ func TestJetBrainsToVSCodeLaunchConverter_ConvertToLaunch(t *testing.T) {
 		task := jetbrainsConfigToTask(jetbrainsConfig, "Go")
 
 		// Convert to VSCode launch
-		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false)
+		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false, nil)
 
 		require.True(t, converter.canConvertToLaunch(task))
 
-		launchConfig, err := converter.convertSingleTaskToLaunch(task)
+		launchConfig, err := converter.convertSingleTaskToLaunch(task, &ReportEntry{})
 		require.NoError(t, err)
 
 		// Verify Go-specific configuration
@@ -52,11 +56,11 @@ func TestJetBrainsToVSCodeLaunchConverter_ConvertToLaunch(t *testing.T) {
 		task := jetbrainsConfigToTask(jetbrainsConfig, "Java")
 
 		// Convert to VSCode launch
-		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false)
+		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false, nil)
 
 		require.True(t, converter.canConvertToLaunch(task))
 
-		launchConfig, err := converter.convertSingleTaskToLaunch(task)
+		launchConfig, err := converter.convertSingleTaskToLaunch(task, &ReportEntry{})
 		require.NoError(t, err)
 
 		// Verify Java-specific configuration
@@ -64,6 +68,7 @@ func TestJetBrainsToVSCodeLaunchConverter_ConvertToLaunch(t *testing.T) {
 		require.Equal(t, "launch", launchConfig.Request)
 		require.Equal(t, "Java Application", launchConfig.Name)
 		require.Equal(t, "com.example.Application", launchConfig.MainClass)
+		require.Equal(t, "-Xmx1024m -Dspring.profiles.active=development", launchConfig.VMArgs)
 		require.Contains(t, launchConfig.Args, "--spring.profiles.active=dev")
 		require.Len(t, launchConfig.Env, 2)
 
@@ -79,11 +84,11 @@ func TestJetBrainsToVSCodeLaunchConverter_ConvertToLaunch(t *testing.T) {
 		task := jetbrainsConfigToTask(jetbrainsConfig, "NodeJS")
 
 		// Convert to VSCode launch
-		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false)
+		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false, nil)
 
 		require.True(t, converter.canConvertToLaunch(task))
 
-		launchConfig, err := converter.convertSingleTaskToLaunch(task)
+		launchConfig, err := converter.convertSingleTaskToLaunch(task, &ReportEntry{})
 		require.NoError(t, err)
 
 		// Verify Node.js-specific configuration
@@ -107,11 +112,11 @@ func TestJetBrainsToVSCodeLaunchConverter_ConvertToLaunch(t *testing.T) {
 		task := jetbrainsConfigToTask(jetbrainsConfig, "Python")
 
 		// Convert to VSCode launch
-		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false)
+		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false, nil)
 
 		require.True(t, converter.canConvertToLaunch(task))
 
-		launchConfig, err := converter.convertSingleTaskToLaunch(task)
+		launchConfig, err := converter.convertSingleTaskToLaunch(task, &ReportEntry{})
 		require.NoError(t, err)
 
 		// Verify Python-specific configuration
@@ -126,10 +131,66 @@ func TestJetBrainsToVSCodeLaunchConverter_ConvertToLaunch(t *testing.T) {
 		// Verify against golden file for exact output
 		verifyVSCodeLaunchConfigGolden(t, launchConfig, "jetbrains_python_to_vscode_expected.json")
 	})
+
+	t.Run("Runtime paths", func(t *testing.T) {
+		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false, nil)
+
+		python, err := converter.convertSingleTaskToLaunch(&config.Task{
+			Name:        "Python App",
+			Type:        config.TypeJetBrains,
+			Command:     "python",
+			Args:        []string{"/test/project/src/main.py"},
+			RuntimePath: "/test/project/.venv/bin/python",
+		}, &ReportEntry{})
+		require.NoError(t, err)
+		require.Equal(t, "/test/project/.venv/bin/python", python.Python)
+		require.Empty(t, python.JavaExec)
+
+		java, err := converter.convertSingleTaskToLaunch(&config.Task{
+			Name:        "Java App",
+			Type:        config.TypeJetBrains,
+			Command:     "java",
+			Args:        []string{"com.example.Main"},
+			RuntimePath: "/usr/lib/jvm/java-8-openjdk/bin/java",
+		}, &ReportEntry{})
+		require.NoError(t, err)
+		require.Equal(t, "/usr/lib/jvm/java-8-openjdk/bin/java", java.JavaExec)
+		require.Empty(t, java.Python)
+	})
+
+	t.Run("Project paths", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the fixture paths are POSIX paths")
+		}
+
+		converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false, nil)
+
+		inside, err := converter.convertSingleTaskToLaunch(&config.Task{
+			Name:    "Python App",
+			Type:    config.TypeJetBrains,
+			Command: "python",
+			Args:    []string{"/test/project/src/main.py"},
+			Cwd:     "/test/project/src",
+		}, &ReportEntry{})
+		require.NoError(t, err)
+		require.Equal(t, "${workspaceFolder}/src/main.py", inside.Program)
+		require.Equal(t, "${workspaceFolder}/src", inside.Cwd)
+
+		outside, err := converter.convertSingleTaskToLaunch(&config.Task{
+			Name:    "Python Tool",
+			Type:    config.TypeJetBrains,
+			Command: "python",
+			Args:    []string{"/opt/tools/lint.py"},
+			Cwd:     "/opt/tools",
+		}, &ReportEntry{})
+		require.NoError(t, err)
+		require.Equal(t, "/opt/tools/lint.py", outside.Program)
+		require.Equal(t, "/opt/tools", outside.Cwd)
+	})
 }
 
 func TestJetBrainsToVSCodeLaunchConverter_LanguageDetection(t *testing.T) {
-	converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false)
+	converter := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false, nil)
 
 	testCases := []struct {
 		name         string
@@ -197,7 +258,7 @@ func TestJetBrainsToVSCodeLaunchConverter_LanguageDetection(t *testing.T) {
 					Request: "launch",
 				}
 
-				err := converter.determineLaunchType(tc.task, launchConfig)
+				err := converter.determineLaunchType(tc.task, launchConfig, &ReportEntry{})
 				require.NoError(t, err)
 				require.Equal(t, tc.expectedType, launchConfig.Type)
 			}
@@ -256,18 +317,18 @@ func TestJetBrainsToVSCodeLaunchConverter_BidirectionalConsistency(t *testing.T)
 			originalTask := launchTasks[0]
 
 			// Convert VSCode → JetBrains
-			vscodeToJB := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false)
-			jetbrainsConfig, err := vscodeToJB.convertSingleLaunchConfig(originalTask)
+			vscodeToJB := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
+			jetbrainsConfig, err := vscodeToJB.convertSingleLaunchConfig(originalTask, &ReportEntry{})
 			require.NoError(t, err)
 
 			// Convert JetBrains back to task
 			jetbrainsTask := jetbrainsConfigToTask(jetbrainsConfig, tc.originalType)
 
 			// Convert JetBrains → VSCode
-			jbToVSCode := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false)
+			jbToVSCode := NewJetBrainsToVSCodeLaunchConverter("/test/project", "", false, nil)
 			require.True(t, jbToVSCode.canConvertToLaunch(jetbrainsTask))
 
-			finalLaunchConfig, err := jbToVSCode.convertSingleTaskToLaunch(jetbrainsTask)
+			finalLaunchConfig, err := jbToVSCode.convertSingleTaskToLaunch(jetbrainsTask, &ReportEntry{})
 			require.NoError(t, err)
 
 			// Verify language consistency
@@ -281,6 +342,95 @@ func TestJetBrainsToVSCodeLaunchConverter_BidirectionalConsistency(t *testing.T)
 	}
 }
 
+func TestJetBrainsToVSCodeLaunchConverter_Passthrough(t *testing.T) {
+	// VSCode → JetBrains → VSCode must keep the launch.json fields taskporter doesn't model
+	launchPath := filepath.Join(t.TempDir(), ".vscode", "launch.json")
+	original, err := os.ReadFile(filepath.Join("testdata", "vscode-launch-passthrough.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(launchPath), 0755))
+	require.NoError(t, os.WriteFile(launchPath, original, 0644))
+
+	tasks, err := vscode.NewLaunchParser("/test/project", nil).ParseLaunchConfigs(launchPath)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Contains(t, string(tasks[0].Passthrough), "serverReadyAction")
+
+	vscodeToJB := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
+	jetbrainsConfig, err := vscodeToJB.convertSingleLaunchConfig(tasks[0], &ReportEntry{})
+	require.NoError(t, err)
+
+	// The JetBrains side has no passthrough of its own; matching happens by name against launch.json
+	jetbrainsTask := jetbrainsConfigToTask(jetbrainsConfig, "node")
+	require.Empty(t, jetbrainsTask.Passthrough)
+
+	jbToVSCode := NewJetBrainsToVSCodeLaunchConverter("/test/project", launchPath, false, nil)
+
+	t.Run("should merge regenerated fields over the original configuration", func(t *testing.T) {
+		launchConfig, err := jbToVSCode.convertSingleTaskToLaunch(jetbrainsTask, &ReportEntry{})
+		require.NoError(t, err)
+
+		originals := jbToVSCode.originalLaunchConfigs([]*config.Task{jetbrainsTask}, launchPath)
+		require.NoError(t, preserveOriginalFields(launchConfig, originals[jetbrainsTask.Name]))
+
+		verifyVSCodeLaunchConfigGolden(t, launchConfig, "node_passthrough_roundtrip_expected.json")
+	})
+
+	t.Run("should keep unknown fields when rewriting launch.json", func(t *testing.T) {
+		jbToVSCode.SetOverwriteGuard(&OverwriteGuard{Force: true})
+		require.NoError(t, jbToVSCode.ConvertToLaunch([]*config.Task{jetbrainsTask}, false))
+
+		data, err := os.ReadFile(launchPath)
+		require.NoError(t, err)
+
+		var launchFile struct {
+			Configurations []map[string]json.RawMessage `json:"configurations"`
+		}
+		require.NoError(t, vscode.ParseJSONC(data, &launchFile))
+		require.Len(t, launchFile.Configurations, 1)
+
+		written := launchFile.Configurations[0]
+		require.JSONEq(t, `{"pattern": "listening on port ([0-9]+)", "uriFormat": "http://localhost:%s", "action": "openExternally"}`, string(written["serverReadyAction"]))
+		require.JSONEq(t, `"npm: build"`, string(written["preLaunchTask"]))
+		require.JSONEq(t, `"Launch Web Server"`, string(written["name"]))
+
+		// A second sync reads back taskporter's own output and keeps the fields again
+		require.NoError(t, jbToVSCode.ConvertToLaunch([]*config.Task{jetbrainsTask}, false))
+
+		again, err := os.ReadFile(launchPath)
+		require.NoError(t, err)
+		require.Equal(t, string(data), string(again))
+	})
+
+	for _, tc := range []struct {
+		key, debugger string
+	}{
+		{"javaExec", "java"},
+		{"vmArgs", "java"},
+		{"python", "python"},
+	} {
+		t.Run("should not bring back a "+tc.key+" the JetBrains configuration no longer sets", func(t *testing.T) {
+			original := `{"name": "App", "type": "` + tc.debugger + `", "request": "launch", "` + tc.key + `": "stale", "presentation": {"group": "apps"}}`
+
+			launchConfig := &VSCodeLaunchConfig{Name: "App", Type: tc.debugger, Request: "launch"}
+			require.NoError(t, preserveOriginalFields(launchConfig, json.RawMessage(original)))
+
+			data, err := json.Marshal(launchConfig)
+			require.NoError(t, err)
+
+			var written map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(data, &written))
+			require.NotContains(t, written, tc.key)
+			require.Contains(t, written, "presentation")
+		})
+	}
+
+	t.Run("should not carry fields onto a different debugger type", func(t *testing.T) {
+		launchConfig := &VSCodeLaunchConfig{Name: "Launch Web Server", Type: "go", Request: "launch"}
+		require.NoError(t, preserveOriginalFields(launchConfig, tasks[0].Passthrough))
+		require.Empty(t, launchConfig.preserved)
+	})
+}
+
 // Helper functions
 
 // loadJetBrainsTestData loads JetBrains XML test data
@@ -326,7 +476,7 @@ func jetbrainsConfigToTask(jbConfig *JetBrainsRunConfiguration, language string)
 			case "PACKAGE":
 				packagePath = option.Value
 			case "PROGRAM_PARAMETERS":
-				programParams = parseSpaceSeparatedArgs(option.Value)
+				programParams = shellwords.Split(option.Value)
 			case "WORKING_DIRECTORY":
 				task.Cwd = option.Value
 			}
@@ -339,6 +489,7 @@ func jetbrainsConfigToTask(jbConfig *JetBrainsRunConfiguration, language string)
 
 		var (
 			mainClass     string
+			vmParams      []string
 			programParams []string
 		)
 
@@ -346,15 +497,17 @@ func jetbrainsConfigToTask(jbConfig *JetBrainsRunConfiguration, language string)
 			switch option.Name {
 			case "MAIN_CLASS_NAME":
 				mainClass = option.Value
+			case "VM_PARAMETERS":
+				vmParams = shellwords.Split(option.Value)
 			case "PROGRAM_PARAMETERS":
-				programParams = parseSpaceSeparatedArgs(option.Value)
+				programParams = shellwords.Split(option.Value)
 			case "WORKING_DIRECTORY":
 				task.Cwd = option.Value
 			}
 		}
 
 		if mainClass != "" {
-			args = append([]string{mainClass}, programParams...)
+			args = append(append(vmParams, mainClass), programParams...)
 		}
 
 	case "NodeJSConfigurationType":
@@ -370,7 +523,7 @@ func jetbrainsConfigToTask(jbConfig *JetBrainsRunConfiguration, language string)
 			case "PATH_TO_JS_FILE":
 				jsFile = option.Value
 			case "APPLICATION_PARAMETERS":
-				appParams = parseSpaceSeparatedArgs(option.Value)
+				appParams = shellwords.Split(option.Value)
 			case "WORKING_DIRECTORY":
 				task.Cwd = option.Value
 			}
@@ -393,7 +546,7 @@ func jetbrainsConfigToTask(jbConfig *JetBrainsRunConfiguration, language string)
 			case "SCRIPT_NAME":
 				scriptName = option.Value
 			case "PARAMETERS":
-				params = parseSpaceSeparatedArgs(option.Value)
+				params = shellwords.Split(option.Value)
 			case "WORKING_DIRECTORY":
 				task.Cwd = option.Value
 			}
@@ -422,18 +575,6 @@ func jetbrainsConfigToTask(jbConfig *JetBrainsRunConfiguration, language string)
 	return task
 }
 
-// parseSpaceSeparatedArgs parses space-separated argument string
-func parseSpaceSeparatedArgs(input string) []string {
-	if input == "" {
-		return nil
-	}
-
-	// Simple space splitting - could be enhanced for quoted args if needed
-	args := strings.Fields(input)
-
-	return args
-}
-
 // containsString checks if a string contains a substring (case-insensitive)
 func containsString(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJetBrainsToVSCodeConverter(t *testing.T) {
+	converter := NewJetBrainsToVSCodeConverter("/test/project", "", false, nil)
+
+	t.Run("should port aggregate tasks as dependsOn without a command", func(t *testing.T) {
+		vscodeTask, err := converter.convertSingleTask(&config.Task{
+			Name:         "ci",
+			Type:         config.TypeJetBrains,
+			DependsOn:    []string{"lint", "test"},
+			DependsOrder: config.DependsOrderSequence,
+		}, &ReportEntry{})
+		require.NoError(t, err)
+		require.Empty(t, vscodeTask.Type)
+		require.Empty(t, vscodeTask.Command)
+		require.Equal(t, []string{"lint", "test"}, vscodeTask.DependsOn)
+		require.Equal(t, config.DependsOrderSequence, vscodeTask.DependsOrder)
+	})
+
+	t.Run("should write working directories inside the project relative to the workspace", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the fixture paths are POSIX paths")
+		}
+
+		inside, err := converter.convertSingleTask(&config.Task{Name: "build", Type: config.TypeJetBrains, Command: "gradle", Cwd: "/test/project/backend"}, &ReportEntry{})
+		require.NoError(t, err)
+		require.Equal(t, "${workspaceFolder}/backend", inside.Options.Cwd)
+
+		outside, err := converter.convertSingleTask(&config.Task{Name: "tool", Type: config.TypeJetBrains, Command: "make", Cwd: "/opt/tools"}, &ReportEntry{})
+		require.NoError(t, err)
+		require.Equal(t, "/opt/tools", outside.Options.Cwd)
+	})
+
+	t.Run("should reject tasks with neither a command nor dependencies", func(t *testing.T) {
+		_, err := converter.convertSingleTask(&config.Task{Name: "broken", Type: config.TypeJetBrains}, &ReportEntry{})
+		require.EqualError(t, err, "empty command in task 'broken'")
+	})
+}
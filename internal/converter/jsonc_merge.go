@@ -0,0 +1,282 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+)
+
+// defaultJSONIndent is the indentation of VSCode files taskporter creates from scratch
+const defaultJSONIndent = "    "
+
+// renderVSCodeFileAt returns the content to write file to path. A new file is marshalled
+// whole, under the provenance header; an existing JSONC file is edited in place instead:
+// entries of its arrayKey array are replaced or appended by their nameKey, in the file's own
+// indentation, and every other byte, comments included, is kept. Entries taskporter wrote to
+// the file before and no longer generates are removed, but only from files it generated.
+func renderVSCodeFileAt(ctx context.Context, path string, file any, arrayKey, nameKey string) ([]byte, error) {
+	existing, err := config.ReadFileContext(ctx, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return renderVSCodeFile(file)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	generated, err := json.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(generated, &document); err != nil {
+		return nil, err
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(document[arrayKey], &entries); err != nil {
+		return nil, err
+	}
+
+	pruneStale := bytes.Contains(existing, []byte(ProvenanceMarker))
+
+	merged, err := mergeJSONCArray(existing, arrayKey, nameKey, entries, pruneStale)
+	if err != nil {
+		return nil, fmt.Errorf("can't edit %s in place: %w", path, err)
+	}
+
+	return merged, nil
+}
+
+// jsoncEdit replaces data[start:end] with text
+type jsoncEdit struct {
+	start, end int
+	text       string
+}
+
+// mergeJSONCArray replaces the elements of the arrayKey array in existing whose nameKey
+// matches one of entries and appends the other entries. Elements that already equal their
+// entry are left untouched; with pruneStale, named elements matching no entry are removed.
+func mergeJSONCArray(existing []byte, arrayKey, nameKey string, entries []json.RawMessage, pruneStale bool) ([]byte, error) {
+	array, err := vscode.LocateJSONCArray(existing, arrayKey)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := detectJSONIndent(existing)
+
+	// New elements line up with the existing ones, or one level inside the array's own line
+	elementIndent := lineIndent(existing, array.Open) + unit
+	if len(array.Elements) > 0 {
+		elementIndent = lineIndent(existing, array.Elements[0].Start)
+	}
+
+	byName := make(map[string]int, len(array.Elements))
+
+	for i := len(array.Elements) - 1; i >= 0; i-- {
+		if name, ok := entryName(array.Elements[i].Value, nameKey); ok {
+			byName[name] = i
+		}
+	}
+
+	var (
+		edits    []jsoncEdit
+		appended []string
+		wanted   = make(map[string]bool, len(entries))
+	)
+
+	for _, entry := range entries {
+		name, _ := entryName(entry, nameKey)
+		wanted[name] = true
+
+		index, found := byName[name]
+		if !found {
+			appended = append(appended, indentJSON(entry, elementIndent, unit))
+			continue
+		}
+
+		element := array.Elements[index]
+		if jsonEqual(element.Value, entry) {
+			continue
+		}
+
+		edits = append(edits, jsoncEdit{start: element.Start, end: element.End, text: indentJSON(entry, lineIndent(existing, element.Start), unit)})
+	}
+
+	keep := make([]bool, len(array.Elements))
+	kept := 0
+
+	for i, element := range array.Elements {
+		name, named := entryName(element.Value, nameKey)
+		keep[i] = !pruneStale || !named || wanted[name]
+
+		if keep[i] {
+			kept++
+		}
+	}
+
+	switch {
+	case kept == 0 && len(array.Elements) > 0:
+		// Every element goes, so the new ones take their place
+		first, last := array.Elements[0], array.Elements[len(array.Elements)-1]
+		edits = append(edits, jsoncEdit{start: first.Start, end: last.End, text: strings.Join(appended, ",\n"+elementIndent)})
+	case len(appended) > 0:
+		edits = append(edits, appendEdit(existing, array, elementIndent, appended))
+		fallthrough
+	default:
+		if kept < len(array.Elements) {
+			edits = append(edits, removalEdits(existing, array, keep)...)
+		}
+	}
+
+	// Apply from the end so earlier offsets stay valid
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+
+	merged := append([]byte(nil), existing...)
+	for _, edit := range edits {
+		merged = append(merged[:edit.start], append([]byte(edit.text), merged[edit.end:]...)...)
+	}
+
+	return merged, nil
+}
+
+// removalEdits cuts the elements of array that keep rejects, each together with the comma
+// before it. Leading elements are cut up to the first kept one instead, so no comma is left
+// right after the [. At least one element must be kept.
+func removalEdits(existing []byte, array *vscode.JSONCArray, keep []bool) []jsoncEdit {
+	var edits []jsoncEdit
+
+	first := 0
+	for !keep[first] {
+		first++
+	}
+
+	if first > 0 {
+		edits = append(edits, jsoncEdit{start: array.Elements[0].Start, end: nextElementLine(existing, array.Elements[first-1].End, array.Elements[first].Start)})
+	}
+
+	for i := first + 1; i < len(array.Elements); i++ {
+		if !keep[i] {
+			edits = append(edits, jsoncEdit{start: array.Elements[i-1].End, end: array.Elements[i].End})
+		}
+	}
+
+	return edits
+}
+
+// nextElementLine returns where the lines before the next element start: past the comma
+// following end and the rest of its line, or at next, the next element's start, when no comma
+// follows end right away.
+func nextElementLine(existing []byte, end, next int) int {
+	offset := next - len(bytes.TrimLeft(existing[end:next], " \t\r\n"))
+	if offset >= next || existing[offset] != ',' {
+		return next
+	}
+
+	rest := existing[offset+1 : next]
+
+	// A line comment after the comma belongs to the removed element
+	line, after, found := bytes.Cut(rest, []byte("\n"))
+	if trimmed := bytes.TrimSpace(line); found && (len(trimmed) == 0 || bytes.HasPrefix(trimmed, []byte("//"))) {
+		rest = after
+	}
+
+	return next - len(bytes.TrimLeft(rest, " \t"))
+}
+
+// appendEdit adds elements after the last element of array, or into an empty array
+func appendEdit(existing []byte, array *vscode.JSONCArray, elementIndent string, elements []string) jsoncEdit {
+	separator := ",\n" + elementIndent
+	text := strings.Join(elements, separator)
+
+	if len(array.Elements) > 0 {
+		end := array.Elements[len(array.Elements)-1].End
+
+		return jsoncEdit{start: end, end: end, text: separator + text}
+	}
+
+	// An empty array written as [] or [ ] is opened onto lines of its own
+	if len(bytes.TrimSpace(existing[array.Open+1:array.Close])) == 0 {
+		return jsoncEdit{start: array.Open + 1, end: array.Close, text: "\n" + elementIndent + text + "\n" + lineIndent(existing, array.Open)}
+	}
+
+	// Keep whatever comments the empty array holds
+	return jsoncEdit{start: array.Open + 1, end: array.Open + 1, text: "\n" + elementIndent + text}
+}
+
+// detectJSONIndent returns the indentation unit of data: a tab, or the smallest number of
+// spaces a line is indented by. Files with no indented lines get defaultJSONIndent.
+func detectJSONIndent(data []byte) string {
+	smallest := 0
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "\t") {
+			return "\t"
+		}
+
+		spaces := len(line) - len(strings.TrimLeft(line, " "))
+		if spaces > 0 && spaces < len(line) && (smallest == 0 || spaces < smallest) {
+			smallest = spaces
+		}
+	}
+
+	if smallest == 0 {
+		return defaultJSONIndent
+	}
+
+	return strings.Repeat(" ", smallest)
+}
+
+// lineIndent returns the whitespace the line holding offset starts with
+func lineIndent(data []byte, offset int) string {
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	line := data[start:offset]
+
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// indentJSON formats value with unit indentation, nested at prefix
+func indentJSON(value json.RawMessage, prefix, unit string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, value, prefix, unit); err != nil {
+		return string(value)
+	}
+
+	return buf.String()
+}
+
+// entryName returns the nameKey string of an object element
+func entryName(value json.RawMessage, nameKey string) (string, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return "", false
+	}
+
+	var name string
+	if err := json.Unmarshal(fields[nameKey], &name); err != nil {
+		return "", false
+	}
+
+	return name, true
+}
+
+// jsonEqual reports whether two JSON values are equal regardless of formatting and key order
+func jsonEqual(a, b json.RawMessage) bool {
+	var left, right any
+
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(left, right)
+}
@@ -0,0 +1,149 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+// handMaintainedTasks is a tasks.json as a team keeps it: 2-space indent and comments
+const handMaintainedTasks = `// Shared tasks, see CONTRIBUTING.md
+{
+  "version": "2.0.0",
+  "tasks": [
+    // Keep in sync with the CI lint job
+    {
+      "label": "lint",
+      "type": "shell",
+      "command": "golangci-lint", /* pinned in tools.go */
+      "args": ["run"]
+    }
+  ]
+}
+`
+
+func TestRenderVSCodeFileAt(t *testing.T) {
+	newTask := func(name string, args ...string) *config.Task {
+		return &config.Task{Name: name, Type: config.TypeJetBrains, Command: "make", Args: args}
+	}
+
+	convert := func(t *testing.T, path string, tasks ...*config.Task) string {
+		t.Helper()
+
+		converter := NewJetBrainsToVSCodeConverter(filepath.Dir(path), path, false, nil)
+		converter.SetOverwriteGuard(&OverwriteGuard{Force: true})
+
+		require.NoError(t, converter.ConvertTasks(tasks, false))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		return string(data)
+	}
+
+	t.Run("should append a task and keep the existing comments and formatting", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		require.NoError(t, os.WriteFile(path, []byte(handMaintainedTasks), 0o600))
+
+		content := convert(t, path, newTask("deploy", "deploy"))
+
+		untouched := strings.TrimSuffix(handMaintainedTasks, "\n    }\n  ]\n}\n") + "\n    }"
+		require.True(t, strings.HasPrefix(content, untouched), content)
+		require.Contains(t, content, "    },\n    {\n      \"label\": \"deploy\",\n      \"type\": \"shell\",\n      \"command\": \"make\",\n      \"args\": [\n        \"deploy\"\n      ],\n")
+		require.True(t, strings.HasSuffix(content, "\n    }\n  ]\n}\n"), content)
+	})
+
+	t.Run("should leave the file unchanged when the entries already match", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		first := convert(t, path, newTask("deploy", "deploy"))
+
+		require.Equal(t, first, convert(t, path, newTask("deploy", "deploy")))
+	})
+
+	t.Run("should replace a changed task in place with tab indentation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		existing := jsonProvenanceHeader + "{\n\t\"version\": \"2.0.0\",\n\t// Build tasks\n\t\"tasks\": [\n\t\t{\"label\": \"deploy\", \"type\": \"shell\", \"command\": \"make\"}, // old\n\t]\n}\n"
+		require.NoError(t, os.WriteFile(path, []byte(existing), 0o600))
+
+		content := convert(t, path, newTask("deploy", "deploy"))
+
+		require.True(t, strings.HasPrefix(content, jsonProvenanceHeader+"{\n\t\"version\": \"2.0.0\",\n\t// Build tasks\n\t\"tasks\": [\n\t\t{\n\t\t\t\"label\": \"deploy\",\n"), content)
+		require.True(t, strings.HasSuffix(content, "\n\t\t}, // old\n\t]\n}\n"), content)
+	})
+
+	t.Run("should remove tasks it no longer generates from a file it generated", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		convert(t, path, newTask("lint"), newTask("test"), newTask("deploy"))
+
+		content := convert(t, path, newTask("test"))
+
+		require.NotContains(t, content, `"lint"`)
+		require.NotContains(t, content, `"deploy"`)
+		require.True(t, strings.HasPrefix(content, jsonProvenanceHeader+"{\n    \"version\": \"2.0.0\",\n    \"tasks\": [\n        {\n            \"label\": \"test\","), content)
+		require.True(t, strings.HasSuffix(content, "\n        }\n    ]\n}"), content)
+	})
+
+	t.Run("should replace every task of a file it generated", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		convert(t, path, newTask("lint"))
+
+		content := convert(t, path, newTask("deploy"))
+
+		require.NotContains(t, content, `"lint"`)
+		require.Contains(t, content, "    \"tasks\": [\n        {\n            \"label\": \"deploy\",")
+	})
+
+	t.Run("should keep the tasks of a hand-written file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		require.NoError(t, os.WriteFile(path, []byte(handMaintainedTasks), 0o600))
+
+		content := convert(t, path, newTask("deploy"))
+
+		require.Contains(t, content, `"label": "lint"`)
+		require.Contains(t, content, `"label": "deploy"`)
+	})
+
+	t.Run("should fail when the existing file can't be read", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		require.NoError(t, os.Mkdir(path, 0o700))
+
+		_, err := renderVSCodeFileAt(context.Background(), path, &VSCodeTasksFile{Version: "2.0.0"}, "tasks", "label")
+		require.Error(t, err)
+	})
+
+	t.Run("should fail when the existing file has no array to edit", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		require.NoError(t, os.WriteFile(path, []byte("{\n  \"version\": \"2.0.0\"\n}\n"), 0o600))
+
+		_, err := renderVSCodeFileAt(context.Background(), path, &VSCodeTasksFile{Version: "2.0.0"}, "tasks", "label")
+		require.ErrorContains(t, err, "can't edit "+path+" in place")
+	})
+
+	t.Run("should fill an empty array", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		require.NoError(t, os.WriteFile(path, []byte("{\n  \"version\": \"2.0.0\",\n  \"tasks\": []\n}\n"), 0o600))
+
+		content := convert(t, path, newTask("deploy"))
+
+		require.Contains(t, content, "  \"tasks\": [\n    {\n      \"label\": \"deploy\",")
+		require.True(t, strings.HasSuffix(content, "\n    }\n  ]\n}\n"), content)
+	})
+
+	t.Run("should marshal a new file with the default indentation", func(t *testing.T) {
+		content := convert(t, filepath.Join(t.TempDir(), "tasks.json"), newTask("deploy"))
+
+		require.Contains(t, content, "{\n    \"version\": \"2.0.0\",\n    \"tasks\": [\n")
+	})
+}
+
+func TestDetectJSONIndent(t *testing.T) {
+	require.Equal(t, "\t", detectJSONIndent([]byte("{\n\t\"a\": 1\n}")))
+	require.Equal(t, "  ", detectJSONIndent([]byte("{\n  \"a\": {\n    \"b\": 1\n  }\n}")))
+	require.Equal(t, defaultJSONIndent, detectJSONIndent([]byte(`{"a": 1}`)))
+}
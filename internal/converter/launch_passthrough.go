@@ -0,0 +1,131 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+)
+
+// regeneratedLaunchFields are the launch.json keys derived from the JetBrains configuration.
+// Every other key of a matching original configuration is carried over untouched.
+var regeneratedLaunchFields = map[string]bool{
+	"name":      true,
+	"type":      true,
+	"request":   true,
+	"program":   true,
+	"module":    true,
+	"mainClass": true,
+	"javaExec":  true,
+	"vmArgs":    true,
+	"python":    true,
+	"args":      true,
+	"cwd":       true,
+	"env":       true,
+}
+
+// MarshalJSON writes the modeled fields first, followed by any preserved fields in key order
+func (c VSCodeLaunchConfig) MarshalJSON() ([]byte, error) {
+	type plain VSCodeLaunchConfig
+
+	data, err := json.Marshal(plain(c))
+	if err != nil || len(c.preserved) == 0 {
+		return data, err
+	}
+
+	var written map[string]json.RawMessage
+	if err := json.Unmarshal(data, &written); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(c.preserved))
+	for key := range c.preserved {
+		if _, ok := written[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+
+	buf.Write(data[:len(data)-1])
+
+	for _, key := range keys {
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.WriteByte(',')
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(c.preserved[key])
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// preserveOriginalFields copies the fields of original that the converter doesn't regenerate
+// onto launchConfig. Configurations of a different debugger type are left alone, since their
+// type-specific fields would not apply.
+func preserveOriginalFields(launchConfig *VSCodeLaunchConfig, original json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(original, &fields); err != nil {
+		return err
+	}
+
+	var originalType string
+	if raw, ok := fields["type"]; ok {
+		_ = json.Unmarshal(raw, &originalType)
+	}
+
+	if originalType != launchConfig.Type {
+		return nil
+	}
+
+	for key, value := range fields {
+		if regeneratedLaunchFields[key] {
+			continue
+		}
+
+		if launchConfig.preserved == nil {
+			launchConfig.preserved = make(map[string]json.RawMessage)
+		}
+
+		launchConfig.preserved[key] = value
+	}
+
+	return nil
+}
+
+// originalLaunchConfigs indexes, by name, the VSCode configurations a regenerated config may
+// have come from: those already in the launch.json being replaced and any passthrough carried
+// on the tasks themselves
+func (c *JetBrainsToVSCodeLaunchConverter) originalLaunchConfigs(tasks []*config.Task, outputPath string) map[string]json.RawMessage {
+	originals := make(map[string]json.RawMessage)
+
+	if data, err := config.ReadFileContext(c.Context, outputPath); err == nil {
+		var launchFile vscode.VSCodeLaunchFile
+		if err := vscode.ParseJSONC(data, &launchFile); err != nil {
+			c.logger.Debug("ignoring unreadable existing launch file", logging.KeyFile, outputPath, "error", err)
+		} else {
+			for _, launchConfig := range launchFile.Configurations {
+				originals[launchConfig.Name] = launchConfig.Raw
+			}
+		}
+	}
+
+	for _, task := range tasks {
+		if len(task.Passthrough) > 0 {
+			originals[task.Name] = task.Passthrough
+		}
+	}
+
+	return originals
+}
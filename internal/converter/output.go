@@ -0,0 +1,338 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// ProvenanceMarker is embedded in every file taskporter writes so later runs can
+// tell generated files apart from hand-written ones
+const ProvenanceMarker = "Generated by taskporter"
+
+// jsonProvenanceHeader is prepended to generated JSONC files (tasks.json, launch.json)
+const jsonProvenanceHeader = "// " + ProvenanceMarker + "\n"
+
+// xmlProvenanceComment is inserted after the XML declaration of generated run configurations
+const xmlProvenanceComment = "<!-- " + ProvenanceMarker + " -->\n"
+
+// ResolveFileOutput resolves --output for targets that produce a single file such as
+// tasks.json. An empty path uses defaultPath, a path with the default's extension is used
+// as-is, and an existing directory or a path ending in a separator gets the conventional
+// filename appended. Extensionless defaults (Makefile) accept any file path.
+func ResolveFileOutput(outputPath, defaultPath string) (string, error) {
+	if outputPath == "" {
+		return defaultPath, nil
+	}
+
+	info, statErr := os.Stat(outputPath)
+	isDir := statErr == nil && info.IsDir()
+	ext := filepath.Ext(defaultPath)
+
+	switch {
+	case hasTrailingSeparator(outputPath) || isDir:
+		return filepath.Join(outputPath, filepath.Base(defaultPath)), nil
+	case ext != "" && !strings.EqualFold(filepath.Ext(outputPath), ext):
+		return "", fmt.Errorf("output path %s must be a %s file or a directory (end it with %c to create one)", outputPath, ext, filepath.Separator)
+	default:
+		return outputPath, nil
+	}
+}
+
+// ResolveDirOutput resolves --output for targets that produce one file per task such as
+// JetBrains run configurations. An empty path uses defaultDir; file paths are rejected.
+func ResolveDirOutput(outputPath, defaultDir string) (string, error) {
+	if outputPath == "" {
+		return defaultDir, nil
+	}
+
+	if info, err := os.Stat(outputPath); err == nil && !info.IsDir() {
+		return "", fmt.Errorf("output path %s is a file, but this target writes one file per task and needs a directory", outputPath)
+	}
+
+	if filepath.Ext(outputPath) == ".json" && !hasTrailingSeparator(outputPath) {
+		return "", fmt.Errorf("output path %s looks like a .json file, but this target writes one file per task and needs a directory", outputPath)
+	}
+
+	return filepath.Clean(outputPath), nil
+}
+
+// hasTrailingSeparator reports whether path ends with a path separator
+func hasTrailingSeparator(path string) bool {
+	return strings.HasSuffix(path, "/") || strings.HasSuffix(path, string(filepath.Separator))
+}
+
+// OverwriteGuard decides whether an existing destination may be replaced. Files carrying
+// the provenance marker are always replaceable; anything else needs Force or Confirm.
+type OverwriteGuard struct {
+	Force   bool
+	Confirm func(path string) bool
+}
+
+// WriteOptions controls how a converter writes its files. Every converter embeds it, so a port
+// configures them all the same way.
+type WriteOptions struct {
+	Context     context.Context // Bounds reads of existing destination files
+	Out         io.Writer       // Receives progress and dry-run previews
+	Guard       *OverwriteGuard // Decides whether files not generated by taskporter may be replaced
+	FullPreview bool            // Makes dry runs print the full generated content, not just what would change
+	Report      *Report         // Records how each configuration was ported; nil records nothing
+}
+
+// defaultWriteOptions reads without a deadline and reports progress on standard output
+func defaultWriteOptions() WriteOptions {
+	return WriteOptions{Context: context.Background(), Out: os.Stdout}
+}
+
+// SetOverwriteGuard controls whether existing files not generated by taskporter may be replaced
+func (w *WriteOptions) SetOverwriteGuard(guard *OverwriteGuard) {
+	w.Guard = guard
+}
+
+// SetFullPreview makes dry runs print the full generated content, not just what would change
+func (w *WriteOptions) SetFullPreview(fullPreview bool) {
+	w.FullPreview = fullPreview
+}
+
+// SetContext bounds the converter's reads of existing destination files by ctx
+func (w *WriteOptions) SetContext(ctx context.Context) {
+	w.Context = ctx
+}
+
+// SetOutput sends the converter's progress and dry-run previews to out instead of standard output
+func (w *WriteOptions) SetOutput(out io.Writer) {
+	w.Out = out
+}
+
+// SetReport records how each configuration was ported in report
+func (w *WriteOptions) SetReport(report *Report) {
+	w.Report = report
+}
+
+// errNotGenerated is wrapped when a destination exists but was not written by taskporter
+var errNotGenerated = errors.New("existing file was not generated by taskporter")
+
+// Check returns an error if writing to path would clobber a file that taskporter did not
+// generate and the overwrite was neither forced nor confirmed. A nil guard never forces.
+func (g *OverwriteGuard) Check(ctx context.Context, path string) error {
+	generated, exists, err := inspectDestination(ctx, path)
+	if err != nil || !exists || generated {
+		return err
+	}
+
+	if g != nil && g.Force {
+		return nil
+	}
+
+	if g != nil && g.Confirm != nil && g.Confirm(path) {
+		return nil
+	}
+
+	return fmt.Errorf("refusing to overwrite %s: %w (use --force to overwrite)", path, errNotGenerated)
+}
+
+// describeDestination returns the absolute destination path and the dry-run action for it
+func describeDestination(ctx context.Context, path string) (string, string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+
+	generated, exists, err := inspectDestination(ctx, path)
+
+	switch {
+	case err != nil:
+		return absPath, fmt.Sprintf("Cannot write (%v)", err)
+	case !exists:
+		return absPath, "Would create"
+	case generated:
+		return absPath, "Would overwrite"
+	default:
+		return absPath, "Would overwrite (requires --force, not generated by taskporter)"
+	}
+}
+
+// inspectDestination reports whether path exists and whether it carries the provenance marker
+func inspectDestination(ctx context.Context, path string) (generated, exists bool, err error) {
+	info, err := config.StatContext(ctx, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, false, nil
+	}
+
+	if err != nil {
+		return false, false, err
+	}
+
+	if info.IsDir() {
+		return false, true, fmt.Errorf("destination %s is a directory", path)
+	}
+
+	data, err := config.ReadFileContext(ctx, path)
+	if err != nil {
+		return false, true, err
+	}
+
+	return bytes.Contains(data, []byte(ProvenanceMarker)), true, nil
+}
+
+// withXMLProvenance inserts the provenance comment after the XML declaration
+func withXMLProvenance(header string, body []byte) []byte {
+	return []byte(header + xmlProvenanceComment + string(body))
+}
+
+// renderRunConfiguration returns the content of a JetBrains run configuration file. A non-empty
+// platform names the launch.json platform block the values were taken from.
+func renderRunConfiguration(config *JetBrainsRunConfiguration, platform string) ([]byte, error) {
+	// Create the root component structure that JetBrains expects
+	component := &JetBrainsComponent{
+		Name:          "ProjectRunConfigurationManager",
+		Configuration: *config,
+	}
+
+	// Marshal to XML with proper formatting
+	xmlData, err := marshalJetBrainsXML(component)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal XML: %w", err)
+	}
+
+	// JetBrains has no per-OS values, so record which platform the configuration was generated for
+	if platform != "" {
+		xmlData = append([]byte(fmt.Sprintf("<!-- Values from the launch.json %q block -->\n", platform)), xmlData...)
+	}
+
+	// Add XML declaration and provenance marker
+	return withXMLProvenance(xml.Header, xmlData), nil
+}
+
+// intellijEntities maps the character references encoding/xml writes in attribute values to
+// the ones IntelliJ writes, so a file the IDE saves again doesn't show up as changed
+var intellijEntities = strings.NewReplacer(
+	"&#34;", "&quot;",
+	"&#39;", "'",
+	"&#xA;", "&#10;",
+	"&#xD;", "&#13;",
+	"&#x9;", "&#9;",
+)
+
+// marshalJetBrainsXML indents v as XML, escaping special characters the way IntelliJ does,
+// e.g. `&quot;` for quotes and `&#10;` for the newlines of a multi-line SCRIPT_TEXT
+func marshalJetBrainsXML(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(intellijEntities.Replace(string(data))), nil
+}
+
+// renderVSCodeFile returns the content of a generated VSCode JSONC file such as tasks.json
+func renderVSCodeFile(file any) ([]byte, error) {
+	jsonData, err := json.MarshalIndent(file, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	return withJSONProvenance(jsonData), nil
+}
+
+// withScriptProvenance inserts the provenance comment after the shebang line of a shell script
+func withScriptProvenance(shebang string, body []byte) []byte {
+	return []byte(shebang + "\n# " + ProvenanceMarker + "\n" + string(body))
+}
+
+// withMakefileProvenance prepends the provenance comment to Makefile content
+func withMakefileProvenance(body []byte) []byte {
+	return append([]byte("# "+ProvenanceMarker+"\n"), body...)
+}
+
+// withJSONProvenance prepends the provenance comment to JSONC content
+func withJSONProvenance(body []byte) []byte {
+	return append([]byte(jsonProvenanceHeader), body...)
+}
+
+// ensureWritableDir creates dir if needed and proves it is writable by creating and removing a
+// temporary file, so read-only destinations fail once up front instead of file by file
+func ensureWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".taskporter-probe-*")
+	if err != nil {
+		return fmt.Errorf("output directory %s is not writable: %w", dir, err)
+	}
+
+	probe.Close()
+
+	return os.Remove(probe.Name())
+}
+
+// WriteFileAtomic writes data to a temporary file next to path, syncs it and renames it over
+// path, so an interrupted write never leaves a truncated file behind. An existing file keeps
+// its permissions; a new one gets perm.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	if info, statErr := os.Stat(path); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// PartialWriteError reports a batch of files that stopped part way through writing.
+// Written lists the files that were written before the failure, so they can be cleaned up.
+type PartialWriteError struct {
+	Written []string
+	Failed  string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *PartialWriteError) Error() string {
+	if len(e.Written) == 0 {
+		return fmt.Sprintf("failed to write %s: %v (no files were written)", e.Failed, e.Err)
+	}
+
+	return fmt.Sprintf("failed to write %s: %v (already written: %s)", e.Failed, e.Err, strings.Join(e.Written, ", "))
+}
+
+// Unwrap returns the underlying write error
+func (e *PartialWriteError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,275 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputResolution(t *testing.T) {
+	tempDir := t.TempDir()
+
+	existingFile := filepath.Join(tempDir, "notes.txt")
+	require.NoError(t, os.WriteFile(existingFile, []byte("hand written"), 0o600))
+
+	t.Run("ResolveFileOutput", func(t *testing.T) {
+		defaultPath := filepath.Join("project", ".vscode", "tasks.json")
+
+		tests := []struct {
+			name        string
+			outputPath  string
+			expected    string
+			expectError bool
+		}{
+			{
+				name:       "empty uses default",
+				outputPath: "",
+				expected:   defaultPath,
+			},
+			{
+				name:       "json file is used as-is",
+				outputPath: filepath.Join(tempDir, "custom.json"),
+				expected:   filepath.Join(tempDir, "custom.json"),
+			},
+			{
+				name:       "existing directory gets conventional filename",
+				outputPath: tempDir,
+				expected:   filepath.Join(tempDir, "tasks.json"),
+			},
+			{
+				name:       "trailing separator means directory",
+				outputPath: filepath.Join(tempDir, "new-dir") + string(filepath.Separator),
+				expected:   filepath.Join(tempDir, "new-dir", "tasks.json"),
+			},
+			{
+				name:        "existing non-json file is rejected",
+				outputPath:  existingFile,
+				expectError: true,
+			},
+			{
+				name:        "ambiguous path is rejected",
+				outputPath:  filepath.Join(tempDir, "missing"),
+				expectError: true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := ResolveFileOutput(tt.outputPath, defaultPath)
+				if tt.expectError {
+					require.Error(t, err)
+					require.Contains(t, err.Error(), tt.outputPath)
+
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, result)
+			})
+		}
+	})
+
+	t.Run("ResolveDirOutput", func(t *testing.T) {
+		defaultDir := filepath.Join("project", ".idea", "runConfigurations")
+
+		tests := []struct {
+			name        string
+			outputPath  string
+			expected    string
+			expectError bool
+		}{
+			{
+				name:       "empty uses default",
+				outputPath: "",
+				expected:   defaultDir,
+			},
+			{
+				name:       "existing directory",
+				outputPath: tempDir,
+				expected:   tempDir,
+			},
+			{
+				name:       "new directory",
+				outputPath: filepath.Join(tempDir, "configs") + string(filepath.Separator),
+				expected:   filepath.Join(tempDir, "configs"),
+			},
+			{
+				name:        "existing file is rejected",
+				outputPath:  existingFile,
+				expectError: true,
+			},
+			{
+				name:        "json file is rejected",
+				outputPath:  filepath.Join(tempDir, "tasks.json"),
+				expectError: true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := ResolveDirOutput(tt.outputPath, defaultDir)
+				if tt.expectError {
+					require.Error(t, err)
+					require.Contains(t, err.Error(), "needs a directory")
+
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, result)
+			})
+		}
+	})
+}
+
+func TestOverwriteGuard(t *testing.T) {
+	tempDir := t.TempDir()
+
+	handWritten := filepath.Join(tempDir, "hand.json")
+	require.NoError(t, os.WriteFile(handWritten, []byte(`{"version": "2.0.0"}`), 0o600))
+
+	generated := filepath.Join(tempDir, "generated.json")
+	require.NoError(t, os.WriteFile(generated, []byte(jsonProvenanceHeader+`{}`), 0o600))
+
+	t.Run("missing destination is allowed", func(t *testing.T) {
+		var guard *OverwriteGuard
+		require.NoError(t, guard.Check(context.Background(), filepath.Join(tempDir, "new.json")))
+	})
+
+	t.Run("generated destination is allowed", func(t *testing.T) {
+		require.NoError(t, (&OverwriteGuard{}).Check(context.Background(), generated))
+	})
+
+	t.Run("hand-written destination is refused", func(t *testing.T) {
+		err := (&OverwriteGuard{}).Check(context.Background(), handWritten)
+		require.ErrorIs(t, err, errNotGenerated)
+		require.Contains(t, err.Error(), "--force")
+	})
+
+	t.Run("force allows hand-written destination", func(t *testing.T) {
+		require.NoError(t, (&OverwriteGuard{Force: true}).Check(context.Background(), handWritten))
+	})
+
+	t.Run("confirmation decides for hand-written destination", func(t *testing.T) {
+		var asked string
+
+		guard := &OverwriteGuard{Confirm: func(path string) bool {
+			asked = path
+
+			return false
+		}}
+
+		require.Error(t, guard.Check(context.Background(), handWritten))
+		require.Equal(t, handWritten, asked)
+
+		guard.Confirm = func(string) bool { return true }
+		require.NoError(t, guard.Check(context.Background(), handWritten))
+	})
+
+	t.Run("directory destination is refused", func(t *testing.T) {
+		require.Error(t, (&OverwriteGuard{Force: true}).Check(context.Background(), tempDir))
+	})
+
+	t.Run("converters mark output and protect hand-written files", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.json")
+		tasks := []*config.Task{{Name: "build", Type: config.TypeJetBrains, Command: "gradle", Args: []string{"build"}}}
+
+		conv := NewJetBrainsToVSCodeConverter("/test/project", outputPath, false, nil)
+		require.NoError(t, conv.ConvertTasks(tasks, false))
+
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		require.Contains(t, string(data), ProvenanceMarker)
+
+		// Re-running over our own output is fine
+		require.NoError(t, conv.ConvertTasks(tasks, false))
+
+		require.NoError(t, os.WriteFile(outputPath, []byte(`{"version": "2.0.0", "tasks": []}`), 0o600))
+		require.ErrorIs(t, conv.ConvertTasks(tasks, false), errNotGenerated)
+
+		conv.SetOverwriteGuard(&OverwriteGuard{Force: true})
+		require.NoError(t, conv.ConvertTasks(tasks, false))
+	})
+}
+
+func TestAtomicWrites(t *testing.T) {
+	t.Run("WriteFileAtomic replaces the file without leftovers", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "run.sh")
+
+		require.NoError(t, WriteFileAtomic(path, []byte("first"), 0o755))
+		require.NoError(t, os.Chmod(path, 0o700))
+		require.NoError(t, WriteFileAtomic(path, []byte("second"), 0o644))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "second", string(data))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o700), info.Mode().Perm(), "existing permissions should be kept")
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1, "no temporary files should remain")
+	})
+
+	t.Run("unusable destinations fail before converting", func(t *testing.T) {
+		blocker := filepath.Join(t.TempDir(), "not-a-dir")
+		require.NoError(t, os.WriteFile(blocker, nil, 0o600))
+
+		tasks := []*config.Task{{Name: "build", Type: config.TypeJetBrains, Command: "gradle", Args: []string{"build"}}}
+
+		conv := NewJetBrainsToVSCodeConverter("/test/project", filepath.Join(blocker, ".vscode", "tasks.json"), false, nil)
+		require.ErrorContains(t, conv.ConvertTasks(tasks, false), "failed to create output directory")
+	})
+
+	t.Run("read-only output directories fail with one error", func(t *testing.T) {
+		outputDir := t.TempDir()
+		require.NoError(t, os.Chmod(outputDir, 0o555))
+		t.Cleanup(func() { _ = os.Chmod(outputDir, 0o755) })
+
+		if probe, err := os.CreateTemp(outputDir, "probe"); err == nil {
+			probe.Close()
+			t.Skip("permissions are not enforced for this user")
+		}
+
+		tasks := []*config.Task{
+			{Name: "build", Type: config.TypeVSCodeTask, Command: "make"},
+			{Name: "test", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"test"}},
+		}
+
+		converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+		require.ErrorContains(t, converter.ConvertTasks(tasks, false), "is not writable")
+
+		entries, err := os.ReadDir(outputDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("a failure mid-batch reports the files already written", func(t *testing.T) {
+		outputDir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(outputDir, "test.xml"), 0o755))
+
+		tasks := []*config.Task{
+			{Name: "build", Type: config.TypeVSCodeTask, Command: "make"},
+			{Name: "test", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"test"}},
+			{Name: "lint", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"lint"}},
+		}
+
+		converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+		err := converter.ConvertTasks(tasks, false)
+
+		var partial *PartialWriteError
+		require.ErrorAs(t, err, &partial)
+		require.Equal(t, []string{filepath.Join(outputDir, "build.xml")}, partial.Written)
+		require.Equal(t, filepath.Join(outputDir, "test.xml"), partial.Failed)
+		require.Contains(t, err.Error(), "already written: "+filepath.Join(outputDir, "build.xml"))
+		require.NoFileExists(t, filepath.Join(outputDir, "lint.xml"))
+	})
+}
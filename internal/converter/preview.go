@@ -0,0 +1,257 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// How a dry run would change a destination file
+const (
+	changeAdded     = "added"
+	changeUpdated   = "updated"
+	changeUnchanged = "unchanged"
+)
+
+// diffContext is the number of unchanged lines shown around each change of a unified diff
+const diffContext = 3
+
+// maxDiffCells bounds the table the line diff fills in; beyond it the differing middle of the
+// files is shown as replaced wholesale rather than aligned line by line
+const maxDiffCells = 4_000_000
+
+// classifyChange compares content with the file at path, returning how writing it would change
+// the file and the file's current content
+func classifyChange(ctx context.Context, path string, content []byte) (string, []byte) {
+	existing, err := config.ReadFileContext(ctx, path)
+	if err != nil {
+		return changeAdded, nil
+	}
+
+	if bytes.Equal(existing, content) {
+		return changeUnchanged, existing
+	}
+
+	return changeUpdated, existing
+}
+
+// previewFile prints to out the dry-run preview of a target written as a single file such as
+// tasks.json: the full content for a new file, and otherwise a unified diff against the file on
+// disk so only what would change has to be reviewed. fullPreview prints the full content too.
+// content is exactly what would be written, so cosmetic churn shows up only if it would land.
+func previewFile(ctx context.Context, out io.Writer, name, path string, content []byte, fullPreview bool) {
+	destination, action := describeDestination(ctx, path)
+	fmt.Fprintf(out, "   [DRY RUN] %s: %s\n", action, destination)
+
+	change, existing := classifyChange(ctx, path, content)
+
+	switch change {
+	case changeUnchanged:
+		fmt.Fprintf(out, "✅ %s is up to date, nothing would change\n", name)
+	case changeUpdated:
+		fmt.Fprintf(out, "📝 Changes to %s:\n%s", name, unifiedDiff(destination, destination, existing, content))
+	}
+
+	if change == changeAdded || fullPreview {
+		fmt.Fprintf(out, "📝 Preview of %s content:\n%s\n", name, content)
+	}
+}
+
+// dirPreview prints the dry-run preview of a target written as one file per configuration, such
+// as .idea/runConfigurations: whether each file would be added, updated or left unchanged, and a
+// tally at the end
+type dirPreview struct {
+	ctx         context.Context
+	out         io.Writer
+	fullPreview bool
+	counts      map[string]int
+}
+
+// newDirPreview creates a preview printing to out; fullPreview also prints the content of every file
+func newDirPreview(ctx context.Context, out io.Writer, fullPreview bool) *dirPreview {
+	return &dirPreview{ctx: ctx, out: out, fullPreview: fullPreview, counts: make(map[string]int)}
+}
+
+// file previews writing content to path
+func (p *dirPreview) file(path string, content []byte) {
+	destination, _ := describeDestination(p.ctx, path)
+	change, _ := classifyChange(p.ctx, path, content)
+	p.counts[change]++
+
+	note := ""
+	if generated, exists, err := inspectDestination(p.ctx, path); err == nil && exists && !generated {
+		note = " (requires --force, not generated by taskporter)"
+	}
+
+	fmt.Fprintf(p.out, "   [DRY RUN] %s: %s%s\n", change, destination, note)
+
+	if p.fullPreview {
+		fmt.Fprintf(p.out, "📝 Preview of %s:\n%s\n", destination, content)
+	}
+}
+
+// summary prints how many files would be added, updated and left unchanged
+func (p *dirPreview) summary() {
+	fmt.Fprintf(p.out, "📋 Dry run: %d added, %d updated, %d unchanged\n", p.counts[changeAdded], p.counts[changeUpdated], p.counts[changeUnchanged])
+}
+
+// unifiedDiff returns the changes from before to after as a unified diff with diffContext lines
+// of context, or "" when they are equal
+func unifiedDiff(fromName, toName string, before, after []byte) string {
+	ops := diffLines(splitLines(before), splitLines(after))
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+
+	hunks := 0
+
+	for start := 0; start < len(ops); {
+		// Find the next change and the run of changes close enough to share its hunk
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+
+		if first == len(ops) {
+			break
+		}
+
+		last := first
+
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				last = i
+			} else if i-last > 2*diffContext {
+				break
+			}
+		}
+
+		from, to := max(first-diffContext, 0), min(last+diffContext+1, len(ops))
+		writeHunk(&b, ops, from, to)
+		hunks++
+
+		start = to
+	}
+
+	if hunks == 0 {
+		return ""
+	}
+
+	return b.String()
+}
+
+// diffOp is one line of a diff: ' ' kept, '-' removed or '+' added
+type diffOp struct {
+	kind byte
+	line string
+	a, b int // Line indexes in before and after the op is at
+}
+
+// writeHunk writes ops[from:to] as a unified diff hunk
+func writeHunk(b *strings.Builder, ops []diffOp, from, to int) {
+	var beforeLen, afterLen int
+
+	for _, op := range ops[from:to] {
+		if op.kind != '+' {
+			beforeLen++
+		}
+
+		if op.kind != '-' {
+			afterLen++
+		}
+	}
+
+	// Empty ranges start at the line before them
+	beforeStart, afterStart := ops[from].a, ops[from].b
+	if beforeLen > 0 {
+		beforeStart++
+	}
+
+	if afterLen > 0 {
+		afterStart++
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", beforeStart, beforeLen, afterStart, afterLen)
+
+	for _, op := range ops[from:to] {
+		fmt.Fprintf(b, "%c%s\n", op.kind, op.line)
+	}
+}
+
+// diffLines aligns before and after along a longest common subsequence of lines. Common leading
+// and trailing lines are matched up front, so the table only covers the part that changed.
+func diffLines(before, after []string) []diffOp {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix && before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(before)+len(after))
+
+	for i := range prefix {
+		ops = append(ops, diffOp{kind: ' ', line: before[i], a: i, b: i})
+	}
+
+	a, b := before[prefix:len(before)-suffix], after[prefix:len(after)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	var lcs [][]int32
+	if len(a)*len(b) <= maxDiffCells {
+		lcs = make([][]int32, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(b)+1)
+		}
+
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+	}
+
+	i, j := 0, 0
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case lcs != nil && i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i], a: prefix + i, b: prefix + j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs == nil || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', line: a[i], a: prefix + i, b: prefix + j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j], a: prefix + i, b: prefix + j})
+			j++
+		}
+	}
+
+	for k := range suffix {
+		ops = append(ops, diffOp{kind: ' ', line: before[len(before)-suffix+k], a: len(before) - suffix + k, b: len(after) - suffix + k})
+	}
+
+	return ops
+}
+
+// splitLines splits content into lines without their line endings
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
@@ -0,0 +1,146 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("should show changed lines with context", func(t *testing.T) {
+		before := "a\nb\nc\nd\ne\nf\ng\nh\n"
+		after := "a\nb\nc\nd\nE\nf\ng\nh\n"
+
+		require.Equal(t, "--- old\n+++ new\n@@ -2,7 +2,7 @@\n b\n c\n d\n-e\n+E\n f\n g\n h\n", unifiedDiff("old", "new", []byte(before), []byte(after)))
+	})
+
+	t.Run("should split distant changes into hunks", func(t *testing.T) {
+		lines := make([]string, 20)
+		for i := range lines {
+			lines[i] = string(rune('a' + i))
+		}
+
+		before := strings.Join(lines, "\n") + "\n"
+
+		lines[1], lines[18] = "B", "S"
+		after := strings.Join(lines, "\n") + "\n"
+
+		diff := unifiedDiff("old", "new", []byte(before), []byte(after))
+		require.Contains(t, diff, "@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n")
+		require.Contains(t, diff, "@@ -16,5 +16,5 @@\n p\n q\n r\n-s\n+S\n t\n")
+	})
+
+	t.Run("should show added and removed lines", func(t *testing.T) {
+		diff := unifiedDiff("old", "new", []byte("a\nb\nc\n"), []byte("a\nc\nd\n"))
+
+		require.Equal(t, "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n c\n+d\n", diff)
+	})
+
+	t.Run("should diff against an empty file", func(t *testing.T) {
+		require.Equal(t, "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n", unifiedDiff("old", "new", nil, []byte("a\nb\n")))
+	})
+
+	t.Run("should be empty for equal content", func(t *testing.T) {
+		require.Empty(t, unifiedDiff("old", "new", []byte("a\nb\n"), []byte("a\nb\n")))
+	})
+}
+
+func TestPreviewFile(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "tasks.json")
+	content := withJSONProvenance([]byte("{\n    \"version\": \"2.0.0\"\n}\n"))
+
+	t.Run("should print the content of a new file", func(t *testing.T) {
+		var output strings.Builder
+		previewFile(context.Background(), &output, "tasks.json", path, content, false)
+
+		require.Contains(t, output.String(), "Would create")
+		require.Contains(t, output.String(), "📝 Preview of tasks.json content:\n"+string(content))
+	})
+
+	t.Run("should report an unchanged file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, content, 0o600))
+
+		var output strings.Builder
+		previewFile(context.Background(), &output, "tasks.json", path, content, false)
+
+		require.Contains(t, output.String(), "✅ tasks.json is up to date, nothing would change")
+		require.NotContains(t, output.String(), "Preview of")
+	})
+
+	t.Run("should diff an existing file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, withJSONProvenance([]byte("{\n    \"version\": \"0.1.0\"\n}\n")), 0o600))
+
+		var output strings.Builder
+		previewFile(context.Background(), &output, "tasks.json", path, content, false)
+
+		require.Contains(t, output.String(), "📝 Changes to tasks.json:\n")
+		require.Contains(t, output.String(), "-    \"version\": \"0.1.0\"\n+    \"version\": \"2.0.0\"\n")
+		require.NotContains(t, output.String(), "Preview of")
+	})
+
+	t.Run("should add the full content with full preview", func(t *testing.T) {
+		var output strings.Builder
+		previewFile(context.Background(), &output, "tasks.json", path, content, true)
+
+		require.Contains(t, output.String(), "📝 Changes to tasks.json:\n")
+		require.Contains(t, output.String(), "📝 Preview of tasks.json content:\n"+string(content))
+	})
+}
+
+func TestDirPreview(t *testing.T) {
+	tempDir := t.TempDir()
+	tasks := []*config.Task{
+		{Name: "build", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"build"}},
+		{Name: "test", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"test"}},
+		{Name: "lint", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"lint"}},
+	}
+
+	require.NoError(t, NewVSCodeToJetBrainsConverter(tempDir, tempDir, false, nil).ConvertTasks(tasks[:2], false))
+
+	// test changes, build stays the same and lint is new
+	tasks[1].Args = []string{"test", "-v"}
+
+	var out strings.Builder
+
+	dryRun := NewVSCodeToJetBrainsConverter(tempDir, tempDir, false, nil)
+	dryRun.SetOutput(&out)
+	require.NoError(t, dryRun.ConvertTasks(tasks, true))
+
+	output := out.String()
+
+	require.Contains(t, output, "[DRY RUN] unchanged: "+filepath.Join(tempDir, "build.xml"))
+	require.Contains(t, output, "[DRY RUN] updated: "+filepath.Join(tempDir, "test.xml"))
+	require.Contains(t, output, "[DRY RUN] added: "+filepath.Join(tempDir, "lint.xml"))
+	require.Contains(t, output, "📋 Dry run: 1 added, 1 updated, 1 unchanged")
+	require.NotContains(t, output, "<configuration")
+	require.NoFileExists(t, filepath.Join(tempDir, "lint.xml"))
+
+	t.Run("should flag hand-written files", func(t *testing.T) {
+		handWritten := filepath.Join(tempDir, "lint.xml")
+		require.NoError(t, os.WriteFile(handWritten, []byte("<component/>\n"), 0o600))
+
+		var output strings.Builder
+		newDirPreview(context.Background(), &output, false).file(handWritten, []byte("<component name=\"x\"/>\n"))
+
+		require.Contains(t, output.String(), "[DRY RUN] updated: "+handWritten+" (requires --force, not generated by taskporter)")
+	})
+
+	t.Run("should print every file with full preview", func(t *testing.T) {
+		converter := NewVSCodeToJetBrainsConverter(tempDir, tempDir, false, nil)
+		converter.SetFullPreview(true)
+
+		var output strings.Builder
+		converter.SetOutput(&output)
+		require.NoError(t, converter.ConvertTasks(tasks[:1], true))
+
+		require.Contains(t, output.String(), "📝 Preview of "+filepath.Join(tempDir, "build.xml")+":\n")
+		require.Contains(t, output.String(), `<configuration name="build"`)
+	})
+}
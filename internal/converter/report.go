@@ -0,0 +1,309 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// Outcomes of porting one source configuration
+const (
+	OutcomeConverted = "converted"
+	OutcomeSkipped   = "skipped"
+	OutcomeFailed    = "failed"
+)
+
+// Fidelity levels of a converted configuration, from best to worst
+const (
+	FidelityFull        = "full"        // Everything set on the source was carried over
+	FidelityPartial     = "partial"     // Some fields have no place in the target and were dropped
+	FidelityApproximate = "approximate" // Some values of the target are guesses or were kept untranslated
+)
+
+// fidelityRanks orders the fidelity levels, higher is better
+var fidelityRanks = map[string]int{FidelityApproximate: 1, FidelityPartial: 2, FidelityFull: 3}
+
+// ReportEntry describes how one source configuration was ported
+type ReportEntry struct {
+	SourceFile    string   `json:"sourceFile"`
+	SourceName    string   `json:"sourceName,omitempty"`
+	TargetFile    string   `json:"targetFile,omitempty"`
+	TargetName    string   `json:"targetName,omitempty"`
+	TargetType    string   `json:"targetType,omitempty"`
+	Outcome       string   `json:"outcome"`
+	Reason        string   `json:"reason,omitempty"`        // Why the configuration was skipped or failed
+	Fidelity      string   `json:"fidelity,omitempty"`      // How faithful a converted configuration is to its source
+	DroppedFields []string `json:"droppedFields,omitempty"` // Fields set on the source the target has no place for
+	// Fields of the target whose value was guessed or kept untranslated
+	ApproximatedFields []string `json:"approximatedFields,omitempty"`
+	Warnings           []string `json:"warnings,omitempty"`
+
+	used []string // Fields of the source the converter carried over, see dropUnused
+}
+
+// use records fields of the source the converter carried over to the target
+func (e *ReportEntry) use(fields ...string) {
+	e.used = append(e.used, fields...)
+}
+
+// dropUnused records as dropped every field set on task that the converter did not use, and
+// the keys of the source the parser did not read
+func (e *ReportEntry) dropUnused(task *config.Task) {
+	for _, field := range sourceFields {
+		if field.isSet(task) && !slices.Contains(e.used, field.name) {
+			e.drop(field.name)
+		}
+	}
+
+	for _, field := range task.UnreadFields {
+		e.drop(field)
+	}
+}
+
+// drop records a field of the source that did not make it to the target
+func (e *ReportEntry) drop(field string) {
+	if !slices.Contains(e.DroppedFields, field) {
+		e.DroppedFields = append(e.DroppedFields, field)
+	}
+}
+
+// approximate records fields of the target whose value is only an approximation of the source
+func (e *ReportEntry) approximate(fields ...string) {
+	for _, field := range fields {
+		if !slices.Contains(e.ApproximatedFields, field) {
+			e.ApproximatedFields = append(e.ApproximatedFields, field)
+		}
+	}
+}
+
+// fidelity grades a converted entry: approximated values weigh more than dropped fields, since
+// the target then does something other than what the source did
+func (e *ReportEntry) fidelity() string {
+	switch {
+	case len(e.ApproximatedFields) > 0:
+		return FidelityApproximate
+	case len(e.DroppedFields) > 0:
+		return FidelityPartial
+	default:
+		return FidelityFull
+	}
+}
+
+// Report is the machine-readable account of a port run written by --report, with one entry
+// per source configuration whatever its outcome. Converters add the entries; a nil *Report
+// records nothing.
+type Report struct {
+	Version          string        `json:"version"`
+	From             string        `json:"from"`
+	To               string        `json:"to"`
+	DryRun           bool          `json:"dryRun"`
+	NothingToConvert bool          `json:"nothingToConvert"` // The source exists but defines no configurations; not a failure
+	Entries          []ReportEntry `json:"entries"`
+
+	projectRoot string
+}
+
+// NewReport creates an empty report. Paths in its entries are made relative to projectRoot.
+func NewReport(projectRoot, version, from, to string, dryRun bool) *Report {
+	return &Report{
+		Version:     version,
+		From:        from,
+		To:          to,
+		DryRun:      dryRun,
+		Entries:     []ReportEntry{},
+		projectRoot: projectRoot,
+	}
+}
+
+// Add records entries, grading the converted ones by their dropped and approximated fields
+func (r *Report) Add(entries ...ReportEntry) {
+	if r == nil {
+		return
+	}
+
+	for _, entry := range entries {
+		entry.SourceFile = r.relative(entry.SourceFile)
+		entry.TargetFile = r.relative(entry.TargetFile)
+
+		entry.Fidelity, entry.used = "", nil
+		if entry.Outcome == OutcomeConverted {
+			entry.Fidelity = entry.fidelity()
+		}
+
+		r.Entries = append(r.Entries, entry)
+	}
+}
+
+// Skip records a source configuration that was left out, with the reason
+func (r *Report) Skip(task *config.Task, reason string) {
+	entry := newReportEntry(task, OutcomeSkipped)
+	entry.Reason = reason
+
+	r.Add(entry)
+}
+
+// failUnprocessed records tasks that were never ported because the batch stopped early
+func (r *Report) failUnprocessed(tasks []*config.Task) {
+	for _, task := range tasks {
+		entry := newReportEntry(task, OutcomeFailed)
+		entry.Reason = "not converted: an earlier write failed"
+
+		r.Add(entry)
+	}
+}
+
+// Count returns how many entries have the given outcome
+func (r *Report) Count(outcome string) int {
+	if r == nil {
+		return 0
+	}
+
+	return countOutcome(r.Entries, outcome)
+}
+
+// CountFidelity returns how many converted entries have the given fidelity level
+func (r *Report) CountFidelity(level string) int {
+	if r == nil {
+		return 0
+	}
+
+	count := 0
+
+	for _, entry := range r.Entries {
+		if entry.Fidelity == level {
+			count++
+		}
+	}
+
+	return count
+}
+
+// BelowFidelity returns the converted entries less faithful than the level minimum
+func (r *Report) BelowFidelity(minimum string) []ReportEntry {
+	if r == nil {
+		return nil
+	}
+
+	var below []ReportEntry
+
+	for _, entry := range r.Entries {
+		if entry.Outcome == OutcomeConverted && fidelityRanks[entry.Fidelity] < fidelityRanks[minimum] {
+			below = append(below, entry)
+		}
+	}
+
+	return below
+}
+
+// ParseMinFidelity validates a --min-fidelity level; approximate is the floor, so only
+// partial and full are worth asking for
+func ParseMinFidelity(level string) (string, error) {
+	switch level {
+	case FidelityPartial, FidelityFull:
+		return level, nil
+	default:
+		return "", fmt.Errorf("invalid fidelity level '%s'. Valid options: %s, %s", level, FidelityPartial, FidelityFull)
+	}
+}
+
+// countOutcome returns how many of entries have the given outcome
+func countOutcome(entries []ReportEntry, outcome string) int {
+	count := 0
+
+	for _, entry := range entries {
+		if entry.Outcome == outcome {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Write saves the report as indented JSON
+func (r *Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	if err := WriteFileAtomic(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return nil
+}
+
+// relative expresses path relative to the project root when it lies inside it
+func (r *Report) relative(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	absRoot, rootErr := filepath.Abs(r.projectRoot)
+	absPath, pathErr := filepath.Abs(path)
+
+	if rootErr != nil || pathErr != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// newReportEntry starts the report entry of a source configuration
+func newReportEntry(task *config.Task, outcome string) ReportEntry {
+	return ReportEntry{SourceFile: task.Source, SourceName: task.Name, Outcome: outcome}
+}
+
+// failEntries marks converted entries as failed when err is set, for targets written as a
+// single file that either all make it to disk or none do
+func failEntries(entries []ReportEntry, err error) []ReportEntry {
+	if err == nil {
+		return entries
+	}
+
+	for i := range entries {
+		if entries[i].Outcome == OutcomeConverted {
+			entries[i].Outcome = OutcomeFailed
+			entries[i].Reason = err.Error()
+		}
+	}
+
+	return entries
+}
+
+// sourceField is a task field that not every target format can express
+type sourceField struct {
+	name  string
+	isSet func(task *config.Task) bool
+}
+
+// sourceFields lists the fields dropUnused checks, named like config.Task's JSON fields. An
+// aggregate task runs no command of its own, so its working directory and environment go unused
+// wherever it runs. Only tasks.json groups are the user's; the other parsers derive the group
+// from the configuration type.
+var sourceFields = []sourceField{
+	{"args", func(t *config.Task) bool { return len(t.Args) > 0 }},
+	{"cwd", func(t *config.Task) bool { return t.Cwd != "" && !t.IsAggregate() }},
+	{"env", func(t *config.Task) bool { return len(t.Env) > 0 && !t.IsAggregate() }},
+	{"unsetEnv", func(t *config.Task) bool { return len(t.UnsetEnv) > 0 }},
+	{"group", func(t *config.Task) bool { return t.Group != "" && t.Type == config.TypeVSCodeTask }},
+	{"dependsOn", func(t *config.Task) bool { return len(t.DependsOn) > 0 }},
+	{"dependsOrder", func(t *config.Task) bool { return t.DependsOrder != "" }},
+	{"preLaunchTask", func(t *config.Task) bool { return t.PreLaunchTask != "" }},
+	{"postDebugTask", func(t *config.Task) bool { return t.PostDebugTask != "" }},
+	{"beforeRun", func(t *config.Task) bool { return len(t.BeforeRun) > 0 }},
+	{"problemPatterns", func(t *config.Task) bool { return len(t.ProblemPatterns) > 0 }},
+	{"runtimePath", func(t *config.Task) bool { return t.RuntimePath != "" }},
+	{"console", func(t *config.Task) bool { return t.Console != "" }},
+	{"interactive", func(t *config.Task) bool { return t.Interactive }},
+	{"confirm", func(t *config.Task) bool { return t.Confirm }},
+}
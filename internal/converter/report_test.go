@@ -0,0 +1,280 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	t.Run("should record every task of a JetBrains port with its target", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		source := filepath.Join(projectRoot, ".vscode", "tasks.json")
+
+		report := NewReport(projectRoot, "1.2.3", "vscode-tasks", "jetbrains", true)
+
+		converter := NewVSCodeToJetBrainsConverter(projectRoot, "", false, nil)
+		converter.SetReport(report)
+
+		tasks := []*config.Task{
+			{Name: "build", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"build"}, Group: "build", ProblemPatterns: []string{"^(.*):(\\d+)$"}, Source: source},
+			{Name: "ci", Type: config.TypeVSCodeTask, DependsOn: []string{"build", "lint"}, DependsOrder: config.DependsOrderSequence, Source: source},
+			{Name: "debug", Type: config.TypeVSCodeLaunch, Command: "go", Source: filepath.Join(projectRoot, ".vscode", "launch.json")},
+		}
+
+		require.NoError(t, converter.ConvertTasks(tasks, true))
+
+		require.Equal(t, []ReportEntry{
+			{
+				SourceFile: ".vscode/launch.json",
+				SourceName: "debug",
+				Outcome:    OutcomeSkipped,
+				Reason:     "not a VSCode task (type vscode-launch)",
+			},
+			{
+				SourceFile:    ".vscode/tasks.json",
+				SourceName:    "build",
+				TargetFile:    ".idea/runConfigurations/build.xml",
+				TargetName:    "build",
+				TargetType:    ShConfigurationType,
+				Outcome:       OutcomeConverted,
+				Fidelity:      FidelityPartial,
+				DroppedFields: []string{"group", "problemPatterns"},
+			},
+			{
+				SourceFile:    ".vscode/tasks.json",
+				SourceName:    "ci",
+				TargetFile:    ".idea/runConfigurations/ci.xml",
+				TargetName:    "ci",
+				TargetType:    ShConfigurationType,
+				Outcome:       OutcomeConverted,
+				Fidelity:      FidelityPartial,
+				DroppedFields: []string{"dependsOn"},
+				Warnings:      []string{`dependency "lint" was not converted and was dropped`},
+			},
+		}, report.Entries)
+
+		require.Equal(t, 2, report.Count(OutcomeConverted))
+		require.Equal(t, 1, report.Count(OutcomeSkipped))
+		require.Zero(t, report.Count(OutcomeFailed))
+	})
+
+	t.Run("should fail every target of a single-file output together", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		makefile := filepath.Join(projectRoot, "Makefile")
+		require.NoError(t, os.WriteFile(makefile, []byte("all:\n\techo hand-written\n"), 0644))
+
+		report := NewReport(projectRoot, "1.2.3", "vscode-tasks", "makefile", false)
+
+		converter := NewVSCodeToMakefileConverter(projectRoot, "", false, nil)
+		converter.SetReport(report)
+
+		tasks := []*config.Task{
+			{Name: "lint", Type: config.TypeVSCodeTask, Command: "golangci-lint", Source: "tasks.json"},
+			{Name: "test", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"test"}, Source: "tasks.json"},
+		}
+
+		err := converter.ConvertTasks(tasks, false)
+		require.ErrorIs(t, err, errNotGenerated)
+
+		require.Len(t, report.Entries, 2)
+
+		for _, entry := range report.Entries {
+			require.Equal(t, OutcomeFailed, entry.Outcome)
+			require.Equal(t, "Makefile", entry.TargetFile)
+			require.Equal(t, err.Error(), entry.Reason)
+		}
+	})
+
+	t.Run("should write the report as JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mapping.json")
+
+		report := NewReport(".", "1.2.3", "jetbrains", "vscode-tasks", true)
+		report.Add(ReportEntry{SourceFile: "run.xml", Outcome: OutcomeSkipped, Reason: "template"})
+
+		require.NoError(t, report.Write(path))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var written map[string]any
+		require.NoError(t, json.Unmarshal(data, &written))
+		require.Equal(t, "1.2.3", written["version"])
+		require.Equal(t, "jetbrains", written["from"])
+		require.Equal(t, "vscode-tasks", written["to"])
+		require.Equal(t, true, written["dryRun"])
+		require.Len(t, written["entries"], 1)
+	})
+
+	t.Run("should ignore entries without a report", func(t *testing.T) {
+		var report *Report
+
+		report.Add(ReportEntry{Outcome: OutcomeConverted})
+		require.Zero(t, report.Count(OutcomeConverted))
+		require.Zero(t, report.CountFidelity(FidelityFull))
+		require.Empty(t, report.BelowFidelity(FidelityFull))
+	})
+}
+
+func TestReportFidelity(t *testing.T) {
+	// fidelities maps the source name of each converted entry to its level and the fields behind it
+	fidelities := func(report *Report) map[string][]string {
+		levels := make(map[string][]string)
+
+		for _, entry := range report.Entries {
+			if entry.Outcome == OutcomeConverted {
+				levels[entry.SourceName] = append(append([]string{entry.Fidelity}, entry.DroppedFields...), entry.ApproximatedFields...)
+			}
+		}
+
+		return levels
+	}
+
+	t.Run("should grade tasks ported to JetBrains", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		report := NewReport(projectRoot, "1.2.3", "vscode-tasks", "jetbrains", true)
+
+		converter := NewVSCodeToJetBrainsConverter(projectRoot, "", false, nil)
+		converter.SetReport(report)
+
+		require.NoError(t, converter.ConvertTasks(parseGoldenTasks(t, projectRoot, "dependency-tasks.json"), true))
+
+		require.Equal(t, map[string][]string{
+			"all":     {FidelityFull},
+			"ci":      {FidelityFull},
+			"compile": {FidelityFull},
+			"lint":    {FidelityFull},
+			"test":    {FidelityFull},
+			"package": {FidelityPartial, "dependsOn"}, // docs is no task
+		}, fidelities(report))
+	})
+
+	t.Run("should grade tasks ported to a Makefile", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		report := NewReport(projectRoot, "1.2.3", "vscode-tasks", "makefile", true)
+
+		converter := NewVSCodeToMakefileConverter(projectRoot, "", false, nil)
+		converter.SetReport(report)
+
+		require.NoError(t, converter.ConvertTasks(parseGoldenTasks(t, projectRoot, "dependency-tasks.json"), true))
+
+		levels := fidelities(report)
+		require.Equal(t, []string{FidelityFull}, levels["all"], "aggregates keep their dependencies as prerequisites")
+		require.Equal(t, []string{FidelityFull}, levels["test"], "tasks with a command keep them too")
+		require.Equal(t, []string{FidelityPartial, "dependsOn"}, levels["package"]) // docs is no task
+	})
+
+	t.Run("should grade launch configurations ported to JetBrains", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		report := NewReport(projectRoot, "1.2.3", "vscode-launch", "jetbrains", true)
+
+		converter := NewVSCodeLaunchToJetBrainsConverter(projectRoot, "", false, nil)
+		converter.SetReport(report)
+
+		tasks, err := vscode.NewLaunchParser(projectRoot, nil).ParseLaunchConfigs(filepath.Join("testdata", "vscode-launch-java.json"))
+		require.NoError(t, err)
+		require.NoError(t, converter.ConvertLaunchConfigs(tasks, true))
+
+		require.Equal(t, map[string][]string{
+			"Launch Java App":  {FidelityPartial, "classPaths"}, // Run configurations take the class path from the module
+			"Debug Java Test":  {FidelityFull},
+			"Java Console App": {FidelityPartial, "console", "interactive"},
+		}, fidelities(report))
+	})
+
+	t.Run("should drop the launch.json keys the parser does not read", func(t *testing.T) {
+		projectRoot := t.TempDir()
+		launchPath := filepath.Join(projectRoot, "launch.json")
+		require.NoError(t, os.WriteFile(launchPath, []byte(`{
+			"version": "0.2.0",
+			"configurations": [
+				{"name": "serve", "type": "go", "request": "launch", "program": "${workspaceFolder}", "buildFlags": "-tags=dev", "envFile": "${workspaceFolder}/.env", "showLog": true, "stopOnEntry": true}
+			]
+		}`), 0600))
+
+		report := NewReport(projectRoot, "1.2.3", "vscode-launch", "jetbrains", true)
+
+		converter := NewVSCodeLaunchToJetBrainsConverter(projectRoot, "", false, nil)
+		converter.SetReport(report)
+
+		tasks, err := vscode.NewLaunchParser(projectRoot, nil).ParseLaunchConfigs(launchPath)
+		require.NoError(t, err)
+		require.NoError(t, converter.ConvertLaunchConfigs(tasks, true))
+
+		require.Equal(t, map[string][]string{
+			"serve": {FidelityPartial, "buildFlags", "envFile", "showLog", "stopOnEntry"},
+		}, fidelities(report))
+	})
+
+	t.Run("should grade references kept as written and guessed values as approximate", func(t *testing.T) {
+		projectRoot := t.TempDir()
+
+		report := NewReport(projectRoot, "1.2.3", "vscode-tasks", "jetbrains", true)
+
+		converter := NewVSCodeToJetBrainsConverter(projectRoot, "", false, nil)
+		converter.SetReport(report)
+
+		require.NoError(t, converter.ConvertTasks([]*config.Task{
+			{Name: "deploy", Type: config.TypeVSCodeTask, Command: "./deploy.sh", Cwd: "${env:DEPLOY_DIR}", Group: "build"},
+		}, true))
+		require.Equal(t, map[string][]string{"deploy": {FidelityApproximate, "group", "WORKING_DIRECTORY"}}, fidelities(report))
+
+		launchReport := NewReport(projectRoot, "1.2.3", "jetbrains", "vscode-launch", true)
+
+		launchConverter := NewJetBrainsToVSCodeLaunchConverter(projectRoot, "", false, nil)
+		launchConverter.SetReport(launchReport)
+
+		require.NoError(t, launchConverter.ConvertToLaunch([]*config.Task{
+			{Name: "Serve", Type: config.TypeJetBrains, Command: "deno run server.ts", Group: "run"},
+			{Name: "Tool Application", Type: config.TypeJetBrains, Command: "python", Args: []string{"-m", "tool"}, Group: "run"},
+		}, true))
+		require.Equal(t, map[string][]string{
+			"Serve":            {FidelityApproximate, "type"},
+			"Tool Application": {FidelityFull},
+		}, fidelities(launchReport))
+	})
+
+	t.Run("should list the configurations below a level", func(t *testing.T) {
+		report := NewReport(".", "1.2.3", "vscode-tasks", "jetbrains", true)
+		report.Add(
+			ReportEntry{SourceName: "build", Outcome: OutcomeConverted},
+			ReportEntry{SourceName: "test", Outcome: OutcomeConverted, DroppedFields: []string{"group"}},
+			ReportEntry{SourceName: "deploy", Outcome: OutcomeConverted, DroppedFields: []string{"group"}, ApproximatedFields: []string{"cwd"}},
+			ReportEntry{SourceName: "lint", Outcome: OutcomeFailed, Reason: "broken"},
+		)
+
+		require.Equal(t, 1, report.CountFidelity(FidelityFull))
+		require.Equal(t, 1, report.CountFidelity(FidelityPartial))
+		require.Equal(t, 1, report.CountFidelity(FidelityApproximate))
+		require.Empty(t, report.Entries[3].Fidelity, "only converted configurations are graded")
+
+		names := func(entries []ReportEntry) []string {
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.SourceName)
+			}
+
+			return names
+		}
+
+		require.Equal(t, []string{"test", "deploy"}, names(report.BelowFidelity(FidelityFull)))
+		require.Equal(t, []string{"deploy"}, names(report.BelowFidelity(FidelityPartial)))
+	})
+
+	t.Run("should accept only partial and full as a minimum", func(t *testing.T) {
+		for _, level := range []string{FidelityPartial, FidelityFull} {
+			parsed, err := ParseMinFidelity(level)
+			require.NoError(t, err)
+			require.Equal(t, level, parsed)
+		}
+
+		_, err := ParseMinFidelity(FidelityApproximate)
+		require.EqualError(t, err, "invalid fidelity level 'approximate'. Valid options: partial, full")
+	})
+}
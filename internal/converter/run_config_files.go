@@ -0,0 +1,238 @@
+package converter
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// outputPlaceholder matches the placeholders of an --output template, e.g. {group}
+var outputPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// OutputPlaceholders are the placeholders --output templates of per-task targets accept
+var OutputPlaceholders = []string{"{name}", "{source}", "{group}"}
+
+// IsOutputTemplate reports whether an --output path has placeholders such as {group}
+func IsOutputTemplate(outputPath string) bool {
+	return outputPlaceholder.MatchString(outputPath)
+}
+
+// OutputTemplate names and organizes the run configurations of per-task targets by task,
+// e.g. ".idea/runConfigurations/{group}/{name}.xml" files each configuration under a folder
+// named after its group. IntelliJ only loads the files directly in runConfigurations, so every
+// file is written to the base directory and the directories after it become the
+// configuration's folderName, the folder it is shown in. Placeholders expand to {name}, the
+// task name, {source}, its type (vscode-task, vscode-launch) and {group}, its group, made
+// filename-safe in the file name. Folders that expand to nothing are left out, so ungrouped
+// tasks stay at the top.
+type OutputTemplate struct {
+	pattern string
+}
+
+// ParseOutputTemplate checks the placeholders of pattern. A pattern whose last element does
+// not end in ext names a directory and gets "{name}"+ext appended.
+func ParseOutputTemplate(pattern, ext string) (*OutputTemplate, error) {
+	for _, placeholder := range outputPlaceholder.FindAllString(pattern, -1) {
+		known := false
+
+		for _, name := range OutputPlaceholders {
+			known = known || placeholder == name
+		}
+
+		if !known {
+			return nil, fmt.Errorf("unknown placeholder %s in output template %s (valid: %s)", placeholder, pattern, strings.Join(OutputPlaceholders, ", "))
+		}
+	}
+
+	if hasTrailingSeparator(pattern) || !strings.EqualFold(filepath.Ext(pattern), ext) {
+		pattern = filepath.Join(pattern, "{name}"+ext)
+	}
+
+	return &OutputTemplate{pattern: filepath.Clean(pattern)}, nil
+}
+
+// BaseDir returns the directory every expanded path lies in: the elements of the pattern
+// before the first placeholder
+func (t *OutputTemplate) BaseDir() string {
+	dir := filepath.Dir(t.pattern[:outputPlaceholder.FindStringIndex(t.pattern)[0]] + "x")
+
+	return filepath.Clean(dir)
+}
+
+// Expand returns the folder task's configuration is shown in, empty for none, and the name of
+// its file in BaseDir without the extension
+func (t *OutputTemplate) Expand(task *config.Task) (string, string) {
+	rest, err := filepath.Rel(t.BaseDir(), t.pattern)
+	if err != nil {
+		rest = filepath.Base(t.pattern)
+	}
+
+	elements := strings.Split(rest, string(filepath.Separator))
+
+	var folders []string
+
+	folderReplacer := strings.NewReplacer("{name}", task.Name, "{source}", string(task.Type), "{group}", task.Group)
+	for _, element := range elements[:len(elements)-1] {
+		if folder := strings.TrimSpace(folderReplacer.Replace(element)); folder != "" {
+			folders = append(folders, folder)
+		}
+	}
+
+	file := strings.NewReplacer(
+		"{name}", templateValue(task.Name),
+		"{source}", templateValue(string(task.Type)),
+		"{group}", templateValue(task.Group),
+	).Replace(elements[len(elements)-1])
+
+	return strings.Join(folders, "/"), strings.TrimSuffix(file, filepath.Ext(file))
+}
+
+// templateValue makes value safe as (part of) a file name, so no task can place its file
+// outside the template's base directory
+func templateValue(value string) string {
+	value = sanitizeFilename(value)
+	if value == "." || value == ".." {
+		return "_"
+	}
+
+	return value
+}
+
+// runConfigFiles hands out JetBrains run configuration filenames for one conversion batch.
+// Two configurations whose names sanitize to the same filename, or a name that matches an
+// existing file holding a different configuration, get a numeric suffix instead of
+// overwriting each other.
+type runConfigFiles struct {
+	ctx             context.Context
+	caseInsensitive bool
+	template        *OutputTemplate
+	used            map[string]bool
+	existing        map[string]string // Normalized filename to the path on disk
+}
+
+// newRunConfigFiles indexes the files already in outputDir. Filenames are compared
+// case-insensitively on macOS and Windows, whose default filesystems are.
+func newRunConfigFiles(ctx context.Context, outputDir string) *runConfigFiles {
+	files := &runConfigFiles{
+		ctx:             ctx,
+		caseInsensitive: runtime.GOOS == "darwin" || runtime.GOOS == "windows",
+		used:            make(map[string]bool),
+		existing:        make(map[string]string),
+	}
+
+	files.index(outputDir)
+
+	return files
+}
+
+// newTemplatedRunConfigFiles names and organizes files by an --output template
+func newTemplatedRunConfigFiles(ctx context.Context, template *OutputTemplate) *runConfigFiles {
+	files := newRunConfigFiles(ctx, template.BaseDir())
+	files.template = template
+
+	return files
+}
+
+// resolveRunConfigOutput resolves --output for JetBrains run configurations into the files
+// of the batch and the directory they are written under
+func resolveRunConfigOutput(ctx context.Context, outputPath, projectRoot string) (*runConfigFiles, string, error) {
+	if IsOutputTemplate(outputPath) {
+		template, err := ParseOutputTemplate(outputPath, ".xml")
+		if err != nil {
+			return nil, "", err
+		}
+
+		return newTemplatedRunConfigFiles(ctx, template), template.BaseDir(), nil
+	}
+
+	outputDir, err := ResolveDirOutput(outputPath, filepath.Join(projectRoot, ".idea", "runConfigurations"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return newRunConfigFiles(ctx, outputDir), outputDir, nil
+}
+
+// index records the existing files in outputDir, a missing directory has none
+func (f *runConfigFiles) index(outputDir string) {
+	entries, err := config.ReadDirContext(f.ctx, outputDir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			f.existing[f.normalize(entry.Name())] = filepath.Join(outputDir, entry.Name())
+		}
+	}
+}
+
+// place returns the folder task's configuration is shown in (empty for none) and its sanitized
+// file name without extension, before allocate resolves clashes
+func (f *runConfigFiles) place(task *config.Task) (string, string) {
+	if f.template == nil {
+		return "", sanitizeFilename(task.Name)
+	}
+
+	return f.template.Expand(task)
+}
+
+// allocate returns the filename in the output directory for the named configuration, and
+// whether it differs from the plain sanitized name
+func (f *runConfigFiles) allocate(configName, sanitized string) (string, bool) {
+	for n := 1; ; n++ {
+		filename := sanitized + ".xml"
+		if n > 1 {
+			filename = fmt.Sprintf("%s_%d.xml", sanitized, n)
+		}
+
+		key := f.normalize(filename)
+		if f.used[key] {
+			continue
+		}
+
+		// Regenerating the same configuration replaces its previous file
+		if existingPath, ok := f.existing[key]; ok && runConfigName(f.ctx, existingPath) != configName {
+			continue
+		}
+
+		f.used[key] = true
+
+		return filename, n > 1
+	}
+}
+
+func (f *runConfigFiles) normalize(filename string) string {
+	if f.caseInsensitive {
+		return strings.ToLower(filename)
+	}
+
+	return filename
+}
+
+// addFolder shows the configuration in folder, ahead of the tag annotation it may already have
+func (c *JetBrainsRunConfiguration) addFolder(folder string) {
+	c.Folder = strings.TrimSpace(folder + " " + c.Folder)
+}
+
+// runConfigName returns the configuration name stored in a JetBrains run configuration file,
+// or an empty string when it can't be read
+func runConfigName(ctx context.Context, path string) string {
+	data, err := config.ReadFileContext(ctx, path)
+	if err != nil {
+		return ""
+	}
+
+	var component JetBrainsComponent
+	if err := xml.Unmarshal(data, &component); err != nil {
+		return ""
+	}
+
+	return component.Configuration.Name
+}
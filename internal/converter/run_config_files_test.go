@@ -0,0 +1,130 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConfigFiles(t *testing.T) {
+	outputDir := t.TempDir()
+
+	converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+	require.NoError(t, converter.ConvertTasks([]*config.Task{{Name: "Build", Type: config.TypeVSCodeTask, Command: "make"}}, false))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "notes.xml"), []byte("not a run configuration"), 0644))
+
+	t.Run("should suffix names used earlier in the batch", func(t *testing.T) {
+		files := newRunConfigFiles(context.Background(), t.TempDir())
+
+		filename, renamed := files.allocate("deploy:prod", "deploy_prod")
+		require.Equal(t, "deploy_prod.xml", filename)
+		require.False(t, renamed)
+
+		filename, renamed = files.allocate("deploy prod", "deploy_prod")
+		require.Equal(t, "deploy_prod_2.xml", filename)
+		require.True(t, renamed)
+
+		filename, _ = files.allocate("deploy/prod", "deploy_prod")
+		require.Equal(t, "deploy_prod_3.xml", filename)
+	})
+
+	t.Run("should reuse the file of the same configuration", func(t *testing.T) {
+		files := newRunConfigFiles(context.Background(), outputDir)
+
+		filename, renamed := files.allocate("Build", "Build")
+		require.Equal(t, "Build.xml", filename)
+		require.False(t, renamed)
+	})
+
+	t.Run("should skip files holding something else", func(t *testing.T) {
+		files := newRunConfigFiles(context.Background(), outputDir)
+
+		filename, renamed := files.allocate("notes", "notes")
+		require.Equal(t, "notes_2.xml", filename)
+		require.True(t, renamed)
+	})
+
+	t.Run("should compare case-insensitively when the filesystem does", func(t *testing.T) {
+		files := &runConfigFiles{caseInsensitive: true, used: map[string]bool{}, existing: map[string]string{}}
+		files.index(outputDir)
+
+		filename, renamed := files.allocate("build", "build")
+		require.Equal(t, "build_2.xml", filename)
+		require.True(t, renamed)
+
+		filename, renamed = files.allocate("Build", "Build")
+		require.Equal(t, "Build.xml", filename)
+		require.False(t, renamed)
+
+		files = &runConfigFiles{caseInsensitive: false, used: map[string]bool{}, existing: map[string]string{}}
+		files.index(outputDir)
+
+		filename, renamed = files.allocate("build", "build")
+		require.Equal(t, "build.xml", filename)
+		require.False(t, renamed)
+	})
+}
+
+func TestOutputTemplate(t *testing.T) {
+	task := &config.Task{Name: "deploy: prod", Type: config.TypeVSCodeLaunch, Group: "ops"}
+
+	t.Run("should expand placeholders into filename-safe values", func(t *testing.T) {
+		template, err := ParseOutputTemplate(filepath.Join(".idea", "runConfigurations", "{source}", "{group}-{name}.xml"), ".xml")
+		require.NoError(t, err)
+
+		require.Equal(t, filepath.Join(".idea", "runConfigurations"), template.BaseDir())
+
+		folder, file := template.Expand(task)
+		require.Equal(t, "vscode-launch", folder)
+		require.Equal(t, "ops-deploy__prod", file)
+	})
+
+	t.Run("should name files after the task when the template is a directory", func(t *testing.T) {
+		template, err := ParseOutputTemplate(filepath.Join("configs", "{group}"), ".xml")
+		require.NoError(t, err)
+
+		require.Equal(t, "configs", template.BaseDir())
+
+		folder, file := template.Expand(task)
+		require.Equal(t, "ops", folder)
+		require.Equal(t, "deploy__prod", file)
+
+		folder, file = template.Expand(&config.Task{Name: "lint"})
+		require.Empty(t, folder)
+		require.Equal(t, "lint", file)
+	})
+
+	t.Run("should join nested folders", func(t *testing.T) {
+		template, err := ParseOutputTemplate(filepath.Join("configs", "{source}", "{group}", "{name}.xml"), ".xml")
+		require.NoError(t, err)
+
+		folder, _ := template.Expand(task)
+		require.Equal(t, "vscode-launch/ops", folder)
+	})
+
+	t.Run("should keep every file in the base directory", func(t *testing.T) {
+		template, err := ParseOutputTemplate(filepath.Join("configs", "{group}", "{name}.xml"), ".xml")
+		require.NoError(t, err)
+
+		_, file := template.Expand(&config.Task{Name: "..", Group: ".."})
+		require.Equal(t, "_", file)
+
+		_, file = template.Expand(&config.Task{Name: "a/b"})
+		require.Equal(t, "a_b", file)
+	})
+
+	t.Run("should reject unknown placeholders", func(t *testing.T) {
+		_, err := ParseOutputTemplate("configs/{label}", ".xml")
+		require.EqualError(t, err, "unknown placeholder {label} in output template configs/{label} (valid: {name}, {source}, {group})")
+	})
+
+	t.Run("should recognize templates", func(t *testing.T) {
+		require.True(t, IsOutputTemplate(".idea/runConfigurations/{group}"))
+		require.False(t, IsOutputTemplate(".idea/runConfigurations/"))
+	})
+}
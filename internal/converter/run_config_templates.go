@@ -0,0 +1,101 @@
+package converter
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// applyTemplate layers the template for the configuration's type beneath it: options,
+// environment variables and external system settings the configuration doesn't set are taken
+// from the template. It reports whether a template applied.
+func applyTemplate(templates config.RunConfigTemplates, runConfig *JetBrainsRunConfiguration) bool {
+	template, ok := templates[runConfig.Type]
+	if !ok {
+		return false
+	}
+
+	if runConfig.ExternalSystemSettings != nil {
+		applySettings(runConfig.ExternalSystemSettings, template.Settings)
+	}
+
+	set := make(map[string]bool, len(runConfig.Options))
+	for _, option := range runConfig.Options {
+		set[option.Name] = true
+	}
+
+	for _, option := range template.Options {
+		if !set[option.Name] {
+			runConfig.Options = append(runConfig.Options, JetBrainsOption{Name: option.Name, Value: option.Value})
+		}
+	}
+
+	if len(template.EnvVars) == 0 {
+		return true
+	}
+
+	if runConfig.EnvVars == nil {
+		runConfig.EnvVars = &JetBrainsEnvVars{}
+	}
+
+	setEnv := make(map[string]bool, len(runConfig.EnvVars.EnvVars))
+	for _, env := range runConfig.EnvVars.EnvVars {
+		setEnv[env.Name] = true
+	}
+
+	for _, env := range template.EnvVars {
+		if !setEnv[env.Name] {
+			runConfig.EnvVars.EnvVars = append(runConfig.EnvVars.EnvVars, JetBrainsEnvVar{Name: env.Name, Value: env.Value})
+		}
+	}
+
+	// Keep the deterministic ordering of generated configurations
+	sort.SliceStable(runConfig.EnvVars.EnvVars, func(i, j int) bool {
+		return runConfig.EnvVars.EnvVars[i].Name < runConfig.EnvVars.EnvVars[j].Name
+	})
+
+	return true
+}
+
+// applySettings layers template settings beneath those of a configuration. A map such as env is
+// merged per key; any other setting is taken only when the configuration lacks it.
+func applySettings(settings *JetBrainsExternalSystemSettings, template []config.TemplateSetting) {
+	for _, setting := range template {
+		i := slices.IndexFunc(settings.Options, func(set JetBrainsSettingOption) bool { return set.Name == setting.Name })
+		if i < 0 {
+			settings.Options = append(settings.Options, JetBrainsSettingOption{Name: setting.Name, Value: setting.Value, Map: settingMap(setting.Map)})
+			continue
+		}
+
+		if setting.Map == nil || settings.Options[i].Map == nil {
+			continue
+		}
+
+		merged := settings.Options[i].Map
+		for _, entry := range setting.Map {
+			if !slices.ContainsFunc(merged.Entries, func(set JetBrainsSettingMapEntry) bool { return set.Key == entry.Name }) {
+				merged.Entries = append(merged.Entries, JetBrainsSettingMapEntry{Key: entry.Name, Value: entry.Value})
+			}
+		}
+
+		sort.SliceStable(merged.Entries, func(a, b int) bool { return merged.Entries[a].Key < merged.Entries[b].Key })
+	}
+
+	// Keep the options in the order JetBrains writes them
+	sort.SliceStable(settings.Options, func(a, b int) bool { return settings.Options[a].Name < settings.Options[b].Name })
+}
+
+// settingMap builds the map of a setting taken from a template, or nil for a plain value
+func settingMap(entries []config.TemplateValue) *JetBrainsSettingMap {
+	if entries == nil {
+		return nil
+	}
+
+	settingMap := &JetBrainsSettingMap{}
+	for _, entry := range entries {
+		settingMap.Entries = append(settingMap.Entries, JetBrainsSettingMapEntry{Key: entry.Name, Value: entry.Value})
+	}
+
+	return settingMap
+}
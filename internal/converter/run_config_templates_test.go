@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTemplate(t *testing.T) {
+	t.Run("should layer templates beneath the configuration", func(t *testing.T) {
+		templates := config.RunConfigTemplates{
+			ShConfigurationType: {
+				Type:    ShConfigurationType,
+				Options: []config.TemplateValue{{Name: "INTERPRETER_PATH", Value: "/bin/bash"}, {Name: "WORKING_DIRECTORY", Value: "/tmp"}},
+				EnvVars: []config.TemplateValue{{Name: "LANG", Value: "C.UTF-8"}, {Name: "STAGE", Value: "template"}},
+			},
+		}
+
+		runConfig := &JetBrainsRunConfiguration{
+			Name:    "deploy",
+			Type:    ShConfigurationType,
+			Options: []JetBrainsOption{{Name: "SCRIPT_TEXT", Value: "./deploy.sh"}, {Name: "WORKING_DIRECTORY", Value: "$PROJECT_DIR$"}},
+			EnvVars: &JetBrainsEnvVars{EnvVars: []JetBrainsEnvVar{{Name: "STAGE", Value: "prod"}}},
+		}
+
+		require.True(t, applyTemplate(templates, runConfig))
+		require.Equal(t, []JetBrainsOption{
+			{Name: "SCRIPT_TEXT", Value: "./deploy.sh"},
+			{Name: "WORKING_DIRECTORY", Value: "$PROJECT_DIR$"},
+			{Name: "INTERPRETER_PATH", Value: "/bin/bash"},
+		}, runConfig.Options)
+		require.Equal(t, []JetBrainsEnvVar{{Name: "LANG", Value: "C.UTF-8"}, {Name: "STAGE", Value: "prod"}}, runConfig.EnvVars.EnvVars)
+
+		require.False(t, applyTemplate(templates, &JetBrainsRunConfiguration{Type: "Application"}))
+	})
+
+	t.Run("should apply templates to generated configurations", func(t *testing.T) {
+		outputDir := t.TempDir()
+
+		conv := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+		conv.SetTemplates(config.RunConfigTemplates{
+			ShConfigurationType: {
+				Type:    ShConfigurationType,
+				Options: []config.TemplateValue{{Name: "INTERPRETER_PATH", Value: "/bin/bash"}},
+				EnvVars: []config.TemplateValue{{Name: "LANG", Value: "C.UTF-8"}},
+			},
+		})
+		require.NoError(t, conv.ConvertTasks([]*config.Task{{Name: "pipeline", Type: config.TypeVSCodeTask, Execution: config.ExecutionShell, Command: "make lint && make test"}}, false))
+
+		data, err := os.ReadFile(filepath.Join(outputDir, "pipeline.xml"))
+		require.NoError(t, err)
+		require.Contains(t, string(data), `<option name="INTERPRETER_PATH" value="/bin/bash"`)
+		require.Contains(t, string(data), `<env name="LANG" value="C.UTF-8"`)
+	})
+
+	t.Run("should apply templates to the settings of generated Gradle configurations", func(t *testing.T) {
+		outputDir := t.TempDir()
+
+		conv := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+		conv.SetTemplates(config.RunConfigTemplates{
+			GradleConfigurationType: {
+				Type: GradleConfigurationType,
+				Settings: []config.TemplateSetting{
+					{Name: "env", Map: []config.TemplateValue{{Name: "CI", Value: "true"}, {Name: "STAGE", Value: "template"}}},
+					{Name: "externalProjectPath", Value: "$PROJECT_DIR$/template"},
+					{Name: "vmOptions", Value: "-Xmx2g"},
+				},
+			},
+		})
+		require.NoError(t, conv.ConvertTasks([]*config.Task{{Name: "build", Type: config.TypeVSCodeTask, Command: "./gradlew", Args: []string{"build"}, Env: map[string]string{"STAGE": "prod"}}}, false))
+
+		settings := readJetBrainsConfig(t, filepath.Join(outputDir, "build.xml")).ExternalSystemSettings
+		require.NotNil(t, settings)
+
+		values := make(map[string]string)
+		for _, option := range settings.Options {
+			values[option.Name] = option.Value
+
+			if option.Name == "env" {
+				require.Equal(t, []JetBrainsSettingMapEntry{{Key: "CI", Value: "true"}, {Key: "STAGE", Value: "prod"}}, option.Map.Entries)
+			}
+		}
+
+		require.Equal(t, "$PROJECT_DIR$", values["externalProjectPath"])
+		require.Equal(t, "-Xmx2g", values["vmOptions"])
+	})
+}
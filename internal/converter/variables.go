@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// variableMapping pairs a VSCode variable with the closest JetBrains macro
+type variableMapping struct {
+	vscode    string
+	jetbrains string
+}
+
+// vscodeVariables maps every VSCode predefined variable that has a JetBrains counterpart.
+// Where several VSCode variables share a macro, the first one is used in the JetBrains → VSCode direction.
+var vscodeVariables = []variableMapping{
+	{"${workspaceFolder}", "$PROJECT_DIR$"},
+	{"${workspaceRoot}", "$PROJECT_DIR$"},
+	{"${fileWorkspaceFolder}", "$PROJECT_DIR$"}, // Taskporter projects have a single workspace folder
+	{"${workspaceFolderBasename}", "$ProjectName$"},
+	{"${file}", "$FilePath$"},
+	{"${relativeFile}", "$FilePathRelativeToProjectRoot$"},
+	{"${relativeFileDirname}", "$FileDirRelativeToProjectRoot$"},
+	{"${fileBasename}", "$FileName$"},
+	{"${fileBasenameNoExtension}", "$FileNameWithoutExtension$"},
+	{"${fileDirname}", "$FileDir$"},
+	{"${fileDirnameBasename}", "$FileDirName$"},
+	{"${fileExtname}", ".$FileExt$"}, // VSCode includes the dot, JetBrains does not
+	{"${lineNumber}", "$LineNumber$"},
+	{"${selectedText}", "$SelectedText$"},
+}
+
+// jetbrainsOnlyMacros maps JetBrains macros with no VSCode variable of their own
+var jetbrainsOnlyMacros = []variableMapping{
+	{"${workspaceFolder}", "$MODULE_DIR$"},
+	{"${workspaceFolder}", "$ContentRoot$"},
+}
+
+// ConvertVSCodeVariables replaces VSCode variables with the closest JetBrains macros
+func ConvertVSCodeVariables(input string) string {
+	result := input
+	for _, mapping := range vscodeVariables {
+		result = strings.ReplaceAll(result, mapping.vscode, mapping.jetbrains)
+	}
+
+	return result
+}
+
+// ConvertJetBrainsVariables replaces JetBrains macros with the closest VSCode variables
+func ConvertJetBrainsVariables(input string) string {
+	result := input
+
+	seen := make(map[string]bool, len(vscodeVariables))
+	for _, mapping := range slices.Concat(vscodeVariables, jetbrainsOnlyMacros) {
+		if seen[mapping.jetbrains] {
+			continue
+		}
+
+		seen[mapping.jetbrains] = true
+		result = strings.ReplaceAll(result, mapping.jetbrains, mapping.vscode)
+	}
+
+	return result
+}
+
+// relativizeToWorkspace rewrites an absolute path inside projectRoot as ${workspaceFolder} or
+// ${workspaceFolder}/<relative path>. Parsers resolve project variables to absolute paths, so
+// converters use this to keep generated configurations usable from any checkout. Paths outside
+// the project root, relative paths and paths with variables are returned unchanged.
+func relativizeToWorkspace(path, projectRoot string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+
+	absRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(absRoot, filepath.Clean(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+
+	if rel == "." {
+		return "${workspaceFolder}"
+	}
+
+	return "${workspaceFolder}/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,87 @@
+package converter
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariableConversion(t *testing.T) {
+	// Every supported VSCode variable, with the JetBrains macro it becomes and the VSCode
+	// variable that macro converts back to
+	testCases := []struct {
+		vscode    string
+		jetbrains string
+		back      string
+	}{
+		{"${workspaceFolder}", "$PROJECT_DIR$", "${workspaceFolder}"},
+		{"${workspaceRoot}", "$PROJECT_DIR$", "${workspaceFolder}"},
+		{"${fileWorkspaceFolder}", "$PROJECT_DIR$", "${workspaceFolder}"},
+		{"${workspaceFolderBasename}", "$ProjectName$", "${workspaceFolderBasename}"},
+		{"${file}", "$FilePath$", "${file}"},
+		{"${relativeFile}", "$FilePathRelativeToProjectRoot$", "${relativeFile}"},
+		{"${relativeFileDirname}", "$FileDirRelativeToProjectRoot$", "${relativeFileDirname}"},
+		{"${fileBasename}", "$FileName$", "${fileBasename}"},
+		{"${fileBasenameNoExtension}", "$FileNameWithoutExtension$", "${fileBasenameNoExtension}"},
+		{"${fileDirname}", "$FileDir$", "${fileDirname}"},
+		{"${fileDirnameBasename}", "$FileDirName$", "${fileDirnameBasename}"},
+		{"${fileExtname}", ".$FileExt$", "${fileExtname}"},
+		{"${lineNumber}", "$LineNumber$", "${lineNumber}"},
+		{"${selectedText}", "$SelectedText$", "${selectedText}"},
+	}
+
+	require.Len(t, testCases, len(vscodeVariables), "every supported variable should be covered")
+
+	for _, tc := range testCases {
+		t.Run(tc.vscode, func(t *testing.T) {
+			input := "--target " + tc.vscode + "/out"
+
+			converted := ConvertVSCodeVariables(input)
+			require.Equal(t, "--target "+tc.jetbrains+"/out", converted)
+			require.NotContains(t, converted, "${")
+			require.Equal(t, "--target "+tc.back+"/out", ConvertJetBrainsVariables(converted))
+		})
+	}
+
+	t.Run("should convert JetBrains-only macros", func(t *testing.T) {
+		require.Equal(t, "${workspaceFolder}/a:${workspaceFolder}/b", ConvertJetBrainsVariables("$MODULE_DIR$/a:$ContentRoot$/b"))
+	})
+
+	t.Run("should keep similar macro names apart", func(t *testing.T) {
+		input := "${fileDirname} ${fileDirnameBasename} ${relativeFileDirname} ${fileBasename}${fileExtname} ${fileBasenameNoExtension}"
+		require.Equal(t, input, ConvertJetBrainsVariables(ConvertVSCodeVariables(input)))
+	})
+
+	t.Run("should leave unknown variables alone", func(t *testing.T) {
+		require.Equal(t, "${env:HOME}/bin", ConvertVSCodeVariables("${env:HOME}/bin"))
+	})
+}
+
+func TestRelativizeToWorkspace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture paths are POSIX paths")
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "project root", path: "/work/app", expected: "${workspaceFolder}"},
+		{name: "project root with trailing separator", path: "/work/app/", expected: "${workspaceFolder}"},
+		{name: "inside the project", path: "/work/app/cmd/server", expected: "${workspaceFolder}/cmd/server"},
+		{name: "directory starting with dots", path: "/work/app/..cache", expected: "${workspaceFolder}/..cache"},
+		{name: "outside the project", path: "/usr/lib/jvm", expected: "/usr/lib/jvm"},
+		{name: "parent of the project", path: "/work", expected: "/work"},
+		{name: "sibling with a common prefix", path: "/work/app-tools", expected: "/work/app-tools"},
+		{name: "relative path", path: "cmd/server", expected: "cmd/server"},
+		{name: "variable", path: "${workspaceFolder}/cmd", expected: "${workspaceFolder}/cmd"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, relativizeToWorkspace(tc.path, "/work/app"))
+		})
+	}
+}
@@ -1,112 +1,229 @@
 package converter
 
 import (
-	"encoding/xml"
+	"errors"
 	"fmt"
-	"os"
+	"log/slog"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/shellwords"
 )
 
 // VSCodeLaunchToJetBrainsConverter converts VSCode launch configurations to JetBrains run configurations
 type VSCodeLaunchToJetBrainsConverter struct {
+	WriteOptions
 	projectRoot string
 	outputPath  string
 	verbose     bool
+	templates   config.RunConfigTemplates
+	logger      *slog.Logger
 }
 
 // NewVSCodeLaunchToJetBrainsConverter creates a new launch to JetBrains converter
-func NewVSCodeLaunchToJetBrainsConverter(projectRoot, outputPath string, verbose bool) *VSCodeLaunchToJetBrainsConverter {
+func NewVSCodeLaunchToJetBrainsConverter(projectRoot, outputPath string, verbose bool, logger *slog.Logger) *VSCodeLaunchToJetBrainsConverter {
 	return &VSCodeLaunchToJetBrainsConverter{
-		projectRoot: projectRoot,
-		outputPath:  outputPath,
-		verbose:     verbose,
+		projectRoot:  projectRoot,
+		outputPath:   outputPath,
+		verbose:      verbose,
+		WriteOptions: defaultWriteOptions(),
+		logger:       logging.OrDiscard(logger).With(logging.KeyComponent, "vscode-launch-to-jetbrains"),
 	}
 }
 
+// SetTemplates layers the project's run configuration templates beneath the generated
+// configurations, so they get the same defaults as configurations created in the IDE
+func (c *VSCodeLaunchToJetBrainsConverter) SetTemplates(templates config.RunConfigTemplates) {
+	c.templates = templates
+}
+
 // ConvertLaunchConfigs converts VSCode launch configurations to JetBrains run configurations
 func (c *VSCodeLaunchToJetBrainsConverter) ConvertLaunchConfigs(tasks []*config.Task, dryRun bool) error {
 	if c.verbose {
-		fmt.Printf("🔄 Converting %d VSCode launch configurations to JetBrains format...\n", len(tasks))
+		fmt.Fprintf(c.Out, "🔄 Converting %d VSCode launch configurations to JetBrains format...\n", len(tasks))
 	}
 
 	// Filter only VSCode launch tasks
 	launchTasks := make([]*config.Task, 0)
 	for _, task := range tasks {
-		if task.Type == config.TypeVSCodeLaunch {
-			launchTasks = append(launchTasks, task)
+		if task.Type != config.TypeVSCodeLaunch {
+			c.Report.Skip(task, fmt.Sprintf("not a VSCode launch configuration (type %s)", task.Type))
+			continue
 		}
+
+		launchTasks = append(launchTasks, task)
 	}
 
 	if len(launchTasks) == 0 {
-		fmt.Printf("⚠️  No VSCode launch configurations found to convert\n")
+		fmt.Fprintf(c.Out, "⚠️  No VSCode launch configurations found to convert\n")
 		return nil
 	}
 
 	if c.verbose {
-		fmt.Printf("📋 Converting %d VSCode launch configurations\n", len(launchTasks))
+		fmt.Fprintf(c.Out, "📋 Converting %d VSCode launch configurations\n", len(launchTasks))
 	}
 
-	// Determine output directory
-	outputDir := c.outputPath
-	if outputDir == "" {
-		outputDir = filepath.Join(c.projectRoot, ".idea", "runConfigurations")
+	// Determine output directory, the top one when --output is a template
+	files, outputDir, err := resolveRunConfigOutput(c.Context, c.outputPath, c.projectRoot)
+	if err != nil {
+		return err
 	}
 
 	if c.verbose {
-		fmt.Printf("📁 Output directory: %s\n", outputDir)
+		fmt.Fprintf(c.Out, "📁 Output directory: %s\n", outputDir)
 	}
 
-	// Create output directory if not in dry-run mode
 	if !dryRun {
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+		if err := ensureWritableDir(outputDir); err != nil {
+			return err
 		}
 	}
 
 	convertedCount := 0
 
-	for _, task := range launchTasks {
-		config, err := c.convertSingleLaunchConfig(task)
+	var written []string
+
+	// Compounds follow the configurations they start, so their members are converted first
+	converted := make(map[string]*JetBrainsRunConfiguration, len(launchTasks))
+	preview := newDirPreview(c.Context, c.Out, c.FullPreview)
+
+	for i, task := range launchTasks {
+		entry := newReportEntry(task, OutcomeConverted)
+
+		config, err := c.convertSingleLaunchConfig(task, &entry)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: failed to convert launch config '%s': %v\n", task.Name, err)
+			c.logger.Warn("failed to convert launch config", logging.KeyTask, task.Name, "error", err)
+
+			entry.Outcome, entry.Reason = OutcomeFailed, err.Error()
+			c.Report.Add(entry)
+
 			continue
 		}
 
-		// Generate filename (sanitize task name)
-		filename := c.sanitizeFilename(task.Name) + ".xml"
+		refs := translateRunConfigurationEnvRefs(config)
+		entry.approximate(refs.fields...)
+
+		for _, warning := range refs.warnings {
+			c.logger.Warn("kept untranslatable env reference", logging.KeyTask, task.Name, "warning", warning)
+
+			entry.Warnings = append(entry.Warnings, warning)
+		}
+
+		if applyTemplate(c.templates, config) {
+			c.logger.Debug("applied run configuration template", logging.KeyTask, task.Name, "type", config.Type, logging.KeyFile, c.templates[config.Type].Source)
+		}
+
+		if task.IsCompound() {
+			for _, name := range c.linkCompoundMembers(task, config, converted) {
+				entry.Warnings = append(entry.Warnings, fmt.Sprintf("compound member %q was not converted and was dropped", name))
+				entry.drop("dependsOn")
+			}
+		} else if _, ok := converted[task.Name]; !ok {
+			converted[task.Name] = config
+		}
+
+		entry.dropUnused(task)
+
+		// Generate filename (sanitize task name), shown in the folder the --output template picks
+		folder, sanitized := files.place(task)
+		config.addFolder(folder)
+
+		filename, renamed := files.allocate(config.Name, sanitized)
 		outputPath := filepath.Join(outputDir, filename)
 
+		if renamed {
+			c.logger.Warn("run configuration file name is already taken", logging.KeyTask, task.Name, logging.KeyFile, sanitized+".xml", "writing", filename)
+
+			entry.Warnings = append(entry.Warnings, fmt.Sprintf("%s.xml is already taken", sanitized))
+		}
+
+		entry.TargetFile, entry.TargetName, entry.TargetType = outputPath, config.Name, config.Type
+
 		if dryRun {
-			fmt.Printf("   [DRY RUN] Would create: %s\n", outputPath)
+			content, err := renderRunConfiguration(config, task.Platform)
+			if err != nil {
+				return err
+			}
 
-			// Show XML preview
-			xmlData, _ := xml.MarshalIndent(config, "", "  ")
-			fmt.Printf("📝 Preview of %s:\n%s\n\n", filename, string(xmlData))
+			preview.file(outputPath, content)
 		} else {
-			if err := c.writeJetBrainsRunConfig(config, outputPath); err != nil {
-				fmt.Printf("⚠️  Warning: failed to write config '%s': %v\n", task.Name, err)
+			if err := c.writeJetBrainsRunConfig(config, task.Platform, outputPath); err != nil {
+				// Protected hand-written files are skipped; anything else stops the batch
+				if !errors.Is(err, errNotGenerated) {
+					entry.Outcome, entry.Reason = OutcomeFailed, err.Error()
+					c.Report.Add(entry)
+					c.Report.failUnprocessed(launchTasks[i+1:])
+
+					return &PartialWriteError{Written: written, Failed: outputPath, Err: err}
+				}
+
+				c.logger.Warn("failed to write config", logging.KeyFile, outputPath, logging.KeyTask, task.Name, "error", err)
+
+				entry.Outcome, entry.Reason = OutcomeSkipped, err.Error()
+				c.Report.Add(entry)
+
 				continue
 			}
 
+			written = append(written, outputPath)
+
 			if c.verbose {
-				fmt.Printf("✅ Created: %s\n", outputPath)
+				fmt.Fprintf(c.Out, "✅ Created: %s\n", outputPath)
 			}
 		}
 
+		c.Report.Add(entry)
+
 		convertedCount++
 	}
 
-	fmt.Printf("✅ Successfully converted %d/%d VSCode launch configurations\n", convertedCount, len(launchTasks))
+	if dryRun {
+		preview.summary()
+	}
+
+	fmt.Fprintf(c.Out, "✅ Successfully converted %d/%d VSCode launch configurations\n", convertedCount, len(launchTasks))
 
 	return nil
 }
 
-// convertSingleLaunchConfig converts a single VSCode launch config to JetBrains format
-func (c *VSCodeLaunchToJetBrainsConverter) convertSingleLaunchConfig(task *config.Task) (*JetBrainsRunConfiguration, error) {
+// linkCompoundMembers lists the converted members of a launch.json compound as toRun entries
+// and returns the members that were dropped because they were not converted
+func (c *VSCodeLaunchToJetBrainsConverter) linkCompoundMembers(task *config.Task, jetbrainsConfig *JetBrainsRunConfiguration, converted map[string]*JetBrainsRunConfiguration) []string {
+	var dropped []string
+
+	for _, name := range task.DependsOn {
+		member, ok := converted[name]
+		if !ok {
+			c.logger.Warn("compound member was not converted; dropping it", logging.KeyTask, task.Name, "member", name)
+
+			dropped = append(dropped, name)
+
+			continue
+		}
+
+		jetbrainsConfig.ToRun = append(jetbrainsConfig.ToRun, JetBrainsToRun{Name: member.Name, Type: member.Type})
+	}
+
+	return dropped
+}
+
+// convertSingleLaunchConfig converts a single VSCode launch config to JetBrains format, recording
+// the fields it carries over in entry
+func (c *VSCodeLaunchToJetBrainsConverter) convertSingleLaunchConfig(task *config.Task, entry *ReportEntry) (*JetBrainsRunConfiguration, error) {
+	if task.IsCompound() {
+		entry.use("dependsOn")
+
+		return &JetBrainsRunConfiguration{
+			Name:   task.Name,
+			Type:   CompoundConfigurationType,
+			Folder: config.TagAnnotation(task),
+		}, nil
+	}
+
 	// Determine JetBrains configuration type based on VSCode launch type
 	configType, err := c.determineJetBrainsConfigType(task)
 	if err != nil {
@@ -116,6 +233,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) convertSingleLaunchConfig(task *confi
 	config := &JetBrainsRunConfiguration{
 		Name:    task.Name,
 		Type:    configType,
+		Folder:  config.TagAnnotation(task),
 		Options: make([]JetBrainsOption, 0),
 		EnvVars: nil,
 	}
@@ -125,6 +243,8 @@ func (c *VSCodeLaunchToJetBrainsConverter) convertSingleLaunchConfig(task *confi
 		return nil, err
 	}
 
+	entry.use("args", "cwd", "env")
+
 	// Set working directory (convert VSCode variables)
 	workingDir := task.Cwd
 	if workingDir == "" {
@@ -223,12 +343,26 @@ func (c *VSCodeLaunchToJetBrainsConverter) addJavaApplicationOptions(task *confi
 		Value: mainClass,
 	})
 
-	// Add program parameters (excluding main class)
+	// Args before the main class are JVM options (the launch configuration's vmArgs)
+	var vmArgs []string
+
 	args := c.filterArgsExcluding(task.Args, mainClass)
+	if index := slices.Index(task.Args, mainClass); index >= 0 {
+		vmArgs, args = task.Args[:index], task.Args[index+1:]
+	}
+
+	if len(vmArgs) > 0 {
+		config.Options = append(config.Options, JetBrainsOption{
+			Name:  "VM_PARAMETERS",
+			Value: shellwords.Join(vmArgs),
+		})
+	}
+
+	// Add program parameters (excluding main class)
 	if len(args) > 0 {
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "PROGRAM_PARAMETERS",
-			Value: strings.Join(args, " "),
+			Value: shellwords.Join(args),
 		})
 	}
 
@@ -259,7 +393,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) addGoApplicationOptions(task *config.
 	if len(args) > 0 {
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "PROGRAM_PARAMETERS",
-			Value: strings.Join(args, " "),
+			Value: shellwords.Join(args),
 		})
 	}
 
@@ -283,7 +417,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) addNodeJSOptions(task *config.Task, c
 	if len(task.Args) > 0 {
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "APPLICATION_PARAMETERS",
-			Value: strings.Join(task.Args, " "),
+			Value: shellwords.Join(task.Args),
 		})
 	}
 
@@ -304,7 +438,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) addPythonOptions(task *config.Task, c
 		// The parameters should include the full module execution
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "PARAMETERS",
-			Value: strings.Join(task.Args, " "),
+			Value: shellwords.Join(task.Args),
 		})
 
 		return nil
@@ -325,7 +459,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) addPythonOptions(task *config.Task, c
 	if len(task.Args) > 0 {
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "PARAMETERS",
-			Value: strings.Join(task.Args, " "),
+			Value: shellwords.Join(task.Args),
 		})
 	}
 
@@ -334,11 +468,11 @@ func (c *VSCodeLaunchToJetBrainsConverter) addPythonOptions(task *config.Task, c
 
 // addGenericOptions adds generic executable options
 func (c *VSCodeLaunchToJetBrainsConverter) addGenericOptions(task *config.Task, config *JetBrainsRunConfiguration) error {
-	// Use command as the executable
+	// Use command as the executable, quoted as one argument since it may be a path with spaces
 	if task.Command != "" {
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "PROGRAM_PARAMETERS",
-			Value: task.Command,
+			Value: shellwords.Join([]string{task.Command}),
 		})
 	}
 
@@ -349,7 +483,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) addGenericOptions(task *config.Task,
 		for i, opt := range config.Options {
 			if opt.Name == "PROGRAM_PARAMETERS" {
 				existing = opt.Value
-				config.Options[i].Value = existing + " " + strings.Join(task.Args, " ")
+				config.Options[i].Value = existing + " " + shellwords.Join(task.Args)
 
 				return nil
 			}
@@ -357,7 +491,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) addGenericOptions(task *config.Task,
 
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "PROGRAM_PARAMETERS",
-			Value: strings.Join(task.Args, " "),
+			Value: shellwords.Join(task.Args),
 		})
 	}
 
@@ -369,7 +503,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) extractMainClassFromLaunch(task *conf
 	// Check if mainClass is specified in command (common pattern)
 	if strings.Contains(task.Command, "mainClass") {
 		// Parse command that might contain "mainClass": "com.example.Main"
-		parts := strings.Fields(task.Command)
+		parts := shellwords.Split(task.Command)
 		for i, part := range parts {
 			if part == "mainClass" && i+1 < len(parts) {
 				return strings.Trim(parts[i+1], `"`)
@@ -378,15 +512,19 @@ func (c *VSCodeLaunchToJetBrainsConverter) extractMainClassFromLaunch(task *conf
 	}
 
 	// Look for class-like names in command
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 	for _, part := range parts {
 		if strings.Contains(part, ".") && !strings.HasPrefix(part, "-") && !strings.HasSuffix(part, ".jar") {
 			return part
 		}
 	}
 
-	// Look in args
-	for _, arg := range task.Args {
+	// Look in args, skipping the class path that may follow a JVM option
+	for i, arg := range task.Args {
+		if i > 0 && isJavaClassPathOption(task.Args[i-1]) {
+			continue
+		}
+
 		if strings.Contains(arg, ".") && !strings.HasPrefix(arg, "-") && !strings.HasSuffix(arg, ".jar") {
 			return arg
 		}
@@ -400,7 +538,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) extractProgramFromLaunch(task *config
 	// Check if program is specified in command
 	if strings.Contains(task.Command, "program") {
 		// Parse command that might contain "program": "/path/to/file"
-		parts := strings.Fields(task.Command)
+		parts := shellwords.Split(task.Command)
 		for i, part := range parts {
 			if part == "program" && i+1 < len(parts) {
 				return strings.Trim(parts[i+1], `"`)
@@ -409,7 +547,7 @@ func (c *VSCodeLaunchToJetBrainsConverter) extractProgramFromLaunch(task *config
 	}
 
 	// Look for file paths in command
-	parts := strings.Fields(task.Command)
+	parts := shellwords.Split(task.Command)
 	for _, part := range parts {
 		if strings.Contains(part, "/") || strings.Contains(part, "\\") ||
 			strings.HasSuffix(part, ".js") || strings.HasSuffix(part, ".ts") ||
@@ -443,19 +581,9 @@ func (c *VSCodeLaunchToJetBrainsConverter) filterArgsExcluding(args []string, ex
 	return filtered
 }
 
-// convertVSCodeVariables converts VSCode variables to JetBrains format (reuse from vscode_to_jetbrains.go)
+// convertVSCodeVariables converts VSCode variables to JetBrains format
 func (c *VSCodeLaunchToJetBrainsConverter) convertVSCodeVariables(input string) string {
-	result := input
-
-	// Convert VSCode variables to JetBrains equivalents
-	result = strings.ReplaceAll(result, "${workspaceFolder}", "$PROJECT_DIR$")
-	result = strings.ReplaceAll(result, "${workspaceRoot}", "$PROJECT_DIR$")
-	result = strings.ReplaceAll(result, "${fileDirname}", "$FileDir$")
-	result = strings.ReplaceAll(result, "${fileBasename}", "$FileName$")
-	result = strings.ReplaceAll(result, "${file}", "$FilePath$")
-	result = strings.ReplaceAll(result, "${relativeFile}", "$FilePathRelativeToProjectRoot$")
-
-	return result
+	return ConvertVSCodeVariables(input)
 }
 
 // sanitizeFilename removes invalid characters from filename (reuse from vscode_to_jetbrains.go)
@@ -472,25 +600,20 @@ func (c *VSCodeLaunchToJetBrainsConverter) sanitizeFilename(name string) string
 	return result
 }
 
-// writeJetBrainsRunConfig writes the JetBrains run configuration XML (reuse from vscode_to_jetbrains.go)
-func (c *VSCodeLaunchToJetBrainsConverter) writeJetBrainsRunConfig(config *JetBrainsRunConfiguration, outputPath string) error {
-	// Create the XML structure
-	component := JetBrainsComponent{
-		Name:          "ProjectRunConfigurationManager",
-		Configuration: *config,
-	}
-
-	// Marshal to XML with proper formatting
-	xmlData, err := xml.MarshalIndent(component, "", "  ")
+// writeJetBrainsRunConfig writes the JetBrains run configuration XML (reuse from vscode_to_jetbrains.go).
+// A non-empty platform names the launch.json platform block the values were taken from.
+func (c *VSCodeLaunchToJetBrainsConverter) writeJetBrainsRunConfig(config *JetBrainsRunConfiguration, platform string, outputPath string) error {
+	xmlContent, err := renderRunConfiguration(config, platform)
 	if err != nil {
-		return fmt.Errorf("failed to marshal XML: %w", err)
+		return err
 	}
 
-	// Add XML declaration
-	xmlContent := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + string(xmlData)
+	if err := c.Guard.Check(c.Context, outputPath); err != nil {
+		return err
+	}
 
 	// Write to file
-	if err := os.WriteFile(outputPath, []byte(xmlContent), 0644); err != nil {
+	if err := WriteFileAtomic(outputPath, xmlContent, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
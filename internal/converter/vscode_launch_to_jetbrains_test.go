@@ -2,12 +2,17 @@ package converter
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/golden"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+	"github.com/syndbg/taskporter/internal/shellwords"
 
 	"github.com/stretchr/testify/require"
 )
@@ -27,8 +32,8 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 		require.Equal(t, config.TypeVSCodeLaunch, launchTask.Type)
 
 		// Convert to JetBrains
-		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false)
-		jetbrainsConfig, err := converter.convertSingleLaunchConfig(launchTask)
+		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
+		jetbrainsConfig, err := converter.convertSingleLaunchConfig(launchTask, &ReportEntry{})
 		require.NoError(t, err)
 
 		// Verify Go-specific configuration
@@ -67,7 +72,52 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 		require.Len(t, jetbrainsConfig.EnvVars.EnvVars, 2)
 
 		// Verify against golden file for exact output
-		verifyJetBrainsConfigGolden(t, jetbrainsConfig, "go_launch_to_jetbrains_expected.json")
+		verifyJetBrainsConfigGolden(t, jetbrainsConfig, "go_launch_to_jetbrains_expected.xml")
+	})
+
+	t.Run("arguments with spaces are quoted", func(t *testing.T) {
+		task := &config.Task{
+			Name:        "Spaced Args",
+			Type:        config.TypeVSCodeLaunch,
+			Command:     "node",
+			Args:        []string{"server.js", "--name", "My App", `--greeting=say "hi"`},
+			Description: "node launch configuration",
+		}
+
+		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
+		jetbrainsConfig, err := converter.convertSingleLaunchConfig(task, &ReportEntry{})
+		require.NoError(t, err)
+
+		for _, option := range jetbrainsConfig.Options {
+			if option.Name == "APPLICATION_PARAMETERS" {
+				require.Equal(t, `server.js --name "My App" "--greeting=say \"hi\""`, option.Value)
+				require.Equal(t, task.Args, shellwords.Split(option.Value))
+			}
+		}
+	})
+
+	t.Run("executables under paths with spaces stay one parameter", func(t *testing.T) {
+		program := "/Users/me/My Projects/app (fork)/bin/server"
+		task := &config.Task{
+			Name:    "Native",
+			Type:    config.TypeVSCodeLaunch,
+			Command: program,
+			Args:    []string{"--port", "8080"},
+		}
+
+		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
+		jetbrainsConfig := &JetBrainsRunConfiguration{Name: task.Name}
+		require.NoError(t, converter.addGenericOptions(task, jetbrainsConfig))
+
+		var parameters string
+
+		for _, option := range jetbrainsConfig.Options {
+			if option.Name == "PROGRAM_PARAMETERS" {
+				parameters = option.Value
+			}
+		}
+
+		require.Equal(t, []string{program, "--port", "8080"}, shellwords.Split(parameters))
 	})
 
 	t.Run("Java launch configuration", func(t *testing.T) {
@@ -83,8 +133,8 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 		require.Equal(t, "Launch Java App", launchTask.Name)
 
 		// Convert to JetBrains
-		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false)
-		jetbrainsConfig, err := converter.convertSingleLaunchConfig(launchTask)
+		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
+		jetbrainsConfig, err := converter.convertSingleLaunchConfig(launchTask, &ReportEntry{})
 		require.NoError(t, err)
 
 		// Verify Java-specific configuration
@@ -93,6 +143,7 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 
 		// Check Java-specific options
 		hasMainClass := false
+		hasVMParams := false
 		hasProgramParams := false
 
 		for _, option := range jetbrainsConfig.Options {
@@ -101,6 +152,10 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 				hasMainClass = true
 
 				require.Equal(t, "com.example.Application", option.Value)
+			case "VM_PARAMETERS":
+				hasVMParams = true
+
+				require.Equal(t, "-Xmx1024m -Dfile.encoding=UTF-8", option.Value)
 			case "PROGRAM_PARAMETERS":
 				hasProgramParams = true
 
@@ -109,10 +164,11 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 		}
 
 		require.True(t, hasMainClass, "Should have MAIN_CLASS_NAME option")
+		require.True(t, hasVMParams, "Should have VM_PARAMETERS option")
 		require.True(t, hasProgramParams, "Should have PROGRAM_PARAMETERS option")
 
 		// Verify against golden file for exact output
-		verifyJetBrainsConfigGolden(t, jetbrainsConfig, "java_launch_to_jetbrains_expected.json")
+		verifyJetBrainsConfigGolden(t, jetbrainsConfig, "java_launch_to_jetbrains_expected.xml")
 	})
 
 	t.Run("Node.js launch configuration", func(t *testing.T) {
@@ -128,8 +184,8 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 		require.Equal(t, "Launch Node.js App", launchTask.Name)
 
 		// Convert to JetBrains
-		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false)
-		jetbrainsConfig, err := converter.convertSingleLaunchConfig(launchTask)
+		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
+		jetbrainsConfig, err := converter.convertSingleLaunchConfig(launchTask, &ReportEntry{})
 		require.NoError(t, err)
 
 		// Verify Node.js-specific configuration
@@ -157,7 +213,7 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 		require.True(t, hasAppParams, "Should have APPLICATION_PARAMETERS option")
 
 		// Verify against golden file for exact output
-		verifyJetBrainsConfigGolden(t, jetbrainsConfig, "nodejs_launch_to_jetbrains_expected.json")
+		verifyJetBrainsConfigGolden(t, jetbrainsConfig, "nodejs_launch_to_jetbrains_expected.xml")
 	})
 
 	t.Run("Python launch configuration", func(t *testing.T) {
@@ -173,8 +229,8 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 		require.Equal(t, "Launch Python App", launchTask.Name)
 
 		// Convert to JetBrains
-		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false)
-		jetbrainsConfig, err := converter.convertSingleLaunchConfig(launchTask)
+		converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
+		jetbrainsConfig, err := converter.convertSingleLaunchConfig(launchTask, &ReportEntry{})
 		require.NoError(t, err)
 
 		// Verify Python-specific configuration
@@ -203,12 +259,29 @@ func TestVSCodeLaunchToJetBrainsConverter_ConvertLaunchConfigs(t *testing.T) {
 		require.True(t, hasParams, "Should have PARAMETERS option")
 
 		// Verify against golden file for exact output
-		verifyJetBrainsConfigGolden(t, jetbrainsConfig, "python_launch_to_jetbrains_expected.json")
+		verifyJetBrainsConfigGolden(t, jetbrainsConfig, "python_launch_to_jetbrains_expected.xml")
 	})
 }
 
+func TestVSCodeLaunchToJetBrainsConverter_Compounds(t *testing.T) {
+	tasks, err := vscode.NewLaunchParser("/test/project", nil).ParseLaunchConfigs(filepath.Join("testdata", "vscode-launch-compound.json"))
+	require.NoError(t, err)
+
+	outputDir := t.TempDir()
+	converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", outputDir, false, nil)
+	require.NoError(t, converter.ConvertLaunchConfigs(tasks, false))
+
+	compound := readJetBrainsConfig(t, filepath.Join(outputDir, "Full_Stack.xml"))
+	require.Equal(t, CompoundConfigurationType, compound.Type)
+	require.Empty(t, compound.Options)
+	require.Equal(t, []JetBrainsToRun{
+		{XMLName: xml.Name{Local: "toRun"}, Name: "Backend", Type: "GoApplicationRunConfiguration"},
+		{XMLName: xml.Name{Local: "toRun"}, Name: "Frontend", Type: "NodeJSConfigurationType"},
+	}, compound.ToRun)
+}
+
 func TestVSCodeLaunchToJetBrainsConverter_LanguageDetection(t *testing.T) {
-	converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false)
+	converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
 
 	testCases := []struct {
 		name         string
@@ -272,7 +345,7 @@ func TestVSCodeLaunchToJetBrainsConverter_LanguageDetection(t *testing.T) {
 }
 
 func TestVSCodeLaunchToJetBrainsConverter_ArgumentExtraction(t *testing.T) {
-	converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false)
+	converter := NewVSCodeLaunchToJetBrainsConverter("/test/project", "", false, nil)
 
 	t.Run("extractGoPackageFromLaunch", func(t *testing.T) {
 		testCases := []struct {
@@ -400,7 +473,8 @@ func parseVSCodeLaunchDataToTasks(t *testing.T, launchFile map[string]interface{
 			command = "java"
 
 			if mainClass != "" {
-				args = append([]string{mainClass}, args...)
+				vmArgs, _ := configMap["vmArgs"].(string)
+				args = append(append(strings.Fields(vmArgs), mainClass), args...)
 			}
 		case "node":
 			command = "node"
@@ -439,76 +513,22 @@ func parseVSCodeLaunchDataToTasks(t *testing.T, launchFile map[string]interface{
 
 // Golden file testing helpers
 
-// verifyJetBrainsConfigGolden verifies a JetBrains configuration against a golden file
+// verifyJetBrainsConfigGolden verifies the run configuration file a JetBrains configuration is written as against a golden file
 func verifyJetBrainsConfigGolden(t *testing.T, config *JetBrainsRunConfiguration, goldenFileName string) {
 	t.Helper()
 
-	goldenPath := filepath.Join("testdata", "golden", goldenFileName)
-
-	// Create directory if it doesn't exist
-	goldenDir := filepath.Dir(goldenPath)
-	if err := os.MkdirAll(goldenDir, 0755); err != nil {
-		t.Fatalf("Failed to create golden directory: %v", err)
-	}
-
-	// Marshal the config to JSON for comparison (easier than XML)
-	actualData, err := json.MarshalIndent(config, "", "  ")
-	require.NoError(t, err, "Failed to marshal JetBrains config")
-
-	updateGolden := os.Getenv("UPDATE_GOLDEN") == "true"
-
-	if updateGolden {
-		// Update the golden file
-		err = os.WriteFile(goldenPath, actualData, 0644)
-		require.NoError(t, err, "Failed to write golden file: %s", goldenPath)
-		t.Logf("Updated golden file: %s", goldenPath)
-	} else {
-		// Compare with existing golden file
-		expectedData, err := os.ReadFile(goldenPath)
-		if err != nil {
-			t.Logf("Golden file not found: %s. Run with UPDATE_GOLDEN=true to create it.", goldenPath)
-			t.Logf("Actual config:\n%s", string(actualData))
-			t.FailNow()
-		}
+	content, err := renderRunConfiguration(config, "")
+	require.NoError(t, err, "Failed to render JetBrains config")
 
-		require.JSONEq(t, string(expectedData), string(actualData),
-			"JetBrains config doesn't match golden file: %s", goldenPath)
-	}
+	golden.Assert(t, content, goldenFileName)
 }
 
 // verifyVSCodeLaunchConfigGolden verifies a VSCode launch configuration against a golden file
 func verifyVSCodeLaunchConfigGolden(t *testing.T, config *VSCodeLaunchConfig, goldenFileName string) {
 	t.Helper()
 
-	goldenPath := filepath.Join("testdata", "golden", goldenFileName)
-
-	// Create directory if it doesn't exist
-	goldenDir := filepath.Dir(goldenPath)
-	if err := os.MkdirAll(goldenDir, 0755); err != nil {
-		t.Fatalf("Failed to create golden directory: %v", err)
-	}
-
-	// Marshal the config to JSON
-	actualData, err := json.MarshalIndent(config, "", "  ")
+	actualData, err := json.Marshal(config)
 	require.NoError(t, err, "Failed to marshal VSCode launch config")
 
-	updateGolden := os.Getenv("UPDATE_GOLDEN") == "true"
-
-	if updateGolden {
-		// Update the golden file
-		err = os.WriteFile(goldenPath, actualData, 0644)
-		require.NoError(t, err, "Failed to write golden file: %s", goldenPath)
-		t.Logf("Updated golden file: %s", goldenPath)
-	} else {
-		// Compare with existing golden file
-		expectedData, err := os.ReadFile(goldenPath)
-		if err != nil {
-			t.Logf("Golden file not found: %s. Run with UPDATE_GOLDEN=true to create it.", goldenPath)
-			t.Logf("Actual config:\n%s", string(actualData))
-			t.FailNow()
-		}
-
-		require.JSONEq(t, string(expectedData), string(actualData),
-			"VSCode launch config doesn't match golden file: %s", goldenPath)
-	}
+	golden.Assert(t, actualData, goldenFileName)
 }
@@ -0,0 +1,112 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+)
+
+// VSCodeTasksModernizer rewrites legacy version 0.1.0 tasks.json files in the 2.0.0 schema
+type VSCodeTasksModernizer struct {
+	WriteOptions
+	projectRoot string
+	outputPath  string
+	verbose     bool
+	logger      *slog.Logger
+}
+
+// NewVSCodeTasksModernizer creates a new tasks.json modernizer
+func NewVSCodeTasksModernizer(projectRoot, outputPath string, verbose bool, logger *slog.Logger) *VSCodeTasksModernizer {
+	return &VSCodeTasksModernizer{
+		projectRoot:  projectRoot,
+		outputPath:   outputPath,
+		verbose:      verbose,
+		WriteOptions: defaultWriteOptions(),
+		logger:       logging.OrDiscard(logger).With(logging.KeyComponent, "vscode-tasks-modernizer"),
+	}
+}
+
+// Modernize converts the tasks file at tasksPath, writing it back in place unless an output path was given
+func (c *VSCodeTasksModernizer) Modernize(tasksPath string, dryRun bool) (err error) {
+	tasksFile, legacy, err := vscode.ReadTasksFile(c.Context, tasksPath)
+	if err != nil {
+		return err
+	}
+
+	if !legacy {
+		fmt.Fprintf(c.Out, "✅ %s already uses the %s schema, nothing to modernize\n", tasksPath, tasksFile.Version)
+
+		for _, task := range tasksFile.Tasks {
+			c.Report.Add(ReportEntry{
+				SourceFile: tasksPath,
+				SourceName: task.Label,
+				Outcome:    OutcomeSkipped,
+				Reason:     fmt.Sprintf("already uses the %s schema", tasksFile.Version),
+			})
+		}
+
+		return nil
+	}
+
+	if c.verbose {
+		fmt.Fprintf(c.Out, "🔄 Modernizing %d tasks from version %s to %s...\n", len(tasksFile.Tasks), vscode.LegacyTasksVersion, tasksFile.Version)
+	}
+
+	// Default to rewriting the file that was read
+	outputPath, err := ResolveFileOutput(c.outputPath, tasksPath)
+	if err != nil {
+		return err
+	}
+
+	if c.verbose {
+		fmt.Fprintf(c.Out, "📁 Output file: %s\n", outputPath)
+	}
+
+	if !dryRun {
+		if err := ensureWritableDir(filepath.Dir(outputPath)); err != nil {
+			return err
+		}
+	}
+
+	// The tasks are rewritten as a whole, so they all fail together
+	entries := make([]ReportEntry, 0, len(tasksFile.Tasks))
+	for _, task := range tasksFile.Tasks {
+		entries = append(entries, ReportEntry{
+			SourceFile: tasksPath,
+			SourceName: task.Label,
+			TargetFile: outputPath,
+			TargetName: task.Label,
+			TargetType: task.Type,
+			Outcome:    OutcomeConverted,
+		})
+	}
+
+	defer func() { c.Report.Add(failEntries(entries, err)...) }()
+
+	jsonData, err := json.MarshalIndent(tasksFile, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tasks.json: %w", err)
+	}
+
+	if dryRun {
+		previewFile(c.Context, c.Out, "tasks.json", outputPath, withJSONProvenance(jsonData), c.FullPreview)
+	} else {
+		if err := c.Guard.Check(c.Context, outputPath); err != nil {
+			return err
+		}
+
+		if err := WriteFileAtomic(outputPath, withJSONProvenance(jsonData), 0644); err != nil {
+			return fmt.Errorf("failed to write tasks.json: %w", err)
+		}
+
+		c.logger.Debug("modernized tasks file", logging.KeyFile, outputPath, "tasks", len(tasksFile.Tasks))
+	}
+
+	fmt.Fprintf(c.Out, "✅ Successfully modernized %d VSCode tasks to version %s\n", len(tasksFile.Tasks), tasksFile.Version)
+
+	return nil
+}
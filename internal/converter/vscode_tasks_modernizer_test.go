@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVSCodeTasksModernizer(t *testing.T) {
+	legacyTasks := `{
+    "version": "0.1.0",
+    "command": "npm",
+    "isShellCommand": true,
+    "args": ["run"],
+    "tasks": [
+        {"taskName": "build", "isBuildCommand": true, "args": ["--", "--production"]}
+    ]
+}`
+
+	writeLegacyTasks := func(t *testing.T) string {
+		tasksPath := filepath.Join(t.TempDir(), ".vscode", "tasks.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(tasksPath), 0o755))
+		require.NoError(t, os.WriteFile(tasksPath, []byte(legacyTasks), 0o600))
+
+		return tasksPath
+	}
+
+	t.Run("should rewrite the file in place in the 2.0.0 schema", func(t *testing.T) {
+		tasksPath := writeLegacyTasks(t)
+
+		modernizer := NewVSCodeTasksModernizer("/test/project", "", false, nil)
+		modernizer.SetOverwriteGuard(&OverwriteGuard{Force: true})
+		require.NoError(t, modernizer.Modernize(tasksPath, false))
+
+		data, err := os.ReadFile(tasksPath)
+		require.NoError(t, err)
+		require.Contains(t, string(data), ProvenanceMarker)
+
+		taskFile, legacy, err := vscode.ReadTasksFile(context.Background(), tasksPath)
+		require.NoError(t, err)
+		require.False(t, legacy)
+		require.Equal(t, "2.0.0", taskFile.Version)
+		require.Len(t, taskFile.Tasks, 1)
+		require.Equal(t, "build", taskFile.Tasks[0].Label)
+		require.Equal(t, "shell", taskFile.Tasks[0].Type)
+		require.Equal(t, "npm", taskFile.Tasks[0].Command.Value())
+		require.Equal(t, []string{"run", "build", "--", "--production"}, taskFile.Tasks[0].Args.Values())
+		require.Equal(t, "build", taskFile.Tasks[0].Group)
+	})
+
+	t.Run("should refuse to replace the hand-written file without force", func(t *testing.T) {
+		tasksPath := writeLegacyTasks(t)
+
+		modernizer := NewVSCodeTasksModernizer("/test/project", "", false, nil)
+		require.ErrorIs(t, modernizer.Modernize(tasksPath, false), errNotGenerated)
+	})
+
+	t.Run("should write to a separate output without touching the original", func(t *testing.T) {
+		tasksPath := writeLegacyTasks(t)
+		outputPath := filepath.Join(t.TempDir(), "tasks.json")
+
+		modernizer := NewVSCodeTasksModernizer("/test/project", outputPath, false, nil)
+		require.NoError(t, modernizer.Modernize(tasksPath, false))
+		require.FileExists(t, outputPath)
+
+		data, err := os.ReadFile(tasksPath)
+		require.NoError(t, err)
+		require.Equal(t, legacyTasks, string(data))
+	})
+
+	t.Run("should leave current files alone", func(t *testing.T) {
+		tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+		current := `{"version": "2.0.0", "tasks": []}`
+		require.NoError(t, os.WriteFile(tasksPath, []byte(current), 0o600))
+
+		modernizer := NewVSCodeTasksModernizer("/test/project", "", false, nil)
+		require.NoError(t, modernizer.Modernize(tasksPath, false))
+
+		data, err := os.ReadFile(tasksPath)
+		require.NoError(t, err)
+		require.Equal(t, current, string(data))
+	})
+}
@@ -2,112 +2,240 @@ package converter
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
-	"os"
+	"log/slog"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/runner"
+	"github.com/syndbg/taskporter/internal/shellwords"
 )
 
 // VSCodeToJetBrainsConverter converts VSCode tasks to JetBrains run configurations
 type VSCodeToJetBrainsConverter struct {
+	WriteOptions
 	projectRoot string
 	outputPath  string
 	verbose     bool
+	templates   config.RunConfigTemplates
+	logger      *slog.Logger
 }
 
 // NewVSCodeToJetBrainsConverter creates a new converter
-func NewVSCodeToJetBrainsConverter(projectRoot, outputPath string, verbose bool) *VSCodeToJetBrainsConverter {
+func NewVSCodeToJetBrainsConverter(projectRoot, outputPath string, verbose bool, logger *slog.Logger) *VSCodeToJetBrainsConverter {
 	return &VSCodeToJetBrainsConverter{
-		projectRoot: projectRoot,
-		outputPath:  outputPath,
-		verbose:     verbose,
+		projectRoot:  projectRoot,
+		outputPath:   outputPath,
+		verbose:      verbose,
+		WriteOptions: defaultWriteOptions(),
+		logger:       logging.OrDiscard(logger).With(logging.KeyComponent, "vscode-to-jetbrains"),
 	}
 }
 
+// SetTemplates layers the project's run configuration templates beneath the generated
+// configurations, so they get the same defaults as configurations created in the IDE
+func (c *VSCodeToJetBrainsConverter) SetTemplates(templates config.RunConfigTemplates) {
+	c.templates = templates
+}
+
 // ConvertTasks converts VSCode tasks to JetBrains run configurations
 func (c *VSCodeToJetBrainsConverter) ConvertTasks(tasks []*config.Task, dryRun bool) error {
 	if c.verbose {
-		fmt.Printf("🔄 Converting %d VSCode tasks to JetBrains format...\n", len(tasks))
+		fmt.Fprintf(c.Out, "🔄 Converting %d VSCode tasks to JetBrains format...\n", len(tasks))
 	}
 
-	// Determine output directory
-	outputDir := c.outputPath
-	if outputDir == "" {
-		outputDir = filepath.Join(c.projectRoot, ".idea", "runConfigurations")
+	// Determine output directory, the top one when --output is a template
+	files, outputDir, err := resolveRunConfigOutput(c.Context, c.outputPath, c.projectRoot)
+	if err != nil {
+		return err
 	}
 
 	if c.verbose {
-		fmt.Printf("📁 Output directory: %s\n", outputDir)
+		fmt.Fprintf(c.Out, "📁 Output directory: %s\n", outputDir)
 	}
 
-	// Create output directory if not in dry-run mode
 	if !dryRun {
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+		if err := ensureWritableDir(outputDir); err != nil {
+			return err
 		}
 	}
 
-	convertedCount := 0
+	// Only convert VSCode tasks (not launch configs)
+	vscodeTasks := make([]*config.Task, 0, len(tasks))
 
 	for _, task := range tasks {
-		// Only convert VSCode tasks (not launch configs)
 		if !strings.HasPrefix(string(task.Type), "vscode-task") {
 			if c.verbose {
-				fmt.Printf("⏭️  Skipping non-VSCode task: %s (type: %s)\n", task.Name, string(task.Type))
+				fmt.Fprintf(c.Out, "⏭️  Skipping non-VSCode task: %s (type: %s)\n", task.Name, string(task.Type))
 			}
 
+			c.Report.Skip(task, fmt.Sprintf("not a VSCode task (type %s)", task.Type))
+
 			continue
 		}
 
-		jetbrainsConfig, err := c.convertSingleTask(task)
+		vscodeTasks = append(vscodeTasks, task)
+	}
+
+	// Converted configurations by task name, so dependents can reference them
+	converted := make(map[string]*JetBrainsRunConfiguration)
+	convertedCount := 0
+
+	var written []string
+
+	// Dependencies are converted before the tasks that reference them
+	sorted := sortByDependencies(vscodeTasks, c.logger)
+	preview := newDirPreview(c.Context, c.Out, c.FullPreview)
+
+	for i, task := range sorted {
+		entry := newReportEntry(task, OutcomeConverted)
+
+		jetbrainsConfig, err := c.convertSingleTask(task, &entry)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: failed to convert task '%s': %v\n", task.Name, err)
+			c.logger.Warn("failed to convert task", logging.KeyTask, task.Name, "error", err)
+
+			entry.Outcome, entry.Reason = OutcomeFailed, err.Error()
+			c.Report.Add(entry)
+
 			continue
 		}
 
-		// Generate filename (sanitize name for filesystem)
-		filename := sanitizeFilename(task.Name) + ".xml"
+		refs := translateRunConfigurationEnvRefs(jetbrainsConfig)
+		entry.approximate(refs.fields...)
+
+		for _, warning := range refs.warnings {
+			c.logger.Warn("kept untranslatable env reference", logging.KeyTask, task.Name, "warning", warning)
+
+			entry.Warnings = append(entry.Warnings, warning)
+		}
+
+		if applyTemplate(c.templates, jetbrainsConfig) {
+			c.logger.Debug("applied run configuration template", logging.KeyTask, task.Name, "type", jetbrainsConfig.Type, logging.KeyFile, c.templates[jetbrainsConfig.Type].Source)
+		}
+
+		entry.use("dependsOn", "dependsOrder")
+
+		for _, name := range c.linkDependencies(task, jetbrainsConfig, converted) {
+			entry.Warnings = append(entry.Warnings, fmt.Sprintf("dependency %q was not converted and was dropped", name))
+			entry.drop("dependsOn")
+		}
+
+		entry.dropUnused(task)
+
+		// Generate filename (sanitize name for filesystem), shown in the folder the --output template picks
+		folder, sanitized := files.place(task)
+		jetbrainsConfig.addFolder(folder)
+
+		filename, renamed := files.allocate(jetbrainsConfig.Name, sanitized)
 		filepath := filepath.Join(outputDir, filename)
 
+		if renamed {
+			c.logger.Warn("run configuration file name is already taken", logging.KeyTask, task.Name, logging.KeyFile, sanitized+".xml", "writing", filename)
+
+			entry.Warnings = append(entry.Warnings, fmt.Sprintf("%s.xml is already taken", sanitized))
+		}
+
+		entry.TargetFile, entry.TargetName, entry.TargetType = filepath, jetbrainsConfig.Name, jetbrainsConfig.Type
+
 		if c.verbose {
-			fmt.Printf("📝 Converting task: %s → %s\n", task.Name, filename)
+			fmt.Fprintf(c.Out, "📝 Converting task: %s → %s\n", task.Name, filename)
 		}
 
 		if dryRun {
-			fmt.Printf("   [DRY RUN] Would create: %s\n", filepath)
+			content, err := renderRunConfiguration(jetbrainsConfig, "")
+			if err != nil {
+				return err
+			}
+
+			preview.file(filepath, content)
 		} else {
 			if err := c.writeJetBrainsConfig(jetbrainsConfig, filepath); err != nil {
-				fmt.Printf("⚠️  Warning: failed to write config for '%s': %v\n", task.Name, err)
+				// Protected hand-written files are skipped; anything else stops the batch
+				if !errors.Is(err, errNotGenerated) {
+					entry.Outcome, entry.Reason = OutcomeFailed, err.Error()
+					c.Report.Add(entry)
+					c.Report.failUnprocessed(sorted[i+1:])
+
+					return &PartialWriteError{Written: written, Failed: filepath, Err: err}
+				}
+
+				c.logger.Warn("failed to write config", logging.KeyFile, filepath, logging.KeyTask, task.Name, "error", err)
+
+				entry.Outcome, entry.Reason = OutcomeSkipped, err.Error()
+				c.Report.Add(entry)
+
 				continue
 			}
+
+			written = append(written, filepath)
 		}
 
+		c.Report.Add(entry)
+
+		converted[task.Name] = jetbrainsConfig
 		convertedCount++
 	}
 
+	if dryRun {
+		preview.summary()
+	}
+
 	if c.verbose {
-		fmt.Printf("✅ Successfully converted %d/%d tasks\n", convertedCount, len(tasks))
+		fmt.Fprintf(c.Out, "✅ Successfully converted %d/%d tasks\n", convertedCount, len(tasks))
 	}
 
 	return nil
 }
 
-// convertSingleTask converts a single VSCode task to JetBrains format
-func (c *VSCodeToJetBrainsConverter) convertSingleTask(task *config.Task) (*JetBrainsRunConfiguration, error) {
+// convertSingleTask converts a single VSCode task to JetBrains format, recording the fields it
+// carries over in entry
+func (c *VSCodeToJetBrainsConverter) convertSingleTask(task *config.Task, entry *ReportEntry) (*JetBrainsRunConfiguration, error) {
+	// A task that only runs its dependencies in parallel is a compound configuration
+	if task.IsCompound() {
+		return &JetBrainsRunConfiguration{
+			Name:   task.Name,
+			Type:   CompoundConfigurationType,
+			Folder: config.TagAnnotation(task),
+		}, nil
+	}
+
+	// A sequence of dependencies without a command of its own becomes a chain of before-run
+	// tasks (added by linkDependencies) in front of a script that does nothing
+	if task.IsAggregate() {
+		return &JetBrainsRunConfiguration{
+			Name:   task.Name,
+			Type:   ShConfigurationType,
+			Folder: config.TagAnnotation(task),
+			Options: []JetBrainsOption{
+				{Name: "SCRIPT_TEXT", Value: aggregateScriptText},
+				{Name: "EXECUTE_SCRIPT_FILE", Value: "false"},
+				{Name: "WORKING_DIRECTORY", Value: "$PROJECT_DIR$"},
+			},
+		}, nil
+	}
+
+	// A command line of plain words names the tool to match in its first word
+	task = splitCommandWords(task)
+
 	// Determine configuration type based on task
 	configType := c.determineConfigType(task)
 
 	config := &JetBrainsRunConfiguration{
 		Name:    task.Name,
 		Type:    configType,
+		Folder:  config.TagAnnotation(task),
 		Options: make([]JetBrainsOption, 0),
 		EnvVars: nil,
 	}
 
-	// Add options based on task type (type was already determined by determineConfigType)
+	// Add options based on task type (type was already determined by determineConfigType); every
+	// type carries the args
+	entry.use("args")
+
 	switch config.Type {
 	case "Application":
 		mainClass := c.extractMainClass(task)
@@ -119,43 +247,41 @@ func (c *VSCodeToJetBrainsConverter) convertSingleTask(task *config.Task) (*JetB
 		if len(task.Args) > 0 {
 			config.Options = append(config.Options, JetBrainsOption{
 				Name:  "PROGRAM_PARAMETERS",
-				Value: strings.Join(task.Args, " "),
+				Value: shellwords.Join(task.Args),
 			})
 		}
-	case "GradleRunTask":
-		config.Options = append(config.Options, JetBrainsOption{
-			Name:  "TASK_NAME",
-			Value: strings.Join(task.Args, " "),
-		})
+	case GradleConfigurationType:
+		// Gradle configurations keep their project, environment and JVM options in settings of their own
+		config.FactoryName = "Gradle"
+		config.ExternalSystemSettings = c.gradleSettings(task)
 	case "MavenRunConfiguration":
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "GOALS",
-			Value: strings.Join(task.Args, " "),
+			Value: shellwords.Join(task.Args),
 		})
+	case ShConfigurationType:
+		// The command line must reach the shell exactly as written, so it is never re-tokenized
+		config.Options = append(config.Options,
+			JetBrainsOption{Name: "SCRIPT_TEXT", Value: shellCommandLine(task)},
+			JetBrainsOption{Name: "EXECUTE_SCRIPT_FILE", Value: "false"},
+		)
 	default:
 		// Generic shell/external tool configuration or other types
-		scriptText := task.Command
-		if len(task.Args) > 0 {
-			scriptText += " " + strings.Join(task.Args, " ")
-		}
-
 		config.Options = append(config.Options, JetBrainsOption{
 			Name:  "SCRIPT_TEXT",
-			Value: scriptText,
+			Value: shellCommandLine(task),
 		})
 	}
 
-	// Set working directory (convert VSCode variables)
-	workingDir := task.Cwd
-	if workingDir == "" {
-		workingDir = "$PROJECT_DIR$"
-	} else {
-		workingDir = c.convertVSCodeVariables(workingDir)
+	entry.use("cwd", "env")
+
+	if config.ExternalSystemSettings != nil {
+		return config, nil
 	}
 
 	config.Options = append(config.Options, JetBrainsOption{
 		Name:  "WORKING_DIRECTORY",
-		Value: workingDir,
+		Value: c.workingDirectory(task.Cwd),
 	})
 
 	// Convert environment variables
@@ -184,15 +310,128 @@ func (c *VSCodeToJetBrainsConverter) convertSingleTask(task *config.Task) (*JetB
 	return config, nil
 }
 
-// determineConfigType determines the best JetBrains configuration type for a task
+// linkDependencies references the already converted dependencies of task from its configuration.
+// Compound configurations list them as toRun entries; everything else runs them as before-run
+// tasks, which JetBrains executes one after another. That preserves dependsOrder "sequence" and
+// keeps the VSCode guarantee that dependencies finish before the task's own command starts.
+// It returns the dependencies that were dropped because they were not converted.
+func (c *VSCodeToJetBrainsConverter) linkDependencies(task *config.Task, jetbrainsConfig *JetBrainsRunConfiguration, converted map[string]*JetBrainsRunConfiguration) []string {
+	if len(task.DependsOn) == 0 {
+		return nil
+	}
+
+	var (
+		beforeRun []JetBrainsBeforeRunTask
+		dropped   []string
+	)
+
+	for _, name := range task.DependsOn {
+		dependency, ok := converted[name]
+		if !ok {
+			c.logger.Warn("dependency was not converted; dropping it", logging.KeyTask, task.Name, "dependency", name)
+
+			dropped = append(dropped, name)
+
+			continue
+		}
+
+		if jetbrainsConfig.Type == CompoundConfigurationType {
+			jetbrainsConfig.ToRun = append(jetbrainsConfig.ToRun, JetBrainsToRun{
+				Name: dependency.Name,
+				Type: dependency.Type,
+			})
+
+			continue
+		}
+
+		beforeRun = append(beforeRun, JetBrainsBeforeRunTask{
+			Name:                 "RunConfigurationTask",
+			Enabled:              "true",
+			RunConfigurationName: dependency.Name,
+			RunConfigurationType: dependency.Type,
+		})
+	}
+
+	if len(beforeRun) > 0 {
+		jetbrainsConfig.Method = &JetBrainsMethod{
+			Version: "2",
+			Options: beforeRun,
+		}
+	}
+
+	if c.verbose {
+		fmt.Fprintf(c.Out, "🔗 Linked %d dependencies of '%s' (%s)\n", len(task.DependsOn), task.Name, task.DependsOrder)
+	}
+
+	return dropped
+}
+
+// sortByDependencies orders tasks so that every task comes after the tasks it depends on,
+// otherwise keeping the original order. Cycles are reported and broken where they close.
+func sortByDependencies(tasks []*config.Task, logger *slog.Logger) []*config.Task {
+	logger = logging.OrDiscard(logger)
+
+	byName := make(map[string]*config.Task, len(tasks))
+	for _, task := range tasks {
+		if _, exists := byName[task.Name]; !exists {
+			byName[task.Name] = task
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[*config.Task]int, len(tasks))
+	sorted := make([]*config.Task, 0, len(tasks))
+
+	var visit func(task *config.Task)
+	visit = func(task *config.Task) {
+		switch state[task] {
+		case visited:
+			return
+		case visiting:
+			logger.Warn("dependency cycle", logging.KeyTask, task.Name)
+			return
+		}
+
+		state[task] = visiting
+
+		for _, name := range task.DependsOn {
+			if dependency, ok := byName[name]; ok {
+				visit(dependency)
+			}
+		}
+
+		state[task] = visited
+		sorted = append(sorted, task)
+	}
+
+	for _, task := range tasks {
+		visit(task)
+	}
+
+	return sorted
+}
+
+// determineConfigType determines the best JetBrains configuration type for a task.
+// A shell task whose command is a whole command line (`bash -c "a && b"`, `npm test | tee log`)
+// becomes a shell script, since matching tools inside it would split the line apart, and so
+// does any task naming no tool JetBrains has a configuration type for.
 func (c *VSCodeToJetBrainsConverter) determineConfigType(task *config.Task) string {
+	if task.Execution == config.ExecutionShell && strings.ContainsAny(task.Command, shellSyntax) {
+		return ShConfigurationType
+	}
+
 	command := strings.ToLower(task.Command)
 
 	switch {
 	case command == "java":
 		return "Application"
 	case strings.Contains(command, "gradle"):
-		return "GradleRunTask"
+		return GradleConfigurationType
 	case strings.Contains(command, "maven") || strings.Contains(command, "mvn"):
 		return "MavenRunConfiguration"
 	case strings.Contains(command, "npm") || strings.Contains(command, "node"):
@@ -200,10 +439,27 @@ func (c *VSCodeToJetBrainsConverter) determineConfigType(task *config.Task) stri
 	case strings.Contains(command, "python") || strings.Contains(command, "py"):
 		return "PythonConfigurationType"
 	default:
-		return "ShellScript"
+		return ShConfigurationType
 	}
 }
 
+// shellCommandLine returns the command line VSCode hands to the shell for a shell task:
+// the command verbatim, followed by the quoted args. Other tasks are written the same way,
+// except that a process command or a script path is a single word and is quoted as one,
+// so absolute paths under project roots with spaces or parentheses survive.
+func shellCommandLine(task *config.Task) string {
+	command := task.Command
+	if task.Execution == config.ExecutionProcess || runner.CommandIsPath(task) {
+		command = posixQuote(command)
+	}
+
+	if len(task.Args) == 0 {
+		return command
+	}
+
+	return command + " " + posixJoin(task.Args)
+}
+
 // extractMainClass attempts to extract a main class from Java-related tasks
 func (c *VSCodeToJetBrainsConverter) extractMainClass(task *config.Task) string {
 	// Look for main class in args
@@ -228,45 +484,35 @@ func (c *VSCodeToJetBrainsConverter) extractMainClass(task *config.Task) string
 	return "Main"
 }
 
-// convertVSCodeVariables converts VSCode variables to JetBrains equivalents
-func (c *VSCodeToJetBrainsConverter) convertVSCodeVariables(path string) string {
-	// Common VSCode → JetBrains variable mappings
-	replacements := map[string]string{
-		"${workspaceFolder}": "$PROJECT_DIR$",
-		"${workspaceRoot}":   "$PROJECT_DIR$",
-		"${file}":            "$FilePath$",
-		"${fileBasename}":    "$FileName$",
-		"${fileDirname}":     "$FileDir$",
-		"${fileExtname}":     "$FileExt$",
+// workingDirectory converts a task's cwd to a JetBrains WORKING_DIRECTORY. The tasks parser
+// resolves ${workspaceFolder} to an absolute path, so paths inside the project root are turned
+// back into $PROJECT_DIR$ to keep the generated configuration usable from any checkout.
+func (c *VSCodeToJetBrainsConverter) workingDirectory(cwd string) string {
+	if cwd == "" {
+		return "$PROJECT_DIR$"
 	}
 
-	result := path
-	for vscode, jetbrains := range replacements {
-		result = strings.ReplaceAll(result, vscode, jetbrains)
-	}
+	return c.convertVSCodeVariables(relativizeToWorkspace(cwd, c.projectRoot))
+}
 
-	return result
+// convertVSCodeVariables converts VSCode variables to JetBrains equivalents
+func (c *VSCodeToJetBrainsConverter) convertVSCodeVariables(path string) string {
+	return ConvertVSCodeVariables(path)
 }
 
 // writeJetBrainsConfig writes the JetBrains configuration to an XML file
 func (c *VSCodeToJetBrainsConverter) writeJetBrainsConfig(config *JetBrainsRunConfiguration, filepath string) error {
-	// Create the root component structure that JetBrains expects
-	component := &JetBrainsComponent{
-		Name:          "ProjectRunConfigurationManager",
-		Configuration: *config,
-	}
-
-	// Marshal to XML with proper formatting
-	xmlData, err := xml.MarshalIndent(component, "", "  ")
+	xmlContent, err := renderRunConfiguration(config, "")
 	if err != nil {
-		return fmt.Errorf("failed to marshal XML: %w", err)
+		return err
 	}
 
-	// Add XML declaration
-	xmlContent := []byte(xml.Header + string(xmlData))
+	if err := c.Guard.Check(c.Context, filepath); err != nil {
+		return err
+	}
 
 	// Write to file
-	if err := os.WriteFile(filepath, xmlContent, 0644); err != nil {
+	if err := WriteFileAtomic(filepath, xmlContent, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -297,11 +543,79 @@ type JetBrainsComponent struct {
 }
 
 type JetBrainsRunConfiguration struct {
-	XMLName xml.Name          `xml:"configuration"`
-	Name    string            `xml:"name,attr"`
-	Type    string            `xml:"type,attr"`
-	Options []JetBrainsOption `xml:"option"`
-	EnvVars *JetBrainsEnvVars `xml:"envs,omitempty"`
+	XMLName                xml.Name                         `xml:"configuration"`
+	Name                   string                           `xml:"name,attr"`
+	Type                   string                           `xml:"type,attr"`
+	FactoryName            string                           `xml:"factoryName,attr,omitempty" json:",omitempty"`
+	Folder                 string                           `xml:"folderName,attr,omitempty" json:",omitempty"` // Carries the `[tags: a,b]` annotation so tags survive porting
+	Options                []JetBrainsOption                `xml:"option"`
+	EnvVars                *JetBrainsEnvVars                `xml:"envs,omitempty"`
+	ExternalSystemSettings *JetBrainsExternalSystemSettings `xml:"ExternalSystemSettings,omitempty" json:",omitempty"`
+	ToRun                  []JetBrainsToRun                 `xml:"toRun" json:",omitempty"`
+	Method                 *JetBrainsMethod                 `xml:"method,omitempty" json:",omitempty"`
+}
+
+// CompoundConfigurationType is the JetBrains type of configurations that start several others together
+const CompoundConfigurationType = "CompoundRunConfigurationType"
+
+// ShConfigurationType is the JetBrains type of configurations that run an inline shell script
+const ShConfigurationType = config.ShConfigurationType
+
+// aggregateScriptText is the script of configurations that only run their before-run tasks.
+// JetBrains has no run configuration without something to run, so ":" stands in.
+const aggregateScriptText = ":"
+
+// splitCommandWords returns a copy of a shell task whose command is a command line of plain
+// words, e.g. "./gradlew build", with the words after the first moved in front of its args.
+// Other tasks are returned as they are.
+func splitCommandWords(task *config.Task) *config.Task {
+	if task.Execution != config.ExecutionShell || strings.ContainsAny(task.Command, shellSyntax) || runner.CommandIsPath(task) {
+		return task
+	}
+
+	words := strings.Fields(task.Command)
+	if len(words) < 2 {
+		return task
+	}
+
+	split := *task
+	split.Command = words[0]
+	split.Args = append(words[1:], task.Args...)
+
+	// The moved words take the default quoting, so the task's own args keep theirs
+	if len(task.ArgQuoting) > 0 {
+		split.ArgQuoting = append(make([]string, len(words)-1), task.ArgQuoting...)
+	}
+
+	return &split
+}
+
+// shellSyntax holds characters that make a command only meaningful to a shell: operators,
+// redirections, quoting, expansions and newlines separating commands. Spaces alone don't, as
+// a command with arguments like "gradle build" still names the tool to match.
+const shellSyntax = "&|;<>()$`\\\"'\n"
+
+// JetBrainsToRun references a configuration started by a compound configuration
+type JetBrainsToRun struct {
+	XMLName xml.Name `xml:"toRun"`
+	Name    string   `xml:"name,attr"`
+	Type    string   `xml:"type,attr"`
+}
+
+// JetBrainsMethod holds the before-run tasks of a configuration
+type JetBrainsMethod struct {
+	XMLName xml.Name                 `xml:"method"`
+	Version string                   `xml:"v,attr"`
+	Options []JetBrainsBeforeRunTask `xml:"option"`
+}
+
+// JetBrainsBeforeRunTask is a before-run step, such as running another configuration
+type JetBrainsBeforeRunTask struct {
+	XMLName              xml.Name `xml:"option"`
+	Name                 string   `xml:"name,attr"`
+	Enabled              string   `xml:"enabled,attr"`
+	RunConfigurationName string   `xml:"run_configuration_name,attr,omitempty"`
+	RunConfigurationType string   `xml:"run_configuration_type,attr,omitempty"`
 }
 
 type JetBrainsOption struct {
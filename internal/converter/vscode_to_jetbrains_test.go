@@ -2,11 +2,15 @@ package converter
 
 import (
 	"encoding/xml"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/golden"
+	"github.com/syndbg/taskporter/internal/logging"
 	"github.com/syndbg/taskporter/internal/parser/vscode"
 
 	"github.com/stretchr/testify/require"
@@ -17,7 +21,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 	tempDir := t.TempDir()
 
 	t.Run("NewVSCodeToJetBrainsConverter", func(t *testing.T) {
-		converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, true)
+		converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, true, nil)
 
 		require.NotNil(t, converter)
 		require.Equal(t, "/test/project", converter.projectRoot)
@@ -28,7 +32,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 	t.Run("ConvertTasks", func(t *testing.T) {
 		t.Run("should convert Java tasks correctly", func(t *testing.T) {
 			tasks := loadTestTasks(t, "java-tasks.json")
-			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false, nil)
 
 			err := converter.ConvertTasks(tasks, false)
 			require.NoError(t, err)
@@ -41,13 +45,13 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 			require.FileExists(t, runFile)
 
 			// Validate XML content
-			validateJavaCompileXML(t, compileFile) // javac command -> ShellScript
+			validateJavaCompileXML(t, compileFile) // javac command -> shell script
 			validateJavaRunXML(t, runFile)         // java command -> Application
 		})
 
 		t.Run("should convert Gradle tasks correctly", func(t *testing.T) {
 			tasks := loadTestTasks(t, "gradle-tasks.json")
-			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false, nil)
 
 			err := converter.ConvertTasks(tasks, false)
 			require.NoError(t, err)
@@ -60,12 +64,12 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 			require.FileExists(t, testFile)
 
 			// Validate that it's recognized as Gradle task
-			validateGradleXML(t, buildFile, "build --info")
+			validateGradleXML(t, buildFile, []string{"build"}, "--info")
 		})
 
 		t.Run("should convert Node.js tasks correctly", func(t *testing.T) {
 			tasks := loadTestTasks(t, "nodejs-tasks.json")
-			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false, nil)
 
 			err := converter.ConvertTasks(tasks, false)
 			require.NoError(t, err)
@@ -83,7 +87,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 
 		t.Run("should convert Python tasks correctly", func(t *testing.T) {
 			tasks := loadTestTasks(t, "python-tasks.json")
-			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false, nil)
 
 			err := converter.ConvertTasks(tasks, false)
 			require.NoError(t, err)
@@ -101,7 +105,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 
 		t.Run("should convert Maven tasks correctly", func(t *testing.T) {
 			tasks := loadTestTasks(t, "maven-tasks.json")
-			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false, nil)
 
 			err := converter.ConvertTasks(tasks, false)
 			require.NoError(t, err)
@@ -115,12 +119,53 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 			validateMavenXML(t, mavenCompileFile, "compile")
 		})
 
+		t.Run("should keep shell command lines verbatim", func(t *testing.T) {
+			tasks := loadTestTasks(t, "shell-tasks.json")
+			outputDir := t.TempDir()
+			converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			pipeline := readJetBrainsConfig(t, filepath.Join(outputDir, "pipeline.xml"))
+			require.Equal(t, ShConfigurationType, pipeline.Type)
+			require.Equal(t, `bash -c "a && b | c"`, findOption(pipeline.Options, "SCRIPT_TEXT").Value)
+
+			content, err := os.ReadFile(filepath.Join(outputDir, "pipeline.xml"))
+			require.NoError(t, err)
+			golden.Assert(t, content, "shell_task_to_jetbrains_expected.xml")
+
+			// Tools inside a shell command line don't make it a tool configuration
+			logged := readJetBrainsConfig(t, filepath.Join(outputDir, "npm-test-log.xml"))
+			require.Equal(t, ShConfigurationType, logged.Type)
+			require.Equal(t, `npm test | tee 'test output.log'`, findOption(logged.Options, "SCRIPT_TEXT").Value)
+
+			process := readJetBrainsConfig(t, filepath.Join(outputDir, "npm-test.xml"))
+			require.Equal(t, "NodeJS", process.Type)
+		})
+
+		t.Run("should split a shell command line of plain words into the command and its args", func(t *testing.T) {
+			outputDir := t.TempDir()
+			converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+
+			task := &config.Task{Name: "build", Type: config.TypeVSCodeTask, Execution: config.ExecutionShell, Command: "./gradlew build", Args: []string{"--info"}}
+			require.NoError(t, converter.ConvertTasks([]*config.Task{task}, false))
+
+			settings := readJetBrainsConfig(t, filepath.Join(outputDir, "build.xml")).ExternalSystemSettings
+			require.NotNil(t, settings)
+
+			content, err := os.ReadFile(filepath.Join(outputDir, "build.xml"))
+			require.NoError(t, err)
+			require.Contains(t, string(content), `<option name="scriptParameters" value="--info">`)
+			require.Contains(t, string(content), `<option value="build">`)
+			require.Equal(t, "./gradlew build", task.Command)
+		})
+
 		t.Run("should handle dry run mode", func(t *testing.T) {
 			tasks := loadTestTasks(t, "java-tasks.json")
 
 			// Use a different temp directory for dry run to avoid conflicts
 			dryRunDir := filepath.Join(tempDir, "dry-run")
-			converter := NewVSCodeToJetBrainsConverter("/test/project", dryRunDir, true)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", dryRunDir, true, nil)
 
 			err := converter.ConvertTasks(tasks, true)
 			require.NoError(t, err)
@@ -131,7 +176,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 		})
 
 		t.Run("should handle empty task list", func(t *testing.T) {
-			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false, nil)
 
 			err := converter.ConvertTasks([]*config.Task{}, false)
 			require.NoError(t, err)
@@ -139,7 +184,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 
 		t.Run("should handle edge cases", func(t *testing.T) {
 			tasks := loadTestTasks(t, "edge-cases.json")
-			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false, nil)
 
 			err := converter.ConvertTasks(tasks, false)
 			require.NoError(t, err)
@@ -162,8 +207,177 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 			validateComplexGradleXML(t, complexGradleFile)
 		})
 
+		t.Run("should port dependsOn as before-run tasks and compounds", func(t *testing.T) {
+			tasks := loadTestTasks(t, "dependency-tasks.json")
+			outputDir := t.TempDir()
+			recorder, logger := logging.NewRecorder()
+			converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, logger)
+
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			// Sequence chain: package -> test -> compile
+			compile := readJetBrainsConfig(t, filepath.Join(outputDir, "compile.xml"))
+			require.Nil(t, compile.Method)
+
+			test := readJetBrainsConfig(t, filepath.Join(outputDir, "test.xml"))
+			require.NotNil(t, test.Method)
+			require.Equal(t, "2", test.Method.Version)
+			require.Len(t, test.Method.Options, 1)
+			require.Equal(t, "RunConfigurationTask", test.Method.Options[0].Name)
+			require.Equal(t, "true", test.Method.Options[0].Enabled)
+			require.Equal(t, "compile", test.Method.Options[0].RunConfigurationName)
+			require.Equal(t, GradleConfigurationType, test.Method.Options[0].RunConfigurationType)
+
+			// The unknown "docs" dependency is dropped with a warning
+			pkg := readJetBrainsConfig(t, filepath.Join(outputDir, "package.xml"))
+			require.NotNil(t, pkg.Method)
+			require.Len(t, pkg.Method.Options, 1)
+			require.Equal(t, "test", pkg.Method.Options[0].RunConfigurationName)
+
+			entries := recorder.Entries()
+			require.Len(t, entries, 1)
+			require.Equal(t, slog.LevelWarn, entries[0].Level)
+			require.Equal(t, "vscode-to-jetbrains", entries[0].Attrs[logging.KeyComponent])
+			require.Equal(t, "package", entries[0].Attrs[logging.KeyTask])
+			require.Equal(t, "docs", entries[0].Attrs["dependency"])
+
+			// Parallel task without a command becomes a compound
+			all := readJetBrainsConfig(t, filepath.Join(outputDir, "all.xml"))
+			require.Equal(t, CompoundConfigurationType, all.Type)
+			require.Empty(t, all.Options)
+			require.Nil(t, all.Method)
+			require.Equal(t, []JetBrainsToRun{
+				{XMLName: xml.Name{Local: "toRun"}, Name: "lint", Type: GradleConfigurationType},
+				{XMLName: xml.Name{Local: "toRun"}, Name: "package", Type: GradleConfigurationType},
+			}, all.ToRun)
+
+			// Sequence task without a command chains before-run tasks instead of an empty script
+			ci := readJetBrainsConfig(t, filepath.Join(outputDir, "ci.xml"))
+			require.Equal(t, ShConfigurationType, ci.Type)
+			require.Contains(t, ci.Options, JetBrainsOption{XMLName: xml.Name{Local: "option"}, Name: "SCRIPT_TEXT", Value: aggregateScriptText})
+			require.NotNil(t, ci.Method)
+			require.Len(t, ci.Method.Options, 2)
+			require.Equal(t, "lint", ci.Method.Options[0].RunConfigurationName)
+			require.Equal(t, "test", ci.Method.Options[1].RunConfigurationName)
+		})
+
+		t.Run("should order dependencies before dependents", func(t *testing.T) {
+			tasks := loadTestTasks(t, "dependency-tasks.json")
+
+			var names []string
+			for _, task := range sortByDependencies(tasks, nil) {
+				names = append(names, task.Name)
+			}
+
+			require.Equal(t, []string{"lint", "compile", "test", "package", "all", "ci"}, names)
+		})
+
+		t.Run("should break dependency cycles", func(t *testing.T) {
+			tasks := []*config.Task{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+			}
+
+			recorder, logger := logging.NewRecorder()
+
+			require.Len(t, sortByDependencies(tasks, logger), 2)
+			require.Len(t, recorder.Entries(), 1)
+			require.Equal(t, "dependency cycle", recorder.Entries()[0].Message)
+		})
+
+		t.Run("should give colliding filenames a numeric suffix", func(t *testing.T) {
+			outputDir := t.TempDir()
+			tasks := []*config.Task{
+				{Name: "deploy:prod", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"deploy-a"}},
+				{Name: "deploy prod", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"deploy-b"}},
+			}
+
+			converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			first := readJetBrainsConfig(t, filepath.Join(outputDir, "deploy_prod.xml"))
+			require.Equal(t, "deploy:prod", first.Name)
+			require.Contains(t, findOption(first.Options, "SCRIPT_TEXT").Value, "deploy-a")
+
+			second := readJetBrainsConfig(t, filepath.Join(outputDir, "deploy_prod_2.xml"))
+			require.Equal(t, "deploy prod", second.Name)
+			require.Contains(t, findOption(second.Options, "SCRIPT_TEXT").Value, "deploy-b")
+
+			// Converting again replaces the same two files instead of adding more
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			entries, err := os.ReadDir(outputDir)
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+		})
+
+		t.Run("should not overwrite an existing file holding another configuration", func(t *testing.T) {
+			outputDir := t.TempDir()
+			tasks := []*config.Task{{Name: "build", Type: config.TypeVSCodeTask, Command: "make"}}
+
+			other := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+			require.NoError(t, other.ConvertTasks([]*config.Task{{Name: "build?", Type: config.TypeVSCodeTask, Command: "make all"}}, false))
+			require.FileExists(t, filepath.Join(outputDir, "build_.xml"))
+
+			require.NoError(t, os.Rename(filepath.Join(outputDir, "build_.xml"), filepath.Join(outputDir, "build.xml")))
+
+			converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			require.Equal(t, "build?", readJetBrainsConfig(t, filepath.Join(outputDir, "build.xml")).Name)
+			require.Equal(t, "build", readJetBrainsConfig(t, filepath.Join(outputDir, "build_2.xml")).Name)
+		})
+
+		t.Run("should place configurations by an output template", func(t *testing.T) {
+			outputDir := t.TempDir()
+			template := filepath.Join(outputDir, "{group}") + string(filepath.Separator)
+
+			tasks := []*config.Task{
+				{Name: "unit", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"test"}, Group: "test"},
+				{Name: "build", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"build"}, Group: "build"},
+				{Name: "test", Type: config.TypeVSCodeTask, Command: "make", Group: "build"},
+				{Name: "lint", Type: config.TypeVSCodeTask, Command: "golangci-lint", Tags: []string{"ci"}},
+			}
+
+			converter := NewVSCodeToJetBrainsConverter("/test/project", template, false, nil)
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			// IntelliJ doesn't load subdirectories of runConfigurations, the folder is folderName
+			entries, err := os.ReadDir(outputDir)
+			require.NoError(t, err)
+
+			for _, entry := range entries {
+				require.False(t, entry.IsDir(), entry.Name())
+			}
+
+			require.Equal(t, "test", readJetBrainsConfig(t, filepath.Join(outputDir, "unit.xml")).Folder)
+			require.Equal(t, "build", readJetBrainsConfig(t, filepath.Join(outputDir, "build.xml")).Folder)
+			require.Equal(t, "build", readJetBrainsConfig(t, filepath.Join(outputDir, "test.xml")).Folder)
+			require.Equal(t, config.TagAnnotation(tasks[3]), readJetBrainsConfig(t, filepath.Join(outputDir, "lint.xml")).Folder)
+		})
+
+		t.Run("should keep tags next to the output template folder", func(t *testing.T) {
+			outputDir := t.TempDir()
+			template := filepath.Join(outputDir, "{group}") + string(filepath.Separator)
+			task := &config.Task{Name: "unit", Type: config.TypeVSCodeTask, Command: "go", Group: "test", Tags: []string{"ci"}}
+
+			converter := NewVSCodeToJetBrainsConverter("/test/project", template, false, nil)
+			require.NoError(t, converter.ConvertTasks([]*config.Task{task}, false))
+
+			folder := readJetBrainsConfig(t, filepath.Join(outputDir, "unit.xml")).Folder
+			require.Equal(t, "test "+config.TagAnnotation(task), folder)
+			require.Equal(t, []string{"ci"}, config.ParseTags(folder))
+		})
+
+		t.Run("should reject unknown output template placeholders", func(t *testing.T) {
+			converter := NewVSCodeToJetBrainsConverter("/test/project", filepath.Join(t.TempDir(), "{label}"), false, nil)
+
+			err := converter.ConvertTasks([]*config.Task{{Name: "build", Type: config.TypeVSCodeTask, Command: "make"}}, false)
+			require.ErrorContains(t, err, "unknown placeholder {label}")
+		})
+
 		t.Run("should handle nil tasks gracefully", func(t *testing.T) {
-			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", tempDir, false, nil)
 
 			// This should not panic
 			err := converter.ConvertTasks(nil, false)
@@ -173,7 +387,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 		t.Run("should handle invalid output directory", func(t *testing.T) {
 			// Try to write to a non-existent directory without creating it
 			invalidDir := filepath.Join(tempDir, "nonexistent", "deeply", "nested")
-			converter := NewVSCodeToJetBrainsConverter("/test/project", invalidDir, false)
+			converter := NewVSCodeToJetBrainsConverter("/test/project", invalidDir, false, nil)
 
 			tasks := loadTestTasks(t, "java-tasks.json")
 
@@ -184,7 +398,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 	})
 
 	t.Run("convertVSCodeVariables", func(t *testing.T) {
-		converter := NewVSCodeToJetBrainsConverter("/test/project", "", false)
+		converter := NewVSCodeToJetBrainsConverter("/test/project", "", false, nil)
 
 		testCases := []struct {
 			name     string
@@ -226,13 +440,43 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 		}
 	})
 
-	t.Run("determineConfigType", func(t *testing.T) {
-		converter := NewVSCodeToJetBrainsConverter("/test/project", "", false)
+	t.Run("workingDirectory", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the fixture paths are POSIX paths")
+		}
+
+		converter := NewVSCodeToJetBrainsConverter("/test/project", "", false, nil)
 
 		testCases := []struct {
 			name     string
-			command  string
+			input    string
 			expected string
+		}{
+			{name: "no cwd", input: "", expected: "$PROJECT_DIR$"},
+			{name: "project root", input: "/test/project", expected: "$PROJECT_DIR$"},
+			{name: "inside the project", input: "/test/project/services/api/", expected: "$PROJECT_DIR$/services/api"},
+			{name: "outside the project", input: "/test/other", expected: "/test/other"},
+			{name: "sibling with a common prefix", input: "/test/project-tools", expected: "/test/project-tools"},
+			{name: "directory starting with dots", input: "/test/project/..cache", expected: "$PROJECT_DIR$/..cache"},
+			{name: "variable", input: "${workspaceFolder}/web", expected: "$PROJECT_DIR$/web"},
+			{name: "relative path", input: "build", expected: "build"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expected, converter.workingDirectory(tc.input))
+			})
+		}
+	})
+
+	t.Run("determineConfigType", func(t *testing.T) {
+		converter := NewVSCodeToJetBrainsConverter("/test/project", "", false, nil)
+
+		testCases := []struct {
+			name      string
+			command   string
+			execution string
+			expected  string
 		}{
 			{
 				name:     "Java application",
@@ -242,7 +486,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 			{
 				name:     "Gradle build",
 				command:  "gradle",
-				expected: "GradleRunTask",
+				expected: GradleConfigurationType,
 			},
 			{
 				name:     "Maven build",
@@ -262,13 +506,37 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 			{
 				name:     "Shell script",
 				command:  "sh",
-				expected: "ShellScript",
+				expected: ShConfigurationType,
+			},
+			{
+				name:      "Shell task with a single command",
+				command:   "npm",
+				execution: config.ExecutionShell,
+				expected:  "NodeJS",
+			},
+			{
+				name:      "Shell task with a command line",
+				command:   "npm run build && npm test",
+				execution: config.ExecutionShell,
+				expected:  ShConfigurationType,
+			},
+			{
+				name:      "Shell task with a command and its arguments",
+				command:   "./gradlew build",
+				execution: config.ExecutionShell,
+				expected:  GradleConfigurationType,
+			},
+			{
+				name:      "Process task",
+				command:   "gradle",
+				execution: config.ExecutionProcess,
+				expected:  GradleConfigurationType,
 			},
 		}
 
 		for _, tc := range testCases {
 			t.Run(tc.name, func(t *testing.T) {
-				task := &config.Task{Command: tc.command}
+				task := &config.Task{Command: tc.command, Execution: tc.execution}
 				result := converter.determineConfigType(task)
 				require.Equal(t, tc.expected, result)
 			})
@@ -316,7 +584,7 @@ func TestVSCodeToJetBrainsConverter(t *testing.T) {
 
 func loadTestTasks(t *testing.T, filename string) []*config.Task {
 	testdataPath := filepath.Join("testdata", filename)
-	parser := vscode.NewTasksParser("/test/project")
+	parser := vscode.NewTasksParser("/test/project", nil)
 
 	tasks, err := parser.ParseTasks(testdataPath)
 	require.NoError(t, err)
@@ -325,6 +593,18 @@ func loadTestTasks(t *testing.T, filename string) []*config.Task {
 	return tasks
 }
 
+func readJetBrainsConfig(t *testing.T, filename string) JetBrainsRunConfiguration {
+	xmlData, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	var component JetBrainsComponent
+
+	err = xml.Unmarshal(xmlData, &component)
+	require.NoError(t, err)
+
+	return component.Configuration
+}
+
 func validateJavaCompileXML(t *testing.T, filename string) {
 	xmlData, err := os.ReadFile(filename)
 	require.NoError(t, err)
@@ -336,7 +616,7 @@ func validateJavaCompileXML(t *testing.T, filename string) {
 
 	config := component.Configuration
 	require.Equal(t, "compile-java", config.Name)
-	require.Equal(t, "ShellScript", config.Type) // javac command should be ShellScript, not Application
+	require.Equal(t, ShConfigurationType, config.Type) // javac command should be a shell script, not Application
 
 	// Check for script text option
 	scriptOption := findOption(config.Options, "SCRIPT_TEXT")
@@ -344,10 +624,10 @@ func validateJavaCompileXML(t *testing.T, filename string) {
 	require.Contains(t, scriptOption.Value, "javac")
 	require.Contains(t, scriptOption.Value, "Main.java")
 
-	// Check working directory (VSCode parser resolves ${workspaceFolder} to absolute path)
+	// Check working directory (the absolute path the parser resolved is made project-relative again)
 	workingDirOption := findOption(config.Options, "WORKING_DIRECTORY")
 	require.NotNil(t, workingDirOption)
-	require.Equal(t, "/test/project", workingDirOption.Value)
+	require.Equal(t, "$PROJECT_DIR$", workingDirOption.Value)
 
 	// Check environment variables
 	require.NotNil(t, config.EnvVars)
@@ -371,13 +651,13 @@ func validateJavaRunXML(t *testing.T, filename string) {
 	require.Equal(t, "run-java-app", config.Name)
 	require.Equal(t, "Application", config.Type) // java command should be detected as Application
 
-	// Check working directory conversion (VSCode parser resolves ${workspaceFolder} to absolute path)
+	// Check working directory conversion (the absolute path the parser resolved is made project-relative again)
 	workingDirOption := findOption(config.Options, "WORKING_DIRECTORY")
 	require.NotNil(t, workingDirOption)
-	require.Equal(t, "/test/project/build", workingDirOption.Value)
+	require.Equal(t, "$PROJECT_DIR$/build", workingDirOption.Value)
 }
 
-func validateGradleXML(t *testing.T, filename string, expectedTaskName string) {
+func validateGradleXML(t *testing.T, filename string, expectedTaskNames []string, expectedScriptParameters string) {
 	xmlData, err := os.ReadFile(filename)
 	require.NoError(t, err)
 
@@ -387,12 +667,13 @@ func validateGradleXML(t *testing.T, filename string, expectedTaskName string) {
 	require.NoError(t, err)
 
 	config := component.Configuration
-	require.Equal(t, "GradleRunTask", config.Type)
+	require.Equal(t, GradleConfigurationType, config.Type)
+	require.NotNil(t, config.ExternalSystemSettings)
 
-	// Check task name option
-	taskNameOption := findOption(config.Options, "TASK_NAME")
-	require.NotNil(t, taskNameOption)
-	require.Equal(t, expectedTaskName, taskNameOption.Value)
+	// Tasks and options are kept apart, like the IDE does
+	require.Equal(t, expectedTaskNames, settingListValues(findSetting(config.ExternalSystemSettings.Options, "taskNames")))
+	require.Equal(t, expectedScriptParameters, findSetting(config.ExternalSystemSettings.Options, "scriptParameters").Value)
+	require.Equal(t, "GRADLE", findSetting(config.ExternalSystemSettings.Options, "externalSystemIdString").Value)
 }
 
 func validateNodeJSXML(t *testing.T, filename string) {
@@ -413,10 +694,10 @@ func validateNodeJSXML(t *testing.T, filename string) {
 	require.Contains(t, scriptOption.Value, "node server.js")
 	require.Contains(t, scriptOption.Value, "--port 8080")
 
-	// Check working directory (VSCode parser resolves ${workspaceFolder} to absolute path)
+	// Check working directory (the absolute path the parser resolved is made project-relative again)
 	workingDirOption := findOption(config.Options, "WORKING_DIRECTORY")
 	require.NotNil(t, workingDirOption)
-	require.Equal(t, "/test/project/src", workingDirOption.Value)
+	require.Equal(t, "$PROJECT_DIR$/src", workingDirOption.Value)
 }
 
 func validatePythonXML(t *testing.T, filename string) {
@@ -468,7 +749,7 @@ func validateEdgeCaseXML(t *testing.T, filename string, originalTaskName string)
 
 	config := component.Configuration
 	require.Equal(t, originalTaskName, config.Name)
-	require.Equal(t, "ShellScript", config.Type)
+	require.Equal(t, ShConfigurationType, config.Type)
 
 	// Check that script text exists
 	scriptOption := findOption(config.Options, "SCRIPT_TEXT")
@@ -486,25 +767,21 @@ func validateComplexGradleXML(t *testing.T, filename string) {
 
 	config := component.Configuration
 	require.Equal(t, "complex-gradle-task", config.Name)
-	require.Equal(t, "GradleRunTask", config.Type)
+	require.Equal(t, GradleConfigurationType, config.Type)
 
-	// Check task arguments
-	taskNameOption := findOption(config.Options, "TASK_NAME")
-	require.NotNil(t, taskNameOption)
-	require.Contains(t, taskNameOption.Value, "clean build")
-	require.Contains(t, taskNameOption.Value, "--parallel")
+	settings := config.ExternalSystemSettings.Options
 
-	// Check environment variables
-	require.NotNil(t, config.EnvVars)
+	// Check task arguments; the value of -x is not a task
+	require.Equal(t, []string{"clean", "build"}, settingListValues(findSetting(settings, "taskNames")))
+	require.Equal(t, "-x test --parallel", findSetting(settings, "scriptParameters").Value)
 
-	gradleOptsEnv := findEnvVar(config.EnvVars.EnvVars, "GRADLE_OPTS")
-	require.NotNil(t, gradleOptsEnv)
-	require.Contains(t, gradleOptsEnv.Value, "-Xmx4g")
+	// Check environment variables
+	env := findSetting(settings, "env")
+	require.NotNil(t, env.Map)
+	require.Contains(t, env.Map.Entries, JetBrainsSettingMapEntry{Key: "GRADLE_OPTS", Value: "-Xmx4g -XX:+UseG1GC"})
 
-	// Check working directory is converted
-	workingDirOption := findOption(config.Options, "WORKING_DIRECTORY")
-	require.NotNil(t, workingDirOption)
-	require.Equal(t, "/test/project/subproject", workingDirOption.Value)
+	// The working directory is the Gradle project
+	require.Equal(t, "$PROJECT_DIR$/subproject", findSetting(settings, "externalProjectPath").Value)
 }
 
 // Helper functions to find options and environment variables
@@ -519,6 +796,29 @@ func findOption(options []JetBrainsOption, name string) *JetBrainsOption {
 	return nil
 }
 
+func findSetting(options []JetBrainsSettingOption, name string) *JetBrainsSettingOption {
+	for _, option := range options {
+		if option.Name == name {
+			return &option
+		}
+	}
+
+	return &JetBrainsSettingOption{}
+}
+
+func settingListValues(option *JetBrainsSettingOption) []string {
+	if option.List == nil {
+		return nil
+	}
+
+	var values []string
+	for _, value := range option.List.Values {
+		values = append(values, value.Value)
+	}
+
+	return values
+}
+
 func findEnvVar(envVars []JetBrainsEnvVar, name string) *JetBrainsEnvVar {
 	for _, envVar := range envVars {
 		if envVar.Name == name {
@@ -528,3 +828,30 @@ func findEnvVar(envVars []JetBrainsEnvVar, name string) *JetBrainsEnvVar {
 
 	return nil
 }
+
+func TestTagAnnotationsSurvivePorting(t *testing.T) {
+	t.Run("VSCode detail becomes the JetBrains folder name verbatim", func(t *testing.T) {
+		outputDir := t.TempDir()
+		tasks := []*config.Task{
+			{Name: "lint", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"lint"}, Description: "Static checks [tags: ci, quick]", Tags: []string{"ci", "quick"}},
+			{Name: "build", Type: config.TypeVSCodeTask, Command: "make", Description: "Build everything"},
+		}
+
+		converter := NewVSCodeToJetBrainsConverter("/test/project", outputDir, false, nil)
+		require.NoError(t, converter.ConvertTasks(tasks, false))
+
+		require.Equal(t, "[tags: ci, quick]", readJetBrainsConfig(t, filepath.Join(outputDir, "lint.xml")).Folder)
+		require.Empty(t, readJetBrainsConfig(t, filepath.Join(outputDir, "build.xml")).Folder)
+	})
+
+	t.Run("JetBrains tags become the VSCode detail", func(t *testing.T) {
+		task := &config.Task{Name: "Migrate", Type: config.TypeJetBrains, Command: "python", Args: []string{"manage.py"}, Tags: []string{"ci", "destructive"}}
+
+		converter := NewJetBrainsToVSCodeConverter("/test/project", filepath.Join(t.TempDir(), "tasks.json"), false, nil)
+
+		vscodeTask, err := converter.convertSingleTask(task, &ReportEntry{})
+		require.NoError(t, err)
+		require.Equal(t, "[tags: ci,destructive]", vscodeTask.Detail)
+		require.Equal(t, []string{"ci", "destructive"}, config.ParseTags(vscodeTask.Detail))
+	})
+}
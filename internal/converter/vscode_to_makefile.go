@@ -0,0 +1,335 @@
+package converter
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/runner"
+)
+
+// invalidMakeTargetChars matches characters that cannot appear in a Make target name
+var invalidMakeTargetChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// VSCodeToMakefileConverter converts VSCode tasks to Makefile targets
+type VSCodeToMakefileConverter struct {
+	WriteOptions
+	projectRoot string
+	outputPath  string
+	verbose     bool
+	logger      *slog.Logger
+}
+
+// NewVSCodeToMakefileConverter creates a new VSCode tasks to Makefile converter
+func NewVSCodeToMakefileConverter(projectRoot, outputPath string, verbose bool, logger *slog.Logger) *VSCodeToMakefileConverter {
+	return &VSCodeToMakefileConverter{
+		projectRoot:  projectRoot,
+		outputPath:   outputPath,
+		verbose:      verbose,
+		WriteOptions: defaultWriteOptions(),
+		logger:       logging.OrDiscard(logger).With(logging.KeyComponent, "vscode-to-makefile"),
+	}
+}
+
+// ConvertTasks writes each VSCode task as a .PHONY Makefile target
+func (c *VSCodeToMakefileConverter) ConvertTasks(tasks []*config.Task, dryRun bool) (err error) {
+	if c.verbose {
+		fmt.Fprintf(c.Out, "🔄 Converting %d VSCode tasks to Makefile targets...\n", len(tasks))
+	}
+
+	// Only convert VSCode tasks (not launch configs)
+	vscodeTasks := make([]*config.Task, 0, len(tasks))
+
+	for _, task := range tasks {
+		if task.Type != config.TypeVSCodeTask {
+			if c.verbose {
+				fmt.Fprintf(c.Out, "⏭️  Skipping non-VSCode task: %s (type: %s)\n", task.Name, string(task.Type))
+			}
+
+			c.Report.Skip(task, fmt.Sprintf("not a VSCode task (type %s)", task.Type))
+
+			continue
+		}
+
+		vscodeTasks = append(vscodeTasks, task)
+	}
+
+	if len(vscodeTasks) == 0 {
+		fmt.Fprintf(c.Out, "⚠️  No VSCode tasks found to convert\n")
+		return nil
+	}
+
+	// Determine output path
+	outputPath, err := ResolveFileOutput(c.outputPath, filepath.Join(c.projectRoot, "Makefile"))
+	if err != nil {
+		return err
+	}
+
+	if c.verbose {
+		fmt.Fprintf(c.Out, "📁 Output file: %s\n", outputPath)
+	}
+
+	if !dryRun {
+		if err := ensureWritableDir(filepath.Dir(outputPath)); err != nil {
+			return err
+		}
+	}
+
+	// Every target lands in the one Makefile, so they all fail together
+	content, entries := c.generateMakefile(vscodeTasks, outputPath)
+	defer func() { c.Report.Add(failEntries(entries, err)...) }()
+
+	for _, task := range vscodeTasks {
+		if err := checkMakeRecipe(task); err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		previewFile(c.Context, c.Out, "Makefile", outputPath, withMakefileProvenance([]byte(content)), c.FullPreview)
+	} else {
+		if err := c.Guard.Check(c.Context, outputPath); err != nil {
+			return err
+		}
+
+		if err := WriteFileAtomic(outputPath, withMakefileProvenance([]byte(content)), 0644); err != nil {
+			return fmt.Errorf("failed to write Makefile: %w", err)
+		}
+
+		if c.verbose {
+			fmt.Fprintf(c.Out, "✅ Successfully created %s\n", outputPath)
+		}
+	}
+
+	fmt.Fprintf(c.Out, "✅ Successfully converted %d VSCode tasks to Makefile targets\n", len(vscodeTasks))
+
+	return nil
+}
+
+// makeTargets returns the target of each task, in order, and the first target by task name
+func makeTargets(tasks []*config.Task) ([]string, map[string]string) {
+	targets := make([]string, 0, len(tasks))
+	targetOf := make(map[string]string, len(tasks))
+	seen := make(map[string]int)
+
+	for _, task := range tasks {
+		target := sanitizeMakeTarget(task.Name)
+
+		// Disambiguate names that sanitize to the same target
+		seen[target]++
+		if seen[target] > 1 {
+			target = fmt.Sprintf("%s-%d", target, seen[target])
+		}
+
+		targets = append(targets, target)
+
+		if _, ok := targetOf[task.Name]; !ok {
+			targetOf[task.Name] = target
+		}
+	}
+
+	return targets, targetOf
+}
+
+// generateMakefile renders the Makefile body for the given tasks, along with the report entry
+// of the target each task becomes in the Makefile at outputPath
+func (c *VSCodeToMakefileConverter) generateMakefile(tasks []*config.Task, outputPath string) (string, []ReportEntry) {
+	targets, targetOf := makeTargets(tasks)
+	entries := make([]ReportEntry, 0, len(tasks))
+
+	var b strings.Builder
+
+	b.WriteString("# Targets derived from .vscode/tasks.json; re-run `taskporter port --from vscode-tasks --to makefile` to update.\n\n")
+	b.WriteString(".PHONY: " + strings.Join(targets, " ") + "\n")
+
+	for i, task := range tasks {
+		target := targets[i]
+
+		entry := newReportEntry(task, OutcomeConverted)
+		entry.TargetFile, entry.TargetName, entry.TargetType = outputPath, target, "phony target"
+
+		b.WriteString("\n")
+
+		// Keep the original task name and description visible when they don't survive as the target
+		switch {
+		case task.Description != "":
+			b.WriteString(fmt.Sprintf("# %s - %s\n", task.Name, strings.ReplaceAll(task.Description, "\n", " ")))
+		case task.Name != target:
+			b.WriteString(fmt.Sprintf("# %s\n", task.Name))
+		}
+
+		// Target-scoped, exported variables reach the recipe's environment
+		keys := make([]string, 0, len(task.Env))
+		for key := range task.Env {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			b.WriteString(fmt.Sprintf("%s: export %s = %s\n", target, key, escapeMakeValue(task.Env[key])))
+		}
+
+		entry.use("env")
+
+		b.WriteString(c.rule(task, target, targetOf, &entry))
+
+		entry.dropUnused(task)
+		entries = append(entries, entry)
+	}
+
+	return b.String(), entries
+}
+
+// rule writes a task's rule: its dependencies, then its recipe unless it only runs them.
+// Parallel dependencies are prerequisites, which `make -j` may build together; a sequence runs
+// them one after another through recursive make so the order holds even under -j.
+func (c *VSCodeToMakefileConverter) rule(task *config.Task, target string, targetOf map[string]string, entry *ReportEntry) string {
+	var dependencies []string
+
+	entry.use("dependsOn", "dependsOrder")
+
+	for _, name := range task.DependsOn {
+		dependency, ok := targetOf[name]
+		if !ok {
+			c.logger.Warn("dependency has no target; dropping it", logging.KeyTask, task.Name, "dependency", name)
+
+			entry.Warnings = append(entry.Warnings, fmt.Sprintf("dependency %q has no target and was dropped", name))
+			entry.drop("dependsOn")
+
+			continue
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	var b strings.Builder
+
+	if task.DependsOrder == config.DependsOrderSequence {
+		b.WriteString(target + ":\n")
+
+		for _, dependency := range dependencies {
+			b.WriteString("\t@$(MAKE) --no-print-directory " + dependency + "\n")
+		}
+	} else {
+		b.WriteString(strings.TrimRight(target+": "+strings.Join(dependencies, " "), " ") + "\n")
+	}
+
+	if !task.IsAggregate() {
+		b.WriteString("\t" + c.recipe(task, entry) + "\n")
+	}
+
+	return b.String()
+}
+
+// recipe builds the shell line for a task, changing into its working directory and unsetting
+// the variables it removes first. Make has no target-specific unexport, so the recipe's shell
+// unsets them.
+func (c *VSCodeToMakefileConverter) recipe(task *config.Task, entry *ReportEntry) string {
+	entry.use("args", "cwd", "unsetEnv")
+
+	// Like VSCode shell tasks, the command is used verbatim and only args are quoted
+	commandLine := task.Command
+	if task.Execution == config.ExecutionProcess || runner.CommandIsPath(task) {
+		commandLine = posixQuote(commandLine)
+	}
+
+	if len(task.Args) > 0 {
+		commandLine += " " + posixJoin(task.Args)
+	}
+
+	commandLine = escapeMakeValue(commandLine)
+
+	if len(task.UnsetEnv) > 0 {
+		commandLine = fmt.Sprintf("unset %s && %s", strings.Join(task.UnsetEnv, " "), commandLine)
+	}
+
+	if dir := c.relativeCwd(task.Cwd); dir != "" {
+		return fmt.Sprintf("cd %s && %s", escapeMakeValue(posixQuote(dir)), commandLine)
+	}
+
+	return commandLine
+}
+
+// relativeCwd expresses cwd relative to the project root, returning "" for the root itself
+func (c *VSCodeToMakefileConverter) relativeCwd(cwd string) string {
+	if cwd == "" {
+		return ""
+	}
+
+	absRoot, rootErr := filepath.Abs(c.projectRoot)
+	absCwd, cwdErr := filepath.Abs(cwd)
+
+	if rootErr != nil || cwdErr != nil {
+		return cwd
+	}
+
+	rel, err := filepath.Rel(absRoot, absCwd)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return absCwd
+	}
+
+	if rel == "." {
+		return ""
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// sanitizeMakeTarget turns a task name into a valid Make target name
+func sanitizeMakeTarget(name string) string {
+	target := strings.Trim(invalidMakeTargetChars.ReplaceAllString(name, "-"), "-")
+	if target == "" {
+		return "task"
+	}
+
+	return target
+}
+
+// checkMakeRecipe rejects tasks whose recipe would silently change meaning in a Makefile.
+// A recipe is a single line, so a newline inside a quoted path or argument cannot be kept.
+func checkMakeRecipe(task *config.Task) error {
+	if strings.ContainsAny(task.Cwd, "\r\n") {
+		return fmt.Errorf("task '%s' cannot be converted to a Makefile target: its working directory %q contains a line break", task.Name, task.Cwd)
+	}
+
+	for _, arg := range task.Args {
+		if strings.ContainsAny(arg, "\r\n") {
+			return fmt.Errorf("task '%s' cannot be converted to a Makefile target: argument %q contains a line break", task.Name, arg)
+		}
+	}
+
+	return nil
+}
+
+// escapeMakeValue escapes characters Make would otherwise interpret in recipes and variables
+func escapeMakeValue(value string) string {
+	value = strings.ReplaceAll(value, "$", "$$")
+
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+// posixJoin joins arguments for /bin/sh, single-quoting those that need it
+func posixJoin(args []string) string {
+	quoted := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		quoted = append(quoted, posixQuote(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// posixQuote single-quotes an argument if it contains shell metacharacters
+func posixQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\$`&|;<>()*?[]{}#~!") {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
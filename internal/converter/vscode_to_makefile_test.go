@@ -0,0 +1,189 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVSCodeToMakefileConverter(t *testing.T) {
+	projectRoot := t.TempDir()
+
+	t.Run("NewVSCodeToMakefileConverter", func(t *testing.T) {
+		converter := NewVSCodeToMakefileConverter("/test/project", "Makefile", true, nil)
+
+		require.NotNil(t, converter)
+		require.Equal(t, "/test/project", converter.projectRoot)
+		require.Equal(t, "Makefile", converter.outputPath)
+		require.True(t, converter.verbose)
+	})
+
+	t.Run("ConvertTasks", func(t *testing.T) {
+		t.Run("should write phony targets with env and cwd", func(t *testing.T) {
+			tasks := []*config.Task{
+				{
+					Name:    "Build App",
+					Type:    config.TypeVSCodeTask,
+					Command: "go",
+					Args:    []string{"build", "-o", "bin/my app", "./..."},
+					Cwd:     projectRoot,
+					Env:     map[string]string{"GOOS": "linux", "CGO_ENABLED": "0"},
+				},
+				{
+					Name:    "test:unit",
+					Type:    config.TypeVSCodeTask,
+					Command: "echo $HOME",
+					Cwd:     filepath.Join(projectRoot, "services", "api"),
+				},
+				{
+					Name:    "Debug",
+					Type:    config.TypeVSCodeLaunch,
+					Command: "node",
+				},
+			}
+
+			converter := NewVSCodeToMakefileConverter(projectRoot, "", false, nil)
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			data, err := os.ReadFile(filepath.Join(projectRoot, "Makefile"))
+			require.NoError(t, err)
+
+			content := string(data)
+			require.Contains(t, content, ProvenanceMarker)
+			require.Contains(t, content, ".PHONY: Build-App test-unit\n")
+			require.Contains(t, content, "Build-App: export CGO_ENABLED = 0\nBuild-App: export GOOS = linux\nBuild-App:\n")
+			require.Contains(t, content, "\tgo build -o 'bin/my app' ./...\n")
+			require.Contains(t, content, "test-unit:\n\tcd services/api && echo $$HOME\n")
+			require.NotContains(t, content, "Debug")
+		})
+
+		t.Run("should quote paths under roots with spaces and parentheses", func(t *testing.T) {
+			root := filepath.Join(t.TempDir(), "app (fork)")
+			script := filepath.Join(root, "scripts", "build.sh")
+			require.NoError(t, os.MkdirAll(filepath.Dir(script), 0755))
+			require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"), 0755))
+
+			tasks := []*config.Task{
+				{Name: "build", Type: config.TypeVSCodeTask, Execution: config.ExecutionShell, Command: script, Args: []string{filepath.Join(root, "out (1)")}},
+				{Name: "tool", Type: config.TypeVSCodeTask, Execution: config.ExecutionProcess, Command: filepath.Join(root, "bin", "my tool")},
+			}
+
+			outputPath := filepath.Join(t.TempDir(), "Makefile")
+
+			converter := NewVSCodeToMakefileConverter(root, outputPath, false, nil)
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+
+			data, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			require.Contains(t, string(data), "build:\n\t'"+script+"' '"+filepath.Join(root, "out (1)")+"'\n")
+			require.Contains(t, string(data), "tool:\n\t'"+filepath.Join(root, "bin", "my tool")+"'\n")
+		})
+
+		t.Run("should reject line breaks a recipe cannot hold", func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "Makefile")
+			tasks := []*config.Task{{Name: "build", Type: config.TypeVSCodeTask, Command: "make", Cwd: filepath.Join(projectRoot, "a\nb")}}
+
+			converter := NewVSCodeToMakefileConverter(projectRoot, outputPath, false, nil)
+			err := converter.ConvertTasks(tasks, false)
+			require.ErrorContains(t, err, "task 'build' cannot be converted to a Makefile target")
+			require.ErrorContains(t, err, "line break")
+			require.NoFileExists(t, outputPath)
+		})
+
+		t.Run("should not write files in dry run", func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "Makefile")
+			tasks := []*config.Task{{Name: "build", Type: config.TypeVSCodeTask, Command: "make"}}
+
+			converter := NewVSCodeToMakefileConverter(projectRoot, outputPath, false, nil)
+			require.NoError(t, converter.ConvertTasks(tasks, true))
+			require.NoFileExists(t, outputPath)
+		})
+
+		t.Run("should refuse to overwrite a hand-written Makefile", func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "Makefile")
+			require.NoError(t, os.WriteFile(outputPath, []byte("all:\n\techo hi\n"), 0o600))
+
+			tasks := []*config.Task{{Name: "build", Type: config.TypeVSCodeTask, Command: "make"}}
+
+			converter := NewVSCodeToMakefileConverter(projectRoot, outputPath, false, nil)
+			require.ErrorIs(t, converter.ConvertTasks(tasks, false), errNotGenerated)
+
+			converter.SetOverwriteGuard(&OverwriteGuard{Force: true})
+			require.NoError(t, converter.ConvertTasks(tasks, false))
+		})
+	})
+
+	t.Run("sanitizeMakeTarget", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			expected string
+		}{
+			{"build", "build"},
+			{"Run Tests", "Run-Tests"},
+			{"npm: install", "npm-install"},
+			{"deploy (prod) $ENV", "deploy-prod-ENV"},
+			{"  ", "task"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require.Equal(t, tt.expected, sanitizeMakeTarget(tt.name))
+			})
+		}
+	})
+
+	t.Run("should disambiguate colliding target names", func(t *testing.T) {
+		converter := NewVSCodeToMakefileConverter(projectRoot, "", false, nil)
+		content, _ := converter.generateMakefile([]*config.Task{
+			{Name: "run tests", Command: "a"},
+			{Name: "run:tests", Command: "b"},
+		}, "")
+
+		require.Contains(t, content, ".PHONY: run-tests run-tests-2\n")
+		require.Contains(t, content, "# run:tests\nrun-tests-2:\n\tb\n")
+	})
+
+	t.Run("should run the dependencies of aggregate tasks", func(t *testing.T) {
+		converter := NewVSCodeToMakefileConverter(projectRoot, "", false, nil)
+		content, _ := converter.generateMakefile([]*config.Task{
+			{Name: "lint", Command: "golangci-lint", Args: []string{"run"}},
+			{Name: "unit tests", Command: "go", Args: []string{"test", "./..."}},
+			{Name: "check", DependsOn: []string{"lint", "unit tests"}},
+			{Name: "ci", DependsOn: []string{"lint", "unit tests"}, DependsOrder: config.DependsOrderSequence},
+		}, "")
+
+		require.Contains(t, content, "\ncheck: lint unit-tests\n")
+		require.Contains(t, content, "\nci:\n\t@$(MAKE) --no-print-directory lint\n\t@$(MAKE) --no-print-directory unit-tests\n")
+	})
+
+	t.Run("should run the dependencies of tasks with a command before it", func(t *testing.T) {
+		converter := NewVSCodeToMakefileConverter(projectRoot, "", false, nil)
+		content, entries := converter.generateMakefile([]*config.Task{
+			{Name: "build", Command: "go", Args: []string{"build", "./..."}},
+			{Name: "generate", Command: "go", Args: []string{"generate", "./..."}},
+			{Name: "test", Command: "go", Args: []string{"test", "./..."}, DependsOn: []string{"build"}},
+			{Name: "release", Command: "goreleaser", DependsOn: []string{"generate", "build"}, DependsOrder: config.DependsOrderSequence},
+		}, "")
+
+		require.Contains(t, content, "\ntest: build\n\tgo test ./...\n")
+		require.Contains(t, content, "\nrelease:\n\t@$(MAKE) --no-print-directory generate\n\t@$(MAKE) --no-print-directory build\n\tgoreleaser\n")
+
+		for _, entry := range entries {
+			require.Empty(t, entry.DroppedFields, entry.SourceName)
+		}
+	})
+
+	t.Run("should unset the variables a task removes in its recipe", func(t *testing.T) {
+		converter := NewVSCodeToMakefileConverter(projectRoot, "", false, nil)
+		content, entries := converter.generateMakefile([]*config.Task{
+			{Name: "build", Command: "go", Args: []string{"build"}, Cwd: filepath.Join(projectRoot, "cmd"), UnsetEnv: []string{"GOFLAGS", "GOOS"}},
+		}, "")
+
+		require.Contains(t, content, "\nbuild:\n\tcd cmd && unset GOFLAGS GOOS && go build\n")
+		require.Empty(t, entries[0].DroppedFields)
+	})
+}
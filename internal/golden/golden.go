@@ -0,0 +1,141 @@
+// Package golden compares test output against snapshot files in the testdata/golden
+// directory of the package under test.
+//
+// Run the tests with -update (go test ./internal/converter -update) or UPDATE_GOLDEN=1
+// to rewrite the snapshots from the actual output instead, then review them with git diff.
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Dir holds the golden files, relative to the package under test
+var Dir = filepath.Join("testdata", "golden")
+
+var update = flag.Bool("update", false, "rewrite golden files with the actual output")
+
+// Updating reports whether golden files are being rewritten rather than compared
+func Updating() bool {
+	if *update {
+		return true
+	}
+
+	enabled, _ := strconv.ParseBool(os.Getenv("UPDATE_GOLDEN"))
+
+	return enabled
+}
+
+// Assert compares got with the golden file name, a slash-separated path inside Dir. Both
+// are normalized first, so formatting that carries no meaning never fails a comparison.
+func Assert(t testing.TB, got []byte, name string) {
+	t.Helper()
+
+	path := filepath.Join(Dir, filepath.FromSlash(name))
+	actual := Normalize(got, name)
+
+	if Updating() {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, actual, 0644), "failed to write golden file %s", path)
+		t.Logf("updated golden file %s", path)
+
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist; run the test with -update or UPDATE_GOLDEN=1 to create it. Actual output:\n%s", path, actual)
+	}
+
+	require.NoError(t, err, "failed to read golden file %s", path)
+	require.Equal(t, string(Normalize(expected, name)), string(actual),
+		"output differs from golden file %s; if the change is intended, run the test with -update or UPDATE_GOLDEN=1", path)
+}
+
+// DirPlaceholder replaces the snapshotted directory in AssertDir snapshots
+const DirPlaceholder = "<dir>"
+
+// AssertDir compares every file below dir with the golden file name, as one snapshot
+// listing the files in path order, each under a "==> path <==" header. Outputs usually
+// embed their directory, which differs between runs, so it is replaced with DirPlaceholder.
+func AssertDir(t testing.TB, dir, name string) {
+	t.Helper()
+
+	var paths []string
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		paths = append(paths, path)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	sort.Strings(paths)
+
+	var snapshot bytes.Buffer
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		rel, err := filepath.Rel(dir, path)
+		require.NoError(t, err)
+
+		snapshot.WriteString("==> " + filepath.ToSlash(rel) + " <==\n")
+		snapshot.Write(bytes.ReplaceAll(Normalize(data, path), []byte(dir), []byte(DirPlaceholder)))
+		snapshot.WriteString("\n")
+	}
+
+	Assert(t, snapshot.Bytes(), name)
+}
+
+// Normalize applies the rules every snapshot is compared under:
+//   - line endings are LF and the content ends in exactly one newline
+//   - JSON (by the .json extension of name) is re-indented with two spaces. Key order is
+//     kept: encoding/json writes struct fields in declaration order and map keys sorted,
+//     so it is already stable, and a changed field order is a change worth reviewing.
+//     Leading // comment lines, such as the provenance header of JSONC files, are kept.
+func Normalize(data []byte, name string) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		data = indentJSON(data)
+	}
+
+	return append(bytes.TrimRight(data, "\n"), '\n')
+}
+
+// indentJSON re-indents JSON after any leading comment lines, leaving invalid JSON as-is
+// so the comparison shows it
+func indentJSON(data []byte) []byte {
+	var header []byte
+
+	body := data
+	for bytes.HasPrefix(bytes.TrimLeft(body, " \t"), []byte("//")) {
+		line, rest, _ := bytes.Cut(body, []byte("\n"))
+		header = append(append(header, line...), '\n')
+		body = rest
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSpace(body), "", "  "); err != nil {
+		return data
+	}
+
+	return append(header, indented.Bytes()...)
+}
@@ -0,0 +1,63 @@
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Run("should end text in exactly one newline with LF line endings", func(t *testing.T) {
+		require.Equal(t, "a\nb\n", string(Normalize([]byte("a\r\nb"), "out.txt")))
+		require.Equal(t, "a\n", string(Normalize([]byte("a\n\n\n"), "out.xml")))
+	})
+
+	t.Run("should re-indent JSON keeping key order and comment headers", func(t *testing.T) {
+		input := "// Generated by taskporter\n{\n    \"b\": 1,\n    \"a\": [true]\n}"
+
+		require.Equal(t, "// Generated by taskporter\n{\n  \"b\": 1,\n  \"a\": [\n    true\n  ]\n}\n", string(Normalize([]byte(input), "tasks.json")))
+	})
+
+	t.Run("should leave invalid JSON as-is", func(t *testing.T) {
+		require.Equal(t, "{oops\n", string(Normalize([]byte("{oops"), "tasks.json")))
+	})
+}
+
+func TestAssert(t *testing.T) {
+	t.Setenv("UPDATE_GOLDEN", "")
+
+	previous := Dir
+	Dir = t.TempDir()
+
+	t.Cleanup(func() { Dir = previous })
+
+	t.Run("should compare normalized content", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(Dir, "config.json"), []byte("{\"a\": 1}"), 0644))
+
+		Assert(t, []byte("{\n    \"a\": 1\n}\n"), "config.json")
+	})
+
+	t.Run("should rewrite golden files when updating", func(t *testing.T) {
+		t.Setenv("UPDATE_GOLDEN", "1")
+		require.True(t, Updating())
+
+		Assert(t, []byte("fresh"), "nested/new.txt")
+
+		data, err := os.ReadFile(filepath.Join(Dir, "nested", "new.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "fresh\n", string(data))
+	})
+
+	t.Run("should snapshot directories in path order without their location", func(t *testing.T) {
+		output := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(output, "sub"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(output, "sub", "b.txt"), []byte("in "+output), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(output, "a.txt"), []byte("first"), 0644))
+
+		require.NoError(t, os.WriteFile(filepath.Join(Dir, "dir.txt"), []byte("==> a.txt <==\nfirst\n\n==> sub/b.txt <==\nin <dir>\n"), 0644))
+
+		AssertDir(t, output, "dir.txt")
+	})
+}
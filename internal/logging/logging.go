@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Attribute keys shared by all diagnostic events
+const (
+	KeyComponent = "component"
+	KeyFile      = "file"
+	KeyTask      = "task"
+)
+
+// Log formats accepted by --log-format
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Levels lists the names accepted by --log-level, from most to least verbose
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// ParseLevel converts a --log-level name to a slog level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level '%s'. Valid options: %s", name, strings.Join(Levels, ", "))
+	}
+}
+
+// New creates a logger writing events at or above level to w in the given format.
+// Text output omits timestamps to stay readable next to regular command output.
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	switch format {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+			Level: level,
+			ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+				if len(groups) == 0 && attr.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+
+				return attr
+			},
+		})), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})), nil
+	default:
+		return nil, fmt.Errorf("invalid log format '%s'. Valid options: %s, %s", format, FormatText, FormatJSON)
+	}
+}
+
+// Discard returns a logger that drops every event
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// OrDiscard returns logger, or a discarding logger when it is nil, so constructors can accept nil
+func OrDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return Discard()
+	}
+
+	return logger
+}
+
+// Entry is a log event captured by a Recorder
+type Entry struct {
+	Level   slog.Level
+	Message string
+	Attrs   map[string]string
+}
+
+// Recorder is a slog.Handler that keeps every event in memory so tests can assert on diagnostics
+type Recorder struct {
+	mu      *sync.Mutex
+	entries *[]Entry
+	attrs   []slog.Attr
+}
+
+// NewRecorder creates a Recorder and a logger that writes to it
+func NewRecorder() (*Recorder, *slog.Logger) {
+	recorder := &Recorder{
+		mu:      &sync.Mutex{},
+		entries: &[]Entry{},
+	}
+
+	return recorder, slog.New(recorder)
+}
+
+// Entries returns a copy of the captured events
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Entry(nil), *r.entries...)
+}
+
+// Enabled records every level
+func (r *Recorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle captures the event with its attributes flattened to strings
+func (r *Recorder) Handle(_ context.Context, record slog.Record) error {
+	entry := Entry{
+		Level:   record.Level,
+		Message: record.Message,
+		Attrs:   make(map[string]string),
+	}
+
+	for _, attr := range r.attrs {
+		entry.Attrs[attr.Key] = attr.Value.String()
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		entry.Attrs[attr.Key] = attr.Value.String()
+		return true
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	*r.entries = append(*r.entries, entry)
+
+	return nil
+}
+
+// WithAttrs returns a handler sharing the same captured events with extra attributes
+func (r *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Recorder{
+		mu:      r.mu,
+		entries: r.entries,
+		attrs:   append(append([]slog.Attr(nil), r.attrs...), attrs...),
+	}
+}
+
+// WithGroup is not used by taskporter; groups are flattened
+func (r *Recorder) WithGroup(string) slog.Handler {
+	return r
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"info", slog.LevelInfo},
+		{"warn", slog.LevelWarn},
+		{"WARNING", slog.LevelWarn},
+		{"error", slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := ParseLevel(tt.name)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, level)
+		})
+	}
+
+	t.Run("should reject unknown levels", func(t *testing.T) {
+		_, err := ParseLevel("trace")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "debug, info, warn, error")
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("text format omits timestamps", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger, err := New(&buf, slog.LevelWarn, FormatText)
+		require.NoError(t, err)
+
+		logger.Info("hidden")
+		logger.Warn("skipping task", KeyComponent, "vscode-tasks", KeyTask, "build")
+
+		require.Equal(t, "level=WARN msg=\"skipping task\" component=vscode-tasks task=build\n", buf.String())
+	})
+
+	t.Run("json format emits one object per event", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger, err := New(&buf, slog.LevelDebug, FormatJSON)
+		require.NoError(t, err)
+
+		logger.With(KeyComponent, "jetbrains").Debug("parsed configuration", KeyFile, "app.xml")
+
+		var event map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &event))
+		require.Equal(t, "DEBUG", event["level"])
+		require.Equal(t, "parsed configuration", event["msg"])
+		require.Equal(t, "jetbrains", event[KeyComponent])
+		require.Equal(t, "app.xml", event[KeyFile])
+		require.Contains(t, event, "time")
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		_, err := New(&bytes.Buffer{}, slog.LevelInfo, "xml")
+		require.Error(t, err)
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	recorder, logger := NewRecorder()
+
+	logger.With(KeyComponent, "runner").Warn("task failed", KeyTask, "test", "exit_code", 2)
+	logger.Debug("done")
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 2)
+	require.Equal(t, slog.LevelWarn, entries[0].Level)
+	require.Equal(t, "task failed", entries[0].Message)
+	require.Equal(t, map[string]string{KeyComponent: "runner", KeyTask: "test", "exit_code": "2"}, entries[0].Attrs)
+	require.Equal(t, "done", entries[1].Message)
+	require.Empty(t, entries[1].Attrs)
+
+	require.NotNil(t, OrDiscard(nil))
+	require.Same(t, logger, OrDiscard(logger))
+}
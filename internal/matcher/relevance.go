@@ -0,0 +1,165 @@
+package matcher
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LevenshteinDistance calculates the edit distance between two strings
+func LevenshteinDistance(s1, s2 string) int {
+	if len(s1) == 0 {
+		return len(s2)
+	}
+
+	if len(s2) == 0 {
+		return len(s1)
+	}
+
+	// Only the previous row of the matrix is needed to fill the next one
+	prev := make([]int, len(s2)+1)
+	curr := make([]int, len(s2)+1)
+
+	for j := 0; j <= len(s2); j++ {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s1); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(s2); j++ {
+			cost := 0
+			if s1[i-1] != s2[j-1] {
+				cost = 1
+			}
+
+			curr[j] = min(
+				prev[j]+1,      // deletion
+				curr[j-1]+1,    // insertion
+				prev[j-1]+cost, // substitution
+			)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(s2)]
+}
+
+// RelevanceScore calculates a relevance score (0-1) for a candidate name against a query
+func RelevanceScore(query, name string) float64 {
+	score, _ := ScoreWithPositions(query, name)
+	return score
+}
+
+// ScoreWithPositions calculates the relevance score like RelevanceScore and also returns the
+// rune indexes in name of the characters that matched the query, in ascending order, so
+// callers can highlight why a name matched. Positions are nil when the score is 0.
+func ScoreWithPositions(query, name string) (float64, []int) {
+	if query == "" {
+		return 1.0, nil // All names are equally relevant for empty query
+	}
+
+	queryLower := strings.ToLower(query)
+	nameLower := strings.ToLower(name)
+
+	// Exact match gets the highest score
+	if queryLower == nameLower {
+		return 1.0, runeRange(0, utf8.RuneCountInString(name))
+	}
+
+	// Exact substring match gets very high score
+	if i := strings.Index(nameLower, queryLower); i >= 0 {
+		// Score based on how much of the name the query represents
+		start := utf8.RuneCountInString(nameLower[:i])
+
+		return 0.9 * (float64(len(queryLower)) / float64(len(nameLower))), runeRange(start, start+utf8.RuneCountInString(queryLower))
+	}
+
+	// For other cases, use Levenshtein distance. It is at least the difference in length, so
+	// strings more than twice as long as each other can never reach the threshold below.
+	maxLen := max(len(queryLower), len(nameLower))
+	if 2*min(len(queryLower), len(nameLower)) < maxLen {
+		return 0.0, nil
+	}
+
+	distance := LevenshteinDistance(queryLower, nameLower)
+
+	if distance > maxLen {
+		return 0.0, nil // Too different
+	}
+
+	// Convert distance to similarity score (0-1)
+	similarity := 1.0 - (float64(distance) / float64(maxLen))
+
+	// Apply threshold - only return matches with reasonable similarity
+	if similarity < 0.5 {
+		return 0.0, nil
+	}
+
+	return similarity * 0.8, alignedPositions([]rune(queryLower), []rune(nameLower)) // Cap at 0.8 to prioritize exact/substring matches
+}
+
+// runeRange returns the indexes from start up to, but not including, end
+func runeRange(start, end int) []int {
+	positions := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		positions = append(positions, i)
+	}
+
+	return positions
+}
+
+// alignedPositions returns the indexes in name of the characters an optimal edit
+// script from query to name keeps unchanged
+func alignedPositions(query, name []rune) []int {
+	// Edit distances between the suffixes query[i:] and name[j:], so the script can be read front to back
+	matrix := make([][]int, len(query)+1)
+	for i := range matrix {
+		matrix[i] = make([]int, len(name)+1)
+		matrix[i][len(name)] = len(query) - i
+	}
+
+	for j := 0; j <= len(name); j++ {
+		matrix[len(query)][j] = len(name) - j
+	}
+
+	for i := len(query) - 1; i >= 0; i-- {
+		for j := len(name) - 1; j >= 0; j-- {
+			cost := 0
+			if query[i] != name[j] {
+				cost = 1
+			}
+
+			matrix[i][j] = min(
+				matrix[i+1][j]+1,      // deletion
+				matrix[i][j+1]+1,      // insertion
+				matrix[i+1][j+1]+cost, // substitution
+			)
+		}
+	}
+
+	var positions []int
+
+	for i, j := 0, 0; i < len(query) && j < len(name); {
+		switch {
+		case query[i] == name[j] && matrix[i][j] == matrix[i+1][j+1]:
+			positions = append(positions, j)
+			i++
+			j++
+		case matrix[i][j] == matrix[i+1][j+1]+1:
+			i++
+			j++
+		case matrix[i][j] == matrix[i][j+1]+1:
+			j++
+		default:
+			i++
+		}
+	}
+
+	return positions
+}
+
+// FuzzyMatch reports whether a name is a plausible match for the query
+func FuzzyMatch(query string, name string) bool {
+	return RelevanceScore(query, name) > 0.0
+}
@@ -0,0 +1,261 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		s1   string
+		s2   string
+		want int
+	}{
+		{
+			name: "empty strings",
+			s1:   "",
+			s2:   "",
+			want: 0,
+		},
+		{
+			name: "empty first string",
+			s1:   "",
+			s2:   "abc",
+			want: 3,
+		},
+		{
+			name: "empty second string",
+			s1:   "abc",
+			s2:   "",
+			want: 3,
+		},
+		{
+			name: "identical strings",
+			s1:   "test",
+			s2:   "test",
+			want: 0,
+		},
+		{
+			name: "single character difference",
+			s1:   "test",
+			s2:   "best",
+			want: 1,
+		},
+		{
+			name: "single insertion",
+			s1:   "test",
+			s2:   "tests",
+			want: 1,
+		},
+		{
+			name: "single deletion",
+			s1:   "tests",
+			s2:   "test",
+			want: 1,
+		},
+		{
+			name: "multiple operations",
+			s1:   "kitten",
+			s2:   "sitting",
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LevenshteinDistance(tt.s1, tt.s2)
+			require.Equal(t, tt.want, got, "LevenshteinDistance(%q, %q)", tt.s1, tt.s2)
+		})
+	}
+}
+
+func TestRelevanceScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		taskName string
+		want     float64
+		minScore float64 // minimum expected score for ranges
+	}{
+		{
+			name:     "empty query",
+			query:    "",
+			taskName: "build",
+			want:     1.0,
+		},
+		{
+			name:     "exact match",
+			query:    "build",
+			taskName: "build",
+			want:     1.0,
+		},
+		{
+			name:     "case insensitive exact match",
+			query:    "BUILD",
+			taskName: "build",
+			want:     1.0,
+		},
+		{
+			name:     "substring match",
+			query:    "uild",
+			taskName: "build",
+			minScore: 0.7, // Should be high score for substring
+		},
+		{
+			name:     "partial substring match",
+			query:    "test",
+			taskName: "run:test:unit",
+			minScore: 0.25, // Score based on substring length ratio
+		},
+		{
+			name:     "similar strings",
+			query:    "tset", // typo of "test"
+			taskName: "test",
+			minScore: 0.4, // Should match with reasonable score (0.5 * 0.8 = 0.4)
+		},
+		{
+			name:     "very different strings",
+			query:    "xyz",
+			taskName: "build",
+			want:     0.0, // Should not match
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RelevanceScore(tt.query, tt.taskName)
+			if tt.want > 0 {
+				require.Equal(t, tt.want, got, "RelevanceScore(%q, %q)", tt.query, tt.taskName)
+			} else {
+				require.GreaterOrEqual(t, got, tt.minScore, "RelevanceScore(%q, %q) should be >= %f, got %f", tt.query, tt.taskName, tt.minScore, got)
+			}
+		})
+	}
+}
+
+func TestScoreWithPositions(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		taskName      string
+		wantPositions []int
+	}{
+		{
+			name:          "empty query highlights nothing",
+			query:         "",
+			taskName:      "build",
+			wantPositions: nil,
+		},
+		{
+			name:          "exact match highlights the whole name",
+			query:         "BUILD",
+			taskName:      "build",
+			wantPositions: []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:          "substring match highlights the substring",
+			query:         "test",
+			taskName:      "run:test:unit",
+			wantPositions: []int{4, 5, 6, 7},
+		},
+		{
+			name:          "substring positions count runes, not bytes",
+			query:         "build",
+			taskName:      "ünit-build",
+			wantPositions: []int{5, 6, 7, 8, 9},
+		},
+		{
+			name:          "typo highlights the characters kept in place",
+			query:         "biuld",
+			taskName:      "build",
+			wantPositions: []int{0, 3, 4},
+		},
+		{
+			name:          "missing character highlights the rest",
+			query:         "buld",
+			taskName:      "build",
+			wantPositions: []int{0, 1, 3, 4},
+		},
+		{
+			name:          "no match highlights nothing",
+			query:         "xyz",
+			taskName:      "build",
+			wantPositions: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, positions := ScoreWithPositions(tt.query, tt.taskName)
+			require.Equal(t, RelevanceScore(tt.query, tt.taskName), score, "score should match RelevanceScore")
+			require.Equal(t, tt.wantPositions, positions)
+		})
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		taskName string
+		want     bool
+	}{
+		{
+			name:     "empty query matches everything",
+			query:    "",
+			taskName: "build",
+			want:     true,
+		},
+		{
+			name:     "exact match",
+			query:    "build",
+			taskName: "build",
+			want:     true,
+		},
+		{
+			name:     "case insensitive exact match",
+			query:    "BUILD",
+			taskName: "build",
+			want:     true,
+		},
+		{
+			name:     "substring match",
+			query:    "uild",
+			taskName: "build",
+			want:     true,
+		},
+		{
+			name:     "similar strings (typo)",
+			query:    "buil", // missing 'd'
+			taskName: "build",
+			want:     true,
+		},
+		{
+			name:     "very different strings",
+			query:    "xyz",
+			taskName: "build",
+			want:     false,
+		},
+		{
+			name:     "partial match in longer name",
+			query:    "test",
+			taskName: "run:test:unit",
+			want:     true,
+		},
+		{
+			name:     "reasonable typo",
+			query:    "tset", // "test" with swapped characters
+			taskName: "test",
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FuzzyMatch(tt.query, tt.taskName)
+			require.Equal(t, tt.want, got, "FuzzyMatch(%q, %q)", tt.query, tt.taskName)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package githubactions
+
+import "gopkg.in/yaml.v3"
+
+// Workflow represents the parts of a GitHub Actions workflow file that matter for running
+// its steps locally
+type Workflow struct {
+	Name     string            `yaml:"name"`
+	Env      map[string]string `yaml:"env"`
+	Defaults WorkflowDefaults  `yaml:"defaults"`
+	Jobs     yaml.Node         `yaml:"jobs"` // Mapping of job IDs to jobs, kept as a node to preserve their order
+}
+
+// WorkflowDefaults holds the defaults.run settings of a workflow or job
+type WorkflowDefaults struct {
+	Run WorkflowRunDefaults `yaml:"run"`
+}
+
+// WorkflowRunDefaults are the shell and working directory run steps use unless they set their own
+type WorkflowRunDefaults struct {
+	Shell            string `yaml:"shell"`
+	WorkingDirectory string `yaml:"working-directory"`
+}
+
+// WorkflowJob represents a job of a workflow
+type WorkflowJob struct {
+	Name     string            `yaml:"name"`
+	Env      map[string]string `yaml:"env"`
+	Defaults WorkflowDefaults  `yaml:"defaults"`
+	Steps    []WorkflowStep    `yaml:"steps"`
+}
+
+// WorkflowStep represents a step of a job, either a run: script or a uses: action
+type WorkflowStep struct {
+	ID               string            `yaml:"id"`
+	Name             string            `yaml:"name"`
+	Run              string            `yaml:"run"`
+	Uses             string            `yaml:"uses"`
+	Shell            string            `yaml:"shell"`
+	WorkingDirectory string            `yaml:"working-directory"`
+	Env              map[string]string `yaml:"env"`
+}
@@ -0,0 +1,273 @@
+package githubactions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workspaceExpressionPattern matches ${{ github.workspace }}, the only expression that has a
+// local equivalent. Other expressions depend on the workflow run and are kept verbatim.
+var workspaceExpressionPattern = regexp.MustCompile(`\$\{\{\s*github\.workspace\s*\}\}`)
+
+// maxStepLabelLength is how much of a run: script names a step that has no name or id
+const maxStepLabelLength = 40
+
+// WorkflowParser extracts the run: steps of GitHub Actions workflows as tasks, so CI steps
+// can be reproduced locally. uses: steps run actions that only exist on GitHub and are skipped.
+type WorkflowParser struct {
+	ctx         context.Context
+	projectRoot string
+	logger      *slog.Logger
+}
+
+// NewWorkflowParser creates a new GitHub Actions workflow parser
+func NewWorkflowParser(projectRoot string, logger *slog.Logger) *WorkflowParser {
+	return &WorkflowParser{
+		ctx:         context.Background(),
+		projectRoot: projectRoot,
+		logger:      logging.OrDiscard(logger).With(logging.KeyComponent, "github-actions"),
+	}
+}
+
+// SetContext bounds the parser's file reads by ctx, e.g. an --op-timeout deadline
+func (p *WorkflowParser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// ParseWorkflow parses a workflow file and returns a task for each run: step, named
+// "<workflow>/<job>: <step>", plus a "<workflow>/<job>" task running the job's steps in order
+func (p *WorkflowParser) ParseWorkflow(workflowPath string) ([]*config.Task, error) {
+	data, err := config.ReadFileContext(p.ctx, workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow file %s: %w", workflowPath, err)
+	}
+
+	var workflow Workflow
+	if err := yaml.Unmarshal(data, &workflow); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file %s: %w", workflowPath, err)
+	}
+
+	if workflow.Jobs.Kind != 0 && workflow.Jobs.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse workflow file %s: jobs must be a mapping", workflowPath)
+	}
+
+	prefix := strings.TrimSuffix(filepath.Base(workflowPath), filepath.Ext(workflowPath))
+
+	var tasks []*config.Task
+
+	// Mapping nodes alternate keys and values
+	for i := 0; i+1 < len(workflow.Jobs.Content); i += 2 {
+		jobID := workflow.Jobs.Content[i].Value
+
+		var job WorkflowJob
+		if err := workflow.Jobs.Content[i+1].Decode(&job); err != nil {
+			p.logger.Warn("failed to parse workflow job", logging.KeyFile, workflowPath, "job", jobID, "error", err)
+			continue
+		}
+
+		tasks = append(tasks, p.convertJob(workflow, jobID, job, prefix+"/"+jobID, workflowPath)...)
+	}
+
+	p.logger.Debug("parsed workflow file", logging.KeyFile, workflowPath, "tasks", len(tasks))
+
+	return tasks, nil
+}
+
+// convertJob converts the run: steps of a job, followed by the task running all of them
+func (p *WorkflowParser) convertJob(workflow Workflow, jobID string, job WorkflowJob, jobName, sourceFile string) []*config.Task {
+	var (
+		tasks   []*config.Task
+		skipped int
+	)
+
+	for i, step := range job.Steps {
+		if step.Run == "" {
+			p.logger.Debug("skipping step without run", logging.KeyFile, sourceFile, "job", jobID, "uses", step.Uses)
+
+			skipped++
+
+			continue
+		}
+
+		task, err := p.convertStep(workflow, job, step, sourceFile)
+		if err != nil {
+			p.logger.Warn("failed to convert workflow step", logging.KeyFile, sourceFile, "job", jobID, "step", i+1, "error", err)
+
+			skipped++
+
+			continue
+		}
+
+		task.Name = uniqueStepName(tasks, jobName+": "+stepLabel(step, i))
+		task.Description = fmt.Sprintf("GitHub Actions step from %s (job %s)", filepath.Base(sourceFile), jobID)
+		tasks = append(tasks, task)
+	}
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	jobTask := &config.Task{
+		Name:         jobName,
+		Type:         config.TypeGitHubActions,
+		DependsOrder: config.DependsOrderSequence,
+		Description:  fmt.Sprintf("Runs the run: steps of job %s from %s in order", jobID, filepath.Base(sourceFile)),
+		Source:       sourceFile,
+	}
+
+	if skipped > 0 {
+		jobTask.Description += fmt.Sprintf(" (%d other steps skipped)", skipped)
+	}
+
+	for _, task := range tasks {
+		jobTask.DependsOn = append(jobTask.DependsOn, task.Name)
+	}
+
+	return append(tasks, jobTask)
+}
+
+// convertStep converts a run: step, applying the workflow and job env and defaults under its own
+func (p *WorkflowParser) convertStep(workflow Workflow, job WorkflowJob, step WorkflowStep, sourceFile string) (*config.Task, error) {
+	shell := firstNonEmpty(step.Shell, job.Defaults.Run.Shell, workflow.Defaults.Run.Shell)
+
+	shellPath, preamble, err := stepShell(shell)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &config.Task{
+		Type:    config.TypeGitHubActions,
+		Command: preamble + p.resolveExpressions(strings.TrimRight(step.Run, "\n")),
+		Shell:   shellPath,
+		Cwd:     p.workingDirectory(firstNonEmpty(step.WorkingDirectory, job.Defaults.Run.WorkingDirectory, workflow.Defaults.Run.WorkingDirectory)),
+		Source:  sourceFile,
+	}
+
+	// Step env wins over job env, which wins over workflow env
+	for _, env := range []map[string]string{workflow.Env, job.Env, step.Env} {
+		for key, value := range env {
+			if task.Env == nil {
+				task.Env = make(map[string]string)
+			}
+
+			task.Env[key] = p.resolveExpressions(value)
+		}
+	}
+
+	return task, nil
+}
+
+// stepShell returns the shell a step's script runs with and the preamble that makes it stop at
+// the first failing command, the way GitHub invokes its built-in shells
+func stepShell(shell string) (string, string, error) {
+	switch shell {
+	case "":
+		return "bash", "set -e; ", nil
+	case "bash":
+		return "bash", "set -eo pipefail; ", nil
+	case "sh":
+		return "sh", "set -e; ", nil
+	case "pwsh", "powershell":
+		return shell, "$ErrorActionPreference = 'stop'; ", nil
+	case "cmd":
+		return "cmd.exe", "", nil
+	default:
+		return "", "", fmt.Errorf("unsupported shell: %s", shell)
+	}
+}
+
+// workingDirectory resolves a working-directory, which is relative to the repository root
+func (p *WorkflowParser) workingDirectory(dir string) string {
+	root, err := filepath.Abs(p.projectRoot)
+	if err != nil {
+		root = p.projectRoot
+	}
+
+	dir = p.resolveExpressions(dir)
+
+	if dir == "" {
+		return root
+	}
+
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+
+	return filepath.Join(root, dir)
+}
+
+// resolveExpressions replaces ${{ github.workspace }} with the project root
+func (p *WorkflowParser) resolveExpressions(value string) string {
+	if !strings.Contains(value, "${{") {
+		return value
+	}
+
+	root, err := filepath.Abs(p.projectRoot)
+	if err != nil {
+		root = p.projectRoot
+	}
+
+	return workspaceExpressionPattern.ReplaceAllLiteralString(value, root)
+}
+
+// stepLabel names a step by its name, its id or the first line of its script
+func stepLabel(step WorkflowStep, index int) string {
+	if step.Name != "" {
+		return step.Name
+	}
+
+	if step.ID != "" {
+		return step.ID
+	}
+
+	line, _, _ := strings.Cut(strings.TrimSpace(step.Run), "\n")
+	if line == "" {
+		return fmt.Sprintf("step %d", index+1)
+	}
+
+	if runes := []rune(line); len(runes) > maxStepLabelLength {
+		line = string(runes[:maxStepLabelLength]) + "…"
+	}
+
+	return line
+}
+
+// uniqueStepName numbers a step name that an earlier step of the job already uses
+func uniqueStepName(tasks []*config.Task, name string) string {
+	taken := func(candidate string) bool {
+		for _, task := range tasks {
+			if task.Name == candidate {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	unique := name
+	for n := 2; taken(unique); n++ {
+		unique = fmt.Sprintf("%s (%d)", name, n)
+	}
+
+	return unique
+}
+
+// firstNonEmpty returns the first of values that is set
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,110 @@
+package githubactions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowParser(t *testing.T) {
+	t.Run("NewWorkflowParser", func(t *testing.T) {
+		parser := NewWorkflowParser("/test/project", nil)
+		require.NotNil(t, parser)
+		require.Equal(t, "/test/project", parser.projectRoot)
+	})
+
+	t.Run("ParseWorkflow", func(t *testing.T) {
+		workflowFile := filepath.Join("testdata", "ci.yml")
+
+		projectDir, err := filepath.Abs("testdata")
+		require.NoError(t, err)
+
+		parser := NewWorkflowParser(projectDir, nil)
+		tasks, err := parser.ParseWorkflow(workflowFile)
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+
+		// Jobs keep their order in the file; uses: steps and the python step are skipped
+		require.Equal(t, []string{
+			"ci/test: Run tests",
+			"ci/test: go vet ./...",
+			"ci/test: Run tests (2)",
+			"ci/test",
+			"ci/web: install",
+			"ci/web: Build into workspace",
+			"ci/web",
+		}, names)
+
+		t.Run("run step", func(t *testing.T) {
+			task := tasks[0]
+			require.Equal(t, config.TypeGitHubActions, task.Type)
+			require.Equal(t, "bash", task.Shell)
+			require.Equal(t, "set -e; go test -race ./...", task.Command)
+			require.Equal(t, projectDir, task.Cwd)
+			require.Equal(t, workflowFile, task.Source)
+			require.Equal(t, "GitHub Actions step from ci.yml (job test)", task.Description)
+		})
+
+		t.Run("step env wins over job and workflow env", func(t *testing.T) {
+			require.Equal(t, map[string]string{
+				"GO_VERSION":  "1.24",
+				"CGO_ENABLED": "1",
+				"GOFLAGS":     "-count=1",
+			}, tasks[0].Env)
+		})
+
+		t.Run("multi-line scripts are named by their first line", func(t *testing.T) {
+			require.Equal(t, "set -e; go vet ./...\ngofmt -l .", tasks[1].Command)
+		})
+
+		t.Run("working-directory and explicit shell", func(t *testing.T) {
+			task := tasks[2]
+			require.Equal(t, filepath.Join(projectDir, "tools"), task.Cwd)
+			require.Equal(t, "set -eo pipefail; go test ./...", task.Command)
+		})
+
+		t.Run("job defaults", func(t *testing.T) {
+			task := tasks[4]
+			require.Equal(t, "sh", task.Shell)
+			require.Equal(t, filepath.Join(projectDir, "web"), task.Cwd)
+		})
+
+		t.Run("github.workspace resolves to the project root", func(t *testing.T) {
+			require.Equal(t, "set -e; npm run build -- --out "+projectDir+"/dist", tasks[5].Command)
+		})
+
+		t.Run("job runs its steps in order", func(t *testing.T) {
+			job := tasks[3]
+			require.True(t, job.IsAggregate())
+			require.Equal(t, []string{"ci/test: Run tests", "ci/test: go vet ./...", "ci/test: Run tests (2)"}, job.DependsOn)
+			require.Equal(t, config.DependsOrderSequence, job.DependsOrder)
+			require.Equal(t, "Runs the run: steps of job test from ci.yml in order (2 other steps skipped)", job.Description)
+		})
+	})
+
+	t.Run("ParseWorkflow errors", func(t *testing.T) {
+		parser := NewWorkflowParser(t.TempDir(), nil)
+
+		_, err := parser.ParseWorkflow(filepath.Join(t.TempDir(), "missing.yml"))
+		require.Error(t, err)
+
+		path := filepath.Join(t.TempDir(), "broken.yml")
+		require.NoError(t, os.WriteFile(path, []byte("jobs: [build"), 0644))
+
+		_, err = parser.ParseWorkflow(path)
+		require.ErrorContains(t, err, "failed to parse workflow file")
+
+		require.NoError(t, os.WriteFile(path, []byte("jobs:\n  - build\n"), 0644))
+
+		_, err = parser.ParseWorkflow(path)
+		require.ErrorContains(t, err, "jobs must be a mapping")
+	})
+}
@@ -5,5 +5,6 @@ import "encoding/xml"
 // JetBrainsConfiguration represents the root element of a JetBrains run configuration XML file
 type JetBrainsConfiguration struct {
 	XMLName       xml.Name                  `xml:"component"`
+	Name          string                    `xml:"name,attr"`
 	Configuration JetBrainsRunConfiguration `xml:"configuration"`
 }
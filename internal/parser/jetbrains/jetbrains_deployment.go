@@ -0,0 +1,10 @@
+package jetbrains
+
+import "encoding/xml"
+
+// JetBrainsDeployment represents the deployment element of Docker run configurations in JetBrains configuration XML
+type JetBrainsDeployment struct {
+	XMLName  xml.Name                     `xml:"deployment"`
+	Type     string                       `xml:"type,attr"`
+	Settings *JetBrainsDeploymentSettings `xml:"settings"`
+}
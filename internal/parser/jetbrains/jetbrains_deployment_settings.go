@@ -0,0 +1,9 @@
+package jetbrains
+
+import "encoding/xml"
+
+// JetBrainsDeploymentSettings represents the settings of a Docker deployment in JetBrains configuration XML
+type JetBrainsDeploymentSettings struct {
+	XMLName xml.Name          `xml:"settings"`
+	Options []JetBrainsOption `xml:"option"`
+}
@@ -0,0 +1,10 @@
+package jetbrains
+
+import "encoding/xml"
+
+// JetBrainsEnv represents a single env element in JetBrains configuration XML
+type JetBrainsEnv struct {
+	XMLName xml.Name `xml:"env"`
+	Name    string   `xml:"name,attr"`
+	Value   string   `xml:"value,attr"`
+}
@@ -0,0 +1,9 @@
+package jetbrains
+
+import "encoding/xml"
+
+// JetBrainsEnvs represents the envs element used by Python and newer Application configurations
+type JetBrainsEnvs struct {
+	XMLName xml.Name       `xml:"envs"`
+	Envs    []JetBrainsEnv `xml:"env"`
+}
@@ -9,4 +9,8 @@ type JetBrainsOption struct {
 	Value   string         `xml:"value,attr"`
 	Map     *JetBrainsMap  `xml:"map"`
 	List    *JetBrainsList `xml:"list"`
+
+	// Set on before-run steps inside <method>
+	Enabled              string `xml:"enabled,attr"`
+	RunConfigurationName string `xml:"run_configuration_name,attr"`
 }
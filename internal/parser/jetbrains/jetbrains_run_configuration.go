@@ -9,8 +9,11 @@ type JetBrainsRunConfiguration struct {
 	Type                   string                           `xml:"type,attr"`
 	FactoryName            string                           `xml:"factoryName,attr"`
 	Default                string                           `xml:"default,attr"`
+	FolderName             string                           `xml:"folderName,attr"`
 	Options                []JetBrainsOption                `xml:"option"`
 	Module                 *JetBrainsModule                 `xml:"module"`
 	Method                 *JetBrainsMethod                 `xml:"method"`
+	Envs                   *JetBrainsEnvs                   `xml:"envs"`
 	ExternalSystemSettings *JetBrainsExternalSystemSettings `xml:"ExternalSystemSettings"`
+	Deployment             *JetBrainsDeployment             `xml:"deployment"`
 }
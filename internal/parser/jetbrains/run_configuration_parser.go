@@ -1,48 +1,179 @@
 package jetbrains
 
 import (
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/shellwords"
 )
 
 // RunConfigurationParser handles parsing of JetBrains run configuration XML files
 type RunConfigurationParser struct {
+	ctx         context.Context
 	projectRoot string
+	templates   config.RunConfigTemplates
+	logger      *slog.Logger
 }
 
 // NewRunConfigurationParser creates a new JetBrains run configuration parser
-func NewRunConfigurationParser(projectRoot string) *RunConfigurationParser {
+func NewRunConfigurationParser(projectRoot string, logger *slog.Logger) *RunConfigurationParser {
 	return &RunConfigurationParser{
+		ctx:         context.Background(),
 		projectRoot: projectRoot,
+		logger:      logging.OrDiscard(logger).With(logging.KeyComponent, "jetbrains"),
 	}
 }
 
-// ParseRunConfiguration parses a JetBrains run configuration XML file and returns internal Task structure
+// SetContext bounds the parser's file reads by ctx, e.g. an --op-timeout deadline
+func (p *RunConfigurationParser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// SetTemplates sets the run configuration templates whose options and environment variables
+// configurations inherit where they don't set their own, like IntelliJ applies them
+func (p *RunConfigurationParser) SetTemplates(templates config.RunConfigTemplates) {
+	p.templates = templates
+}
+
+// ErrTemplate is reported for run configuration templates. IntelliJ routinely writes them next to
+// run configurations, but they only hold the defaults for new configurations and are never run.
+var ErrTemplate = errors.New("run configuration template")
+
+// ErrUnsupportedType is reported for run configurations of a type taskporter cannot run, such as
+// JUnit or Docker ones
+var ErrUnsupportedType = errors.New("unsupported JetBrains configuration type")
+
+// IsTemplateFile reports whether the file is a run configuration template by its name, e.g.
+// "_template__of_Application.xml"
+func IsTemplateFile(configFilePath string) bool {
+	return config.IsRunConfigTemplateFile(configFilePath)
+}
+
+// ParseRunConfiguration parses a JetBrains run configuration XML file and returns internal Task structure.
+// Templates are not parsed and fail with ErrTemplate.
 func (p *RunConfigurationParser) ParseRunConfiguration(configFilePath string) (*config.Task, error) {
-	data, err := os.ReadFile(configFilePath)
+	if IsTemplateFile(configFilePath) {
+		return nil, fmt.Errorf("skipped %s: %w", configFilePath, ErrTemplate)
+	}
+
+	data, err := config.ReadFileContext(p.ctx, configFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file %s: %w", configFilePath, err)
 	}
 
+	return p.parseRunConfigurationData(data, configFilePath)
+}
+
+// parseRunConfigurationData parses run configuration XML read from configFilePath
+func (p *RunConfigurationParser) parseRunConfigurationData(data []byte, configFilePath string) (*config.Task, error) {
 	var jetbrainsConfig JetBrainsConfiguration
 	if err := xml.Unmarshal(data, &jetbrainsConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
+		// A bare EOF means there was no root element at all
+		if errors.Is(err, io.EOF) {
+			err = errors.New("unexpected end of file: empty or truncated XML")
+		}
+
+		return nil, fmt.Errorf("failed to parse XML in %s: %w", configFilePath, err)
+	}
+
+	if err := checkRunConfiguration(jetbrainsConfig); err != nil {
+		return nil, fmt.Errorf("skipped %s: %w", configFilePath, err)
 	}
 
+	p.applyTemplate(&jetbrainsConfig.Configuration)
+
 	// Convert JetBrains configuration to our internal Task structure
 	task, err := p.convertRunConfiguration(jetbrainsConfig.Configuration, configFilePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert configuration: %w", err)
+		return nil, fmt.Errorf("failed to convert configuration in %s: %w", configFilePath, err)
 	}
 
+	p.logger.Debug("parsed run configuration", logging.KeyFile, configFilePath, logging.KeyTask, task.Name, "type", jetbrainsConfig.Configuration.Type)
+
 	return task, nil
 }
 
+// applyTemplate fills in the options and environment variables a configuration inherits from
+// the template for its type. Anything the configuration sets itself wins, including variables
+// in the older ENV_VARIABLES map.
+func (p *RunConfigurationParser) applyTemplate(configuration *JetBrainsRunConfiguration) {
+	template, ok := p.templates[configuration.Type]
+	if !ok {
+		return
+	}
+
+	set := make(map[string]bool, len(configuration.Options))
+	setEnv := make(map[string]bool)
+
+	for _, option := range configuration.Options {
+		set[option.Name] = true
+
+		if option.Name == "ENV_VARIABLES" && option.Map != nil {
+			for _, entry := range option.Map.Entries {
+				setEnv[entry.Key] = true
+			}
+		}
+	}
+
+	for _, option := range template.Options {
+		if !set[option.Name] {
+			configuration.Options = append(configuration.Options, JetBrainsOption{Name: option.Name, Value: option.Value})
+		}
+	}
+
+	if configuration.Envs != nil {
+		for _, env := range configuration.Envs.Envs {
+			setEnv[env.Name] = true
+		}
+	}
+
+	var inherited []JetBrainsEnv
+
+	for _, env := range template.EnvVars {
+		if !setEnv[env.Name] {
+			inherited = append(inherited, JetBrainsEnv{Name: env.Name, Value: env.Value})
+		}
+	}
+
+	if len(inherited) > 0 {
+		if configuration.Envs == nil {
+			configuration.Envs = &JetBrainsEnvs{}
+		}
+
+		// Inherited variables go first so the configuration's own stay authoritative
+		configuration.Envs.Envs = append(inherited, configuration.Envs.Envs...)
+	}
+
+	p.logger.Debug("applied run configuration template", logging.KeyFile, template.Source, "type", configuration.Type, "configuration", configuration.Name)
+}
+
+// checkRunConfiguration rejects files that hold no runnable configuration, naming the elements it
+// found, since .idea/runConfigurations also collects templates and other IDE state
+func checkRunConfiguration(jetbrainsConfig JetBrainsConfiguration) error {
+	configuration := jetbrainsConfig.Configuration
+
+	switch {
+	case configuration.XMLName.Local == "":
+		return fmt.Errorf("found <component name=%q> without a <configuration> element, not a run configuration", jetbrainsConfig.Name)
+	case configuration.Default == "true":
+		return fmt.Errorf("configuration '%s' in <component name=%q> is a %w (default=\"true\")", configuration.Name, jetbrainsConfig.Name, ErrTemplate)
+	case configuration.Type == "":
+		return fmt.Errorf("configuration '%s' in <component name=%q> has no type attribute", configuration.Name, jetbrainsConfig.Name)
+	}
+
+	return nil
+}
+
 // convertRunConfiguration converts a JetBrains run config to our internal Task structure
 func (p *RunConfigurationParser) convertRunConfiguration(jetbrainsConfig JetBrainsRunConfiguration, sourceFile string) (*config.Task, error) {
 	task := &config.Task{
@@ -50,22 +181,48 @@ func (p *RunConfigurationParser) convertRunConfiguration(jetbrainsConfig JetBrai
 		Type:        config.TypeJetBrains,
 		Source:      sourceFile,
 		Description: fmt.Sprintf("JetBrains %s configuration", jetbrainsConfig.Type),
+		Tags:        config.ParseTags(jetbrainsConfig.FolderName),
 	}
 
 	// Handle different configuration types
 	switch jetbrainsConfig.Type {
-	case "Application":
+	case "Application", "JetRunConfigurationType":
 		if err := p.handleApplicationConfig(jetbrainsConfig, task); err != nil {
 			return nil, err
 		}
+	case "SpringBootApplicationConfigurationType":
+		if err := p.handleSpringBootConfig(jetbrainsConfig, task); err != nil {
+			return nil, err
+		}
 	case "GradleRunConfiguration":
 		if err := p.handleGradleConfig(jetbrainsConfig, task); err != nil {
 			return nil, err
 		}
+	case "PythonConfigurationType":
+		if err := p.handlePythonConfig(jetbrainsConfig, task); err != nil {
+			return nil, err
+		}
+	case "docker-deploy", "DockerComposeDeploymentConfiguration":
+		if err := p.handleDockerComposeConfig(jetbrainsConfig, task); err != nil {
+			return nil, err
+		}
+	case config.ShConfigurationType:
+		if err := p.handleShellScriptConfig(jetbrainsConfig, task); err != nil {
+			return nil, err
+		}
 	default:
-		return nil, fmt.Errorf("unsupported JetBrains configuration type: %s", jetbrainsConfig.Type)
+		return nil, fmt.Errorf("%w: %s (configuration '%s')", ErrUnsupportedType, jetbrainsConfig.Type, jetbrainsConfig.Name)
+	}
+
+	// EXECUTE_IN_TERMINAL marks configurations that need an interactive terminal
+	for _, option := range jetbrainsConfig.Options {
+		if option.Name == "EXECUTE_IN_TERMINAL" && option.Value == "true" {
+			task.Interactive = true
+		}
 	}
 
+	task.BeforeRun = beforeRunConfigurations(jetbrainsConfig.Method)
+
 	// Set default working directory to project root if not specified
 	if task.Cwd == "" {
 		task.Cwd = p.projectRoot
@@ -74,7 +231,27 @@ func (p *RunConfigurationParser) convertRunConfiguration(jetbrainsConfig JetBrai
 	return task, nil
 }
 
-// handleApplicationConfig handles Java Application run configurations
+// beforeRunConfigurations returns the names of the configurations the enabled "Run Another
+// Configuration" before-run steps start. Other steps, such as Make, name no configuration.
+func beforeRunConfigurations(method *JetBrainsMethod) []string {
+	if method == nil {
+		return nil
+	}
+
+	var names []string
+
+	for _, option := range method.Options {
+		if option.Name != "RunConfigurationTask" || option.Enabled == "false" || option.RunConfigurationName == "" {
+			continue
+		}
+
+		names = append(names, option.RunConfigurationName)
+	}
+
+	return names
+}
+
+// handleApplicationConfig handles Java Application and Kotlin run configurations, which share their options
 func (p *RunConfigurationParser) handleApplicationConfig(jetbrainsConfig JetBrainsRunConfiguration, task *config.Task) error {
 	task.Command = "java"
 	task.Group = "run"
@@ -85,13 +262,15 @@ func (p *RunConfigurationParser) handleApplicationConfig(jetbrainsConfig JetBrai
 		programParameters string
 		workingDirectory  string
 		envVars           map[string]string
+		jrePathEnabled    bool
+		jrePath           string
 	)
 
 	// Parse options
 
 	for _, option := range jetbrainsConfig.Options {
 		switch option.Name {
-		case "MAIN_CLASS_NAME":
+		case "MAIN_CLASS_NAME", "SPRING_BOOT_MAIN_CLASS":
 			mainClass = option.Value
 		case "VM_PARAMETERS":
 			vmParameters = option.Value
@@ -99,6 +278,10 @@ func (p *RunConfigurationParser) handleApplicationConfig(jetbrainsConfig JetBrai
 			programParameters = option.Value
 		case "WORKING_DIRECTORY":
 			workingDirectory = option.Value
+		case "ALTERNATIVE_JRE_PATH_ENABLED":
+			jrePathEnabled = option.Value == "true"
+		case "ALTERNATIVE_JRE_PATH":
+			jrePath = option.Value
 		case "ENV_VARIABLES":
 			if option.Map != nil {
 				envVars = make(map[string]string)
@@ -120,7 +303,7 @@ func (p *RunConfigurationParser) handleApplicationConfig(jetbrainsConfig JetBrai
 
 	// Add main class
 	if mainClass == "" {
-		return fmt.Errorf("MAIN_CLASS_NAME is required for Application configuration")
+		return fmt.Errorf("MAIN_CLASS_NAME is required for %s configuration", jetbrainsConfig.Type)
 	}
 
 	args = append(args, mainClass)
@@ -138,15 +321,147 @@ func (p *RunConfigurationParser) handleApplicationConfig(jetbrainsConfig JetBrai
 		task.Cwd = p.resolveJetBrainsPath(workingDirectory)
 	}
 
+	// Newer IDE versions write envs elements instead of the ENV_VARIABLES map
+	if jetbrainsConfig.Envs != nil && len(jetbrainsConfig.Envs.Envs) > 0 {
+		if envVars == nil {
+			envVars = make(map[string]string, len(jetbrainsConfig.Envs.Envs))
+		}
+
+		for _, env := range jetbrainsConfig.Envs.Envs {
+			envVars[env.Name] = env.Value
+		}
+	}
+
 	// Set environment variables
 	if envVars != nil {
 		task.Env = envVars
 	}
 
+	// An alternative JRE is either a JRE home directory or the name of an SDK known only to the IDE
+	if jrePathEnabled && jrePath != "" {
+		if strings.ContainsAny(jrePath, `/\$`) {
+			task.RuntimePath = filepath.Join(p.resolveJetBrainsPath(jrePath), "bin", executableName("java"))
+		} else {
+			p.logger.Debug("alternative JRE names an IDE SDK, using java from PATH", logging.KeyTask, task.Name, "jre", jrePath)
+		}
+	}
+
+	return nil
+}
+
+// handleSpringBootConfig handles Spring Boot run configurations, which run the application class like
+// an Application configuration with the active profiles passed as SPRING_PROFILES_ACTIVE
+func (p *RunConfigurationParser) handleSpringBootConfig(jetbrainsConfig JetBrainsRunConfiguration, task *config.Task) error {
+	if err := p.handleApplicationConfig(jetbrainsConfig, task); err != nil {
+		return err
+	}
+
+	for _, option := range jetbrainsConfig.Options {
+		if option.Name != "ACTIVE_PROFILES" || strings.TrimSpace(option.Value) == "" {
+			continue
+		}
+
+		// An explicitly configured variable wins, as it does when Spring reads the environment
+		if _, ok := task.Env["SPRING_PROFILES_ACTIVE"]; ok {
+			p.logger.Debug("SPRING_PROFILES_ACTIVE is set, ignoring active profiles", logging.KeyTask, task.Name, "profiles", option.Value)
+			continue
+		}
+
+		if task.Env == nil {
+			task.Env = make(map[string]string, 1)
+		}
+
+		task.Env["SPRING_PROFILES_ACTIVE"] = strings.TrimSpace(option.Value)
+	}
+
+	return nil
+}
+
+// handlePythonConfig handles Python run configurations
+func (p *RunConfigurationParser) handlePythonConfig(jetbrainsConfig JetBrainsRunConfiguration, task *config.Task) error {
+	task.Command = "python"
+	task.Group = "run"
+
+	var (
+		scriptName         string
+		parameters         string
+		interpreterOptions string
+		workingDirectory   string
+		sdkHome            string
+		moduleMode         bool
+	)
+
+	for _, option := range jetbrainsConfig.Options {
+		switch option.Name {
+		case "SCRIPT_NAME":
+			scriptName = option.Value
+		case "PARAMETERS":
+			parameters = option.Value
+		case "INTERPRETER_OPTIONS":
+			interpreterOptions = option.Value
+		case "WORKING_DIRECTORY":
+			workingDirectory = option.Value
+		case "SDK_HOME":
+			sdkHome = option.Value
+		case "MODULE_MODE":
+			moduleMode = option.Value == "true"
+		}
+	}
+
+	if scriptName == "" {
+		return fmt.Errorf("SCRIPT_NAME is required for Python configuration")
+	}
+
+	var args []string
+
+	if interpreterOptions != "" {
+		args = append(args, p.parseParameters(interpreterOptions)...)
+	}
+
+	if moduleMode {
+		args = append(args, "-m", scriptName)
+	} else {
+		args = append(args, p.resolveJetBrainsPath(scriptName))
+	}
+
+	if parameters != "" {
+		args = append(args, p.parseParameters(parameters)...)
+	}
+
+	task.Args = args
+
+	if workingDirectory != "" {
+		task.Cwd = p.resolveJetBrainsPath(workingDirectory)
+	}
+
+	if jetbrainsConfig.Envs != nil && len(jetbrainsConfig.Envs.Envs) > 0 {
+		task.Env = make(map[string]string, len(jetbrainsConfig.Envs.Envs))
+		for _, env := range jetbrainsConfig.Envs.Envs {
+			task.Env[env.Name] = env.Value
+		}
+	}
+
+	// SDK_HOME is the interpreter itself, typically inside a virtualenv
+	if sdkHome != "" {
+		task.RuntimePath = p.resolveJetBrainsPath(sdkHome)
+	}
+
 	return nil
 }
 
-// handleGradleConfig handles Gradle run configurations
+// executableName adds the platform's executable suffix
+func executableName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+
+	return name
+}
+
+// handleGradleConfig handles Gradle run configurations. Their settings live in
+// ExternalSystemSettings: the tasks and script parameters become arguments, VM options the
+// build's JVM arguments (-Dorg.gradle.jvmargs, as the IDE passes them through the tooling API),
+// the env map the environment, and externalProjectPath, the Gradle project, the working directory.
 func (p *RunConfigurationParser) handleGradleConfig(jetbrainsConfig JetBrainsRunConfiguration, task *config.Task) error {
 	task.Command = "gradle"
 	task.Group = "build"
@@ -156,8 +471,12 @@ func (p *RunConfigurationParser) handleGradleConfig(jetbrainsConfig JetBrainsRun
 	}
 
 	var (
-		taskNames        []string
-		scriptParameters string
+		taskNames           []string
+		taskDescriptions    []string
+		scriptParameters    string
+		vmOptions           string
+		externalProjectPath string
+		envVars             map[string]string
 	)
 
 	// Parse external system settings
@@ -170,8 +489,27 @@ func (p *RunConfigurationParser) handleGradleConfig(jetbrainsConfig JetBrainsRun
 					taskNames = append(taskNames, listOption.Value)
 				}
 			}
+		case "taskDescriptions":
+			if option.List != nil {
+				for _, listOption := range option.List.Options {
+					if listOption.Value != "" {
+						taskDescriptions = append(taskDescriptions, listOption.Value)
+					}
+				}
+			}
 		case "scriptParameters":
 			scriptParameters = option.Value
+		case "vmOptions":
+			vmOptions = strings.TrimSpace(option.Value)
+		case "externalProjectPath":
+			externalProjectPath = option.Value
+		case "env":
+			if option.Map != nil {
+				envVars = make(map[string]string, len(option.Map.Entries))
+				for _, entry := range option.Map.Entries {
+					envVars[entry.Key] = entry.Value
+				}
+			}
 		}
 	}
 
@@ -186,52 +524,168 @@ func (p *RunConfigurationParser) handleGradleConfig(jetbrainsConfig JetBrainsRun
 		args = append(args, scriptArgs...)
 	}
 
+	// One argument however many options, so the build JVM gets them all
+	if vmOptions != "" {
+		args = append(args, "-Dorg.gradle.jvmargs="+vmOptions)
+	}
+
 	task.Args = args
 
+	if externalProjectPath != "" {
+		task.Cwd = p.resolveJetBrainsPath(externalProjectPath)
+	}
+
+	if len(envVars) > 0 {
+		task.Env = envVars
+	}
+
+	if len(taskDescriptions) > 0 {
+		task.Description = strings.Join(taskDescriptions, "; ")
+	}
+
 	return nil
 }
 
-// parseParameters parses a parameter string and splits it into individual arguments
-func (p *RunConfigurationParser) parseParameters(params string) []string {
-	if params == "" {
-		return nil
+// handleDockerComposeConfig handles Docker Compose run configurations, which JetBrains stores as
+// docker-deploy configurations with a docker-compose.yml deployment. They become
+// `docker compose -f <file> up <services...>`.
+func (p *RunConfigurationParser) handleDockerComposeConfig(jetbrainsConfig JetBrainsRunConfiguration, task *config.Task) error {
+	task.Command = "docker"
+	task.Group = "run"
+
+	// Older IDE versions keep the settings on the configuration itself
+	options := jetbrainsConfig.Options
+	deploymentType := jetbrainsConfig.FactoryName
+
+	if deployment := jetbrainsConfig.Deployment; deployment != nil {
+		if deployment.Type != "" {
+			deploymentType = deployment.Type
+		}
+
+		if deployment.Settings != nil {
+			options = deployment.Settings.Options
+		}
+	}
+
+	if jetbrainsConfig.Type == "docker-deploy" && !strings.Contains(strings.ToLower(deploymentType), "compose") {
+		return fmt.Errorf("unsupported Docker deployment type: %s (only Docker Compose deployments are supported)", deploymentType)
 	}
 
-	// Parse parameters with quoted string support
 	var (
-		args      []string
-		current   strings.Builder
-		inQuote   bool
-		quoteChar rune
+		composeFile string
+		envFile     string
+		services    []string
 	)
 
-	for _, char := range params {
-		switch {
-		case !inQuote && (char == '"' || char == '\''):
-			// Start of quoted string - don't include the quote in output
-			inQuote = true
-			quoteChar = char
-		case inQuote && char == quoteChar:
-			// End of quoted string - don't include the quote in output
-			inQuote = false
-		case !inQuote && char == ' ':
-			// Space outside quotes - end current argument
-			if current.Len() > 0 {
-				args = append(args, current.String())
-				current.Reset()
+	for _, option := range options {
+		switch option.Name {
+		case "sourceFilePath":
+			composeFile = option.Value
+		case "envFilePath":
+			envFile = option.Value
+		case "services":
+			if option.List != nil {
+				for _, listOption := range option.List.Options {
+					services = append(services, listOption.Value)
+				}
 			}
-		default:
-			// Regular character or space inside quotes
-			current.WriteRune(char)
 		}
 	}
 
-	// Add final argument
-	if current.Len() > 0 {
-		args = append(args, current.String())
+	if composeFile == "" {
+		return fmt.Errorf("sourceFilePath is required for Docker Compose configuration")
+	}
+
+	args := []string{"compose"}
+
+	if envFile != "" {
+		args = append(args, "--env-file", p.resolveJetBrainsPath(envFile))
 	}
 
-	return args
+	args = append(args, "-f", p.resolveJetBrainsPath(composeFile), "up")
+	task.Args = append(args, services...)
+
+	return nil
+}
+
+// defaultScriptInterpreter runs shell script configurations that don't name an interpreter
+const defaultScriptInterpreter = "/bin/sh"
+
+// handleShellScriptConfig handles Shell Script configurations. An inline SCRIPT_TEXT, which
+// may span several lines, is kept verbatim as a shell command line; a script file runs with
+// its interpreter, interpreter options and script options as args.
+func (p *RunConfigurationParser) handleShellScriptConfig(jetbrainsConfig JetBrainsRunConfiguration, task *config.Task) error {
+	task.Group = "run"
+
+	var (
+		scriptText         string
+		scriptPath         string
+		scriptOptions      string
+		interpreter        string
+		interpreterOptions string
+		workingDirectory   string
+		executeFile        bool
+	)
+
+	for _, option := range jetbrainsConfig.Options {
+		switch option.Name {
+		case "SCRIPT_TEXT":
+			scriptText = option.Value
+		case "SCRIPT_PATH":
+			scriptPath = option.Value
+		case "SCRIPT_OPTIONS":
+			scriptOptions = option.Value
+		case "INTERPRETER_PATH":
+			interpreter = option.Value
+		case "INTERPRETER_OPTIONS":
+			interpreterOptions = option.Value
+		case "SCRIPT_WORKING_DIRECTORY", "WORKING_DIRECTORY":
+			workingDirectory = option.Value
+		case "EXECUTE_SCRIPT_FILE":
+			executeFile = option.Value == "true"
+		}
+	}
+
+	if interpreter == "" {
+		interpreter = defaultScriptInterpreter
+	}
+
+	if executeFile {
+		if scriptPath == "" {
+			return fmt.Errorf("SCRIPT_PATH is required for Shell Script configuration running a file")
+		}
+
+		task.Command = interpreter
+		task.Execution = config.ExecutionProcess
+		task.Args = append(p.parseParameters(interpreterOptions), p.resolveJetBrainsPath(scriptPath))
+		task.Args = append(task.Args, p.parseParameters(scriptOptions)...)
+	} else {
+		if strings.TrimSpace(scriptText) == "" {
+			return fmt.Errorf("SCRIPT_TEXT is required for Shell Script configuration")
+		}
+
+		task.Command = scriptText
+		task.Execution = config.ExecutionShell
+		task.Shell = interpreter
+	}
+
+	if workingDirectory != "" {
+		task.Cwd = p.resolveJetBrainsPath(workingDirectory)
+	}
+
+	if jetbrainsConfig.Envs != nil && len(jetbrainsConfig.Envs.Envs) > 0 {
+		task.Env = make(map[string]string, len(jetbrainsConfig.Envs.Envs))
+		for _, env := range jetbrainsConfig.Envs.Envs {
+			task.Env[env.Name] = env.Value
+		}
+	}
+
+	return nil
+}
+
+// parseParameters parses a parameter string and splits it into individual arguments
+func (p *RunConfigurationParser) parseParameters(params string) []string {
+	return shellwords.Split(params)
 }
 
 // resolveJetBrainsPath resolves JetBrains variables in paths
@@ -240,6 +694,10 @@ func (p *RunConfigurationParser) resolveJetBrainsPath(path string) string {
 	resolved := strings.ReplaceAll(path, "$PROJECT_DIR$", p.projectRoot)
 	resolved = strings.ReplaceAll(resolved, "$MODULE_DIR$", p.projectRoot)
 
+	if home, err := os.UserHomeDir(); err == nil {
+		resolved = strings.ReplaceAll(resolved, "$USER_HOME$", home)
+	}
+
 	// Handle relative paths
 	if !filepath.IsAbs(resolved) {
 		resolved = filepath.Join(p.projectRoot, resolved)
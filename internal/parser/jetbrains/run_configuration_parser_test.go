@@ -1,17 +1,20 @@
 package jetbrains
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/converter"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestRunConfigurationParser(t *testing.T) {
 	t.Run("NewRunConfigurationParser", func(t *testing.T) {
-		parser := NewRunConfigurationParser("/test/project")
+		parser := NewRunConfigurationParser("/test/project", nil)
 		require.NotNil(t, parser)
 		require.Equal(t, "/test/project", parser.projectRoot)
 	})
@@ -21,7 +24,7 @@ func TestRunConfigurationParser(t *testing.T) {
 			testDataPath := filepath.Join("..", "..", "test", "jetbrains-testdata", ".idea", "runConfigurations", "Application.xml")
 			projectRoot := filepath.Join("..", "..", "test", "jetbrains-testdata")
 
-			parser := NewRunConfigurationParser(projectRoot)
+			parser := NewRunConfigurationParser(projectRoot, nil)
 			task, err := parser.ParseRunConfiguration(testDataPath)
 
 			require.NoError(t, err)
@@ -44,7 +47,7 @@ func TestRunConfigurationParser(t *testing.T) {
 			testDataPath := filepath.Join("..", "..", "test", "jetbrains-testdata", ".idea", "runConfigurations", "Gradle_Build.xml")
 			projectRoot := filepath.Join("..", "..", "test", "jetbrains-testdata")
 
-			parser := NewRunConfigurationParser(projectRoot)
+			parser := NewRunConfigurationParser(projectRoot, nil)
 			task, err := parser.ParseRunConfiguration(testDataPath)
 
 			require.NoError(t, err)
@@ -57,11 +60,207 @@ func TestRunConfigurationParser(t *testing.T) {
 			require.Contains(t, task.Args, "build")
 			require.Equal(t, testDataPath, task.Source)
 		})
+
+		t.Run("should parse Kotlin and Spring Boot configurations from testdata", func(t *testing.T) {
+			projectRoot := filepath.Join("..", "..", "test", "jetbrains-testdata")
+			configDir := filepath.Join(projectRoot, ".idea", "runConfigurations")
+			parser := NewRunConfigurationParser(projectRoot, nil)
+
+			kotlin, err := parser.ParseRunConfiguration(filepath.Join(configDir, "Kotlin_App.xml"))
+			require.NoError(t, err)
+			require.Equal(t, "Kotlin App", kotlin.Name)
+			require.Equal(t, "java", kotlin.Command)
+			require.Equal(t, []string{"-Xmx512m", "-Dkotlinx.coroutines.debug=on", "com.example.cli.MainKt", "--input", "data.csv", "--verbose"}, kotlin.Args)
+			require.Equal(t, filepath.Join(projectRoot, "cli"), kotlin.Cwd)
+			require.Equal(t, map[string]string{"LOG_LEVEL": "debug"}, kotlin.Env)
+
+			spring, err := parser.ParseRunConfiguration(filepath.Join(configDir, "Spring_Boot_App.xml"))
+			require.NoError(t, err)
+			require.Equal(t, "Spring Boot App", spring.Name)
+			require.Equal(t, "java", spring.Command)
+			require.Equal(t, []string{"-Xms256m", "-Dspring.output.ansi.enabled=ALWAYS", "com.example.demo.DemoApplication", "--server.port=8081"}, spring.Args)
+			require.Equal(t, map[string]string{
+				"DB_URL":                 "jdbc:postgresql://localhost:5432/demo",
+				"SPRING_PROFILES_ACTIVE": "dev,local",
+			}, spring.Env)
+			require.Equal(t, []string{"Gradle Build"}, spring.BeforeRun)
+
+			// Both port to Java launch configurations with their JVM options as vmArgs
+			outputPath := filepath.Join(t.TempDir(), "launch.json")
+			launchConverter := converter.NewJetBrainsToVSCodeLaunchConverter(projectRoot, outputPath, false, nil)
+			require.NoError(t, launchConverter.ConvertToLaunch([]*config.Task{kotlin, spring}, false))
+
+			data, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+
+			var launchFile converter.VSCodeLaunchFile
+			require.NoError(t, vscode.ParseJSONC(data, &launchFile))
+			require.Len(t, launchFile.Configurations, 2)
+
+			kotlinLaunch := launchFile.Configurations[0]
+			require.Equal(t, "java", kotlinLaunch.Type)
+			require.Equal(t, "com.example.cli.MainKt", kotlinLaunch.MainClass)
+			require.Equal(t, "-Xmx512m -Dkotlinx.coroutines.debug=on", kotlinLaunch.VMArgs)
+			require.Equal(t, []string{"--input", "data.csv", "--verbose"}, kotlinLaunch.Args)
+			require.Equal(t, map[string]string{"LOG_LEVEL": "debug"}, kotlinLaunch.Env)
+
+			springLaunch := launchFile.Configurations[1]
+			require.Equal(t, "java", springLaunch.Type)
+			require.Equal(t, "com.example.demo.DemoApplication", springLaunch.MainClass)
+			require.Equal(t, "-Xms256m -Dspring.output.ansi.enabled=ALWAYS", springLaunch.VMArgs)
+			require.Equal(t, []string{"--server.port=8081"}, springLaunch.Args)
+			require.Equal(t, "dev,local", springLaunch.Env["SPRING_PROFILES_ACTIVE"])
+		})
+
+		t.Run("should include the file path in parse errors", func(t *testing.T) {
+			tests := []struct {
+				name     string
+				content  string
+				contains string
+			}{
+				{
+					name:     "empty file",
+					content:  "",
+					contains: "empty or truncated XML",
+				},
+				{
+					name:     "truncated file",
+					content:  `<component name="ProjectRunConfigurationManager"><configuration name="App" type="Application">`,
+					contains: "XML syntax error on line 1",
+				},
+				{
+					name:     "unsupported type",
+					content:  `<component><configuration name="App" type="Unknown" /></component>`,
+					contains: "unsupported JetBrains configuration type: Unknown (configuration 'App')",
+				},
+				{
+					name:     "other IDE state",
+					content:  `<component name="RunManager" selected="Application.App" />`,
+					contains: `found <component name="RunManager"> without a <configuration> element`,
+				},
+				{
+					name:     "missing type",
+					content:  `<component name="ProjectRunConfigurationManager"><configuration name="App" /></component>`,
+					contains: `configuration 'App' in <component name="ProjectRunConfigurationManager"> has no type attribute`,
+				},
+				{
+					name:     "other root element",
+					content:  `<project version="4" />`,
+					contains: "expected element type <component> but have <project>",
+				},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					configPath := filepath.Join(t.TempDir(), "Broken.xml")
+					require.NoError(t, os.WriteFile(configPath, []byte(tt.content), 0o600))
+
+					parser := NewRunConfigurationParser("/test/project", nil)
+					task, err := parser.ParseRunConfiguration(configPath)
+
+					require.Error(t, err)
+					require.Nil(t, task)
+					require.Contains(t, err.Error(), configPath)
+					require.Contains(t, err.Error(), tt.contains)
+				})
+			}
+		})
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		parser := NewRunConfigurationParser("/test/project", nil)
+		dir := t.TempDir()
+
+		t.Run("should skip template files by name without reading them", func(t *testing.T) {
+			_, err := parser.ParseRunConfiguration(filepath.Join(dir, "_template__of_Application.xml"))
+			require.ErrorIs(t, err, ErrTemplate)
+		})
+
+		t.Run("should skip default configurations", func(t *testing.T) {
+			configPath := filepath.Join(dir, "Application.xml")
+			require.NoError(t, os.WriteFile(configPath, []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration default="true" type="Application" factoryName="Application" />
+</component>`), 0o600))
+
+			_, err := parser.ParseRunConfiguration(configPath)
+			require.ErrorIs(t, err, ErrTemplate)
+		})
+
+		t.Run("should inherit options and env the configuration doesn't set", func(t *testing.T) {
+			inheriting := NewRunConfigurationParser("/test/project", nil)
+			inheriting.SetTemplates(config.RunConfigTemplates{
+				"Application": {
+					Type:    "Application",
+					Options: []config.TemplateValue{{Name: "VM_PARAMETERS", Value: "-Xmx1g"}, {Name: "PROGRAM_PARAMETERS", Value: "--verbose"}},
+					EnvVars: []config.TemplateValue{{Name: "JAVA_TOOL_OPTIONS", Value: "-Dfile.encoding=UTF-8"}, {Name: "PROFILE", Value: "dev"}},
+				},
+			})
+
+			data := []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration name="Server" type="Application" factoryName="Application">
+    <option name="MAIN_CLASS_NAME" value="com.example.Server" />
+    <option name="PROGRAM_PARAMETERS" value="--port 8080" />
+    <envs>
+      <env name="PROFILE" value="prod" />
+    </envs>
+  </configuration>
+</component>`)
+
+			task, err := inheriting.parseRunConfigurationData(data, "/test/Server.xml")
+			require.NoError(t, err)
+			require.Equal(t, []string{"-Xmx1g", "com.example.Server", "--port", "8080"}, task.Args)
+			require.Equal(t, map[string]string{"JAVA_TOOL_OPTIONS": "-Dfile.encoding=UTF-8", "PROFILE": "prod"}, task.Env)
+
+			// Configurations of other types are left alone
+			gradle, err := inheriting.parseRunConfigurationData([]byte(`<component name="ProjectRunConfigurationManager">
+  <configuration name="build" type="GradleRunConfiguration" factoryName="Gradle">
+    <ExternalSystemSettings>
+      <option name="taskNames"><list><option value="build" /></list></option>
+    </ExternalSystemSettings>
+  </configuration>
+</component>`), "/test/build.xml")
+			require.NoError(t, err)
+			require.Empty(t, gradle.Env)
+		})
+	})
+
+	t.Run("convertRunConfiguration", func(t *testing.T) {
+		parser := NewRunConfigurationParser("/test/project", nil)
+
+		t.Run("EXECUTE_IN_TERMINAL marks task interactive", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name: "Interactive App",
+				Type: "Application",
+				Options: []JetBrainsOption{
+					{Name: "MAIN_CLASS_NAME", Value: "com.test.Main"},
+					{Name: "EXECUTE_IN_TERMINAL", Value: "true"},
+				},
+			}
+
+			task, err := parser.convertRunConfiguration(jetbrainsConfig, "/test/app.xml")
+			require.NoError(t, err)
+			require.True(t, task.Interactive)
+		})
+
+		t.Run("non-terminal configurations are not interactive", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name: "Batch App",
+				Type: "Application",
+				Options: []JetBrainsOption{
+					{Name: "MAIN_CLASS_NAME", Value: "com.test.Main"},
+					{Name: "EXECUTE_IN_TERMINAL", Value: "false"},
+				},
+			}
+
+			task, err := parser.convertRunConfiguration(jetbrainsConfig, "/test/app.xml")
+			require.NoError(t, err)
+			require.False(t, task.Interactive)
+		})
 	})
 
 	t.Run("handleApplicationConfig", func(t *testing.T) {
 		projectRoot := "/test/project"
-		parser := NewRunConfigurationParser(projectRoot)
+		parser := NewRunConfigurationParser(projectRoot, nil)
 
 		t.Run("should handle basic Application configuration", func(t *testing.T) {
 			jetbrainsConfig := JetBrainsRunConfiguration{
@@ -115,6 +314,22 @@ func TestRunConfigurationParser(t *testing.T) {
 			require.Equal(t, "test", task.Env["ENV"])
 		})
 
+		t.Run("should keep an explicit SPRING_PROFILES_ACTIVE over active profiles", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name: "Demo",
+				Type: "SpringBootApplicationConfigurationType",
+				Options: []JetBrainsOption{
+					{Name: "SPRING_BOOT_MAIN_CLASS", Value: "com.example.demo.DemoApplication"},
+					{Name: "ACTIVE_PROFILES", Value: "dev"},
+				},
+				Envs: &JetBrainsEnvs{Envs: []JetBrainsEnv{{Name: "SPRING_PROFILES_ACTIVE", Value: "prod"}}},
+			}
+
+			task := &config.Task{}
+			require.NoError(t, parser.handleSpringBootConfig(jetbrainsConfig, task))
+			require.Equal(t, "prod", task.Env["SPRING_PROFILES_ACTIVE"])
+		})
+
 		t.Run("should fail without MAIN_CLASS_NAME", func(t *testing.T) {
 			jetbrainsConfig := JetBrainsRunConfiguration{
 				Name: "Test App",
@@ -132,9 +347,99 @@ func TestRunConfigurationParser(t *testing.T) {
 		})
 	})
 
+	t.Run("runtime paths", func(t *testing.T) {
+		projectRoot := "/test/project"
+		parser := NewRunConfigurationParser(projectRoot, nil)
+
+		t.Run("should use an enabled alternative JRE home", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name: "Legacy App",
+				Type: "Application",
+				Options: []JetBrainsOption{
+					{Name: "MAIN_CLASS_NAME", Value: "com.test.Main"},
+					{Name: "ALTERNATIVE_JRE_PATH_ENABLED", Value: "true"},
+					{Name: "ALTERNATIVE_JRE_PATH", Value: "/usr/lib/jvm/java-8-openjdk"},
+				},
+			}
+
+			task := &config.Task{}
+			require.NoError(t, parser.handleApplicationConfig(jetbrainsConfig, task))
+			require.Equal(t, filepath.Join("/usr/lib/jvm/java-8-openjdk", "bin", executableName("java")), task.RuntimePath)
+			require.Equal(t, "java", task.Command)
+		})
+
+		t.Run("should ignore disabled JREs and IDE SDK names", func(t *testing.T) {
+			for _, options := range [][]JetBrainsOption{
+				{{Name: "ALTERNATIVE_JRE_PATH_ENABLED", Value: "false"}, {Name: "ALTERNATIVE_JRE_PATH", Value: "/usr/lib/jvm/java-8-openjdk"}},
+				{{Name: "ALTERNATIVE_JRE_PATH_ENABLED", Value: "true"}, {Name: "ALTERNATIVE_JRE_PATH", Value: "corretto-17"}},
+			} {
+				jetbrainsConfig := JetBrainsRunConfiguration{
+					Name:    "App",
+					Type:    "Application",
+					Options: append([]JetBrainsOption{{Name: "MAIN_CLASS_NAME", Value: "com.test.Main"}}, options...),
+				}
+
+				task := &config.Task{}
+				require.NoError(t, parser.handleApplicationConfig(jetbrainsConfig, task))
+				require.Empty(t, task.RuntimePath)
+			}
+		})
+
+		t.Run("should parse Python configurations with their interpreter", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name: "Python App",
+				Type: "PythonConfigurationType",
+				Options: []JetBrainsOption{
+					{Name: "SCRIPT_NAME", Value: "$PROJECT_DIR$/src/main.py"},
+					{Name: "PARAMETERS", Value: "--verbose --config settings.ini"},
+					{Name: "INTERPRETER_OPTIONS", Value: "-u"},
+					{Name: "SDK_HOME", Value: "$PROJECT_DIR$/.venv/bin/python"},
+				},
+				Envs: &JetBrainsEnvs{Envs: []JetBrainsEnv{{Name: "DEBUG", Value: "1"}}},
+			}
+
+			task, err := parser.convertRunConfiguration(jetbrainsConfig, "/test/python.xml")
+			require.NoError(t, err)
+			require.Equal(t, "python", task.Command)
+			require.Equal(t, []string{"-u", filepath.Join(projectRoot, "src", "main.py"), "--verbose", "--config", "settings.ini"}, task.Args)
+			require.Equal(t, filepath.Join(projectRoot, ".venv", "bin", "python"), task.RuntimePath)
+			require.Equal(t, map[string]string{"DEBUG": "1"}, task.Env)
+			require.Equal(t, projectRoot, task.Cwd)
+		})
+
+		t.Run("should run Python modules with -m", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name: "Server",
+				Type: "PythonConfigurationType",
+				Options: []JetBrainsOption{
+					{Name: "SCRIPT_NAME", Value: "uvicorn"},
+					{Name: "MODULE_MODE", Value: "true"},
+					{Name: "PARAMETERS", Value: "app:main"},
+				},
+			}
+
+			task, err := parser.convertRunConfiguration(jetbrainsConfig, "/test/server.xml")
+			require.NoError(t, err)
+			require.Equal(t, []string{"-m", "uvicorn", "app:main"}, task.Args)
+			require.Empty(t, task.RuntimePath)
+		})
+
+		t.Run("should read tags from the folder name", func(t *testing.T) {
+			data := []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration name="Migrate" type="PythonConfigurationType" folderName="Database [tags: ci, destructive]">
+    <option name="SCRIPT_NAME" value="manage.py" />
+  </configuration>
+</component>`)
+
+			task, err := parser.parseRunConfigurationData(data, "/test/migrate.xml")
+			require.NoError(t, err)
+			require.Equal(t, []string{"ci", "destructive"}, task.Tags)
+		})
+	})
+
 	t.Run("handleGradleConfig", func(t *testing.T) {
 		projectRoot := "/test/project"
-		parser := NewRunConfigurationParser(projectRoot)
+		parser := NewRunConfigurationParser(projectRoot, nil)
 
 		t.Run("should handle basic Gradle configuration", func(t *testing.T) {
 			jetbrainsConfig := JetBrainsRunConfiguration{
@@ -167,10 +472,334 @@ func TestRunConfigurationParser(t *testing.T) {
 			require.Contains(t, task.Args, "--info")
 			require.Contains(t, task.Args, "--stacktrace")
 		})
+
+		t.Run("should carry env, VM options and the project path", func(t *testing.T) {
+			data := []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration default="false" name="api:test" type="GradleRunConfiguration" factoryName="Gradle">
+    <ExternalSystemSettings>
+      <option name="env">
+        <map>
+          <entry key="SPRING_PROFILES_ACTIVE" value="test" />
+          <entry key="DB_URL" value="jdbc:h2:mem:test" />
+        </map>
+      </option>
+      <option name="executionName" />
+      <option name="externalProjectPath" value="$PROJECT_DIR$/services/api" />
+      <option name="externalSystemIdString" value="GRADLE" />
+      <option name="scriptParameters" value="--tests &quot;com.example.*IT&quot; -Pprofile=ci" />
+      <option name="taskDescriptions">
+        <list>
+          <option value="Runs the API integration tests" />
+        </list>
+      </option>
+      <option name="taskNames">
+        <list>
+          <option value=":api:test" />
+        </list>
+      </option>
+      <option name="vmOptions" value="-Xmx2g -Dfile.encoding=UTF-8" />
+    </ExternalSystemSettings>
+    <GradleScriptDebugEnabled>true</GradleScriptDebugEnabled>
+    <method v="2" />
+  </configuration>
+</component>`)
+
+			task, err := parser.parseRunConfigurationData(data, "/test/api_test.xml")
+			require.NoError(t, err)
+			require.Equal(t, []string{":api:test", "--tests", "com.example.*IT", "-Pprofile=ci", "-Dorg.gradle.jvmargs=-Xmx2g -Dfile.encoding=UTF-8"}, task.Args)
+			require.Equal(t, map[string]string{"SPRING_PROFILES_ACTIVE": "test", "DB_URL": "jdbc:h2:mem:test"}, task.Env)
+			require.Equal(t, filepath.Join(projectRoot, "services", "api"), task.Cwd)
+			require.Equal(t, "Runs the API integration tests", task.Description)
+		})
+
+		t.Run("should run in the project root without a project path", func(t *testing.T) {
+			testDataPath := filepath.Join("..", "..", "test", "jetbrains-testdata", ".idea", "runConfigurations", "Gradle_Build.xml")
+			projectRoot := filepath.Join("..", "..", "test", "jetbrains-testdata")
+
+			task, err := NewRunConfigurationParser(projectRoot, nil).ParseRunConfiguration(testDataPath)
+			require.NoError(t, err)
+			require.Equal(t, projectRoot, task.Cwd)
+			require.Equal(t, []string{"build"}, task.Args, "empty script parameters and VM options add nothing")
+			require.Nil(t, task.Env)
+			require.Equal(t, "JetBrains GradleRunConfiguration configuration", task.Description)
+		})
+
+		t.Run("should run composite build tasks in their project and port them with it", func(t *testing.T) {
+			testDataPath := filepath.Join("..", "..", "test", "jetbrains-testdata", ".idea", "runConfigurations", "Service_API_bootRun.xml")
+			projectRoot, err := filepath.Abs(filepath.Join("..", "..", "test", "jetbrains-testdata"))
+			require.NoError(t, err)
+
+			task, err := NewRunConfigurationParser(projectRoot, nil).ParseRunConfiguration(testDataPath)
+			require.NoError(t, err)
+			require.Equal(t, "gradle", task.Command)
+			require.Equal(t, filepath.Join(projectRoot, "services"), task.Cwd)
+			require.Equal(t, []string{":service-api:bootRun", "--console", "plain", "-Dorg.gradle.jvmargs=-Xmx1g -XX:+UseG1GC"}, task.Args)
+			require.Equal(t, map[string]string{"SPRING_PROFILES_ACTIVE": "local"}, task.Env)
+
+			tasksPath := filepath.Join(t.TempDir(), "tasks.json")
+			require.NoError(t, converter.NewJetBrainsToVSCodeConverter(projectRoot, tasksPath, false, nil).ConvertTasks([]*config.Task{task}, false))
+
+			data, err := os.ReadFile(tasksPath)
+			require.NoError(t, err)
+
+			var tasksFile converter.VSCodeTasksFile
+			require.NoError(t, vscode.ParseJSONC(data, &tasksFile))
+			require.Len(t, tasksFile.Tasks, 1)
+			require.NotNil(t, tasksFile.Tasks[0].Options)
+			require.Equal(t, "${workspaceFolder}/services", tasksFile.Tasks[0].Options.Cwd)
+			require.Equal(t, task.Args, tasksFile.Tasks[0].Args)
+		})
+
+		t.Run("should read the project path ported from a -p flag", func(t *testing.T) {
+			projectRoot := t.TempDir()
+			task := &config.Task{
+				Name:      "service-api-boot-run",
+				Type:      config.TypeVSCodeTask,
+				Command:   "./gradlew",
+				Args:      []string{"--project-dir", "services", ":service-api:bootRun", "-Dorg.gradle.jvmargs=-Xmx1g"},
+				Execution: config.ExecutionProcess,
+				Source:    filepath.Join(projectRoot, ".vscode", "tasks.json"),
+			}
+
+			require.NoError(t, converter.NewVSCodeToJetBrainsConverter(projectRoot, "", false, nil).ConvertTasks([]*config.Task{task}, false))
+
+			ported, err := NewRunConfigurationParser(projectRoot, nil).ParseRunConfiguration(filepath.Join(projectRoot, ".idea", "runConfigurations", task.Name+".xml"))
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(projectRoot, "services"), ported.Cwd)
+			require.Equal(t, []string{":service-api:bootRun", "-Dorg.gradle.jvmargs=-Xmx1g"}, ported.Args)
+		})
+	})
+
+	t.Run("handleDockerComposeConfig", func(t *testing.T) {
+		projectRoot := "/test/project"
+		parser := NewRunConfigurationParser(projectRoot, nil)
+
+		t.Run("should run the compose services with docker compose up", func(t *testing.T) {
+			data := []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration default="false" name="Compose: web" type="docker-deploy" factoryName="docker-compose.yml" server-name="Docker">
+    <deployment type="docker-compose.yml">
+      <settings>
+        <option name="envFilePath" value="$PROJECT_DIR$/.env.dev" />
+        <option name="services">
+          <list>
+            <option value="web" />
+            <option value="worker" />
+          </list>
+        </option>
+        <option name="sourceFilePath" value="deploy/docker-compose.yml" />
+      </settings>
+    </deployment>
+    <method v="2" />
+  </configuration>
+</component>`)
+
+			task, err := parser.parseRunConfigurationData(data, "/test/compose.xml")
+			require.NoError(t, err)
+			require.Equal(t, "docker", task.Command)
+			require.Equal(t, []string{
+				"compose",
+				"--env-file", filepath.Join(projectRoot, ".env.dev"),
+				"-f", filepath.Join(projectRoot, "deploy", "docker-compose.yml"),
+				"up", "web", "worker",
+			}, task.Args)
+			require.Equal(t, "run", task.Group)
+			require.Equal(t, projectRoot, task.Cwd)
+		})
+
+		t.Run("should start every service without a services list", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name:    "Compose",
+				Type:    "DockerComposeDeploymentConfiguration",
+				Options: []JetBrainsOption{{Name: "sourceFilePath", Value: "$PROJECT_DIR$/docker-compose.yml"}},
+			}
+
+			task := &config.Task{}
+			require.NoError(t, parser.handleDockerComposeConfig(jetbrainsConfig, task))
+			require.Equal(t, []string{"compose", "-f", filepath.Join(projectRoot, "docker-compose.yml"), "up"}, task.Args)
+		})
+
+		t.Run("should reject Dockerfile deployments", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name:       "Image",
+				Type:       "docker-deploy",
+				Deployment: &JetBrainsDeployment{Type: "dockerfile"},
+			}
+
+			err := parser.handleDockerComposeConfig(jetbrainsConfig, &config.Task{})
+			require.EqualError(t, err, "unsupported Docker deployment type: dockerfile (only Docker Compose deployments are supported)")
+		})
+
+		t.Run("should fail without a compose file", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name:       "Compose",
+				Type:       "docker-deploy",
+				Deployment: &JetBrainsDeployment{Type: "docker-compose.yml", Settings: &JetBrainsDeploymentSettings{}},
+			}
+
+			err := parser.handleDockerComposeConfig(jetbrainsConfig, &config.Task{})
+			require.EqualError(t, err, "sourceFilePath is required for Docker Compose configuration")
+		})
+	})
+
+	t.Run("handleShellScriptConfig", func(t *testing.T) {
+		projectRoot := "/test/project"
+		parser := NewRunConfigurationParser(projectRoot, nil)
+
+		t.Run("should keep an IntelliJ-written multi-line script verbatim", func(t *testing.T) {
+			data := []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration default="false" name="Release notes" type="ShConfigurationType">
+    <option name="SCRIPT_TEXT" value="echo &quot;# Release&quot; &gt; notes.md&#10;git log --oneline v1.0..HEAD &gt;&gt; notes.md &amp;&amp; echo 'done → notes.md'" />
+    <option name="INDEPENDENT_SCRIPT_PATH" value="true" />
+    <option name="SCRIPT_PATH" value="" />
+    <option name="SCRIPT_OPTIONS" value="" />
+    <option name="INDEPENDENT_SCRIPT_WORKING_DIRECTORY" value="true" />
+    <option name="SCRIPT_WORKING_DIRECTORY" value="$PROJECT_DIR$/docs" />
+    <option name="INDEPENDENT_INTERPRETER_PATH" value="true" />
+    <option name="INTERPRETER_PATH" value="/bin/bash" />
+    <option name="INTERPRETER_OPTIONS" value="" />
+    <option name="EXECUTE_IN_TERMINAL" value="false" />
+    <option name="EXECUTE_SCRIPT_FILE" value="false" />
+    <envs />
+    <method v="2" />
+  </configuration>
+</component>`)
+
+			task, err := parser.parseRunConfigurationData(data, "/test/notes.xml")
+			require.NoError(t, err)
+			require.Equal(t, "echo \"# Release\" > notes.md\ngit log --oneline v1.0..HEAD >> notes.md && echo 'done → notes.md'", task.Command)
+			require.Empty(t, task.Args)
+			require.Equal(t, config.ExecutionShell, task.Execution)
+			require.Equal(t, "/bin/bash", task.Shell)
+			require.Equal(t, filepath.Join(projectRoot, "docs"), task.Cwd)
+		})
+
+		t.Run("should run script files with their interpreter and options", func(t *testing.T) {
+			jetbrainsConfig := JetBrainsRunConfiguration{
+				Name: "Deploy",
+				Type: "ShConfigurationType",
+				Options: []JetBrainsOption{
+					{Name: "EXECUTE_SCRIPT_FILE", Value: "true"},
+					{Name: "SCRIPT_PATH", Value: "$PROJECT_DIR$/scripts/deploy.sh"},
+					{Name: "SCRIPT_OPTIONS", Value: `--target "staging &amp; qa"`},
+					{Name: "INTERPRETER_OPTIONS", Value: "-eu"},
+				},
+			}
+
+			task := &config.Task{}
+			require.NoError(t, parser.handleShellScriptConfig(jetbrainsConfig, task))
+			require.Equal(t, "/bin/sh", task.Command)
+			require.Equal(t, config.ExecutionProcess, task.Execution)
+			require.Equal(t, []string{"-eu", filepath.Join(projectRoot, "scripts", "deploy.sh"), "--target", "staging &amp; qa"}, task.Args)
+		})
+
+		t.Run("should fail without a script", func(t *testing.T) {
+			err := parser.handleShellScriptConfig(JetBrainsRunConfiguration{Name: "Empty", Type: "ShConfigurationType"}, &config.Task{})
+			require.EqualError(t, err, "SCRIPT_TEXT is required for Shell Script configuration")
+		})
+	})
+
+	t.Run("special characters", func(t *testing.T) {
+		t.Run("should decode entities in parameters into args", func(t *testing.T) {
+			data := []byte(`<component name="ProjectRunConfigurationManager">
+  <configuration default="false" name="Main" type="Application">
+    <option name="MAIN_CLASS_NAME" value="com.example.Main" />
+    <option name="PROGRAM_PARAMETERS" value="--query &quot;a &lt; b &amp;&amp; c&quot; --note &quot;line 1&#10;line 2&quot; --arrow →" />
+  </configuration>
+</component>`)
+
+			task, err := NewRunConfigurationParser("/test/project", nil).parseRunConfigurationData(data, "/test/main.xml")
+			require.NoError(t, err)
+			require.Equal(t, []string{"com.example.Main", "--query", "a < b && c", "--note", "line 1\nline 2", "--arrow", "→"}, task.Args)
+		})
+
+		// Tasks go VSCode → JetBrains → VSCode and must come back with the same command and args
+		roundTrip := func(t *testing.T, task *config.Task) (*config.Task, *config.Task, string) {
+			t.Helper()
+
+			projectRoot := t.TempDir()
+			task.Type = config.TypeVSCodeTask
+			task.Source = filepath.Join(projectRoot, ".vscode", "tasks.json")
+
+			require.NoError(t, converter.NewVSCodeToJetBrainsConverter(projectRoot, "", false, nil).ConvertTasks([]*config.Task{task}, false))
+
+			xmlPath := filepath.Join(projectRoot, ".idea", "runConfigurations", task.Name+".xml")
+
+			xmlData, err := os.ReadFile(xmlPath)
+			require.NoError(t, err)
+
+			jetbrainsTask, err := NewRunConfigurationParser(projectRoot, nil).ParseRunConfiguration(xmlPath)
+			require.NoError(t, err)
+
+			tasksPath := filepath.Join(projectRoot, "ported", "tasks.json")
+			require.NoError(t, converter.NewJetBrainsToVSCodeConverter(projectRoot, tasksPath, false, nil).ConvertTasks([]*config.Task{jetbrainsTask}, false))
+
+			tasks, err := vscode.NewTasksParser(projectRoot, nil).ParseTasks(tasksPath)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+
+			return jetbrainsTask, tasks[0], string(xmlData)
+		}
+
+		t.Run("should round-trip a multi-line shell script", func(t *testing.T) {
+			script := "echo \"# Release\" > notes.md\n\tgit log --oneline >> notes.md && echo 'done → notes.md'"
+
+			jetbrainsTask, vscodeTask, xmlData := roundTrip(t, &config.Task{Name: "notes", Command: script, Execution: config.ExecutionShell})
+
+			require.Contains(t, xmlData, `value="echo &quot;# Release&quot; &gt; notes.md&#10;&#9;git log --oneline &gt;&gt; notes.md &amp;&amp; echo 'done → notes.md'"`)
+			require.Equal(t, script, jetbrainsTask.Command)
+			require.Equal(t, script, vscodeTask.Command)
+			require.Equal(t, config.ExecutionShell, vscodeTask.Execution)
+			require.Empty(t, vscodeTask.Args)
+		})
+
+		t.Run("should round-trip args with quotes, ampersands and newlines", func(t *testing.T) {
+			args := []string{"com.example.Main", "--query", "a < b && c", `say "hi"`, "it's", "line 1\nline 2", "→"}
+
+			jetbrainsTask, vscodeTask, xmlData := roundTrip(t, &config.Task{Name: "main", Command: "java", Args: args, Execution: config.ExecutionProcess})
+
+			require.Contains(t, xmlData, "&#10;")
+			require.Equal(t, args, jetbrainsTask.Args[len(jetbrainsTask.Args)-len(args):])
+			require.Equal(t, "java", vscodeTask.Command)
+			require.Equal(t, args, vscodeTask.Args[len(vscodeTask.Args)-len(args):])
+		})
+
+		t.Run("should parse configurations ported from plain tasks.json tasks", func(t *testing.T) {
+			projectRoot := t.TempDir()
+			tasksPath := filepath.Join(projectRoot, ".vscode", "tasks.json")
+			require.NoError(t, os.MkdirAll(filepath.Dir(tasksPath), 0755))
+			require.NoError(t, os.WriteFile(tasksPath, []byte(`{
+  "version": "2.0.0",
+  "tasks": [
+    {"label": "b", "type": "shell", "command": "echo", "args": ["b"]},
+    {"label": "a", "type": "process", "command": "make", "args": ["all"], "dependsOn": ["b"]}
+  ]
+}`), 0644))
+
+			tasks, err := vscode.NewTasksParser(projectRoot, nil).ParseTasks(tasksPath)
+			require.NoError(t, err)
+			require.NoError(t, converter.NewVSCodeToJetBrainsConverter(projectRoot, "", false, nil).ConvertTasks(tasks, false))
+
+			runConfigsDir := filepath.Join(projectRoot, ".idea", "runConfigurations")
+			parser := NewRunConfigurationParser(projectRoot, nil)
+
+			b, err := parser.ParseRunConfiguration(filepath.Join(runConfigsDir, "b.xml"))
+			require.NoError(t, err)
+			require.Equal(t, "echo b", b.Command)
+			require.Equal(t, config.ExecutionShell, b.Execution)
+
+			a, err := parser.ParseRunConfiguration(filepath.Join(runConfigsDir, "a.xml"))
+			require.NoError(t, err)
+			require.Equal(t, "make all", a.Command)
+			require.Equal(t, []string{"b"}, a.BeforeRun)
+
+			xmlData, err := os.ReadFile(filepath.Join(runConfigsDir, "a.xml"))
+			require.NoError(t, err)
+			require.Contains(t, string(xmlData), `run_configuration_type="ShConfigurationType"`)
+		})
 	})
 
 	t.Run("parseParameters", func(t *testing.T) {
-		parser := NewRunConfigurationParser("/test")
+		parser := NewRunConfigurationParser("/test", nil)
 
 		tests := []struct {
 			name     string
@@ -209,7 +838,7 @@ func TestRunConfigurationParser(t *testing.T) {
 
 	t.Run("resolveJetBrainsPath", func(t *testing.T) {
 		projectRoot := "/home/user/project"
-		parser := NewRunConfigurationParser(projectRoot)
+		parser := NewRunConfigurationParser(projectRoot, nil)
 
 		tests := []struct {
 			name     string
@@ -246,3 +875,28 @@ func TestRunConfigurationParser(t *testing.T) {
 		}
 	})
 }
+
+func FuzzParseRunConfiguration(f *testing.F) {
+	seed, err := os.ReadFile(filepath.Join("..", "..", "test", "jetbrains-testdata", ".idea", "runConfigurations", "Application.xml"))
+	require.NoError(f, err)
+
+	f.Add(seed)
+	f.Add(seed[:len(seed)/2])
+	f.Add([]byte(`<component><configuration name="Build" type="GradleRunConfiguration"><ExternalSystemSettings /></configuration></component>`))
+	f.Add([]byte(`<component><configuration type="Application"><option name="MAIN_CLASS_NAME" value="Main" /></configuration></component>`))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parser := NewRunConfigurationParser("/test/project", nil)
+
+		task, err := parser.parseRunConfigurationData(data, "Fuzz.xml")
+		if err != nil {
+			require.Nil(t, task)
+			require.Contains(t, err.Error(), "Fuzz.xml")
+
+			return
+		}
+
+		require.NotNil(t, task)
+	})
+}
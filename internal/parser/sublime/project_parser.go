@@ -0,0 +1,318 @@
+package sublime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
+	"github.com/syndbg/taskporter/internal/shellwords"
+)
+
+// sublimeVariablePattern matches $name, ${name} and ${name:default}
+var sublimeVariablePattern = regexp.MustCompile(`\$\{(\w+)(?::([^}]*))?\}|\$(\w+)`)
+
+// ProjectParser handles parsing of Sublime Text .sublime-project files
+type ProjectParser struct {
+	ctx         context.Context
+	projectRoot string
+	logger      *slog.Logger
+}
+
+// NewProjectParser creates a new Sublime Text project parser
+func NewProjectParser(projectRoot string, logger *slog.Logger) *ProjectParser {
+	return &ProjectParser{
+		ctx:         context.Background(),
+		projectRoot: projectRoot,
+		logger:      logging.OrDiscard(logger).With(logging.KeyComponent, "sublime"),
+	}
+}
+
+// SetContext bounds the parser's file reads by ctx, e.g. an --op-timeout deadline
+func (p *ProjectParser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// ParseProject parses a .sublime-project file and returns a Task for each build system and variant
+func (p *ProjectParser) ParseProject(projectFilePath string) ([]*config.Task, error) {
+	data, err := config.ReadFileContext(p.ctx, projectFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file %s: %w", projectFilePath, err)
+	}
+
+	// Sublime Text project files allow comments, like VSCode's JSON
+	var project SublimeProject
+	if err := vscode.ParseJSONC(data, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse project file %s: %w", projectFilePath, err)
+	}
+
+	var tasks []*config.Task
+
+	for _, buildSystem := range project.BuildSystems {
+		task, err := p.convertBuildSystem(buildSystem, projectFilePath)
+		if err != nil {
+			p.logger.Warn("failed to convert build system", logging.KeyFile, projectFilePath, logging.KeyTask, buildSystem.Name, "error", err)
+			continue
+		}
+
+		tasks = append(tasks, task)
+
+		// Variants appear in Sublime as "<build system> - <variant>" and inherit unset fields
+		for _, variant := range buildSystem.Variants {
+			merged := mergeBuildSystem(buildSystem, variant)
+			merged.Name = buildSystem.Name + " - " + variant.Name
+
+			variantTask, err := p.convertBuildSystem(merged, projectFilePath)
+			if err != nil {
+				p.logger.Warn("failed to convert build system", logging.KeyFile, projectFilePath, logging.KeyTask, merged.Name, "error", err)
+				continue
+			}
+
+			tasks = append(tasks, variantTask)
+		}
+	}
+
+	p.logger.Debug("parsed project file", logging.KeyFile, projectFilePath, "build_systems", len(tasks))
+
+	return tasks, nil
+}
+
+// convertBuildSystem converts a Sublime build system to our internal Task structure
+func (p *ProjectParser) convertBuildSystem(buildSystem SublimeBuildSystem, sourceFile string) (*config.Task, error) {
+	buildSystem = applyPlatformOverrides(buildSystem, runtime.GOOS)
+
+	if buildSystem.Name == "" {
+		return nil, fmt.Errorf("build system has no name")
+	}
+
+	task := &config.Task{
+		Name:        buildSystem.Name,
+		Type:        config.TypeSublime,
+		Group:       "build",
+		Description: fmt.Sprintf("Sublime Text build system from %s", filepath.Base(sourceFile)),
+		Source:      sourceFile,
+	}
+
+	cmdArgs := cmdList(buildSystem.Cmd)
+
+	switch {
+	case buildSystem.ShellCmd != "":
+		task.Shell = sublimeShell()
+		task.Command = p.resolveVariables(buildSystem.ShellCmd, sourceFile)
+	case len(cmdArgs) > 0 && buildSystem.Shell:
+		// "shell": true runs cmd through the shell as a single command line
+		task.Shell = sublimeShell()
+		task.Command = p.shellCommandLine(cmdArgs, sourceFile)
+	case len(cmdArgs) > 0:
+		task.Command = p.resolveVariables(cmdArgs[0], sourceFile)
+
+		for _, arg := range cmdArgs[1:] {
+			task.Args = append(task.Args, p.resolveVariables(arg, sourceFile))
+		}
+	default:
+		return nil, fmt.Errorf("build system requires cmd or shell_cmd")
+	}
+
+	// Relative working directories are relative to the project file, like in Sublime
+	projectDir := projectDirectory(sourceFile)
+
+	task.Cwd = projectDir
+	if buildSystem.WorkingDir != "" {
+		task.Cwd = p.resolveVariables(buildSystem.WorkingDir, sourceFile)
+		if !filepath.IsAbs(task.Cwd) {
+			task.Cwd = filepath.Join(projectDir, task.Cwd)
+		}
+	}
+
+	if len(buildSystem.Env) > 0 {
+		task.Env = make(map[string]string, len(buildSystem.Env))
+		for k, v := range buildSystem.Env {
+			task.Env[k] = p.resolveVariables(v, sourceFile)
+		}
+	}
+
+	return task, nil
+}
+
+// resolveVariables maps Sublime build variables onto their VSCode equivalents and resolves
+// the project ones. File variables stay as ${file}-style placeholders since the CLI has no
+// active editor file.
+func (p *ProjectParser) resolveVariables(value, sourceFile string) string {
+	projectDir := projectDirectory(sourceFile)
+	projectName := filepath.Base(sourceFile)
+
+	variables := map[string]string{
+		"project_path":      projectDir,
+		"folder":            projectDir,
+		"project":           sourceFile,
+		"project_name":      projectName,
+		"project_base_name": strings.TrimSuffix(projectName, filepath.Ext(projectName)),
+		"file":              "${file}",
+		"file_path":         "${fileDirname}",
+		"file_name":         "${fileBasename}",
+		"file_base_name":    "${fileBasenameNoExtension}",
+		"file_extension":    "${fileExtname}",
+		"platform":          sublimePlatform(runtime.GOOS),
+	}
+
+	return sublimeVariablePattern.ReplaceAllStringFunc(value, func(match string) string {
+		groups := sublimeVariablePattern.FindStringSubmatch(match)
+
+		name := groups[1]
+		if name == "" {
+			name = groups[3]
+		}
+
+		if resolved, ok := variables[name]; ok {
+			return resolved
+		}
+
+		// ${name:default} falls back to its default for unknown variables
+		if groups[2] != "" {
+			return groups[2]
+		}
+
+		return match
+	})
+}
+
+// projectDirectory returns the absolute directory containing the project file
+func projectDirectory(sourceFile string) string {
+	dir, err := filepath.Abs(filepath.Dir(sourceFile))
+	if err != nil {
+		return filepath.Dir(sourceFile)
+	}
+
+	return dir
+}
+
+// mergeBuildSystem overlays a variant on its parent build system
+func mergeBuildSystem(base, variant SublimeBuildSystem) SublimeBuildSystem {
+	merged := base
+	merged.Variants = nil
+
+	if variant.Cmd != nil || variant.ShellCmd != "" {
+		merged.Cmd = variant.Cmd
+		merged.ShellCmd = variant.ShellCmd
+		merged.Shell = variant.Shell
+	}
+
+	if variant.WorkingDir != "" {
+		merged.WorkingDir = variant.WorkingDir
+	}
+
+	if len(variant.Env) > 0 {
+		merged.Env = make(map[string]string, len(base.Env)+len(variant.Env))
+		for k, v := range base.Env {
+			merged.Env[k] = v
+		}
+
+		for k, v := range variant.Env {
+			merged.Env[k] = v
+		}
+	}
+
+	return merged
+}
+
+// applyPlatformOverrides applies the windows/osx/linux block matching goos
+func applyPlatformOverrides(buildSystem SublimeBuildSystem, goos string) SublimeBuildSystem {
+	var override *SublimeBuildSystem
+
+	switch goos {
+	case "windows":
+		override = buildSystem.Windows
+	case "darwin":
+		override = buildSystem.OSX
+	default:
+		override = buildSystem.Linux
+	}
+
+	if override == nil {
+		return buildSystem
+	}
+
+	return mergeBuildSystem(buildSystem, *override)
+}
+
+// cmdList normalizes cmd, which can be a string or an array of strings
+func cmdList(cmd interface{}) []string {
+	switch v := cmd.(type) {
+	case string:
+		return shellwords.Split(v)
+	case []interface{}:
+		args := make([]string, 0, len(v))
+
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				args = append(args, s)
+			}
+		}
+
+		return args
+	default:
+		return nil
+	}
+}
+
+// shellCommandLine joins a "shell": true cmd list into one command line. Words written
+// literally are kept as shell syntax, while words containing variables are quoted after
+// expansion, since paths like $file may contain spaces or parentheses.
+func (p *ProjectParser) shellCommandLine(cmdArgs []string, sourceFile string) string {
+	words := make([]string, 0, len(cmdArgs))
+
+	for _, arg := range cmdArgs {
+		resolved := p.resolveVariables(arg, sourceFile)
+		if resolved != arg {
+			resolved = shellQuote(resolved)
+		}
+
+		words = append(words, resolved)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// shellQuote quotes a word for sublimeShell when it contains characters the shell would interpret
+func shellQuote(word string) string {
+	if runtime.GOOS == "windows" {
+		if word != "" && !strings.ContainsAny(word, " \t\"&|<>()^%!,;=") {
+			return word
+		}
+
+		return `"` + strings.ReplaceAll(word, `"`, `\"`) + `"`
+	}
+
+	if word != "" && !strings.ContainsAny(word, " \t\n\"'\\$`&|;<>()*?[]{}#~!") {
+		return word
+	}
+
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// sublimeShell returns the shell Sublime Text uses for shell_cmd on the current platform
+func sublimeShell() string {
+	if runtime.GOOS == "windows" {
+		return "cmd.exe"
+	}
+
+	return "bash"
+}
+
+// sublimePlatform returns the value of Sublime's $platform variable for goos
+func sublimePlatform(goos string) string {
+	switch goos {
+	case "windows":
+		return "Windows"
+	case "darwin":
+		return "OSX"
+	default:
+		return "Linux"
+	}
+}
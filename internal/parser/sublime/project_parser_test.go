@@ -0,0 +1,166 @@
+package sublime
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectParser(t *testing.T) {
+	t.Run("NewProjectParser", func(t *testing.T) {
+		parser := NewProjectParser("/test/project", nil)
+		require.NotNil(t, parser)
+		require.Equal(t, "/test/project", parser.projectRoot)
+	})
+
+	t.Run("ParseProject", func(t *testing.T) {
+		projectFile := filepath.Join("testdata", "app.sublime-project")
+
+		projectDir, err := filepath.Abs("testdata")
+		require.NoError(t, err)
+
+		parser := NewProjectParser(projectDir, nil)
+		tasks, err := parser.ParseProject(projectFile)
+		require.NoError(t, err)
+
+		// The build system without cmd/shell_cmd is skipped
+		require.Len(t, tasks, 5)
+
+		names := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+
+		require.Equal(t, []string{"Go Build", "Go Build - Test", "Go Build - Race", "Lint", "Echo Project"}, names)
+
+		t.Run("cmd array", func(t *testing.T) {
+			task := tasks[0]
+			require.Equal(t, config.TypeSublime, task.Type)
+			require.Equal(t, "build", task.Group)
+			require.Equal(t, "go", task.Command)
+			require.Equal(t, []string{"build", "-o", filepath.Join(projectDir, "bin", "app"), "./..."}, task.Args)
+			require.Equal(t, projectDir, task.Cwd)
+			require.Equal(t, "0", task.Env["CGO_ENABLED"])
+			require.Empty(t, task.Shell)
+			require.Equal(t, projectFile, task.Source)
+		})
+
+		t.Run("variants inherit unset fields", func(t *testing.T) {
+			testTask := tasks[1]
+			require.Equal(t, "go", testTask.Command)
+			require.Equal(t, []string{"test", "./..."}, testTask.Args)
+			require.Equal(t, "0", testTask.Env["CGO_ENABLED"])
+
+			raceTask := tasks[2]
+			require.Equal(t, tasks[0].Args, raceTask.Args)
+			require.Equal(t, "0", raceTask.Env["CGO_ENABLED"])
+			require.Equal(t, "-race", raceTask.Env["GOFLAGS"])
+		})
+
+		t.Run("shell_cmd runs through the shell", func(t *testing.T) {
+			task := tasks[3]
+			require.NotEmpty(t, task.Shell)
+			require.Equal(t, "golangci-lint run ${fileDirname}", task.Command)
+			require.Empty(t, task.Args)
+			require.Equal(t, filepath.Join(projectDir, "tools"), task.Cwd)
+		})
+
+		t.Run("project variables and defaults", func(t *testing.T) {
+			task := tasks[4]
+			require.Equal(t, []string{"app", "fallback"}, task.Args)
+		})
+	})
+
+	t.Run("shell cmd list quotes expanded paths", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("POSIX quoting")
+		}
+
+		projectDir := filepath.Join(t.TempDir(), "My Projects", "app (fork)")
+		projectFile := filepath.Join(projectDir, "app.sublime-project")
+
+		require.NoError(t, os.MkdirAll(projectDir, 0755))
+		require.NoError(t, os.WriteFile(projectFile, []byte(`{
+    "build_systems": [
+        {"name": "Check", "shell": true, "cmd": ["make", "-C", "$project_path", "check", "&&", "echo", "done"]}
+    ]
+}`), 0644))
+
+		tasks, err := NewProjectParser(projectDir, nil).ParseProject(projectFile)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, "make -C '"+projectDir+"' check && echo done", tasks[0].Command)
+	})
+
+	t.Run("ParseProject errors", func(t *testing.T) {
+		parser := NewProjectParser("/test/project", nil)
+
+		_, err := parser.ParseProject(filepath.Join("testdata", "missing.sublime-project"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing.sublime-project")
+	})
+
+	t.Run("resolveVariables", func(t *testing.T) {
+		parser := NewProjectParser("/home/user/project", nil)
+		sourceFile := "/home/user/project/app.sublime-project"
+
+		tests := []struct {
+			name     string
+			input    string
+			expected string
+		}{
+			{
+				name:     "project path with braces",
+				input:    "${project_path}/src",
+				expected: "/home/user/project/src",
+			},
+			{
+				name:     "folder without braces",
+				input:    "$folder/bin",
+				expected: "/home/user/project/bin",
+			},
+			{
+				name:     "project name",
+				input:    "$project_name",
+				expected: "app.sublime-project",
+			},
+			{
+				name:     "file maps to VSCode placeholder",
+				input:    "$file",
+				expected: "${file}",
+			},
+			{
+				name:     "file base name",
+				input:    "${file_base_name}.o",
+				expected: "${fileBasenameNoExtension}.o",
+			},
+			{
+				name:     "unknown variable is kept",
+				input:    "$HOME/bin",
+				expected: "$HOME/bin",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require.Equal(t, tt.expected, parser.resolveVariables(tt.input, sourceFile))
+			})
+		}
+	})
+
+	t.Run("applyPlatformOverrides", func(t *testing.T) {
+		buildSystem := SublimeBuildSystem{
+			Name:    "Run",
+			Cmd:     []interface{}{"./run.sh"},
+			Windows: &SublimeBuildSystem{Cmd: []interface{}{"run.bat"}},
+		}
+
+		require.Equal(t, []string{"run.bat"}, cmdList(applyPlatformOverrides(buildSystem, "windows").Cmd))
+		require.Equal(t, []string{"./run.sh"}, cmdList(applyPlatformOverrides(buildSystem, "linux").Cmd))
+	})
+}
@@ -0,0 +1,27 @@
+package sublime
+
+// SublimeProject represents the structure of a Sublime Text .sublime-project file
+type SublimeProject struct {
+	Folders      []SublimeFolder      `json:"folders,omitempty"`
+	BuildSystems []SublimeBuildSystem `json:"build_systems,omitempty"`
+}
+
+// SublimeFolder represents a folder entry in a Sublime Text project
+type SublimeFolder struct {
+	Path string `json:"path"`
+}
+
+// SublimeBuildSystem represents a single build system in a Sublime Text project
+type SublimeBuildSystem struct {
+	Name       string               `json:"name"`
+	Cmd        interface{}          `json:"cmd,omitempty"` // Can be string or array of strings
+	ShellCmd   string               `json:"shell_cmd,omitempty"`
+	Shell      bool                 `json:"shell,omitempty"`
+	WorkingDir string               `json:"working_dir,omitempty"`
+	Env        map[string]string    `json:"env,omitempty"`
+	Selector   string               `json:"selector,omitempty"`
+	Variants   []SublimeBuildSystem `json:"variants,omitempty"`
+	Windows    *SublimeBuildSystem  `json:"windows,omitempty"`
+	OSX        *SublimeBuildSystem  `json:"osx,omitempty"`
+	Linux      *SublimeBuildSystem  `json:"linux,omitempty"`
+}
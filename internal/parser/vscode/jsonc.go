@@ -1,28 +1,256 @@
 package vscode
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"strings"
+
+	"github.com/syndbg/taskporter/internal/logging"
 )
 
+// JSONCSyntaxError describes a JSONC parse failure at a position in the original (pre-strip) text
+type JSONCSyntaxError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+// Error implements the error interface
+func (e *JSONCSyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *JSONCSyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// errUnterminatedBlockComment is reported when a /* comment is never closed
+var errUnterminatedBlockComment = errors.New("unterminated block comment")
+
+// ParseJSONC parses JSON with comments, the dialect VSCode shares with other editors'
+// configuration files (e.g. Sublime Text projects)
+func ParseJSONC(data []byte, v interface{}) error {
+	return parseJSONC(data, v)
+}
+
 // parseJSONC parses JSON with comments (JSONC format) commonly used by VSCode
 func parseJSONC(data []byte, v interface{}) error {
+	_, err := parseJSONCTolerant(data, v)
+
+	return err
+}
+
+// IsBlankJSONC reports whether data holds nothing but whitespace and comments, like the 0-byte
+// launch.json VSCode sometimes leaves behind. Such files define nothing rather than being invalid.
+func IsBlankJSONC(data []byte) bool {
+	stripped, err := stripJSONCommentsStrict(string(data))
+
+	return err == nil && strings.TrimSpace(stripped) == ""
+}
+
+// Constructs a JSONC parse accepts that strict JSON parsers reject
+const (
+	ToleratedComments       = "comments"
+	ToleratedTrailingCommas = "trailing commas"
+)
+
+// parseJSONCTolerant parses JSONC like VSCode does, accepting comments and trailing commas
+// before } and ], and reports which of those constructs the data relied on
+func parseJSONCTolerant(data []byte, v interface{}) ([]string, error) {
 	// Strip comments from the JSON data
-	stripped := stripJSONComments(string(data))
+	stripped, err := stripJSONCommentsStrict(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var tolerated []string
+	if stripped != string(data) {
+		tolerated = append(tolerated, ToleratedComments)
+	}
+
+	stripped, commas := stripTrailingCommas(stripped)
+	if commas > 0 {
+		tolerated = append(tolerated, ToleratedTrailingCommas)
+	}
+
+	// Parse the cleaned JSON; offsets in stripped text match the original
+	err = json.Unmarshal([]byte(stripped), v)
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return nil, newJSONCSyntaxError(data, syntaxErr.Offset, err)
+	case errors.As(err, &typeErr):
+		return nil, newJSONCSyntaxError(data, typeErr.Offset, err)
+	case err != nil:
+		return nil, err
+	default:
+		return tolerated, nil
+	}
+}
+
+// logTolerated notes when a file only parsed because of JSONC leniency
+func logTolerated(logger *slog.Logger, path string, tolerated []string) {
+	if len(tolerated) > 0 {
+		logger.Debug("accepted JSONC that strict JSON parsers reject", logging.KeyFile, path, "constructs", strings.Join(tolerated, ", "))
+	}
+}
+
+// RemoveTrailingCommas deletes the commas directly before } and ] in JSONC data, leaving
+// comments and everything else untouched. It returns the rewritten data and the number of commas removed.
+func RemoveTrailingCommas(data []byte) ([]byte, int, error) {
+	stripped, err := stripJSONCommentsStrict(string(data))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Comments are blanked in place, so offsets in stripped text are offsets in data
+	offsets := trailingCommaOffsets(stripped)
+	if len(offsets) == 0 {
+		return data, 0, nil
+	}
+
+	fixed := make([]byte, 0, len(data)-len(offsets))
+	last := 0
+
+	for _, offset := range offsets {
+		fixed = append(fixed, data[last:offset]...)
+		last = offset + 1
+	}
+
+	fixed = append(fixed, data[last:]...)
+
+	return fixed, len(offsets), nil
+}
+
+// stripTrailingCommas blanks the commas directly before } and ], keeping byte offsets.
+// The input must already have its comments stripped.
+func stripTrailingCommas(jsonStr string) (string, int) {
+	offsets := trailingCommaOffsets(jsonStr)
+	if len(offsets) == 0 {
+		return jsonStr, 0
+	}
+
+	result := []byte(jsonStr)
+	for _, offset := range offsets {
+		result[offset] = ' '
+	}
+
+	return string(result), len(offsets)
+}
+
+// trailingCommaOffsets finds commas outside strings that follow a value and are followed,
+// after whitespace, by } or ]. Commas after [, { or another comma are left for the JSON
+// parser to report. The input must already have its comments stripped.
+func trailingCommaOffsets(jsonStr string) []int {
+	var (
+		offsets  []int
+		inString bool
+		escaped  bool
+		previous byte // Last significant byte outside strings
+	)
+
+	for i := 0; i < len(jsonStr); i++ {
+		char := jsonStr[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case char == '\\':
+				escaped = true
+			case char == '"':
+				inString = false
+				previous = char
+			}
+
+			continue
+		}
+
+		switch char {
+		case '"':
+			inString = true
+		case ' ', '\t', '\n', '\r':
+			// Whitespace is not significant
+		case ',':
+			if previous != 0 && previous != '[' && previous != '{' && previous != ',' && previous != ':' && closesAfter(jsonStr, i+1) {
+				offsets = append(offsets, i)
+				continue
+			}
+
+			previous = char
+		default:
+			previous = char
+		}
+	}
+
+	return offsets
+}
+
+// closesAfter reports whether the next non-whitespace byte from start closes an object or array
+func closesAfter(jsonStr string, start int) bool {
+	for i := start; i < len(jsonStr); i++ {
+		switch jsonStr[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '}', ']':
+			return true
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// newJSONCSyntaxError converts a byte offset into a 1-based line and column in data
+func newJSONCSyntaxError(data []byte, offset int64, err error) *JSONCSyntaxError {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+
+	// Point at the last byte read rather than one past it
+	if len(before) > 0 && before[len(before)-1] != '\n' {
+		before = before[:len(before)-1]
+	}
+
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
 
-	// Parse the cleaned JSON
-	return json.Unmarshal([]byte(stripped), v)
+	return &JSONCSyntaxError{
+		Line:   bytes.Count(before, []byte{'\n'}) + 1,
+		Column: len(before) - lineStart + 1,
+		Err:    err,
+	}
 }
 
 // stripJSONComments removes both line comments (//) and block comments (/* */)
-// from JSON data while preserving strings that might contain comment-like sequences
+// from JSON data while preserving strings that might contain comment-like sequences.
+// Comments are replaced with spaces (keeping line breaks) so byte offsets and line
+// numbers in the result map directly back to the original text.
 func stripJSONComments(jsonStr string) string {
+	stripped, _ := stripJSONCommentsStrict(jsonStr)
+
+	return stripped
+}
+
+// stripJSONCommentsStrict behaves like stripJSONComments but reports an unterminated
+// block comment as a JSONCSyntaxError positioned at the opening /*
+func stripJSONCommentsStrict(jsonStr string) (string, error) {
 	var (
 		result   strings.Builder
 		inString bool
 		escaped  bool
 	)
 
+	result.Grow(len(jsonStr))
+
 	for i := 0; i < len(jsonStr); i++ {
 		char := jsonStr[i]
 
@@ -59,10 +287,11 @@ func stripJSONComments(jsonStr string) string {
 			continue
 		}
 
-		// Handle line comments (//)
+		// Handle line comments (//) - blank until end of line
 		if char == '/' && i+1 < len(jsonStr) && jsonStr[i+1] == '/' {
-			// Skip until end of line
 			for i < len(jsonStr) && jsonStr[i] != '\n' && jsonStr[i] != '\r' {
+				result.WriteByte(' ')
+
 				i++
 			}
 			// Don't increment i again at the end of the loop
@@ -71,20 +300,20 @@ func stripJSONComments(jsonStr string) string {
 			continue
 		}
 
-		// Handle block comments (/* */)
+		// Handle block comments (/* */) - blank everything but line breaks
 		if char == '/' && i+1 < len(jsonStr) && jsonStr[i+1] == '*' {
-			// Skip until we find the closing */
-			i += 2 // Skip /*
-			for i+1 < len(jsonStr) {
-				if jsonStr[i] == '*' && jsonStr[i+1] == '/' {
-					i += 2 // Skip */
-					break
-				}
+			end := strings.Index(jsonStr[i+2:], "*/")
+			if end < 0 {
+				result.WriteString(blankComment(jsonStr[i:]))
 
-				i++
+				return result.String(), newJSONCSyntaxError([]byte(jsonStr), int64(i+1), errUnterminatedBlockComment)
 			}
+
+			commentEnd := i + 2 + end + 2
+			result.WriteString(blankComment(jsonStr[i:commentEnd]))
+
 			// Don't increment i again at the end of the loop
-			i--
+			i = commentEnd - 1
 
 			continue
 		}
@@ -93,5 +322,18 @@ func stripJSONComments(jsonStr string) string {
 		result.WriteByte(char)
 	}
 
-	return result.String()
+	return result.String(), nil
+}
+
+// blankComment replaces every byte of a comment with a space, keeping line breaks
+func blankComment(comment string) string {
+	blanked := []byte(comment)
+
+	for i, b := range blanked {
+		if b != '\n' && b != '\r' {
+			blanked[i] = ' '
+		}
+	}
+
+	return string(blanked)
 }
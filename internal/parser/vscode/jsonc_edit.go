@@ -0,0 +1,98 @@
+package vscode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONCArray locates an array of a JSONC document by byte offsets, so its elements can be
+// replaced or appended to without rewriting (and losing the comments of) the rest of the file
+type JSONCArray struct {
+	Open     int // Offset of the [
+	Close    int // Offset of the ]
+	Elements []JSONCElement
+}
+
+// JSONCElement is one element of a JSONCArray
+type JSONCElement struct {
+	Start int             // Offset of the element's first byte
+	End   int             // Offset just past the element's last byte
+	Value json.RawMessage // The element without comments
+}
+
+// LocateJSONCArray finds the array under key in the top-level object of JSONC data
+func LocateJSONCArray(data []byte, key string) (*JSONCArray, error) {
+	stripped, err := stripJSONCommentsStrict(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments and trailing commas are blanked in place, so offsets in stripped are offsets in data
+	stripped, _ = stripTrailingCommas(stripped)
+	text := []byte(stripped)
+
+	decoder := json.NewDecoder(bytes.NewReader(text))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if token != key {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+			return nil, fmt.Errorf("%q is not an array", key)
+		}
+
+		array := &JSONCArray{Open: int(decoder.InputOffset()) - 1}
+
+		for decoder.More() {
+			start := skipSeparators(text, int(decoder.InputOffset()))
+
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return nil, err
+			}
+
+			array.Elements = append(array.Elements, JSONCElement{Start: start, End: int(decoder.InputOffset()), Value: value})
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		array.Close = int(decoder.InputOffset()) - 1
+
+		return array, nil
+	}
+
+	return nil, fmt.Errorf("no %q array found", key)
+}
+
+// skipSeparators returns the offset of the first byte from start that is neither whitespace
+// nor the comma between two array elements
+func skipSeparators(text []byte, start int) int {
+	for start < len(text) {
+		switch text[start] {
+		case ' ', '\t', '\n', '\r', ',':
+			start++
+		default:
+			return start
+		}
+	}
+
+	return start
+}
@@ -1,6 +1,9 @@
 package vscode
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -24,7 +27,7 @@ func TestStripJSONComments(t *testing.T) {
 				"value": 123
 			}`,
 			expected: `{
-				"name": "test", 
+				"name": "test",                     
 				"value": 123
 			}`,
 		},
@@ -37,7 +40,7 @@ func TestStripJSONComments(t *testing.T) {
 			}`,
 			expected: `{
 				"name": "test",
-				
+				                         
 				"value": 123
 			}`,
 		},
@@ -48,7 +51,7 @@ func TestStripJSONComments(t *testing.T) {
 				"value": 123
 			}`,
 			expected: `{
-				"name": "test", 
+				"name": "test",                    
 				"value": 123
 			}`,
 		},
@@ -63,7 +66,9 @@ func TestStripJSONComments(t *testing.T) {
 			}`,
 			expected: `{
 				"name": "test",
-				
+				            
+                
+                 
 				"value": 123
 			}`,
 		},
@@ -88,7 +93,7 @@ func TestStripJSONComments(t *testing.T) {
 			}`,
 			expected: `{
 				"name": "test \"quoted\" // not a comment",
-				"value": 123 
+				"value": 123                  
 			}`,
 		},
 		{
@@ -101,10 +106,10 @@ func TestStripJSONComments(t *testing.T) {
 				"enabled": true
 			}`,
 			expected: `{
-				
-				"name": "test", 
-				"value": 123, 
-				
+				                        
+				"name": "test",                   
+				"value": 123,                
+				                           
 				"enabled": true
 			}`,
 		},
@@ -119,8 +124,8 @@ func TestStripJSONComments(t *testing.T) {
 			}`,
 			expected: `{
 				"items": [
-					"first", 
-					"second", 
+					"first",             
+					"second",                
 					"third"
 				]
 			}`,
@@ -271,3 +276,258 @@ func TestParseJSONC(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestStripJSONCommentsPreservesPositions(t *testing.T) {
+	t.Run("line numbers and offsets are unchanged", func(t *testing.T) {
+		input := "{\n  // comment\n  /* multi\n     line */ \"a\": 1 /* x */\n}"
+
+		result := stripJSONComments(input)
+		require.Len(t, result, len(input))
+		require.Equal(t, strings.Count(input, "\n"), strings.Count(result, "\n"))
+		require.Equal(t, strings.Index(input, `"a"`), strings.Index(result, `"a"`))
+	})
+
+	t.Run("multi-byte characters in comments keep byte offsets", func(t *testing.T) {
+		input := `{/* ünïcödé ✓ */"a": 1}`
+
+		result := stripJSONComments(input)
+		require.Len(t, result, len(input))
+		require.Equal(t, strings.Index(input, `"a"`), strings.Index(result, `"a"`))
+	})
+
+	t.Run("unterminated block comment is blanked instead of leaking text", func(t *testing.T) {
+		result := stripJSONComments(`{"a": 1} /* never closed`)
+		require.Equal(t, `{"a": 1}`, strings.TrimRight(result, " "))
+	})
+}
+
+func TestParseJSONCErrors(t *testing.T) {
+	t.Run("unterminated block comment", func(t *testing.T) {
+		var result map[string]interface{}
+
+		err := parseJSONC([]byte("{\n  \"a\": 1,\n  /* oops\n  \"b\": 2\n}"), &result)
+		require.Error(t, err)
+
+		var syntaxErr *JSONCSyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		require.Equal(t, 3, syntaxErr.Line)
+		require.Equal(t, 3, syntaxErr.Column)
+		require.ErrorIs(t, err, errUnterminatedBlockComment)
+	})
+
+	t.Run("syntax error position refers to original text", func(t *testing.T) {
+		input := "{\n  // leading comment\n  /* a\n     block */\n  \"a\": 1,\n  \"b\": ]\n}"
+
+		var result map[string]interface{}
+
+		err := parseJSONC([]byte(input), &result)
+		require.Error(t, err)
+
+		var syntaxErr *JSONCSyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		require.Equal(t, 6, syntaxErr.Line)
+		require.Equal(t, 8, syntaxErr.Column)
+		require.Contains(t, err.Error(), "line 6, column 8")
+	})
+
+	t.Run("type error position refers to original text", func(t *testing.T) {
+		var result struct {
+			Version string `json:"version"`
+		}
+
+		err := parseJSONC([]byte("{\n  // comment\n  \"version\": 2\n}"), &result)
+		require.Error(t, err)
+
+		var syntaxErr *JSONCSyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		require.Equal(t, 3, syntaxErr.Line)
+	})
+}
+
+func TestTrailingCommas(t *testing.T) {
+	t.Run("stripTrailingCommas", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			input    string
+			expected string
+			count    int
+		}{
+			{
+				name:     "object and array closers",
+				input:    `{"a": [1, 2,], "b": {"c": true,},}`,
+				expected: `{"a": [1, 2 ], "b": {"c": true } }`,
+				count:    3,
+			},
+			{
+				name:     "whitespace and line breaks before the closer",
+				input:    "{\n  \"a\": 1,\n\n}",
+				expected: "{\n  \"a\": 1 \n\n}",
+				count:    1,
+			},
+			{
+				name:     "commas inside strings are kept",
+				input:    `{"a": ",}", "b": ",]", "c": [",", "x,"],}`,
+				expected: `{"a": ",}", "b": ",]", "c": [",", "x,"] }`,
+				count:    1,
+			},
+			{
+				name:     "escaped quotes do not end strings",
+				input:    `{"a": "say \",}\"",}`,
+				expected: `{"a": "say \",}\"" }`,
+				count:    1,
+			},
+			{
+				name:     "nested structures",
+				input:    `[[1, [2,],], {"a": [{"b": 1,},],},]`,
+				expected: `[[1, [2 ] ], {"a": [{"b": 1 } ] } ]`,
+				count:    6,
+			},
+			{
+				name:     "commas between values are kept",
+				input:    `{"a": 1, "b": [1, 2]}`,
+				expected: `{"a": 1, "b": [1, 2]}`,
+				count:    0,
+			},
+			{
+				name:     "commas without a preceding value are left to the parser",
+				input:    `{"a": [,], "b": {,}, "c": [1,,]}`,
+				expected: `{"a": [,], "b": {,}, "c": [1,,]}`,
+				count:    0,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, count := stripTrailingCommas(tt.input)
+				require.Equal(t, tt.expected, result)
+				require.Equal(t, tt.count, count)
+				require.Len(t, result, len(tt.input))
+			})
+		}
+	})
+
+	t.Run("parseJSONC accepts trailing commas like VSCode", func(t *testing.T) {
+		input := `{
+			"version": "2.0.0",
+			"tasks": [
+				{
+					"label": "build, then test",
+					"args": ["-v", "./...",], // trailing comma after a comment-free line
+				},
+				{
+					"label": "lint",
+					"args": ["--fix" /* block */ ,],
+				},
+			],
+		}`
+
+		var result VSCodeTaskFile
+
+		tolerated, err := parseJSONCTolerant([]byte(input), &result)
+		require.NoError(t, err)
+		require.Equal(t, []string{ToleratedComments, ToleratedTrailingCommas}, tolerated)
+		require.Len(t, result.Tasks, 2)
+		require.Equal(t, "build, then test", result.Tasks[0].Label)
+		require.Equal(t, []string{"-v", "./..."}, result.Tasks[0].Args.Values())
+		require.Equal(t, []string{"--fix"}, result.Tasks[1].Args.Values())
+	})
+
+	t.Run("strict JSON reports nothing tolerated", func(t *testing.T) {
+		var result map[string]interface{}
+
+		tolerated, err := parseJSONCTolerant([]byte(`{"a": [1, 2], "b": "x,]"}`), &result)
+		require.NoError(t, err)
+		require.Empty(t, tolerated)
+	})
+
+	t.Run("syntax errors still point at the original text", func(t *testing.T) {
+		var result map[string]interface{}
+
+		err := parseJSONC([]byte("{\n  \"a\": [1,],\n  \"b\": ]\n}"), &result)
+
+		var syntaxErr *JSONCSyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		require.Equal(t, 3, syntaxErr.Line)
+	})
+
+	t.Run("RemoveTrailingCommas keeps comments", func(t *testing.T) {
+		input := "{\n  // build \"tasks\",\n  \"a\": [1, /* two, */ 2,],\n  \"b\": \"x,}\",\n}"
+
+		fixed, removed, err := RemoveTrailingCommas([]byte(input))
+		require.NoError(t, err)
+		require.Equal(t, 2, removed)
+		require.Equal(t, "{\n  // build \"tasks\",\n  \"a\": [1, /* two, */ 2],\n  \"b\": \"x,}\"\n}", string(fixed))
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(stripJSONComments(string(fixed))), &result))
+	})
+}
+
+func FuzzStripJSONComments(f *testing.F) {
+	f.Add(`{"name": "test", // comment` + "\n" + `"value": 1}`)
+	f.Add(`{"a": "/* not a comment */", /* block */ "b": 2}`)
+	f.Add(`{"a": "escaped \" // quote"} /* unterminated`)
+	f.Add(`/*/`)
+	f.Add(`"\`)
+	f.Add("//\r\n/**/")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		result, _ := stripJSONCommentsStrict(input)
+
+		// Positions must map one-to-one back to the original text
+		require.Len(t, result, len(input))
+
+		for i := 0; i < len(input); i++ {
+			if input[i] == '\n' || input[i] == '\r' {
+				require.Equal(t, input[i], result[i], "line break at offset %d", i)
+			}
+		}
+	})
+}
+
+func TestLocateJSONCArray(t *testing.T) {
+	t.Run("should locate the elements of an array between comments", func(t *testing.T) {
+		data := []byte("{\n  \"version\": \"2.0.0\", // [not, this]\n  \"tasks\": [\n    /* first */ {\"label\": \"a\"},\n    {\"label\": \"b,]\"}, // last\n  ]\n}\n")
+
+		array, err := LocateJSONCArray(data, "tasks")
+		require.NoError(t, err)
+
+		require.Equal(t, byte('['), data[array.Open])
+		require.Equal(t, byte(']'), data[array.Close])
+		require.Len(t, array.Elements, 2)
+		require.Equal(t, `{"label": "a"}`, string(data[array.Elements[0].Start:array.Elements[0].End]))
+		require.Equal(t, `{"label": "b,]"}`, string(data[array.Elements[1].Start:array.Elements[1].End]))
+		require.JSONEq(t, `{"label": "b,]"}`, string(array.Elements[1].Value))
+	})
+
+	t.Run("should fail without the array", func(t *testing.T) {
+		_, err := LocateJSONCArray([]byte(`{"tasks": {}}`), "tasks")
+		require.EqualError(t, err, `"tasks" is not an array`)
+
+		_, err = LocateJSONCArray([]byte(`{"version": "2.0.0"}`), "tasks")
+		require.EqualError(t, err, `no "tasks" array found`)
+
+		_, err = LocateJSONCArray([]byte(`[]`), "tasks")
+		require.Error(t, err)
+	})
+}
+
+func FuzzParseJSONC(f *testing.F) {
+	f.Add([]byte(`{"version": "2.0.0", "tasks": [{"label": "build"}]}`))
+	f.Add([]byte("{\n  // comment\n  \"tasks\": [\n"))
+	f.Add([]byte(`{"tasks": [/* unterminated`))
+	f.Add([]byte(`{"version": 2}`))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var taskFile VSCodeTaskFile
+
+		err := parseJSONC(data, &taskFile)
+
+		var syntaxErr *JSONCSyntaxError
+		if errors.As(err, &syntaxErr) {
+			require.GreaterOrEqual(t, syntaxErr.Line, 1)
+			require.GreaterOrEqual(t, syntaxErr.Column, 1)
+		}
+	})
+}
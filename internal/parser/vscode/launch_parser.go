@@ -1,61 +1,131 @@
 package vscode
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"log/slog"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/shellwords"
 )
 
 // LaunchParser handles parsing of VSCode launch.json files
 type LaunchParser struct {
+	ctx         context.Context
 	projectRoot string
+	settings    *VSCodeSettings
+	goos        string // OS whose platform blocks are applied
+	logger      *slog.Logger
 }
 
 // NewLaunchParser creates a new VSCode launch parser
-func NewLaunchParser(projectRoot string) *LaunchParser {
+func NewLaunchParser(projectRoot string, logger *slog.Logger) *LaunchParser {
 	return &LaunchParser{
+		ctx:         context.Background(),
 		projectRoot: projectRoot,
+		goos:        runtime.GOOS,
+		logger:      logging.OrDiscard(logger).With(logging.KeyComponent, "vscode-launch"),
 	}
 }
 
+// SetTargetOS sets the OS ("linux", "darwin" or "windows") whose "linux", "osx" or "windows"
+// blocks are merged into configurations, for generating configs for another platform
+func (p *LaunchParser) SetTargetOS(goos string) {
+	p.goos = goos
+}
+
+// NewLaunchParserWithSettings creates a launch parser that applies settings.json terminal env
+func NewLaunchParserWithSettings(projectRoot string, settings *VSCodeSettings, logger *slog.Logger) *LaunchParser {
+	parser := NewLaunchParser(projectRoot, logger)
+	parser.settings = settings
+
+	return parser
+}
+
+// SetContext bounds the parser's file reads by ctx, e.g. an --op-timeout deadline
+func (p *LaunchParser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
 // ParseLaunchConfigs parses a VSCode launch.json file and returns internal Task structures
 func (p *LaunchParser) ParseLaunchConfigs(launchFilePath string) ([]*config.Task, error) {
-	data, err := os.ReadFile(launchFilePath)
+	data, err := config.ReadFileContext(p.ctx, launchFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read launch file %s: %w", launchFilePath, err)
 	}
 
+	if IsBlankJSONC(data) {
+		p.logger.Debug("launch file is empty", logging.KeyFile, launchFilePath)
+		return nil, nil
+	}
+
 	var launchFile VSCodeLaunchFile
-	if err := parseJSONC(data, &launchFile); err != nil {
-		return nil, fmt.Errorf("failed to parse launch JSON: %w", err)
+
+	tolerated, err := parseJSONCTolerant(data, &launchFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse launch file %s: %w", launchFilePath, err)
 	}
 
+	logTolerated(p.logger, launchFilePath, tolerated)
+
 	var tasks []*config.Task
 
 	for _, vscodeConfig := range launchFile.Configurations {
 		task, err := p.convertLaunchConfig(vscodeConfig, launchFilePath)
 		if err != nil {
 			// Log error but continue with other configs
-			fmt.Printf("Warning: failed to convert launch config %s: %v\n", vscodeConfig.Name, err)
+			p.logger.Warn("failed to convert launch config", logging.KeyFile, launchFilePath, logging.KeyTask, vscodeConfig.Name, "error", err)
+			continue
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	for _, compound := range launchFile.Compounds {
+		task, err := p.convertCompound(compound, launchFilePath)
+		if err != nil {
+			p.logger.Warn("failed to convert launch compound", logging.KeyFile, launchFilePath, logging.KeyTask, compound.Name, "error", err)
 			continue
 		}
 
 		tasks = append(tasks, task)
 	}
 
+	p.logger.Debug("parsed launch file", logging.KeyFile, launchFilePath, "configurations", len(tasks))
+
 	return tasks, nil
 }
 
 // convertLaunchConfig converts a VSCode launch config to our internal Task structure
 func (p *LaunchParser) convertLaunchConfig(vscodeConfig VSCodeLaunchConfig, sourceFile string) (*config.Task, error) {
+	var platform string
+
+	if vscodeConfig.HasPlatformBlock(p.goos) {
+		platform = platformKey(p.goos)
+
+		merged, err := vscodeConfig.ForPlatform(p.goos)
+		if err != nil {
+			return nil, err
+		}
+
+		vscodeConfig = merged
+	}
+
 	task := &config.Task{
-		Name:        vscodeConfig.Name,
-		Type:        config.TypeVSCodeLaunch,
-		Source:      sourceFile,
-		Description: fmt.Sprintf("%s %s configuration", vscodeConfig.Type, vscodeConfig.Request),
+		Name:          vscodeConfig.Name,
+		Type:          config.TypeVSCodeLaunch,
+		Source:        sourceFile,
+		Description:   fmt.Sprintf("%s %s configuration", vscodeConfig.Type, vscodeConfig.Request),
+		Confirm:       vscodeConfig.Confirm,
+		PreLaunchTask: vscodeConfig.PreLaunchTask,
+		PostDebugTask: vscodeConfig.PostDebugTask,
+		Platform:      platform,
+		Passthrough:   vscodeConfig.Raw,
+		UnreadFields:  vscodeConfig.UnreadKeys(),
 	}
 
 	// Handle different launch types
@@ -72,6 +142,10 @@ func (p *LaunchParser) convertLaunchConfig(vscodeConfig VSCodeLaunchConfig, sour
 		if err := p.handlePythonLaunchConfig(vscodeConfig, task); err != nil {
 			return nil, err
 		}
+	case "java":
+		if err := p.handleJavaLaunchConfig(vscodeConfig, task); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported launch type: %s", vscodeConfig.Type)
 	}
@@ -99,6 +173,15 @@ func (p *LaunchParser) convertLaunchConfig(vscodeConfig VSCodeLaunchConfig, sour
 		}
 	}
 
+	// Terminal consoles imply the program may read stdin or draw a TUI
+	task.Console = vscodeConfig.Console
+	task.Interactive = vscodeConfig.Console == "integratedTerminal" || vscodeConfig.Console == "externalTerminal"
+
+	// Terminal env from settings.json has the lowest precedence
+	if p.settings != nil {
+		task.Env = mergeTerminalEnv(p.settings.EnvForPlatform(p.goos), task.Env)
+	}
+
 	// Set group based on request type
 	switch vscodeConfig.Request {
 	case "launch":
@@ -112,11 +195,42 @@ func (p *LaunchParser) convertLaunchConfig(vscodeConfig VSCodeLaunchConfig, sour
 	return task, nil
 }
 
+// convertCompound converts a launch.json compound to a task that starts its configurations together
+func (p *LaunchParser) convertCompound(compound VSCodeLaunchCompound, sourceFile string) (*config.Task, error) {
+	var members []string
+
+	for _, member := range compound.Configurations {
+		switch m := member.(type) {
+		case string:
+			members = append(members, m)
+		case map[string]interface{}:
+			// Multi-root workspaces qualify the name with a folder; a project only has one
+			if name, ok := m["name"].(string); ok && name != "" {
+				members = append(members, name)
+			}
+		}
+	}
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("compound has no configurations")
+	}
+
+	return &config.Task{
+		Name:          compound.Name,
+		Type:          config.TypeVSCodeLaunch,
+		Source:        sourceFile,
+		Description:   fmt.Sprintf("compound of %d configurations", len(members)),
+		Group:         "compound",
+		Cwd:           p.projectRoot,
+		DependsOn:     members,
+		DependsOrder:  config.DependsOrderParallel,
+		PreLaunchTask: compound.PreLaunchTask,
+	}, nil
+}
+
 // resolveWorkspacePath resolves VSCode workspace variables in paths
 func (p *LaunchParser) resolveWorkspacePath(path string) string {
-	// Replace common VSCode variables
-	resolved := strings.ReplaceAll(path, "${workspaceFolder}", p.projectRoot)
-	resolved = strings.ReplaceAll(resolved, "${workspaceRoot}", p.projectRoot)
+	resolved := resolveWorkspaceVariables(path, p.projectRoot)
 
 	// Handle relative paths
 	if !filepath.IsAbs(resolved) {
@@ -126,27 +240,6 @@ func (p *LaunchParser) resolveWorkspacePath(path string) string {
 	return resolved
 }
 
-// GetPreLaunchTask returns the preLaunchTask name if specified
-func (p *LaunchParser) GetPreLaunchTask(launchFilePath string, configName string) (string, error) {
-	data, err := os.ReadFile(launchFilePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read launch file: %w", err)
-	}
-
-	var launchFile VSCodeLaunchFile
-	if err := parseJSONC(data, &launchFile); err != nil {
-		return "", fmt.Errorf("failed to parse launch JSON: %w", err)
-	}
-
-	for _, config := range launchFile.Configurations {
-		if config.Name == configName {
-			return config.PreLaunchTask, nil
-		}
-	}
-
-	return "", fmt.Errorf("launch configuration '%s' not found", configName)
-}
-
 // handleGoLaunchConfig handles Go-specific launch configuration
 func (p *LaunchParser) handleGoLaunchConfig(vscodeConfig VSCodeLaunchConfig, task *config.Task) error {
 	switch vscodeConfig.Request {
@@ -159,10 +252,9 @@ func (p *LaunchParser) handleGoLaunchConfig(vscodeConfig VSCodeLaunchConfig, tas
 			task.Args = []string{"run"}
 		}
 
-		// Add program path
+		// Add program path: go run builds a package directory as a whole and a .go file on its own
 		if vscodeConfig.Program != "" {
-			programPath := p.resolveWorkspacePath(vscodeConfig.Program)
-			task.Args = append(task.Args, programPath)
+			task.Args = append(task.Args, p.resolveWorkspacePath(vscodeConfig.Program))
 		} else {
 			task.Args = append(task.Args, ".")
 		}
@@ -239,3 +331,48 @@ func (p *LaunchParser) handlePythonLaunchConfig(vscodeConfig VSCodeLaunchConfig,
 
 	return nil
 }
+
+// handleJavaLaunchConfig handles Java-specific launch configuration
+func (p *LaunchParser) handleJavaLaunchConfig(vscodeConfig VSCodeLaunchConfig, task *config.Task) error {
+	switch vscodeConfig.Request {
+	case "launch":
+		task.Command = "java"
+
+		if vscodeConfig.MainClass == "" {
+			return fmt.Errorf("java launch config requires mainClass")
+		}
+
+		// JVM options go before the main class, program arguments after it
+		task.Args = append(parseVMArgs(vscodeConfig.VMArgs), vscodeConfig.MainClass)
+		task.Args = append(task.Args, vscodeConfig.Args...)
+
+	case "attach":
+		return fmt.Errorf("java attach mode not yet supported")
+
+	default:
+		return fmt.Errorf("unsupported Java request type: %s", vscodeConfig.Request)
+	}
+
+	return nil
+}
+
+// parseVMArgs reads vmArgs, which is either one string of (possibly quoted) arguments or an
+// array of strings
+func parseVMArgs(vmArgs interface{}) []string {
+	switch v := vmArgs.(type) {
+	case string:
+		return shellwords.Split(v)
+	case []interface{}:
+		args := make([]string, 0, len(v))
+
+		for _, arg := range v {
+			if s, ok := arg.(string); ok {
+				args = append(args, s)
+			}
+		}
+
+		return args
+	}
+
+	return nil
+}
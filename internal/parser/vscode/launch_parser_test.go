@@ -1,17 +1,19 @@
 package vscode
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestLaunchParser(t *testing.T) {
 	t.Run("NewLaunchParser", func(t *testing.T) {
-		parser := NewLaunchParser("/test/project")
+		parser := NewLaunchParser("/test/project", nil)
 		require.NotNil(t, parser)
 		require.Equal(t, "/test/project", parser.projectRoot)
 	})
@@ -22,7 +24,7 @@ func TestLaunchParser(t *testing.T) {
 			testDataPath := filepath.Join("..", "..", "test", "testdata", ".vscode", "launch.json")
 			projectRoot := filepath.Join("..", "..", "test", "testdata")
 
-			parser := NewLaunchParser(projectRoot)
+			parser := NewLaunchParser(projectRoot, nil)
 			tasks, err := parser.ParseLaunchConfigs(testDataPath)
 
 			require.NoError(t, err)
@@ -55,11 +57,22 @@ func TestLaunchParser(t *testing.T) {
 			}
 		})
 
+		t.Run("should treat empty files and empty configuration lists as no configurations", func(t *testing.T) {
+			for _, content := range []string{"", "\n  \n", "// launch configurations go here\n", `{"version": "0.2.0", "configurations": []}`} {
+				launchPath := filepath.Join(t.TempDir(), "launch.json")
+				require.NoError(t, os.WriteFile(launchPath, []byte(content), 0644))
+
+				tasks, err := NewLaunchParser(t.TempDir(), nil).ParseLaunchConfigs(launchPath)
+				require.NoError(t, err, "content %q", content)
+				require.Empty(t, tasks)
+			}
+		})
+
 		t.Run("should parse specific launch config properties", func(t *testing.T) {
 			testDataPath := filepath.Join("..", "..", "test", "testdata", ".vscode", "launch.json")
 			projectRoot := filepath.Join("..", "..", "test", "testdata")
 
-			parser := NewLaunchParser(projectRoot)
+			parser := NewLaunchParser(projectRoot, nil)
 			tasks, err := parser.ParseLaunchConfigs(testDataPath)
 
 			require.NoError(t, err)
@@ -85,6 +98,9 @@ func TestLaunchParser(t *testing.T) {
 				require.Equal(t, "launch", launchTaskporter.Group)
 				require.NotNil(t, launchTaskporter.Env)
 				require.Equal(t, "true", launchTaskporter.Env["DEBUG"])
+				require.False(t, launchTaskporter.Interactive)
+				require.Equal(t, "build", launchTaskporter.PreLaunchTask)
+				require.Empty(t, launchTaskporter.PostDebugTask)
 			})
 
 			t.Run("Debug taskporter port properties", func(t *testing.T) {
@@ -99,13 +115,15 @@ func TestLaunchParser(t *testing.T) {
 				require.Contains(t, debugPort.Args, "--dry-run")
 				require.NotNil(t, debugPort.Env)
 				require.Equal(t, "1", debugPort.Env["VERBOSE"])
+				require.Equal(t, "integratedTerminal", debugPort.Console)
+				require.True(t, debugPort.Interactive)
 			})
 		})
 	})
 
 	t.Run("convertLaunchConfig", func(t *testing.T) {
 		projectRoot := "/test/project"
-		parser := NewLaunchParser(projectRoot)
+		parser := NewLaunchParser(projectRoot, nil)
 
 		t.Run("Go launch configuration", func(t *testing.T) {
 			vscodeConfig := VSCodeLaunchConfig{
@@ -118,7 +136,9 @@ func TestLaunchParser(t *testing.T) {
 				Env: map[string]string{
 					"GO_ENV": "test",
 				},
-				Cwd: "${workspaceFolder}/subdir",
+				Cwd:           "${workspaceFolder}/subdir",
+				PreLaunchTask: "build",
+				PostDebugTask: "cleanup",
 			}
 
 			task, err := parser.convertLaunchConfig(vscodeConfig, "/test/launch.json")
@@ -134,6 +154,37 @@ func TestLaunchParser(t *testing.T) {
 			require.Equal(t, "launch", task.Group)
 			require.Equal(t, filepath.Join(projectRoot, "subdir"), task.Cwd)
 			require.Equal(t, "test", task.Env["GO_ENV"])
+			require.Equal(t, "build", task.PreLaunchTask)
+			require.Equal(t, "cleanup", task.PostDebugTask)
+		})
+
+		t.Run("Go launch configuration of a package or a file", func(t *testing.T) {
+			goRoot := t.TempDir()
+			goParser := NewLaunchParser(goRoot, nil)
+
+			tests := []struct {
+				name         string
+				program      string
+				expectedArgs []string
+			}{
+				{"package directory", "cmd/server", []string{"run", filepath.Join(goRoot, "cmd", "server"), "--port", "8080"}},
+				{"single file", "${workspaceFolder}/cmd/server/main.go", []string{"run", filepath.Join(goRoot, "cmd", "server", "main.go"), "--port", "8080"}},
+			}
+
+			for _, tt := range tests {
+				t.Run("should run a "+tt.name+" with go run", func(t *testing.T) {
+					task, err := goParser.convertLaunchConfig(VSCodeLaunchConfig{
+						Name:    "server",
+						Type:    "go",
+						Request: "launch",
+						Program: tt.program,
+						Args:    []string{"--port", "8080"},
+					}, filepath.Join(goRoot, ".vscode", "launch.json"))
+					require.NoError(t, err)
+					require.Equal(t, "go", task.Command)
+					require.Equal(t, tt.expectedArgs, task.Args)
+				})
+			}
 		})
 
 		t.Run("Node.js launch configuration", func(t *testing.T) {
@@ -182,6 +233,64 @@ func TestLaunchParser(t *testing.T) {
 			require.Equal(t, projectRoot, task.Env["PYTHONPATH"])
 		})
 
+		t.Run("Java launch configuration", func(t *testing.T) {
+			for _, vmArgs := range []interface{}{"-Xmx512m -ea", []interface{}{"-Xmx512m", "-ea"}} {
+				vscodeConfig := VSCodeLaunchConfig{
+					Name:      "test-java-launch",
+					Type:      "java",
+					Request:   "launch",
+					MainClass: "com.example.Main",
+					VMArgs:    vmArgs,
+					Args:      []string{"--port", "8080"},
+				}
+
+				task, err := parser.convertLaunchConfig(vscodeConfig, "/test/launch.json")
+				require.NoError(t, err)
+				require.Equal(t, "java", task.Command)
+				require.Equal(t, []string{"-Xmx512m", "-ea", "com.example.Main", "--port", "8080"}, task.Args)
+			}
+
+			task, err := parser.convertLaunchConfig(VSCodeLaunchConfig{
+				Name:      "quoted",
+				Type:      "java",
+				Request:   "launch",
+				MainClass: "com.example.Main",
+				VMArgs:    `-Dgreeting="hello world" -ea`,
+			}, "/test/launch.json")
+			require.NoError(t, err)
+			require.Equal(t, []string{"-Dgreeting=hello world", "-ea", "com.example.Main"}, task.Args)
+
+			_, err = parser.convertLaunchConfig(VSCodeLaunchConfig{Name: "no-main", Type: "java", Request: "launch"}, "/test/launch.json")
+			require.ErrorContains(t, err, "requires mainClass")
+		})
+
+		t.Run("console kinds", func(t *testing.T) {
+			tests := []struct {
+				console     string
+				interactive bool
+			}{
+				{console: "integratedTerminal", interactive: true},
+				{console: "externalTerminal", interactive: true},
+				{console: "internalConsole", interactive: false},
+				{console: "", interactive: false},
+			}
+
+			for _, tt := range tests {
+				vscodeConfig := VSCodeLaunchConfig{
+					Name:    "console-" + tt.console,
+					Type:    "node",
+					Request: "launch",
+					Program: "index.js",
+					Console: tt.console,
+				}
+
+				task, err := parser.convertLaunchConfig(vscodeConfig, "/test/launch.json")
+				require.NoError(t, err)
+				require.Equal(t, tt.console, task.Console)
+				require.Equal(t, tt.interactive, task.Interactive, "console %q", tt.console)
+			}
+		})
+
 		t.Run("unsupported launch type", func(t *testing.T) {
 			vscodeConfig := VSCodeLaunchConfig{
 				Name:    "test-unsupported",
@@ -209,12 +318,73 @@ func TestLaunchParser(t *testing.T) {
 		})
 	})
 
+	t.Run("ParseLaunchConfigs with compounds", func(t *testing.T) {
+		recorder, logger := logging.NewRecorder()
+		parser := NewLaunchParser("/test/project", logger)
+
+		tasks, err := parser.ParseLaunchConfigs(filepath.Join("testdata", "launch_with_compounds.json"))
+		require.NoError(t, err)
+		require.Len(t, tasks, 3)
+
+		compound := tasks[2]
+		require.Equal(t, "Full Stack", compound.Name)
+		require.Equal(t, config.TypeVSCodeLaunch, compound.Type)
+		require.True(t, compound.IsCompound())
+		require.Equal(t, []string{"Backend", "Frontend"}, compound.DependsOn)
+		require.Equal(t, "build", compound.PreLaunchTask)
+		require.Equal(t, "/test/project", compound.Cwd)
+
+		// A compound without configurations has nothing to start
+		entries := recorder.Entries()
+		require.Equal(t, "failed to convert launch compound", entries[0].Message)
+		require.Equal(t, "Nothing", entries[0].Attrs[logging.KeyTask])
+	})
+
+	t.Run("ParseLaunchConfigs with platform blocks", func(t *testing.T) {
+		parse := func(t *testing.T, goos string) []*config.Task {
+			parser := NewLaunchParser("/test/project", nil)
+			parser.SetTargetOS(goos)
+
+			tasks, err := parser.ParseLaunchConfigs(filepath.Join("testdata", "launch_with_platforms.json"))
+			require.NoError(t, err)
+			require.Len(t, tasks, 2)
+
+			return tasks
+		}
+
+		t.Run("should let the platform block win for scalars and merge env per key", func(t *testing.T) {
+			server := parse(t, "windows")[0]
+			require.Equal(t, "windows", server.Platform)
+			require.Equal(t, []string{"/test/project/server.win.js", "--port", "8080"}, server.Args)
+			require.Equal(t, map[string]string{"MODE": "dev", "SCRIPT_SHELL": "cmd"}, server.Env)
+		})
+
+		t.Run("should replace arrays rather than append to them", func(t *testing.T) {
+			server := parse(t, "darwin")[0]
+			require.Equal(t, "osx", server.Platform)
+			require.Equal(t, []string{"/test/project/server.js", "--port", "9090"}, server.Args)
+			require.Equal(t, map[string]string{"MODE": "dev", "SCRIPT_SHELL": "sh"}, server.Env)
+		})
+
+		t.Run("should keep the base values without a block for the platform", func(t *testing.T) {
+			tasks := parse(t, "linux")
+
+			// Blocks for other platforms leave the configuration as written
+			server := tasks[0]
+			require.Empty(t, server.Platform)
+			require.Equal(t, []string{"/test/project/server.js", "--port", "8080"}, server.Args)
+			require.Equal(t, "sh", server.Env["SCRIPT_SHELL"])
+
+			require.Empty(t, tasks[1].Platform)
+		})
+	})
+
 	t.Run("ParseLaunchConfigs with comments", func(t *testing.T) {
 		t.Run("should parse launch.json with comments", func(t *testing.T) {
 			testDataPath := "testdata/launch_with_comments.json"
 			projectRoot := "/test/project"
 
-			parser := NewLaunchParser(projectRoot)
+			parser := NewLaunchParser(projectRoot, nil)
 			tasks, err := parser.ParseLaunchConfigs(testDataPath)
 
 			require.NoError(t, err)
@@ -242,7 +412,7 @@ func TestLaunchParser(t *testing.T) {
 
 	t.Run("resolveWorkspacePath", func(t *testing.T) {
 		projectRoot := "/home/user/project"
-		parser := NewLaunchParser(projectRoot)
+		parser := NewLaunchParser(projectRoot, nil)
 
 		tests := []struct {
 			name     string
@@ -278,28 +448,4 @@ func TestLaunchParser(t *testing.T) {
 			})
 		}
 	})
-
-	t.Run("GetPreLaunchTask", func(t *testing.T) {
-		testDataPath := filepath.Join("..", "..", "test", "testdata", ".vscode", "launch.json")
-		parser := NewLaunchParser("/test")
-
-		t.Run("config with preLaunchTask", func(t *testing.T) {
-			preLaunchTask, err := parser.GetPreLaunchTask(testDataPath, "Launch taskporter")
-			require.NoError(t, err)
-			require.Equal(t, "build", preLaunchTask)
-		})
-
-		t.Run("config without preLaunchTask", func(t *testing.T) {
-			preLaunchTask, err := parser.GetPreLaunchTask(testDataPath, "Debug taskporter list")
-			require.NoError(t, err)
-			require.Empty(t, preLaunchTask)
-		})
-
-		t.Run("nonexistent config", func(t *testing.T) {
-			preLaunchTask, err := parser.GetPreLaunchTask(testDataPath, "Nonexistent Config")
-			require.Error(t, err)
-			require.Empty(t, preLaunchTask)
-			require.Contains(t, err.Error(), "not found")
-		})
-	})
 }
@@ -0,0 +1,211 @@
+package vscode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// Settings keys read from .vscode/settings.json
+const (
+	terminalEnvKeyPrefix            = "terminal.integrated.env."
+	terminalDefaultProfileKeyPrefix = "terminal.integrated.defaultProfile."
+	terminalProfilesKeyPrefix       = "terminal.integrated.profiles."
+	terminalLegacyShellKeyPrefix    = "terminal.integrated.shell."
+)
+
+// VSCodeSettings holds the subset of .vscode/settings.json that affects how tasks run
+type VSCodeSettings struct {
+	// TerminalEnv maps a platform key (linux, osx, windows) to its terminal environment
+	TerminalEnv map[string]map[string]string
+	// DefaultShells maps a platform key to the resolved default shell executable
+	DefaultShells map[string]string
+}
+
+// VSCodeTerminalProfile represents a single terminal profile entry
+type VSCodeTerminalProfile struct {
+	Path interface{} `json:"path,omitempty"` // Can be string or array of strings
+	Args interface{} `json:"args,omitempty"`
+}
+
+// SettingsParser handles parsing of VSCode settings.json files
+type SettingsParser struct {
+	ctx         context.Context
+	projectRoot string
+}
+
+// NewSettingsParser creates a new VSCode settings parser
+func NewSettingsParser(projectRoot string) *SettingsParser {
+	return &SettingsParser{
+		ctx:         context.Background(),
+		projectRoot: projectRoot,
+	}
+}
+
+// SetContext bounds the parser's file reads by ctx, e.g. an --op-timeout deadline
+func (p *SettingsParser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// ParseSettings parses a VSCode settings.json file and extracts terminal env and default shells
+func (p *SettingsParser) ParseSettings(settingsFilePath string) (*VSCodeSettings, error) {
+	data, err := config.ReadFileContext(p.ctx, settingsFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings file %s: %w", settingsFilePath, err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := parseJSONC(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse settings file %s: %w", settingsFilePath, err)
+	}
+
+	settings := &VSCodeSettings{
+		TerminalEnv:   make(map[string]map[string]string),
+		DefaultShells: make(map[string]string),
+	}
+
+	for _, platform := range []string{"linux", "osx", "windows"} {
+		if env := p.parseTerminalEnv(raw[terminalEnvKeyPrefix+platform]); len(env) > 0 {
+			settings.TerminalEnv[platform] = env
+		}
+
+		if shell := p.resolveDefaultShell(raw, platform); shell != "" {
+			settings.DefaultShells[platform] = shell
+		}
+	}
+
+	return settings, nil
+}
+
+// parseTerminalEnv decodes a terminal.integrated.env.* object, skipping null (unset) entries
+func (p *SettingsParser) parseTerminalEnv(data json.RawMessage) map[string]string {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil
+	}
+
+	env := make(map[string]string)
+
+	for key, value := range values {
+		if s, ok := value.(string); ok {
+			env[key] = resolveWorkspaceVariables(s, p.projectRoot)
+		}
+	}
+
+	return env
+}
+
+// resolveDefaultShell determines the default shell executable for a platform
+func (p *SettingsParser) resolveDefaultShell(raw map[string]json.RawMessage, platform string) string {
+	var profileName string
+	if data, ok := raw[terminalDefaultProfileKeyPrefix+platform]; ok {
+		_ = json.Unmarshal(data, &profileName)
+	}
+
+	if profileName != "" {
+		var profiles map[string]VSCodeTerminalProfile
+		if data, ok := raw[terminalProfilesKeyPrefix+platform]; ok {
+			_ = json.Unmarshal(data, &profiles)
+		}
+
+		if profile, ok := profiles[profileName]; ok {
+			if path := firstString(profile.Path); path != "" {
+				return path
+			}
+		}
+
+		return builtinProfileShell(profileName)
+	}
+
+	// Fall back to the deprecated terminal.integrated.shell.* setting
+	var legacyShell string
+	if data, ok := raw[terminalLegacyShellKeyPrefix+platform]; ok {
+		_ = json.Unmarshal(data, &legacyShell)
+	}
+
+	return legacyShell
+}
+
+// EnvForPlatform returns the terminal environment for the given GOOS value
+func (s *VSCodeSettings) EnvForPlatform(goos string) map[string]string {
+	if s == nil {
+		return nil
+	}
+
+	return s.TerminalEnv[platformKey(goos)]
+}
+
+// ShellForPlatform returns the default shell for the given GOOS value
+func (s *VSCodeSettings) ShellForPlatform(goos string) string {
+	if s == nil {
+		return ""
+	}
+
+	return s.DefaultShells[platformKey(goos)]
+}
+
+// mergeTerminalEnv layers task env on top of the terminal env so task values win
+func mergeTerminalEnv(terminalEnv, taskEnv map[string]string) map[string]string {
+	if len(terminalEnv) == 0 {
+		return taskEnv
+	}
+
+	merged := make(map[string]string, len(terminalEnv)+len(taskEnv))
+	for k, v := range terminalEnv {
+		merged[k] = v
+	}
+
+	for k, v := range taskEnv {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// platformKey maps a GOOS value to the platform suffix VSCode uses in settings keys
+func platformKey(goos string) string {
+	switch goos {
+	case "darwin":
+		return "osx"
+	case "windows":
+		return "windows"
+	default:
+		return "linux"
+	}
+}
+
+// builtinProfileShell maps VSCode's auto-detected profile names to executables
+func builtinProfileShell(profileName string) string {
+	switch profileName {
+	case "PowerShell":
+		return "powershell.exe"
+	case "Command Prompt":
+		return "cmd.exe"
+	case "Git Bash":
+		return "bash"
+	default:
+		return profileName
+	}
+}
+
+// firstString returns a string value or the first element of a string array
+func firstString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		if len(v) > 0 {
+			if s, ok := v[0].(string); ok {
+				return s
+			}
+		}
+	}
+
+	return ""
+}
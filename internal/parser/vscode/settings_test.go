@@ -0,0 +1,129 @@
+package vscode
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsParser(t *testing.T) {
+	t.Run("NewSettingsParser", func(t *testing.T) {
+		parser := NewSettingsParser("/test/project")
+		require.NotNil(t, parser)
+		require.Equal(t, "/test/project", parser.projectRoot)
+	})
+
+	t.Run("ParseSettings", func(t *testing.T) {
+		parser := NewSettingsParser("/test/project")
+
+		settings, err := parser.ParseSettings("testdata/settings_with_terminal.json")
+		require.NoError(t, err)
+		require.NotNil(t, settings)
+
+		t.Run("terminal env per platform", func(t *testing.T) {
+			linuxEnv := settings.TerminalEnv["linux"]
+			require.Equal(t, "-mod=vendor", linuxEnv["GOFLAGS"])
+			require.Equal(t, "/test/project", linuxEnv["PROJECT_HOME"])
+			require.NotContains(t, linuxEnv, "UNSET_ME")
+
+			require.Equal(t, "-mod=vendor", settings.TerminalEnv["osx"]["GOFLAGS"])
+			require.Empty(t, settings.TerminalEnv["windows"])
+		})
+
+		t.Run("default shells", func(t *testing.T) {
+			require.Equal(t, "/usr/bin/zsh", settings.ShellForPlatform("linux"))
+			require.Equal(t, "/bin/bash", settings.ShellForPlatform("darwin"))
+			require.Equal(t, "powershell.exe", settings.ShellForPlatform("windows"))
+		})
+
+		t.Run("missing file", func(t *testing.T) {
+			_, err := parser.ParseSettings("testdata/does-not-exist.json")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "failed to read settings file")
+		})
+	})
+
+	t.Run("nil settings are safe", func(t *testing.T) {
+		var settings *VSCodeSettings
+
+		require.Nil(t, settings.EnvForPlatform("linux"))
+		require.Empty(t, settings.ShellForPlatform("linux"))
+	})
+
+	t.Run("tasks parser applies settings", func(t *testing.T) {
+		tempDir := t.TempDir()
+		tasksFile := filepath.Join(tempDir, "tasks.json")
+		tasksJSON := `{
+			"version": "2.0.0",
+			"tasks": [
+				{"label": "shell-task", "type": "shell", "command": "make", "options": {"env": {"GOFLAGS": "-mod=mod"}}},
+				{"label": "process-task", "type": "process", "command": "make"}
+			]
+		}`
+		require.NoError(t, os.WriteFile(tasksFile, []byte(tasksJSON), 0644))
+
+		platform := platformKey(runtime.GOOS)
+		settings := &VSCodeSettings{
+			TerminalEnv: map[string]map[string]string{
+				platform: {"GOFLAGS": "-mod=vendor", "EXTRA": "1"},
+			},
+			DefaultShells: map[string]string{platform: "/bin/sh"},
+		}
+
+		parser := NewTasksParserWithSettings(tempDir, settings, nil)
+		tasks, err := parser.ParseTasks(tasksFile)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+
+		// Task env wins over terminal env
+		require.Equal(t, "-mod=mod", tasks[0].Env["GOFLAGS"])
+		require.Equal(t, "1", tasks[0].Env["EXTRA"])
+		require.Equal(t, "/bin/sh", tasks[0].Shell)
+
+		// Process tasks get the env but never a shell
+		require.Equal(t, "-mod=vendor", tasks[1].Env["GOFLAGS"])
+		require.Empty(t, tasks[1].Shell)
+	})
+
+	t.Run("null task env unsets terminal env", func(t *testing.T) {
+		tempDir := t.TempDir()
+		tasksFile := filepath.Join(tempDir, "tasks.json")
+		tasksJSON := `{
+			"version": "2.0.0",
+			"tasks": [
+				{"label": "unset-task", "type": "shell", "command": "make", "options": {"env": {"GOFLAGS": "-mod=mod", "EXTRA": null}}},
+				{"label": "process-task", "type": "process", "command": "make"}
+			]
+		}`
+		require.NoError(t, os.WriteFile(tasksFile, []byte(tasksJSON), 0644))
+
+		platform := platformKey(runtime.GOOS)
+		settings := &VSCodeSettings{
+			TerminalEnv: map[string]map[string]string{
+				platform: {"GOFLAGS": "-mod=vendor", "EXTRA": "1"},
+			},
+		}
+
+		parser := NewTasksParserWithSettings(tempDir, settings, nil)
+		tasks, err := parser.ParseTasks(tasksFile)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+
+		require.Equal(t, map[string]string{"GOFLAGS": "-mod=mod"}, tasks[0].Env)
+		require.Equal(t, []string{"EXTRA"}, tasks[0].UnsetEnv)
+
+		// Other tasks keep the terminal env
+		require.Equal(t, "1", tasks[1].Env["EXTRA"])
+		require.Empty(t, tasks[1].UnsetEnv)
+	})
+
+	t.Run("platformKey", func(t *testing.T) {
+		require.Equal(t, "osx", platformKey("darwin"))
+		require.Equal(t, "windows", platformKey("windows"))
+		require.Equal(t, "linux", platformKey("linux"))
+		require.Equal(t, "linux", platformKey("freebsd"))
+	})
+}
@@ -0,0 +1,178 @@
+package vscode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// VSCodeTaskArg is a tasks.json argument, written either as a plain string or as
+// {"value": "...", "quoting": "escape" | "strong" | "weak"} to control how a shell task quotes it
+type VSCodeTaskArg struct {
+	Value   string
+	Quoting string // config.QuotingEscape, QuotingStrong or QuotingWeak; empty for plain strings
+}
+
+// VSCodeTaskArgs is the args array of a tasks.json task
+type VSCodeTaskArgs []VSCodeTaskArg
+
+// vscodeTaskArgObject is the object form of an argument
+type vscodeTaskArgObject struct {
+	Value   json.RawMessage `json:"value"`
+	Quoting string          `json:"quoting,omitempty"`
+}
+
+// UnmarshalJSON accepts both the string and the object form
+func (a *VSCodeTaskArg) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*a = VSCodeTaskArg{Value: value}
+		return nil
+	}
+
+	var object vscodeTaskArgObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("task arg must be a string or an object with value and quoting: %w", err)
+	}
+
+	if err := json.Unmarshal(object.Value, &value); err != nil {
+		return fmt.Errorf("task arg value must be a string: %s", object.Value)
+	}
+
+	switch object.Quoting {
+	case "", config.QuotingEscape, config.QuotingStrong, config.QuotingWeak:
+	default:
+		return fmt.Errorf("unknown quoting '%s' for task arg '%s' (expected escape, strong or weak)", object.Quoting, value)
+	}
+
+	*a = VSCodeTaskArg{Value: value, Quoting: object.Quoting}
+
+	return nil
+}
+
+// MarshalJSON writes arguments without quoting as plain strings, so files round-trip as written
+func (a VSCodeTaskArg) MarshalJSON() ([]byte, error) {
+	if a.Quoting == "" {
+		return json.Marshal(a.Value)
+	}
+
+	return json.Marshal(struct {
+		Value   string `json:"value"`
+		Quoting string `json:"quoting"`
+	}{a.Value, a.Quoting})
+}
+
+// VSCodeTaskCommand is the command of a tasks.json task, written as a string, a quoted-string
+// object or an array (argv form) whose first element is the command and the rest its leading
+// arguments. Whatever the form, it holds one element per written string.
+type VSCodeTaskCommand []VSCodeTaskArg
+
+// UnmarshalJSON accepts the string, object and array forms
+func (c *VSCodeTaskCommand) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var parts []VSCodeTaskArg
+		if err := json.Unmarshal(trimmed, &parts); err != nil {
+			return fmt.Errorf("task command array must hold strings or objects with value and quoting: %w", err)
+		}
+
+		*c = parts
+
+		return nil
+	}
+
+	var command VSCodeTaskArg
+	if err := json.Unmarshal(trimmed, &command); err != nil {
+		return fmt.Errorf("task command must be a string, an object with value and quoting, or an array of them: %w", err)
+	}
+
+	*c = VSCodeTaskCommand{command}
+
+	return nil
+}
+
+// MarshalJSON writes a single command as a string or object and several elements as an array,
+// so files round-trip as written
+func (c VSCodeTaskCommand) MarshalJSON() ([]byte, error) {
+	if len(c) == 1 {
+		return json.Marshal(c[0])
+	}
+
+	return json.Marshal([]VSCodeTaskArg(c))
+}
+
+// PlainTaskCommand wraps a command written as a plain string
+func PlainTaskCommand(command string) VSCodeTaskCommand {
+	if command == "" {
+		return nil
+	}
+
+	return VSCodeTaskCommand{{Value: command}}
+}
+
+// Value returns the command itself, the first element of the array form
+func (c VSCodeTaskCommand) Value() string {
+	if len(c) == 0 {
+		return ""
+	}
+
+	return c[0].Value
+}
+
+// Args returns the arguments fixed by the array form, which come before the task's args
+func (c VSCodeTaskCommand) Args() VSCodeTaskArgs {
+	if len(c) < 2 {
+		return nil
+	}
+
+	return VSCodeTaskArgs(c[1:])
+}
+
+// PlainTaskArgs wraps plain string arguments
+func PlainTaskArgs(values []string) VSCodeTaskArgs {
+	if values == nil {
+		return nil
+	}
+
+	args := make(VSCodeTaskArgs, len(values))
+	for i, value := range values {
+		args[i] = VSCodeTaskArg{Value: value}
+	}
+
+	return args
+}
+
+// Values returns the argument values
+func (a VSCodeTaskArgs) Values() []string {
+	if a == nil {
+		return nil
+	}
+
+	values := make([]string, len(a))
+	for i, arg := range a {
+		values[i] = arg.Value
+	}
+
+	return values
+}
+
+// Quoting returns the quoting style of each argument, or nil when none sets one
+func (a VSCodeTaskArgs) Quoting() []string {
+	var quoting []string
+
+	for i, arg := range a {
+		if arg.Quoting == "" {
+			continue
+		}
+
+		if quoting == nil {
+			quoting = make([]string, len(a))
+		}
+
+		quoting[i] = arg.Quoting
+	}
+
+	return quoting
+}
@@ -1,38 +1,56 @@
 package vscode
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"log/slog"
+	"maps"
 	"path/filepath"
-	"strings"
+	"runtime"
+	"sort"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
 )
 
 // VSCodeTaskFile represents the structure of VSCode tasks.json
 type VSCodeTaskFile struct {
-	Version string       `json:"version"`
-	Tasks   []VSCodeTask `json:"tasks"`
+	Version string             `json:"version"`
+	Options *VSCodeTaskOptions `json:"options,omitempty"` // Defaults for every task's cwd and env
+	Tasks   []VSCodeTask       `json:"tasks"`
 }
 
 // VSCodeTask represents a single task in VSCode tasks.json
 type VSCodeTask struct {
 	Label          string                  `json:"label"`
 	Type           string                  `json:"type"`
-	Command        string                  `json:"command,omitempty"`
-	Args           []string                `json:"args,omitempty"`
+	Command        VSCodeTaskCommand       `json:"command,omitempty"` // Can be string, object or array
+	Args           VSCodeTaskArgs          `json:"args,omitempty"`
 	Group          interface{}             `json:"group,omitempty"` // Can be string or object
 	Options        *VSCodeTaskOptions      `json:"options,omitempty"`
 	Presentation   *VSCodeTaskPresentation `json:"presentation,omitempty"`
 	ProblemMatcher interface{}             `json:"problemMatcher,omitempty"`
-	DependsOn      interface{}             `json:"dependsOn,omitempty"`
+	DependsOn      interface{}             `json:"dependsOn,omitempty"` // Can be string, object or array of either
+	DependsOrder   string                  `json:"dependsOrder,omitempty"`
 	Detail         string                  `json:"detail,omitempty"`
+	Confirm        bool                    `json:"confirm,omitempty"` // taskporter extension: ask before running
+	Windows        *VSCodeTaskPlatform     `json:"windows,omitempty"`
+	Linux          *VSCodeTaskPlatform     `json:"linux,omitempty"`
+	Osx            *VSCodeTaskPlatform     `json:"osx,omitempty"`
+}
+
+// VSCodeTaskPlatform overrides a task's command, args and options on one OS
+type VSCodeTaskPlatform struct {
+	Command VSCodeTaskCommand  `json:"command,omitempty"`
+	Args    VSCodeTaskArgs     `json:"args,omitempty"`
+	Options *VSCodeTaskOptions `json:"options,omitempty"`
 }
 
 // VSCodeTaskOptions represents task execution options
 type VSCodeTaskOptions struct {
-	Cwd string            `json:"cwd,omitempty"`
-	Env map[string]string `json:"env,omitempty"`
+	Cwd string             `json:"cwd,omitempty"`
+	Env map[string]*string `json:"env,omitempty"` // A null value unsets the variable
 }
 
 // VSCodeTaskPresentation represents task presentation options
@@ -49,60 +67,160 @@ type VSCodeTaskGroup struct {
 	IsDefault bool   `json:"isDefault,omitempty"`
 }
 
+// ErrDuplicateTaskLabel is returned in strict mode when two tasks in one file share a label
+var ErrDuplicateTaskLabel = errors.New("duplicate task label")
+
 // TasksParser handles parsing of VSCode tasks.json files
 type TasksParser struct {
+	ctx         context.Context
 	projectRoot string
+	settings    *VSCodeSettings
+	strict      bool
+	goos        string // OS whose platform blocks are applied
+	logger      *slog.Logger
+
+	legacyNoticeShown bool
 }
 
 // NewTasksParser creates a new VSCode tasks parser
-func NewTasksParser(projectRoot string) *TasksParser {
+func NewTasksParser(projectRoot string, logger *slog.Logger) *TasksParser {
 	return &TasksParser{
+		ctx:         context.Background(),
 		projectRoot: projectRoot,
+		goos:        runtime.GOOS,
+		logger:      logging.OrDiscard(logger).With(logging.KeyComponent, "vscode-tasks"),
 	}
 }
 
+// NewTasksParserWithSettings creates a tasks parser that applies settings.json terminal env and shell
+func NewTasksParserWithSettings(projectRoot string, settings *VSCodeSettings, logger *slog.Logger) *TasksParser {
+	parser := NewTasksParser(projectRoot, logger)
+	parser.settings = settings
+
+	return parser
+}
+
+// SetStrict makes problems that would otherwise only be warned about, such as duplicate labels, fail parsing
+func (p *TasksParser) SetStrict(strict bool) {
+	p.strict = strict
+}
+
+// SetTargetOS sets the OS ("linux", "darwin" or "windows") whose "linux", "osx" or "windows"
+// task blocks are applied, and whose terminal settings are used
+func (p *TasksParser) SetTargetOS(goos string) {
+	p.goos = goos
+}
+
+// SetContext bounds the parser's file reads by ctx, e.g. an --op-timeout deadline
+func (p *TasksParser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
 // ParseTasks parses a VSCode tasks.json file and returns internal Task structures
 func (p *TasksParser) ParseTasks(tasksFilePath string) ([]*config.Task, error) {
-	data, err := os.ReadFile(tasksFilePath)
+	taskFile, legacy, tolerated, err := readTasksFile(p.ctx, tasksFilePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read tasks file %s: %w", tasksFilePath, err)
+		return nil, err
+	}
+
+	logTolerated(p.logger, tasksFilePath, tolerated)
+
+	if legacy && !p.legacyNoticeShown {
+		p.legacyNoticeShown = true
+		p.logger.Warn("tasks file uses the legacy 0.1.0 schema; migrate it with `taskporter port --from vscode-tasks --to vscode-tasks --modernize`", logging.KeyFile, tasksFilePath)
 	}
 
-	var taskFile VSCodeTaskFile
-	if err := parseJSONC(data, &taskFile); err != nil {
-		return nil, fmt.Errorf("failed to parse tasks JSON: %w", err)
+	if err := p.checkDuplicateLabels(taskFile.Tasks, tasksFilePath); err != nil {
+		return nil, err
 	}
 
 	var tasks []*config.Task
 
 	for _, vscodeTask := range taskFile.Tasks {
+		vscodeTask.Options = mergeTaskOptions(taskFile.Options, vscodeTask.Options)
+
 		task, err := p.convertTask(vscodeTask, tasksFilePath)
 		if err != nil {
 			// Log error but continue with other tasks
-			fmt.Printf("Warning: failed to convert task %s: %v\n", vscodeTask.Label, err)
+			p.logger.Warn("failed to convert task", logging.KeyFile, tasksFilePath, logging.KeyTask, vscodeTask.Label, "error", err)
 			continue
 		}
 
 		tasks = append(tasks, task)
 	}
 
+	p.logger.Debug("parsed tasks file", logging.KeyFile, tasksFilePath, "tasks", len(tasks))
+
 	return tasks, nil
 }
 
+// checkDuplicateLabels reports tasks whose label was already used earlier in the same file.
+// Lookups by name always find the first definition, so later ones are silently shadowed.
+func (p *TasksParser) checkDuplicateLabels(vscodeTasks []VSCodeTask, tasksFilePath string) error {
+	firstIndex := make(map[string]int, len(vscodeTasks))
+
+	for i, vscodeTask := range vscodeTasks {
+		first, seen := firstIndex[vscodeTask.Label]
+		if !seen {
+			firstIndex[vscodeTask.Label] = i
+			continue
+		}
+
+		if p.strict {
+			return fmt.Errorf("%w %q in %s: task #%d shadows task #%d", ErrDuplicateTaskLabel, vscodeTask.Label, tasksFilePath, first+1, i+1)
+		}
+
+		p.logger.Warn("duplicate task label; only the first definition will run",
+			logging.KeyFile, tasksFilePath, logging.KeyTask, vscodeTask.Label, "first", first+1, "duplicate", i+1)
+	}
+
+	return nil
+}
+
 // convertTask converts a VSCode task to our internal Task structure
 func (p *TasksParser) convertTask(vscodeTask VSCodeTask, sourceFile string) (*config.Task, error) {
+	platform := vscodeTask.platformBlock(p.goos)
+	if platform != nil {
+		vscodeTask = vscodeTask.withPlatform(platform)
+	}
+
+	// The array form of the command fixes the first arguments
+	args := vscodeTask.Args
+	if commandArgs := vscodeTask.Command.Args(); len(commandArgs) > 0 {
+		args = append(append(VSCodeTaskArgs{}, commandArgs...), vscodeTask.Args...)
+	}
+
 	task := &config.Task{
 		Name:        vscodeTask.Label,
 		Type:        config.TypeVSCodeTask,
-		Command:     vscodeTask.Command,
-		Args:        vscodeTask.Args,
+		Execution:   executionKind(vscodeTask.Type),
+		Command:     vscodeTask.Command.Value(),
+		Args:        args.Values(),
+		ArgQuoting:  args.Quoting(),
 		Description: vscodeTask.Detail,
+		Tags:        config.ParseTags(vscodeTask.Detail),
+		Confirm:     vscodeTask.Confirm,
 		Source:      sourceFile,
 	}
 
+	if platform != nil {
+		task.Platform = platformKey(p.goos)
+	}
+
 	// Handle group information
 	task.Group = p.parseGroup(vscodeTask.Group)
 
+	// Handle dependencies
+	task.DependsOn = p.parseDependsOn(vscodeTask.DependsOn)
+	if len(task.DependsOn) > 0 {
+		task.DependsOrder = config.DependsOrderParallel
+		if vscodeTask.DependsOrder == config.DependsOrderSequence {
+			task.DependsOrder = config.DependsOrderSequence
+		}
+	}
+
+	task.ProblemPatterns = p.parseProblemPatterns(vscodeTask.ProblemMatcher)
+
 	// Handle options (cwd and env)
 	if vscodeTask.Options != nil {
 		if vscodeTask.Options.Cwd != "" {
@@ -111,9 +229,35 @@ func (p *TasksParser) convertTask(vscodeTask VSCodeTask, sourceFile string) (*co
 
 		if vscodeTask.Options.Env != nil {
 			task.Env = make(map[string]string)
+
 			for k, v := range vscodeTask.Options.Env {
-				task.Env[k] = v
+				if v == nil {
+					task.UnsetEnv = append(task.UnsetEnv, k)
+					continue
+				}
+
+				task.Env[k] = *v
 			}
+
+			sort.Strings(task.UnsetEnv)
+		}
+	}
+
+	// Apply terminal settings (env has the lowest precedence)
+	if p.settings != nil {
+		task.Env = mergeTerminalEnv(p.settings.EnvForPlatform(p.goos), task.Env)
+
+		// A task unsetting a variable wins over the terminal setting it too
+		for _, key := range task.UnsetEnv {
+			delete(task.Env, key)
+		}
+	}
+
+	// Shell tasks run in the terminal's default profile, or the platform's shell without one
+	if task.Execution == config.ExecutionShell {
+		task.Shell = p.settings.ShellForPlatform(p.goos)
+		if task.Shell == "" {
+			task.Shell = config.DefaultShell(p.goos)
 		}
 	}
 
@@ -125,6 +269,73 @@ func (p *TasksParser) convertTask(vscodeTask VSCodeTask, sourceFile string) (*co
 	return task, nil
 }
 
+// platformBlock returns the task's "windows", "osx" or "linux" block for goos, or nil without one
+func (t VSCodeTask) platformBlock(goos string) *VSCodeTaskPlatform {
+	switch platformKey(goos) {
+	case "windows":
+		return t.Windows
+	case "osx":
+		return t.Osx
+	default:
+		return t.Linux
+	}
+}
+
+// withPlatform returns the task with a platform block applied the way VSCode does: its command
+// and args replace the task's, and its options are layered over the task's like the task's are
+// over the file's top-level options
+func (t VSCodeTask) withPlatform(platform *VSCodeTaskPlatform) VSCodeTask {
+	if len(platform.Command) > 0 {
+		t.Command = platform.Command
+	}
+
+	if platform.Args != nil {
+		t.Args = platform.Args
+	}
+
+	if platform.Options != nil {
+		t.Options = mergeTaskOptions(t.Options, platform.Options)
+	}
+
+	return t
+}
+
+// mergeTaskOptions layers a task's options over the file's top-level ones, like VSCode does: the
+// task's cwd replaces the global one, and its env entries (null ones included, which unset the
+// variable) replace global entries with the same name while the others are inherited
+func mergeTaskOptions(global, task *VSCodeTaskOptions) *VSCodeTaskOptions {
+	if global == nil {
+		return task
+	}
+
+	merged := VSCodeTaskOptions{Cwd: global.Cwd}
+	if task != nil && task.Cwd != "" {
+		merged.Cwd = task.Cwd
+	}
+
+	if global.Env != nil || (task != nil && task.Env != nil) {
+		merged.Env = make(map[string]*string, len(global.Env))
+		maps.Copy(merged.Env, global.Env)
+
+		if task != nil {
+			maps.Copy(merged.Env, task.Env)
+		}
+	}
+
+	return &merged
+}
+
+// executionKind maps the VSCode task type to how its command runs. Other types, such as
+// "npm" or extension-contributed ones, don't say, so they map to an empty kind.
+func executionKind(taskType string) string {
+	switch taskType {
+	case config.ExecutionShell, config.ExecutionProcess:
+		return taskType
+	default:
+		return ""
+	}
+}
+
 // parseGroup extracts group information from VSCode task group field
 func (p *TasksParser) parseGroup(group interface{}) string {
 	if group == nil {
@@ -143,11 +354,63 @@ func (p *TasksParser) parseGroup(group interface{}) string {
 	return ""
 }
 
+// parseDependsOn extracts dependency task names from the VSCode dependsOn field, which
+// may be a label, a {"type", "task"} reference, or an array of either
+func (p *TasksParser) parseDependsOn(dependsOn interface{}) []string {
+	switch d := dependsOn.(type) {
+	case string:
+		if d != "" {
+			return []string{d}
+		}
+	case map[string]interface{}:
+		if name, ok := d["task"].(string); ok && name != "" {
+			return []string{name}
+		}
+	case []interface{}:
+		var names []string
+		for _, item := range d {
+			names = append(names, p.parseDependsOn(item)...)
+		}
+
+		return names
+	}
+
+	return nil
+}
+
+// parseProblemPatterns extracts the pattern regexps from the VSCode problemMatcher field, which
+// may be a matcher name, an object with a pattern (or multi-line pattern array), or an array of either.
+// Only the first line of a multi-line pattern is kept, since that is the line a problem starts on.
+func (p *TasksParser) parseProblemPatterns(problemMatcher interface{}) []string {
+	switch m := problemMatcher.(type) {
+	case string:
+		p.logger.Debug("named problem matcher has no pattern to match", "matcher", m)
+	case map[string]interface{}:
+		pattern := m["pattern"]
+		if patterns, ok := pattern.([]interface{}); ok && len(patterns) > 0 {
+			pattern = patterns[0]
+		}
+
+		if fields, ok := pattern.(map[string]interface{}); ok {
+			if regexp, ok := fields["regexp"].(string); ok && regexp != "" {
+				return []string{regexp}
+			}
+		}
+	case []interface{}:
+		var patterns []string
+		for _, item := range m {
+			patterns = append(patterns, p.parseProblemPatterns(item)...)
+		}
+
+		return patterns
+	}
+
+	return nil
+}
+
 // resolveWorkspacePath resolves VSCode workspace variables in paths
 func (p *TasksParser) resolveWorkspacePath(path string) string {
-	// Replace common VSCode variables
-	resolved := strings.ReplaceAll(path, "${workspaceFolder}", p.projectRoot)
-	resolved = strings.ReplaceAll(resolved, "${workspaceRoot}", p.projectRoot)
+	resolved := resolveWorkspaceVariables(path, p.projectRoot)
 
 	// Handle relative paths
 	if !filepath.IsAbs(resolved) {
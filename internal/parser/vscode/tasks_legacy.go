@@ -0,0 +1,128 @@
+package vscode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// LegacyTasksVersion is the pre-2.0.0 tasks.json schema with one command shared by all tasks
+const LegacyTasksVersion = "0.1.0"
+
+// LegacyTaskFile represents a version 0.1.0 tasks.json, where a top-level command and options
+// apply to every task and each task only contributes its name and extra args
+type LegacyTaskFile struct {
+	Version          string             `json:"version"`
+	Command          string             `json:"command"`
+	Args             []string           `json:"args,omitempty"`
+	IsShellCommand   interface{}        `json:"isShellCommand,omitempty"` // Can be bool or shell object
+	SuppressTaskName bool               `json:"suppressTaskName,omitempty"`
+	Options          *VSCodeTaskOptions `json:"options,omitempty"`
+	Tasks            []LegacyTask       `json:"tasks"`
+}
+
+// LegacyTask represents a single task in a version 0.1.0 tasks.json
+type LegacyTask struct {
+	TaskName         string      `json:"taskName"`
+	Args             []string    `json:"args,omitempty"`
+	SuppressTaskName *bool       `json:"suppressTaskName,omitempty"`
+	IsBuildCommand   bool        `json:"isBuildCommand,omitempty"`
+	IsTestCommand    bool        `json:"isTestCommand,omitempty"`
+	ProblemMatcher   interface{} `json:"problemMatcher,omitempty"`
+}
+
+// ReadTasksFile reads a tasks.json in the 2.0.0 schema, converting version 0.1.0 files on the fly.
+// The returned flag reports whether the file used the legacy schema.
+func ReadTasksFile(ctx context.Context, tasksFilePath string) (*VSCodeTaskFile, bool, error) {
+	taskFile, legacy, _, err := readTasksFile(ctx, tasksFilePath)
+
+	return taskFile, legacy, err
+}
+
+// readTasksFile is ReadTasksFile that also reports the JSONC constructs the file relied on
+func readTasksFile(ctx context.Context, tasksFilePath string) (*VSCodeTaskFile, bool, []string, error) {
+	data, err := config.ReadFileContext(ctx, tasksFilePath)
+	if err != nil {
+		return nil, false, nil, fmt.Errorf("failed to read tasks file %s: %w", tasksFilePath, err)
+	}
+
+	// An empty file defines no tasks, like an empty "tasks" array
+	if IsBlankJSONC(data) {
+		return &VSCodeTaskFile{}, false, nil, nil
+	}
+
+	var taskFile VSCodeTaskFile
+
+	tolerated, err := parseJSONCTolerant(data, &taskFile)
+	if err != nil {
+		return nil, false, nil, fmt.Errorf("failed to parse tasks file %s: %w", tasksFilePath, err)
+	}
+
+	if taskFile.Version != LegacyTasksVersion {
+		return &taskFile, false, tolerated, nil
+	}
+
+	var legacyFile LegacyTaskFile
+	if err := parseJSONC(data, &legacyFile); err != nil {
+		return nil, true, nil, fmt.Errorf("failed to parse legacy tasks file %s: %w", tasksFilePath, err)
+	}
+
+	return legacyFile.Modernize(), true, tolerated, nil
+}
+
+// Modernize converts the legacy file to the 2.0.0 schema. Each task runs the shared command
+// with the global args, then the task name (unless suppressed), then the task's own args.
+func (f *LegacyTaskFile) Modernize() *VSCodeTaskFile {
+	taskType := "process"
+	if isShell, ok := f.IsShellCommand.(bool); (ok && isShell) || (!ok && f.IsShellCommand != nil) {
+		taskType = "shell"
+	}
+
+	modern := &VSCodeTaskFile{
+		Version: "2.0.0",
+		Tasks:   make([]VSCodeTask, 0, len(f.Tasks)),
+	}
+
+	for _, legacyTask := range f.Tasks {
+		suppressTaskName := f.SuppressTaskName
+		if legacyTask.SuppressTaskName != nil {
+			suppressTaskName = *legacyTask.SuppressTaskName
+		}
+
+		args := append([]string(nil), f.Args...)
+		if !suppressTaskName {
+			args = append(args, legacyTask.TaskName)
+		}
+
+		args = append(args, legacyTask.Args...)
+
+		task := VSCodeTask{
+			Label:          legacyTask.TaskName,
+			Type:           taskType,
+			Command:        PlainTaskCommand(f.Command),
+			Args:           PlainTaskArgs(args),
+			ProblemMatcher: legacyTask.ProblemMatcher,
+		}
+
+		if len(args) == 0 {
+			task.Args = nil
+		}
+
+		switch {
+		case legacyTask.IsBuildCommand:
+			task.Group = "build"
+		case legacyTask.IsTestCommand:
+			task.Group = "test"
+		}
+
+		if f.Options != nil {
+			options := *f.Options
+			task.Options = &options
+		}
+
+		modern.Tasks = append(modern.Tasks, task)
+	}
+
+	return modern
+}
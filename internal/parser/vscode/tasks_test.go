@@ -1,17 +1,21 @@
 package vscode
 
 import (
+	"context"
+	"encoding/json"
+	"log/slog"
 	"path/filepath"
 	"testing"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestTasksParser(t *testing.T) {
 	t.Run("NewTasksParser", func(t *testing.T) {
-		parser := NewTasksParser("/test/project")
+		parser := NewTasksParser("/test/project", nil)
 		require.NotNil(t, parser)
 		require.Equal(t, "/test/project", parser.projectRoot)
 	})
@@ -22,7 +26,7 @@ func TestTasksParser(t *testing.T) {
 			testDataPath := filepath.Join("..", "..", "test", "testdata", ".vscode", "tasks.json")
 			projectRoot := filepath.Join("..", "..", "test", "testdata")
 
-			parser := NewTasksParser(projectRoot)
+			parser := NewTasksParser(projectRoot, nil)
 			tasks, err := parser.ParseTasks(testDataPath)
 
 			require.NoError(t, err)
@@ -61,7 +65,7 @@ func TestTasksParser(t *testing.T) {
 			testDataPath := filepath.Join("..", "..", "test", "testdata", ".vscode", "tasks.json")
 			projectRoot := filepath.Join("..", "..", "test", "testdata")
 
-			parser := NewTasksParser(projectRoot)
+			parser := NewTasksParser(projectRoot, nil)
 			tasks, err := parser.ParseTasks(testDataPath)
 
 			require.NoError(t, err)
@@ -99,7 +103,7 @@ func TestTasksParser(t *testing.T) {
 			testDataPath := "testdata/tasks_with_comments.json"
 			projectRoot := "/test/project"
 
-			parser := NewTasksParser(projectRoot)
+			parser := NewTasksParser(projectRoot, nil)
 			tasks, err := parser.ParseTasks(testDataPath)
 
 			require.NoError(t, err)
@@ -135,23 +139,344 @@ func TestTasksParser(t *testing.T) {
 			require.Equal(t, "true", devTask.Env["DEBUG"])
 			require.Equal(t, "true", devTask.Env["RELOAD"])
 		})
+
+		t.Run("should report file and position for unterminated comments", func(t *testing.T) {
+			testDataPath := "testdata/tasks_unterminated_comment.json"
+
+			parser := NewTasksParser("/test/project", nil)
+			tasks, err := parser.ParseTasks(testDataPath)
+
+			require.Error(t, err)
+			require.Nil(t, tasks)
+			require.Contains(t, err.Error(), testDataPath)
+			require.Contains(t, err.Error(), "line 5, column 9")
+			require.ErrorIs(t, err, errUnterminatedBlockComment)
+		})
+	})
+
+	t.Run("ParseTasks with legacy 0.1.0 schema", func(t *testing.T) {
+		projectRoot := "/test/project"
+		testDataPath := "testdata/tasks_legacy.json"
+
+		t.Run("should apply the shared command and options to each task", func(t *testing.T) {
+			parser := NewTasksParser(projectRoot, nil)
+			tasks, err := parser.ParseTasks(testDataPath)
+
+			require.NoError(t, err)
+			require.Len(t, tasks, 3)
+
+			for _, task := range tasks {
+				require.Equal(t, config.TypeVSCodeTask, task.Type)
+				require.Equal(t, "npm", task.Command)
+				require.Equal(t, filepath.Join(projectRoot, "web"), task.Cwd)
+				require.Equal(t, "development", task.Env["NODE_ENV"])
+			}
+
+			// Global args, then the task name, then the task's own args
+			require.Equal(t, "build", tasks[0].Name)
+			require.Equal(t, []string{"run", "--silent", "build", "--", "--production"}, tasks[0].Args)
+			require.Equal(t, "build", tasks[0].Group)
+
+			require.Equal(t, "test", tasks[1].Name)
+			require.Equal(t, []string{"run", "--silent", "test"}, tasks[1].Args)
+			require.Equal(t, "test", tasks[1].Group)
+
+			// suppressTaskName leaves the task name out of the command line
+			require.Equal(t, "lint", tasks[2].Name)
+			require.Equal(t, []string{"run", "--silent", "lint:fix"}, tasks[2].Args)
+			require.Empty(t, tasks[2].Group)
+		})
+
+		t.Run("should suggest migrating the file once", func(t *testing.T) {
+			recorder, logger := logging.NewRecorder()
+			parser := NewTasksParser(projectRoot, logger)
+
+			_, err := parser.ParseTasks(testDataPath)
+			require.NoError(t, err)
+
+			_, err = parser.ParseTasks(testDataPath)
+			require.NoError(t, err)
+
+			var notices []logging.Entry
+
+			for _, entry := range recorder.Entries() {
+				if entry.Level == slog.LevelWarn {
+					notices = append(notices, entry)
+				}
+			}
+
+			require.Len(t, notices, 1)
+			require.Contains(t, notices[0].Message, "--modernize")
+			require.Equal(t, testDataPath, notices[0].Attrs[logging.KeyFile])
+		})
+
+		t.Run("Modernize", func(t *testing.T) {
+			taskFile, legacy, err := ReadTasksFile(context.Background(), testDataPath)
+			require.NoError(t, err)
+			require.True(t, legacy)
+			require.Equal(t, "2.0.0", taskFile.Version)
+			require.Equal(t, "shell", taskFile.Tasks[0].Type)
+			require.Equal(t, "${workspaceFolder}/web", taskFile.Tasks[0].Options.Cwd)
+
+			taskFile, legacy, err = ReadTasksFile(context.Background(), "testdata/tasks_with_comments.json")
+			require.NoError(t, err)
+			require.False(t, legacy)
+			require.Len(t, taskFile.Tasks, 3)
+
+			process := (&LegacyTaskFile{Command: "make", Tasks: []LegacyTask{{TaskName: "all"}}}).Modernize()
+			require.Equal(t, "process", process.Tasks[0].Type)
+			require.Equal(t, []string{"all"}, process.Tasks[0].Args.Values())
+		})
+	})
+
+	t.Run("ParseTasks with tags in detail", func(t *testing.T) {
+		parser := NewTasksParser("/test/project", nil)
+
+		tasks, err := parser.ParseTasks(filepath.Join("testdata", "tasks_tags.json"))
+		require.NoError(t, err)
+		require.Len(t, tasks, 3)
+
+		require.Equal(t, []string{"ci", "quick"}, tasks[0].Tags)
+		require.Equal(t, "Static checks [tags: ci,quick]", tasks[0].Description, "detail should be kept verbatim")
+		require.Equal(t, []string{"destructive"}, tasks[1].Tags)
+		require.Empty(t, tasks[2].Tags)
+	})
+
+	t.Run("ParseTasks with top-level options", func(t *testing.T) {
+		parser := NewTasksParser("/test/project", nil)
+
+		tasks, err := parser.ParseTasks(filepath.Join("testdata", "tasks_global_options.json"))
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+
+		// A task without options of its own inherits the global cwd and env
+		require.Equal(t, filepath.Join("/test/project", "app"), tasks[0].Cwd)
+		require.Equal(t, map[string]string{"GOFLAGS": "-mod=vendor", "CGO_ENABLED": "0", "HTTP_PROXY": "http://proxy:3128"}, tasks[0].Env)
+		require.Empty(t, tasks[0].UnsetEnv)
+
+		// Task options win, a null unsets an inherited variable, and the rest is still inherited
+		require.Equal(t, filepath.Join("/test/project", "native"), tasks[1].Cwd)
+		require.Equal(t, map[string]string{"GOFLAGS": "-mod=vendor", "CGO_ENABLED": "1"}, tasks[1].Env)
+		require.Equal(t, []string{"HTTP_PROXY"}, tasks[1].UnsetEnv)
+	})
+
+	t.Run("ParseTasks with platform overrides", func(t *testing.T) {
+		parse := func(t *testing.T, goos string) []*config.Task {
+			t.Helper()
+
+			parser := NewTasksParser("/test/project", nil)
+			parser.SetTargetOS(goos)
+
+			tasks, err := parser.ParseTasks(filepath.Join("testdata", "tasks_platforms.json"))
+			require.NoError(t, err)
+			require.Len(t, tasks, 2)
+
+			return tasks
+		}
+
+		t.Run("should apply the linux block on linux", func(t *testing.T) {
+			tasks := parse(t, "linux")
+
+			require.Equal(t, "rm", tasks[0].Command, "a task without a linux block is unchanged")
+			require.Equal(t, []string{"-rf", "dist"}, tasks[0].Args)
+			require.Empty(t, tasks[0].Platform)
+
+			require.Equal(t, "xdg-open", tasks[1].Command)
+			require.Equal(t, []string{"docs/index.html"}, tasks[1].Args)
+			require.Equal(t, []string{"BROWSER"}, tasks[1].UnsetEnv)
+			require.Equal(t, "linux", tasks[1].Platform)
+		})
+
+		t.Run("should apply the osx block on darwin", func(t *testing.T) {
+			tasks := parse(t, "darwin")
+
+			require.Equal(t, "rm", tasks[0].Command, "args alone can be overridden")
+			require.Equal(t, []string{"-rf", "dist", ".DS_Store"}, tasks[0].Args)
+			require.Equal(t, "osx", tasks[0].Platform)
+
+			require.Equal(t, "open", tasks[1].Command, "the array form of an overriding command fixes its first arguments")
+			require.Equal(t, []string{"-a", "Safari", "docs/index.html"}, tasks[1].Args)
+			require.Empty(t, tasks[1].UnsetEnv)
+		})
+
+		t.Run("should apply the windows block on windows", func(t *testing.T) {
+			tasks := parse(t, "windows")
+
+			require.Equal(t, "cmd", tasks[0].Command)
+			require.Equal(t, []string{"/c", "rmdir", "/s", "/q", "dist"}, tasks[0].Args)
+			require.Equal(t, filepath.Join("/test/project", "web"), tasks[0].Cwd, "options the block leaves out are kept")
+			require.Equal(t, map[string]string{"LOG_LEVEL": "debug", "NODE_ENV": "production"}, tasks[0].Env, "block env is merged over the task's and the file's")
+			require.Equal(t, "windows", tasks[0].Platform)
+
+			require.Equal(t, "explorer", tasks[1].Command)
+			require.Equal(t, []string{"docs/index.html"}, tasks[1].Args, "args are kept when the block only replaces the command")
+			require.Equal(t, filepath.Join("/test/project", "docs"), tasks[1].Cwd)
+		})
+	})
+
+	t.Run("ParseTasks with problem matchers", func(t *testing.T) {
+		parser := NewTasksParser("/test/project", nil)
+
+		tasks, err := parser.ParseTasks(filepath.Join("testdata", "tasks_problem_matchers.json"))
+		require.NoError(t, err)
+		require.Len(t, tasks, 3)
+
+		require.Equal(t, []string{`^(.*):(\d+):(\d+):\s+(.*)$`}, tasks[0].ProblemPatterns)
+		require.Equal(t, []string{`^FAIL:\s+(.*)$`}, tasks[1].ProblemPatterns, "only the first line of a multi-line pattern should be kept")
+		require.Empty(t, tasks[2].ProblemPatterns, "named matchers have no pattern")
+	})
+
+	t.Run("ParseTasks with trailing commas", func(t *testing.T) {
+		testDataPath := filepath.Join("testdata", "tasks_trailing_commas.json")
+		recorder, logger := logging.NewRecorder()
+		parser := NewTasksParser("/test/project", logger)
+
+		tasks, err := parser.ParseTasks(testDataPath)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, []string{"build", "./..."}, tasks[0].Args)
+
+		var notices []logging.Entry
+
+		for _, entry := range recorder.Entries() {
+			if entry.Level == slog.LevelDebug && entry.Message == "accepted JSONC that strict JSON parsers reject" {
+				notices = append(notices, entry)
+			}
+		}
+
+		require.Len(t, notices, 1)
+		require.Equal(t, testDataPath, notices[0].Attrs[logging.KeyFile])
+		require.Equal(t, "trailing commas", notices[0].Attrs["constructs"])
+	})
+
+	t.Run("ParseTasks with duplicate labels", func(t *testing.T) {
+		projectRoot := "/test/project"
+		testDataPath := filepath.Join("testdata", "tasks_duplicate_labels.json")
+
+		t.Run("should warn about the shadowed task", func(t *testing.T) {
+			recorder, logger := logging.NewRecorder()
+			parser := NewTasksParser(projectRoot, logger)
+
+			tasks, err := parser.ParseTasks(testDataPath)
+			require.NoError(t, err)
+			require.Len(t, tasks, 3)
+
+			var warnings []logging.Entry
+
+			for _, entry := range recorder.Entries() {
+				if entry.Level == slog.LevelWarn {
+					warnings = append(warnings, entry)
+				}
+			}
+
+			require.Len(t, warnings, 1)
+			require.Contains(t, warnings[0].Message, "duplicate task label")
+			require.Equal(t, "build", warnings[0].Attrs[logging.KeyTask])
+			require.Equal(t, testDataPath, warnings[0].Attrs[logging.KeyFile])
+			require.Equal(t, "1", warnings[0].Attrs["first"])
+			require.Equal(t, "3", warnings[0].Attrs["duplicate"])
+		})
+
+		t.Run("should fail in strict mode", func(t *testing.T) {
+			parser := NewTasksParser(projectRoot, nil)
+			parser.SetStrict(true)
+
+			_, err := parser.ParseTasks(testDataPath)
+			require.ErrorIs(t, err, ErrDuplicateTaskLabel)
+			require.Contains(t, err.Error(), `"build"`)
+			require.Contains(t, err.Error(), testDataPath)
+
+			_, err = parser.ParseTasks(filepath.Join("testdata", "tasks_with_comments.json"))
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("ParseTasks with quoted args", func(t *testing.T) {
+		parser := NewTasksParser("/test/project", nil)
+
+		tasks, err := parser.ParseTasks(filepath.Join("testdata", "tasks_quoted_args.json"))
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+
+		require.Equal(t, []string{"-rn", "TODO: fix", "$SRC_DIR"}, tasks[0].Args)
+		require.Equal(t, []string{"", config.QuotingStrong, config.QuotingWeak}, tasks[0].ArgQuoting)
+
+		require.Equal(t, []string{"plain", "args"}, tasks[1].Args)
+		require.Nil(t, tasks[1].ArgQuoting)
+
+		t.Run("should write args without quoting back as plain strings", func(t *testing.T) {
+			var args VSCodeTaskArgs
+			require.NoError(t, json.Unmarshal([]byte(`["-rn", {"value": "a b", "quoting": "escape"}]`), &args))
+
+			data, err := json.Marshal(args)
+			require.NoError(t, err)
+			require.JSONEq(t, `["-rn", {"value": "a b", "quoting": "escape"}]`, string(data))
+		})
+
+		t.Run("should reject unknown quoting styles", func(t *testing.T) {
+			var args VSCodeTaskArgs
+			require.ErrorContains(t, json.Unmarshal([]byte(`[{"value": "x", "quoting": "double"}]`), &args), "unknown quoting 'double'")
+		})
+	})
+
+	t.Run("ParseTasks with command arrays", func(t *testing.T) {
+		parser := NewTasksParser("/test/project", nil)
+
+		tasks, err := parser.ParseTasks(filepath.Join("testdata", "tasks_command_array.json"))
+		require.NoError(t, err)
+		require.Len(t, tasks, 3)
+
+		t.Run("should split the array into the command and leading args", func(t *testing.T) {
+			require.Equal(t, "docker", tasks[0].Command)
+			require.Equal(t, []string{"compose", "--file", "docker-compose.dev.yml", "up", "--build"}, tasks[0].Args)
+			require.Nil(t, tasks[0].ArgQuoting)
+		})
+
+		t.Run("should keep the quoting of array elements", func(t *testing.T) {
+			require.Equal(t, "grep", tasks[1].Command)
+			require.Equal(t, []string{"TODO: fix"}, tasks[1].Args)
+			require.Equal(t, []string{config.QuotingStrong}, tasks[1].ArgQuoting)
+		})
+
+		t.Run("should still accept a string command", func(t *testing.T) {
+			require.Equal(t, "go", tasks[2].Command)
+			require.Equal(t, []string{"build", "./..."}, tasks[2].Args)
+		})
+
+		t.Run("should write commands back in the form they were read", func(t *testing.T) {
+			for _, command := range []string{`"go"`, `{"value": "my tool", "quoting": "strong"}`, `["docker", "compose", "up"]`} {
+				var parsed VSCodeTaskCommand
+				require.NoError(t, json.Unmarshal([]byte(command), &parsed))
+
+				data, err := json.Marshal(parsed)
+				require.NoError(t, err)
+				require.JSONEq(t, command, string(data))
+			}
+		})
+
+		t.Run("should reject commands of other types", func(t *testing.T) {
+			var parsed VSCodeTaskCommand
+			require.ErrorContains(t, json.Unmarshal([]byte(`42`), &parsed), "task command must be a string")
+			require.ErrorContains(t, json.Unmarshal([]byte(`["docker", 42]`), &parsed), "task command array must hold strings")
+		})
 	})
 
 	t.Run("convertTask", func(t *testing.T) {
 		projectRoot := "/test/project"
-		parser := NewTasksParser(projectRoot)
+		parser := NewTasksParser(projectRoot, nil)
+		testValue := "test_value"
 
 		vscodeTask := VSCodeTask{
 			Label:   "test-task",
 			Type:    "shell",
-			Command: "echo",
-			Args:    []string{"hello", "world"},
+			Command: PlainTaskCommand("echo"),
+			Args:    PlainTaskArgs([]string{"hello", "world"}),
 			Detail:  "A test task",
 			Group:   "test",
 			Options: &VSCodeTaskOptions{
 				Cwd: "${workspaceFolder}/subdir",
-				Env: map[string]string{
-					"TEST_VAR": "test_value",
+				Env: map[string]*string{
+					"TEST_VAR": &testValue,
 				},
 			},
 		}
@@ -169,6 +494,25 @@ func TestTasksParser(t *testing.T) {
 			require.Equal(t, "/test/tasks.json", task.Source)
 		})
 
+		t.Run("execution kind", func(t *testing.T) {
+			require.Equal(t, config.ExecutionShell, task.Execution)
+
+			processTask, err := parser.convertTask(VSCodeTask{Label: "build", Type: "process", Command: PlainTaskCommand("make")}, "/test/tasks.json")
+			require.NoError(t, err)
+			require.Equal(t, config.ExecutionProcess, processTask.Execution)
+
+			npmTask, err := parser.convertTask(VSCodeTask{Label: "lint", Type: "npm", Command: PlainTaskCommand("lint")}, "/test/tasks.json")
+			require.NoError(t, err)
+			require.Empty(t, npmTask.Execution)
+		})
+
+		t.Run("shell command line kept unsplit", func(t *testing.T) {
+			shellTask, err := parser.convertTask(VSCodeTask{Label: "pipeline", Type: "shell", Command: PlainTaskCommand(`bash -c "a && b | c"`)}, "/test/tasks.json")
+			require.NoError(t, err)
+			require.Equal(t, `bash -c "a && b | c"`, shellTask.Command)
+			require.Empty(t, shellTask.Args)
+		})
+
 		t.Run("workspace path resolution", func(t *testing.T) {
 			expectedCwd := filepath.Join(projectRoot, "subdir")
 			require.Equal(t, expectedCwd, task.Cwd)
@@ -178,10 +522,34 @@ func TestTasksParser(t *testing.T) {
 			require.NotNil(t, task.Env)
 			require.Equal(t, "test_value", task.Env["TEST_VAR"])
 		})
+
+		t.Run("null environment values unset the variable", func(t *testing.T) {
+			var options VSCodeTaskOptions
+			require.NoError(t, json.Unmarshal([]byte(`{"env": {"PROXY": null, "EMPTY": "", "CI": null}}`), &options))
+
+			unsetTask, err := parser.convertTask(VSCodeTask{Label: "offline", Type: "shell", Command: PlainTaskCommand("make"), Options: &options}, "/test/tasks.json")
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"EMPTY": ""}, unsetTask.Env)
+			require.Equal(t, []string{"CI", "PROXY"}, unsetTask.UnsetEnv)
+		})
+
+		t.Run("confirmation opt-in", func(t *testing.T) {
+			require.False(t, task.RequiresConfirmation())
+
+			confirmTask, err := parser.convertTask(VSCodeTask{Label: "db-reset", Type: "shell", Command: PlainTaskCommand("make"), Confirm: true}, "/test/tasks.json")
+			require.NoError(t, err)
+			require.True(t, confirmTask.Confirm)
+			require.True(t, confirmTask.RequiresConfirmation())
+
+			deployTask, err := parser.convertTask(VSCodeTask{Label: "ship", Type: "shell", Command: PlainTaskCommand("make"), Group: "deploy"}, "/test/tasks.json")
+			require.NoError(t, err)
+			require.False(t, deployTask.Confirm)
+			require.True(t, deployTask.RequiresConfirmation())
+		})
 	})
 
 	t.Run("parseGroup", func(t *testing.T) {
-		parser := NewTasksParser("/test")
+		parser := NewTasksParser("/test", nil)
 
 		tests := []struct {
 			name     string
@@ -223,9 +591,56 @@ func TestTasksParser(t *testing.T) {
 		}
 	})
 
+	t.Run("parseDependsOn", func(t *testing.T) {
+		parser := NewTasksParser("/test", nil)
+
+		tests := []struct {
+			name      string
+			dependsOn interface{}
+			expected  []string
+		}{
+			{
+				name:      "nil dependsOn",
+				dependsOn: nil,
+				expected:  nil,
+			},
+			{
+				name:      "single label",
+				dependsOn: "build",
+				expected:  []string{"build"},
+			},
+			{
+				name:      "task reference object",
+				dependsOn: map[string]interface{}{"type": "shell", "task": "build"},
+				expected:  []string{"build"},
+			},
+			{
+				name:      "mixed array",
+				dependsOn: []interface{}{"lint", map[string]interface{}{"task": "test"}, 42},
+				expected:  []string{"lint", "test"},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require.Equal(t, tt.expected, parser.parseDependsOn(tt.dependsOn))
+			})
+		}
+
+		t.Run("dependsOrder defaults to parallel", func(t *testing.T) {
+			task, err := parser.convertTask(VSCodeTask{Label: "all", DependsOn: "build"}, "tasks.json")
+			require.NoError(t, err)
+			require.Equal(t, config.DependsOrderParallel, task.DependsOrder)
+
+			task, err = parser.convertTask(VSCodeTask{Label: "all", DependsOn: "build", DependsOrder: "sequence"}, "tasks.json")
+			require.NoError(t, err)
+			require.Equal(t, config.DependsOrderSequence, task.DependsOrder)
+		})
+	})
+
 	t.Run("resolveWorkspacePath", func(t *testing.T) {
 		projectRoot := "/home/user/project"
-		parser := NewTasksParser(projectRoot)
+		parser := NewTasksParser(projectRoot, nil)
 
 		tests := []struct {
 			name     string
@@ -0,0 +1,21 @@
+package vscode
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// resolveWorkspaceVariables replaces the VSCode variables that only depend on the workspace.
+// A project is a single-folder workspace, so the folder of any file in it is the project root.
+// Variables about the active editor (${file}, ${lineNumber}, ...) have no value outside the IDE
+// and are left as they are.
+func resolveWorkspaceVariables(value string, projectRoot string) string {
+	replacer := strings.NewReplacer(
+		"${workspaceFolder}", projectRoot,
+		"${workspaceRoot}", projectRoot,
+		"${fileWorkspaceFolder}", projectRoot,
+		"${workspaceFolderBasename}", filepath.Base(projectRoot),
+	)
+
+	return replacer.Replace(value)
+}
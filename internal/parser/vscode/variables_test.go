@@ -0,0 +1,26 @@
+package vscode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveWorkspaceVariables(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"${workspaceFolder}/src", "/home/dev/api/src"},
+		{"${workspaceRoot}/src", "/home/dev/api/src"},
+		{"${fileWorkspaceFolder}/src", "/home/dev/api/src"},
+		{"build/${workspaceFolderBasename}", "build/api"},
+		{"${fileDirname}/out", "${fileDirname}/out"}, // Editor variables have no value outside the IDE
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			require.Equal(t, tc.expected, resolveWorkspaceVariables(tc.input, "/home/dev/api"))
+		})
+	}
+}
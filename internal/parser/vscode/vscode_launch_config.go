@@ -1,5 +1,15 @@
 package vscode
 
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// platformKeys are the launch.json blocks that override a configuration on one OS
+var platformKeys = []string{"windows", "osx", "linux"}
+
 // VSCodeLaunchConfig represents a single launch configuration in VSCode launch.json
 type VSCodeLaunchConfig struct {
 	Name          string            `json:"name"`
@@ -7,6 +17,8 @@ type VSCodeLaunchConfig struct {
 	Request       string            `json:"request"`
 	Mode          string            `json:"mode,omitempty"`
 	Program       string            `json:"program,omitempty"`
+	MainClass     string            `json:"mainClass,omitempty"`
+	VMArgs        interface{}       `json:"vmArgs,omitempty"` // Can be string or array of strings
 	Args          []string          `json:"args,omitempty"`
 	Env           map[string]string `json:"env,omitempty"`
 	Cwd           string            `json:"cwd,omitempty"`
@@ -14,5 +26,127 @@ type VSCodeLaunchConfig struct {
 	StopOnEntry   bool              `json:"stopOnEntry,omitempty"`
 	JustMyCode    bool              `json:"justMyCode,omitempty"`
 	PreLaunchTask string            `json:"preLaunchTask,omitempty"`
+	PostDebugTask string            `json:"postDebugTask,omitempty"`
 	ProcessId     interface{}       `json:"processId,omitempty"`
+	Confirm       bool              `json:"confirm,omitempty"` // taskporter extension: ask before running
+	Raw           json.RawMessage   `json:"-"`                 // The configuration object as written, including fields not modeled above
+}
+
+// UnmarshalJSON decodes the modeled fields and keeps the original object in Raw
+func (c *VSCodeLaunchConfig) UnmarshalJSON(data []byte) error {
+	type plain VSCodeLaunchConfig
+
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*c = VSCodeLaunchConfig(decoded)
+	c.Raw = append(json.RawMessage(nil), data...)
+
+	return nil
+}
+
+// readLaunchKeys lists the keys of a launch configuration the parser reads
+var readLaunchKeys = []string{
+	"name", "type", "request", "program", "mainClass", "vmArgs", "args", "env", "cwd",
+	"console", "preLaunchTask", "postDebugTask", "confirm", "windows", "osx", "linux",
+}
+
+// UnreadKeys returns the keys of the configuration as written, including those of its platform
+// blocks, that the parser does not read, sorted
+func (c VSCodeLaunchConfig) UnreadKeys() []string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(c.Raw, &fields); err != nil {
+		return nil
+	}
+
+	keys := slices.Collect(maps.Keys(fields))
+
+	for _, platform := range platformKeys {
+		var block map[string]json.RawMessage
+		if json.Unmarshal(fields[platform], &block) == nil {
+			keys = append(keys, slices.Collect(maps.Keys(block))...)
+		}
+	}
+
+	keys = slices.DeleteFunc(keys, func(key string) bool { return slices.Contains(readLaunchKeys, key) })
+	slices.Sort(keys)
+
+	return slices.Compact(keys)
+}
+
+// HasPlatformBlock reports whether the configuration has a block for goos ("windows", "osx" or
+// "linux")
+func (c VSCodeLaunchConfig) HasPlatformBlock(goos string) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(c.Raw, &fields); err != nil {
+		return false
+	}
+
+	_, ok := fields[platformKey(goos)]
+
+	return ok
+}
+
+// ForPlatform returns the configuration with the block for goos ("windows", "osx" or "linux")
+// merged over it, the way VSCode applies them: fields in the block replace the base fields,
+// except objects such as env, which are merged key by key. Raw keeps the configuration as written.
+func (c VSCodeLaunchConfig) ForPlatform(goos string) (VSCodeLaunchConfig, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(c.Raw, &fields); err != nil {
+		return c, nil
+	}
+
+	block, ok := fields[platformKey(goos)]
+	if !ok {
+		return c, nil
+	}
+
+	var overrides map[string]json.RawMessage
+	if err := json.Unmarshal(block, &overrides); err != nil {
+		return c, fmt.Errorf("invalid \"%s\" block: %w", platformKey(goos), err)
+	}
+
+	for key, value := range overrides {
+		fields[key] = mergeJSONObjects(fields[key], value)
+	}
+
+	for _, key := range platformKeys {
+		delete(fields, key)
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return c, err
+	}
+
+	var merged VSCodeLaunchConfig
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return c, fmt.Errorf("invalid \"%s\" block: %w", platformKey(goos), err)
+	}
+
+	merged.Raw = c.Raw
+
+	return merged, nil
+}
+
+// mergeJSONObjects returns override merged key by key over base when both are objects, and
+// override otherwise
+func mergeJSONObjects(base, override json.RawMessage) json.RawMessage {
+	var baseObject, overrideObject map[string]json.RawMessage
+	if json.Unmarshal(base, &baseObject) != nil || json.Unmarshal(override, &overrideObject) != nil || baseObject == nil || overrideObject == nil {
+		return override
+	}
+
+	for key, value := range overrideObject {
+		baseObject[key] = mergeJSONObjects(baseObject[key], value)
+	}
+
+	merged, err := json.Marshal(baseObject)
+	if err != nil {
+		return override
+	}
+
+	return merged
 }
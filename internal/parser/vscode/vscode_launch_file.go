@@ -2,6 +2,15 @@ package vscode
 
 // VSCodeLaunchFile represents the structure of VSCode launch.json
 type VSCodeLaunchFile struct {
-	Version        string               `json:"version"`
-	Configurations []VSCodeLaunchConfig `json:"configurations"`
+	Version        string                 `json:"version"`
+	Configurations []VSCodeLaunchConfig   `json:"configurations"`
+	Compounds      []VSCodeLaunchCompound `json:"compounds,omitempty"`
+}
+
+// VSCodeLaunchCompound represents a compound launch that starts several configurations together
+type VSCodeLaunchCompound struct {
+	Name           string        `json:"name"`
+	Configurations []interface{} `json:"configurations"` // Configuration names, or {"name", "folder"} objects
+	PreLaunchTask  string        `json:"preLaunchTask,omitempty"`
+	StopAll        bool          `json:"stopAll,omitempty"`
 }
@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+)
+
+// ChangedTaskFilter keeps the tasks affected by a set of changed files, so a monorepo can run
+// tasks only for the modules that were touched. A task is affected when a changed file lies
+// under its working directory or is the file the task is defined in.
+type ChangedTaskFilter struct {
+	projectRoot string
+	changed     []string // Absolute paths of changed files
+	logger      *slog.Logger
+}
+
+// NewChangedTaskFilter creates a filter for the given changed files. Relative paths are
+// resolved against projectRoot, as are relative task working directories.
+func NewChangedTaskFilter(projectRoot string, changed []string, logger *slog.Logger) *ChangedTaskFilter {
+	filter := &ChangedTaskFilter{
+		projectRoot: resolvePath("", projectRoot),
+		logger:      logging.OrDiscard(logger).With(logging.KeyComponent, "changed-filter"),
+	}
+
+	for _, path := range changed {
+		filter.changed = append(filter.changed, resolvePath(filter.projectRoot, path))
+	}
+
+	return filter
+}
+
+// NewChangedTaskFilterFromGit creates a filter for the files that differ between base and the
+// working tree: everything committed since the branch left base, plus staged, unstaged and
+// untracked changes.
+func NewChangedTaskFilterFromGit(projectRoot string, base string, logger *slog.Logger) (*ChangedTaskFilter, error) {
+	// A leading dash would be read by git as an option rather than a ref
+	if base == "" || strings.HasPrefix(base, "-") {
+		return nil, fmt.Errorf("invalid base ref: %q", base)
+	}
+
+	topLevel, err := runGit(projectRoot, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	mergeBase, err := runGit(projectRoot, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare against '%s': %w", base, err)
+	}
+
+	diff, err := runGit(projectRoot, "diff", "--name-only", "--no-renames", mergeBase)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := runGit(projectRoot, "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		if line != "" {
+			changed = append(changed, filepath.Join(topLevel, filepath.FromSlash(line)))
+		}
+	}
+
+	return NewChangedTaskFilter(projectRoot, changed, logger), nil
+}
+
+// Changed returns the absolute paths of the changed files
+func (f *ChangedTaskFilter) Changed() []string {
+	return f.changed
+}
+
+// Affected reports whether a changed file lies under the task's working directory or is its source.
+// Tasks without a working directory run in the project root, so any change affects them.
+func (f *ChangedTaskFilter) Affected(task *config.Task) bool {
+	cwd := f.projectRoot
+	if task.Cwd != "" {
+		cwd = resolvePath(f.projectRoot, task.Cwd)
+	}
+
+	var source string
+	if task.Source != "" {
+		source = resolvePath(f.projectRoot, task.Source)
+	}
+
+	for _, path := range f.changed {
+		if path == source || isWithin(cwd, path) {
+			f.logger.Debug("task affected by change", logging.KeyTask, task.Name, logging.KeyFile, path)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the tasks affected by the changed files, in their original order
+func (f *ChangedTaskFilter) Filter(tasks []*config.Task) []*config.Task {
+	var affected []*config.Task
+
+	for _, task := range tasks {
+		if f.Affected(task) {
+			affected = append(affected, task)
+		}
+	}
+
+	return affected
+}
+
+// runGit runs a git command in dir and returns its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// resolvePath makes path absolute against base and resolves symlinks where possible, so paths
+// reported by git compare equal to paths from task configurations
+func resolvePath(base, path string) string {
+	if !filepath.IsAbs(path) && base != "" {
+		path = filepath.Join(base, path)
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	// Deleted files no longer exist; resolve their directory instead
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		return filepath.Join(dir, filepath.Base(path))
+	}
+
+	return path
+}
+
+// isWithin reports whether path is dir or lies below it
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+
+	return err == nil && filepath.IsLocal(rel)
+}
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedTaskFilter(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, ".vscode", "tasks.json")
+
+	api := &config.Task{Name: "test api", Cwd: filepath.Join(root, "services", "api"), Source: source}
+	web := &config.Task{Name: "test web", Cwd: "services/web", Source: source}
+	all := &config.Task{Name: "test all", Source: source}
+	tasks := []*config.Task{api, web, all}
+
+	t.Run("keeps tasks whose working directory contains a change", func(t *testing.T) {
+		filter := NewChangedTaskFilter(root, []string{"services/api/handler.go"}, nil)
+
+		require.Equal(t, []*config.Task{api, all}, filter.Filter(tasks))
+	})
+
+	t.Run("resolves relative working directories against the project root", func(t *testing.T) {
+		filter := NewChangedTaskFilter(root, []string{filepath.Join(root, "services", "web", "index.ts")}, nil)
+
+		require.True(t, filter.Affected(web))
+		require.False(t, filter.Affected(api))
+	})
+
+	t.Run("does not treat sibling prefixes as parents", func(t *testing.T) {
+		filter := NewChangedTaskFilter(root, []string{"services/api-gateway/main.go"}, nil)
+
+		require.False(t, filter.Affected(api))
+	})
+
+	t.Run("keeps every task of a changed source file", func(t *testing.T) {
+		filter := NewChangedTaskFilter(root, []string{".vscode/tasks.json"}, nil)
+
+		require.Equal(t, tasks, filter.Filter(tasks))
+	})
+
+	t.Run("keeps nothing without changes", func(t *testing.T) {
+		require.Empty(t, NewChangedTaskFilter(root, nil, nil).Filter(tasks))
+	})
+}
+
+func TestNewChangedTaskFilterFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+
+	gitCmd := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	writeFile := func(path, content string) {
+		t.Helper()
+
+		path = filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	commit := func(message string) {
+		t.Helper()
+
+		gitCmd("add", "-A")
+		gitCmd("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", message)
+	}
+
+	gitCmd("init", "-q", "-b", "main")
+	writeFile("services/api/main.go", "package main")
+	writeFile("services/web/index.ts", "export {}")
+	writeFile("docs/README.md", "# Docs")
+	commit("initial")
+
+	gitCmd("checkout", "-q", "-b", "feature")
+	writeFile("docs/README.md", "# Changed docs")
+	commit("docs")
+
+	writeFile("services/api/main.go", "package main // changed")
+	writeFile("services/api/routes.go", "package main")
+
+	t.Run("collects committed, unstaged and untracked changes since the base", func(t *testing.T) {
+		filter, err := NewChangedTaskFilterFromGit(root, "main", nil)
+		require.NoError(t, err)
+
+		require.ElementsMatch(t, []string{
+			resolvePath("", filepath.Join(root, "docs", "README.md")),
+			resolvePath("", filepath.Join(root, "services", "api", "main.go")),
+			resolvePath("", filepath.Join(root, "services", "api", "routes.go")),
+		}, filter.Changed())
+
+		require.True(t, filter.Affected(&config.Task{Name: "api", Cwd: filepath.Join(root, "services", "api")}))
+		require.False(t, filter.Affected(&config.Task{Name: "web", Cwd: filepath.Join(root, "services", "web")}))
+	})
+
+	t.Run("fails for an unknown base", func(t *testing.T) {
+		_, err := NewChangedTaskFilterFromGit(root, "does-not-exist", nil)
+		require.ErrorContains(t, err, "does-not-exist")
+	})
+
+	t.Run("rejects refs that look like options", func(t *testing.T) {
+		_, err := NewChangedTaskFilterFromGit(root, "--output=/tmp/x", nil)
+		require.ErrorContains(t, err, "invalid base ref")
+	})
+}
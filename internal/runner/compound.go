@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+)
+
+// RunCompound starts the members of a compound task together, like IDEs start compound launches.
+// Members start in order, each after waiting its delay, so a backend can come up before a frontend
+// attaches to it. It returns once every member has finished, reporting every member that failed.
+// run executes a single member; nil runs it with RunTask.
+func (tr *TaskRunner) RunCompound(compound *config.Task, members []*config.Task, delays []time.Duration, run func(*config.Task) error) error {
+	if run == nil {
+		run = tr.RunTask
+	}
+
+	errs := make([]error, len(members))
+
+	var wg sync.WaitGroup
+
+	for i, member := range members {
+		if i < len(delays) && delays[i] > 0 {
+			if tr.verbose {
+				fmt.Printf("⏳ Waiting %s before starting %s\n", delays[i], member.Name)
+			}
+
+			tr.logger.Debug("delaying compound member", logging.KeyTask, compound.Name, "member", member.Name, "delay", delays[i])
+			time.Sleep(delays[i])
+		}
+
+		if tr.verbose {
+			fmt.Printf("🚀 Starting compound member: %s\n", member.Name)
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs[i] = run(member)
+		}()
+	}
+
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("compound '%s' failed: %w", compound.Name, err)
+	}
+
+	return nil
+}
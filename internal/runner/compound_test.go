@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRunner_RunCompound(t *testing.T) {
+	compound := &config.Task{Name: "Full Stack", DependsOn: []string{"Backend", "Frontend"}}
+	members := []*config.Task{{Name: "Backend"}, {Name: "Frontend"}}
+
+	t.Run("starts members in order after their delays", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			started = map[string]time.Time{}
+		)
+
+		run := func(task *config.Task) error {
+			mu.Lock()
+			started[task.Name] = time.Now()
+			mu.Unlock()
+
+			return nil
+		}
+
+		begin := time.Now()
+		err := NewTaskRunner(false, nil).RunCompound(compound, members, []time.Duration{0, 50 * time.Millisecond}, run)
+		require.NoError(t, err)
+
+		require.Less(t, started["Backend"].Sub(begin), 50*time.Millisecond)
+		require.GreaterOrEqual(t, started["Frontend"].Sub(begin), 50*time.Millisecond)
+	})
+
+	t.Run("runs members concurrently", func(t *testing.T) {
+		frontendStarted := make(chan struct{})
+
+		run := func(task *config.Task) error {
+			if task.Name == "Frontend" {
+				close(frontendStarted)
+				return nil
+			}
+
+			// A long-running backend must not hold back the frontend
+			select {
+			case <-frontendStarted:
+				return nil
+			case <-time.After(time.Second):
+				return errors.New("frontend never started")
+			}
+		}
+
+		require.NoError(t, NewTaskRunner(false, nil).RunCompound(compound, members, nil, run))
+	})
+
+	t.Run("waits for every member and reports all failures", func(t *testing.T) {
+		var (
+			mu  sync.Mutex
+			ran []string
+		)
+
+		run := func(task *config.Task) error {
+			mu.Lock()
+			ran = append(ran, task.Name)
+			mu.Unlock()
+
+			return errors.New(task.Name + " crashed")
+		}
+
+		err := NewTaskRunner(false, nil).RunCompound(compound, members, nil, run)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "compound 'Full Stack' failed")
+		require.Contains(t, err.Error(), "Backend crashed")
+		require.Contains(t, err.Error(), "Frontend crashed")
+		require.ElementsMatch(t, []string{"Backend", "Frontend"}, ran)
+	})
+}
@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// Container engines supported by --container-engine
+const (
+	ContainerEngineDocker = "docker"
+	ContainerEnginePodman = "podman"
+)
+
+// containerWorkspace is where the project root is mounted inside the container
+const containerWorkspace = "/workspace"
+
+// SetContainer runs tasks inside image instead of on the host, with the project root mounted
+// at /workspace. An empty engine picks docker or podman, whichever is installed.
+func (tr *TaskRunner) SetContainer(image, engine string) {
+	tr.container = image
+	tr.engine = engine
+}
+
+// DetectContainerEngine returns the first installed container engine, preferring docker
+func DetectContainerEngine() (string, error) {
+	for _, engine := range []string{ContainerEngineDocker, ContainerEnginePodman} {
+		if _, err := exec.LookPath(engine); err == nil {
+			return engine, nil
+		}
+	}
+
+	return "", fmt.Errorf("no container engine found (install %s or %s)", ContainerEngineDocker, ContainerEnginePodman)
+}
+
+// buildContainerCommand wraps the task in a `<engine> run --rm` invocation that mounts the project
+// root, starts in the task's working directory and passes the task's environment with -e
+func (tr *TaskRunner) buildContainerCommand(task *config.Task, args []string) (*exec.Cmd, error) {
+	// A leading dash would be read by the engine as an option rather than an image
+	if strings.HasPrefix(tr.container, "-") {
+		return nil, fmt.Errorf("invalid container image: %s", tr.container)
+	}
+
+	engine := tr.engine
+	if engine == "" {
+		detected, err := DetectContainerEngine()
+		if err != nil {
+			return nil, err
+		}
+
+		engine = detected
+	}
+
+	cwd, env, err := tr.taskCwdAndEnv(task)
+	if err != nil {
+		return nil, err
+	}
+
+	projectRoot, err := filepath.Abs(tr.projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project root: %w", err)
+	}
+
+	workdir, err := containerWorkdir(projectRoot, cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	runArgs := []string{"run", "--rm", "-i"}
+
+	// Interactive tasks need a terminal inside the container
+	if task.Interactive {
+		runArgs = append(runArgs, "-t")
+	}
+
+	runArgs = append(runArgs, "-v", projectRoot+":"+containerWorkspace, "-w", workdir)
+
+	// Pass names only so values are read from the engine's environment and stay out of process listings
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	cmdEnv := os.Environ()
+
+	for _, key := range keys {
+		runArgs = append(runArgs, "-e", key)
+		cmdEnv = append(cmdEnv, fmt.Sprintf("%s=%s", key, env[key]))
+	}
+
+	runArgs = append(runArgs, tr.container)
+
+	// Shell tasks run in the image's POSIX shell, since the host's shell from settings.json is
+	// rarely installed there, with their command line quoted for it
+	if task.Shell != "" || task.Execution == config.ExecutionShell {
+		posix := *task
+		posix.Shell = ""
+
+		runArgs = append(runArgs, "sh", "-c", shellCommandLine(&posix, args))
+	} else {
+		runArgs = append(runArgs, task.Command)
+		runArgs = append(runArgs, args...)
+	}
+
+	cmd := exec.Command(engine, runArgs...)
+	cmd.Env = cmdEnv
+
+	return cmd, nil
+}
+
+// containerWorkdir maps a host working directory inside the project to its path in the container
+func containerWorkdir(projectRoot, cwd string) (string, error) {
+	if cwd == "" {
+		return containerWorkspace, nil
+	}
+
+	absCwd, err := filepath.Abs(cwd)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+
+	rel, err := filepath.Rel(projectRoot, absCwd)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("working directory %s is outside the project root %s and is not mounted in the container", cwd, projectRoot)
+	}
+
+	return path.Join(containerWorkspace, filepath.ToSlash(rel)), nil
+}
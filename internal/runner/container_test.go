@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerExecution(t *testing.T) {
+	// installFakeEngine puts a stand-in engine on PATH that prints its arguments and the
+	// value of APP_ENV, one per line
+	installFakeEngine := func(t *testing.T, name string) {
+		binDir := t.TempDir()
+		script := "#!/bin/sh\necho \"engine=" + name + "\"\nfor arg in \"$@\"; do echo \"$arg\"; done\necho \"APP_ENV=$APP_ENV\"\n"
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, name), []byte(script), 0o755))
+		t.Setenv("PATH", binDir)
+	}
+
+	projectRoot := t.TempDir()
+
+	runContainerTask := func(t *testing.T, engine string, task *config.Task) []string {
+		var stdout bytes.Buffer
+
+		runner := NewTaskRunnerWithProjectRoot(false, projectRoot, nil)
+		runner.SetOutput(&stdout, nil)
+		runner.SetContainer("golang:1.24", engine)
+
+		require.NoError(t, runner.RunTask(task))
+
+		return strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	}
+
+	t.Run("mounts the project and passes env by name", func(t *testing.T) {
+		installFakeEngine(t, ContainerEngineDocker)
+
+		lines := runContainerTask(t, "", &config.Task{
+			Name:    "test",
+			Command: "go",
+			Args:    []string{"test", "./..."},
+			Cwd:     filepath.Join(projectRoot, "services", "api"),
+			Env:     map[string]string{"APP_ENV": "ci"},
+		})
+
+		require.Equal(t, []string{
+			"engine=docker",
+			"run", "--rm", "-i",
+			"-v", projectRoot + ":/workspace",
+			"-w", "/workspace/services/api",
+			"-e", "APP_ENV",
+			"golang:1.24",
+			"go", "test", "./...",
+			"APP_ENV=ci",
+		}, lines)
+	})
+
+	t.Run("falls back to podman and wraps shell tasks", func(t *testing.T) {
+		installFakeEngine(t, ContainerEnginePodman)
+
+		lines := runContainerTask(t, "", &config.Task{
+			Name:    "lint",
+			Command: "make lint &&",
+			Args:    []string{"echo", "done ok"},
+			Shell:   "sh",
+		})
+
+		require.Equal(t, "engine=podman", lines[0])
+		require.Equal(t, []string{"-w", "/workspace", "golang:1.24", "sh", "-c", "make lint && echo 'done ok'"}, lines[6:12])
+	})
+
+	t.Run("buildContainerCommand runs shell tasks in the image's sh", func(t *testing.T) {
+		runner := NewTaskRunnerWithProjectRoot(false, projectRoot, nil)
+		runner.SetContainer("node:22", ContainerEngineDocker)
+
+		tests := []struct {
+			name  string
+			shell string
+		}{
+			{name: "host zsh", shell: "/opt/homebrew/bin/zsh"},
+			{name: "host PowerShell", shell: "pwsh.exe"},
+			{name: "no profile", shell: ""},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				task := &config.Task{
+					Name:       "build",
+					Command:    "npm run build &&",
+					Args:       []string{"echo", "it's done", "$HOME"},
+					ArgQuoting: []string{"", "", config.QuotingWeak},
+					Execution:  config.ExecutionShell,
+					Shell:      tt.shell,
+				}
+
+				cmd, err := runner.buildContainerCommand(task, task.Args)
+				require.NoError(t, err)
+				require.Equal(t, []string{"node:22", "sh", "-c", `npm run build && echo 'it'\''s done' "$HOME"`}, cmd.Args[len(cmd.Args)-4:])
+			})
+		}
+	})
+
+	t.Run("respects the engine override", func(t *testing.T) {
+		installFakeEngine(t, ContainerEngineDocker)
+
+		runner := NewTaskRunnerWithProjectRoot(false, projectRoot, nil)
+		runner.SetContainer("golang:1.24", ContainerEnginePodman)
+
+		err := runner.RunTask(&config.Task{Name: "test", Command: "go"})
+		require.Error(t, err)
+	})
+
+	t.Run("reports a missing engine", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+
+		_, err := DetectContainerEngine()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no container engine found")
+	})
+
+	t.Run("containerWorkdir", func(t *testing.T) {
+		workdir, err := containerWorkdir("/home/dev/project", "/home/dev/project/web")
+		require.NoError(t, err)
+		require.Equal(t, "/workspace/web", workdir)
+
+		workdir, err = containerWorkdir("/home/dev/project", "")
+		require.NoError(t, err)
+		require.Equal(t, "/workspace", workdir)
+
+		_, err = containerWorkdir("/home/dev/project", "/home/dev/other")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "outside the project root")
+	})
+}
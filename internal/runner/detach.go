@@ -0,0 +1,275 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+)
+
+// Where detached tasks keep their output and pidfiles, relative to the project root
+const (
+	DetachedLogsDir = ".taskporter/logs"
+	DetachedPidsDir = ".taskporter/pids"
+)
+
+// detachHealthWindow is how long a detached task has to stay up to count as started, so a
+// server that crashes on startup is reported instead of silently dying in the background
+var detachHealthWindow = time.Second
+
+// unsafeFileChars are replaced in task names used as log file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// DetachedTask is a task started with --detach, as recorded in its pidfile
+type DetachedTask struct {
+	Task    string    `json:"task"`
+	Source  string    `json:"source,omitempty"`
+	PID     int       `json:"pid"`
+	Log     string    `json:"log"`
+	Command []string  `json:"command"`
+	Started time.Time `json:"started"`
+
+	// Exited is set when the task finished successfully within the health window, so it is
+	// neither running nor tracked
+	Exited bool `json:"-"`
+}
+
+// StartDetached starts task in the background and returns once it has stayed up for the health
+// window. The task gets its own session and process group where supported, reads no input and
+// writes its output to a log file under .taskporter/logs; a pidfile under .taskporter/pids
+// tracks it for ListDetached and StopDetached. A task exiting non-zero within the window fails
+// with its exit code and the log path.
+func (tr *TaskRunner) StartDetached(task *config.Task) (*DetachedTask, error) {
+	if tr.paranoidMode {
+		if err := tr.validateTaskSecurity(task); err != nil {
+			return nil, fmt.Errorf("security validation failed for task '%s': %w", task.Name, err)
+		}
+	}
+
+	cmd, err := tr.prepareCommand(task)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+
+	logPath, err := filepath.Abs(filepath.Join(tr.projectRoot, DetachedLogsDir, detachedLogName(task.Name, started)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve log file of task '%s': %w", task.Name, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file of task '%s': %w", task.Name, err)
+	}
+
+	// The task holds its own descriptor once started
+	defer logFile.Close()
+
+	cmd.Stdin = nil
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detachProcess(cmd)
+
+	tr.logger.Debug("starting detached task", logging.KeyTask, task.Name, logging.KeyFile, task.Source, "path", cmd.Path, "args", cmd.Args[1:], "cwd", cmd.Dir, "log", logPath)
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("task '%s' failed: %w", task.Name, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	detached := &DetachedTask{
+		Task:    task.Name,
+		Source:  task.Source,
+		PID:     cmd.Process.Pid,
+		Log:     logPath,
+		Command: cmd.Args,
+		Started: started,
+	}
+
+	if err := writePidfile(tr.projectRoot, detached); err != nil {
+		_ = terminateDetached(detached.PID, true)
+
+		return nil, err
+	}
+
+	select {
+	case err := <-done:
+		removePidfile(tr.projectRoot, detached.PID)
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("exited during startup, see %s: %w", logPath, &TaskExitError{Task: task.Name, Code: exitErr.ExitCode(), Err: err})
+		} else if err != nil {
+			return nil, fmt.Errorf("task '%s' failed: %w", task.Name, err)
+		}
+
+		detached.Exited = true
+	case <-time.After(detachHealthWindow):
+		tr.logger.Debug("detached task is running", logging.KeyTask, task.Name, "pid", detached.PID)
+	}
+
+	return detached, nil
+}
+
+// ListDetached returns the detached tasks of the project that are still running, oldest first.
+// Pidfiles of tasks that have exited since are removed.
+func (tr *TaskRunner) ListDetached() ([]DetachedTask, error) {
+	dir := filepath.Join(tr.projectRoot, DetachedPidsDir)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pidfiles: %w", err)
+	}
+
+	running := []DetachedTask{}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pidfile: %w", err)
+		}
+
+		var detached DetachedTask
+		if err := json.Unmarshal(data, &detached); err != nil || detached.PID <= 0 {
+			tr.logger.Warn("ignoring unreadable pidfile", logging.KeyFile, path, "error", err)
+			continue
+		}
+
+		if !detachedAlive(detached.PID) {
+			tr.logger.Debug("removing pidfile of exited task", logging.KeyTask, detached.Task, "pid", detached.PID)
+			removePidfile(tr.projectRoot, detached.PID)
+
+			continue
+		}
+
+		running = append(running, detached)
+	}
+
+	sort.SliceStable(running, func(i, j int) bool {
+		return running[i].Started.Before(running[j].Started)
+	})
+
+	return running, nil
+}
+
+// StopDetached stops the running detached tasks named target, or the one with target as its
+// PID. Each gets a termination signal and is killed if still running after the grace period.
+func (tr *TaskRunner) StopDetached(target string) ([]DetachedTask, error) {
+	running, err := tr.ListDetached()
+	if err != nil {
+		return nil, err
+	}
+
+	pid, _ := strconv.Atoi(target)
+
+	var stopped []DetachedTask
+
+	for _, detached := range running {
+		if detached.Task != target && detached.PID != pid {
+			continue
+		}
+
+		if err := tr.stopDetachedTask(detached); err != nil {
+			return stopped, err
+		}
+
+		stopped = append(stopped, detached)
+	}
+
+	if len(stopped) == 0 {
+		return nil, fmt.Errorf("no detached task '%s' is running; see 'taskporter ps'", target)
+	}
+
+	return stopped, nil
+}
+
+// stopDetachedTask terminates a detached task's process group, then kills it after the grace period
+func (tr *TaskRunner) stopDetachedTask(detached DetachedTask) error {
+	tr.logger.Debug("stopping detached task", logging.KeyTask, detached.Task, "pid", detached.PID)
+
+	if err := terminateDetached(detached.PID, false); err != nil && detachedAlive(detached.PID) {
+		return fmt.Errorf("failed to stop task '%s' (PID %d): %w", detached.Task, detached.PID, err)
+	}
+
+	if !waitDetachedExit(detached.PID, terminationGrace) {
+		tr.logger.Warn("detached task did not exit after being signalled, killing it", logging.KeyTask, detached.Task, "pid", detached.PID, "grace", terminationGrace)
+
+		if err := terminateDetached(detached.PID, true); err != nil && detachedAlive(detached.PID) {
+			return fmt.Errorf("failed to kill task '%s' (PID %d): %w", detached.Task, detached.PID, err)
+		}
+
+		waitDetachedExit(detached.PID, time.Second)
+	}
+
+	removePidfile(tr.projectRoot, detached.PID)
+
+	return nil
+}
+
+// waitDetachedExit waits up to timeout for a detached task to exit and reports whether it did
+func waitDetachedExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	for detachedAlive(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return true
+}
+
+// detachedLogName names the log file of a detached run, e.g. "api-server-20250102-150405.log"
+func detachedLogName(taskName string, started time.Time) string {
+	name := unsafeFileChars.ReplaceAllString(taskName, "-")
+	if name == "" || name == "-" {
+		name = "task"
+	}
+
+	return fmt.Sprintf("%s-%s.log", name, started.Format("20060102-150405"))
+}
+
+// writePidfile records a detached task under .taskporter/pids/<pid>.json
+func writePidfile(projectRoot string, detached *DetachedTask) error {
+	dir := filepath.Join(projectRoot, DetachedPidsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create pidfile directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(detached, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pidfile: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, strconv.Itoa(detached.PID)+".json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write pidfile: %w", err)
+	}
+
+	return nil
+}
+
+// removePidfile stops tracking a detached task
+func removePidfile(projectRoot string, pid int) {
+	_ = os.Remove(filepath.Join(projectRoot, DetachedPidsDir, strconv.Itoa(pid)+".json"))
+}
@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRunner_Detached(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture tasks need sleep and a POSIX shell")
+	}
+
+	window := detachHealthWindow
+	detachHealthWindow = 200 * time.Millisecond
+
+	t.Cleanup(func() { detachHealthWindow = window })
+
+	newRunner := func(t *testing.T) (*TaskRunner, string) {
+		t.Helper()
+
+		projectRoot := t.TempDir()
+
+		return NewTaskRunnerWithProjectRoot(false, projectRoot, nil), projectRoot
+	}
+
+	t.Run("should start a task in the background, track it and stop it", func(t *testing.T) {
+		taskRunner, projectRoot := newRunner(t)
+
+		task := &config.Task{Name: "api server", Command: "sh", Args: []string{"-c", "echo listening; sleep 30"}, Type: config.TypeVSCodeTask, Source: "tasks.json"}
+
+		detached, err := taskRunner.StartDetached(task)
+		require.NoError(t, err)
+		require.False(t, detached.Exited)
+		require.Positive(t, detached.PID)
+		require.Equal(t, filepath.Join(projectRoot, DetachedLogsDir), filepath.Dir(detached.Log))
+		require.Regexp(t, `^api-server-\d{8}-\d{6}\.log$`, filepath.Base(detached.Log))
+		require.FileExists(t, filepath.Join(projectRoot, DetachedPidsDir, strconv.Itoa(detached.PID)+".json"))
+
+		running, err := taskRunner.ListDetached()
+		require.NoError(t, err)
+		require.Len(t, running, 1)
+		require.Equal(t, "api server", running[0].Task)
+		require.Equal(t, detached.PID, running[0].PID)
+
+		stopped, err := taskRunner.StopDetached("api server")
+		require.NoError(t, err)
+		require.Len(t, stopped, 1)
+		require.NoFileExists(t, filepath.Join(projectRoot, DetachedPidsDir, strconv.Itoa(detached.PID)+".json"))
+
+		running, err = taskRunner.ListDetached()
+		require.NoError(t, err)
+		require.Empty(t, running)
+
+		output, err := os.ReadFile(detached.Log)
+		require.NoError(t, err)
+		require.Equal(t, "listening\n", string(output))
+	})
+
+	t.Run("should stop a task by PID and kill it after the grace period", func(t *testing.T) {
+		grace := terminationGrace
+		terminationGrace = 300 * time.Millisecond
+
+		t.Cleanup(func() { terminationGrace = grace })
+
+		taskRunner, _ := newRunner(t)
+
+		detached, err := taskRunner.StartDetached(&config.Task{Name: "stubborn", Command: "sh", Args: []string{"-c", `trap "" TERM; sleep 30`}, Type: config.TypeVSCodeTask})
+		require.NoError(t, err)
+
+		started := time.Now()
+
+		stopped, err := taskRunner.StopDetached(strconv.Itoa(detached.PID))
+		require.NoError(t, err)
+		require.Len(t, stopped, 1)
+		require.Less(t, time.Since(started), 5*time.Second)
+		require.False(t, detachedAlive(detached.PID))
+	})
+
+	t.Run("should report a task that crashes during startup", func(t *testing.T) {
+		taskRunner, projectRoot := newRunner(t)
+
+		_, err := taskRunner.StartDetached(&config.Task{Name: "broken", Command: "sh", Args: []string{"-c", "echo port in use; exit 3"}, Type: config.TypeVSCodeTask})
+		require.ErrorContains(t, err, "exited during startup, see "+filepath.Join(projectRoot, DetachedLogsDir))
+		require.Equal(t, 3, ExitCode(err))
+
+		pidfiles, err := filepath.Glob(filepath.Join(projectRoot, DetachedPidsDir, "*.json"))
+		require.NoError(t, err)
+		require.Empty(t, pidfiles)
+	})
+
+	t.Run("should report a task that finished within the health window", func(t *testing.T) {
+		taskRunner, _ := newRunner(t)
+
+		detached, err := taskRunner.StartDetached(&config.Task{Name: "quick", Command: "true", Type: config.TypeVSCodeTask})
+		require.NoError(t, err)
+		require.True(t, detached.Exited)
+
+		running, err := taskRunner.ListDetached()
+		require.NoError(t, err)
+		require.Empty(t, running)
+	})
+
+	t.Run("should forget tasks that have exited", func(t *testing.T) {
+		taskRunner, projectRoot := newRunner(t)
+
+		require.NoError(t, writePidfile(projectRoot, &DetachedTask{Task: "gone", PID: 999999999}))
+
+		running, err := taskRunner.ListDetached()
+		require.NoError(t, err)
+		require.Empty(t, running)
+		require.NoFileExists(t, filepath.Join(projectRoot, DetachedPidsDir, "999999999.json"))
+	})
+
+	t.Run("should fail to stop a task that is not running", func(t *testing.T) {
+		taskRunner, _ := newRunner(t)
+
+		_, err := taskRunner.StopDetached("api")
+		require.EqualError(t, err, "no detached task 'api' is running; see 'taskporter ps'")
+	})
+}
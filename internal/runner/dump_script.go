@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// shellVariableName matches the variable names a POSIX shell can export or unset
+var shellVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// posixShells run a command line the way the POSIX shell a script is pasted into does
+var posixShells = []string{"sh", "bash", "zsh", "dash", "ksh", "ash"}
+
+// DumpScript returns a POSIX shell one-liner doing what running the task would: it changes into
+// the task's working directory, unsets and exports the variables the task changes, and runs the
+// command with its arguments quoted. It runs in a subshell, so pasting it into a terminal leaves
+// that terminal's directory and variables alone. Isolated and clean environments start from
+// `env -i`; remote and container runs are the ssh or engine invocation taskporter would start.
+func (tr *TaskRunner) DumpScript(task *config.Task) (string, error) {
+	args, err := tr.commandArgs(task)
+	if err != nil {
+		return "", err
+	}
+
+	if tr.remote != "" || tr.container != "" {
+		var cmd *exec.Cmd
+
+		if tr.remote != "" {
+			cmd, err = tr.buildRemoteCommand(task, args)
+		} else {
+			cmd, err = tr.buildContainerCommand(task, args)
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		return shellJoin(cmd.Args), nil
+	}
+
+	cwd, env, err := tr.taskCwdAndEnv(task)
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare task '%s': %w", task.Name, err)
+	}
+
+	var steps []string
+
+	if cwd != "" {
+		steps = append(steps, "cd "+shellQuote("", cwd))
+	}
+
+	// Variables that cannot be exported are passed by env, in front of the command
+	var envArgs []string
+
+	if tr.EnvStrategy(task) != EnvStrategyInherit {
+		// The whole environment is spelled out, as the task would not inherit the terminal's
+		built, _, err := tr.buildEnvironment(task)
+		if err != nil {
+			return "", fmt.Errorf("failed to build environment for task '%s': %w", task.Name, err)
+		}
+
+		envArgs = append([]string{"-i"}, effectiveEnv(built)...)
+	} else {
+		for _, key := range task.UnsetEnv {
+			if shellVariableName.MatchString(key) {
+				steps = append(steps, "unset "+key)
+			} else {
+				envArgs = append(envArgs, "-u", key)
+			}
+		}
+
+		keys := make([]string, 0, len(env))
+		for key := range env {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if shellVariableName.MatchString(key) {
+				steps = append(steps, fmt.Sprintf("export %s=%s", key, shellQuote("", env[key])))
+			} else {
+				envArgs = append(envArgs, key+"="+env[key])
+			}
+		}
+	}
+
+	command := tr.scriptCommand(task, args, len(envArgs) == 0)
+	if len(envArgs) > 0 {
+		command = shellJoin(append([]string{"env"}, envArgs...)) + " " + command
+	}
+
+	return "(" + strings.Join(append(steps, command), " && ") + ")", nil
+}
+
+// scriptCommand returns the command line running the task from a POSIX shell. With inline, the
+// command line of a task run by a POSIX shell is used as it is; otherwise, and for other shells,
+// the shell is invoked like taskporter does, so a program in front of it runs all of it.
+func (tr *TaskRunner) scriptCommand(task *config.Task, args []string, inline bool) string {
+	if task.Shell == "" {
+		return shellJoin(append([]string{tr.executable(task)}, args...))
+	}
+
+	commandLine := shellCommandLine(task, args)
+	if inline && slices.Contains(posixShells, shellName(task.Shell)) {
+		return commandLine
+	}
+
+	return shellJoin(append([]string{task.Shell}, shellInvocationArgs(task.Shell, commandLine)...))
+}
@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpScript(t *testing.T) {
+	t.Run("should cd, unset and export before running the quoted command", func(t *testing.T) {
+		task := &config.Task{
+			Name:     "greet",
+			Command:  "printf",
+			Args:     []string{"%s|%s\n", "it's", "$HOME"},
+			Cwd:      "/work/my project",
+			Env:      map[string]string{"GREETING": "say \"hi\"", "A_FLAG": "1"},
+			UnsetEnv: []string{"GOFLAGS"},
+		}
+
+		script, err := NewTaskRunner(false, nil).DumpScript(task)
+		require.NoError(t, err)
+		require.Equal(t, `(cd '/work/my project' && unset GOFLAGS && export A_FLAG=1 && export GREETING='say "hi"' && printf '%s|%s`+"\n"+`' 'it'\''s' '$HOME')`, script)
+	})
+
+	t.Run("should pass variables a shell cannot export through env", func(t *testing.T) {
+		task := &config.Task{
+			Name:     "build",
+			Command:  "make",
+			Env:      map[string]string{"npm_config_cache.dir": "/tmp/npm cache"},
+			UnsetEnv: []string{"ProgramFiles(x86)"},
+		}
+
+		script, err := NewTaskRunner(false, nil).DumpScript(task)
+		require.NoError(t, err)
+		require.Equal(t, `(env -u 'ProgramFiles(x86)' 'npm_config_cache.dir=/tmp/npm cache' make)`, script)
+	})
+
+	t.Run("should inline the command line of a POSIX shell task", func(t *testing.T) {
+		task := &config.Task{Name: "check", Command: "make lint && make test", Args: []string{"V=1 2"}, Shell: "/bin/bash"}
+
+		script, err := NewTaskRunner(false, nil).DumpScript(task)
+		require.NoError(t, err)
+		require.Equal(t, `(make lint && make test 'V=1 2')`, script)
+	})
+
+	t.Run("should invoke other shells and shells env runs", func(t *testing.T) {
+		task := &config.Task{Name: "check", Command: "dir", Shell: "cmd.exe"}
+
+		script, err := NewTaskRunner(false, nil).DumpScript(task)
+		require.NoError(t, err)
+		require.Equal(t, `(cmd.exe /d /c dir)`, script)
+
+		task = &config.Task{Name: "check", Command: "make lint && make test", Shell: "bash", Env: map[string]string{"my-var": "x"}}
+
+		script, err = NewTaskRunner(false, nil).DumpScript(task)
+		require.NoError(t, err)
+		require.Equal(t, `(env my-var=x bash -c 'make lint && make test')`, script, "env runs the whole command line")
+	})
+
+	t.Run("should spell out an isolated environment with env -i", func(t *testing.T) {
+		t.Setenv("PATH", "/usr/bin:/bin")
+		t.Setenv("HOME", "/home/dev")
+		t.Setenv("TMPDIR", "")
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetIsolatedEnv(nil)
+
+		script, err := runner.DumpScript(&config.Task{Name: "test", Command: "go", Args: []string{"test", "./..."}, Env: map[string]string{"CGO_ENABLED": "0"}})
+		require.NoError(t, err)
+		require.Equal(t, `(env -i CGO_ENABLED=0 HOME=/home/dev PATH=/usr/bin:/bin TMPDIR= go test ./...)`, script)
+	})
+
+	t.Run("should validate the task like running it would", func(t *testing.T) {
+		runner := NewTaskRunnerWithOptions(false, ".", true, nil)
+
+		_, err := runner.DumpScript(&config.Task{Name: "evil", Command: "echo", Args: []string{"$(rm -rf /)"}})
+		require.Error(t, err)
+	})
+
+	t.Run("should do what the task does when run by sh", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the script needs a POSIX shell")
+		}
+
+		dir := filepath.Join(t.TempDir(), "it's a dir")
+		require.NoError(t, os.Mkdir(dir, 0o755))
+
+		t.Setenv("GOFLAGS", "-mod=vendor")
+
+		task := &config.Task{
+			Name:     "show",
+			Command:  "sh",
+			Args:     []string{"-c", `printf '%s|%s|%s|%s' "$(basename "$PWD")" "$GREETING" "${GOFLAGS-unset}" "$1"`, "show", "a 'quoted' $arg"},
+			Cwd:      dir,
+			Env:      map[string]string{"GREETING": `say "hi" & $bye`},
+			UnsetEnv: []string{"GOFLAGS"},
+		}
+
+		script, err := NewTaskRunner(false, nil).DumpScript(task)
+		require.NoError(t, err)
+
+		out, err := exec.Command("sh", "-c", script).Output()
+		require.NoError(t, err)
+		require.Equal(t, `it's a dir|say "hi" & $bye|unset|a 'quoted' $arg`, string(out))
+	})
+}
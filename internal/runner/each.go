@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// itemReference is the name of the variable holding the current item of run --each
+const itemReference = "item"
+
+// ItemVariable is replaced with the current item in every iteration of run --each
+const ItemVariable = "${" + itemReference + "}"
+
+// ReadItems reads the items of run --each, one per line. Surrounding whitespace (including the
+// \r of CRLF files) is trimmed and blank lines are skipped.
+func ReadItems(r io.Reader) ([]string, error) {
+	var items []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if item := strings.TrimSpace(scanner.Text()); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read items: %w", err)
+	}
+
+	return items, nil
+}
+
+// ExpandItem returns a copy of the task with ${item} replaced by item in its args and command,
+// $${item} standing for a literal ${item}. Args are quoted by the runner like any other, but the
+// command of a shell task is a command line passed verbatim, so the item is shell-quoted there
+// to keep file names with spaces or shell characters one word.
+func ExpandItem(task *config.Task, item string) *config.Task {
+	expanded := *task
+	expanded.Args = slices.Clone(task.Args)
+
+	for i, arg := range expanded.Args {
+		expanded.Args[i] = config.ExpandVariable(arg, itemReference, item)
+	}
+
+	commandItem := item
+	if task.Shell != "" {
+		commandItem = shellQuote(task.Shell, item)
+	}
+
+	expanded.Command = config.ExpandVariable(task.Command, itemReference, commandItem)
+
+	return &expanded
+}
+
+// UsesItem reports whether the task references ${item} anywhere ExpandItem replaces it
+func UsesItem(task *config.Task) bool {
+	return strings.Contains(task.Command, ItemVariable) || slices.ContainsFunc(task.Args, func(arg string) bool {
+		return strings.Contains(arg, ItemVariable)
+	})
+}
+
+// RunEach calls run with the index of every item and the task expanded for it, in order. It
+// stops at the first failure unless keepGoing is set, and before any further item once ctx is
+// cancelled; the failures are returned joined.
+func RunEach(ctx context.Context, task *config.Task, items []string, keepGoing bool, run func(i int, task *config.Task) error) error {
+	var errs []error
+
+	for i, item := range items {
+		if ctx.Err() != nil {
+			errs = append(errs, fmt.Errorf("%d of %d items of '%s' were not run: %w", len(items)-i, len(items), task.Name, context.Cause(ctx)))
+			break
+		}
+
+		if err := run(i, ExpandItem(task, item)); err != nil {
+			errs = append(errs, fmt.Errorf("item %q: %w", item, err))
+
+			if !keepGoing {
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
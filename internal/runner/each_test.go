@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadItems(t *testing.T) {
+	t.Run("should read one item per line, skipping blank lines", func(t *testing.T) {
+		items, err := ReadItems(strings.NewReader("a.txt\r\n\n  dir/b c.txt  \nd.txt"))
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"a.txt", "dir/b c.txt", "d.txt"}, items)
+	})
+}
+
+func TestExpandItem(t *testing.T) {
+	t.Run("should replace ${item} in args without touching the original task", func(t *testing.T) {
+		task := &config.Task{Name: "process", Command: "convert", Args: []string{"--in", "${item}", "--out=${item}.png"}}
+
+		expanded := ExpandItem(task, "my file")
+
+		require.Equal(t, "convert", expanded.Command)
+		require.Equal(t, []string{"--in", "my file", "--out=my file.png"}, expanded.Args)
+		require.Equal(t, []string{"--in", "${item}", "--out=${item}.png"}, task.Args)
+		require.True(t, UsesItem(task))
+		require.False(t, UsesItem(expanded))
+	})
+
+	t.Run("should quote the item in a shell command line", func(t *testing.T) {
+		task := &config.Task{Name: "count", Command: "wc -l ${item}", Shell: "/bin/sh"}
+
+		require.Equal(t, "wc -l 'my file.txt'", ExpandItem(task, "my file.txt").Command)
+		require.Equal(t, "wc -l a.txt", ExpandItem(task, "a.txt").Command)
+	})
+
+	t.Run("should leave escaped and other variables as written", func(t *testing.T) {
+		task := &config.Task{Name: "echo", Command: "echo", Args: []string{"$${item}", "${workspaceFolder}/${item}"}}
+
+		require.Equal(t, []string{"${item}", "${workspaceFolder}/a.txt"}, ExpandItem(task, "a.txt").Args)
+	})
+}
+
+func TestRunEach(t *testing.T) {
+	task := &config.Task{Name: "process", Command: "process", Args: []string{"${item}"}}
+	items := []string{"a", "b", "c"}
+
+	t.Run("should run every item in order", func(t *testing.T) {
+		var ran []string
+
+		err := RunEach(context.Background(), task, items, false, func(i int, expanded *config.Task) error {
+			ran = append(ran, expanded.Args[0])
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, items, ran)
+	})
+
+	t.Run("should stop at the first failure", func(t *testing.T) {
+		var ran []string
+
+		err := RunEach(context.Background(), task, items, false, func(i int, expanded *config.Task) error {
+			ran = append(ran, expanded.Args[0])
+			return errors.New("boom")
+		})
+
+		require.EqualError(t, err, `item "a": boom`)
+		require.Equal(t, []string{"a"}, ran)
+	})
+
+	t.Run("should run the remaining items with keepGoing", func(t *testing.T) {
+		err := RunEach(context.Background(), task, items, true, func(i int, expanded *config.Task) error {
+			if expanded.Args[0] == "b" {
+				return nil
+			}
+
+			return errors.New("boom")
+		})
+
+		require.EqualError(t, err, "item \"a\": boom\nitem \"c\": boom")
+	})
+
+	t.Run("should not start items once cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := RunEach(ctx, task, items, true, func(i int, expanded *config.Task) error {
+			cancel()
+			return nil
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorContains(t, err, "2 of 3 items of 'process' were not run")
+	})
+}
@@ -0,0 +1,180 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// secretKeyMarkers are key substrings that mark a variable's value as sensitive
+var secretKeyMarkers = []string{"TOKEN", "SECRET", "KEY", "PASSWORD"}
+
+// redactedValue replaces sensitive values in output
+const redactedValue = "<redacted>"
+
+// maxDisplayValueLength is how much of a value is printed before it is truncated
+const maxDisplayValueLength = 60
+
+// EnvChange is a variable a task sets, with the inherited value it replaces, if any
+type EnvChange struct {
+	Key        string
+	Value      string
+	Previous   string
+	Overridden bool
+}
+
+// EnvDiff describes what a task's environment adds to, overrides in or removes from the
+// inherited environment. The lists are sorted by key.
+type EnvDiff struct {
+	Added      []EnvChange
+	Overridden []EnvChange
+	Removed    []EnvChange // Variables the task unsets, with their inherited value as Previous
+	Inherited  int         // Number of variables in the environment the task starts from
+}
+
+// Changes returns every added and overridden variable, sorted by key. Removed variables are not changes to apply.
+func (d *EnvDiff) Changes() []EnvChange {
+	changes := append(append([]EnvChange(nil), d.Added...), d.Overridden...)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// IsEmpty reports whether the task leaves the inherited environment untouched
+func (d *EnvDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Overridden) == 0 && len(d.Removed) == 0
+}
+
+// Lines renders the diff for display, "+ KEY=value" for added, "~ KEY=value (was: old)" for
+// overridden and "- KEY (was: old)" for removed variables, with secrets redacted and long
+// values truncated
+func (d *EnvDiff) Lines() []string {
+	lines := make([]string, 0, len(d.Added)+len(d.Overridden)+len(d.Removed))
+
+	for _, change := range d.Changes() {
+		value := DisplayEnvValue(change.Key, change.Value)
+
+		if change.Overridden {
+			lines = append(lines, fmt.Sprintf("~ %s=%s (was: %s)", change.Key, value, DisplayEnvValue(change.Key, change.Previous)))
+		} else {
+			lines = append(lines, fmt.Sprintf("+ %s=%s", change.Key, value))
+		}
+	}
+
+	for _, change := range d.Removed {
+		lines = append(lines, fmt.Sprintf("- %s (was: %s)", change.Key, DisplayEnvValue(change.Key, change.Previous)))
+	}
+
+	return lines
+}
+
+// removeEnvKeys drops the variables named in keys from a KEY=VALUE environment, returning what
+// is left and the removed variables sorted by key. Keys that are not set are ignored.
+func removeEnvKeys(env []string, keys []string) ([]string, []EnvChange) {
+	if len(keys) == 0 {
+		return env, nil
+	}
+
+	var (
+		kept    []string
+		removed []EnvChange
+	)
+
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+
+		if slices.Contains(keys, key) {
+			removed = append(removed, EnvChange{Key: key, Previous: value})
+			continue
+		}
+
+		kept = append(kept, entry)
+	}
+
+	sort.Slice(removed, func(i, j int) bool {
+		return removed[i].Key < removed[j].Key
+	})
+
+	return kept, removed
+}
+
+// diffEnvironment compares task variables against an inherited KEY=VALUE environment.
+// Variables set to their inherited value are not changes.
+func diffEnvironment(inherited []string, taskEnv map[string]string) *EnvDiff {
+	current := make(map[string]string, len(inherited))
+
+	for _, entry := range inherited {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			current[key] = value
+		}
+	}
+
+	keys := make([]string, 0, len(taskEnv))
+	for key := range taskEnv {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	diff := &EnvDiff{}
+
+	for _, key := range keys {
+		value := taskEnv[key]
+
+		previous, exists := current[key]
+
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, EnvChange{Key: key, Value: value})
+		case previous != value:
+			diff.Overridden = append(diff.Overridden, EnvChange{Key: key, Value: value, Previous: previous, Overridden: true})
+		}
+	}
+
+	return diff
+}
+
+// IsSecretEnvKey reports whether a variable name suggests its value is a credential
+func IsSecretEnvKey(key string) bool {
+	upper := strings.ToUpper(key)
+
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DisplayEnvValue returns a value that is safe to print: secrets are redacted and long values truncated
+func DisplayEnvValue(key, value string) string {
+	if IsSecretEnvKey(key) && value != "" {
+		return redactedValue
+	}
+
+	if runes := []rune(value); len(runes) > maxDisplayValueLength {
+		return string(runes[:maxDisplayValueLength]) + "…"
+	}
+
+	return value
+}
+
+// RedactEnv returns a copy of env with secret values redacted, for printing
+func RedactEnv(env map[string]string) map[string]string {
+	redacted := make(map[string]string, len(env))
+
+	for key, value := range env {
+		if IsSecretEnvKey(key) && value != "" {
+			value = redactedValue
+		}
+
+		redacted[key] = value
+	}
+
+	return redacted
+}
@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvDiff(t *testing.T) {
+	inherited := []string{"HOME=/home/dev", "GITHUB_TOKEN=ghp_inherited", "MODE=local", "EMPTY="}
+
+	t.Run("diffEnvironment", func(t *testing.T) {
+		diff := diffEnvironment(inherited, map[string]string{
+			"MODE":         "ci",
+			"HOME":         "/home/dev",
+			"GITHUB_TOKEN": "ghp_task",
+			"NEW":          "1",
+			"EMPTY":        "set",
+		})
+
+		require.Equal(t, []EnvChange{{Key: "NEW", Value: "1"}}, diff.Added)
+		require.Equal(t, []string{"EMPTY", "GITHUB_TOKEN", "MODE"}, keysOf(diff.Overridden))
+		require.Equal(t, "local", diff.Overridden[2].Previous)
+		require.False(t, diff.IsEmpty())
+		require.True(t, diffEnvironment(inherited, nil).IsEmpty())
+	})
+
+	t.Run("Lines redacts secrets and marks overrides", func(t *testing.T) {
+		diff := diffEnvironment(inherited, map[string]string{
+			"MODE":         "ci",
+			"GITHUB_TOKEN": "ghp_task",
+			"NEW":          "1",
+		})
+
+		require.Equal(t, []string{
+			"~ GITHUB_TOKEN=<redacted> (was: <redacted>)",
+			"~ MODE=ci (was: local)",
+			"+ NEW=1",
+		}, diff.Lines())
+	})
+
+	t.Run("removeEnvKeys", func(t *testing.T) {
+		kept, removed := removeEnvKeys(inherited, []string{"MODE", "GITHUB_TOKEN", "MISSING"})
+
+		require.Equal(t, []string{"HOME=/home/dev", "EMPTY="}, kept)
+		require.Equal(t, []string{"GITHUB_TOKEN", "MODE"}, keysOf(removed))
+
+		diff := &EnvDiff{Removed: removed}
+		require.False(t, diff.IsEmpty())
+		require.Equal(t, []string{"- GITHUB_TOKEN (was: <redacted>)", "- MODE (was: local)"}, diff.Lines())
+	})
+
+	t.Run("DisplayEnvValue", func(t *testing.T) {
+		for _, key := range []string{"API_TOKEN", "client_secret", "AWS_ACCESS_KEY_ID", "DB_PASSWORD"} {
+			require.Equal(t, "<redacted>", DisplayEnvValue(key, "hunter2"), key)
+		}
+
+		require.Equal(t, "", DisplayEnvValue("API_TOKEN", ""))
+		require.Equal(t, "debug", DisplayEnvValue("LOG_LEVEL", "debug"))
+
+		long := strings.Repeat("/usr/local/bin:", 10)
+		require.Equal(t, long[:60]+"…", DisplayEnvValue("PATH", long))
+	})
+
+	t.Run("RedactEnv", func(t *testing.T) {
+		require.Equal(t,
+			map[string]string{"NPM_TOKEN": "<redacted>", "NODE_ENV": "production"},
+			RedactEnv(map[string]string{"NPM_TOKEN": "npm_abc", "NODE_ENV": "production"}))
+	})
+
+	t.Run("PreviewEnvironment treats remote variables as added", func(t *testing.T) {
+		t.Setenv("TASKPORTER_TEST_MODE", "local")
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetRemote("dev@devbox", nil)
+
+		diff, err := runner.PreviewEnvironment(&config.Task{Env: map[string]string{"TASKPORTER_TEST_MODE": "ci"}})
+		require.NoError(t, err)
+		require.Equal(t, []EnvChange{{Key: "TASKPORTER_TEST_MODE", Value: "ci"}}, diff.Added)
+		require.Empty(t, diff.Overridden)
+	})
+}
+
+func TestTaskRunner_ResolveEnvironment(t *testing.T) {
+	t.Run("should list the final value of every variable the task gets", func(t *testing.T) {
+		t.Setenv("TASKPORTER_TEST_MODE", "local")
+		t.Setenv("TASKPORTER_TEST_TOKEN", "secret")
+		t.Setenv("TASKPORTER_TEST_DROPPED", "1")
+
+		env, err := NewTaskRunner(false, nil).ResolveEnvironment(&config.Task{
+			Env:      map[string]string{"TASKPORTER_TEST_MODE": "ci", "TASKPORTER_TEST_NEW": "a=b"},
+			UnsetEnv: []string{"TASKPORTER_TEST_DROPPED"},
+		})
+		require.NoError(t, err)
+		require.Contains(t, env, "TASKPORTER_TEST_MODE=ci")
+		require.NotContains(t, env, "TASKPORTER_TEST_MODE=local")
+		require.Contains(t, env, "TASKPORTER_TEST_NEW=a=b")
+		require.Contains(t, env, "TASKPORTER_TEST_TOKEN=secret")
+		require.NotContains(t, env, "TASKPORTER_TEST_DROPPED=1")
+		require.IsIncreasing(t, env)
+	})
+
+	t.Run("should list only the task's variables for remote runs", func(t *testing.T) {
+		runner := NewTaskRunner(false, nil)
+		runner.SetRemote("dev@devbox", nil)
+
+		env, err := runner.ResolveEnvironment(&config.Task{Env: map[string]string{"B": "2", "A": "1"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"A=1", "B=2"}, env)
+	})
+}
+
+func keysOf(changes []EnvChange) []string {
+	keys := make([]string, 0, len(changes))
+	for _, change := range changes {
+		keys = append(keys, change.Key)
+	}
+
+	return keys
+}
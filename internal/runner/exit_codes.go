@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TaskExitError reports a task whose process ran but exited non-zero, carrying the exit code
+// so callers can tell a failed check (exit 1) from a missing command (exit 127)
+type TaskExitError struct {
+	Task string
+	Code int // -1 when the process was killed by a signal
+	Err  error
+}
+
+func (e *TaskExitError) Error() string {
+	return fmt.Sprintf("task '%s' failed: %v", e.Task, e.Err)
+}
+
+func (e *TaskExitError) Unwrap() error {
+	return e.Err
+}
+
+// exitCodeRange is an inclusive range of exit codes
+type exitCodeRange struct {
+	from, to int
+}
+
+// ExitCodes is a set of exit codes a task may end with and still count as successful
+type ExitCodes []exitCodeRange
+
+// ParseExitCodes parses a comma-separated list of exit codes and inclusive ranges, e.g. "1" or "0-3,5"
+func ParseExitCodes(spec string) (ExitCodes, error) {
+	var codes ExitCodes
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fromText, toText, isRange := strings.Cut(part, "-")
+		if !isRange {
+			toText = fromText
+		}
+
+		from, err := parseExitCode(fromText)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := parseExitCode(toText)
+		if err != nil {
+			return nil, err
+		}
+
+		if from > to {
+			return nil, fmt.Errorf("invalid exit code range '%s': %d is greater than %d", part, from, to)
+		}
+
+		codes = append(codes, exitCodeRange{from: from, to: to})
+	}
+
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("no exit codes in '%s'", spec)
+	}
+
+	return codes, nil
+}
+
+// parseExitCode parses a single exit code, which processes report in 0-255
+func parseExitCode(text string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || code < 0 || code > 255 {
+		return 0, fmt.Errorf("invalid exit code '%s': must be a number from 0 to 255", strings.TrimSpace(text))
+	}
+
+	return code, nil
+}
+
+// Contains reports whether code is in the set
+func (c ExitCodes) Contains(code int) bool {
+	for _, r := range c {
+		if code >= r.from && code <= r.to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String formats the set the way ParseExitCodes reads it
+func (c ExitCodes) String() string {
+	parts := make([]string, len(c))
+
+	for i, r := range c {
+		if r.from == r.to {
+			parts[i] = strconv.Itoa(r.from)
+		} else {
+			parts[i] = fmt.Sprintf("%d-%d", r.from, r.to)
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
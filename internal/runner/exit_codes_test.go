@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExitCodes(t *testing.T) {
+	t.Run("reads codes and ranges", func(t *testing.T) {
+		codes, err := ParseExitCodes("1, 3-5,10")
+		require.NoError(t, err)
+		require.Equal(t, "1,3-5,10", codes.String())
+
+		for _, code := range []int{1, 3, 4, 5, 10} {
+			require.True(t, codes.Contains(code), code)
+		}
+
+		for _, code := range []int{0, 2, 6, 127} {
+			require.False(t, codes.Contains(code), code)
+		}
+	})
+
+	t.Run("rejects invalid specs", func(t *testing.T) {
+		for _, spec := range []string{"", ",", "one", "5-3", "256", "-1", "1-"} {
+			_, err := ParseExitCodes(spec)
+			require.Error(t, err, spec)
+		}
+	})
+}
+
+func TestTaskRunner_ExitCodes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture script needs a POSIX shell")
+	}
+
+	// exitingTask exits with code, like a report-only linter with findings
+	exitingTask := func(code int) *config.Task {
+		return &config.Task{
+			Name:    "lint",
+			Command: "sh",
+			Args:    []string{"-c", "exit " + strconv.Itoa(code)},
+			Type:    config.TypeVSCodeTask,
+		}
+	}
+
+	t.Run("reports the child's exit code in a typed error", func(t *testing.T) {
+		for _, code := range []int{1, 2, 127} {
+			err := NewTaskRunner(false, nil).RunTask(exitingTask(code))
+
+			var exitErr *TaskExitError
+			require.ErrorAs(t, err, &exitErr)
+			require.Equal(t, "lint", exitErr.Task)
+			require.Equal(t, code, exitErr.Code)
+			require.Equal(t, code, ExitCode(err))
+			require.EqualError(t, err, "task 'lint' failed: exit status "+strconv.Itoa(code))
+
+			// The process error stays reachable
+			var processErr *exec.ExitError
+			require.ErrorAs(t, err, &processErr)
+		}
+	})
+
+	t.Run("does not report an exit code for tasks that cannot start", func(t *testing.T) {
+		err := NewTaskRunner(false, nil).RunTask(&config.Task{Name: "missing", Command: "taskporter-no-such-command", Type: config.TypeVSCodeTask})
+		require.Error(t, err)
+
+		var exitErr *TaskExitError
+		require.False(t, errors.As(err, &exitErr))
+		require.Equal(t, 1, ExitCode(err))
+	})
+
+	t.Run("counts expected exit codes as success", func(t *testing.T) {
+		codes, err := ParseExitCodes("1-2")
+		require.NoError(t, err)
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetExpectedExitCodes(codes)
+
+		require.NoError(t, runner.RunTask(exitingTask(0)))
+		require.NoError(t, runner.RunTask(exitingTask(1)))
+		require.NoError(t, runner.RunTask(exitingTask(2)))
+
+		err = runner.RunTask(exitingTask(3))
+
+		var exitErr *TaskExitError
+		require.ErrorAs(t, err, &exitErr)
+		require.Equal(t, 3, exitErr.Code)
+	})
+}
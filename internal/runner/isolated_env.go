@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// Strategies for the environment tasks start from
+const (
+	EnvStrategyInherit  = "inherit"  // Taskporter's whole environment, like IDEs run tasks
+	EnvStrategyIsolated = "isolated" // Only the variables isolatedEnvKeys and --keep-env name
+	EnvStrategyClean    = "clean"    // Only cleanPath and the variables --keep-env names
+)
+
+// isolatedEnvKeys are the variables an isolated environment takes from taskporter's
+var isolatedEnvKeys = []string{"PATH", "HOME", "TMPDIR"}
+
+// windowsIsolatedEnvKeys are kept as well on Windows, where many programs fail to start without them
+var windowsIsolatedEnvKeys = []string{"SYSTEMROOT", "USERPROFILE", "TEMP", "TMP", "PATHEXT", "COMSPEC"}
+
+// cleanPath is the PATH of a clean environment: the system directories only, so tools installed
+// per user or per shell session can't make a build differ between machines
+var cleanPath = []string{"/usr/local/sbin", "/usr/local/bin", "/usr/sbin", "/usr/bin", "/sbin", "/bin"}
+
+// SetIsolatedEnv starts tasks from a minimal environment of PATH, HOME and TMPDIR plus the
+// variables named in keep, instead of inheriting taskporter's whole environment. The task's
+// own variables are applied on top. The user asked for the kept variables, so in paranoid
+// mode tasks may set them even when they are system variables such as PATH.
+func (tr *TaskRunner) SetIsolatedEnv(keep []string) {
+	tr.isolateEnv = true
+	tr.keepEnv = keep
+}
+
+// SetCleanEnv starts tasks from an environment holding nothing but a PATH of the system
+// directories and the variables named in keep, for reproducible builds that inherited shell
+// variables can't change. The task's own variables are applied on top. Tasks with "cleanEnv":
+// true start from a clean environment without it.
+func (tr *TaskRunner) SetCleanEnv(keep []string) {
+	tr.cleanEnv = true
+	tr.keepEnv = keep
+}
+
+// EnvStrategy returns the strategy for the environment task starts from
+func (tr *TaskRunner) EnvStrategy(task *config.Task) string {
+	switch {
+	case tr.cleanEnv || task.CleanEnv:
+		return EnvStrategyClean
+	case tr.isolateEnv:
+		return EnvStrategyIsolated
+	default:
+		return EnvStrategyInherit
+	}
+}
+
+// baseEnvironment returns the KEY=VALUE environment of strategy, before the task's own variables
+func (tr *TaskRunner) baseEnvironment(strategy string) []string {
+	var keys []string
+
+	switch strategy {
+	case EnvStrategyInherit:
+		return os.Environ()
+	case EnvStrategyIsolated:
+		keys = slices.Concat(isolatedEnvKeys, tr.keepEnv)
+	case EnvStrategyClean:
+		keys = slices.Clone(tr.keepEnv)
+	}
+
+	// Windows programs need these whatever the strategy, and its system PATH isn't at fixed places
+	if runtime.GOOS == "windows" {
+		keys = append(keys, windowsIsolatedEnvKeys...)
+		if strategy == EnvStrategyClean {
+			keys = append(keys, "PATH")
+		}
+	}
+
+	var env []string
+
+	if strategy == EnvStrategyClean && runtime.GOOS != "windows" && !slices.Contains(keys, "PATH") {
+		env = append(env, "PATH="+strings.Join(cleanPath, string(os.PathListSeparator)))
+	}
+
+	for i, key := range keys {
+		if slices.Contains(keys[:i], key) {
+			continue
+		}
+
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+
+	return env
+}
+
+// sanitizeTaskEnv validates a task's own variables for paranoid mode, allowing the kept ones
+func (tr *TaskRunner) sanitizeTaskEnv(env map[string]string) (map[string]string, error) {
+	return tr.sanitizer.SanitizeEnvironmentKeeping(env, tr.keepEnv)
+}
@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"regexp"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+)
+
+// problemScanner forwards output unchanged while collecting the lines that match a problem pattern.
+// Each output stream gets its own scanner, so it is only written from one goroutine.
+type problemScanner struct {
+	w        io.Writer
+	patterns []*regexp.Regexp
+	partial  []byte
+	problems []string
+}
+
+// newProblemScanner wraps w, matching every complete line against patterns
+func newProblemScanner(w io.Writer, patterns []*regexp.Regexp) *problemScanner {
+	return &problemScanner{w: w, patterns: patterns}
+}
+
+// Write implements io.Writer
+func (s *problemScanner) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+
+	s.partial = append(s.partial, p[:n]...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			break
+		}
+
+		s.match(s.partial[:i])
+		s.partial = s.partial[i+1:]
+	}
+
+	return n, err
+}
+
+// Flush matches the last line when the output did not end with a newline
+func (s *problemScanner) Flush() {
+	if len(s.partial) > 0 {
+		s.match(s.partial)
+		s.partial = nil
+	}
+}
+
+// match records line if any pattern matches it
+func (s *problemScanner) match(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+
+	for _, pattern := range s.patterns {
+		if pattern.Match(line) {
+			s.problems = append(s.problems, string(line))
+			return
+		}
+	}
+}
+
+// compileProblemPatterns compiles the task's problemMatcher regexps. Patterns Go cannot compile
+// (e.g. JavaScript lookarounds) are skipped with a warning.
+func compileProblemPatterns(task *config.Task, logger *slog.Logger) []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(task.ProblemPatterns))
+
+	for _, expr := range task.ProblemPatterns {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			logger.Warn("skipping problem matcher pattern that cannot be compiled", logging.KeyTask, task.Name, "pattern", expr, "error", err)
+			continue
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns
+}
@@ -0,0 +1,50 @@
+//go:build !unix
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+// InterruptSignals are the signals taskporter passes on to running tasks
+var InterruptSignals = []os.Signal{os.Interrupt}
+
+// startInOwnGroup leaves the task in taskporter's console, which delivers Ctrl+C to both
+func startInOwnGroup(*exec.Cmd) bool {
+	return false
+}
+
+// signalTask stops the task. Processes can only be killed here, not sent other signals.
+func signalTask(cmd *exec.Cmd, _ os.Signal, _ bool) error {
+	return cmd.Process.Kill()
+}
+
+// killTask kills the task
+func killTask(cmd *exec.Cmd, _ bool) error {
+	return cmd.Process.Kill()
+}
+
+// detachProcess leaves the task in taskporter's console. Without process groups only its
+// output is redirected.
+func detachProcess(*exec.Cmd) {}
+
+// detachedAlive reports whether a process with this PID exists
+func detachedAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	return process.Release() == nil
+}
+
+// terminateDetached kills the detached task; a graceful stop is not possible here
+func terminateDetached(pid int, _ bool) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return process.Kill()
+}
@@ -0,0 +1,68 @@
+//go:build unix
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// InterruptSignals are the signals taskporter passes on to running tasks
+var InterruptSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// startInOwnGroup makes the task the leader of a new process group, so signals reach every
+// process it starts
+func startInOwnGroup(cmd *exec.Cmd) bool {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	cmd.SysProcAttr.Setpgid = true
+
+	return true
+}
+
+// signalTask sends sig to the task's process group, or only to its process when it shares
+// taskporter's group
+func signalTask(cmd *exec.Cmd, sig os.Signal, ownGroup bool) error {
+	number, ok := sig.(syscall.Signal)
+	if !ownGroup || !ok {
+		return cmd.Process.Signal(sig)
+	}
+
+	return syscall.Kill(-cmd.Process.Pid, number)
+}
+
+// killTask kills the task along with the rest of its process group
+func killTask(cmd *exec.Cmd, ownGroup bool) error {
+	return signalTask(cmd, syscall.SIGKILL, ownGroup)
+}
+
+// detachProcess starts the task in a new session, so it has its own process group and keeps
+// running when the terminal taskporter ran in closes
+func detachProcess(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	cmd.SysProcAttr.Setsid = true
+}
+
+// detachedAlive reports whether the detached task with this PID still runs. A detached task
+// leads its own process group, which tells it apart from an unrelated process reusing the PID.
+func detachedAlive(pid int) bool {
+	pgid, err := syscall.Getpgid(pid)
+
+	return err == nil && pgid == pid
+}
+
+// terminateDetached sends SIGTERM, or SIGKILL when kill is set, to a detached task's process group
+func terminateDetached(pid int, kill bool) error {
+	sig := syscall.SIGTERM
+	if kill {
+		sig = syscall.SIGKILL
+	}
+
+	return syscall.Kill(-pid, sig)
+}
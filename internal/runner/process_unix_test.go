@@ -0,0 +1,44 @@
+//go:build unix
+
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRunner_ForwardsSignals(t *testing.T) {
+	t.Run("should pass SIGTERM on to the task's process group", func(t *testing.T) {
+		dir := t.TempDir()
+		ready := filepath.Join(dir, "ready")
+
+		// The shell reports the signal it got, proving it reached the task rather than killing it
+		script := `trap 'echo terminated; exit 3' TERM; touch "$0"; while :; do sleep 0.1; done`
+
+		var stdout bytes.Buffer
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetOutput(&stdout, &stdout)
+
+		go func() {
+			for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+				if _, err := os.Stat(ready); err == nil {
+					_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+					return
+				}
+			}
+		}()
+
+		err := runner.RunTask(&config.Task{Name: "server", Command: "sh", Args: []string{"-c", script, ready}, Type: config.TypeVSCodeTask})
+		require.ErrorContains(t, err, "task 'server' was stopped by terminated")
+		require.Contains(t, stdout.String(), "terminated\n")
+	})
+}
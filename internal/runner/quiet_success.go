@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"io"
+	"os/exec"
+	"sync"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+)
+
+// heldOutput is the output of a task run with quiet success, kept back until the task's outcome is known
+type heldOutput struct {
+	mu     sync.Mutex
+	chunks []heldChunk
+	size   int
+}
+
+// heldChunk is output written to one stream in a row
+type heldChunk struct {
+	out  io.Writer // Where the output would have gone
+	data []byte
+}
+
+// heldStream holds back the output of one of the task's streams
+type heldStream struct {
+	held *heldOutput
+	out  io.Writer
+}
+
+// Write adds p to the held output, after whatever either stream wrote before it
+func (s *heldStream) Write(p []byte) (int, error) {
+	s.held.mu.Lock()
+	defer s.held.mu.Unlock()
+
+	if last := len(s.held.chunks) - 1; last >= 0 && s.held.chunks[last].out == s.out {
+		s.held.chunks[last].data = append(s.held.chunks[last].data, p...)
+	} else {
+		s.held.chunks = append(s.held.chunks, heldChunk{out: s.out, data: append([]byte(nil), p...)})
+	}
+
+	s.held.size += len(p)
+
+	return len(p), nil
+}
+
+// SetQuietSuccess holds task output back and shows it only when the task fails, like `make -s`
+// with errors only, so noisy tasks that succeed leave nothing in CI logs
+func (tr *TaskRunner) SetQuietSuccess(quiet bool) {
+	tr.quietSuccess = quiet
+}
+
+// holdOutput holds the command's output back when quiet success is enabled. A failing task's
+// output is shown interleaved as it was read, each stream going back where it would have gone.
+// Streams going to the same writer share one pipe, which keeps their exact order. Interactive
+// tasks keep the raw terminal.
+func (tr *TaskRunner) holdOutput(task *config.Task, cmd *exec.Cmd) *heldOutput {
+	if !tr.quietSuccess || (task.Interactive && !tr.forceCapture) {
+		return nil
+	}
+
+	held := &heldOutput{}
+	stdout := &heldStream{held: held, out: cmd.Stdout}
+
+	if cmd.Stderr == cmd.Stdout {
+		cmd.Stdout, cmd.Stderr = stdout, stdout
+	} else {
+		cmd.Stdout, cmd.Stderr = stdout, &heldStream{held: held, out: cmd.Stderr}
+	}
+
+	return held
+}
+
+// release shows the held output of a failed task, or drops the output of one that succeeded
+func (tr *TaskRunner) release(task *config.Task, held *heldOutput, failed bool) {
+	if held == nil {
+		return
+	}
+
+	if !failed {
+		tr.logger.Debug("dropped the output of a successful task", logging.KeyTask, task.Name, "bytes", held.size)
+		return
+	}
+
+	for _, chunk := range held.chunks {
+		if _, err := chunk.out.Write(chunk.data); err != nil {
+			tr.logger.Warn("failed to show the output of a failed task", logging.KeyTask, task.Name, "error", err)
+			return
+		}
+	}
+}
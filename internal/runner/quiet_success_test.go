@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietSuccess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture tasks need a POSIX shell")
+	}
+
+	newShellTask := func(script string, interactive bool) *config.Task {
+		return &config.Task{Name: "noisy", Command: "sh", Args: []string{"-c", script}, Interactive: interactive}
+	}
+
+	newRunner := func(out *bytes.Buffer) *TaskRunner {
+		runner := NewTaskRunner(false, nil)
+		runner.SetOutput(out, out)
+		runner.SetQuietSuccess(true)
+
+		return runner
+	}
+
+	t.Run("drops the output of a task that succeeds", func(t *testing.T) {
+		var out bytes.Buffer
+
+		require.NoError(t, newRunner(&out).RunTask(newShellTask("echo compiling; echo warning >&2", false)))
+		require.Empty(t, out.String())
+	})
+
+	t.Run("shows both streams in order when the task fails", func(t *testing.T) {
+		var out bytes.Buffer
+
+		err := newRunner(&out).RunTask(newShellTask("echo compiling; echo 'main.go:3: undefined: x' >&2; echo done; exit 2", false))
+		require.Equal(t, 2, ExitCode(err))
+		require.Equal(t, "compiling\nmain.go:3: undefined: x\ndone\n", out.String())
+	})
+
+	t.Run("shows each stream's output on that stream when the task fails", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetOutput(&stdout, &stderr)
+		runner.SetQuietSuccess(true)
+
+		err := runner.RunTask(newShellTask("echo compiling; echo 'main.go:3: undefined: x' >&2; echo done; exit 2", false))
+		require.Equal(t, 2, ExitCode(err))
+		require.Equal(t, "compiling\ndone\n", stdout.String())
+		require.Equal(t, "main.go:3: undefined: x\n", stderr.String())
+	})
+
+	t.Run("shows the output of a task failing its problem matcher", func(t *testing.T) {
+		var out bytes.Buffer
+
+		runner := newRunner(&out)
+		runner.SetRespectProblemMatcher(true)
+
+		task := newShellTask("echo 'lint.go:1: unused variable'", false)
+		task.ProblemPatterns = []string{`^(.*):(\d+): (.*)$`}
+
+		require.ErrorContains(t, runner.RunTask(task), "reported 1 problem(s)")
+		require.Equal(t, "lint.go:1: unused variable\n", out.String())
+	})
+
+	t.Run("holds interactive tasks only with force capture", func(t *testing.T) {
+		var out bytes.Buffer
+
+		runner := newRunner(&out)
+		runner.SetForceCapture(true)
+
+		require.Error(t, runner.RunTask(newShellTask("echo 'Continue? [y/N]'; exit 1", true)))
+		require.Equal(t, "Continue? [y/N]\n", out.String())
+	})
+}
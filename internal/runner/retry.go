@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+)
+
+// RetryPolicy controls how a task that exits non-zero is attempted again
+type RetryPolicy struct {
+	Retries int           // Attempts after the first one
+	Delay   time.Duration // Wait before the first retry; each further retry waits twice as long
+}
+
+// SetRetryPolicy retries tasks that exit non-zero when run with RunTaskWithRetries
+func (tr *TaskRunner) SetRetryPolicy(policy RetryPolicy) {
+	tr.retry = policy
+}
+
+// RunTaskWithRetries runs the task, attempting it again after a failed attempt as the retry
+// policy allows. Only non-zero exits are retried: a task that cannot start or that only
+// reported problems would fail the same way again. Cancelling ctx, e.g. on an interrupt or
+// a timeout, stops further attempts, and the errors then carry the context's cause.
+func (tr *TaskRunner) RunTaskWithRetries(ctx context.Context, task *config.Task) error {
+	attempts := tr.retry.Retries + 1
+	delay := tr.retry.Delay
+
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			return fmt.Errorf("task '%s' was not started: %w", task.Name, context.Cause(ctx))
+		}
+
+		err := tr.RunTask(task)
+		if err == nil {
+			if attempt > 1 {
+				fmt.Printf("✅ Attempt %d/%d of '%s' succeeded\n", attempt, attempts, task.Name)
+			}
+
+			return nil
+		}
+
+		var exitErr *TaskExitError
+		if !errors.As(err, &exitErr) || attempt == attempts {
+			if attempt > 1 {
+				return fmt.Errorf("task '%s' failed after %d attempts: %w", task.Name, attempt, err)
+			}
+
+			return err
+		}
+
+		// An interrupt also reaches the task itself, which is why it failed; don't start it again
+		if ctx.Err() != nil {
+			return err
+		}
+
+		fmt.Printf("🔁 Attempt %d/%d of '%s' failed with exit %d, retrying in %s\n", attempt, attempts, task.Name, exitErr.Code, delay)
+		tr.logger.Debug("retrying task", logging.KeyTask, task.Name, "attempt", attempt, "exit_code", exitErr.Code, "delay", delay)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("retries of task '%s' cancelled: %w", task.Name, errors.Join(err, context.Cause(ctx)))
+		case <-timer.C:
+		}
+
+		delay *= 2
+	}
+}
+
+// ExitCode returns the exit code taskporter should end with after err: the highest exit code
+// among the tasks that failed, 130 after an interrupt, 1 for other failures and 0 for nil
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	worst := 0
+
+	var walk func(err error)
+
+	walk = func(err error) {
+		var exitErr *TaskExitError
+
+		switch {
+		case errors.As(err, &exitErr) && exitErr.Code > 0:
+			worst = max(worst, exitErr.Code)
+		case errors.Is(err, context.Canceled):
+			worst = max(worst, 130)
+		}
+
+		// Joined errors carry every failed task
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, inner := range joined.Unwrap() {
+				walk(inner)
+			}
+		} else if inner := errors.Unwrap(err); inner != nil {
+			walk(inner)
+		}
+	}
+
+	walk(err)
+
+	if worst == 0 {
+		return 1
+	}
+
+	return worst
+}
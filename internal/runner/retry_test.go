@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRunner_RunTaskWithRetries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture script needs a POSIX shell")
+	}
+
+	// countingTask appends to a file on every attempt and exits 4 until the given attempt
+	countingTask := func(t *testing.T, succeedOn int) (*config.Task, func() int) {
+		t.Helper()
+
+		counter := filepath.Join(t.TempDir(), "attempts")
+		script := `echo x >> "$0"; [ $(wc -l < "$0") -ge "$1" ] || exit 4`
+
+		task := &config.Task{
+			Name:    "flaky",
+			Command: "sh",
+			Args:    []string{"-c", script, counter, strconv.Itoa(succeedOn)},
+			Type:    config.TypeVSCodeTask,
+		}
+
+		attempts := func() int {
+			data, err := os.ReadFile(counter)
+			require.NoError(t, err)
+
+			return len(data) / 2
+		}
+
+		return task, attempts
+	}
+
+	t.Run("retries until an attempt succeeds", func(t *testing.T) {
+		task, attempts := countingTask(t, 2)
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetRetryPolicy(RetryPolicy{Retries: 2, Delay: time.Millisecond})
+
+		require.NoError(t, runner.RunTaskWithRetries(context.Background(), task))
+		require.Equal(t, 2, attempts())
+	})
+
+	t.Run("reports the last failure once retries are exhausted", func(t *testing.T) {
+		task, attempts := countingTask(t, 9)
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetRetryPolicy(RetryPolicy{Retries: 2, Delay: time.Millisecond})
+
+		err := runner.RunTaskWithRetries(context.Background(), task)
+		require.ErrorContains(t, err, "task 'flaky' failed after 3 attempts")
+		require.Equal(t, 4, ExitCode(err))
+		require.Equal(t, 3, attempts())
+	})
+
+	t.Run("does not retry without a policy", func(t *testing.T) {
+		task, attempts := countingTask(t, 2)
+
+		err := NewTaskRunner(false, nil).RunTaskWithRetries(context.Background(), task)
+		require.Error(t, err)
+		require.Equal(t, 1, attempts())
+	})
+
+	t.Run("does not retry tasks that cannot start", func(t *testing.T) {
+		task := &config.Task{Name: "missing", Command: "taskporter-no-such-command", Type: config.TypeVSCodeTask}
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetRetryPolicy(RetryPolicy{Retries: 2, Delay: time.Hour})
+
+		err := runner.RunTaskWithRetries(context.Background(), task)
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "attempts")
+	})
+
+	t.Run("cancelling the context stops waiting for the next attempt", func(t *testing.T) {
+		task, attempts := countingTask(t, 9)
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetRetryPolicy(RetryPolicy{Retries: 2, Delay: time.Hour})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		err := runner.RunTaskWithRetries(ctx, task)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "retries of task 'flaky' cancelled")
+		require.Equal(t, 1, attempts())
+	})
+}
+
+func TestExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture commands need a POSIX shell")
+	}
+
+	exitErr := func(code int) error {
+		t.Helper()
+
+		err := exec.Command("sh", "-c", "exit "+strconv.Itoa(code)).Run()
+		require.Error(t, err)
+
+		return &TaskExitError{Task: "check", Code: code, Err: err}
+	}
+
+	t.Run("is zero without an error", func(t *testing.T) {
+		require.Equal(t, 0, ExitCode(nil))
+	})
+
+	t.Run("is one for failures without an exit code", func(t *testing.T) {
+		require.Equal(t, 1, ExitCode(errors.New("not found")))
+	})
+
+	t.Run("is the worst exit code among joined failures", func(t *testing.T) {
+		err := errors.Join(exitErr(2), errors.New("not found"), exitErr(5))
+		require.Equal(t, 5, ExitCode(err))
+	})
+
+	t.Run("is 130 after an interrupt", func(t *testing.T) {
+		require.Equal(t, 130, ExitCode(context.Canceled))
+	})
+}
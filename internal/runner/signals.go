@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+
+	"github.com/mattn/go-isatty"
+)
+
+// terminationGrace is how long a task gets to exit after a forwarded signal before it is killed
+var terminationGrace = 10 * time.Second
+
+// runCommand starts cmd and waits for it. Interrupt and termination signals taskporter gets
+// meanwhile are passed on to the task, and cancelling ctx interrupts it, so dev servers get to
+// release their ports instead of being orphaned. A task that hasn't exited terminationGrace
+// after the first signal is killed. The first signal passed on is returned, if any.
+//
+// Non-interactive tasks run in their own process group where supported, so signals reach
+// every process they start. Interactive tasks and tasks reading taskporter's terminal keep the
+// terminal's process group: it already delivers Ctrl+C to them, and a background process group
+// reading the terminal would be stopped.
+func (tr *TaskRunner) runCommand(ctx context.Context, task *config.Task, cmd *exec.Cmd) (os.Signal, error) {
+	readsTerminal := cmd.Stdin == os.Stdin && stdinIsTerminal()
+	ownGroup := !task.Interactive && !readsTerminal && startInOwnGroup(cmd)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, InterruptSignals...)
+
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var (
+		first os.Signal
+		kill  <-chan time.Time
+	)
+
+	forward := func(sig os.Signal, delivered bool) {
+		if first == nil {
+			first = sig
+			kill = time.After(terminationGrace)
+		}
+
+		// The terminal already sent Ctrl+C to tasks sharing taskporter's process group
+		if delivered && !ownGroup && sig == os.Interrupt {
+			return
+		}
+
+		tr.logger.Debug("forwarding signal to task", logging.KeyTask, task.Name, "signal", sig.String(), "process_group", ownGroup)
+
+		if err := signalTask(cmd, sig, ownGroup); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			tr.logger.Debug("failed to forward signal", logging.KeyTask, task.Name, "signal", sig.String(), "error", err)
+		}
+	}
+
+	cancelled := ctx.Done()
+
+	for {
+		select {
+		case err := <-done:
+			// Processes the task left behind would otherwise keep running, and keep ports bound
+			if first != nil && ownGroup {
+				_ = killTask(cmd, ownGroup)
+			}
+
+			return first, err
+		case sig := <-signals:
+			forward(sig, true)
+		case <-cancelled:
+			cancelled = nil
+
+			forward(os.Interrupt, false)
+		case <-kill:
+			kill = nil
+
+			tr.logger.Warn("task did not exit after being signalled, killing it", logging.KeyTask, task.Name, "grace", terminationGrace)
+
+			if err := killTask(cmd, ownGroup); err != nil && !errors.Is(err, os.ErrProcessDone) {
+				tr.logger.Debug("failed to kill task", logging.KeyTask, task.Name, "error", err)
+			}
+		}
+	}
+}
+
+// interruptedError reports a task that stopped after taskporter passed a signal on to it.
+// It matches context.Canceled, so the run ends with exit code 130.
+func interruptedError(task *config.Task, sig os.Signal, err error) error {
+	return fmt.Errorf("task '%s' was stopped by %s: %w", task.Name, signalName(sig), errors.Join(err, context.Canceled))
+}
+
+// signalName names a signal for messages, e.g. "interrupt (Ctrl+C)"
+func signalName(sig os.Signal) string {
+	if sig == os.Interrupt {
+		return "interrupt (Ctrl+C)"
+	}
+
+	return sig.String()
+}
+
+// stdinIsTerminal reports whether taskporter's standard input is a terminal. Other character
+// devices such as /dev/null, which CI runners often attach, don't count.
+func stdinIsTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
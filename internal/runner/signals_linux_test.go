@@ -0,0 +1,68 @@
+//go:build linux
+
+package runner
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRunner_TerminalStdin(t *testing.T) {
+	t.Run("should let a task read taskporter's terminal", func(t *testing.T) {
+		master, terminal := openPty(t)
+
+		stdin := os.Stdin
+		os.Stdin = terminal
+
+		t.Cleanup(func() { os.Stdin = stdin })
+
+		_, err := master.WriteString("hello\n")
+		require.NoError(t, err)
+
+		var stdout bytes.Buffer
+
+		runner := NewTaskRunner(false, nil)
+		runner.SetOutput(&stdout, &stdout)
+
+		require.NoError(t, runner.RunTask(&config.Task{Name: "ask", Command: "sh", Args: []string{"-c", `read -r x; echo "got:[$x]"`}, Type: config.TypeVSCodeTask}))
+		require.Equal(t, "got:[hello]\n", stdout.String())
+	})
+}
+
+// openPty opens a pseudo-terminal, returning its controlling side and the terminal end a
+// process reads from
+func openPty(t *testing.T) (*os.File, *os.File) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminals: %v", err)
+	}
+
+	t.Cleanup(func() { _ = master.Close() })
+
+	var unlock int32
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock)))
+	require.Zero(t, errno)
+
+	var number uint32
+
+	_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&number)))
+	require.Zero(t, errno)
+
+	terminal, err := os.OpenFile("/dev/pts/"+strconv.Itoa(int(number)), os.O_RDWR|syscall.O_NOCTTY, 0)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = terminal.Close() })
+
+	return master, terminal
+}
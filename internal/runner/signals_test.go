@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRunner_RunTaskContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture tasks need sleep and a POSIX shell")
+	}
+
+	runCancelled := func(t *testing.T, task *config.Task) (error, time.Duration) {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		timer := time.AfterFunc(200*time.Millisecond, cancel)
+
+		t.Cleanup(func() {
+			timer.Stop()
+			cancel()
+		})
+
+		started := time.Now()
+		err := NewTaskRunner(false, nil).RunTaskContext(ctx, task)
+
+		return err, time.Since(started)
+	}
+
+	t.Run("should interrupt a running task when the context is cancelled", func(t *testing.T) {
+		err, elapsed := runCancelled(t, &config.Task{Name: "serve", Command: "sleep", Args: []string{"30"}, Type: config.TypeVSCodeTask})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorContains(t, err, "task 'serve' was stopped by interrupt (Ctrl+C)")
+		require.Equal(t, 130, ExitCode(err))
+		require.Less(t, elapsed, 5*time.Second)
+	})
+
+	t.Run("should kill a task that ignores the interrupt after the grace period", func(t *testing.T) {
+		grace := terminationGrace
+		terminationGrace = 300 * time.Millisecond
+
+		t.Cleanup(func() { terminationGrace = grace })
+
+		task := &config.Task{Name: "stubborn", Command: "sh", Args: []string{"-c", `trap "" INT; sleep 30`}, Type: config.TypeVSCodeTask}
+
+		err, elapsed := runCancelled(t, task)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, elapsed, 5*time.Second)
+	})
+
+	t.Run("should leave tasks that finish alone", func(t *testing.T) {
+		err := NewTaskRunner(false, nil).RunTaskContext(context.Background(), &config.Task{Name: "quick", Command: "true", Type: config.TypeVSCodeTask})
+		require.NoError(t, err)
+	})
+}
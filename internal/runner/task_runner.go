@@ -1,59 +1,151 @@
 package runner
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/matcher"
 	"github.com/syndbg/taskporter/internal/security"
+	"runtime"
 )
 
 // TaskRunner handles execution of tasks
 type TaskRunner struct {
 	verbose      bool
 	paranoidMode bool
+	forceCapture bool
+	quietSuccess bool
+	problems     bool
+	projectRoot  string
+	remote       string
+	allowedHosts []string
+	container    string
+	engine       string
+	isolateEnv   bool
+	cleanEnv     bool
+	keepEnv      []string
+	createCwd    bool
+	retry        RetryPolicy
+	expectedExit ExitCodes
+	stdout       io.Writer
+	stderr       io.Writer
 	sanitizer    *security.Sanitizer
+	logger       *slog.Logger
 }
 
 // NewTaskRunner creates a new task runner
-func NewTaskRunner(verbose bool) *TaskRunner {
+func NewTaskRunner(verbose bool, logger *slog.Logger) *TaskRunner {
 	return &TaskRunner{
 		verbose:      verbose,
-		paranoidMode: false,                      // Default: trust user configurations
+		paranoidMode: false, // Default: trust user configurations
+		projectRoot:  ".",
 		sanitizer:    security.NewSanitizer("."), // Will be updated with proper project root
+		logger:       runnerLogger(logger),
 	}
 }
 
 // NewTaskRunnerWithProjectRoot creates a new task runner with a specific project root
-func NewTaskRunnerWithProjectRoot(verbose bool, projectRoot string) *TaskRunner {
+func NewTaskRunnerWithProjectRoot(verbose bool, projectRoot string, logger *slog.Logger) *TaskRunner {
 	return &TaskRunner{
 		verbose:      verbose,
 		paranoidMode: false, // Default: trust user configurations
+		projectRoot:  projectRoot,
 		sanitizer:    security.NewSanitizer(projectRoot),
+		logger:       runnerLogger(logger),
 	}
 }
 
 // NewTaskRunnerWithOptions creates a new task runner with all options
-func NewTaskRunnerWithOptions(verbose bool, projectRoot string, paranoidMode bool) *TaskRunner {
+func NewTaskRunnerWithOptions(verbose bool, projectRoot string, paranoidMode bool, logger *slog.Logger) *TaskRunner {
 	return &TaskRunner{
 		verbose:      verbose,
 		paranoidMode: paranoidMode,
+		projectRoot:  projectRoot,
 		sanitizer:    security.NewSanitizer(projectRoot),
+		logger:       runnerLogger(logger),
 	}
 }
 
-// RunTask executes a given task with proper environment and working directory setup
+// runnerLogger tags diagnostics with the runner component
+func runnerLogger(logger *slog.Logger) *slog.Logger {
+	return logging.OrDiscard(logger).With(logging.KeyComponent, "runner")
+}
+
+// SetOutput redirects task output (e.g. for capture or prefixing) instead of using the terminal.
+// Interactive tasks keep the raw terminal unless force capture is enabled.
+func (tr *TaskRunner) SetOutput(stdout, stderr io.Writer) {
+	tr.stdout = stdout
+	tr.stderr = stderr
+}
+
+// SetForceCapture applies output redirection even to interactive tasks
+func (tr *TaskRunner) SetForceCapture(forceCapture bool) {
+	tr.forceCapture = forceCapture
+}
+
+// SetRespectProblemMatcher fails tasks whose output matches their problemMatcher patterns,
+// even when the process exits 0 (like linters that print errors without failing)
+func (tr *TaskRunner) SetRespectProblemMatcher(respect bool) {
+	tr.problems = respect
+}
+
+// SetExpectedExitCodes makes tasks exiting with one of codes count as successful, for checks
+// that report findings through their exit code. Exit code 0 is always successful.
+func (tr *TaskRunner) SetExpectedExitCodes(codes ExitCodes) {
+	tr.expectedExit = codes
+}
+
+// SetRemote runs tasks on host ([user@]host) over ssh instead of locally. The task's working
+// directory and environment are recreated on the remote side, so the project is expected to be
+// checked out at the same path there. In paranoid mode host must be one of allowedHosts.
+func (tr *TaskRunner) SetRemote(host string, allowedHosts []string) {
+	tr.remote = host
+	tr.allowedHosts = allowedHosts
+}
+
+// RunTask executes a given task with proper environment and working directory setup.
+// Interrupt and termination signals sent to taskporter while it runs are passed on to it.
 func (tr *TaskRunner) RunTask(task *config.Task) error {
+	return tr.RunTaskContext(context.Background(), task)
+}
+
+// RunTaskContext is RunTask that also stops the task when ctx is cancelled, as if Ctrl+C had
+// been pressed: the task is interrupted, and killed if it is still running after a grace period
+func (tr *TaskRunner) RunTaskContext(ctx context.Context, task *config.Task) error {
 	if tr.verbose {
 		fmt.Printf("🚀 Executing task: %s\n", task.Name)
 		fmt.Printf("📋 Type: %s\n", task.Type)
 		fmt.Printf("💻 Command: %s %v\n", task.Command, task.Args)
 		fmt.Printf("📁 Working directory: %s\n", task.Cwd)
 
+		if task.Shell != "" {
+			fmt.Printf("🐚 Shell: %s\n", task.Shell)
+		}
+
+		if task.RuntimePath != "" {
+			fmt.Printf("🧰 Runtime: %s\n", task.RuntimePath)
+		}
+
 		if len(task.Env) > 0 {
-			fmt.Printf("🌐 Environment variables: %v\n", task.Env)
+			fmt.Printf("🌐 Environment variables: %v\n", RedactEnv(task.Env))
+		}
+
+		if tr.remote != "" {
+			fmt.Printf("🛰️  Remote host: %s\n", tr.remote)
+		}
+
+		if tr.container != "" {
+			fmt.Printf("📦 Container image: %s\n", tr.container)
 		}
 
 		if tr.paranoidMode {
@@ -66,72 +158,603 @@ func (tr *TaskRunner) RunTask(task *config.Task) error {
 		fmt.Println()
 	}
 
-	// Security validation (only in paranoid mode)
-	if tr.paranoidMode {
-		if err := tr.validateTaskSecurity(task); err != nil {
-			return fmt.Errorf("security validation failed for task '%s': %w", task.Name, err)
+	cmd, err := tr.prepareCommand(task)
+	if err != nil {
+		return err
+	}
+
+	// Set up input/output
+	cmd.Stdin = os.Stdin
+	cmd.Stdout, cmd.Stderr = tr.outputWriters(task)
+	held := tr.holdOutput(task, cmd)
+	scanners := tr.watchProblems(task, cmd)
+
+	tr.logger.Debug("starting task",
+		logging.KeyTask, task.Name,
+		logging.KeyFile, task.Source,
+		"path", cmd.Path,
+		"args", cmd.Args[1:],
+		"cwd", cmd.Dir,
+		"remote", tr.remote,
+		"container", tr.container,
+		"paranoid", tr.paranoidMode,
+	)
+
+	err = tr.waitForTask(ctx, task, cmd, scanners)
+	tr.release(task, held, err != nil)
+
+	if err != nil {
+		return err
+	}
+
+	if tr.verbose {
+		fmt.Println()
+		fmt.Printf("✅ Task '%s' completed successfully\n", task.Name)
+		fmt.Println("📡 Strand connection maintained... delivery complete!")
+	}
+
+	return nil
+}
+
+// waitForTask runs the prepared command to completion and turns how it ended into the task's
+// error: a signal, a failure to start, an unexpected exit code or problems its output reported
+func (tr *TaskRunner) waitForTask(ctx context.Context, task *config.Task, cmd *exec.Cmd, scanners []*problemScanner) error {
+	// Execute the command
+	if sig, err := tr.runCommand(ctx, task, cmd); sig != nil {
+		tr.logger.Debug("task stopped after a signal", logging.KeyTask, task.Name, "signal", sig.String(), "error", err)
+		return interruptedError(task, sig, err)
+	} else if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			tr.logger.Debug("task failed to start", logging.KeyTask, task.Name, "error", err)
+			return fmt.Errorf("task '%s' failed: %w", task.Name, err)
+		}
+
+		code := exitErr.ExitCode()
+		if code <= 0 || !tr.expectedExit.Contains(code) {
+			tr.logger.Debug("task failed", logging.KeyTask, task.Name, "exit_code", code, "error", err)
+			return &TaskExitError{Task: task.Name, Code: code, Err: err}
 		}
 
+		tr.logger.Info("task exited with an expected code", logging.KeyTask, task.Name, "exit_code", code, "expected", tr.expectedExit.String())
+
 		if tr.verbose {
-			fmt.Printf("✅ Security validation passed\n")
+			fmt.Printf("☑️  Task '%s' exited with %d, which --expect-exit counts as success\n", task.Name, code)
 		}
+	} else {
+		tr.logger.Debug("task finished", logging.KeyTask, task.Name, "exit_code", cmd.ProcessState.ExitCode())
 	}
 
-	// Create the command with optional sanitization
-	var args []string
+	if problems := collectProblems(scanners); len(problems) > 0 {
+		tr.logger.Debug("task output matched its problem matcher", logging.KeyTask, task.Name, "problems", len(problems))
+		return fmt.Errorf("task '%s' exited successfully but reported %d problem(s) matching its problemMatcher, first: %s", task.Name, len(problems), problems[0])
+	}
 
-	var err error
+	return nil
+}
 
-	if tr.paranoidMode {
-		args, err = tr.sanitizer.SanitizeArgs(task.Args)
+// prepareCommand builds the command running task, validated in paranoid mode, with its working
+// directory and environment set up. Input and output are left to the caller.
+func (tr *TaskRunner) prepareCommand(task *config.Task) (*exec.Cmd, error) {
+	args, err := tr.commandArgs(task)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd *exec.Cmd
+
+	switch {
+	case tr.remote != "":
+		cmd, err = tr.buildRemoteCommand(task, args)
 		if err != nil {
-			return fmt.Errorf("failed to sanitize arguments for task '%s': %w", task.Name, err)
+			return nil, fmt.Errorf("failed to prepare remote execution for task '%s': %w", task.Name, err)
 		}
-	} else {
-		args = task.Args // Use original arguments as-is
-	}
+	case tr.container != "":
+		cmd, err = tr.buildContainerCommand(task, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare container execution for task '%s': %w", task.Name, err)
+		}
+	default:
+		cmd = tr.buildCommand(task, args)
 
-	cmd := exec.Command(task.Command, args...)
+		// Set working directory (with optional validation)
+		if task.Cwd != "" {
+			if tr.paranoidMode {
+				sanitizedCwd, err := tr.sanitizer.SanitizePath(task.Cwd)
+				if err != nil {
+					return nil, fmt.Errorf("failed to sanitize working directory for task '%s': %w", task.Name, err)
+				}
 
-	// Set working directory (with optional validation)
-	if task.Cwd != "" {
-		if tr.paranoidMode {
-			sanitizedCwd, err := tr.sanitizer.SanitizePath(task.Cwd)
-			if err != nil {
-				return fmt.Errorf("failed to sanitize working directory for task '%s': %w", task.Name, err)
+				cmd.Dir = sanitizedCwd
+			} else {
+				cmd.Dir = task.Cwd // Use original path as-is
 			}
+		}
 
-			cmd.Dir = sanitizedCwd
-		} else {
-			cmd.Dir = task.Cwd // Use original path as-is
+		if err := tr.prepareWorkingDirectory(task, cmd.Dir); err != nil {
+			return nil, err
+		}
+
+		// Set up environment variables (with optional validation)
+		env, diff, err := tr.buildEnvironment(task)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build environment for task '%s': %w", task.Name, err)
+		}
+
+		if tr.verbose {
+			fmt.Printf("🌐 Environment strategy: %s (%d inherited, %d set by the task)\n", tr.EnvStrategy(task), diff.Inherited, len(diff.Changes()))
 		}
+
+		cmd.Env = env
+	}
+
+	return cmd, nil
+}
+
+// commandArgs returns the task's arguments, after validating the task and sanitizing them in
+// paranoid mode
+func (tr *TaskRunner) commandArgs(task *config.Task) ([]string, error) {
+	// Use original arguments as-is in trust mode
+	if !tr.paranoidMode {
+		return task.Args, nil
 	}
 
-	// Set up environment variables (with optional validation)
-	env, err := tr.buildEnvironment(task.Env)
+	if err := tr.validateTaskSecurity(task); err != nil {
+		return nil, fmt.Errorf("security validation failed for task '%s': %w", task.Name, err)
+	}
+
+	if tr.verbose {
+		fmt.Printf("✅ Security validation passed\n")
+	}
+
+	args, err := tr.sanitizer.SanitizeArgs(task.Args)
 	if err != nil {
-		return fmt.Errorf("failed to build environment for task '%s': %w", task.Name, err)
+		return nil, fmt.Errorf("failed to sanitize arguments for task '%s': %w", task.Name, err)
 	}
 
-	cmd.Env = env
+	return args, nil
+}
 
-	// Set up input/output
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// outputWriters picks where task output goes, passing the raw terminal through for interactive tasks
+func (tr *TaskRunner) outputWriters(task *config.Task) (io.Writer, io.Writer) {
+	if tr.stdout == nil && tr.stderr == nil {
+		return os.Stdout, os.Stderr
+	}
 
-	// Execute the command
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("task '%s' failed: %w", task.Name, err)
+	if task.Interactive && !tr.forceCapture {
+		tr.logger.Debug("interactive task bypasses output capture", logging.KeyTask, task.Name)
+
+		if tr.verbose {
+			fmt.Printf("🖥️  Interactive task: passing the terminal through without output capture (use --force-capture to override)\n")
+		}
+
+		return os.Stdout, os.Stderr
 	}
 
-	if tr.verbose {
-		fmt.Println()
-		fmt.Printf("✅ Task '%s' completed successfully\n", task.Name)
-		fmt.Println("📡 Strand connection maintained... delivery complete!")
+	stdout, stderr := tr.stdout, tr.stderr
+	if stdout == nil {
+		stdout = os.Stdout
 	}
 
-	return nil
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	return stdout, stderr
+}
+
+// watchProblems wraps the command's output in problem scanners when the task has problemMatcher
+// patterns and problem matching is enabled. Interactive tasks keep the raw terminal and are not scanned.
+func (tr *TaskRunner) watchProblems(task *config.Task, cmd *exec.Cmd) []*problemScanner {
+	if !tr.problems || len(task.ProblemPatterns) == 0 {
+		return nil
+	}
+
+	if task.Interactive && !tr.forceCapture {
+		tr.logger.Debug("interactive task output is not scanned for problems", logging.KeyTask, task.Name)
+		return nil
+	}
+
+	patterns := compileProblemPatterns(task, tr.logger)
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	stdout := newProblemScanner(cmd.Stdout, patterns)
+
+	// A shared writer keeps a single scanner, so both streams are still written from one goroutine
+	if cmd.Stdout == cmd.Stderr {
+		cmd.Stdout, cmd.Stderr = stdout, stdout
+
+		return []*problemScanner{stdout}
+	}
+
+	stderr := newProblemScanner(cmd.Stderr, patterns)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	return []*problemScanner{stdout, stderr}
+}
+
+// collectProblems flushes the scanners and returns every problem line they matched
+func collectProblems(scanners []*problemScanner) []string {
+	var problems []string
+
+	for _, scanner := range scanners {
+		scanner.Flush()
+		problems = append(problems, scanner.problems...)
+	}
+
+	return problems
+}
+
+// buildCommand creates the command, wrapping it in the task's shell when one is configured.
+// A shell task without one still needs a shell to run its command line, so it gets the
+// platform's default.
+func (tr *TaskRunner) buildCommand(task *config.Task, args []string) *exec.Cmd {
+	if task.Shell == "" && task.Execution == config.ExecutionShell {
+		withShell := *task
+		withShell.Shell = config.DefaultShell(runtime.GOOS)
+		task = &withShell
+	}
+
+	if task.Shell == "" {
+		return exec.Command(tr.executable(task), args...)
+	}
+
+	return exec.Command(task.Shell, shellInvocationArgs(task.Shell, shellCommandLine(task, args))...)
+}
+
+// executable returns the runtime the IDE configuration pins when it exists on this machine.
+// Configurations are often shared, so a missing runtime falls back to the command from PATH.
+func (tr *TaskRunner) executable(task *config.Task) string {
+	if task.RuntimePath == "" {
+		return task.Command
+	}
+
+	if info, err := os.Stat(task.RuntimePath); err == nil && !info.IsDir() {
+		return task.RuntimePath
+	}
+
+	tr.logger.Warn("configured runtime not found, using command from PATH",
+		logging.KeyTask, task.Name, "runtime", task.RuntimePath, "command", task.Command)
+
+	return task.Command
+}
+
+// buildRemoteCommand wraps the task in an ssh invocation that changes into its working directory
+// and exports its environment on the remote host before running it
+func (tr *TaskRunner) buildRemoteCommand(task *config.Task, args []string) (*exec.Cmd, error) {
+	// A leading dash would be read by ssh as an option rather than a destination
+	if strings.HasPrefix(tr.remote, "-") {
+		return nil, fmt.Errorf("invalid remote host: %s", tr.remote)
+	}
+
+	if tr.paranoidMode {
+		if err := tr.sanitizer.ValidateRemoteHost(tr.remote, tr.allowedHosts); err != nil {
+			return nil, err
+		}
+	}
+
+	cwd, env, err := tr.taskCwdAndEnv(task)
+	if err != nil {
+		return nil, err
+	}
+
+	var sshArgs []string
+
+	// Interactive tasks need a remote terminal
+	if task.Interactive {
+		sshArgs = append(sshArgs, "-t")
+	}
+
+	sshArgs = append(sshArgs, "--", tr.remote, remoteCommandLine(task, args, cwd, env))
+
+	// ssh itself keeps the local environment (e.g. SSH_AUTH_SOCK); the task env is exported remotely
+	cmd := exec.Command("ssh", sshArgs...)
+	cmd.Env = os.Environ()
+
+	return cmd, nil
+}
+
+// taskCwdAndEnv returns the task's working directory and its own environment variables
+// (without the inherited environment), sanitized in paranoid mode, for runs outside this process
+func (tr *TaskRunner) taskCwdAndEnv(task *config.Task) (string, map[string]string, error) {
+	if !tr.paranoidMode {
+		return task.Cwd, task.Env, nil
+	}
+
+	cwd := task.Cwd
+
+	if cwd != "" {
+		sanitizedCwd, err := tr.sanitizer.SanitizePath(cwd)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to sanitize working directory: %w", err)
+		}
+
+		cwd = sanitizedCwd
+	}
+
+	env, err := tr.sanitizeTaskEnv(task.Env)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sanitize environment variables: %w", err)
+	}
+
+	return cwd, env, nil
+}
+
+// remoteCommandLine builds the POSIX shell command the remote login shell runs for a task
+func remoteCommandLine(task *config.Task, args []string, cwd string, env map[string]string) string {
+	var steps []string
+
+	if cwd != "" {
+		steps = append(steps, "cd "+shellQuote("", cwd))
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		steps = append(steps, fmt.Sprintf("export %s=%s", key, shellQuote("", env[key])))
+	}
+
+	// Shell tasks pass their command line through verbatim, like buildCommand does locally;
+	// only a single program can replace the remote shell
+	command := "exec " + shellQuote("", task.Command)
+	if task.Shell != "" {
+		command = task.Command
+	}
+
+	for i, arg := range args {
+		// Quoting styles only apply to shell tasks; other args must arrive verbatim
+		quoting := ""
+		if task.Shell != "" {
+			quoting = argQuoting(task, i)
+		}
+
+		command += " " + quoteArg("", arg, quoting)
+	}
+
+	return strings.Join(append(steps, command), " && ")
+}
+
+// posixSpecialChars are the characters a POSIX shell interprets in an unquoted word
+const posixSpecialChars = " \t\n\"'\\$`&|;<>()*?[]{}#~!"
+
+// CommandIsPath reports whether the task's command names an existing file by absolute path, as
+// when a shell task's command is "${workspaceFolder}/scripts/build.sh". Such a command is
+// a single word even if the path contains spaces, so it must be quoted inside command lines.
+func CommandIsPath(task *config.Task) bool {
+	if !filepath.IsAbs(task.Command) {
+		return false
+	}
+
+	info, err := os.Stat(task.Command)
+
+	return err == nil && !info.IsDir()
+}
+
+// shellName returns the lowercase base name of a shell, without an .exe suffix
+func shellName(shell string) string {
+	// Settings may name Windows shells by path, so strip both separator styles
+	name := shell[strings.LastIndexAny(shell, `/\`)+1:]
+
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
+
+// shellInvocationArgs returns the flags needed to make a shell execute a command line
+func shellInvocationArgs(shell, commandLine string) []string {
+	switch shellName(shell) {
+	case "cmd":
+		return []string{"/d", "/c", commandLine}
+	case "powershell", "pwsh":
+		return []string{"-NoProfile", "-Command", commandLine}
+	default:
+		return []string{"-c", commandLine}
+	}
+}
+
+// shellCommandLine builds the command line a shell task runs. Like VSCode, the command is
+// passed verbatim and only args are quoted, except that a command naming a script by path is
+// quoted as one word, so project roots with spaces or parentheses survive the shell.
+func shellCommandLine(task *config.Task, args []string) string {
+	commandLine := task.Command
+
+	if CommandIsPath(task) {
+		commandLine = shellQuote(task.Shell, task.Command)
+
+		// PowerShell treats a quoted string as a value; the call operator runs it
+		if name := shellName(task.Shell); commandLine != task.Command && (name == "powershell" || name == "pwsh") {
+			commandLine = "& " + commandLine
+		}
+	}
+
+	for i, arg := range args {
+		commandLine += " " + quoteArg(task.Shell, arg, argQuoting(task, i))
+	}
+
+	return commandLine
+}
+
+// argQuoting returns the VSCode quoting style of the task's i-th arg, empty for the default
+func argQuoting(task *config.Task, i int) string {
+	if i < len(task.ArgQuoting) {
+		return task.ArgQuoting[i]
+	}
+
+	return ""
+}
+
+// quoteArg quotes an arg for shell in a VSCode quoting style. The default style quotes only
+// args the shell would interpret.
+func quoteArg(shell, word, quoting string) string {
+	switch quoting {
+	case config.QuotingStrong:
+		return strongQuote(shell, word)
+	case config.QuotingWeak:
+		return weakQuote(shell, word)
+	case config.QuotingEscape:
+		return escapeWord(shell, word)
+	default:
+		return shellQuote(shell, word)
+	}
+}
+
+// shellQuote quotes a word for shell when it contains anything the shell would interpret. An
+// empty shell means a POSIX one.
+func shellQuote(shell, word string) string {
+	if word != "" && !strings.ContainsAny(word, shellSpecialChars(shell)) {
+		return word
+	}
+
+	return strongQuote(shell, word)
+}
+
+// shellSpecialChars returns the characters shell interprets in an unquoted word
+func shellSpecialChars(shell string) string {
+	switch shellName(shell) {
+	case "cmd":
+		return " \t\"&|<>()^%!,;="
+	case "powershell", "pwsh":
+		return " \t\n\"'$`&|;<>(){}@#,"
+	default:
+		return posixSpecialChars
+	}
+}
+
+// strongQuote quotes a word so shell interprets nothing in it
+func strongQuote(shell, word string) string {
+	switch shellName(shell) {
+	case "cmd":
+		return `"` + strings.ReplaceAll(word, `"`, `\"`) + `"`
+	case "powershell", "pwsh":
+		return "'" + strings.ReplaceAll(word, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+	}
+}
+
+// weakQuote quotes a word in double quotes, so shell still expands variables in it
+func weakQuote(shell, word string) string {
+	switch shellName(shell) {
+	case "cmd":
+		return `"` + strings.ReplaceAll(word, `"`, `\"`) + `"`
+	case "powershell", "pwsh":
+		return `"` + strings.ReplaceAll(word, `"`, "`\"") + `"`
+	default:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(word) + `"`
+	}
+}
+
+// escapeWord escapes each character shell would interpret with the shell's escape character.
+// Words that cannot be escaped that way (empty, or with a newline) are quoted instead.
+func escapeWord(shell, word string) string {
+	if word == "" || strings.Contains(word, "\n") {
+		return strongQuote(shell, word)
+	}
+
+	escapeChar := `\`
+
+	switch shellName(shell) {
+	case "cmd":
+		escapeChar = "^"
+	case "powershell", "pwsh":
+		escapeChar = "`"
+	}
+
+	special := shellSpecialChars(shell)
+
+	var escaped strings.Builder
+
+	for _, r := range word {
+		if strings.ContainsRune(special, r) {
+			escaped.WriteString(escapeChar)
+		}
+
+		escaped.WriteRune(r)
+	}
+
+	return escaped.String()
+}
+
+// shellJoin joins arguments into a POSIX command line, quoting those the shell would interpret
+func shellJoin(parts []string) string {
+	quoted := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		quoted = append(quoted, shellQuote("", part))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// TaskCommandLine returns the full command line running a task executes, its shell
+// invocation included, quoted so it can be read (or pasted) as one line
+func TaskCommandLine(task *config.Task) string {
+	if task.IsAggregate() {
+		return ""
+	}
+
+	if task.Shell != "" {
+		return shellJoin(append([]string{task.Shell}, shellInvocationArgs(task.Shell, shellCommandLine(task, task.Args))...))
+	}
+
+	return shellJoin(append([]string{task.Command}, task.Args...))
+}
+
+// TaskSummary describes what running a task will do - command, args, shell, working
+// directory and environment - one "label: value" line each, for confirmation prompts.
+// Secret environment values are redacted.
+func TaskSummary(task *config.Task) []string {
+	lines := TaskCommandSummary(task)
+
+	if len(task.Env) == 0 && len(task.UnsetEnv) == 0 {
+		return append(lines, "Env: (inherited)")
+	}
+
+	keys := make([]string, 0, len(task.Env))
+	for key := range task.Env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("Env: %s=%s", key, DisplayEnvValue(key, task.Env[key])))
+	}
+
+	for _, key := range task.UnsetEnv {
+		lines = append(lines, fmt.Sprintf("Env: %s (unset)", key))
+	}
+
+	return lines
+}
+
+// TaskCommandSummary is TaskSummary without the environment lines
+func TaskCommandSummary(task *config.Task) []string {
+	lines := []string{"Command: " + task.Command}
+
+	if len(task.Args) > 0 {
+		lines = append(lines, "Args: "+shellJoin(task.Args))
+	}
+
+	if task.Shell != "" {
+		lines = append(lines, "Shell: "+task.Shell)
+	}
+
+	if task.RuntimePath != "" {
+		lines = append(lines, "Runtime: "+task.RuntimePath)
+	}
+
+	cwd := task.Cwd
+	if cwd == "" {
+		cwd = "(current directory)"
+	}
+
+	return append(lines, "Cwd: "+cwd)
 }
 
 // validateTaskSecurity performs comprehensive security validation on a task (paranoid mode only)
@@ -159,52 +782,181 @@ func (tr *TaskRunner) validateTaskSecurity(task *config.Task) error {
 	}
 
 	// Validate environment variables
-	if _, err := tr.sanitizer.SanitizeEnvironment(task.Env); err != nil {
+	if _, err := tr.sanitizeTaskEnv(task.Env); err != nil {
 		return fmt.Errorf("invalid environment variables: %w", err)
 	}
 
 	return nil
 }
 
-// buildEnvironment creates the environment for task execution with optional security validation
-func (tr *TaskRunner) buildEnvironment(taskEnv map[string]string) ([]string, error) {
-	// Start with current environment
-	env := os.Environ()
+// buildEnvironment returns the task's KEY=VALUE environment, validated in paranoid mode: the base
+// environment without the unset variables, with the task's variables applied on top, along with
+// a diff of what the task adds to, overrides in or removes from the inherited environment
+func (tr *TaskRunner) buildEnvironment(task *config.Task) ([]string, *EnvDiff, error) {
+	taskEnv := task.Env
 
-	// Handle task-specific environment variables
-	if len(taskEnv) > 0 {
-		if tr.paranoidMode {
-			// Validate and sanitize in paranoid mode
-			sanitizedEnv, err := tr.sanitizer.SanitizeEnvironment(taskEnv)
-			if err != nil {
-				return nil, fmt.Errorf("failed to sanitize environment variables: %w", err)
-			}
+	// Start with the base environment of the strategy in effect
+	inherited, removed := removeEnvKeys(tr.baseEnvironment(tr.EnvStrategy(task)), task.UnsetEnv)
 
-			// Add sanitized task-specific environment variables
-			for key, value := range sanitizedEnv {
-				env = append(env, fmt.Sprintf("%s=%s", key, value))
-			}
-		} else {
-			// Use original environment variables as-is (trust mode)
-			for key, value := range taskEnv {
-				env = append(env, fmt.Sprintf("%s=%s", key, value))
-			}
+	// Validate and sanitize in paranoid mode, use original variables as-is in trust mode
+	if tr.paranoidMode && len(taskEnv) > 0 {
+		sanitizedEnv, err := tr.sanitizeTaskEnv(taskEnv)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to sanitize environment variables: %w", err)
+		}
+
+		taskEnv = sanitizedEnv
+	}
+
+	diff := diffEnvironment(inherited, taskEnv)
+	diff.Removed = removed
+	diff.Inherited = len(inherited)
+
+	// Task-specific variables come last so they win over inherited ones
+	env := inherited
+	for _, change := range diff.Changes() {
+		env = append(env, fmt.Sprintf("%s=%s", change.Key, change.Value))
+	}
+
+	return env, diff, nil
+}
+
+// PreviewEnvironment reports which variables the task would add or override without running it.
+// Remote and container runs start from a different environment, so every variable counts as added.
+func (tr *TaskRunner) PreviewEnvironment(task *config.Task) (*EnvDiff, error) {
+	if tr.remote != "" || tr.container != "" {
+		_, env, err := tr.taskCwdAndEnv(task)
+		if err != nil {
+			return nil, err
 		}
+
+		return diffEnvironment(nil, env), nil
+	}
+
+	_, diff, err := tr.buildEnvironment(task)
+
+	return diff, err
+}
+
+// ResolveEnvironment returns the environment the task's process would get, one KEY=VALUE per
+// variable sorted by key, with nothing redacted. Remote and container runs start from the
+// environment of the other side, so only the task's own variables are listed for them.
+func (tr *TaskRunner) ResolveEnvironment(task *config.Task) ([]string, error) {
+	var env []string
+
+	if tr.remote != "" || tr.container != "" {
+		_, taskEnv, err := tr.taskCwdAndEnv(task)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range taskEnv {
+			env = append(env, key+"="+value)
+		}
+	} else {
+		built, _, err := tr.buildEnvironment(task)
+		if err != nil {
+			return nil, err
+		}
+
+		env = built
+	}
+
+	return effectiveEnv(env), nil
+}
+
+// effectiveEnv drops variables a later entry overrides, like the process would see them, and
+// sorts the rest by key
+func effectiveEnv(env []string) []string {
+	values := make(map[string]string, len(env))
+
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		values[key] = value
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	resolved := make([]string, 0, len(keys))
+	for _, key := range keys {
+		resolved = append(resolved, key+"="+values[key])
+	}
+
+	return resolved
+}
+
+// maxSuggestions is the number of closest task names offered when a task is not found
+const maxSuggestions = 3
+
+// TaskNotFoundError is returned by FindTask when no task matches by name. Suggestions holds
+// up to three of the closest task names, with SuggestedTasks the tasks they name, and Match
+// is set when one is close enough to offer directly. The match is never used implicitly so
+// callers can confirm it.
+type TaskNotFoundError struct {
+	Query          string
+	Suggestions    []string
+	SuggestedTasks []*config.Task
+	Match          *config.Task
+}
+
+// AmbiguousMatchError is returned by FindTask when a query is a prefix or part of several
+// task names. Matches holds every candidate so callers can let the user pick one.
+type AmbiguousMatchError struct {
+	Query   string
+	Matches []*config.Task
+}
+
+// Error implements the error interface
+func (e *AmbiguousMatchError) Error() string {
+	names := make([]string, 0, len(e.Matches))
+	for _, match := range e.Matches {
+		names = append(names, match.Name)
 	}
 
-	return env, nil
+	return fmt.Sprintf("multiple tasks match '%s': %s", e.Query, strings.Join(names, ", "))
+}
+
+// Error implements the error interface
+func (e *TaskNotFoundError) Error() string {
+	msg := fmt.Sprintf("task '%s' not found", e.Query)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(". Did you mean: %s?", strings.Join(e.Suggestions, ", "))
+	}
+
+	return msg
 }
 
 // TaskFinder helps find tasks by name from a list
-type TaskFinder struct{}
+type TaskFinder struct {
+	aliases []config.Alias
+}
 
 // NewTaskFinder creates a new task finder
 func NewTaskFinder() *TaskFinder {
 	return &TaskFinder{}
 }
 
-// FindTask searches for a task by name in the given list
+// SetAliases sets the user-defined short names FindTask resolves before matching task names
+func (tf *TaskFinder) SetAliases(aliases []config.Alias) {
+	tf.aliases = aliases
+}
+
+// FindTask searches for a task by name in the given list. An alias is resolved first, unless
+// a task has the alias's name, in which case the real task wins.
 func (tf *TaskFinder) FindTask(taskName string, tasks []*config.Task) (*config.Task, error) {
+	if alias := tf.findAlias(taskName, tasks); alias != nil {
+		if task := config.FindTaskByName(tasks, alias.Target); task != nil {
+			return task, nil
+		}
+
+		return nil, fmt.Errorf("alias '%s' refers to unknown task '%s'", alias.Name, alias.Target)
+	}
+
 	// Exact match first
 	for _, task := range tasks {
 		if task.Name == taskName {
@@ -221,6 +973,23 @@ func (tf *TaskFinder) FindTask(taskName string, tasks []*config.Task) (*config.T
 		}
 	}
 
+	// Prefix match (if unique), like git's subcommand abbreviations
+	var prefixMatches []*config.Task
+
+	for _, task := range tasks {
+		if strings.HasPrefix(strings.ToLower(task.Name), taskNameLower) {
+			prefixMatches = append(prefixMatches, task)
+		}
+	}
+
+	if len(prefixMatches) == 1 {
+		return prefixMatches[0], nil
+	}
+
+	if len(prefixMatches) > 1 {
+		return nil, &AmbiguousMatchError{Query: taskName, Matches: prefixMatches}
+	}
+
 	// Partial match (if unique)
 	var matches []*config.Task
 
@@ -235,13 +1004,96 @@ func (tf *TaskFinder) FindTask(taskName string, tasks []*config.Task) (*config.T
 	}
 
 	if len(matches) > 1 {
-		var names []string
-		for _, match := range matches {
-			names = append(names, match.Name)
+		return nil, &AmbiguousMatchError{Query: taskName, Matches: matches}
+	}
+
+	// Fuzzy match as a last resort, consistent with the interactive selector
+	suggested := tf.suggestTasks(taskName, tasks)
+
+	suggestions := make([]string, 0, len(suggested))
+	for _, task := range suggested {
+		suggestions = append(suggestions, task.Name)
+	}
+
+	return nil, &TaskNotFoundError{
+		Query:          taskName,
+		Suggestions:    suggestions,
+		SuggestedTasks: suggested,
+		Match:          tf.bestFuzzyMatch(taskName, tasks),
+	}
+}
+
+// findAlias returns the alias named taskName (case-insensitive), or nil if there is none
+// or a task has that name
+func (tf *TaskFinder) findAlias(taskName string, tasks []*config.Task) *config.Alias {
+	for _, task := range tasks {
+		if strings.EqualFold(task.Name, taskName) {
+			return nil
+		}
+	}
+
+	for i := range tf.aliases {
+		if strings.EqualFold(tf.aliases[i].Name, taskName) {
+			return &tf.aliases[i]
+		}
+	}
+
+	return nil
+}
+
+// suggestTasks returns up to three tasks whose names are closest to the query by edit distance
+func (tf *TaskFinder) suggestTasks(taskName string, tasks []*config.Task) []*config.Task {
+	type candidate struct {
+		task     *config.Task
+		distance int
+	}
+
+	queryLower := strings.ToLower(taskName)
+
+	var candidates []candidate
+
+	for _, task := range tasks {
+		nameLower := strings.ToLower(task.Name)
+		distance := matcher.LevenshteinDistance(queryLower, nameLower)
+
+		// Skip names that share too little with the query to be a plausible typo
+		if float64(distance) > 0.6*float64(max(len(queryLower), len(nameLower))) {
+			continue
 		}
 
-		return nil, fmt.Errorf("multiple tasks match '%s': %s", taskName, strings.Join(names, ", "))
+		candidates = append(candidates, candidate{task: task, distance: distance})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var suggestions []*config.Task
+
+	for _, c := range candidates {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+
+		suggestions = append(suggestions, c.task)
+	}
+
+	return suggestions
+}
+
+// bestFuzzyMatch returns the task with the highest relevance score, or nil if none is close
+func (tf *TaskFinder) bestFuzzyMatch(taskName string, tasks []*config.Task) *config.Task {
+	var (
+		best      *config.Task
+		bestScore float64
+	)
+
+	for _, task := range tasks {
+		if score := matcher.RelevanceScore(taskName, task.Name); score > bestScore {
+			best = task
+			bestScore = score
+		}
 	}
 
-	return nil, fmt.Errorf("task '%s' not found", taskName)
+	return best
 }
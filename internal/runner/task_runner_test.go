@@ -1,22 +1,29 @@
 package runner
 
 import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+	"github.com/syndbg/taskporter/internal/parser/vscode"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestTaskRunner(t *testing.T) {
 	t.Run("NewTaskRunner", func(t *testing.T) {
-		runner := NewTaskRunner(false)
+		runner := NewTaskRunner(false, nil)
 		require.NotNil(t, runner)
 		require.False(t, runner.verbose)
 	})
 
 	t.Run("NewTaskRunnerWithOptions", func(t *testing.T) {
-		runner := NewTaskRunnerWithOptions(true, "/test/project", true)
+		runner := NewTaskRunnerWithOptions(true, "/test/project", true, nil)
 		require.NotNil(t, runner)
 		require.True(t, runner.verbose)
 		require.True(t, runner.paranoidMode)
@@ -25,7 +32,7 @@ func TestTaskRunner(t *testing.T) {
 
 	t.Run("buildEnvironment", func(t *testing.T) {
 		t.Run("trust mode", func(t *testing.T) {
-			runner := NewTaskRunner(false) // paranoidMode = false by default
+			runner := NewTaskRunner(false, nil) // paranoidMode = false by default
 
 			t.Run("with task environment variables", func(t *testing.T) {
 				taskEnv := map[string]string{
@@ -34,7 +41,7 @@ func TestTaskRunner(t *testing.T) {
 					"PATH":     "/custom/bin:$PATH", // Should be allowed in trust mode
 				}
 
-				env, err := runner.buildEnvironment(taskEnv)
+				env, _, err := runner.buildEnvironment(&config.Task{Env: taskEnv})
 				require.NoError(t, err)
 				require.NotEmpty(t, env)
 
@@ -61,8 +68,70 @@ func TestTaskRunner(t *testing.T) {
 			})
 		})
 
+		t.Run("reports added and overridden variables", func(t *testing.T) {
+			t.Setenv("TASKPORTER_TEST_MODE", "local")
+			t.Setenv("TASKPORTER_TEST_SAME", "same")
+
+			runner := NewTaskRunner(false, nil)
+
+			env, diff, err := runner.buildEnvironment(&config.Task{Env: map[string]string{
+				"TASKPORTER_TEST_MODE": "ci",
+				"TASKPORTER_TEST_SAME": "same",
+				"TASKPORTER_TEST_NEW":  "1",
+			}})
+			require.NoError(t, err)
+
+			require.Equal(t, []EnvChange{{Key: "TASKPORTER_TEST_NEW", Value: "1"}}, diff.Added)
+			require.Equal(t, []EnvChange{{Key: "TASKPORTER_TEST_MODE", Value: "ci", Previous: "local", Overridden: true}}, diff.Overridden)
+
+			// Overrides come after the inherited values so they win
+			require.Contains(t, env, "TASKPORTER_TEST_MODE=local")
+			require.Equal(t, []string{"TASKPORTER_TEST_MODE=ci", "TASKPORTER_TEST_NEW=1"}, env[len(env)-2:])
+		})
+
+		t.Run("removes unset variables instead of setting them empty", func(t *testing.T) {
+			t.Setenv("TASKPORTER_TEST_UNSET", "inherited")
+			t.Setenv("TASKPORTER_TEST_EMPTY", "inherited")
+
+			runner := NewTaskRunner(false, nil)
+
+			env, diff, err := runner.buildEnvironment(&config.Task{
+				Env:      map[string]string{"TASKPORTER_TEST_EMPTY": ""},
+				UnsetEnv: []string{"TASKPORTER_TEST_UNSET", "TASKPORTER_TEST_NEVER_SET"},
+			})
+			require.NoError(t, err)
+
+			for _, entry := range env {
+				require.NotContains(t, entry, "TASKPORTER_TEST_UNSET")
+			}
+
+			require.Equal(t, "TASKPORTER_TEST_EMPTY=", env[len(env)-1])
+			require.Equal(t, []EnvChange{{Key: "TASKPORTER_TEST_UNSET", Previous: "inherited"}}, diff.Removed)
+			require.Equal(t, len(env)-1, diff.Inherited)
+		})
+
+		t.Run("unsets variables for the task's process", func(t *testing.T) {
+			t.Setenv("TASKPORTER_TEST_UNSET", "inherited")
+
+			var stdout bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, &stdout)
+
+			task := &config.Task{
+				Name:     "unset",
+				Command:  "sh",
+				Args:     []string{"-c", `echo "${TASKPORTER_TEST_UNSET-unset}"`},
+				UnsetEnv: []string{"TASKPORTER_TEST_UNSET"},
+				Type:     config.TypeVSCodeTask,
+			}
+
+			require.NoError(t, runner.RunTask(task))
+			require.Equal(t, "unset\n", stdout.String())
+		})
+
 		t.Run("paranoid mode", func(t *testing.T) {
-			runner := NewTaskRunnerWithOptions(false, "/test/project", true)
+			runner := NewTaskRunnerWithOptions(false, "/test/project", true, nil)
 
 			t.Run("with valid environment variables", func(t *testing.T) {
 				taskEnv := map[string]string{
@@ -70,7 +139,7 @@ func TestTaskRunner(t *testing.T) {
 					"BUILD_TYPE": "release",
 				}
 
-				env, err := runner.buildEnvironment(taskEnv)
+				env, _, err := runner.buildEnvironment(&config.Task{Env: taskEnv})
 				require.NoError(t, err)
 				require.NotEmpty(t, env)
 			})
@@ -80,16 +149,132 @@ func TestTaskRunner(t *testing.T) {
 					"PATH": "/malicious/path", // Should be rejected in paranoid mode
 				}
 
-				_, err := runner.buildEnvironment(taskEnv)
+				_, _, err := runner.buildEnvironment(&config.Task{Env: taskEnv})
 				require.Error(t, err)
 				require.Contains(t, err.Error(), "PATH")
 			})
 		})
+
+		t.Run("isolated environment", func(t *testing.T) {
+			t.Setenv("TASKPORTER_TEST_KEPT", "kept")
+			t.Setenv("TASKPORTER_TEST_DROPPED", "dropped")
+			t.Setenv("HOME", "/home/tester")
+
+			t.Run("starts from the minimal and kept variables", func(t *testing.T) {
+				runner := NewTaskRunner(false, nil)
+				runner.SetIsolatedEnv([]string{"TASKPORTER_TEST_KEPT", "HOME"})
+				require.Equal(t, EnvStrategyIsolated, runner.EnvStrategy(&config.Task{}))
+
+				env, diff, err := runner.buildEnvironment(&config.Task{Env: map[string]string{"HOME": "/tmp/home", "MODE": "release"}})
+				require.NoError(t, err)
+
+				require.Contains(t, env, "TASKPORTER_TEST_KEPT=kept")
+				require.NotContains(t, env, "TASKPORTER_TEST_DROPPED=dropped")
+				require.Equal(t, len(env)-2, diff.Inherited, "HOME is kept once even though it is listed twice")
+				require.Equal(t, []string{"HOME=/tmp/home", "MODE=release"}, env[len(env)-2:])
+			})
+
+			t.Run("lets paranoid mode set kept system variables", func(t *testing.T) {
+				runner := NewTaskRunnerWithOptions(false, "/test/project", true, nil)
+				runner.SetIsolatedEnv([]string{"PATH"})
+
+				_, _, err := runner.buildEnvironment(&config.Task{Env: map[string]string{"PATH": "/opt/toolchain/bin"}})
+				require.NoError(t, err)
+
+				_, _, err = runner.buildEnvironment(&config.Task{Env: map[string]string{"LD_PRELOAD": "/tmp/hook.so"}})
+				require.Error(t, err)
+			})
+
+			t.Run("inherits everything by default", func(t *testing.T) {
+				runner := NewTaskRunner(false, nil)
+				require.Equal(t, EnvStrategyInherit, runner.EnvStrategy(&config.Task{}))
+
+				env, diff, err := runner.buildEnvironment(&config.Task{})
+				require.NoError(t, err)
+				require.Contains(t, env, "TASKPORTER_TEST_DROPPED=dropped")
+				require.Equal(t, len(os.Environ()), diff.Inherited)
+			})
+		})
+
+		t.Run("clean environment", func(t *testing.T) {
+			t.Setenv("TASKPORTER_TEST_KEPT", "kept")
+			t.Setenv("TASKPORTER_TEST_DROPPED", "dropped")
+			t.Setenv("HOME", "/home/tester")
+			t.Setenv("PATH", "/home/tester/.local/bin:/usr/bin")
+
+			t.Run("starts from a minimal PATH and the task's variables only", func(t *testing.T) {
+				if runtime.GOOS == "windows" {
+					t.Skip("Windows keeps its own PATH")
+				}
+
+				runner := NewTaskRunner(false, nil)
+				runner.SetCleanEnv(nil)
+				require.Equal(t, EnvStrategyClean, runner.EnvStrategy(&config.Task{}))
+
+				env, diff, err := runner.buildEnvironment(&config.Task{Env: map[string]string{"MODE": "release"}})
+				require.NoError(t, err)
+
+				require.Equal(t, []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin", "MODE=release"}, env)
+				require.Equal(t, 1, diff.Inherited)
+			})
+
+			t.Run("keeps the variables named with --keep-env", func(t *testing.T) {
+				runner := NewTaskRunner(false, nil)
+				runner.SetCleanEnv([]string{"TASKPORTER_TEST_KEPT", "PATH"})
+
+				env, _, err := runner.buildEnvironment(&config.Task{})
+				require.NoError(t, err)
+
+				require.Contains(t, env, "TASKPORTER_TEST_KEPT=kept")
+				require.Contains(t, env, "PATH=/home/tester/.local/bin:/usr/bin")
+				require.NotContains(t, env, "TASKPORTER_TEST_DROPPED=dropped")
+				require.NotContains(t, env, "HOME=/home/tester")
+			})
+
+			t.Run("applies to tasks that opt in with cleanEnv", func(t *testing.T) {
+				runner := NewTaskRunner(false, nil)
+				task := &config.Task{CleanEnv: true, Env: map[string]string{"MODE": "release"}}
+				require.Equal(t, EnvStrategyClean, runner.EnvStrategy(task))
+
+				env, _, err := runner.buildEnvironment(task)
+				require.NoError(t, err)
+				require.NotContains(t, env, "TASKPORTER_TEST_DROPPED=dropped")
+				require.NotContains(t, env, "HOME=/home/tester")
+				require.Contains(t, env, "MODE=release")
+
+				env, _, err = runner.buildEnvironment(&config.Task{})
+				require.NoError(t, err)
+				require.Contains(t, env, "TASKPORTER_TEST_DROPPED=dropped", "other tasks still inherit")
+			})
+
+			t.Run("excludes inherited variables from the task's process", func(t *testing.T) {
+				if runtime.GOOS == "windows" {
+					t.Skip("the task needs a POSIX shell")
+				}
+
+				var stdout bytes.Buffer
+
+				runner := NewTaskRunner(false, nil)
+				runner.SetOutput(&stdout, &stdout)
+				runner.SetCleanEnv(nil)
+
+				task := &config.Task{
+					Name:    "clean",
+					Command: "sh",
+					Args:    []string{"-c", `echo "${TASKPORTER_TEST_DROPPED-unset} ${HOME-unset} $MODE"`},
+					Env:     map[string]string{"MODE": "release"},
+					Type:    config.TypeVSCodeTask,
+				}
+
+				require.NoError(t, runner.RunTask(task))
+				require.Equal(t, "unset unset release\n", stdout.String())
+			})
+		})
 	})
 
 	t.Run("RunTask modes", func(t *testing.T) {
 		t.Run("trust mode allows shell operators", func(t *testing.T) {
-			runner := NewTaskRunner(false) // paranoidMode = false by default
+			runner := NewTaskRunner(false, nil) // paranoidMode = false by default
 			task := &config.Task{
 				Name:    "complex-build",
 				Command: "echo",
@@ -103,7 +288,7 @@ func TestTaskRunner(t *testing.T) {
 		})
 
 		t.Run("paranoid mode blocks dangerous patterns", func(t *testing.T) {
-			runner := NewTaskRunnerWithOptions(false, ".", true) // paranoidMode = true
+			runner := NewTaskRunnerWithOptions(false, ".", true, nil) // paranoidMode = true
 			task := &config.Task{
 				Name:    "malicious",
 				Command: "rm -rf /", // This should be blocked by command validation
@@ -120,7 +305,7 @@ func TestTaskRunner(t *testing.T) {
 
 	t.Run("RunTask", func(t *testing.T) {
 		t.Run("successful command execution", func(t *testing.T) {
-			runner := NewTaskRunner(false)
+			runner := NewTaskRunner(false, nil)
 			task := &config.Task{
 				Name:    "test-echo",
 				Command: "echo",
@@ -133,7 +318,7 @@ func TestTaskRunner(t *testing.T) {
 		})
 
 		t.Run("command not found", func(t *testing.T) {
-			runner := NewTaskRunner(false)
+			runner := NewTaskRunner(false, nil)
 			task := &config.Task{
 				Name:    "test-nonexistent",
 				Command: "nonexistent-command-12345",
@@ -147,7 +332,7 @@ func TestTaskRunner(t *testing.T) {
 		})
 
 		t.Run("with environment variables", func(t *testing.T) {
-			runner := NewTaskRunner(false)
+			runner := NewTaskRunner(false, nil)
 			task := &config.Task{
 				Name:    "test-env",
 				Command: "sh",
@@ -161,6 +346,390 @@ func TestTaskRunner(t *testing.T) {
 			err := runner.RunTask(task)
 			require.NoError(t, err)
 		})
+
+		t.Run("with shell", func(t *testing.T) {
+			runner := NewTaskRunner(false, nil)
+			task := &config.Task{
+				Name:    "test-shell",
+				Command: "test \"$0\" = sh &&",
+				Args:    []string{"echo", "quoted arg"},
+				Shell:   "sh",
+				Type:    config.TypeVSCodeTask,
+			}
+
+			err := runner.RunTask(task)
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("working directory", func(t *testing.T) {
+		newTask := func(cwd string) *config.Task {
+			return &config.Task{Name: "build", Command: "echo", Cwd: cwd, Type: config.TypeVSCodeTask, Source: ".vscode/tasks.json"}
+		}
+
+		t.Run("fails naming the missing directory and the task's source", func(t *testing.T) {
+			cwd := filepath.Join(t.TempDir(), "build")
+
+			err := NewTaskRunner(false, nil).RunTask(newTask(cwd))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), cwd)
+			require.Contains(t, err.Error(), ".vscode/tasks.json")
+			require.Contains(t, err.Error(), "--create-cwd")
+		})
+
+		t.Run("creates the directory with --create-cwd", func(t *testing.T) {
+			cwd := filepath.Join(t.TempDir(), "build", "out")
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetCreateCwd(true)
+
+			require.NoError(t, runner.RunTask(newTask(cwd)))
+			require.DirExists(t, cwd)
+		})
+
+		t.Run("creates the directory for tasks setting createCwd", func(t *testing.T) {
+			task := newTask(filepath.Join(t.TempDir(), "dist"))
+			task.CreateCwd = true
+
+			require.NoError(t, NewTaskRunner(false, nil).RunTask(task))
+			require.DirExists(t, task.Cwd)
+		})
+
+		t.Run("rejects a file", func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "Makefile")
+			require.NoError(t, os.WriteFile(file, nil, 0644))
+
+			err := NewTaskRunner(false, nil).RunTask(newTask(file))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "is not a directory")
+		})
+
+		t.Run("warns about JetBrains directories outside the project in trust mode", func(t *testing.T) {
+			projectRoot := t.TempDir()
+			outside := t.TempDir()
+
+			recorder, logger := logging.NewRecorder()
+			runner := NewTaskRunnerWithProjectRoot(false, projectRoot, logger)
+
+			task := newTask(outside)
+			task.Type = config.TypeJetBrains
+
+			require.NoError(t, runner.RunTask(task))
+
+			entries := recorder.Entries()
+			require.NotEmpty(t, entries)
+			require.Equal(t, slog.LevelWarn, entries[0].Level)
+			require.Equal(t, outside, entries[0].Attrs["cwd"])
+		})
+	})
+
+	t.Run("output capture", func(t *testing.T) {
+		newEchoTask := func(interactive bool) *config.Task {
+			return &config.Task{
+				Name:        "test-capture",
+				Command:     "echo",
+				Args:        []string{"captured"},
+				Interactive: interactive,
+				Type:        config.TypeVSCodeLaunch,
+			}
+		}
+
+		t.Run("non-interactive task output is captured", func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, nil)
+
+			require.NoError(t, runner.RunTask(newEchoTask(false)))
+			require.Equal(t, "captured\n", stdout.String())
+		})
+
+		t.Run("interactive task keeps the raw terminal", func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, nil)
+
+			require.NoError(t, runner.RunTask(newEchoTask(true)))
+			require.Empty(t, stdout.String())
+		})
+
+		t.Run("force capture overrides interactivity", func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, nil)
+			runner.SetForceCapture(true)
+
+			require.NoError(t, runner.RunTask(newEchoTask(true)))
+			require.Equal(t, "captured\n", stdout.String())
+		})
+	})
+
+	t.Run("configured runtime", func(t *testing.T) {
+		binDir := t.TempDir()
+		runtimePath := filepath.Join(binDir, "python-venv")
+		require.NoError(t, os.WriteFile(runtimePath, []byte("#!/bin/sh\necho \"venv $*\"\n"), 0o755))
+
+		newRuntimeTask := func(runtimePath string) *config.Task {
+			return &config.Task{
+				Name:        "script",
+				Command:     "echo",
+				Args:        []string{"main.py"},
+				RuntimePath: runtimePath,
+			}
+		}
+
+		t.Run("prefers the runtime when it exists", func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, nil)
+
+			require.NoError(t, runner.RunTask(newRuntimeTask(runtimePath)))
+			require.Equal(t, "venv main.py\n", stdout.String())
+		})
+
+		t.Run("falls back to PATH with a warning when it is missing", func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			recorder, logger := logging.NewRecorder()
+			runner := NewTaskRunner(false, logger)
+			runner.SetOutput(&stdout, nil)
+
+			missing := filepath.Join(binDir, "missing", "python")
+			require.NoError(t, runner.RunTask(newRuntimeTask(missing)))
+			require.Equal(t, "main.py\n", stdout.String())
+
+			var warnings []logging.Entry
+
+			for _, entry := range recorder.Entries() {
+				if entry.Level == slog.LevelWarn {
+					warnings = append(warnings, entry)
+				}
+			}
+
+			require.Len(t, warnings, 1)
+			require.Equal(t, "script", warnings[0].Attrs[logging.KeyTask])
+			require.Equal(t, missing, warnings[0].Attrs["runtime"])
+		})
+
+		t.Run("does not treat a directory as the runtime", func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, nil)
+
+			require.NoError(t, runner.RunTask(newRuntimeTask(binDir)))
+			require.Equal(t, "main.py\n", stdout.String())
+		})
+	})
+
+	t.Run("problem matcher", func(t *testing.T) {
+		newLintTask := func(patterns ...string) *config.Task {
+			return &config.Task{
+				Name:            "lint",
+				Command:         "sh",
+				Args:            []string{"-c", `echo "checking"; echo "main.go:3:1: error: unused variable" >&2; printf "util.go:9:2: error: shadowed"`},
+				ProblemPatterns: patterns,
+				Type:            config.TypeVSCodeTask,
+			}
+		}
+
+		t.Run("ignores problems unless enabled", func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, &stderr)
+
+			require.NoError(t, runner.RunTask(newLintTask(`^(.*):(\d+):(\d+):\s+error:\s+(.*)$`)))
+		})
+
+		t.Run("fails a task that exits 0 but prints problems", func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, &stderr)
+			runner.SetRespectProblemMatcher(true)
+
+			err := runner.RunTask(newLintTask(`^(.*):(\d+):(\d+):\s+error:\s+(.*)$`))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "2 problem(s)")
+			require.Equal(t, "checking\nutil.go:9:2: error: shadowed", stdout.String(), "output should be forwarded unchanged")
+			require.Equal(t, "main.go:3:1: error: unused variable\n", stderr.String())
+		})
+
+		t.Run("passes when nothing matches", func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, &stdout)
+			runner.SetRespectProblemMatcher(true)
+
+			require.NoError(t, runner.RunTask(newLintTask(`^(.*):(\d+):\s+warning:\s+(.*)$`)))
+		})
+
+		t.Run("skips patterns Go cannot compile", func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			recorder, logger := logging.NewRecorder()
+			runner := NewTaskRunner(false, logger)
+			runner.SetOutput(&stdout, &stdout)
+			runner.SetRespectProblemMatcher(true)
+
+			require.NoError(t, runner.RunTask(newLintTask(`^(?!checking).*error`)))
+
+			entries := recorder.Entries()
+			require.NotEmpty(t, entries)
+			require.Equal(t, slog.LevelWarn, entries[0].Level)
+			require.Equal(t, `^(?!checking).*error`, entries[0].Attrs["pattern"])
+		})
+	})
+
+	t.Run("remote execution", func(t *testing.T) {
+		t.Run("remoteCommandLine", func(t *testing.T) {
+			task := &config.Task{Name: "build", Command: "go"}
+			env := map[string]string{"GOOS": "linux", "MSG": "it's here"}
+
+			require.Equal(t,
+				`cd '/home/dev/my project' && export GOOS=linux && export MSG='it'\''s here' && exec go build -o 'bin/my app'`,
+				remoteCommandLine(task, []string{"build", "-o", "bin/my app"}, "/home/dev/my project", env))
+
+			shellTask := &config.Task{Name: "test", Command: "make lint && make test", Shell: "bash"}
+			require.Equal(t, "make lint && make test", remoteCommandLine(shellTask, nil, "", nil))
+		})
+
+		t.Run("runs the task through ssh with cwd and env", func(t *testing.T) {
+			// A stand-in ssh that runs the remote command line locally
+			binDir := t.TempDir()
+			fakeSSH := "#!/bin/sh\n[ \"$1\" = -- ] || exit 99\nshift 2\nexec sh -c \"$1\"\n"
+			require.NoError(t, os.WriteFile(filepath.Join(binDir, "ssh"), []byte(fakeSSH), 0o755))
+			t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+			workDir := filepath.Join(t.TempDir(), "remote dir")
+			require.NoError(t, os.Mkdir(workDir, 0o755))
+
+			var stdout bytes.Buffer
+
+			runner := NewTaskRunner(false, nil)
+			runner.SetOutput(&stdout, nil)
+			runner.SetRemote("dev@devbox", nil)
+
+			task := &config.Task{
+				Name:    "remote-echo",
+				Command: "sh",
+				Args:    []string{"-c", `echo "$GREETING from ${PWD##*/}"`},
+				Cwd:     workDir,
+				Env:     map[string]string{"GREETING": "hello $USER"},
+				Type:    config.TypeVSCodeTask,
+			}
+
+			require.NoError(t, runner.RunTask(task))
+			require.Equal(t, "hello $USER from remote dir\n", stdout.String())
+		})
+
+		t.Run("rejects hosts that look like ssh options", func(t *testing.T) {
+			runner := NewTaskRunner(false, nil)
+			runner.SetRemote("-oProxyCommand=touch /tmp/pwned", nil)
+
+			err := runner.RunTask(&config.Task{Name: "echo", Command: "echo"})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid remote host")
+		})
+
+		t.Run("paranoid mode requires an allowed host", func(t *testing.T) {
+			runner := NewTaskRunnerWithOptions(false, ".", true, nil)
+			runner.SetRemote("dev@devbox", []string{"dev@otherbox"})
+
+			err := runner.RunTask(&config.Task{Name: "echo", Command: "echo"})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "not in the allowed list")
+		})
+	})
+
+	t.Run("shellInvocationArgs", func(t *testing.T) {
+		require.Equal(t, []string{"-c", "echo hi"}, shellInvocationArgs("/bin/bash", "echo hi"))
+		require.Equal(t, []string{"/d", "/c", "echo hi"}, shellInvocationArgs(`C:\Windows\cmd.exe`, "echo hi"))
+		require.Equal(t, []string{"-NoProfile", "-Command", "echo hi"}, shellInvocationArgs("pwsh", "echo hi"))
+	})
+
+	t.Run("shellJoin", func(t *testing.T) {
+		require.Equal(t, "build ./...", shellJoin([]string{"build", "./..."}))
+		require.Equal(t, `--name 'My App' 'it'\''s' ''`, shellJoin([]string{"--name", "My App", "it's", ""}))
+	})
+
+	t.Run("shellCommandLine", func(t *testing.T) {
+		script := filepath.Join(t.TempDir(), "app (fork)", "build.sh")
+		require.NoError(t, os.MkdirAll(filepath.Dir(script), 0755))
+		require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"), 0755))
+
+		// Command lines stay verbatim; only args are quoted
+		task := &config.Task{Command: "npm test | tee log", Shell: "/bin/bash"}
+		require.Equal(t, "npm test | tee log 'out (1).txt'", shellCommandLine(task, []string{"out (1).txt"}))
+
+		// A script path is one word, whatever it contains
+		task = &config.Task{Command: script, Shell: "/bin/bash"}
+		require.Equal(t, "'"+script+"' --fast", shellCommandLine(task, []string{"--fast"}))
+
+		task.Shell = "cmd.exe"
+		require.Equal(t, `"`+script+`" "a&b"`, shellCommandLine(task, []string{"a&b"}))
+
+		task.Shell = "pwsh"
+		require.Equal(t, "& '"+script+"' 'it''s'", shellCommandLine(task, []string{"it's"}))
+	})
+
+	t.Run("shellCommandLine with quoting styles", func(t *testing.T) {
+		args := []string{"$HOME/a b", "$HOME/a b", "$HOME/a b", "$HOME/a b"}
+		task := &config.Task{
+			Command:    "echo",
+			Shell:      "/bin/bash",
+			ArgQuoting: []string{config.QuotingStrong, config.QuotingWeak, config.QuotingEscape},
+		}
+
+		// The fourth arg has no style and gets the default quoting
+		require.Equal(t, `echo '$HOME/a b' "$HOME/a b" \$HOME/a\ b '$HOME/a b'`, shellCommandLine(task, args))
+
+		task.Shell = "pwsh"
+		require.Equal(t, "echo '$HOME/a b' \"$HOME/a b\" `$HOME/a` b '$HOME/a b'", shellCommandLine(task, args))
+
+		task.Shell = "cmd.exe"
+		task.ArgQuoting = []string{config.QuotingEscape}
+		require.Equal(t, "echo a^&b", shellCommandLine(task, []string{"a&b"}))
+	})
+
+	t.Run("should run shell tasks in the platform's shell without settings.json", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the command line needs a POSIX shell")
+		}
+
+		projectRoot := t.TempDir()
+		tasksPath := filepath.Join(projectRoot, ".vscode", "tasks.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(tasksPath), 0755))
+		require.NoError(t, os.WriteFile(tasksPath, []byte(`{
+  "version": "2.0.0",
+  "tasks": [{
+    "label": "greet",
+    "type": "shell",
+    "command": "echo hello && echo",
+    "args": [{"value": "$HOME/a b", "quoting": "strong"}, {"value": "world", "quoting": "weak"}]
+  }]
+}`), 0644))
+
+		tasks, err := vscode.NewTasksParser(projectRoot, nil).ParseTasks(tasksPath)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, "/bin/sh", tasks[0].Shell)
+
+		output, err := NewTaskRunner(false, nil).buildCommand(tasks[0], tasks[0].Args).Output()
+		require.NoError(t, err)
+		require.Equal(t, "hello\n$HOME/a b world\n", string(output))
+
+		// Tasks from elsewhere that name no shell get the same one
+		task := &config.Task{Command: "echo a && echo", Args: []string{"b c"}, Execution: config.ExecutionShell}
+
+		output, err = NewTaskRunner(false, nil).buildCommand(task, task.Args).Output()
+		require.NoError(t, err)
+		require.Equal(t, "a\nb c\n", string(output))
 	})
 }
 
@@ -231,6 +800,80 @@ func TestTaskFinder(t *testing.T) {
 			require.Contains(t, err.Error(), "multiple tasks match")
 		})
 
+		t.Run("prefix match", func(t *testing.T) {
+			prefixTasks := []*config.Task{
+				{Name: "deploy"},
+				{Name: "redeploy"},
+				{Name: "lint"},
+				{Name: "lint-fix"},
+			}
+
+			t.Run("unique prefix is preferred over substring matches", func(t *testing.T) {
+				task, err := finder.FindTask("dep", prefixTasks)
+				require.NoError(t, err)
+				require.Equal(t, "deploy", task.Name)
+			})
+
+			t.Run("ambiguous prefix lists candidates", func(t *testing.T) {
+				task, err := finder.FindTask("li", prefixTasks)
+				require.Error(t, err)
+				require.Nil(t, task)
+				require.Contains(t, err.Error(), "multiple tasks match 'li': lint, lint-fix")
+
+				var ambiguousErr *AmbiguousMatchError
+				require.ErrorAs(t, err, &ambiguousErr)
+				require.Equal(t, "li", ambiguousErr.Query)
+				require.Len(t, ambiguousErr.Matches, 2)
+				require.Equal(t, "lint", ambiguousErr.Matches[0].Name)
+				require.Equal(t, "lint-fix", ambiguousErr.Matches[1].Name)
+			})
+
+			t.Run("prefix that is also an exact name", func(t *testing.T) {
+				task, err := finder.FindTask("lint", prefixTasks)
+				require.NoError(t, err)
+				require.Equal(t, "lint", task.Name)
+			})
+
+			t.Run("prefix is case-insensitive", func(t *testing.T) {
+				task, err := finder.FindTask("DEP", prefixTasks)
+				require.NoError(t, err)
+				require.Equal(t, "deploy", task.Name)
+			})
+		})
+
+		t.Run("fuzzy match suggests closest task", func(t *testing.T) {
+			task, err := finder.FindTask("biuld", tasks)
+			require.Error(t, err)
+			require.Nil(t, task)
+
+			var notFoundErr *TaskNotFoundError
+			require.ErrorAs(t, err, &notFoundErr)
+			require.Equal(t, "biuld", notFoundErr.Query)
+			require.Equal(t, "build", notFoundErr.Match.Name)
+			require.Equal(t, []string{"build"}, notFoundErr.Suggestions)
+			require.Len(t, notFoundErr.SuggestedTasks, 1)
+			require.Same(t, notFoundErr.Match, notFoundErr.SuggestedTasks[0])
+			require.Contains(t, err.Error(), "task 'biuld' not found. Did you mean: build?")
+		})
+
+		t.Run("suggestions are limited to the three closest names", func(t *testing.T) {
+			similar := []*config.Task{
+				{Name: "build-docker"},
+				{Name: "build-dockr"},
+				{Name: "bulid-docker"},
+				{Name: "build-dokcer"},
+				{Name: "deploy"},
+			}
+
+			_, err := finder.FindTask("buidl-docker", similar)
+
+			var notFoundErr *TaskNotFoundError
+			require.ErrorAs(t, err, &notFoundErr)
+			require.Len(t, notFoundErr.Suggestions, 3)
+			require.NotContains(t, notFoundErr.Suggestions, "deploy")
+			require.Contains(t, err.Error(), "Did you mean: ")
+		})
+
 		t.Run("no match", func(t *testing.T) {
 			task, err := finder.FindTask("nonexistent", tasks)
 			require.Error(t, err)
@@ -245,4 +888,43 @@ func TestTaskFinder(t *testing.T) {
 			require.Contains(t, err.Error(), "task 'build' not found")
 		})
 	})
+
+	t.Run("aliases", func(t *testing.T) {
+		tasks := []*config.Task{
+			{Name: "Run API server (staging profile, port 8081)", Type: config.TypeJetBrains},
+			{Name: "test", Type: config.TypeVSCodeTask},
+		}
+
+		finder := NewTaskFinder()
+		finder.SetAliases([]config.Alias{
+			{Name: "api", Target: "Run API server (staging profile, port 8081)"},
+			{Name: "test", Target: "Run API server (staging profile, port 8081)"},
+			{Name: "gone", Target: "deleted task"},
+		})
+
+		t.Run("should expand an alias to its task", func(t *testing.T) {
+			task, err := finder.FindTask("API", tasks)
+			require.NoError(t, err)
+			require.Equal(t, "Run API server (staging profile, port 8081)", task.Name)
+		})
+
+		t.Run("should prefer a real task over an alias with its name", func(t *testing.T) {
+			task, err := finder.FindTask("test", tasks)
+			require.NoError(t, err)
+			require.Equal(t, "test", task.Name)
+		})
+
+		t.Run("should report an alias to an unknown task", func(t *testing.T) {
+			task, err := finder.FindTask("gone", tasks)
+			require.Error(t, err)
+			require.Nil(t, task)
+			require.Contains(t, err.Error(), "alias 'gone' refers to unknown task 'deleted task'")
+		})
+
+		t.Run("should still match task names normally", func(t *testing.T) {
+			task, err := finder.FindTask("run api", tasks)
+			require.NoError(t, err)
+			require.Equal(t, "Run API server (staging profile, port 8081)", task.Name)
+		})
+	})
 }
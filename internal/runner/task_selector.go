@@ -3,9 +3,12 @@ package runner
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/matcher"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -51,6 +54,20 @@ var (
 			Foreground(lipgloss.Color("#6B7280")).
 			MarginTop(1)
 
+	confirmStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FBBF24")).
+			Bold(true)
+
+	// Applied on top of the item style to the characters of a name that matched the search
+	matchStyle = lipgloss.NewStyle().
+			Bold(true).
+			Underline(true)
+
+	// Marks tasks found by their command, group or description rather than their name
+	matchedFieldStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#6B7280")).
+				Faint(true)
+
 	containerStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("#374151")).
@@ -58,172 +75,370 @@ var (
 			MarginTop(1)
 )
 
-// levenshteinDistance calculates the edit distance between two strings
-func levenshteinDistance(s1, s2 string) int {
-	if len(s1) == 0 {
-		return len(s2)
-	}
+// matchField is the part of a task a search matched, in ranking order: a match on an earlier
+// field always ranks above a match on a later one
+type matchField int
 
-	if len(s2) == 0 {
-		return len(s1)
-	}
+const (
+	matchName        matchField = iota // The task name
+	matchCommand                       // The command and its arguments
+	matchGroup                         // The task group
+	matchDescription                   // The task description
+)
 
-	// Create a matrix to store distances
-	matrix := make([][]int, len(s1)+1)
-	for i := range matrix {
-		matrix[i] = make([]int, len(s2)+1)
+// String returns the field name shown next to tasks that did not match by name
+func (f matchField) String() string {
+	switch f {
+	case matchCommand:
+		return "command"
+	case matchGroup:
+		return "group"
+	case matchDescription:
+		return "description"
+	default:
+		return "name"
 	}
+}
 
-	// Initialize first row and column
-	for i := 0; i <= len(s1); i++ {
-		matrix[i][0] = i
-	}
+// taskMatch represents a task with its relevance score
+type taskMatch struct {
+	task      config.Task
+	score     float64
+	field     matchField // What the score was earned on
+	positions []int      // Rune indexes in the task name that matched the query
+}
 
-	for j := 0; j <= len(s2); j++ {
-		matrix[0][j] = j
-	}
+// candidate is a task a search could not rule out for longer queries, with the shortest and
+// longest searched field so edit-distance matches can be ruled in or out without scoring
+type candidate struct {
+	index   int // Into the selector's tasks
+	matched bool
+	minLen  int
+	maxLen  int
+}
+
+// searchCache keeps the state of the last search so a query typed one character at a time
+// neither rescores every task nor reallocates the results on each keystroke
+type searchCache struct {
+	valid      bool
+	query      string
+	tags       string
+	fullText   bool
+	candidates []candidate
+	matches    []taskMatch
+	tasks      []config.Task
+	highlights [][]int
+	fields     []matchField
+}
 
-	// Fill the matrix
-	for i := 1; i <= len(s1); i++ {
-		for j := 1; j <= len(s2); j++ {
-			cost := 0
-			if s1[i-1] != s2[j-1] {
-				cost = 1
+// extends reports whether the candidates of the cached search also hold every match of query.
+// A name containing query contains every prefix of it, so substring matches only narrow as
+// the query grows; edit-distance matches are ruled in by length instead (see filterCandidates).
+func (c *searchCache) extends(query, tags string, fullText bool) bool {
+	return c.valid && c.fullText == fullText && c.tags == tags && strings.HasPrefix(query, c.query)
+}
+
+// splitSearchInput separates `#tag` and `@tag` tokens from the name query in the search input
+func splitSearchInput(input string) (string, []string) {
+	var (
+		words []string
+		tags  []string
+	)
+
+	for _, field := range strings.Fields(input) {
+		if strings.HasPrefix(field, "#") || strings.HasPrefix(field, "@") {
+			if tag := field[1:]; tag != "" {
+				tags = append(tags, tag)
 			}
 
-			matrix[i][j] = min(
-				matrix[i-1][j]+1,      // deletion
-				matrix[i][j-1]+1,      // insertion
-				matrix[i-1][j-1]+cost, // substitution
-			)
+			continue
 		}
+
+		words = append(words, field)
 	}
 
-	return matrix[len(s1)][len(s2)]
+	return strings.Join(words, " "), tags
 }
 
-// min returns the minimum of three integers
-func min(a, b, c int) int {
-	if a <= b && a <= c {
-		return a
-	}
+// scoreTask rates how well task matches query. In full-text mode the command line, group and
+// description are searched too, in that order, when the name does not match; positions are
+// only set for name matches since nothing else is shown.
+func (m *TaskSelectorModel) scoreTask(query string, task config.Task) taskMatch {
+	score, positions := matcher.ScoreWithPositions(query, task.Name)
 
-	if b <= c {
-		return b
+	match := taskMatch{task: task, score: score, field: matchName, positions: positions}
+	if score > 0 || !m.fullText {
+		return match
 	}
 
-	return c
-}
-
-// taskMatch represents a task with its relevance score
-type taskMatch struct {
-	task  config.Task
-	score float64
-}
-
-// calculateRelevanceScore calculates a relevance score for a task name against a query
-func calculateRelevanceScore(query, taskName string) float64 {
-	if query == "" {
-		return 1.0 // All tasks are equally relevant for empty query
+	fields := []struct {
+		field matchField
+		text  string
+	}{
+		{matchCommand, strings.Join(append([]string{task.Command}, task.Args...), " ")},
+		{matchGroup, task.Group},
+		{matchDescription, task.Description},
 	}
 
-	queryLower := strings.ToLower(query)
-	taskNameLower := strings.ToLower(taskName)
+	for _, candidate := range fields {
+		if candidate.text == "" {
+			continue
+		}
 
-	// Exact match gets the highest score
-	if queryLower == taskNameLower {
-		return 1.0
+		if score := matcher.RelevanceScore(query, candidate.text); score > 0 {
+			return taskMatch{task: task, score: score, field: candidate.field}
+		}
 	}
 
-	// Exact substring match gets very high score
-	if strings.Contains(taskNameLower, queryLower) {
-		// Score based on how much of the task name the query represents
-		return 0.9 * (float64(len(queryLower)) / float64(len(taskNameLower)))
-	}
+	return match
+}
 
-	// For other cases, use Levenshtein distance
-	distance := levenshteinDistance(queryLower, taskNameLower)
-	maxLen := max(len(queryLower), len(taskNameLower))
+// searchedLens returns the shortest and longest lowercased field scoreTask compares query to
+func (m *TaskSelectorModel) searchedLens(task config.Task) (int, int) {
+	minLen := len(strings.ToLower(task.Name))
+	maxLen := minLen
 
-	if distance > maxLen {
-		return 0.0 // Too different
+	if !m.fullText {
+		return minLen, maxLen
 	}
 
-	// Convert distance to similarity score (0-1)
-	similarity := 1.0 - (float64(distance) / float64(maxLen))
-
-	// Apply threshold - only return matches with reasonable similarity
-	if similarity < 0.5 {
-		return 0.0
+	// Empty fields are skipped by scoreTask
+	for _, field := range []string{strings.Join(append([]string{task.Command}, task.Args...), " "), task.Group, task.Description} {
+		if field != "" {
+			length := len(strings.ToLower(field))
+			minLen, maxLen = min(minLen, length), max(maxLen, length)
+		}
 	}
 
-	return similarity * 0.8 // Cap at 0.8 to prioritize exact/substring matches
+	return minLen, maxLen
 }
 
-// max returns the maximum of two integers
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
+// filterCandidates scores the cached candidates against a query extending the cached one and
+// drops those no longer query can match. A task that did not match a prefix of the query can
+// only match now by edit distance, which needs the query and some field within half of each
+// other's length: shorter fields are ruled out for good, longer ones once the query gets there.
+func (m *TaskSelectorModel) filterCandidates(query string) {
+	queryLen := len(strings.ToLower(query))
+	kept := m.cache.candidates[:0]
+
+	for _, c := range m.cache.candidates {
+		if !c.matched && queryLen > 2*c.maxLen {
+			continue
+		}
 
-	return b
-}
+		if c.matched || 2*queryLen >= c.minLen {
+			match := m.scoreTask(query, m.tasks[c.index])
+			if c.matched = match.score > 0.0; c.matched {
+				m.cache.matches = append(m.cache.matches, match)
+			}
+		}
 
-// fuzzyMatch performs fuzzy matching using Levenshtein distance
-func fuzzyMatch(query string, taskName string) bool {
-	return calculateRelevanceScore(query, taskName) > 0.0
+		kept = append(kept, c)
+	}
+
+	m.cache.candidates = kept
 }
 
-// filterTasks filters tasks based on the search input using Levenshtein distance scoring
+// filterTasks filters tasks based on the search input using Levenshtein distance scoring.
+// `#tag` tokens in the input and the tag filter only keep tasks carrying that tag.
 func (m *TaskSelectorModel) filterTasks() {
+	defer m.scrollToCursor()
+
 	if m.searchInput == "" {
 		m.filteredTasks = m.tasks
+		m.highlights = nil
+		m.matchedFields = nil
+		m.cache.valid = false
+
+		if m.tagFilter != "" {
+			m.filteredTasks = nil
+
+			for _, task := range m.tasks {
+				if task.HasTags([]string{m.tagFilter}) {
+					m.filteredTasks = append(m.filteredTasks, task)
+				}
+			}
+		}
+
+		if m.cursor >= len(m.filteredTasks) {
+			m.cursor = 0
+		}
+
 		return
 	}
 
-	// Calculate relevance scores for all tasks
-	var matches []taskMatch
+	query, tags := splitSearchInput(m.searchInput)
+	if m.tagFilter != "" {
+		tags = append(tags, m.tagFilter)
+	}
 
-	for _, task := range m.tasks {
-		score := calculateRelevanceScore(m.searchInput, task.Name)
-		if score > 0.0 {
-			matches = append(matches, taskMatch{
-				task:  task,
-				score: score,
-			})
+	tagKey := strings.Join(tags, "\x00")
+	m.cache.matches = m.cache.matches[:0]
+
+	if m.cache.extends(query, tagKey, m.fullText) {
+		m.filterCandidates(query)
+	} else {
+		// Calculate relevance scores for all tasks
+		queryLen := len(strings.ToLower(query))
+		m.cache.candidates = m.cache.candidates[:0]
+
+		for i, task := range m.tasks {
+			if !task.HasTags(tags) {
+				continue
+			}
+
+			match := m.scoreTask(query, task)
+			if match.score > 0.0 {
+				m.cache.matches = append(m.cache.matches, match)
+			}
+
+			if minLen, maxLen := m.searchedLens(task); match.score > 0.0 || queryLen <= 2*maxLen {
+				m.cache.candidates = append(m.cache.candidates, candidate{index: i, matched: match.score > 0.0, minLen: minLen, maxLen: maxLen})
+			}
 		}
 	}
 
-	// Sort by relevance score (highest first)
-	sort.Slice(matches, func(i, j int) bool {
+	m.cache.valid = true
+	m.cache.query, m.cache.tags, m.cache.fullText = query, tagKey, m.fullText
+	matches := m.cache.matches
+
+	// Sort by matched field, then by relevance score (highest first)
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].field != matches[j].field {
+			return matches[i].field < matches[j].field
+		}
+
 		return matches[i].score > matches[j].score
 	})
 
-	// Extract the tasks from sorted matches
-	m.filteredTasks = make([]config.Task, len(matches))
-	for i, match := range matches {
-		m.filteredTasks[i] = match.task
+	// Extract the tasks and their matched characters from sorted matches, reusing the buffers
+	// of the previous search; they never alias tasks, which is shown as is without a search
+	m.cache.tasks = m.cache.tasks[:0]
+	m.cache.highlights = m.cache.highlights[:0]
+	m.cache.fields = m.cache.fields[:0]
+
+	for _, match := range matches {
+		m.cache.tasks = append(m.cache.tasks, match.task)
+		m.cache.highlights = append(m.cache.highlights, match.positions)
+		m.cache.fields = append(m.cache.fields, match.field)
 	}
 
+	m.filteredTasks = m.cache.tasks
+	m.highlights = m.cache.highlights
+	m.matchedFields = m.cache.fields
+
 	// Reset cursor if it's out of bounds
 	if m.cursor >= len(m.filteredTasks) {
 		m.cursor = 0
 	}
 }
 
+// Rows of tasks the list shows at once, so long lists render only what fits on screen
+const (
+	defaultWindowRows = 20 // Until the terminal reports its size
+	minWindowRows     = 5
+	selectorChrome    = 14 // Lines around the rows: borders, padding, title, search, header and help
+)
+
+// windowRows returns how many tasks the list shows at once
+func (m *TaskSelectorModel) windowRows() int {
+	if m.height == 0 {
+		return defaultWindowRows
+	}
+
+	return max(m.height-selectorChrome, minWindowRows)
+}
+
+// scrollToCursor moves the window of rendered rows as little as needed to show the cursor
+func (m *TaskSelectorModel) scrollToCursor() {
+	rows := m.windowRows()
+
+	switch {
+	case m.cursor < m.offset:
+		m.offset = m.cursor
+	case m.cursor >= m.offset+rows:
+		m.offset = m.cursor - rows + 1
+	}
+
+	// Fill the window when the list shrinks below it
+	m.offset = max(min(m.offset, len(m.filteredTasks)-rows), 0)
+}
+
+// formatCount formats n with thousands separators, e.g. 1,431
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+
+	var b strings.Builder
+
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteRune(digit)
+	}
+
+	return b.String()
+}
+
+// selectorState is the screen the task selector is showing
+type selectorState int
+
+const (
+	stateList    selectorState = iota // Browsing and searching tasks
+	stateConfirm                      // Reviewing a task before running it
+	statePreview                      // Showing the resolved command of the highlighted task
+)
+
 // TaskSelectorModel represents the Bubble Tea model for task selection
 type TaskSelectorModel struct {
 	tasks         []config.Task
 	filteredTasks []config.Task
+	highlights    [][]int      // Matched rune indexes per filtered task, nil without a search
+	matchedFields []matchField // Matched field per filtered task, nil without a search
 	cursor        int
 	selected      *config.Task
+	pending       *config.Task
 	quitting      bool
 	width         int
 	height        int
+	offset        int // First filtered task rendered, moved by scrollToCursor
+	cache         searchCache
 	searchInput   string
 	searchMode    bool
+	fullText      bool   // Also search command lines, groups and descriptions, not just names
+	tagFilter     string // Only show tasks carrying this tag, cycled with t
+	state         selectorState
+	confirmAll    bool
+	noConfirm     bool // The caller confirms the selected task itself
+	batches       <-chan TaskBatch
+	loading       bool // Until the final batch arrives
+	loaded        int  // Sources parsed so far
+	sources       int  // Sources being parsed
+	frame         int  // Of the loading spinner
+}
+
+// TaskBatch is the task list of a selector that opened while tasks were still being parsed,
+// sent each time another source completes
+type TaskBatch struct {
+	Tasks   []config.Task // Every task loaded so far, replacing the previous batch's
+	Loaded  int           // Sources parsed so far
+	Sources int           // Sources being parsed
+	Final   bool          // Loading finished and Tasks is the complete list
+	Err     error         // Loading failed; the selector closes for the caller to report it
 }
 
+// taskBatchMsg delivers a TaskBatch to the selector
+type taskBatchMsg TaskBatch
+
+// spinnerTickMsg advances the loading spinner
+type spinnerTickMsg struct{}
+
+// Frames of the loading spinner, advanced every spinnerInterval
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 100 * time.Millisecond
+
 // NewTaskSelectorModel creates a new task selector model
 func NewTaskSelectorModel(tasks []config.Task) *TaskSelectorModel {
 	return &TaskSelectorModel{
@@ -231,12 +446,120 @@ func NewTaskSelectorModel(tasks []config.Task) *TaskSelectorModel {
 		filteredTasks: tasks, // Initially show all tasks
 		cursor:        0,
 		searchMode:    false,
+		fullText:      true,
+		state:         stateList,
 	}
 }
 
+// NewTaskSelectorModelWithConfirm creates a task selector that shows a confirmation screen
+// before running every task, not just those that opted in
+func NewTaskSelectorModelWithConfirm(tasks []config.Task, confirmAll bool) *TaskSelectorModel {
+	model := NewTaskSelectorModel(tasks)
+	model.confirmAll = confirmAll
+
+	return model
+}
+
+// NewStreamingTaskSelectorModel creates a task selector that opens before any task is loaded
+// and fills in from batches. The last batch must be Final or carry an error.
+func NewStreamingTaskSelectorModel(batches <-chan TaskBatch, confirmAll bool) *TaskSelectorModel {
+	model := NewTaskSelectorModelWithConfirm(nil, confirmAll)
+	model.batches = batches
+	model.loading = true
+
+	return model
+}
+
+// SetQuery fills in the search box and filters the tasks by it without entering search mode,
+// so the first candidate can be picked right away with enter or a number key
+func (m *TaskSelectorModel) SetQuery(query string) {
+	m.searchInput = query
+	m.cursor = 0
+	m.filterTasks()
+}
+
 // Init implements the tea.Model interface
 func (m *TaskSelectorModel) Init() tea.Cmd {
-	return nil
+	if m.batches == nil {
+		return nil
+	}
+
+	return tea.Batch(m.nextBatch(), spinnerTick())
+}
+
+// nextBatch waits for the next batch of tasks
+func (m *TaskSelectorModel) nextBatch() tea.Cmd {
+	batches := m.batches
+
+	return func() tea.Msg {
+		batch, ok := <-batches
+		if !ok {
+			return nil
+		}
+
+		return taskBatchMsg(batch)
+	}
+}
+
+// spinnerTick advances the loading spinner after spinnerInterval
+func spinnerTick() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
+// applyBatch replaces the tasks with those of batch, closing the selector when loading failed
+// or finished without any task to pick
+func (m *TaskSelectorModel) applyBatch(batch TaskBatch) (tea.Model, tea.Cmd) {
+	if batch.Err != nil || (batch.Final && len(batch.Tasks) == 0) {
+		m.loading = false
+		m.quitting = true
+
+		return m, tea.Quit
+	}
+
+	m.loaded, m.sources = batch.Loaded, batch.Sources
+	m.loading = !batch.Final
+	m.setTasks(batch.Tasks)
+
+	if m.loading {
+		return m, m.nextBatch()
+	}
+
+	return m, nil
+}
+
+// setTasks replaces the tasks, keeping the search, the tag filter and the cursor on the task
+// it was on
+func (m *TaskSelectorModel) setTasks(tasks []config.Task) {
+	var current *config.Task
+	if m.cursor < len(m.filteredTasks) {
+		task := m.filteredTasks[m.cursor]
+		current = &task
+	}
+
+	// The pending task points into the filtered tasks, whose buffer the new search reuses
+	if m.pending != nil {
+		pending := *m.pending
+		m.pending = &pending
+	}
+
+	m.tasks = tasks
+	m.cache.valid = false
+	m.filterTasks()
+
+	if current == nil {
+		return
+	}
+
+	for i, task := range m.filteredTasks {
+		if task.Name == current.Name && task.Source == current.Source {
+			m.cursor = i
+			m.scrollToCursor()
+
+			break
+		}
+	}
 }
 
 // Update implements the tea.Model interface
@@ -245,9 +568,22 @@ func (m *TaskSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.scrollToCursor()
 
 		return m, nil
 
+	case taskBatchMsg:
+		return m.applyBatch(TaskBatch(msg))
+
+	case spinnerTickMsg:
+		if !m.loading || m.quitting {
+			return m, nil
+		}
+
+		m.frame = (m.frame + 1) % len(spinnerFrames)
+
+		return m, spinnerTick()
+
 	case tea.KeyMsg:
 		// Handle global quit commands
 		if msg.String() == "ctrl+c" {
@@ -255,6 +591,50 @@ func (m *TaskSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		// Handle the confirmation screen
+		if m.state == stateConfirm {
+			switch msg.String() {
+			case "enter", "y":
+				m.selected = m.pending
+				m.quitting = true
+
+				return m, tea.Quit
+
+			case "esc", "n":
+				// Go back to the list without running anything
+				m.pending = nil
+				m.state = stateList
+			}
+
+			return m, nil
+		}
+
+		// Handle the command preview
+		if m.state == statePreview {
+			switch msg.String() {
+			case "enter", "r", "y":
+				// The preview already showed what will run, so no confirmation follows
+				m.selected = m.pending
+				m.quitting = true
+
+				return m, tea.Quit
+
+			case "esc", "c", "n", "q":
+				m.pending = nil
+				m.state = stateList
+			}
+
+			return m, nil
+		}
+
+		// Terminals send ctrl+/ as ctrl+_
+		if msg.String() == "ctrl+_" {
+			m.fullText = !m.fullText
+			m.filterTasks()
+
+			return m, nil
+		}
+
 		// Handle search mode
 		if m.searchMode {
 			switch msg.String() {
@@ -302,22 +682,42 @@ func (m *TaskSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.searchMode = true
 			return m, nil
 
+		case "t":
+			m.nextTagFilter()
+			return m, nil
+
+		case "p":
+			// Review the highlighted task's command before deciding to run it
+			if len(m.filteredTasks) > 0 {
+				m.pending = &m.filteredTasks[m.cursor]
+				m.state = statePreview
+			}
+
+			return m, nil
+
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
+				m.scrollToCursor()
 			}
 
 		case "down", "j":
 			if m.cursor < len(m.filteredTasks)-1 {
 				m.cursor++
+				m.scrollToCursor()
 			}
 
 		case "enter", " ":
 			if len(m.filteredTasks) > 0 {
-				m.selected = &m.filteredTasks[m.cursor]
-				m.quitting = true
+				return m.choose(m.cursor)
+			}
 
-				return m, tea.Quit
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Quick select: run the task at that position in the list
+			index := int(msg.String()[0] - '1')
+			if index < len(m.filteredTasks) {
+				m.cursor = index
+				return m.choose(index)
 			}
 		}
 	}
@@ -325,6 +725,64 @@ func (m *TaskSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// nextTagFilter switches the tag filter to the next tag the tasks carry, in alphabetical
+// order, and back to showing every task after the last one
+func (m *TaskSelectorModel) nextTagFilter() {
+	seen := make(map[string]bool)
+
+	var tags []string
+
+	for _, task := range m.tasks {
+		for _, tag := range task.Tags {
+			if key := strings.ToLower(tag); !seen[key] {
+				seen[key] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+
+	next := ""
+
+	for i, tag := range tags {
+		if m.tagFilter == "" {
+			next = tag
+			break
+		}
+
+		if strings.EqualFold(tag, m.tagFilter) {
+			if i+1 < len(tags) {
+				next = tags[i+1]
+			}
+
+			break
+		}
+	}
+
+	m.tagFilter = next
+	m.filterTasks()
+}
+
+// choose runs the filtered task at index, after the confirmation screen if it needs one
+func (m *TaskSelectorModel) choose(index int) (tea.Model, tea.Cmd) {
+	task := &m.filteredTasks[index]
+
+	if !m.noConfirm && (m.confirmAll || task.RequiresConfirmation()) {
+		m.pending = task
+		m.state = stateConfirm
+
+		return m, nil
+	}
+
+	m.selected = task
+	m.quitting = true
+
+	return m, tea.Quit
+}
+
 // View implements the tea.Model interface
 func (m *TaskSelectorModel) View() string {
 	if m.quitting {
@@ -335,7 +793,7 @@ func (m *TaskSelectorModel) View() string {
 		return "👋 Porter mission cancelled. Until next time!\n"
 	}
 
-	if len(m.tasks) == 0 {
+	if len(m.tasks) == 0 && !m.loading {
 		return containerStyle.Render(
 			titleStyle.Render("🎮 Taskporter - Task Selection") + "\n\n" +
 				"❌ No tasks or launch configurations found.\n" +
@@ -344,72 +802,228 @@ func (m *TaskSelectorModel) View() string {
 		)
 	}
 
+	if m.state == stateConfirm && m.pending != nil {
+		return m.confirmView()
+	}
+
+	if m.state == statePreview && m.pending != nil {
+		return m.previewView()
+	}
+
 	// Header
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("🎮 Taskporter - Select Task to Run"))
 	b.WriteString("\n")
 
 	// Search input display
+	scope := "names only"
+	if m.fullText {
+		scope = "full text"
+	}
+
+	// The tag filter narrows the list along with the search
+	tagged := ""
+	if m.tagFilter != "" {
+		tagged = " tagged #" + m.tagFilter
+	}
+
 	if m.searchMode {
-		searchPrompt := searchPromptStyle.Render("Search: ")
+		searchPrompt := searchPromptStyle.Render(fmt.Sprintf("Search (%s): ", scope))
 		searchInput := searchStyle.Render(m.searchInput + "█") // Add cursor
 		b.WriteString(searchPrompt + searchInput + "\n")
-		b.WriteString(headerStyle.Render(fmt.Sprintf("Showing %d of %d tasks", len(m.filteredTasks), len(m.tasks))))
+		b.WriteString(headerStyle.Render(fmt.Sprintf("Showing %d of %d tasks%s", len(m.filteredTasks), len(m.tasks), tagged)))
 	} else {
 		if m.searchInput != "" {
-			searchPrompt := searchPromptStyle.Render("Filter: ")
+			searchPrompt := searchPromptStyle.Render(fmt.Sprintf("Filter (%s): ", scope))
 			searchInput := sourceStyle.Render(m.searchInput)
 			b.WriteString(searchPrompt + searchInput + "\n")
-			b.WriteString(headerStyle.Render(fmt.Sprintf("Showing %d of %d tasks", len(m.filteredTasks), len(m.tasks))))
+		}
+
+		if m.searchInput != "" || m.tagFilter != "" {
+			b.WriteString(headerStyle.Render(fmt.Sprintf("Showing %d of %d tasks%s", len(m.filteredTasks), len(m.tasks), tagged)))
 		} else {
 			b.WriteString(headerStyle.Render(fmt.Sprintf("Found %d configurations", len(m.tasks))))
 		}
 	}
 
+	if m.loading {
+		b.WriteString(sourceStyle.Render(fmt.Sprintf("  %s Loading tasks… (%d of %d sources)", spinnerFrames[m.frame], m.loaded, m.sources)))
+	}
+
 	b.WriteString("\n\n")
 
 	// Task list (using filtered tasks)
-	if len(m.filteredTasks) == 0 {
+	if len(m.filteredTasks) == 0 && m.loading && m.searchInput == "" && m.tagFilter == "" {
+		b.WriteString("⏳ Waiting for the first tasks…\n")
+	} else if len(m.filteredTasks) == 0 {
 		b.WriteString("🔍 No tasks match your search.\n")
 
 		if m.searchInput != "" {
 			b.WriteString("Try a different search term or press Esc to clear.\n")
 		}
 	} else {
-		for i, task := range m.filteredTasks {
+		// Only the window around the cursor is rendered; long lists scroll with it
+		end := min(m.offset+m.windowRows(), len(m.filteredTasks))
+
+		for i := m.offset; i < end; i++ {
+			task := m.filteredTasks[i]
+
 			cursor := "  "
 			if i == m.cursor {
 				cursor = "▶ "
 			}
 
-			// Format task line
-			line := fmt.Sprintf("%s%s", cursor, task.Name)
+			// The first nine tasks carry the digit that runs them
+			if i < 9 {
+				cursor += fmt.Sprintf("%d. ", i+1)
+			} else {
+				cursor += "   "
+			}
 
 			// Add source and type info
 			source := getTaskSource(task)
 			taskType := getTaskType(task)
 			info := fmt.Sprintf(" [%s - %s]", source, taskType)
 
+			if len(task.Tags) > 0 {
+				info += " #" + strings.Join(task.Tags, " #")
+			}
+
+			var positions []int
+			if i < len(m.highlights) {
+				positions = m.highlights[i]
+			}
+
+			itemStyle := normalItemStyle
 			if i == m.cursor {
-				line = selectedItemStyle.Render(line) + sourceStyle.Render(info)
-			} else {
-				line = normalItemStyle.Render(line) + sourceStyle.Render(info)
+				itemStyle = selectedItemStyle
+			}
+
+			line := renderTaskName(cursor, task.Name, positions, itemStyle) + sourceStyle.Render(info)
+
+			if i < len(m.matchedFields) && m.matchedFields[i] != matchName {
+				line += " " + matchedFieldStyle.Render(fmt.Sprintf("(matched %s)", m.matchedFields[i]))
 			}
 
 			b.WriteString(line)
 			b.WriteString("\n")
 		}
+
+		if m.offset > 0 || end < len(m.filteredTasks) {
+			noun := "tasks"
+			if m.searchInput != "" || m.tagFilter != "" {
+				noun = "matches"
+			}
+
+			b.WriteString(sourceStyle.Render(fmt.Sprintf("   showing %d of %s %s (%d-%d)", end-m.offset, formatCount(len(m.filteredTasks)), noun, m.offset+1, end)))
+			b.WriteString("\n")
+		}
 	}
 
 	// Help text
 	b.WriteString("\n")
 
 	if m.searchMode {
-		b.WriteString(helpStyle.Render("Type to search • Enter: Exit search • Esc: Clear search • Ctrl+C: Quit"))
+		b.WriteString(helpStyle.Render("Type to search, #tag or @tag to filter by tag • Ctrl+/: Names only/full text • Enter: Exit search • Esc: Clear search • Ctrl+C: Quit"))
+	} else {
+		b.WriteString(helpStyle.Render("↑/↓ Navigate • Enter: Run Task • p: Preview • 1-9: Run Nth Task • /: Search • t: Filter by tag • q: Quit"))
+	}
+
+	return containerStyle.Render(b.String())
+}
+
+// renderTaskName renders the cursor and task name in style, emphasizing the characters at
+// positions so users can see why a task matched the search
+func renderTaskName(cursor, name string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(cursor + name)
+	}
+
+	// Render runs of matched and unmatched characters separately; the padding is added
+	// around the whole name so the runs sit next to each other
+	plain := style.UnsetPadding()
+	marked := plain.Inherit(matchStyle)
+
+	matched := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		matched[position] = true
+	}
+
+	var b strings.Builder
+
+	b.WriteString(plain.Render(strings.Repeat(" ", style.GetPaddingLeft()) + cursor))
+
+	runes := []rune(name)
+	for start := 0; start < len(runes); {
+		end := start
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+
+		segment := string(runes[start:end])
+		if matched[start] {
+			b.WriteString(marked.Render(segment))
+		} else {
+			b.WriteString(plain.Render(segment))
+		}
+
+		start = end
+	}
+
+	b.WriteString(plain.Render(strings.Repeat(" ", style.GetPaddingRight())))
+
+	return b.String()
+}
+
+// confirmView renders the confirmation screen for the pending task
+func (m *TaskSelectorModel) confirmView() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("🎮 Taskporter - Confirm Task"))
+	b.WriteString("\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%s [%s - %s]", m.pending.Name, getTaskSource(*m.pending), getTaskType(*m.pending))))
+	b.WriteString("\n\n")
+
+	for _, line := range TaskSummary(m.pending) {
+		b.WriteString(normalItemStyle.Render(line))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(confirmStyle.Render("⚠️  Run this task?"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("Enter: Confirm and run • Esc: Back to list • Ctrl+C: Quit"))
+
+	return containerStyle.Render(b.String())
+}
+
+// previewView renders the resolved command of the pending task with a run or cancel prompt
+func (m *TaskSelectorModel) previewView() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("🎮 Taskporter - Preview Task"))
+	b.WriteString("\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%s [%s - %s]", m.pending.Name, getTaskSource(*m.pending), getTaskType(*m.pending))))
+	b.WriteString("\n\n")
+
+	if commandLine := TaskCommandLine(m.pending); commandLine != "" {
+		b.WriteString(searchStyle.Render("$ " + commandLine))
 	} else {
-		b.WriteString(helpStyle.Render("↑/↓ Navigate • Enter: Run Task • /: Search • q: Quit"))
+		b.WriteString(searchStyle.Render("Runs: " + strings.Join(m.pending.DependsOn, ", ")))
 	}
 
+	b.WriteString("\n\n")
+
+	for _, line := range TaskSummary(m.pending) {
+		b.WriteString(normalItemStyle.Render(line))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(confirmStyle.Render("Run this task?"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("Enter: Run • Esc: Cancel • Ctrl+C: Quit"))
+
 	return containerStyle.Render(b.String())
 }
 
@@ -445,7 +1059,37 @@ func getTaskType(task config.Task) string {
 
 // RunInteractiveTaskSelector runs the interactive task selector and returns the selected task
 func RunInteractiveTaskSelector(tasks []config.Task) (*config.Task, error) {
+	return RunInteractiveTaskSelectorWithConfirm(tasks, false)
+}
+
+// RunInteractiveTaskSelectorWithConfirm runs the interactive task selector, asking for
+// confirmation before every task when confirmAll is set. Tasks that opted in to
+// confirmation are always confirmed. A nil task means the user cancelled.
+func RunInteractiveTaskSelectorWithConfirm(tasks []config.Task, confirmAll bool) (*config.Task, error) {
+	return runTaskSelector(NewTaskSelectorModelWithConfirm(tasks, confirmAll))
+}
+
+// RunInteractiveTaskSelectorWithQuery runs the interactive task selector over tasks with query
+// already in the search box, e.g. to pick one of the tasks an ambiguous name matched. The
+// selected task is returned without a confirmation screen, so callers confirm it along with
+// any other tasks. A nil task means the user cancelled.
+func RunInteractiveTaskSelectorWithQuery(tasks []config.Task, query string) (*config.Task, error) {
 	model := NewTaskSelectorModel(tasks)
+	model.noConfirm = true
+	model.SetQuery(query)
+
+	return runTaskSelector(model)
+}
+
+// RunStreamingTaskSelector runs the interactive task selector before the tasks are loaded, showing
+// those of each batch as it arrives, and returns the selected task. Tasks can be picked before
+// loading finishes. A nil task means the user cancelled, or that loading failed or found no tasks.
+func RunStreamingTaskSelector(batches <-chan TaskBatch, confirmAll bool) (*config.Task, error) {
+	return runTaskSelector(NewStreamingTaskSelectorModel(batches, confirmAll))
+}
+
+// runTaskSelector runs a task selector model full screen and returns the task it selected
+func runTaskSelector(model *TaskSelectorModel) (*config.Task, error) {
 	program := tea.NewProgram(model, tea.WithAltScreen())
 
 	finalModel, err := program.Run()
@@ -1,206 +1,16 @@
 package runner
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/require"
 	"github.com/syndbg/taskporter/internal/config"
 )
 
-func TestLevenshteinDistance(t *testing.T) {
-	tests := []struct {
-		name string
-		s1   string
-		s2   string
-		want int
-	}{
-		{
-			name: "empty strings",
-			s1:   "",
-			s2:   "",
-			want: 0,
-		},
-		{
-			name: "empty first string",
-			s1:   "",
-			s2:   "abc",
-			want: 3,
-		},
-		{
-			name: "empty second string",
-			s1:   "abc",
-			s2:   "",
-			want: 3,
-		},
-		{
-			name: "identical strings",
-			s1:   "test",
-			s2:   "test",
-			want: 0,
-		},
-		{
-			name: "single character difference",
-			s1:   "test",
-			s2:   "best",
-			want: 1,
-		},
-		{
-			name: "single insertion",
-			s1:   "test",
-			s2:   "tests",
-			want: 1,
-		},
-		{
-			name: "single deletion",
-			s1:   "tests",
-			s2:   "test",
-			want: 1,
-		},
-		{
-			name: "multiple operations",
-			s1:   "kitten",
-			s2:   "sitting",
-			want: 3,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := levenshteinDistance(tt.s1, tt.s2)
-			require.Equal(t, tt.want, got, "levenshteinDistance(%q, %q)", tt.s1, tt.s2)
-		})
-	}
-}
-
-func TestCalculateRelevanceScore(t *testing.T) {
-	tests := []struct {
-		name     string
-		query    string
-		taskName string
-		want     float64
-		minScore float64 // minimum expected score for ranges
-	}{
-		{
-			name:     "empty query",
-			query:    "",
-			taskName: "build",
-			want:     1.0,
-		},
-		{
-			name:     "exact match",
-			query:    "build",
-			taskName: "build",
-			want:     1.0,
-		},
-		{
-			name:     "case insensitive exact match",
-			query:    "BUILD",
-			taskName: "build",
-			want:     1.0,
-		},
-		{
-			name:     "substring match",
-			query:    "uild",
-			taskName: "build",
-			minScore: 0.7, // Should be high score for substring
-		},
-		{
-			name:     "partial substring match",
-			query:    "test",
-			taskName: "run:test:unit",
-			minScore: 0.25, // Score based on substring length ratio
-		},
-		{
-			name:     "similar strings",
-			query:    "tset", // typo of "test"
-			taskName: "test",
-			minScore: 0.4, // Should match with reasonable score (0.5 * 0.8 = 0.4)
-		},
-		{
-			name:     "very different strings",
-			query:    "xyz",
-			taskName: "build",
-			want:     0.0, // Should not match
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := calculateRelevanceScore(tt.query, tt.taskName)
-			if tt.want > 0 {
-				require.Equal(t, tt.want, got, "calculateRelevanceScore(%q, %q)", tt.query, tt.taskName)
-			} else {
-				require.GreaterOrEqual(t, got, tt.minScore, "calculateRelevanceScore(%q, %q) should be >= %f, got %f", tt.query, tt.taskName, tt.minScore, got)
-			}
-		})
-	}
-}
-
-func TestFuzzyMatch(t *testing.T) {
-	tests := []struct {
-		name     string
-		query    string
-		taskName string
-		want     bool
-	}{
-		{
-			name:     "empty query matches everything",
-			query:    "",
-			taskName: "build",
-			want:     true,
-		},
-		{
-			name:     "exact match",
-			query:    "build",
-			taskName: "build",
-			want:     true,
-		},
-		{
-			name:     "case insensitive exact match",
-			query:    "BUILD",
-			taskName: "build",
-			want:     true,
-		},
-		{
-			name:     "substring match",
-			query:    "uild",
-			taskName: "build",
-			want:     true,
-		},
-		{
-			name:     "similar strings (typo)",
-			query:    "buil", // missing 'd'
-			taskName: "build",
-			want:     true,
-		},
-		{
-			name:     "very different strings",
-			query:    "xyz",
-			taskName: "build",
-			want:     false,
-		},
-		{
-			name:     "partial match in longer name",
-			query:    "test",
-			taskName: "run:test:unit",
-			want:     true,
-		},
-		{
-			name:     "reasonable typo",
-			query:    "tset", // "test" with swapped characters
-			taskName: "test",
-			want:     true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := fuzzyMatch(tt.query, tt.taskName)
-			require.Equal(t, tt.want, got, "fuzzyMatch(%q, %q)", tt.query, tt.taskName)
-		})
-	}
-}
-
 func TestTaskSelectorModel_FilterTasks(t *testing.T) {
 	// Create test tasks
 	tasks := []config.Task{
@@ -451,3 +261,790 @@ func TestTaskSelectorModel_SearchWorkflow(t *testing.T) {
 		require.Len(t, model.filteredTasks, 4) // back to all tasks
 	})
 }
+
+func TestTaskSelectorModel_TagSearch(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "build:dev", Type: config.TypeVSCodeTask, Source: "vscode-tasks", Tags: []string{"quick"}},
+		{Name: "build:prod", Type: config.TypeVSCodeTask, Source: "vscode-tasks", Tags: []string{"ci", "slow"}},
+		{Name: "test:unit", Type: config.TypeVSCodeTask, Source: "vscode-tasks", Tags: []string{"ci", "quick"}},
+		{Name: "deploy", Type: config.TypeJetBrains, Source: "jetbrains"},
+	}
+
+	t.Run("should keep only tagged tasks in their original order", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "#ci"
+		model.filterTasks()
+
+		require.Len(t, model.filteredTasks, 2)
+		require.Equal(t, "build:prod", model.filteredTasks[0].Name)
+		require.Equal(t, "test:unit", model.filteredTasks[1].Name)
+	})
+
+	t.Run("should compose tags with the name search", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "build #quick"
+		model.filterTasks()
+
+		require.Len(t, model.filteredTasks, 1)
+		require.Equal(t, "build:dev", model.filteredTasks[0].Name)
+	})
+
+	t.Run("should accept @tag like #tag", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "@quick test"
+		model.filterTasks()
+
+		require.Len(t, model.filteredTasks, 1)
+		require.Equal(t, "test:unit", model.filteredTasks[0].Name)
+	})
+
+	t.Run("should show tags inline", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		require.Contains(t, model.View(), "#ci #slow")
+	})
+
+	t.Run("should cycle the tag filter with t", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+
+		press := func() {
+			model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+		}
+
+		press()
+		require.Equal(t, "ci", model.tagFilter)
+		require.Len(t, model.filteredTasks, 2)
+		require.Contains(t, model.View(), "Showing 2 of 4 tasks tagged #ci")
+
+		press()
+		require.Equal(t, "quick", model.tagFilter)
+		require.Len(t, model.filteredTasks, 2)
+
+		// The filter composes with the search
+		model.searchInput = "build"
+		model.filterTasks()
+		require.Len(t, model.filteredTasks, 1)
+		require.Equal(t, "build:dev", model.filteredTasks[0].Name)
+		model.searchInput = ""
+
+		press()
+		require.Equal(t, "slow", model.tagFilter)
+		require.Len(t, model.filteredTasks, 1)
+
+		press()
+		require.Empty(t, model.tagFilter)
+		require.Len(t, model.filteredTasks, 4)
+	})
+}
+
+func TestTaskSelectorModel_MatchHighlights(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "build:dev", Type: config.TypeVSCodeTask, Source: "vscode-tasks"},
+		{Name: "test:unit", Type: config.TypeVSCodeTask, Source: "vscode-tasks"},
+	}
+
+	t.Run("should record the matched characters of each result", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "unit"
+		model.filterTasks()
+
+		require.Len(t, model.filteredTasks, 1)
+		require.Equal(t, [][]int{{5, 6, 7, 8}}, model.highlights)
+	})
+
+	t.Run("should clear highlights with the search", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "unit"
+		model.filterTasks()
+		model.searchInput = ""
+		model.filterTasks()
+
+		require.Nil(t, model.highlights)
+	})
+
+	t.Run("should keep the name readable when highlighted", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "unit"
+		model.filterTasks()
+
+		require.Contains(t, model.View(), "▶ 1. test:unit")
+	})
+
+	t.Run("should render the same text with and without highlights", func(t *testing.T) {
+		require.Equal(t,
+			renderTaskName("▶ ", "test:unit", nil, selectedItemStyle),
+			renderTaskName("▶ ", "test:unit", []int{0, 5, 6}, selectedItemStyle),
+		)
+	})
+}
+
+func TestTaskSelectorModel_FullTextSearch(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "compile", Command: "go", Args: []string{"build", "./..."}, Source: "vscode-tasks"},
+		{Name: "go build tools", Command: "make", Args: []string{"tools"}, Source: "vscode-tasks"},
+		{Name: "lint", Command: "golangci-lint", Description: "Static checks before review", Source: "vscode-tasks"},
+	}
+
+	t.Run("should find tasks by command and rank name matches first", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "go build"
+		model.filterTasks()
+
+		require.Len(t, model.filteredTasks, 2)
+		require.Equal(t, "go build tools", model.filteredTasks[0].Name)
+		require.Equal(t, "compile", model.filteredTasks[1].Name)
+		require.Nil(t, model.highlights[1], "a command match highlights nothing in the name")
+	})
+
+	t.Run("should find tasks by description", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "static checks"
+		model.filterTasks()
+
+		require.Len(t, model.filteredTasks, 1)
+		require.Equal(t, "lint", model.filteredTasks[0].Name)
+	})
+
+	t.Run("should toggle name-only search with ctrl+/", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchMode = true
+		model.searchInput = "go build"
+		model.filterTasks()
+		require.Len(t, model.filteredTasks, 2)
+
+		model.Update(tea.KeyMsg{Type: tea.KeyCtrlUnderscore})
+		require.False(t, model.fullText)
+		require.Len(t, model.filteredTasks, 1)
+		require.Equal(t, "go build tools", model.filteredTasks[0].Name)
+		require.Contains(t, model.View(), "names only")
+
+		model.Update(tea.KeyMsg{Type: tea.KeyCtrlUnderscore})
+		require.True(t, model.fullText)
+		require.Len(t, model.filteredTasks, 2)
+	})
+}
+
+func TestTaskSelectorModel_ScoreTask(t *testing.T) {
+	model := NewTaskSelectorModel(nil)
+
+	testCases := []struct {
+		name      string
+		query     string
+		task      config.Task
+		field     matchField
+		positions bool
+	}{
+		{
+			name:      "exact name",
+			query:     "test",
+			task:      config.Task{Name: "test", Command: "go", Args: []string{"test"}},
+			field:     matchName,
+			positions: true,
+		},
+		{
+			name:      "name substring wins over command",
+			query:     "test",
+			task:      config.Task{Name: "run integration tests", Command: "go", Args: []string{"test"}},
+			field:     matchName,
+			positions: true,
+		},
+		{
+			name:  "command with arguments",
+			query: "pytest",
+			task:  config.Task{Name: "unit", Command: "python", Args: []string{"-m", "pytest"}},
+			field: matchCommand,
+		},
+		{
+			name:  "group",
+			query: "build",
+			task:  config.Task{Name: "compile", Command: "make", Group: "build"},
+			field: matchGroup,
+		},
+		{
+			name:  "description",
+			query: "release",
+			task:  config.Task{Name: "compile", Command: "make", Description: "Build the release binaries"},
+			field: matchDescription,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match := model.scoreTask(tc.query, tc.task)
+
+			require.Greater(t, match.score, 0.0)
+			require.Equal(t, tc.field, match.field)
+			require.Equal(t, tc.positions, match.positions != nil)
+		})
+	}
+
+	t.Run("should ignore other fields when searching names only", func(t *testing.T) {
+		model := NewTaskSelectorModel(nil)
+		model.fullText = false
+
+		match := model.scoreTask("pytest", config.Task{Name: "unit", Command: "python", Args: []string{"-m", "pytest"}})
+		require.Zero(t, match.score)
+	})
+}
+
+func TestTaskSelectorModel_FullTextRanking(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "docs", Command: "mkdocs", Description: "Serve the test reports", Source: "vscode-tasks"},
+		{Name: "unit", Command: "go", Args: []string{"test", "./..."}, Source: "vscode-tasks"},
+		{Name: "ci", Command: "make", Group: "test", Source: "vscode-tasks"},
+		{Name: "run all the integration tests", Command: "make", Args: []string{"integration"}, Source: "vscode-tasks"},
+		{Name: "test", Command: "make", Args: []string{"check"}, Source: "vscode-tasks"},
+	}
+
+	model := NewTaskSelectorModel(tasks)
+	model.searchInput = "test"
+	model.filterTasks()
+
+	var names []string
+	for _, task := range model.filteredTasks {
+		names = append(names, task.Name)
+	}
+
+	// Name exact > name substring > command > group > description, even when the
+	// name substring covers less of its field than the command match does
+	require.Equal(t, []string{"test", "run all the integration tests", "unit", "ci", "docs"}, names)
+	require.Equal(t, []matchField{matchName, matchName, matchCommand, matchGroup, matchDescription}, model.matchedFields)
+
+	view := model.View()
+	require.Contains(t, view, "(matched command)")
+	require.Contains(t, view, "(matched group)")
+	require.Contains(t, view, "(matched description)")
+	require.NotContains(t, view, "(matched name)")
+}
+
+func TestTaskSelectorModel_ConfirmWorkflow(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "build", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"build"}},
+		{Name: "deploy", Type: config.TypeVSCodeTask, Command: "./deploy.sh", Group: "deploy", Env: map[string]string{"STAGE": "prod"}},
+		{Name: "db-reset", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"db-reset"}, Confirm: true},
+	}
+
+	press := func(m *TaskSelectorModel, key string) tea.Cmd {
+		var msg tea.KeyMsg
+
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		}
+
+		_, cmd := m.Update(msg)
+
+		return cmd
+	}
+
+	t.Run("tasks without opt-in run immediately", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+
+		require.NotNil(t, press(model, "enter"))
+		require.Equal(t, "build", model.selected.Name)
+		require.Equal(t, stateList, model.state)
+	})
+
+	t.Run("deploy group asks for confirmation", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		press(model, "down")
+
+		require.Nil(t, press(model, "enter"))
+		require.Equal(t, stateConfirm, model.state)
+		require.Nil(t, model.selected)
+
+		view := model.View()
+		require.Contains(t, view, "Command: ./deploy.sh")
+		require.Contains(t, view, "Env: STAGE=prod")
+
+		require.NotNil(t, press(model, "enter"))
+		require.Equal(t, "deploy", model.selected.Name)
+	})
+
+	t.Run("esc goes back to the list", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		press(model, "down")
+		press(model, "down")
+
+		press(model, "enter")
+		require.Equal(t, stateConfirm, model.state)
+
+		require.Nil(t, press(model, "esc"))
+		require.Equal(t, stateList, model.state)
+		require.Nil(t, model.selected)
+		require.False(t, model.quitting)
+		require.Equal(t, 2, model.cursor)
+	})
+
+	t.Run("confirm all asks for every task", func(t *testing.T) {
+		model := NewTaskSelectorModelWithConfirm(tasks, true)
+
+		require.Nil(t, press(model, "enter"))
+		require.Equal(t, stateConfirm, model.state)
+		require.Contains(t, model.View(), "Args: build")
+	})
+}
+
+func TestTaskSelectorModel_PreviewWorkflow(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "build", Type: config.TypeVSCodeTask, Command: "go", Args: []string{"build", "-o", "bin/my app"}},
+		{Name: "db-reset", Type: config.TypeVSCodeTask, Command: "make", Args: []string{"db-reset"}, Confirm: true},
+		{Name: "all", Type: config.TypeVSCodeTask, DependsOn: []string{"build", "db-reset"}},
+	}
+
+	press := func(m *TaskSelectorModel, msg tea.KeyMsg) tea.Cmd {
+		_, cmd := m.Update(msg)
+		return cmd
+	}
+
+	key := func(key string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+
+	t.Run("should show the resolved command of the highlighted task", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+
+		require.Nil(t, press(model, key("p")))
+		require.Equal(t, statePreview, model.state)
+		require.Nil(t, model.selected)
+
+		view := model.View()
+		require.Contains(t, view, "Preview Task")
+		require.Contains(t, view, "$ go build -o 'bin/my app'")
+		require.Contains(t, view, "Run this task?")
+	})
+
+	t.Run("should run the previewed task without asking again", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		press(model, tea.KeyMsg{Type: tea.KeyDown})
+		press(model, key("p"))
+
+		require.NotNil(t, press(model, tea.KeyMsg{Type: tea.KeyEnter}))
+		require.Equal(t, "db-reset", model.selected.Name)
+		require.True(t, model.quitting)
+	})
+
+	t.Run("should go back to the list on cancel", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		press(model, key("p"))
+
+		require.Nil(t, press(model, tea.KeyMsg{Type: tea.KeyEsc}))
+		require.Equal(t, stateList, model.state)
+		require.Nil(t, model.selected)
+		require.False(t, model.quitting)
+	})
+
+	t.Run("should list what an aggregate task runs", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.cursor = 2
+		press(model, key("p"))
+
+		require.Contains(t, model.View(), "Runs: build, db-reset")
+	})
+
+	t.Run("should type p into the search instead", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchMode = true
+
+		press(model, key("p"))
+		require.Equal(t, stateList, model.state)
+		require.Equal(t, "p", model.searchInput)
+	})
+
+	t.Run("should keep enter as an instant run", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+
+		require.NotNil(t, press(model, tea.KeyMsg{Type: tea.KeyEnter}))
+		require.Equal(t, "build", model.selected.Name)
+		require.Contains(t, NewTaskSelectorModel(tasks).View(), "p: Preview")
+	})
+}
+
+func TestTaskSelectorModel_StreamingBatches(t *testing.T) {
+	build := config.Task{Name: "build", Type: config.TypeVSCodeTask, Source: ".vscode/tasks.json"}
+	test := config.Task{Name: "test", Type: config.TypeVSCodeTask, Source: ".vscode/tasks.json"}
+	app := config.Task{Name: "app", Type: config.TypeJetBrains, Source: ".idea/runConfigurations/App.xml"}
+
+	receive := func(m *TaskSelectorModel, batch TaskBatch) tea.Cmd {
+		_, cmd := m.Update(taskBatchMsg(batch))
+		return cmd
+	}
+
+	t.Run("should show a spinner until the first tasks arrive", func(t *testing.T) {
+		model := NewStreamingTaskSelectorModel(make(chan TaskBatch), false)
+		require.NotNil(t, model.Init())
+
+		view := model.View()
+		require.Contains(t, view, "Loading tasks… (0 of 0 sources)")
+		require.Contains(t, view, "Waiting for the first tasks")
+		require.NotContains(t, view, "No tasks or launch configurations found")
+	})
+
+	t.Run("should add tasks as sources complete and keep the cursor on its task", func(t *testing.T) {
+		model := NewStreamingTaskSelectorModel(make(chan TaskBatch), false)
+
+		require.NotNil(t, receive(model, TaskBatch{Tasks: []config.Task{build, test}, Loaded: 1, Sources: 2}))
+		require.Contains(t, model.View(), "Loading tasks… (1 of 2 sources)")
+
+		model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		require.Equal(t, "test", model.filteredTasks[model.cursor].Name)
+
+		// A task from another source lands above the cursor
+		require.Nil(t, receive(model, TaskBatch{Tasks: []config.Task{app, build, test}, Loaded: 2, Sources: 2, Final: true}))
+		require.False(t, model.loading)
+		require.Len(t, model.tasks, 3)
+		require.Equal(t, "test", model.filteredTasks[model.cursor].Name)
+		require.NotContains(t, model.View(), "Loading tasks")
+	})
+
+	t.Run("should keep the search while tasks arrive", func(t *testing.T) {
+		model := NewStreamingTaskSelectorModel(make(chan TaskBatch), false)
+		model.SetQuery("app")
+
+		receive(model, TaskBatch{Tasks: []config.Task{build}, Loaded: 1, Sources: 2})
+		require.Empty(t, model.filteredTasks)
+
+		receive(model, TaskBatch{Tasks: []config.Task{build, app}, Loaded: 2, Sources: 2, Final: true})
+		require.Len(t, model.filteredTasks, 1)
+		require.Equal(t, "app", model.filteredTasks[0].Name)
+	})
+
+	t.Run("should run a task picked before loading finished", func(t *testing.T) {
+		model := NewStreamingTaskSelectorModel(make(chan TaskBatch), false)
+		receive(model, TaskBatch{Tasks: []config.Task{build}, Loaded: 1, Sources: 3})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		require.NotNil(t, cmd)
+		require.Equal(t, "build", model.selected.Name)
+	})
+
+	t.Run("should close when loading fails or finds nothing", func(t *testing.T) {
+		model := NewStreamingTaskSelectorModel(make(chan TaskBatch), false)
+		require.NotNil(t, receive(model, TaskBatch{Err: errors.New("timed out reading .vscode/tasks.json")}))
+		require.True(t, model.quitting)
+		require.Nil(t, model.selected)
+
+		model = NewStreamingTaskSelectorModel(make(chan TaskBatch), false)
+		require.NotNil(t, receive(model, TaskBatch{Final: true}))
+		require.True(t, model.quitting)
+	})
+
+	t.Run("should read batches from the channel", func(t *testing.T) {
+		batches := make(chan TaskBatch, 1)
+		batches <- TaskBatch{Tasks: []config.Task{build}, Loaded: 1, Sources: 1, Final: true}
+
+		model := NewStreamingTaskSelectorModel(batches, false)
+		msg := model.nextBatch()()
+		require.Equal(t, taskBatchMsg(TaskBatch{Tasks: []config.Task{build}, Loaded: 1, Sources: 1, Final: true}), msg)
+
+		close(batches)
+		require.Nil(t, model.nextBatch()())
+	})
+}
+
+func TestTaskCommandLine(t *testing.T) {
+	require.Equal(t, "go build -o 'bin/my app'", TaskCommandLine(&config.Task{Command: "go", Args: []string{"build", "-o", "bin/my app"}}))
+	require.Equal(t, "/bin/sh -c 'make build'", TaskCommandLine(&config.Task{Command: "make", Args: []string{"build"}, Shell: "/bin/sh"}))
+	require.Empty(t, TaskCommandLine(&config.Task{DependsOn: []string{"build"}}))
+}
+
+func TestTaskSelectorModel_QuickSelect(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "build", Type: config.TypeVSCodeTask},
+		{Name: "test", Type: config.TypeVSCodeTask},
+		{Name: "db-reset", Type: config.TypeVSCodeTask, Confirm: true},
+	}
+
+	press := func(m *TaskSelectorModel, key string) tea.Cmd {
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return cmd
+	}
+
+	t.Run("should run the task at the pressed position", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+
+		require.NotNil(t, press(model, "2"))
+		require.Equal(t, "test", model.selected.Name)
+		require.True(t, model.quitting)
+	})
+
+	t.Run("should count positions in the filtered list", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchInput = "test"
+		model.filterTasks()
+
+		require.NotNil(t, press(model, "1"))
+		require.Equal(t, "test", model.selected.Name)
+	})
+
+	t.Run("should ignore positions past the end of the list", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+
+		require.Nil(t, press(model, "9"))
+		require.Nil(t, model.selected)
+		require.False(t, model.quitting)
+	})
+
+	t.Run("should still confirm tasks that opted in", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+
+		require.Nil(t, press(model, "3"))
+		require.Equal(t, stateConfirm, model.state)
+		require.Nil(t, model.selected)
+	})
+
+	t.Run("should type digits into the search instead", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.searchMode = true
+
+		require.Nil(t, press(model, "1"))
+		require.Equal(t, "1", model.searchInput)
+		require.Nil(t, model.selected)
+	})
+
+	t.Run("should document the shortcut", func(t *testing.T) {
+		require.Contains(t, NewTaskSelectorModel(tasks).View(), "1-9: Run Nth Task")
+	})
+
+	t.Run("should number the tasks the digits run", func(t *testing.T) {
+		view := NewTaskSelectorModel(tasks).View()
+
+		require.Contains(t, view, "▶ 1. build")
+		require.Contains(t, view, "  2. test")
+		require.Contains(t, view, "  3. db-reset")
+	})
+}
+
+func TestTaskSummary(t *testing.T) {
+	task := &config.Task{
+		Command: "go",
+		Args:    []string{"build", "-o", "bin/my app"},
+		Cwd:     "/project",
+		Env:     map[string]string{"B": "2", "A": "1"},
+	}
+
+	require.Equal(t, []string{
+		"Command: go",
+		"Args: build -o 'bin/my app'",
+		"Cwd: /project",
+		"Env: A=1",
+		"Env: B=2",
+	}, TaskSummary(task))
+
+	require.Contains(t, TaskSummary(&config.Task{Command: "make"}), "Env: (inherited)")
+	require.Contains(t, TaskSummary(&config.Task{Command: "make", Env: map[string]string{"API_TOKEN": "abc"}}), "Env: API_TOKEN=<redacted>")
+}
+
+func TestTaskSelectorModel_SetQuery(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "lint", Type: config.TypeVSCodeTask},
+		{Name: "lint-fix", Type: config.TypeVSCodeTask, Confirm: true},
+	}
+
+	t.Run("should prefill the search without entering search mode", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.SetQuery("lint-f")
+
+		require.False(t, model.searchMode)
+		require.Equal(t, "lint-fix", model.filteredTasks[0].Name)
+		require.Contains(t, model.View(), "lint-f")
+	})
+
+	t.Run("should keep every candidate matching the query", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.SetQuery("li")
+
+		require.Len(t, model.filteredTasks, 2)
+	})
+
+	t.Run("should pick without confirming when the caller confirms", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.noConfirm = true
+		model.SetQuery("lint-f")
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		require.NotNil(t, cmd)
+		require.Equal(t, "lint-fix", model.selected.Name)
+	})
+}
+
+// syntheticTasks generates a monorepo-sized task list with names, commands and groups
+// resembling what task generators emit
+func syntheticTasks(n int) []config.Task {
+	packages := []string{"api", "web", "worker", "billing", "auth", "search", "docs", "cli"}
+	actions := []string{"build", "test", "lint", "deploy", "watch", "bench"}
+
+	tasks := make([]config.Task, 0, n)
+
+	for i := range n {
+		pkg, action := packages[i%len(packages)], actions[(i/len(packages))%len(actions)]
+
+		tasks = append(tasks, config.Task{
+			Name:        fmt.Sprintf("%s:%s:%d", pkg, action, i),
+			Command:     "npm",
+			Args:        []string{"run", action, "--workspace", "packages/" + pkg},
+			Group:       action,
+			Description: fmt.Sprintf("Run %s for the %s package", action, pkg),
+			Source:      "vscode-tasks",
+		})
+	}
+
+	return tasks
+}
+
+// taskNames returns the names of tasks, in order
+func taskNames(tasks []config.Task) []string {
+	names := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+
+	return names
+}
+
+func TestTaskSelectorModel_IncrementalFiltering(t *testing.T) {
+	tasks := syntheticTasks(300)
+
+	typeText := func(m *TaskSelectorModel, text string) {
+		for _, r := range text {
+			m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	for _, query := range []string{"api:build", "billing test", "wroker", "npm run lint", "deploy #missing", "packages/search"} {
+		for _, fullText := range []bool{true, false} {
+			t.Run(fmt.Sprintf("should match a full search for %q (full text %v)", query, fullText), func(t *testing.T) {
+				typed := NewTaskSelectorModel(tasks)
+				typed.fullText = fullText
+				typed.searchMode = true
+
+				fresh := NewTaskSelectorModel(tasks)
+				fresh.fullText = fullText
+
+				for i := 1; i <= len(query); i++ {
+					typeText(typed, query[i-1:i])
+
+					fresh.cache = searchCache{}
+					fresh.searchInput = query[:i]
+					fresh.filterTasks()
+
+					require.Equal(t, taskNames(fresh.filteredTasks), taskNames(typed.filteredTasks), "after typing %q", query[:i])
+					require.Equal(t, append([][]int{}, fresh.highlights...), append([][]int{}, typed.highlights...), "after typing %q", query[:i])
+				}
+			})
+		}
+	}
+
+	t.Run("should find edit-distance matches a shorter query ruled out", func(t *testing.T) {
+		model := NewTaskSelectorModel([]config.Task{{Name: "build"}})
+		model.fullText = false
+
+		model.SetQuery("bx")
+		require.Empty(t, model.filteredTasks)
+
+		model.SetQuery("bxild")
+		require.Len(t, model.filteredTasks, 1)
+	})
+
+	t.Run("should rescan every task when the query is edited", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.SetQuery("docs:lint")
+		model.SetQuery("auth:lint")
+
+		require.NotEmpty(t, model.filteredTasks)
+		require.Equal(t, "auth:lint:20", model.filteredTasks[0].Name)
+	})
+}
+
+func TestTaskSelectorModel_WindowedRendering(t *testing.T) {
+	tasks := syntheticTasks(100)
+
+	press := func(m *TaskSelectorModel, key tea.KeyType, times int) {
+		for range times {
+			m.Update(tea.KeyMsg{Type: key})
+		}
+	}
+
+	visible := func(m *TaskSelectorModel, i int) bool {
+		return strings.Contains(m.View(), tasks[i].Name+" ")
+	}
+
+	t.Run("should render only a window of rows with a scroll indicator", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		view := model.View()
+
+		require.Contains(t, view, "showing 20 of 100 tasks (1-20)")
+		require.True(t, visible(model, 19))
+		require.False(t, visible(model, 20))
+	})
+
+	t.Run("should keep the cursor visible past the bottom edge", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		press(model, tea.KeyDown, 25)
+
+		require.Equal(t, 25, model.cursor)
+		require.Contains(t, model.View(), "▶    "+tasks[25].Name)
+		require.Contains(t, model.View(), "(7-26)")
+		require.False(t, visible(model, 5))
+	})
+
+	t.Run("should keep the cursor visible past the top edge", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		press(model, tea.KeyDown, 60)
+		press(model, tea.KeyUp, 30)
+
+		require.Equal(t, 30, model.cursor)
+		require.Contains(t, model.View(), "▶    "+tasks[30].Name)
+		require.Contains(t, model.View(), "(31-50)")
+	})
+
+	t.Run("should size the window to the terminal", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks)
+		model.Update(tea.WindowSizeMsg{Width: 120, Height: 24})
+		press(model, tea.KeyDown, 99)
+
+		require.Contains(t, model.View(), "showing 10 of 100 tasks (91-100)")
+		require.Contains(t, model.View(), "▶    "+tasks[99].Name)
+	})
+
+	t.Run("should scroll back into range when a search shrinks the list", func(t *testing.T) {
+		model := NewTaskSelectorModel(syntheticTasks(2000))
+		press(model, tea.KeyDown, 500)
+		model.SetQuery("api:build")
+
+		require.Zero(t, model.offset)
+		require.Contains(t, model.View(), "matches (1-20)")
+	})
+
+	t.Run("should render short lists unchanged", func(t *testing.T) {
+		model := NewTaskSelectorModel(tasks[:5])
+
+		require.NotContains(t, model.View(), "showing")
+	})
+}
+
+func TestFormatCount(t *testing.T) {
+	for n, want := range map[int]string{0: "0", 20: "20", 999: "999", 1431: "1,431", 1000000: "1,000,000"} {
+		require.Equal(t, want, formatCount(n))
+	}
+}
+
+func BenchmarkTaskSelectorModel_Typing(b *testing.B) {
+	tasks := syntheticTasks(2000)
+	query := "billing:test"
+
+	b.ReportAllocs()
+
+	for range b.N {
+		model := NewTaskSelectorModel(tasks)
+		model.searchMode = true
+
+		for _, r := range query {
+			model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			_ = model.View()
+		}
+	}
+}
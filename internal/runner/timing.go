@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+)
+
+// TaskTiming is when a task run started and ended, retries included
+type TaskTiming struct {
+	Started time.Time
+	Ended   time.Time
+}
+
+// Duration returns the wall time of the run
+func (t TaskTiming) Duration() time.Duration {
+	return t.Ended.Sub(t.Started)
+}
+
+// RunTaskTimed is RunTaskWithRetries that also measures the run, failed or not
+func (tr *TaskRunner) RunTaskTimed(ctx context.Context, task *config.Task) (TaskTiming, error) {
+	timing := TaskTiming{Started: time.Now()}
+
+	err := tr.RunTaskWithRetries(ctx, task)
+	timing.Ended = time.Now()
+
+	return timing, err
+}
@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/syndbg/taskporter/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRunner_RunTaskTimed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture task needs a POSIX shell")
+	}
+
+	t.Run("should measure failed runs too", func(t *testing.T) {
+		tr := NewTaskRunner(false, nil)
+		task := &config.Task{Name: "slow", Type: config.TypeVSCodeTask, Command: "sh", Args: []string{"-c", "sleep 0.1; exit 3"}}
+
+		timing, err := tr.RunTaskTimed(context.Background(), task)
+
+		require.Equal(t, 3, ExitCode(err))
+		require.GreaterOrEqual(t, timing.Duration(), 100*time.Millisecond)
+		require.False(t, timing.Ended.Before(timing.Started))
+	})
+}
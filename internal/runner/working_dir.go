@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/syndbg/taskporter/internal/config"
+	"github.com/syndbg/taskporter/internal/logging"
+)
+
+// SetCreateCwd creates missing task working directories before running, as if every task set createCwd
+func (tr *TaskRunner) SetCreateCwd(create bool) {
+	tr.createCwd = create
+}
+
+// prepareWorkingDirectory checks that dir, the task's resolved working directory, exists, and
+// creates it when the task or --create-cwd asks for that. Otherwise exec would fail with a bare
+// "no such file or directory" that doesn't say which path or task is at fault.
+func (tr *TaskRunner) prepareWorkingDirectory(task *config.Task, dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	if !tr.paranoidMode && task.Type == config.TypeJetBrains && config.IsOutsideProject(dir, tr.projectRoot) {
+		tr.logger.Warn("working directory resolves outside the project root", logging.KeyTask, task.Name, logging.KeyFile, task.Source, "cwd", dir)
+	}
+
+	info, err := os.Stat(dir)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if !tr.createCwd && !task.CreateCwd {
+			return fmt.Errorf("working directory %s of task '%s' does not exist (defined in %s); create it or run with --create-cwd", dir, task.Name, task.Source)
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create working directory %s of task '%s': %w", dir, task.Name, err)
+		}
+
+		if tr.verbose {
+			fmt.Printf("📁 Created working directory: %s\n", dir)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to check working directory of task '%s': %w", task.Name, err)
+	case !info.IsDir():
+		return fmt.Errorf("working directory %s of task '%s' is not a directory (defined in %s)", dir, task.Name, task.Source)
+	}
+
+	return nil
+}
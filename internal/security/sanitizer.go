@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -105,6 +106,12 @@ func (s *Sanitizer) validateArgument(arg string) error {
 
 // SanitizeEnvironment validates and sanitizes environment variables
 func (s *Sanitizer) SanitizeEnvironment(env map[string]string) (map[string]string, error) {
+	return s.SanitizeEnvironmentKeeping(env, nil)
+}
+
+// SanitizeEnvironmentKeeping validates environment variables like SanitizeEnvironment, except
+// that the system variables named in allowed may be set. Their values are still validated.
+func (s *Sanitizer) SanitizeEnvironmentKeeping(env map[string]string, allowed []string) (map[string]string, error) {
 	if len(env) == 0 {
 		return env, nil
 	}
@@ -113,7 +120,7 @@ func (s *Sanitizer) SanitizeEnvironment(env map[string]string) (map[string]strin
 
 	for key, value := range env {
 		// Validate environment variable key
-		if err := s.validateEnvKey(key); err != nil {
+		if err := s.validateEnvKey(key, allowed); err != nil {
 			return nil, fmt.Errorf("invalid environment variable key '%s': %w", key, err)
 		}
 
@@ -128,8 +135,9 @@ func (s *Sanitizer) SanitizeEnvironment(env map[string]string) (map[string]strin
 	return sanitizedEnv, nil
 }
 
-// validateEnvKey validates an environment variable key
-func (s *Sanitizer) validateEnvKey(key string) error {
+// validateEnvKey validates an environment variable key. System variables are rejected unless
+// they are in allowed.
+func (s *Sanitizer) validateEnvKey(key string, allowed []string) error {
 	if key == "" {
 		return fmt.Errorf("environment variable key cannot be empty")
 	}
@@ -147,7 +155,7 @@ func (s *Sanitizer) validateEnvKey(key string) error {
 	}
 
 	for _, dangerous := range dangerousKeys {
-		if key == dangerous {
+		if key == dangerous && !slices.Contains(allowed, key) {
 			return fmt.Errorf("modifying system environment variable '%s' is not allowed", key)
 		}
 	}
@@ -280,3 +288,24 @@ func (s *Sanitizer) ValidateOutputPath(outputPath string) error {
 
 	return nil
 }
+
+// ValidateRemoteHost checks that an ssh destination ([user@]host) is well-formed and explicitly allowed
+func (s *Sanitizer) ValidateRemoteHost(host string, allowedHosts []string) error {
+	if host == "" {
+		return fmt.Errorf("remote host cannot be empty")
+	}
+
+	// A leading dash would be read by ssh as an option (e.g. -oProxyCommand=...)
+	validHostPattern := regexp.MustCompile(`^([a-zA-Z0-9._\-]+@)?[a-zA-Z0-9._:%\-\[\]]+$`)
+	if strings.HasPrefix(host, "-") || !validHostPattern.MatchString(host) {
+		return fmt.Errorf("remote host contains invalid characters: %s", host)
+	}
+
+	for _, allowed := range allowedHosts {
+		if host == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("remote host %s is not in the allowed list (use --remote-allow %s)", host, host)
+}
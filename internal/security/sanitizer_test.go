@@ -137,6 +137,20 @@ func TestSanitizer(t *testing.T) {
 			}
 		})
 
+		t.Run("should allow explicitly kept system variables", func(t *testing.T) {
+			env := map[string]string{"PATH": "/opt/toolchain/bin", "DEBUG": "true"}
+
+			result, err := sanitizer.SanitizeEnvironmentKeeping(env, []string{"PATH"})
+			require.NoError(t, err)
+			require.Equal(t, env, result)
+
+			_, err = sanitizer.SanitizeEnvironmentKeeping(map[string]string{"LD_PRELOAD": "x.so"}, []string{"PATH"})
+			require.Error(t, err)
+
+			_, err = sanitizer.SanitizeEnvironmentKeeping(map[string]string{"PATH": "$(whoami)"}, []string{"PATH"})
+			require.Error(t, err, "values of kept variables are still validated")
+		})
+
 		t.Run("should reject invalid environment variable names", func(t *testing.T) {
 			invalidEnvs := map[string]string{
 				"invalid-name": "value", // hyphens not allowed
@@ -360,4 +374,29 @@ func TestSanitizer(t *testing.T) {
 			require.Error(t, err)
 		})
 	})
+
+	t.Run("ValidateRemoteHost", func(t *testing.T) {
+		sanitizer := NewSanitizer("/test/project")
+		allowed := []string{"devbox", "dev@devbox.internal", "ci-runner"}
+
+		t.Run("should allow listed hosts", func(t *testing.T) {
+			for _, host := range allowed {
+				require.NoError(t, sanitizer.ValidateRemoteHost(host, allowed), "Host should be allowed: %s", host)
+			}
+		})
+
+		t.Run("should reject hosts that are not listed", func(t *testing.T) {
+			err := sanitizer.ValidateRemoteHost("root@devbox", allowed)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "--remote-allow root@devbox")
+		})
+
+		t.Run("should reject malformed hosts even when listed", func(t *testing.T) {
+			malformed := []string{"", "-oProxyCommand=sh", "dev@box;id", "dev@@box", "box name"}
+
+			for _, host := range malformed {
+				require.Error(t, sanitizer.ValidateRemoteHost(host, append(allowed, host)), "Host should be rejected: %q", host)
+			}
+		})
+	})
 }
@@ -0,0 +1,98 @@
+// Package shellwords splits and joins the parameter strings of run configurations, such as
+// JetBrains PROGRAM_PARAMETERS, into and from arguments
+package shellwords
+
+import (
+	"strings"
+)
+
+// Join joins arguments into a single parameter string as used by JetBrains options
+// such as PROGRAM_PARAMETERS. Arguments that are empty or contain whitespace or quotes are
+// wrapped in double quotes, with embedded double quotes and backslashes escaped.
+func Join(args []string) string {
+	quoted := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		quoted = append(quoted, quote(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// quote quotes a single argument if it would otherwise not survive Split
+func quote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\r\"'") {
+		return arg
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('"')
+
+	for _, char := range arg {
+		if char == '"' || char == '\\' {
+			b.WriteByte('\\')
+		}
+
+		b.WriteRune(char)
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// Split splits a parameter string into arguments; it is the inverse of Join.
+// Double-quoted sections support \" and \\ escapes, single-quoted sections are literal,
+// and quotes may appear mid-argument (e.g. -Dprop="quoted value").
+func Split(params string) []string {
+	var (
+		args      []string
+		current   strings.Builder
+		hasToken  bool
+		inQuote   bool
+		quoteChar rune
+	)
+
+	runes := []rune(params)
+
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+
+		switch {
+		case inQuote && quoteChar == '"' && char == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+			// Escaped quote or backslash inside double quotes
+			i++
+			current.WriteRune(runes[i])
+		case inQuote && char == quoteChar:
+			// End of quoted section - don't include the quote in output
+			inQuote = false
+		case inQuote:
+			current.WriteRune(char)
+		case char == '"' || char == '\'':
+			// Start of quoted section - an empty quoted section still yields an argument
+			inQuote = true
+			quoteChar = char
+			hasToken = true
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			// Whitespace outside quotes - end current argument
+			if hasToken {
+				args = append(args, current.String())
+				current.Reset()
+
+				hasToken = false
+			}
+		default:
+			current.WriteRune(char)
+
+			hasToken = true
+		}
+	}
+
+	// Add final argument
+	if hasToken {
+		args = append(args, current.String())
+	}
+
+	return args
+}
@@ -0,0 +1,114 @@
+package shellwords
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellwords(t *testing.T) {
+	t.Run("Join", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			args     []string
+			expected string
+		}{
+			{
+				name:     "plain arguments",
+				args:     []string{"--port", "8080"},
+				expected: "--port 8080",
+			},
+			{
+				name:     "argument with spaces",
+				args:     []string{"--name", "My App"},
+				expected: `--name "My App"`,
+			},
+			{
+				name:     "embedded quotes and backslashes",
+				args:     []string{`say "hi"`, `C:\Program Files\app`},
+				expected: `"say \"hi\"" "C:\\Program Files\\app"`,
+			},
+			{
+				name:     "empty argument",
+				args:     []string{"a", "", "b"},
+				expected: `a "" b`,
+			},
+			{
+				name:     "no arguments",
+				args:     nil,
+				expected: "",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require.Equal(t, tt.expected, Join(tt.args))
+			})
+		}
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			input    string
+			expected []string
+		}{
+			{
+				name:     "simple parameters",
+				input:    "--port 8080 --debug",
+				expected: []string{"--port", "8080", "--debug"},
+			},
+			{
+				name:     "quoted parameters",
+				input:    `--config "test file.properties" --name 'My App'`,
+				expected: []string{"--config", "test file.properties", "--name", "My App"},
+			},
+			{
+				name:     "quotes mid-argument",
+				input:    `-Dprop="quoted value" --flag`,
+				expected: []string{"-Dprop=quoted value", "--flag"},
+			},
+			{
+				name:     "unescaped backslashes are literal",
+				input:    `C:\tools\bin "C:\Program Files"`,
+				expected: []string{`C:\tools\bin`, `C:\Program Files`},
+			},
+			{
+				name:     "extra whitespace",
+				input:    "  a \t b  ",
+				expected: []string{"a", "b"},
+			},
+			{
+				name:     "empty string",
+				input:    "",
+				expected: nil,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require.Equal(t, tt.expected, Split(tt.input))
+			})
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		vectors := [][]string{
+			{"build", "--info"},
+			{"--name", "My App"},
+			{"--message", `he said "hello"`},
+			{"it's", "a 'quoted' word"},
+			{`C:\Program Files\Java\bin\java.exe`, `-Dpath=C:\temp\`},
+			{`trailing\`, `\"`, `\\`},
+			{"", "empty", ""},
+			{"tab\tseparated", "new\nline"},
+			{"-Dprop=a b", "--flag=\"x\""},
+			{"unicode ✓ arg", "ünïcödé"},
+			{"${workspaceFolder}/my dir/file.txt"},
+		}
+
+		for _, args := range vectors {
+			require.Equal(t, args, Split(Join(args)), "round trip of %q via %q", args, Join(args))
+		}
+	})
+}